@@ -15,11 +15,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -27,25 +28,30 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/application/services"
+	graphservice "sql-graph-visualizer/internal/application/services/graph"
 	graphqlserver "sql-graph-visualizer/internal/application/services/graphql"
 	"sql-graph-visualizer/internal/application/services/performance"
 	"sql-graph-visualizer/internal/application/services/transform"
-	"sql-graph-visualizer/internal/domain/aggregates/graph"
 	"sql-graph-visualizer/internal/domain/models"
 	"sql-graph-visualizer/internal/domain/repositories/config"
 	"sql-graph-visualizer/internal/domain/repositories/configrule"
+	transformVal "sql-graph-visualizer/internal/domain/valueobjects/transform"
+	"sql-graph-visualizer/internal/infrastructure/factories"
 	"sql-graph-visualizer/internal/infrastructure/middleware"
-	mysqlrepo "sql-graph-visualizer/internal/infrastructure/persistence/mysql"
+	infrastructure "sql-graph-visualizer/internal/infrastructure/persistence"
 	"sql-graph-visualizer/internal/infrastructure/persistence/neo4j"
-	postgresqlrepo "sql-graph-visualizer/internal/infrastructure/persistence/postgresql"
+	"sql-graph-visualizer/internal/infrastructure/persistence/registry"
 	"sql-graph-visualizer/internal/interfaces/api"
 
 	// Import database drivers
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
-)
 
-var addr = "127.0.0.1:3000"
+	// Register the supported source databases
+	_ "sql-graph-visualizer/internal/infrastructure/persistence/mysql"
+	_ "sql-graph-visualizer/internal/infrastructure/persistence/postgresql"
+)
 
 func main() {
 	ctx := context.Background()
@@ -77,73 +83,32 @@ func main() {
 	// Initialize database connection based on configuration
 	var dbPort ports.DatabasePort
 	var db *sql.DB
+	retryPolicy := connectRetryPolicy(cfg)
 
-	// Check if we have a new multi-database configuration or legacy MySQL
-	if cfg.Database != nil && cfg.Database.Type != "" {
-		logrus.Infof("Using new multi-database configuration: %s", cfg.Database.Type)
-
-		// For now, PostgreSQL will use the existing MySQL port interface
-		// This is a temporary workaround until we have a unified database interface
-		switch cfg.Database.Type {
-		case models.DatabaseTypePostgreSQL:
-			pgConfig := cfg.Database.PostgreSQL
-			logrus.Infof("Connecting to PostgreSQL: %s@%s:%d/%s", pgConfig.GetUsername(), pgConfig.GetHost(), pgConfig.GetPort(), pgConfig.GetDatabase())
-
-			// Create PostgreSQL repository
-			postgresRepo := postgresqlrepo.NewPostgreSQLRepository(nil)
-			db, err = postgresRepo.ConnectToExisting(ctx, pgConfig)
-			if err != nil {
-				logrus.Fatalf("Failed to connect to PostgreSQL: %v", err)
-			}
-
-			// Use PostgreSQL repository as a DatabasePort
-			dbPort = postgresqlrepo.NewPostgreSQLDatabasePort(db)
-			logrus.Infof("Successfully connected to PostgreSQL database")
-
-		case models.DatabaseTypeMySQL:
-			mysqlConfig := cfg.Database.MySQL
-			dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-				mysqlConfig.GetUsername(),
-				mysqlConfig.GetPassword(),
-				mysqlConfig.GetHost(),
-				mysqlConfig.GetPort(),
-				mysqlConfig.GetDatabase(),
-			)
-
-			db, err = sql.Open("mysql", dsn)
-			if err != nil {
-				logrus.Fatalf("Failed to connect to MySQL: %v", err)
-			}
-
-			dbPort = mysqlrepo.NewMySQLDatabasePort(db)
-			logrus.Infof("Successfully connected to MySQL database")
-
-		default:
-			logrus.Fatalf("Unsupported database type: %s", cfg.Database.Type)
-		}
-
-	} else {
-		// Legacy MySQL configuration
-		logrus.Infof("Using legacy MySQL configuration")
-
-		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
-			cfg.MySQL.User,
-			cfg.MySQL.Password,
-			cfg.MySQL.Host,
-			cfg.MySQL.Port,
-			cfg.MySQL.Database,
-		)
-
-		logrus.Infof("DSN: %s", dsn)
-		db, err = sql.Open("mysql", dsn)
-		if err != nil {
-			logrus.Fatalf("Failed to connect to MySQL: %v", err)
-		}
+	// Connect through the driver registered for the configured type; without a database
+	// section the legacy MySQL settings are used
+	dbConfig := cfg.GetDatabaseConfig()
+	driver, err := registry.Lookup(cfg.GetDatabaseType())
+	if err != nil {
+		logrus.Fatalf("Failed to select database: %v", err)
+	}
+	if dbConfig == nil {
+		logrus.Fatalf("No connection settings for database type %s", driver.Type)
+	}
 
-		dbPort = mysqlrepo.NewMySQLDatabasePort(db)
-		logrus.Infof("MySQL connection successful")
+	logrus.Infof("Connecting to %s: %s@%s:%d/%s", driver.Type, dbConfig.GetUsername(), dbConfig.GetHost(), dbConfig.GetPort(), dbConfig.GetDatabase())
+	err = infrastructure.ConnectWithRetry(ctx, string(driver.Type), retryPolicy, func(ctx context.Context) error {
+		var connectErr error
+		db, connectErr = driver.Open(ctx, dbConfig)
+		return connectErr
+	})
+	if err != nil {
+		logrus.Fatalf("Failed to connect to %s: %v", driver.Type, err)
 	}
 
+	dbPort = driver.NewDatabasePort(db)
+	logrus.Infof("Successfully connected to %s database", driver.Type)
+
 	defer func() {
 		if err := db.Close(); err != nil {
 			logrus.Errorf("Error closing database connection: %v", err)
@@ -151,18 +116,31 @@ func main() {
 	}()
 
 	logrus.Infof("Initializing Neo4j connection...")
-	neo4jRepo, err := neo4j.NewNeo4jRepository(cfg.Neo4j.URI, cfg.Neo4j.User, cfg.Neo4j.Password)
+	var neo4jRepo *neo4j.Neo4jRepository
+	err = infrastructure.ConnectWithRetry(ctx, "Neo4j", retryPolicy, func(ctx context.Context) error {
+		repo, connectErr := neo4j.NewNeo4jRepository(cfg.Neo4j.URI, cfg.Neo4j.User, cfg.Neo4j.Password)
+		if connectErr != nil {
+			return connectErr
+		}
+		if connectErr = repo.VerifyConnectivity(); connectErr != nil {
+			_ = repo.Close()
+			return connectErr
+		}
+		neo4jRepo = repo
+		return nil
+	})
 	if err != nil {
 		logrus.Fatalf("Failed to create Neo4j repository: %v", err)
 	}
 	logrus.Infof("Neo4j connection successful")
+	configureWriteBudget(cfg, neo4jRepo)
 	defer func() {
 		if err := neo4jRepo.Close(); err != nil {
 			logrus.Errorf("Error closing Neo4j repository: %v", err)
 		}
 	}()
 
-	logrus.Infof("Deleting all data in Neo4j...")
+	relationshipsOnly := cfg.Transform != nil && cfg.Transform.RelationshipsOnly
 	session := neo4jRepo.NewSession(neo4jDriver.SessionConfig{})
 	defer func() {
 		if err := session.Close(); err != nil {
@@ -170,20 +148,96 @@ func main() {
 		}
 	}()
 
-	_, err = session.Run("MATCH (n) DETACH DELETE n", nil)
-	if err != nil {
-		logrus.Fatalf("Error deleting data in Neo4j: %v", err)
+	reconcile := cfg.Transform != nil && cfg.Transform.Reconcile
+	if reconcile {
+		// The stored graph is kept and brought in line with the source after the transform
+		logrus.Infof("Reconcile mode: keeping the data in Neo4j")
+	} else if relationshipsOnly {
+		// Nodes are kept as they are; only the relationships are rebuilt
+		logrus.Infof("Deleting all relationships in Neo4j...")
+		if _, err = session.Run("MATCH ()-[r]->() DELETE r", nil); err != nil {
+			logrus.Fatalf("Error deleting relationships in Neo4j: %v", err)
+		}
+		logrus.Infof("All relationships in Neo4j deleted")
+	} else {
+		logrus.Infof("Deleting all data in Neo4j...")
+		if _, err = session.Run("MATCH (n) DETACH DELETE n", nil); err != nil {
+			logrus.Fatalf("Error deleting data in Neo4j: %v", err)
+		}
+		logrus.Infof("All data in Neo4j deleted")
 	}
-	logrus.Infof("All data in Neo4j deleted")
 
 	logrus.Infof("Initializing services...")
-	transformService := transform.NewTransformService(dbPort, neo4jRepo, configrule.NewRuleRepository())
+	ruleRepo := configrule.NewRuleRepository()
+	transformService := transform.NewTransformService(dbPort, neo4jRepo, ruleRepo)
+	if timeout := transformTimeout(cfg, os.Args[1:]); timeout > 0 {
+		logrus.Infof("Transform timeout set to %s", timeout)
+		transformService.SetTimeout(timeout)
+	}
+	if relationshipsOnly {
+		logrus.Infof("Relationship-only mode: node rules are skipped")
+		transformService.SetRelationshipsOnly(true)
+	}
+	if reconcile {
+		transformService.SetReconcile(cfg.Transform.AnnotationLabel)
+	}
+	if cfg.Transform != nil && cfg.Transform.ExcludeSoftDeleted {
+		column := cfg.Transform.SoftDeleteColumn
+		if column == "" {
+			column = "deleted_at"
+		}
+		logrus.Infof("Excluding soft-deleted rows where %s is set", column)
+		transformService.SetSoftDeleteColumn(column)
+	}
+	if cfg.Transform != nil && cfg.Transform.MaxConcurrentTables > 1 {
+		configureTableConcurrency(ctx, cfg, transformService)
+	}
+	if !relationshipsOnly {
+		configureIdentityDetection(ctx, cfg, transformService)
+	}
+	if !relationshipsOnly && cfg.Transform != nil && cfg.Transform.ColumnLineage {
+		configureColumnLineage(ctx, cfg, transformService)
+	}
+	if cfg.Transform != nil && cfg.Transform.Provenance {
+		transformService.SetProvenance(cfg.GetDatabaseConfig().GetDatabase())
+	}
+	if cfg.Transform != nil && cfg.Transform.RelationshipCardinality {
+		transformService.SetRelationshipCardinality(true)
+	}
+	if cfg.Transform != nil && cfg.Transform.Timezone != "" {
+		configureTimezone(cfg, transformService)
+	}
+	if cfg.Transform != nil && cfg.Transform.LookupCacheRows > 0 {
+		transformService.SetLookupCache(cfg.Transform.LookupCacheRows, cfg.Transform.LookupCacheTotalRows)
+	}
+	if cfg.Transform != nil && cfg.Transform.StreamBatchSize > 0 {
+		transformService.SetStreamBatchSize(cfg.Transform.StreamBatchSize)
+	}
+	if cfg.Transform != nil && cfg.Transform.PropertyNaming != nil {
+		configurePropertyNaming(cfg, transformService)
+	}
+	configureRunStore(cfg, transformService)
+	transformService.SetSystemSchemas(cfg.GetDatabaseConfig().GetDataFiltering().SystemSchemaList(cfg.GetDatabaseType()))
+	snapshotRetention := 0
+	if cfg.Transform != nil {
+		snapshotRetention = cfg.Transform.SnapshotRetention
+	}
+	snapshots := graphservice.NewGraphSnapshotStore(snapshotRetention)
+	transformService.SetSnapshotRecorder(snapshots)
+	if cfg.Transform != nil && cfg.Transform.TableGrowthInterval != "" {
+		startTableGrowthCapture(ctx, cfg, snapshots)
+	}
 
 	// Initialize performance services if enabled
 	var performanceServices *PerformanceServiceContainer
 	if cfg.Performance != nil && cfg.Performance.Monitoring != nil && cfg.Performance.Monitoring.Enabled {
 		logrus.Info("Initializing performance .monitoring services...")
 		performanceServices = initializePerformanceServices(cfg, db)
+		defer func() {
+			if err := performanceServices.PSAdapter.Close(); err != nil {
+				logrus.Errorf("Error closing performance replica connection: %v", err)
+			}
+		}()
 		logrus.Info("Performance services initialized")
 	} else {
 		logrus.Info("Performance .monitoring is disabled")
@@ -212,14 +266,11 @@ func main() {
 	graphqlserver.StartGraphQLServer(neo4jRepo, cfg)
 	logrus.Info("GraphQL server started")
 
-	logrus.Infof("Starting data transformation...")
-	if err := transformService.TransformAndStore(ctx); err != nil {
-		logrus.Fatalf("Failed to transform and store data: %v", err)
-	}
-	logrus.Infof("Data transformation successful")
-
 	logrus.Infof("Starting server...")
-	vizServer := startVisualizationServer(neo4jRepo, cfg)
+	vizServer, err := startVisualizationServer(neo4jRepo, cfg)
+	if err != nil {
+		logrus.Fatalf("Failed to start visualization server: %v", err)
+	}
 	defer func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -229,6 +280,7 @@ func main() {
 	}()
 
 	router := mux.NewRouter()
+	router.Use(api.NewScopeMiddleware(logrus.StandardLogger(), apiAuthConfig(cfg)))
 
 	// Register performance routes if services are initialized
 	if performanceServices != nil {
@@ -241,10 +293,67 @@ func main() {
 			performanceServices.RealtimeMonitor,
 			performanceServices.PSAdapter,
 		)
+		if cfg.Performance.MetricPrecision != nil {
+			performanceHandlers.SetMetricPrecision(*cfg.Performance.MetricPrecision)
+		}
+		performanceHandlers.SetDiagnosticsToken(cfg.Performance.DiagnosticsToken)
+		performanceHandlers.SetAPIAuthEnabled(apiAuthConfig(cfg).Enabled())
+		if baselines, err := performance.NewBaselineStore(cfg.Performance.BaselineDir); err != nil {
+			logrus.Warnf("Performance baselines disabled: %v", err)
+		} else {
+			performanceHandlers.SetBaselineStore(baselines)
+		}
 		performanceHandlers.RegisterRoutes(router)
 		logrus.Info("Performance API routes registered")
 	}
 
+	transformHandlers := api.NewTransformHandlers(logrus.StandardLogger(), transformService)
+	transformHandlers.RegisterRoutes(router)
+
+	// Register rule bundle export/import and schema dictionary routes; the schema is read over a separate connection
+	if schemaService, err := newSchemaService(cfg); err != nil {
+		logrus.Warnf("Rule bundle and schema routes disabled: %v", err)
+	} else {
+		ruleHandlers := api.NewRuleHandlers(logrus.StandardLogger(), cfg.TransformRules, cfg.GetDatabaseType(), schemaService.SchemaColumns)
+		ruleHandlers.SetImportDir(cfg.TransformRulesDir)
+		ruleHandlers.SetPreviewer(transformService)
+		ruleHandlers.RegisterRoutes(router)
+		api.NewSchemaHandlers(logrus.StandardLogger(), schemaService.ConnectAndAnalyze).RegisterRoutes(router)
+	}
+
+	graphValidator := graphservice.NewGraphValidator(neo4jRepo, ruleRepo)
+	graphValidator.SetWriteProgress(func() ports.GraphWriteProgress {
+		return transformService.Progress().GraphWriteProgress
+	})
+	graphHandlers := api.NewGraphHandlers(
+		logrus.StandardLogger(),
+		graphservice.NewIndexAdvisor(neo4jRepo, ruleRepo),
+		graphValidator,
+	)
+	graphHandlers.SetSnapshots(snapshots)
+	queryService, err := graphservice.NewGraphQueryService(neo4jRepo, graphQueries(cfg), graphQueryMode(cfg))
+	if err != nil {
+		logrus.Fatalf("Invalid graph query configuration: %v", err)
+	}
+	graphHandlers.SetQueries(queryService)
+	graphHandlers.SetNodeDetails(graphservice.NewNodeDetailsService(neo4jRepo))
+	graphHandlers.RegisterRoutes(router)
+
+	// Liveness and readiness probes
+	healthHandlers := api.NewHealthHandlers(logrus.StandardLogger())
+	healthHandlers.AddCheck("database", db.PingContext)
+	healthHandlers.AddCheck("neo4j", func(ctx context.Context) error {
+		return neo4jRepo.VerifyConnectivity()
+	})
+	healthHandlers.AddCheck("transform", transformHandlers.ReadinessCheck)
+	if performanceServices != nil {
+		healthHandlers.AddCheck("performance_schema", performanceServices.PSAdapter.CheckAvailability)
+	}
+	healthHandlers.RegisterRoutes(router)
+
+	// OpenAPI spec describing the routes registered on the router
+	api.NewOpenAPIHandlers(logrus.StandardLogger(), router, "SQL Graph Visualizer API", "1.0.0").RegisterRoutes(router)
+
 	// Health check endpoint
 	router.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
 		logrus.Info("Health check requested")
@@ -263,12 +372,17 @@ func main() {
 			dbStatus = "not_initialized"
 		}
 
+		neo4jStatus := "connected"
+		if err := neo4jRepo.VerifyConnectivity(); err != nil {
+			neo4jStatus = "error: " + err.Error()
+		}
+
 		response := map[string]interface{}{
 			"status":    "healthy",
 			"timestamp": time.Now().Format(time.RFC3339),
 			"version":   "v1.1.0",
 			"database":  dbStatus,
-			"neo4j":     "connected", // TODO: Add real Neo4j health check
+			"neo4j":     neo4jStatus,
 			"environment": map[string]string{
 				"railway":    getEnvOrDefault("RAILWAY_ENVIRONMENT", "not_set"),
 				"port":       getEnvOrDefault("PORT", "not_set"),
@@ -287,12 +401,7 @@ func main() {
 		}
 	})
 
-	router.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(cfg); err != nil {
-			logrus.Errorf("Error encoding config: %v", err)
-		}
-	})
+	router.Handle(api.ConfigPath, api.NewConfigHandler(logrus.StandardLogger(), cfg))
 
 	corsOptions := middleware.CORSOptions{
 		AllowedOrigins:   []string{"*"},
@@ -331,6 +440,10 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		if _, err := transformService.Cancel(ctx); err == nil {
+			logrus.Println("Running transform cancelled")
+		}
+
 		if err := server.Shutdown(ctx); err != nil {
 			logrus.Errorf("Error shutting down API server: %v", err)
 		}
@@ -342,57 +455,194 @@ func main() {
 		logrus.Println("Servers successfully shut down")
 	}()
 
+	// Run the transformation in the background so it can be monitored and cancelled
+	// through /api/transform while it runs. The readiness probe fails until it is done;
+	// a failure is reported there and by /api/transform/status.
+	go func() {
+		logrus.Infof("Starting data transformation...")
+		if err := transformService.TransformAndStore(ctx); err != nil {
+			if errors.Is(err, transform.ErrTransformCancelled) {
+				logrus.Warnf("Data transformation cancelled: %v", err)
+				return
+			}
+			logrus.Errorf("Failed to transform and store data: %v", err)
+			return
+		}
+		logrus.Infof("Data transformation successful")
+
+		prune := cfg.Transform != nil && cfg.Transform.PruneDanglingRelationships
+		if _, err := graphValidator.Validate(ctx, prune); err != nil {
+			logrus.Warnf("Graph validation failed: %v", err)
+		}
+	}()
+
 	logrus.Infof("Starting API server on %s", apiAddr)
 	if err := server.ListenAndServe(); err != nil {
 		logrus.Fatalf("Failed to start server: %v", err)
 	}
 }
 
-func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *http.Server {
+// apiAuthConfig maps the configured API tokens; health probes stay public unless
+// public_paths is set explicitly, and /config needs an admin token unless admin_paths is
+func apiAuthConfig(cfg *models.Config) api.APIAuthConfig {
+	if cfg.API == nil || cfg.API.Auth == nil {
+		return api.APIAuthConfig{}
+	}
+
+	auth := *cfg.API.Auth
+	if auth.PublicPaths == nil {
+		auth.PublicPaths = api.DefaultPublicPaths
+	}
+	if auth.AdminPaths == nil {
+		auth.AdminPaths = api.DefaultAdminPaths
+	}
+	if auth.Enabled() {
+		logrus.Infof("API auth enabled: %d read and %d admin tokens", len(auth.ReadTokens), len(auth.AdminTokens))
+	}
+	return auth
+}
+
+// graphViews converts the configured saved views for the visualization
+func graphViews(cfg *models.Config) []graphservice.GraphView {
+	if cfg.Graph == nil {
+		return nil
+	}
+	views := make([]graphservice.GraphView, 0, len(cfg.Graph.Views))
+	for _, view := range cfg.Graph.Views {
+		views = append(views, graphservice.GraphView{
+			Name:        view.Name,
+			Description: view.Description,
+			Cypher:      view.Cypher,
+			Display:     view.Display,
+		})
+	}
+	return views
+}
+
+// graphStylingRules converts the configured styling rules for the graph response
+func graphStylingRules(cfg *models.Config) []graphservice.StylingRule {
+	if cfg.Graph == nil {
+		return nil
+	}
+	rules := make([]graphservice.StylingRule, 0, len(cfg.Graph.StylingRules))
+	for _, rule := range cfg.Graph.StylingRules {
+		predicates := make([]graphservice.StylePredicate, 0, len(rule.When))
+		for _, predicate := range rule.When {
+			predicates = append(predicates, graphservice.StylePredicate{
+				Property: predicate.Property,
+				Operator: predicate.Operator,
+				Value:    predicate.Value,
+			})
+		}
+		rules = append(rules, graphservice.StylingRule{
+			Name:   rule.Name,
+			Target: rule.Target,
+			Label:  rule.Label,
+			When:   predicates,
+			Style:  rule.Style,
+		})
+	}
+	return rules
+}
+
+// graphExporter creates the exporter serving /api/graph/export with the configured limits
+func graphExporter(neo4jRepo ports.Neo4jPort, cfg *models.Config) *graphservice.GraphExporter {
+	if cfg.Graph == nil || cfg.Graph.Export == nil {
+		return graphservice.NewGraphExporter(neo4jRepo, 0, 0)
+	}
+	return graphservice.NewGraphExporter(neo4jRepo, cfg.Graph.Export.MaxInMemoryElements, cfg.Graph.Export.PageSize)
+}
+
+// graphQueries converts the configured named queries for the graph query API
+func graphQueries(cfg *models.Config) []graphservice.NamedQuery {
+	if cfg.Graph == nil {
+		return nil
+	}
+	queries := make([]graphservice.NamedQuery, 0, len(cfg.Graph.Queries))
+	for _, query := range cfg.Graph.Queries {
+		queries = append(queries, graphservice.NamedQuery{
+			Name:        query.Name,
+			Description: query.Description,
+			Cypher:      query.Cypher,
+			Params:      query.Params,
+		})
+	}
+	return queries
+}
+
+func graphQueryMode(cfg *models.Config) string {
+	if cfg.Graph == nil {
+		return ""
+	}
+	return cfg.Graph.QueryMode
+}
+
+func graphDefaultView(cfg *models.Config) string {
+	if cfg.Graph == nil {
+		return ""
+	}
+	return cfg.Graph.DefaultView
+}
+
+// startVisualizationServer serves the visualization on the configured address. An address
+// already in use is an error unless visualization_server.fallback_to_free_port is set.
+func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) (*http.Server, error) {
 	logrus.Infof("Starting visualization server")
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
-		logrus.Infof("Request to /config endpoint")
-		w.Header().Set("Content-Type", "application/json")
+	// The Neo4j connection the visualization uses, without its password
+	configHandler := api.NewConfigHandler(logrus.StandardLogger(), map[string]any{
+		"neo4j": map[string]string{
+			"uri":      cfg.Neo4j.URI,
+			"username": cfg.Neo4j.User,
+			"password": cfg.Neo4j.Password,
+		},
+	})
+	mux.HandleFunc(api.ConfigPath, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
+		configHandler(w, r)
+	})
 
-		configResponse := map[string]any{
-			"neo4j": map[string]string{
-				"uri":      cfg.Neo4j.URI,
-				"username": cfg.Neo4j.User,
-				"password": cfg.Neo4j.Password,
-			},
-		}
+	viewService, err := graphservice.NewGraphViewService(neo4jRepo, graphViews(cfg), graphDefaultView(cfg))
+	if err != nil {
+		logrus.Fatalf("Invalid graph view configuration: %v", err)
+	}
 
-		if err := json.NewEncoder(w).Encode(configResponse); err != nil {
-			logrus.Errorf("Error encoding config response: %v", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+	styler, err := graphservice.NewGraphStyler(graphStylingRules(cfg))
+	if err != nil {
+		logrus.Fatalf("Invalid graph styling configuration: %v", err)
+	}
+
+	mux.HandleFunc("/api/graph/views", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if err := json.NewEncoder(w).Encode(viewService.Views()); err != nil {
+			logrus.Errorf("Error encoding graph views: %v", err)
 		}
-		logrus.Infof("Config response sent successfully")
 	})
 
 	mux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
 		logrus.Infof("Request to API endpoint /api/graph")
 
-		graphInterface, err := neo4jRepo.ExportGraph("MATCH (n)-[r]->(m) RETURN n, r, m")
+		g, view, err := viewService.Load(r.URL.Query().Get("view"))
+		if errors.Is(err, graphservice.ErrUnknownView) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			logrus.Errorf("Error retrieving data: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		g, ok := graphInterface.(*graph.GraphAggregate)
-		if !ok {
-			logrus.Warnf("Invalid graph type")
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
 		response := struct {
+			View          string           `json:"view"`
+			Display       map[string]any   `json:"display,omitempty"`
 			Nodes         []map[string]any `json:"nodes"`
 			Relationships []map[string]any `json:"relationships"`
 		}{
+			View:          view.Name,
+			Display:       view.Display,
 			Nodes:         make([]map[string]any, 0),
 			Relationships: make([]map[string]any, 0),
 		}
@@ -403,6 +653,9 @@ func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *ht
 				"label":      node.Type,
 				"properties": node.Properties,
 			}
+			if style := styler.NodeStyle(node.Type, node.Properties); style != nil {
+				nodeData["style"] = style
+			}
 			response.Nodes = append(response.Nodes, nodeData)
 			logrus.Infof("Adding node: %v", nodeData)
 		}
@@ -414,6 +667,12 @@ func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *ht
 				"type":       rel.Type,
 				"properties": rel.Properties,
 			}
+			if cardinality, ok := rel.Properties[transformVal.CardinalityProperty]; ok {
+				relData["cardinality"] = cardinality
+			}
+			if style := styler.RelationshipStyle(rel.Type, rel.Properties); style != nil {
+				relData["style"] = style
+			}
 			response.Relationships = append(response.Relationships, relData)
 			logrus.Infof("Adding relationship: %v", relData)
 		}
@@ -430,6 +689,71 @@ func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *ht
 		}
 	})
 
+	// Node details for the UI's click-through, served by the same handler as the REST API
+	nodeHandlers := api.NewGraphHandlers(logrus.StandardLogger(), nil, nil)
+	nodeHandlers.SetNodeDetails(graphservice.NewNodeDetailsService(neo4jRepo))
+	mux.HandleFunc("GET /api/graph/node/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		nodeHandlers.GetNodeDetails(w, r)
+	})
+
+	deltas := graphservice.NewGraphDeltaTracker(graphservice.DefaultDeltaHistory, styler)
+	mux.HandleFunc("/api/graph/delta", func(w http.ResponseWriter, r *http.Request) {
+		g, view, err := viewService.Load(r.URL.Query().Get("view"))
+		if errors.Is(err, graphservice.ErrUnknownView) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logrus.Errorf("Error retrieving data: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		delta := deltas.Delta(view.Name, g, r.URL.Query().Get("since"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if err := json.NewEncoder(w).Encode(delta); err != nil {
+			logrus.Errorf("Error encoding graph delta: %v", err)
+		}
+	})
+
+	exporter := graphExporter(neo4jRepo, cfg)
+	mux.HandleFunc("/api/graph/export", func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = graphservice.ExportFormatGraphML
+		}
+		contentType, extension, err := graphservice.ExportContentType(format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		window, err := graphservice.ParseExportWindow(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="graph.`+extension+`"`)
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		stats, err := exporter.ExportWindowed(r.Context(), w, format, window)
+		if err != nil {
+			if stats.Bytes == 0 {
+				w.Header().Del("Content-Disposition")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			logrus.Errorf("Graph export failed after %d nodes and %d relationships: %v",
+				stats.Nodes, stats.Relationships, err)
+			return
+		}
+		logrus.Infof("Exported graph as %s: %d nodes, %d relationships (streamed: %t)",
+			format, stats.Nodes, stats.Relationships, stats.Streamed)
+	})
+
 	webRoot := filepath.Join(findProjectRoot(), "internal", "interfaces", "web")
 	logrus.Infof("Using web root: %s", webRoot)
 
@@ -447,16 +771,19 @@ func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *ht
 		http.ServeFile(w, r, filepath.Join(webRoot, "templates", "visualization.html"))
 	})
 
-	// Use PORT environment variable if available (for Railway deployment)
-	vizPort := os.Getenv("PORT")
-	if vizPort == "" {
-		vizPort = "3000"
+	var settings models.VisualizationServerConfig
+	if cfg.VisualizationServer != nil {
+		settings = *cfg.VisualizationServer
 	}
-	vizAddr := ":" + vizPort // Listen on all interfaces
+	listener, err := api.Listen(api.VisualizationAddress(settings.BindAddress), settings.FallbackToFreePort)
+	if err != nil {
+		return nil, err
+	}
+	vizAddr := listener.Addr().String()
 
+	// The graph endpoints serve the whole imported graph, so they need the API tokens too
 	server := &http.Server{
-		Handler:           mux,
-		Addr:              vizAddr,
+		Handler:           api.NewScopeMiddleware(logrus.StandardLogger(), apiAuthConfig(cfg))(mux),
 		ReadTimeout:       15 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 		WriteTimeout:      15 * time.Second,
@@ -465,13 +792,13 @@ func startVisualizationServer(neo4jRepo ports.Neo4jPort, cfg *models.Config) *ht
 
 	go func() {
 		logrus.Warnf("Starting visualization server on %s", vizAddr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			logrus.Fatalf("Visualization server terminated with error: %v", err)
 		}
 	}()
 
-	logrus.Infof("Visualization is available at http://localhost:%s", vizPort)
-	return server
+	logrus.Infof("Visualization is available at http://%s", vizAddr)
+	return server, nil
 }
 
 func findProjectRoot() string {
@@ -578,7 +905,7 @@ func init() {
 type PerformanceServiceContainer struct {
 	BenchmarkService    *performance.BenchmarkService
 	PerformanceAnalyzer *performance.PerformanceAnalyzer
-	PSAdapter           *performance.PerformanceSchemaAdapter
+	PSAdapter           ports.PerformanceCollectorPort
 	GraphMapper         *performance.GraphPerformanceMapper
 	RealtimeMonitor     *performance.RealtimePerformanceMonitor
 	MetricsInjector     *performance.SimpleMetricsInjector
@@ -600,9 +927,40 @@ func initializePerformanceServices(cfg *models.Config, db *sql.DB) *PerformanceS
 	// Create Performance Schema Adapter configuration with safe defaults
 	maxStatements := 100
 	maxTables := 50
+	var focusedTables, ignoredTables []string
+	var collectionBudget, shareWindow, digestResetInterval, keepaliveInterval time.Duration
+	var autoReduceLimits bool
+	var replicaDSN string
+	digestTextFallback := true
 	if cfg.Performance != nil && cfg.Performance.Monitoring != nil && cfg.Performance.Monitoring.PerformanceSchema != nil {
-		maxStatements = cfg.Performance.Monitoring.PerformanceSchema.StatementLimit
-		maxTables = cfg.Performance.Monitoring.PerformanceSchema.TableIOLimit
+		psSettings := cfg.Performance.Monitoring.PerformanceSchema
+		maxStatements = psSettings.StatementLimit
+		maxTables = psSettings.TableIOLimit
+		focusedTables = psSettings.FocusedTables
+		ignoredTables = psSettings.IgnoredTables
+		autoReduceLimits = psSettings.AutoReduceLimits
+		replicaDSN = psSettings.ReplicaDSN
+		digestTextFallback = !psSettings.DisableDigestTextFallback
+		if psSettings.CollectionBudget != "" {
+			if collectionBudget, err = time.ParseDuration(psSettings.CollectionBudget); err != nil {
+				logrus.Warnf("Invalid collection_budget, slow collections will not be reported: %v", err)
+			}
+		}
+		if psSettings.ShareWindow != "" {
+			if shareWindow, err = time.ParseDuration(psSettings.ShareWindow); err != nil {
+				logrus.Warnf("Invalid share_window, only concurrent collections are shared: %v", err)
+			}
+		}
+		if psSettings.DigestResetInterval != "" {
+			if digestResetInterval, err = time.ParseDuration(psSettings.DigestResetInterval); err != nil {
+				logrus.Warnf("Invalid digest_reset_interval, the digest summary will not be reset: %v", err)
+			}
+		}
+		if psSettings.KeepaliveInterval != "" {
+			if keepaliveInterval, err = time.ParseDuration(psSettings.KeepaliveInterval); err != nil {
+				logrus.Warnf("Invalid keepalive_interval, idle connections will not be pinged: %v", err)
+			}
+		}
 	}
 
 	psConfig := &performance.PerformanceSchemaConfig{
@@ -617,15 +975,25 @@ func initializePerformanceServices(cfg *models.Config, db *sql.DB) *PerformanceS
 		CollectReplication:  false,
 		MaxStatements:       maxStatements,
 		MaxTables:           maxTables,
-		IgnoredSchemas:      []string{"mysql", "information_schema", "performance_schema", "sys"},
+		CollectionBudget:    collectionBudget,
+		AutoReduceLimits:    autoReduceLimits,
+		ShareWindow:         shareWindow,
+		DigestResetInterval: digestResetInterval,
+		KeepaliveInterval:   keepaliveInterval,
+		IgnoredSchemas:      cfg.GetDatabaseConfig().GetDataFiltering().SystemSchemaList(cfg.GetDatabaseType()),
 		IgnoredUsers:        []string{"root", "mysql.sys", "mysql.session"},
+		IgnoredTables:       ignoredTables,
+		FocusedTables:       focusedTables,
 		EnableDigestText:    true,
+		DigestTextFallback:  digestTextFallback,
 		MinExecutionCount:   10,
 		MinAvgLatency:       10.0,
+		Engine:              cfg.GetDatabaseType(),
+		ReplicaDSN:          replicaDSN,
 	}
 
-	// Initialize Performance Schema Adapter
-	psAdapter := performance.NewPerformanceSchemaAdapter(db, logger, psConfig)
+	// Initialize the performance collector for the configured database type
+	psAdapter := performance.NewPerformanceCollector(db, logger, psConfig)
 
 	// Create Performance Analyzer configuration with safe defaults
 	slowQueryThreshold := 200.0 // Default 200ms
@@ -719,6 +1087,242 @@ func createGraphMapperConfig(cfg *models.Config) *performance.GraphPerformanceMa
 	return config
 }
 
+// connectRetryPolicy reads the startup retry settings; STARTUP_CONNECT_MAX_WAIT overrides the configured max wait
+func connectRetryPolicy(cfg *models.Config) infrastructure.ConnectRetryPolicy {
+	policy := infrastructure.DefaultConnectRetryPolicy()
+
+	var settings models.StartupConfig
+	if cfg.Startup != nil {
+		settings = *cfg.Startup
+	}
+	if value := os.Getenv("STARTUP_CONNECT_MAX_WAIT"); value != "" {
+		settings.ConnectMaxWait = value
+	}
+
+	parse := func(name, value string, target *time.Duration) {
+		if value == "" {
+			return
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			logrus.Warnf("Invalid startup %s %q, using %s: %v", name, value, *target, err)
+			return
+		}
+		*target = duration
+	}
+	parse("connect_max_wait", settings.ConnectMaxWait, &policy.MaxWait)
+	parse("connect_initial_backoff", settings.ConnectInitialBackoff, &policy.InitialBackoff)
+	parse("connect_max_backoff", settings.ConnectMaxBackoff, &policy.MaxBackoff)
+	return policy
+}
+
+// configureWriteBudget bounds the transactions used to store the graph in Neo4j
+func configureWriteBudget(cfg *models.Config, neo4jRepo *neo4j.Neo4jRepository) {
+	batch := cfg.Neo4j.BatchProcessing
+	if batch == nil {
+		return
+	}
+	budget := neo4j.WriteBudget{
+		MaxEntities: batch.MaxTransactionEntities,
+		MaxBytes:    batch.MaxTransactionBytes,
+	}
+	if batch.TransactionTimeout != "" {
+		timeout, err := time.ParseDuration(batch.TransactionTimeout)
+		if err != nil {
+			logrus.Fatalf("Invalid neo4j.batch_processing.transaction_timeout %q: %v", batch.TransactionTimeout, err)
+		}
+		budget.TxTimeout = timeout
+	}
+	if budget != (neo4j.WriteBudget{}) {
+		logrus.Infof("Neo4j write transactions limited to %d entities, %d bytes, timeout %s",
+			budget.MaxEntities, budget.MaxBytes, budget.TxTimeout)
+	}
+	neo4jRepo.SetWriteBudget(budget)
+}
+
+// newSchemaService creates the service the source schema is read with. With
+// transform.schema_cache_ttl set, the schema is discovered once and reused from the schema
+// cache for that long, across the lookups below and across restarts.
+func newSchemaService(cfg *models.Config) (*services.UniversalDatabaseService, error) {
+	schemaRepo, err := factories.NewDatabaseRepositoryFactory().CreateRepository(cfg.GetDatabaseType())
+	if err != nil {
+		return nil, err
+	}
+	service := services.NewUniversalDatabaseService(schemaRepo, cfg.GetDatabaseConfig())
+	if cfg.Transform == nil || cfg.Transform.SchemaCacheTTL == "" {
+		return service, nil
+	}
+
+	ttl, err := time.ParseDuration(cfg.Transform.SchemaCacheTTL)
+	if err != nil || ttl <= 0 {
+		logrus.Fatalf("Invalid transform schema_cache_ttl %q", cfg.Transform.SchemaCacheTTL)
+	}
+	cache, err := services.NewSchemaAnalysisCache(cfg.Transform.SchemaCacheDir, ttl)
+	if err != nil {
+		logrus.Warnf("Schema cache disabled: %v", err)
+		return service, nil
+	}
+	service.SetSchemaCache(cache, false)
+	return service, nil
+}
+
+// configureTableConcurrency lets node rules of independent tables run in parallel, ordered by
+// the foreign keys of the source schema. Without the schema, rules keep running one by one.
+func configureTableConcurrency(ctx context.Context, cfg *models.Config, transformService *transform.TransformService) {
+	schemaService, err := newSchemaService(cfg)
+	if err != nil {
+		logrus.Warnf("Table concurrency disabled: %v", err)
+		return
+	}
+	references, err := schemaService.TableReferences(ctx)
+	if err != nil {
+		logrus.Warnf("Table concurrency disabled: %v", err)
+		return
+	}
+	logrus.Infof("Node rules run for up to %d tables at once", cfg.Transform.MaxConcurrentTables)
+	transformService.SetTableConcurrency(cfg.Transform.MaxConcurrentTables, references)
+}
+
+// configureIdentityDetection identifies the nodes of rules without a key by the primary keys
+// of the source schema. Without the schema, such rules use the identity fallback only.
+func configureIdentityDetection(ctx context.Context, cfg *models.Config, transformService *transform.TransformService) {
+	fallbackSetting := ""
+	if cfg.Transform != nil {
+		fallbackSetting = cfg.Transform.IdentityFallback
+	}
+	fallback, err := transformVal.ParseIdentityFallback(fallbackSetting)
+	if err != nil {
+		logrus.Fatalf("Invalid transform settings: %v", err)
+	}
+
+	var primaryKeys map[string][]string
+	schemaService, err := newSchemaService(cfg)
+	if err == nil {
+		primaryKeys, err = schemaService.PrimaryKeys(ctx)
+	}
+	if err != nil {
+		logrus.Warnf("Primary key detection unavailable, rules without a key use the %q identity fallback: %v", fallback, err)
+	}
+	transformService.SetIdentityDetection(primaryKeys, fallback)
+}
+
+// configureTimezone normalizes imported timestamps to the configured timezone
+func configureTimezone(cfg *models.Config, transformService *transform.TransformService) {
+	timezone, err := time.LoadLocation(cfg.Transform.Timezone)
+	if err != nil {
+		logrus.Fatalf("Invalid transform timezone: %v", err)
+	}
+	if mysqlConfig, ok := cfg.GetDatabaseConfig().(*models.MySQLConfig); ok && !mysqlConfig.ParsesTime() {
+		logrus.Fatalf("transform.timezone cannot convert MySQL timestamps read as text; remove parse_time: false from the MySQL config")
+	}
+	var sourceTimezone *time.Location
+	if cfg.Transform.SourceTimezone != "" {
+		if sourceTimezone, err = time.LoadLocation(cfg.Transform.SourceTimezone); err != nil {
+			logrus.Fatalf("Invalid transform source_timezone: %v", err)
+		}
+	}
+	logrus.Infof("Normalizing timestamps to %s", timezone)
+	transformService.SetTimezone(timezone, sourceTimezone)
+}
+
+// startTableGrowthCapture records the size of the source tables in the snapshot store every
+// table growth interval, in the background
+func startTableGrowthCapture(ctx context.Context, cfg *models.Config, snapshots *graphservice.GraphSnapshotStore) {
+	interval, err := time.ParseDuration(cfg.Transform.TableGrowthInterval)
+	if err != nil || interval <= 0 {
+		logrus.Fatalf("Invalid transform table_growth_interval %q", cfg.Transform.TableGrowthInterval)
+	}
+	schemaRepo, err := factories.NewDatabaseRepositoryFactory().CreateRepository(cfg.GetDatabaseType())
+	if err != nil {
+		logrus.Warnf("Table growth capture disabled: %v", err)
+		return
+	}
+	service := services.NewUniversalDatabaseService(schemaRepo, cfg.GetDatabaseConfig())
+	logrus.Infof("Capturing table sizes every %s", interval)
+	go snapshots.CaptureTableSizes(ctx, interval, service.TableSizes)
+}
+
+// configurePropertyNaming renames imported properties as configured
+func configurePropertyNaming(cfg *models.Config, transformService *transform.TransformService) {
+	naming := transformVal.PropertyNaming{
+		Case:          transformVal.NamingCase(strings.ToLower(cfg.Transform.PropertyNaming.Case)),
+		StripPrefixes: cfg.Transform.PropertyNaming.StripPrefixes,
+		Rename:        cfg.Transform.PropertyNaming.Rename,
+	}
+	if err := naming.Validate(); err != nil {
+		logrus.Fatalf("Invalid transform property_naming: %v", err)
+	}
+	transformService.SetPropertyNaming(naming)
+}
+
+// configureRunStore resumes incremental runs from the last successful run of a previous
+// process. Without a usable state file, "@last_run" starts from the epoch.
+func configureRunStore(cfg *models.Config, transformService *transform.TransformService) {
+	path := ""
+	if cfg.Transform != nil {
+		path = cfg.Transform.StateFile
+	}
+	store, err := transform.NewFileRunStore(path)
+	if err != nil {
+		logrus.Warnf("Transform run state disabled: %v", err)
+		return
+	}
+	if err := transformService.SetRunStore(store); err != nil {
+		logrus.Warnf("Transform run state disabled: %v", err)
+	}
+}
+
+// configureColumnLineage adds the foreign key columns of the source schema to the graph as
+// Column nodes linked by REFERENCES. Without the schema, runs add no lineage.
+func configureColumnLineage(ctx context.Context, cfg *models.Config, transformService *transform.TransformService) {
+	schemaService, err := newSchemaService(cfg)
+	if err != nil {
+		logrus.Warnf("Column lineage disabled, cannot read the schema: %v", err)
+		return
+	}
+	references, err := schemaService.ColumnReferences(ctx)
+	if err != nil {
+		logrus.Warnf("Column lineage disabled, failed to read foreign keys: %v", err)
+		return
+	}
+	transformService.SetColumnLineage(references)
+}
+
+// transformTimeout returns the overall transform timeout given by --transform-timeout (or
+// --transform-timeout=) in args, falling back to TRANSFORM_TIMEOUT and then the config
+func transformTimeout(cfg *models.Config, args []string) time.Duration {
+	value := flagValue(args, "--transform-timeout")
+	if value == "" {
+		value = os.Getenv("TRANSFORM_TIMEOUT")
+	}
+	if value == "" && cfg.Transform != nil {
+		value = cfg.Transform.Timeout
+	}
+	if value == "" {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.Warnf("Invalid transform timeout %q, running without a limit: %v", value, err)
+		return 0
+	}
+	return timeout
+}
+
+// flagValue returns the value of the flag name given as "name value" or "name=value" in args
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, name+"="); ok {
+			return value
+		}
+		if arg == name && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 func createRealtimeConfig(cfg *models.Config) *performance.RealtimeMonitorConfig {
 	config := &performance.RealtimeMonitorConfig{}
 
@@ -728,6 +1332,9 @@ func createRealtimeConfig(cfg *models.Config) *performance.RealtimeMonitorConfig
 		writeTimeout, _ := time.ParseDuration(cfg.Performance.Realtime.WriteTimeout)
 		readTimeout, _ := time.ParseDuration(cfg.Performance.Realtime.ReadTimeout)
 		pingTimeout, _ := time.ParseDuration(cfg.Performance.Realtime.PingTimeout)
+		maxPollDuration, _ := time.ParseDuration(cfg.Performance.Realtime.MaxPollDuration)
+		coalesceWindow, _ := time.ParseDuration(cfg.Performance.Realtime.CoalesceWindow)
+		backfillWindow, _ := time.ParseDuration(cfg.Performance.Realtime.BackfillWindow)
 
 		config.DataUpdateInterval = updateInterval
 		config.HeartbeatInterval = heartbeatInterval
@@ -736,7 +1343,13 @@ func createRealtimeConfig(cfg *models.Config) *performance.RealtimeMonitorConfig
 		config.ReadTimeout = readTimeout
 		config.PingTimeout = pingTimeout
 		config.MaxMessageSize = cfg.Performance.Realtime.MaxMessageSize
+		config.MaxOutboundMessageSize = cfg.Performance.Realtime.MaxOutboundSize
+		config.CoalesceWindow = coalesceWindow
 		config.CompressionEnabled = cfg.Performance.Realtime.CompressionEnabled
+		config.PollBufferSize = cfg.Performance.Realtime.PollBufferSize
+		config.MaxPollDuration = maxPollDuration
+		config.HistoryFile = cfg.Performance.Realtime.HistoryFile
+		config.BackfillWindow = backfillWindow
 
 		if cfg.Performance.Realtime.Alerts != nil {
 			config.AlertThresholds = performance.AlertThresholds{
@@ -748,11 +1361,36 @@ func createRealtimeConfig(cfg *models.Config) *performance.RealtimeMonitorConfig
 				DeadlockThreshold:  cfg.Performance.Realtime.Alerts.DeadlockThreshold,
 			}
 		}
+
+		if auth := cfg.Performance.Realtime.Auth; auth != nil {
+			config.Auth = &performance.WebSocketAuthConfig{
+				Tokens: make(map[string]performance.ClientCapabilities, len(auth.Tokens)),
+			}
+			for _, token := range auth.Tokens {
+				config.Auth.Tokens[token.Token] = clientCapabilities(token.RealtimeCapabilities)
+			}
+			if auth.Anonymous != nil {
+				anonymous := clientCapabilities(*auth.Anonymous)
+				config.Auth.Anonymous = &anonymous
+			}
+		}
+	}
+
+	if cfg.Performance.Monitoring != nil && cfg.Performance.Monitoring.PerformanceSchema != nil {
+		config.StatementDeltas = cfg.Performance.Monitoring.PerformanceSchema.StatementDeltas
 	}
 
 	return config
 }
 
+func clientCapabilities(capabilities models.RealtimeCapabilities) performance.ClientCapabilities {
+	return performance.ClientCapabilities{
+		Role:   capabilities.Role,
+		Topics: capabilities.Topics,
+		Tables: capabilities.Tables,
+	}
+}
+
 // createMinimalRailwayConfig creates a basic config when YAML loading fails on Railway
 func createMinimalRailwayConfig() *models.Config {
 	logrus.Info("Creating minimal Railway configuration from environment variables...")
@@ -760,7 +1398,7 @@ func createMinimalRailwayConfig() *models.Config {
 	return &models.Config{
 		MySQL: models.MySQLConfig{
 			Host:     os.Getenv("MYSQL_HOST"),
-			Port:     3306,
+			Port:     models.DefaultMySQLPort,
 			User:     os.Getenv("MYSQL_USER"),
 			Password: os.Getenv("MYSQL_PASSWORD"),
 			Database: os.Getenv("MYSQL_DATABASE"),
@@ -805,8 +1443,29 @@ func createBenchmarkConfig(cfg *models.Config) *performance.BenchmarkServiceConf
 		if cfg.Performance.Benchmarks.Limits != nil {
 			config.MaxConcurrentRuns = cfg.Performance.Benchmarks.Limits.MaxConcurrentBenchmarks
 			config.MaxResultsInMemory = cfg.Performance.Benchmarks.Limits.MemoryLimitMB
+			config.QueueEnabled = cfg.Performance.Benchmarks.Limits.QueueBenchmarks
+			config.MaxQueueSize = cfg.Performance.Benchmarks.Limits.MaxQueuedBenchmarks
 			// CPUThreshold not available in BenchmarkServiceConfig
 		}
+
+		for _, sink := range cfg.Performance.Benchmarks.Sinks {
+			timeout, _ := time.ParseDuration(sink.Timeout)
+			config.Sinks = append(config.Sinks, performance.BenchmarkSinkConfig{
+				Type:    sink.Type,
+				Path:    sink.Path,
+				URL:     sink.URL,
+				Job:     sink.Job,
+				Timeout: timeout,
+			})
+		}
+
+		if rawOutput := cfg.Performance.Benchmarks.RawOutput; rawOutput != nil {
+			config.RawOutput = performance.RawOutputConfig{
+				MaxSize:  rawOutput.MaxSize,
+				Compress: rawOutput.Compress,
+				SpillDir: rawOutput.SpillDir,
+			}
+		}
 	}
 
 	return config
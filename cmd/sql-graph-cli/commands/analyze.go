@@ -43,6 +43,16 @@ func NewAnalyzeCmd() *cobra.Command {
 		connectionTimeout int
 		queryTimeout      int
 		maxConnections    int
+		noCache           bool
+		refresh           bool
+		cacheTTL          time.Duration
+		profile           bool
+		profileSampleSize int
+		maxCandidates     int
+		maxInferred       int
+		estimateOnly      bool
+		discoverIndexes   bool
+		dictionary        string
 
 		// PostgreSQL specific flags
 		schema           string
@@ -74,7 +84,13 @@ Supports both MySQL and PostgreSQL databases with database-specific optimization
   sql-graph-cli analyze --db-type mysql --host localhost --database mydb --whitelist "users,orders,products"
 
   # Save analysis to JSON file
-  sql-graph-cli analyze --db-type postgresql --host localhost --database chinook --output analysis.json`,
+  sql-graph-cli analyze --db-type postgresql --host localhost --database chinook --output analysis.json
+
+  # Data dictionary of the schema for documentation or compliance reviews
+  sql-graph-cli analyze --db-type mysql --host localhost --database mydb --dictionary markdown --output dictionary.md
+
+  # Pre-flight estimate from catalog statistics, without reading any rows
+  sql-graph-cli analyze --db-type mysql --host localhost --database mydb --estimate-only --format json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runAnalyze(cmd, analyzeOptions{
 				DBType:            models.DatabaseType(dbType),
@@ -92,6 +108,16 @@ Supports both MySQL and PostgreSQL databases with database-specific optimization
 				ConnectionTimeout: connectionTimeout,
 				QueryTimeout:      queryTimeout,
 				MaxConnections:    maxConnections,
+				NoCache:           noCache,
+				Refresh:           refresh,
+				CacheTTL:          cacheTTL,
+				Profile:           profile,
+				ProfileSampleSize: profileSampleSize,
+				MaxCandidates:     maxCandidates,
+				MaxInferred:       maxInferred,
+				EstimateOnly:      estimateOnly,
+				DiscoverIndexes:   discoverIndexes,
+				Dictionary:        dictionary,
 				// PostgreSQL specific
 				Schema:           schema,
 				SSLMode:          sslMode,
@@ -123,6 +149,20 @@ Supports both MySQL and PostgreSQL databases with database-specific optimization
 
 	// Control flags
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Perform analysis without generating transformation rules")
+	cmd.Flags().BoolVar(&estimateOnly, "estimate-only", false, "Only report estimated rows, size, processing order and duration from catalog statistics")
+	cmd.Flags().StringVar(&dictionary, "dictionary", "", "Write a data dictionary (markdown, html or json) instead of the analysis")
+	cmd.Flags().BoolVar(&discoverIndexes, "indexes", false, "Discover table indexes to refine relationship hints and recommend index changes (one catalog query per table)")
+
+	// Schema cache flags
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Do not read or write the schema analysis cache")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore the cached schema and re-analyze, updating the cache")
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", services.DefaultSchemaCacheTTL, "How long a cached schema analysis is reused")
+
+	// Relationship profiling flags
+	cmd.Flags().BoolVar(&profile, "profile", false, "Sample data to adjust implicit relationship confidence by actual key overlap (slower)")
+	cmd.Flags().IntVar(&profileSampleSize, "profile-sample-size", services.DefaultProfileSampleSize, "Distinct values sampled per candidate relationship when profiling")
+	cmd.Flags().IntVar(&maxCandidates, "max-candidates-per-column", services.DefaultMaxCandidatesPerColumn, "Tables one column may be linked to by naming convention, best match first")
+	cmd.Flags().IntVar(&maxInferred, "max-inferred-relationships", services.DefaultMaxInferredRelationships, "Maximum implicit relationships reported for the schema")
 
 	// Connection settings
 	cmd.Flags().IntVar(&connectionTimeout, "connection-timeout", 30, "Connection timeout in seconds")
@@ -163,6 +203,16 @@ type analyzeOptions struct {
 	ConnectionTimeout int
 	QueryTimeout      int
 	MaxConnections    int
+	NoCache           bool
+	Refresh           bool
+	CacheTTL          time.Duration
+	Profile           bool
+	ProfileSampleSize int
+	MaxCandidates     int
+	MaxInferred       int
+	EstimateOnly      bool
+	DiscoverIndexes   bool
+	Dictionary        string
 
 	// PostgreSQL specific options
 	Schema           string
@@ -180,12 +230,13 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 
 	// Auto-detect port if not specified
 	if opts.Port == 0 {
-		switch opts.DBType {
-		case models.DatabaseTypeMySQL:
-			opts.Port = 3306
-		case models.DatabaseTypePostgreSQL:
-			opts.Port = 5432
-		}
+		opts.Port = models.DefaultPort(opts.DBType)
+	}
+
+	switch opts.Dictionary {
+	case "", services.DictionaryFormatMarkdown, services.DictionaryFormatHTML, services.DictionaryFormatJSON:
+	default:
+		return fmt.Errorf("unsupported dictionary format %q (expected markdown, html or json)", opts.Dictionary)
 	}
 
 	// Build database-specific configuration
@@ -205,6 +256,7 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 				TableWhitelist:   opts.TableWhitelist,
 				TableBlacklist:   opts.TableBlacklist,
 				RowLimitPerTable: opts.RowLimit,
+				DiscoverIndexes:  opts.DiscoverIndexes,
 			},
 
 			Security: models.SecurityConfig{
@@ -252,6 +304,7 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 				TableWhitelist:   opts.TableWhitelist,
 				TableBlacklist:   opts.TableBlacklist,
 				RowLimitPerTable: opts.RowLimit,
+				DiscoverIndexes:  opts.DiscoverIndexes,
 			},
 
 			Security: models.SecurityConfig{
@@ -288,6 +341,20 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 	// Create universal database service
 	dbService := services.NewUniversalDatabaseService(repo, config)
 
+	if !opts.NoCache {
+		cache, err := services.NewSchemaAnalysisCache("", opts.CacheTTL)
+		if err != nil {
+			fmt.Printf("WARN  Schema cache unavailable: %v\n", err)
+		} else {
+			dbService.SetSchemaCache(cache, opts.Refresh)
+		}
+	}
+	dbService.SetRelationshipProfiling(opts.Profile, opts.ProfileSampleSize)
+	dbService.SetInferenceLimits(services.InferenceLimits{
+		MaxCandidatesPerColumn: opts.MaxCandidates,
+		MaxRelationships:       opts.MaxInferred,
+	})
+
 	// Validate configuration
 	fmt.Printf("🔧 Validating configuration...\n")
 	if err := dbService.ValidateConfiguration(); err != nil {
@@ -316,6 +383,16 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 	if opts.DryRun {
 		fmt.Printf("Dry run mode: analysis only, no rule generation\n")
 	}
+	if opts.DiscoverIndexes {
+		fmt.Printf("Index discovery: enabled\n")
+	}
+	if opts.Profile {
+		fmt.Printf("Relationship profiling: sampling up to %d values per candidate\n", opts.ProfileSampleSize)
+	}
+
+	if opts.EstimateOnly {
+		return runEstimate(ctx, dbService, opts)
+	}
 
 	// Start analysis
 	fmt.Printf("\n🔍 Starting database analysis...\n")
@@ -331,7 +408,15 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 	}
 
 	duration := time.Since(startTime)
-	fmt.Printf("Analysis completed in %v\n", duration)
+	if result.FromCache {
+		fmt.Printf("Analysis loaded from cache in %v (use --refresh to re-analyze)\n", duration)
+	} else {
+		fmt.Printf("Analysis completed in %v\n", duration)
+	}
+
+	if opts.Dictionary != "" {
+		return outputDictionary(result, opts.Dictionary, opts.OutputFile)
+	}
 
 	// Output results based on format
 	switch opts.OutputFormat {
@@ -344,6 +429,41 @@ func runAnalyze(cmd *cobra.Command, opts analyzeOptions) error {
 	}
 }
 
+func runEstimate(ctx context.Context, dbService *services.UniversalDatabaseService, opts analyzeOptions) error {
+	fmt.Printf("\n📏 Estimating dataset from catalog statistics...\n")
+
+	info, err := dbService.EstimateDataset(ctx)
+	if err != nil {
+		return fmt.Errorf("dataset estimation failed: %w", err)
+	}
+
+	if opts.OutputFormat == "json" || opts.OutputFormat == "yaml" {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return writeOutput(string(data), opts.OutputFile)
+	}
+
+	var output strings.Builder
+	output.WriteString("\n" + strings.Repeat("=", 60) + "\n")
+	output.WriteString("PRE-FLIGHT DATASET ESTIMATE\n")
+	output.WriteString(strings.Repeat("=", 60) + "\n\n")
+	output.WriteString(fmt.Sprintf("   Tables: %d\n", info.TotalTables))
+	output.WriteString(fmt.Sprintf("   Estimated Rows: %d\n", info.TotalRows))
+	output.WriteString(fmt.Sprintf("   Estimated Size: %.2f MB\n", info.EstimatedSizeMB))
+	output.WriteString(fmt.Sprintf("   Estimated Duration: %v\n", info.EstimatedDuration.Round(time.Second)))
+
+	if len(info.ProcessingOrder) > 0 {
+		output.WriteString("\nINFO PROCESSING ORDER:\n")
+		for i, table := range info.ProcessingOrder {
+			output.WriteString(fmt.Sprintf("   %3d. %-20s %d rows\n", i+1, table, info.TableSizes[table]))
+		}
+	}
+
+	return writeOutput(output.String(), opts.OutputFile)
+}
+
 func outputSummary(result *models.UniversalDatabaseAnalysisResult, outputFile string) error {
 	var output strings.Builder
 
@@ -409,6 +529,19 @@ func outputSummary(result *models.UniversalDatabaseAnalysisResult, outputFile st
 		}
 	}
 
+	// Implicit relationships
+	if result.SchemaAnalysis != nil && len(result.SchemaAnalysis.ImplicitRelationships) > 0 {
+		output.WriteString("\nLINK IMPLICIT RELATIONSHIPS:\n")
+		for _, rel := range result.SchemaAnalysis.ImplicitRelationships {
+			profile := ""
+			if rel.Profiled {
+				profile = fmt.Sprintf(", %.0f%% of %d sampled values match", rel.MatchRatio*100, rel.SampledRows)
+			}
+			output.WriteString(fmt.Sprintf("   %s.%s -> %s.%s (confidence %.2f%s)\n",
+				rel.FromTable, rel.FromColumn, rel.ToTable, rel.ToColumn, rel.Confidence, profile))
+		}
+	}
+
 	// Warnings and recommendations
 	if result.Summary != nil {
 		if len(result.Summary.Warnings) > 0 {
@@ -435,6 +568,14 @@ func outputSummary(result *models.UniversalDatabaseAnalysisResult, outputFile st
 	return writeOutput(output.String(), outputFile)
 }
 
+func outputDictionary(result *models.UniversalDatabaseAnalysisResult, format string, outputFile string) error {
+	data, _, err := services.BuildDataDictionary(result).Render(format)
+	if err != nil {
+		return err
+	}
+	return writeOutput(string(data), outputFile)
+}
+
 func outputJSON(result *models.UniversalDatabaseAnalysisResult, outputFile string) error {
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
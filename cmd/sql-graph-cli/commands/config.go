@@ -16,6 +16,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"sql-graph-visualizer/internal/domain/repositories/config"
+
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -42,12 +45,17 @@ func newConfigValidateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate configuration file",
-		Long:  `Validate the syntax and structure of a configuration file without connecting to databases.`,
+		Long: `Validate the syntax and structure of a configuration file without connecting to databases.
+
+Checks required connection fields, the database type, durations and transform rules, and
+exits with a non-zero status listing every error found.`,
 		Example: `  # Validate configuration file
   sql-graph-cli config validate --config mysql-production.yml
 
   # Validate current directory config
   sql-graph-cli config validate`,
+		// Validation errors are listed by the command; usage would only bury them
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runConfigValidate(configFile)
 		},
@@ -125,60 +133,37 @@ func runConfigValidate(configFile string) error {
 		fmt.Printf("📄 Validating: %s\n", configFile)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		return fmt.Errorf("configuration file not found: %s", configFile)
-	}
-
-	// Read file
-	// #nosec G304 - configFile is validated and comes from user input
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to read configuration file: %w", err)
-	}
-
-	fmt.Printf("📏 File size: %d bytes\n", len(data))
-
-	// Parse as YAML
-	var config map[string]interface{}
-	err = yaml.Unmarshal(data, &config)
-	if err != nil {
-		fmt.Printf("YAML syntax error: %v\n", err)
-		return fmt.Errorf("invalid YAML syntax")
-	}
-
-	fmt.Println("YAML syntax is valid")
-
-	// Basic structure validation
-	validationErrors := validateConfigStructure(config)
-
+	validationErrors := validateConfigFile(configFile)
 	if len(validationErrors) > 0 {
-		fmt.Printf(" Configuration warnings:\n")
+		fmt.Printf("Found %d configuration errors:\n", len(validationErrors))
 		for _, err := range validationErrors {
-			fmt.Printf("   • %s\n", err)
+			fmt.Printf("   • %v\n", err)
 		}
-	} else {
-		fmt.Println("Configuration structure is valid")
+		return fmt.Errorf("configuration %s is invalid: %d errors", configFile, len(validationErrors))
 	}
 
-	// Connection parameters validation
-	if mysqlConfig, ok := config["mysql"].(map[interface{}]interface{}); ok {
-		validateMySQLConfig(mysqlConfig)
-	}
+	fmt.Println("No issues found - configuration is ready for use")
+	return nil
+}
 
-	if neo4jConfig, ok := config["neo4j"].(map[interface{}]interface{}); ok {
-		validateNeo4jConfig(neo4jConfig)
+// validateConfigFile loads configFile the way the server does, including its rule files,
+// and validates its structure. No database or Neo4j connection is opened.
+func validateConfigFile(configFile string) []error {
+	if _, err := os.Stat(configFile); err != nil {
+		return []error{fmt.Errorf("configuration file not found: %s", configFile)}
 	}
 
-	fmt.Println("\nDONE Configuration validation completed!")
+	// The loader logs every rule it reads and every error it returns; the errors are
+	// listed by the command instead
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.FatalLevel)
+	defer logrus.SetLevel(level)
 
-	if len(validationErrors) == 0 {
-		fmt.Println("No issues found - configuration is ready for use")
-	} else {
-		fmt.Printf(" Found %d warnings - please review before use\n", len(validationErrors))
+	cfg, err := config.LoadFile(configFile)
+	if err != nil {
+		return []error{err}
 	}
-
-	return nil
+	return config.Validate(cfg)
 }
 
 func runConfigShow(configFile string, format string) error {
@@ -311,70 +296,6 @@ func findConfigFile() string {
 	return ""
 }
 
-func validateConfigStructure(config map[string]interface{}) []string {
-	var errors []string
-
-	// Check for required sections
-	if _, ok := config["mysql"]; !ok {
-		errors = append(errors, "Missing 'mysql' section")
-	}
-
-	if _, ok := config["neo4j"]; !ok {
-		errors = append(errors, "Missing 'neo4j' section - required for graph transformation")
-	}
-
-	return errors
-}
-
-func validateMySQLConfig(mysqlConfig map[interface{}]interface{}) {
-	fmt.Println("\nDETAIL MySQL Configuration:")
-
-	// Check required fields
-	requiredFields := []string{"host", "port", "username", "password", "database"}
-	for _, field := range requiredFields {
-		if _, ok := mysqlConfig[field]; !ok {
-			if _, ok := mysqlConfig[field+"s"]; !ok { // Check plural form too
-				fmt.Printf("   WARN  Missing required field: %s\n", field)
-			}
-		}
-	}
-
-	// Check connection mode
-	if mode, ok := mysqlConfig["connection_mode"]; ok {
-		if mode == "existing" {
-			fmt.Println("   PASS Connection mode: existing (direct database connection)")
-		}
-	} else {
-		fmt.Println("   WARN  Consider adding 'connection_mode: existing' for direct connections")
-	}
-
-	// Check security settings
-	if _, ok := mysqlConfig["security"]; ok {
-		fmt.Println("   PASS Security configuration present")
-	} else {
-		fmt.Println("   WARN  Consider adding security configuration for production use")
-	}
-}
-
-func validateNeo4jConfig(neo4jConfig map[interface{}]interface{}) {
-	fmt.Println("\nDETAIL Neo4j Configuration:")
-
-	// Check required fields
-	if _, ok := neo4jConfig["uri"]; !ok {
-		fmt.Println("   WARN  Missing required field: uri")
-	} else {
-		fmt.Println("   PASS URI configured")
-	}
-
-	if _, ok := neo4jConfig["user"]; !ok {
-		fmt.Println("   WARN  Missing required field: user")
-	}
-
-	if _, ok := neo4jConfig["password"]; !ok {
-		fmt.Println("   WARN  Missing required field: password")
-	}
-}
-
 // Template functions (simplified versions)
 func getMinimalConfigTemplate() string {
 	return `# SQL Graph Visualizer - Minimal Configuration
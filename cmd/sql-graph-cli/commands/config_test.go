@@ -0,0 +1,109 @@
+/*
+ * SQL Graph Visualizer - Config Command Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig points at hosts that do not exist: validation must not connect
+const validConfig = `database:
+  type: "mysql"
+  mysql:
+    host: "db.invalid"
+    port: 3306
+    username: "graph"
+    database: "shop"
+neo4j:
+  uri: "bolt://neo4j.invalid:7687"
+startup:
+  connect_max_wait: "2m"
+transform_rules:
+  - name: "customers"
+    rule_type: "node"
+    source:
+      type: "query"
+      value: "SELECT id, name FROM customers"
+    target_type: "Customer"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestConfigValidateAcceptsValidConfig(t *testing.T) {
+	path := writeConfig(t, validConfig)
+
+	assert.Empty(t, validateConfigFile(path))
+	assert.NoError(t, runConfigValidate(path))
+}
+
+func TestConfigValidateRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		message string
+	}{
+		{
+			name:    "bad duration",
+			old:     `connect_max_wait: "2m"`,
+			new:     `connect_max_wait: "two minutes"`,
+			message: `startup.connect_max_wait "two minutes" is not a valid duration`,
+		},
+		{
+			name:    "unknown database type",
+			old:     `type: "mysql"`,
+			new:     `type: "sqlite"`,
+			message: `database.type "sqlite" is unknown`,
+		},
+		{
+			name:    "malformed rule",
+			old:     `target_type: "Customer"`,
+			new:     `relationship_type: "KNOWS"`,
+			message: `transform rule "customers": target_type is required for node rules`,
+		},
+		{
+			name:    "invalid YAML",
+			old:     `transform_rules:`,
+			new:     `transform_rules: [`,
+			message: "yaml:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Contains(t, validConfig, tt.old)
+			path := writeConfig(t, strings.Replace(validConfig, tt.old, tt.new, 1))
+
+			errs := validateConfigFile(path)
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), tt.message)
+
+			err := runConfigValidate(path)
+			require.Error(t, err, "the command exits non-zero")
+			assert.Contains(t, err.Error(), "1 errors")
+		})
+	}
+}
+
+func TestConfigValidateMissingFile(t *testing.T) {
+	err := runConfigValidate(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
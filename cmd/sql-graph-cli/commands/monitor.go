@@ -0,0 +1,360 @@
+/*
+ * SQL Graph Visualizer - Monitor Command
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/performance"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// clearScreen moves the cursor home and clears the terminal before each refresh
+const clearScreen = "\033[H\033[2J"
+
+type monitorOptions struct {
+	Interval   time.Duration
+	TopQueries int
+	MaxAlerts  int
+	// Count stops after this many refreshes; zero runs until interrupted
+	Count int
+	// Clear redraws in place instead of appending each refresh
+	Clear bool
+	// Token is sent in an auth message when the server restricts WebSocket clients
+	Token string
+	// APIToken is sent as a bearer token on the upgrade request when the server has API
+	// tokens configured
+	APIToken string
+}
+
+// NewMonitorCmd creates the monitor command
+func NewMonitorCmd() *cobra.Command {
+	var serverURL string
+	opts := monitorOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Stream live performance metrics to the terminal",
+		Long: `Connects to the performance WebSocket of a running server and shows a top-style view of
+queries per second, the slowest queries and recent alerts, refreshed on every interval.`,
+		Example: `  # Watch a local server
+  sql-graph-cli monitor --server http://localhost:8080
+
+  # Print a single snapshot and exit
+  sql-graph-cli monitor --count 1
+
+  # Against a server with API tokens and WebSocket auth configured
+  sql-graph-cli monitor --api-token "$API_READ_TOKEN" --token "$WS_ADMIN_TOKEN"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			wsURL, err := performanceWebSocketURL(serverURL)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			opts.Clear = opts.Count != 1
+			return runMonitor(ctx, wsURL, cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&serverURL, "server", "http://localhost:8080", "API server URL")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 2*time.Second, "Refresh interval")
+	cmd.Flags().IntVar(&opts.TopQueries, "top", 10, "Number of slow queries to show")
+	cmd.Flags().IntVar(&opts.MaxAlerts, "alerts", 5, "Number of recent alerts to show")
+	cmd.Flags().IntVar(&opts.Count, "count", 0, "Exit after this many refreshes (0 runs until interrupted)")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "WebSocket auth token, when the server restricts clients")
+	cmd.Flags().StringVar(&opts.APIToken, "api-token", "", apiTokenUsage)
+
+	return cmd
+}
+
+// performanceWebSocketURL maps the API server URL onto its performance WebSocket endpoint
+func performanceWebSocketURL(serverURL string) (string, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "":
+		parsed.Scheme = "ws"
+	case "https":
+		parsed.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("unsupported server URL scheme %q", parsed.Scheme)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/ws/performance"
+	return parsed.String(), nil
+}
+
+// monitorFrame is a WebSocketMessage whose data is decoded once the topic is known
+type monitorFrame struct {
+	Type  string          `json:"type"`
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data"`
+	ID    string          `json:"id"`
+}
+
+// monitorState accumulates what the server has streamed since the last refresh
+type monitorState struct {
+	mu      sync.Mutex
+	metrics *performance.RealtimeMetrics
+	alerts  []performance.PerformanceAlert
+	updated time.Time
+	// chunks holds the payload pieces of oversized messages by message ID
+	chunks map[string][][]byte
+}
+
+func newMonitorState() *monitorState {
+	return &monitorState{chunks: make(map[string][][]byte)}
+}
+
+// apply decodes one WebSocket frame, reassembling chunked messages and unpacking batches
+// before handling them
+func (s *monitorState) apply(raw []byte) error {
+	var frame monitorFrame
+	if err := json.Unmarshal(raw, &frame); err != nil {
+		return fmt.Errorf("failed to decode frame: %w", err)
+	}
+
+	if frame.Type == "error" {
+		var rejection performance.WebSocketError
+		if err := json.Unmarshal(frame.Data, &rejection); err != nil {
+			return fmt.Errorf("failed to decode error: %w", err)
+		}
+		return fmt.Errorf("server rejected request: %s (%s)", rejection.Message, rejection.Code)
+	}
+
+	if frame.Type == "chunk" {
+		var chunk performance.WebSocketChunk
+		if err := json.Unmarshal(frame.Data, &chunk); err != nil {
+			return fmt.Errorf("failed to decode chunk: %w", err)
+		}
+		message, complete := s.addChunk(chunk)
+		if !complete {
+			return nil
+		}
+		return s.apply(message)
+	}
+
+	if frame.Type == "batch" {
+		var parts []json.RawMessage
+		if err := json.Unmarshal(frame.Data, &parts); err != nil {
+			return fmt.Errorf("failed to decode batch: %w", err)
+		}
+		for _, part := range parts {
+			if err := s.apply(part); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch frame.Topic {
+	case "metrics":
+		var metrics performance.RealtimeMetrics
+		if err := json.Unmarshal(frame.Data, &metrics); err != nil {
+			return fmt.Errorf("failed to decode metrics: %w", err)
+		}
+		s.mu.Lock()
+		s.metrics = &metrics
+		s.alerts = append(s.alerts, metrics.Alerts...)
+		s.updated = time.Now()
+		s.mu.Unlock()
+	case "alerts":
+		var alert performance.PerformanceAlert
+		if err := json.Unmarshal(frame.Data, &alert); err != nil {
+			return fmt.Errorf("failed to decode alert: %w", err)
+		}
+		s.mu.Lock()
+		s.alerts = append(s.alerts, alert)
+		s.updated = time.Now()
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// addChunk stores a chunk and returns the whole message once every sequence has arrived
+func (s *monitorState) addChunk(chunk performance.WebSocketChunk) ([]byte, bool) {
+	if chunk.Total <= 0 || chunk.Sequence < 0 || chunk.Sequence >= chunk.Total {
+		return nil, false
+	}
+
+	parts, ok := s.chunks[chunk.MessageID]
+	if !ok {
+		parts = make([][]byte, chunk.Total)
+		s.chunks[chunk.MessageID] = parts
+	}
+	parts[chunk.Sequence] = chunk.Payload
+
+	var message []byte
+	for _, part := range parts {
+		if part == nil {
+			return nil, false
+		}
+		message = append(message, part...)
+	}
+	delete(s.chunks, chunk.MessageID)
+	return message, true
+}
+
+// render writes the current view; it reports false while no metrics have arrived yet
+func (s *monitorState) render(w io.Writer, source string, opts monitorOptions) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metrics == nil {
+		return false
+	}
+
+	if opts.Clear {
+		fmt.Fprint(w, clearScreen)
+	}
+	fmt.Fprintf(w, "SQL Graph Visualizer - live performance (%s)  updated %s\n\n", source, s.updated.Format("15:04:05"))
+
+	if db := s.metrics.DatabaseMetrics; db != nil {
+		fmt.Fprintf(w, "QPS: %.1f   Slow queries: %d   Connections: %d/%d   Active: %d\n",
+			db.QueriesPerSecond, db.SlowQueries, db.ConnectionsUsed, db.ConnectionsMax, s.metrics.ActiveConnections)
+		fmt.Fprintf(w, "Deadlocks/min: %.1f   Lock waits/min: %.1f   Waiting locks: %d\n\n",
+			db.DeadlocksPerMinute, db.LockWaitsPerMinute, db.CurrentLockWaits)
+	}
+
+	queries := append([]performance.QueryPerformanceMetric(nil), s.metrics.TopQueries...)
+	sort.SliceStable(queries, func(i, j int) bool {
+		return queries[i].AvgExecutionTime > queries[j].AvgExecutionTime
+	})
+	if opts.TopQueries > 0 && len(queries) > opts.TopQueries {
+		queries = queries[:opts.TopQueries]
+	}
+
+	fmt.Fprintln(w, "SLOWEST QUERIES")
+	fmt.Fprintf(w, "  %10s %10s %10s %8s  %s\n", "AVG(ms)", "MAX(ms)", "CALLS", "ERRORS", "QUERY")
+	if len(queries) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for _, query := range queries {
+		fmt.Fprintf(w, "  %10.2f %10.2f %10d %8d  %s\n", query.AvgExecutionTime, query.MaxExecutionTime,
+			query.ExecutionCount, query.ErrorCount, truncateQuery(query.DigestText, 80))
+	}
+
+	if len(s.metrics.ContendedTables) > 0 {
+		fmt.Fprintln(w, "\nLOCK CONTENTION")
+		for _, table := range s.metrics.ContendedTables {
+			fmt.Fprintf(w, "  %6d waiting  %s.%s\n", table.WaitingLocks, table.SchemaName, table.TableName)
+		}
+	}
+
+	alerts := s.alerts
+	if opts.MaxAlerts > 0 && len(alerts) > opts.MaxAlerts {
+		alerts = alerts[len(alerts)-opts.MaxAlerts:]
+	}
+	s.alerts = alerts
+
+	fmt.Fprintln(w, "\nALERTS")
+	if len(alerts) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for i := len(alerts) - 1; i >= 0; i-- {
+		alert := alerts[i]
+		fmt.Fprintf(w, "  [%s] %s: %s\n", strings.ToUpper(alert.Severity), alert.Title, alert.Description)
+	}
+	return true
+}
+
+// truncateQuery collapses whitespace so each query fits on one line
+func truncateQuery(query string, limit int) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) <= limit {
+		return query
+	}
+	return query[:limit-3] + "..."
+}
+
+// runMonitor streams frames from wsURL into the view and redraws it on every interval
+func runMonitor(ctx context.Context, wsURL string, out io.Writer, opts monitorOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, apiTokenHeader(opts.APIToken))
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			return fmt.Errorf("server rejected the connection with status %d; pass an API token with --api-token", resp.StatusCode)
+		}
+		return fmt.Errorf("failed to connect to %s: %w", wsURL, err)
+	}
+	defer conn.Close()
+
+	if opts.Token != "" {
+		if err := conn.WriteJSON(map[string]string{"type": "auth", "token": opts.Token}); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	// Metrics are published on their own topic, which clients are not subscribed to by default
+	if err := conn.WriteJSON(map[string]string{"type": "subscribe", "topic": "metrics"}); err != nil {
+		return fmt.Errorf("failed to subscribe to metrics: %w", err)
+	}
+
+	state := newMonitorState()
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			_, frame, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			if err := state.apply(frame); err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping frame: %v\n", err)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	refreshes := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return fmt.Errorf("connection lost: %w", err)
+		case <-ticker.C:
+			if !state.render(out, wsURL, opts) {
+				continue
+			}
+			refreshes++
+			if opts.Count > 0 && refreshes >= opts.Count {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * SQL Graph Visualizer - Monitor Command Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/performance"
+	"sql-graph-visualizer/internal/interfaces/api"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFramesServer serves a WebSocket that records the client's first message and then writes frames
+func newFramesServer(t *testing.T, frames [][]byte) (*httptest.Server, chan map[string]string) {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	received := make(chan map[string]string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ws/performance", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var subscribe map[string]string
+		if err := conn.ReadJSON(&subscribe); err == nil {
+			received <- subscribe
+		}
+		for _, frame := range frames {
+			if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				return
+			}
+		}
+		// Keep the connection open until the client hangs up
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func encodeFrame(t *testing.T, message performance.WebSocketMessage) []byte {
+	t.Helper()
+	frame, err := json.Marshal(message)
+	require.NoError(t, err)
+	return frame
+}
+
+func sampleMetrics() *performance.RealtimeMetrics {
+	return &performance.RealtimeMetrics{
+		DatabaseMetrics: &performance.DatabaseMetrics{QueriesPerSecond: 42.5, ConnectionsUsed: 3, ConnectionsMax: 151, DeadlocksPerMinute: 6},
+		ContendedTables: []performance.TableLockStatistic{{SchemaName: "shop", TableName: "orders", WaitingLocks: 4}},
+		TopQueries: []performance.QueryPerformanceMetric{
+			{DigestText: "SELECT * FROM `users`", ExecutionCount: 10, AvgExecutionTime: 1.5, MaxExecutionTime: 3},
+			{DigestText: "SELECT *\n  FROM `orders` WHERE `user_id` = ?", ExecutionCount: 4, AvgExecutionTime: 120.25, MaxExecutionTime: 300},
+		},
+	}
+}
+
+func TestRunMonitor_RendersStreamedMetricsAndAlerts(t *testing.T) {
+	alert := encodeFrame(t, performance.WebSocketMessage{Type: "data", Topic: "alerts", Data: performance.PerformanceAlert{
+		Severity: "high", Title: "Slow Query Detected", Description: "Query execution time 120.25ms exceeds threshold",
+	}})
+	metrics := encodeFrame(t, performance.WebSocketMessage{Type: "data", Topic: "metrics", Data: sampleMetrics()})
+	server, received := newFramesServer(t, [][]byte{alert, metrics})
+
+	wsURL, err := performanceWebSocketURL(server.URL)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, runMonitor(ctx, wsURL, &out, monitorOptions{Interval: 20 * time.Millisecond, TopQueries: 10, MaxAlerts: 5, Count: 1}))
+
+	assert.Equal(t, map[string]string{"type": "subscribe", "topic": "metrics"}, <-received)
+
+	rendered := out.String()
+	assert.Contains(t, rendered, "QPS: 42.5")
+	assert.Contains(t, rendered, "Connections: 3/151")
+	assert.Contains(t, rendered, "Deadlocks/min: 6.0")
+	assert.Contains(t, rendered, "4 waiting  shop.orders")
+	assert.Contains(t, rendered, "[HIGH] Slow Query Detected")
+	assert.NotContains(t, rendered, clearScreen)
+
+	orders := strings.Index(rendered, "SELECT * FROM `orders` WHERE `user_id` = ?")
+	users := strings.Index(rendered, "SELECT * FROM `users`")
+	require.True(t, orders >= 0 && users >= 0, rendered)
+	assert.Less(t, orders, users, "slowest query is listed first")
+}
+
+func TestRunMonitor_SendsAPIToken(t *testing.T) {
+	metrics := encodeFrame(t, performance.WebSocketMessage{Type: "data", Topic: "metrics", Data: sampleMetrics()})
+	server, _ := newFramesServer(t, [][]byte{metrics})
+	server.Config.Handler = api.NewScopeMiddleware(logrus.New(), api.APIAuthConfig{ReadTokens: []string{"reader"}})(server.Config.Handler)
+
+	wsURL, err := performanceWebSocketURL(server.URL)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = runMonitor(ctx, wsURL, &bytes.Buffer{}, monitorOptions{Interval: 20 * time.Millisecond, Count: 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--api-token")
+
+	var out bytes.Buffer
+	require.NoError(t, runMonitor(ctx, wsURL, &out, monitorOptions{Interval: 20 * time.Millisecond, Count: 1, APIToken: "reader"}))
+	assert.Contains(t, out.String(), "QPS: 42.5")
+}
+
+func TestMonitorState_ReassemblesChunkedMetrics(t *testing.T) {
+	message := encodeFrame(t, performance.WebSocketMessage{Type: "data", Topic: "metrics", Data: sampleMetrics(), ID: "msg-1"})
+	half := len(message) / 2
+
+	state := newMonitorState()
+	for sequence, payload := range [][]byte{message[half:], message[:half]} {
+		// Deliver the second half first to check ordering by sequence
+		chunk := performance.WebSocketChunk{MessageID: "msg-1", Sequence: 1 - sequence, Total: 2, Payload: payload}
+		require.NoError(t, state.apply(encodeFrame(t, performance.WebSocketMessage{Type: "chunk", Topic: "metrics", Data: chunk})))
+	}
+
+	var out bytes.Buffer
+	require.True(t, state.render(&out, "test", monitorOptions{TopQueries: 1}))
+	assert.Contains(t, out.String(), "`orders`")
+	assert.NotContains(t, out.String(), "`users`", "only the top query is shown")
+	assert.Empty(t, state.chunks)
+}
+
+func TestMonitorState_UnpacksBatches(t *testing.T) {
+	state := newMonitorState()
+	require.NoError(t, state.apply(encodeFrame(t, performance.WebSocketMessage{
+		Type: "batch",
+		Data: []performance.WebSocketMessage{
+			{Type: "data", Topic: "metrics", Data: sampleMetrics(), ID: "msg-1"},
+			{Type: "data", Topic: "alerts", Data: performance.PerformanceAlert{Title: "Deadlocks Detected", Severity: "high"}, ID: "msg-2"},
+		},
+	})))
+
+	require.NotNil(t, state.metrics)
+	assert.InDelta(t, 42.5, state.metrics.DatabaseMetrics.QueriesPerSecond, 1e-9)
+	require.NotEmpty(t, state.alerts)
+	assert.Equal(t, "Deadlocks Detected", state.alerts[len(state.alerts)-1].Title)
+}
+
+func TestMonitorState_ReportsRejectedSubscriptions(t *testing.T) {
+	state := newMonitorState()
+	err := state.apply(encodeFrame(t, performance.WebSocketMessage{
+		Type: "error", Topic: "metrics",
+		Data: performance.WebSocketError{Code: "forbidden", Message: `not permitted to subscribe to "metrics"`},
+	}))
+	assert.ErrorContains(t, err, "forbidden")
+	assert.Nil(t, state.metrics, "an error frame is not decoded as metrics")
+}
+
+func TestMonitorState_RenderWaitsForMetrics(t *testing.T) {
+	var out bytes.Buffer
+	assert.False(t, newMonitorState().render(&out, "test", monitorOptions{}))
+	assert.Empty(t, out.String())
+}
+
+func TestPerformanceWebSocketURL(t *testing.T) {
+	cases := map[string]string{
+		"http://localhost:8080":    "ws://localhost:8080/ws/performance",
+		"https://example.com/app/": "wss://example.com/app/ws/performance",
+		"ws://localhost:8080":      "ws://localhost:8080/ws/performance",
+	}
+	for server, expected := range cases {
+		actual, err := performanceWebSocketURL(server)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+
+	_, err := performanceWebSocketURL("ftp://localhost")
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+/*
+ * SQL Graph Visualizer - Rules Command
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/services"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repositories/config"
+	"sql-graph-visualizer/internal/infrastructure/factories"
+
+	"github.com/spf13/cobra"
+)
+
+// ruleSchemaOptions holds the connection used to fingerprint or validate against a schema
+type ruleSchemaOptions struct {
+	DBType   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Database string
+	Schema   string
+	SSLMode  string
+}
+
+func (o *ruleSchemaOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.DBType, "db-type", "mysql", "Database type: mysql, postgresql")
+	cmd.Flags().StringVar(&o.Host, "host", "localhost", "Database host")
+	cmd.Flags().IntVar(&o.Port, "port", 0, "Database port (0 = auto-detect: MySQL=3306, PostgreSQL=5432)")
+	cmd.Flags().StringVar(&o.Username, "username", "", "Database username")
+	cmd.Flags().StringVar(&o.Password, "password", "", "Database password")
+	cmd.Flags().StringVar(&o.Database, "database", "", "Database name")
+	cmd.Flags().StringVar(&o.Schema, "schema", "public", "PostgreSQL schema name")
+	cmd.Flags().StringVar(&o.SSLMode, "ssl-mode", "prefer", "PostgreSQL SSL mode")
+}
+
+// schemaColumns connects with the options and lists every table's columns
+func (o *ruleSchemaOptions) schemaColumns(ctx context.Context) (config.SchemaColumns, error) {
+	dbType := models.DatabaseType(o.DBType)
+	port := o.Port
+	if port == 0 {
+		port = models.DefaultPort(dbType)
+	}
+
+	var dbConfig models.DatabaseConfig
+	switch dbType {
+	case models.DatabaseTypeMySQL:
+		dbConfig = &models.MySQLConfig{
+			Host:           o.Host,
+			Port:           port,
+			Username:       o.Username,
+			Password:       o.Password,
+			Database:       o.Database,
+			ConnectionMode: models.ConnectionModeExisting,
+			DataFiltering:  models.DataFilteringConfig{SchemaDiscovery: true},
+			Security:       models.SecurityConfig{ReadOnly: true, ConnectionTimeout: 10, QueryTimeout: 30, MaxConnections: 1},
+		}
+	case models.DatabaseTypePostgreSQL:
+		dbConfig = &models.PostgreSQLConfig{
+			Host:           o.Host,
+			Port:           port,
+			Username:       o.Username,
+			Password:       o.Password,
+			Database:       o.Database,
+			Schema:         o.Schema,
+			ConnectionMode: models.ConnectionModeExisting,
+			SSLConfig:      models.PostgreSQLSSLConfig{Mode: o.SSLMode},
+			DataFiltering:  models.DataFilteringConfig{SchemaDiscovery: true},
+			Security:       models.SecurityConfig{ReadOnly: true, ConnectionTimeout: 10, QueryTimeout: 30, MaxConnections: 1},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", o.DBType)
+	}
+
+	repo, err := factories.NewDatabaseRepositoryFactory().CreateRepository(dbType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database repository: %w", err)
+	}
+	return services.NewUniversalDatabaseService(repo, dbConfig).SchemaColumns(ctx)
+}
+
+// NewRulesCmd creates the rules command
+func NewRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Share transform rule sets as portable bundles",
+		Long: `Export the transform rules of a configuration into a single YAML or JSON bundle, and import
+bundles into a rules directory after validating them against a database schema.`,
+	}
+
+	cmd.AddCommand(newRulesExportCmd())
+	cmd.AddCommand(newRulesImportCmd())
+
+	return cmd
+}
+
+func newRulesExportCmd() *cobra.Command {
+	var (
+		configFile string
+		outputFile string
+		format     string
+		db         ruleSchemaOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export transform rules as a bundle",
+		Long: `Writes every transform rule of a configuration, including its transform_rules_dir, into one
+bundle. With --database the bundle records the fingerprint of that database's schema.`,
+		Example: `  # Export rules as YAML
+  sql-graph-cli rules export --config config/config.yml --output rules-bundle.yml
+
+  # Export as JSON with the fingerprint of the source schema
+  sql-graph-cli rules export --config config/config.yml --output rules-bundle.json \
+    --username user --password pass --database mydb`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesExport(cmd.Context(), configFile, outputFile, format, db)
+		},
+	}
+
+	cmd.Flags().StringVarP(&configFile, "config", "c", "config/config.yml", "Configuration file with the rules to export")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Bundle file (default: stdout)")
+	cmd.Flags().StringVar(&format, "format", "", "Bundle format: yaml, json (default: from the output extension, else yaml)")
+	db.addFlags(cmd)
+
+	return cmd
+}
+
+func newRulesImportCmd() *cobra.Command {
+	var (
+		bundleFile string
+		outputDir  string
+		name       string
+		dryRun     bool
+		db         ruleSchemaOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Validate a rule bundle and add it to a rules directory",
+		Long: `Checks that every table and column the bundle's rules read exists in the target database,
+then writes the rules as a rule file into the given directory (normally transform_rules_dir).`,
+		Example: `  # Import a bundle into the rules directory
+  sql-graph-cli rules import --bundle rules-bundle.yml --output-dir config/rules \
+    --username user --password pass --database mydb
+
+  # Only validate
+  sql-graph-cli rules import --bundle rules-bundle.json --dry-run --username user --password pass --database mydb`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesImport(cmd.Context(), bundleFile, outputDir, name, dryRun, db)
+		},
+	}
+
+	cmd.Flags().StringVar(&bundleFile, "bundle", "", "Rule bundle to import")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "config/rules", "Directory the rule file is written to")
+	cmd.Flags().StringVar(&name, "name", "", "Rule file name (default: the bundle file name)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate without writing the rule file")
+	db.addFlags(cmd)
+
+	cmd.MarkFlagRequired("bundle")
+	cmd.MarkFlagRequired("database")
+
+	return cmd
+}
+
+func runRulesExport(ctx context.Context, configFile, outputFile, format string, db ruleSchemaOptions) error {
+	path, err := filepath.Abs(configFile)
+	if err != nil {
+		return fmt.Errorf("invalid config path: %w", err)
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	dbType := cfg.GetDatabaseType()
+	var schema config.SchemaColumns
+	if db.Database != "" {
+		if schema, err = db.schemaColumns(ctx); err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		dbType = models.DatabaseType(db.DBType)
+	}
+
+	if format == "" {
+		format = "yaml"
+		if strings.EqualFold(filepath.Ext(outputFile), ".json") {
+			format = "json"
+		}
+	}
+
+	data, err := config.MarshalRuleBundle(config.NewRuleBundle(cfg.TransformRules, dbType, schema), format)
+	if err != nil {
+		return err
+	}
+
+	if outputFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outputFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	fmt.Printf("Exported %d transform rules to %s\n", len(cfg.TransformRules), outputFile)
+	return nil
+}
+
+func runRulesImport(ctx context.Context, bundleFile, outputDir, name string, dryRun bool, db ruleSchemaOptions) error {
+	// #nosec G304 - bundleFile comes from user input
+	data, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	bundle, err := config.ParseRuleBundle(data)
+	if err != nil {
+		return err
+	}
+
+	schema, err := db.schemaColumns(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	if err := config.ValidateRuleBundle(bundle, schema); err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle with %d transform rules matches the schema of %s\n", len(bundle.TransformRules), db.Database)
+	if bundle.SchemaFingerprint != "" && bundle.SchemaFingerprint != schema.Fingerprint() {
+		fmt.Println("WARN  The bundle was exported from a different schema layout; only the referenced columns were checked")
+	}
+	if dryRun {
+		return nil
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(bundleFile), filepath.Ext(bundleFile))
+	}
+	if err := os.MkdirAll(outputDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create rules directory: %w", err)
+	}
+	path, err := config.WriteRuleBundleFile(outputDir, name, bundle)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", path)
+	return nil
+}
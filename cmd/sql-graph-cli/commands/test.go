@@ -143,12 +143,7 @@ func runTest(opts testOptions) error {
 
 	// Auto-detect port if not specified
 	if opts.Port == 0 {
-		switch opts.DBType {
-		case models.DatabaseTypeMySQL:
-			opts.Port = 3306
-		case models.DatabaseTypePostgreSQL:
-			opts.Port = 5432
-		}
+		opts.Port = models.DefaultPort(opts.DBType)
 	}
 
 	// Build database-specific configuration for testing
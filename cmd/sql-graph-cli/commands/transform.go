@@ -0,0 +1,173 @@
+/*
+ * SQL Graph Visualizer - Transform Command
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewTransformCmd creates the transform command
+func NewTransformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transform",
+		Short: "Control the data transformation of a running server",
+		Long:  `Inspect or cancel the transformation running on a SQL Graph Visualizer server.`,
+	}
+
+	cmd.AddCommand(newTransformStatusCmd())
+	cmd.AddCommand(newTransformCancelCmd())
+
+	return cmd
+}
+
+func newTransformStatusCmd() *cobra.Command {
+	var serverURL, apiToken string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show progress of the active or last transform",
+		Example: `  # Show transform progress
+  sql-graph-cli transform status --server http://localhost:8080
+
+  # Against a server with API tokens configured
+  sql-graph-cli transform status --api-token "$API_READ_TOKEN"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransformRequest(http.MethodGet, serverURL, "/api/transform/status", apiToken)
+		},
+	}
+
+	cmd.Flags().StringVar(&serverURL, "server", "http://localhost:8080", "API server URL")
+	cmd.Flags().StringVar(&apiToken, "api-token", "", apiTokenUsage)
+	return cmd
+}
+
+func newTransformCancelCmd() *cobra.Command {
+	var serverURL, apiToken string
+
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel the running transform",
+		Long: `Cancels the running transform. The Neo4j batch in progress is rolled back; batches
+committed before cancellation are kept.`,
+		Example: `  # Cancel the transform running on a local server
+  sql-graph-cli transform cancel --server http://localhost:8080
+
+  # Against a server with API tokens configured; cancelling needs an admin token
+  sql-graph-cli transform cancel --api-token "$API_ADMIN_TOKEN"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransformRequest(http.MethodPost, serverURL, "/api/transform/cancel", apiToken)
+		},
+	}
+
+	cmd.Flags().StringVar(&serverURL, "server", "http://localhost:8080", "API server URL")
+	cmd.Flags().StringVar(&apiToken, "api-token", "", apiTokenUsage)
+	return cmd
+}
+
+type transformProgress struct {
+	Running              bool       `json:"running"`
+	Cancelled            bool       `json:"cancelled"`
+	Phase                string     `json:"phase"`
+	Rule                 string     `json:"rule"`
+	StartedAt            time.Time  `json:"started_at"`
+	FinishedAt           *time.Time `json:"finished_at"`
+	Error                string     `json:"error"`
+	NodesWritten         int        `json:"nodes_written"`
+	RelationshipsWritten int        `json:"relationships_written"`
+	RelationshipsSkipped int        `json:"relationships_skipped"`
+	BatchesCommitted     int        `json:"batches_committed"`
+}
+
+// apiTokenUsage describes the --api-token flag of the commands calling the API server
+const apiTokenUsage = "API token sent as a bearer token, when the server has api.auth tokens configured"
+
+// apiTokenHeader carries apiToken as the bearer token the API server checks; it is empty
+// without a token
+func apiTokenHeader(apiToken string) http.Header {
+	header := http.Header{}
+	if apiToken != "" {
+		header.Set("Authorization", "Bearer "+apiToken)
+	}
+	return header
+}
+
+func runTransformRequest(method, serverURL, path, apiToken string) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(serverURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header = apiTokenHeader(apiToken)
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Success bool              `json:"success"`
+		Data    transformProgress `json:"data"`
+		Error   *struct {
+			Message string `json:"message"`
+			Details string `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !response.Success {
+		if response.Error != nil {
+			return fmt.Errorf("%s", response.Error.Message)
+		}
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	printTransformProgress(response.Data)
+	return nil
+}
+
+func printTransformProgress(progress transformProgress) {
+	state := "finished"
+	switch {
+	case progress.Running:
+		state = "running"
+	case progress.Cancelled:
+		state = "cancelled"
+	case progress.Error != "":
+		state = "failed"
+	case progress.StartedAt.IsZero():
+		state = "not started"
+	}
+
+	fmt.Printf("Transform: %s\n", state)
+	if progress.Phase != "" {
+		phase := progress.Phase
+		if progress.Rule != "" {
+			phase = fmt.Sprintf("%s (rule %s)", phase, progress.Rule)
+		}
+		fmt.Printf("   Phase: %s\n", phase)
+	}
+	fmt.Printf("   Nodes written: %d\n", progress.NodesWritten)
+	fmt.Printf("   Relationships written: %d\n", progress.RelationshipsWritten)
+	if progress.RelationshipsSkipped > 0 {
+		fmt.Printf("   Relationships skipped: %d\n", progress.RelationshipsSkipped)
+	}
+	fmt.Printf("   Batches committed: %d\n", progress.BatchesCommitted)
+	if progress.Error != "" {
+		fmt.Printf("   Error: %s\n", progress.Error)
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * SQL Graph Visualizer - Transform Command Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sql-graph-visualizer/internal/interfaces/api"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTransformRequest_SendsAPIToken(t *testing.T) {
+	auth := api.APIAuthConfig{ReadTokens: []string{"reader"}, AdminTokens: []string{"admin"}}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success": true, "data": {"cancelled": true, "nodes_written": 3}}`))
+	})
+	server := httptest.NewServer(api.NewScopeMiddleware(logrus.New(), auth)(handler))
+	defer server.Close()
+
+	err := runTransformRequest(http.MethodGet, server.URL, "/api/transform/status", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "valid API token")
+
+	assert.NoError(t, runTransformRequest(http.MethodGet, server.URL, "/api/transform/status", "reader"))
+	assert.Error(t, runTransformRequest(http.MethodPost, server.URL, "/api/transform/cancel", "reader"), "cancelling needs an admin token")
+	assert.NoError(t, runTransformRequest(http.MethodPost, server.URL, "/api/transform/cancel", "admin"))
+}
@@ -37,6 +37,9 @@ func init() {
 	rootCmd.AddCommand(commands.NewTestCmd())
 	rootCmd.AddCommand(commands.NewGenerateCmd())
 	rootCmd.AddCommand(commands.NewConfigCmd())
+	rootCmd.AddCommand(commands.NewTransformCmd())
+	rootCmd.AddCommand(commands.NewMonitorCmd())
+	rootCmd.AddCommand(commands.NewRulesCmd())
 }
 
 func main() {
@@ -3,6 +3,8 @@ package ports
 import (
 	"context"
 	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
 )
 
 // BenchmarkToolPort defines the interface for benchmark tool integrations
@@ -26,12 +28,12 @@ type BenchmarkToolPort interface {
 // BenchmarkConfig represents configuration for benchmark execution
 type BenchmarkConfig struct {
 	// Tool-specific configuration
-	TestType   string        `json:"test_type" yaml:"test_type"`
-	Duration   time.Duration `json:"duration" yaml:"duration"`
-	Threads    int           `json:"threads" yaml:"threads"`
-	TableSize  int           `json:"table_size,omitempty" yaml:"table_size,omitempty"`
-	Tables     int           `json:"tables,omitempty" yaml:"tables,omitempty"`
-	WarmupTime time.Duration `json:"warmup_time,omitempty" yaml:"warmup_time,omitempty"`
+	TestType   string          `json:"test_type" yaml:"test_type"`
+	Duration   models.Duration `json:"duration" yaml:"duration"`
+	Threads    int             `json:"threads" yaml:"threads"`
+	TableSize  int             `json:"table_size,omitempty" yaml:"table_size,omitempty"`
+	Tables     int             `json:"tables,omitempty" yaml:"tables,omitempty"`
+	WarmupTime models.Duration `json:"warmup_time,omitempty" yaml:"warmup_time,omitempty"`
 
 	// Database connection
 	DatabaseType string `json:"database_type" yaml:"database_type"`
@@ -44,12 +46,12 @@ type BenchmarkConfig struct {
 // BenchmarkResult represents the result of a benchmark execution
 type BenchmarkResult struct {
 	// Execution metadata
-	ID        string        `json:"id"`
-	ToolName  string        `json:"tool_name"`
-	TestType  string        `json:"test_type"`
-	StartTime time.Time     `json:"start_time"`
-	EndTime   time.Time     `json:"end_time"`
-	Duration  time.Duration `json:"duration"`
+	ID        string          `json:"id"`
+	ToolName  string          `json:"tool_name"`
+	TestType  string          `json:"test_type"`
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	Duration  models.Duration `json:"duration"`
 
 	// Performance metrics
 	Metrics *PerformanceMetrics `json:"metrics"`
@@ -57,8 +59,14 @@ type BenchmarkResult struct {
 	// Query-level results for graph mapping
 	QueryResults []QueryPerformance `json:"query_results,omitempty"`
 
-	// Raw output from tool (for debugging)
-	RawOutput string `json:"raw_output,omitempty"`
+	// Raw output from tool (for debugging). RawOutputEncoding names how it was encoded when
+	// it is stored compressed; RawOutputTruncated means only the end of the RawOutputSize
+	// bytes of output was kept, and RawOutputFile holds all of it when it was spilled to disk.
+	RawOutput          string `json:"raw_output,omitempty"`
+	RawOutputEncoding  string `json:"raw_output_encoding,omitempty"`
+	RawOutputSize      int    `json:"raw_output_size,omitempty"`
+	RawOutputTruncated bool   `json:"raw_output_truncated,omitempty"`
+	RawOutputFile      string `json:"raw_output_file,omitempty"`
 
 	// Status and errors
 	Status BenchmarkStatus `json:"status"`
@@ -100,13 +108,13 @@ type PerformanceMetrics struct {
 
 // QueryPerformance represents performance data for individual query patterns
 type QueryPerformance struct {
-	QueryPattern   string        `json:"query_pattern"`
-	QueryType      string        `json:"query_type"` // SELECT, INSERT, UPDATE, DELETE
-	ExecutionCount int64         `json:"execution_count"`
-	TotalTime      time.Duration `json:"total_time"`
-	AverageTime    time.Duration `json:"average_time"`
-	MinTime        time.Duration `json:"min_time"`
-	MaxTime        time.Duration `json:"max_time"`
+	QueryPattern   string          `json:"query_pattern"`
+	QueryType      string          `json:"query_type"` // SELECT, INSERT, UPDATE, DELETE
+	ExecutionCount int64           `json:"execution_count"`
+	TotalTime      models.Duration `json:"total_time"`
+	AverageTime    models.Duration `json:"average_time"`
+	MinTime        models.Duration `json:"min_time"`
+	MaxTime        models.Duration `json:"max_time"`
 
 	// Tables involved in the query
 	SourceTables []string `json:"source_tables"`
@@ -119,9 +127,9 @@ type QueryPerformance struct {
 	IndexName    string `json:"index_name,omitempty"`
 
 	// Resource usage
-	CPUTime  time.Duration `json:"cpu_time,omitempty"`
-	IOReads  int64         `json:"io_reads,omitempty"`
-	IOWrites int64         `json:"io_writes,omitempty"`
+	CPUTime  models.Duration `json:"cpu_time,omitempty"`
+	IOReads  int64           `json:"io_reads,omitempty"`
+	IOWrites int64           `json:"io_writes,omitempty"`
 
 	// Classification for graph mapping
 	RelationshipType  string `json:"relationship_type,omitempty"` // JOIN, FK_LOOKUP, etc.
@@ -132,6 +140,7 @@ type QueryPerformance struct {
 type BenchmarkStatus string
 
 const (
+	BenchmarkStatusQueued    BenchmarkStatus = "queued"
 	BenchmarkStatusPending   BenchmarkStatus = "pending"
 	BenchmarkStatusRunning   BenchmarkStatus = "running"
 	BenchmarkStatusCompleted BenchmarkStatus = "completed"
@@ -143,7 +152,7 @@ const (
 type CustomBenchmarkConfig struct {
 	Name        string                  `json:"name" yaml:"name"`
 	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
-	Duration    time.Duration           `json:"duration" yaml:"duration"`
+	Duration    models.Duration         `json:"duration" yaml:"duration"`
 	Threads     int                     `json:"threads" yaml:"threads"`
 	Queries     []CustomQueryDefinition `json:"queries" yaml:"queries"`
 }
@@ -156,6 +165,6 @@ type CustomQueryDefinition struct {
 	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
 
 	// Performance expectations (optional)
-	ExpectedLatency time.Duration `json:"expected_latency,omitempty" yaml:"expected_latency,omitempty"`
-	TargetQPS       float64       `json:"target_qps,omitempty" yaml:"target_qps,omitempty"`
+	ExpectedLatency models.Duration `json:"expected_latency,omitempty" yaml:"expected_latency,omitempty"`
+	TargetQPS       float64         `json:"target_qps,omitempty" yaml:"target_qps,omitempty"`
 }
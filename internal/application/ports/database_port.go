@@ -11,6 +11,8 @@
 
 package ports
 
+import "context"
+
 // DatabasePort is a generic interface for database operations used by transform services
 // This interface abstracts the common database operations needed for data transformation,
 // regardless of the underlying database type (MySQL, PostgreSQL, etc.)
@@ -19,3 +21,30 @@ type DatabasePort interface {
 	ExecuteQuery(query string) ([]map[string]any, error)
 	Close() error
 }
+
+// ContextQueryExecutor is implemented by database ports whose queries can be cancelled
+// through a context. Callers should prefer it over ExecuteQuery when available.
+type ContextQueryExecutor interface {
+	ExecuteQueryWithContext(ctx context.Context, query string) ([]map[string]any, error)
+}
+
+// RowStreamer is implemented by database ports that can hand the rows of a query to fn one
+// at a time, as they are read, instead of loading the whole result into memory. An error
+// returned by fn stops the query and is returned.
+type RowStreamer interface {
+	StreamQuery(ctx context.Context, query string, fn func(row map[string]any) error) error
+}
+
+// ParameterizedRowStreamer is implemented by database ports that can stream the rows of a
+// query with arguments, as RowStreamer does for queries without them
+type ParameterizedRowStreamer interface {
+	StreamQueryWithArgs(ctx context.Context, query string, args []any, fn func(row map[string]any) error) error
+}
+
+// ParameterizedQueryExecutor is implemented by database ports that pass query arguments to
+// the database separately from the query text, so values are never interpreted as SQL
+type ParameterizedQueryExecutor interface {
+	// QueryPlaceholder returns the placeholder of the n-th (1-based) query argument
+	QueryPlaceholder(n int) string
+	ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error)
+}
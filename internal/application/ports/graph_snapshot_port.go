@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package ports
+
+import (
+	"time"
+
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+)
+
+// GraphSnapshotMetadata describes the transform run that produced a graph
+type GraphSnapshotMetadata struct {
+	RunStartedAt time.Time `json:"run_started_at"`
+	// SourceFingerprint hashes the source rows the run read, so runs over unchanged data match
+	SourceFingerprint string `json:"source_fingerprint"`
+	// RuleSetVersion hashes the transform rules the run applied
+	RuleSetVersion string `json:"rule_set_version"`
+}
+
+// GraphSnapshotCounts counts the nodes of a run's graph by label and its relationships by type
+type GraphSnapshotCounts struct {
+	NodesByLabel        map[string]int `json:"nodes_by_label"`
+	RelationshipsByType map[string]int `json:"relationships_by_type"`
+}
+
+// GraphSnapshotRecorder keeps the graph of each completed transform run. RecordCounts keeps
+// only the counts of a run that stored its graph batch by batch without holding all of it.
+type GraphSnapshotRecorder interface {
+	RecordSnapshot(graph *graph.GraphAggregate, metadata GraphSnapshotMetadata)
+	RecordCounts(counts GraphSnapshotCounts, metadata GraphSnapshotMetadata)
+}
+
+// TransformRunStore persists the metadata of the last successful transform run, so runs
+// in a new process continue from it (e.g. incremental "@last_run" queries)
+type TransformRunStore interface {
+	// LoadLastRun returns the last recorded run, or nil when none was recorded
+	LoadLastRun() (*GraphSnapshotMetadata, error)
+	SaveLastRun(metadata GraphSnapshotMetadata) error
+}
@@ -11,7 +11,12 @@
 
 package ports
 
-import "sql-graph-visualizer/internal/domain/aggregates/graph"
+import (
+	"context"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+)
 
 type Neo4jPort interface {
 	StoreGraph(graph *graph.GraphAggregate) error
@@ -21,3 +26,128 @@ type Neo4jPort interface {
 	ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error)
 	Close() error
 }
+
+// ContextGraphStore is implemented by Neo4j ports that can abort a graph write when the
+// context is cancelled or its deadline passes
+type ContextGraphStore interface {
+	StoreGraphWithContext(ctx context.Context, graph *graph.GraphAggregate) error
+}
+
+// GraphWriteProgress counts the graph elements committed to the store so far.
+// RelationshipsSkipped counts relationships that were not written because their source or
+// target node was not found in the store.
+type GraphWriteProgress struct {
+	NodesWritten         int `json:"nodes_written"`
+	RelationshipsWritten int `json:"relationships_written"`
+	RelationshipsSkipped int `json:"relationships_skipped"`
+	BatchesCommitted     int `json:"batches_committed"`
+}
+
+// BatchedGraphStore is implemented by Neo4j ports that write a graph in transactional
+// batches. A batch interrupted by cancellation is rolled back while earlier batches stay
+// committed; onCommit, if set, is called after every committed batch.
+type BatchedGraphStore interface {
+	StoreGraphInBatches(ctx context.Context, graph *graph.GraphAggregate, onCommit func(GraphWriteProgress)) error
+}
+
+// RelationshipGraphStore is implemented by Neo4j ports that can write only the relationships
+// of a graph, matching endpoints that are already stored instead of creating nodes
+type RelationshipGraphStore interface {
+	StoreRelationshipsInBatches(ctx context.Context, graph *graph.GraphAggregate, onCommit func(GraphWriteProgress)) error
+}
+
+// GraphQueryReader is implemented by Neo4j ports that can turn the nodes, relationships and
+// paths returned by an arbitrary Cypher query into a graph
+type GraphQueryReader interface {
+	QueryGraph(query string, params map[string]any) (*graph.GraphAggregate, error)
+}
+
+// GraphCounts is the number of nodes and relationships stored in the graph
+type GraphCounts struct {
+	Nodes         int64 `json:"nodes"`
+	Relationships int64 `json:"relationships"`
+}
+
+// ExportedNode is a stored node as read for export. ID is the store's internal id, used as
+// the paging cursor; Properties always carries an "id". Labels, when set, holds every label
+// of the node, Label among them; otherwise Label is its only label.
+type ExportedNode struct {
+	ID         int64
+	Label      string
+	Labels     []string
+	Properties map[string]any
+}
+
+// AllLabels returns the labels of the node
+func (n ExportedNode) AllLabels() []string {
+	if len(n.Labels) == 0 {
+		return []string{n.Label}
+	}
+	return n.Labels
+}
+
+// ExportedRelationship is a stored relationship with the label and id property of its endpoints
+type ExportedRelationship struct {
+	ID          int64
+	Type        string
+	SourceLabel string
+	SourceKey   any
+	TargetLabel string
+	TargetKey   any
+	Properties  map[string]any
+}
+
+// ImportWindow limits a paged read to the elements imported in [From, To), by their
+// transform.ProvenanceImportedAt property. A zero bound leaves that side open; a zero window
+// reads everything, including elements without the property.
+type ImportWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// GraphPageReader is implemented by Neo4j ports that can count the stored graph and read it
+// in pages ordered by internal id, so large graphs can be exported without holding them in
+// memory. A page shorter than limit is the last one. Relationship pages of a window hold only
+// relationships whose endpoints were imported within it too.
+type GraphPageReader interface {
+	CountGraph(ctx context.Context) (GraphCounts, error)
+	ReadNodePage(ctx context.Context, afterID int64, limit int, window ImportWindow) ([]ExportedNode, error)
+	ReadRelationshipPage(ctx context.Context, afterID int64, limit int, window ImportWindow) ([]ExportedRelationship, error)
+}
+
+// ReconcileScope is the part of the stored graph a transform owns: nodes with one of
+// NodeLabels and relationships of RelationshipTypes between them. Nodes labelled
+// AnnotationLabel hold manual annotations and are never part of the scope.
+type ReconcileScope struct {
+	NodeLabels        []string
+	RelationshipTypes []string
+	AnnotationLabel   string
+}
+
+// GraphDelta lists the writes that bring a stored graph in line with a transformed one.
+// Updated and deleted elements carry their stored internal ID; created relationships find
+// their endpoints by label and id property.
+type GraphDelta struct {
+	CreateNodes []ExportedNode
+	// UpdateNodes carry the node's new properties and labels; RemoveLabels lists, by node
+	// ID, the stored labels an updated node no longer carries
+	UpdateNodes         []ExportedNode
+	RemoveLabels        map[int64][]string
+	DeleteNodes         []ExportedNode
+	CreateRelationships []ExportedRelationship
+	UpdateRelationships []ExportedRelationship
+	DeleteRelationships []ExportedRelationship
+}
+
+// Empty reports whether the delta has no writes
+func (d *GraphDelta) Empty() bool {
+	return len(d.CreateNodes)+len(d.UpdateNodes)+len(d.DeleteNodes)+
+		len(d.CreateRelationships)+len(d.UpdateRelationships)+len(d.DeleteRelationships) == 0
+}
+
+// GraphReconciler is implemented by Neo4j ports that can read the part of the graph a
+// transform owns and apply only the differences to it, keeping the graph across restarts
+type GraphReconciler interface {
+	ReadManagedGraph(ctx context.Context, scope ReconcileScope) ([]ExportedNode, []ExportedRelationship, error)
+	ApplyGraphDelta(ctx context.Context, delta *GraphDelta, onCommit func(GraphWriteProgress)) error
+}
@@ -3,6 +3,8 @@ package ports
 import (
 	"context"
 	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
 )
 
 // PerformanceAnalyzerPort defines the interface for performance analysis services
@@ -82,18 +84,18 @@ type PerformanceImpact struct {
 
 // CriticalPathAnalysis identifies the slowest execution paths
 type CriticalPathAnalysis struct {
-	CriticalPaths  []CriticalPath `json:"critical_paths"`
-	OverallScore   float64        `json:"overall_score"`
-	MaxPathLatency time.Duration  `json:"max_path_latency"`
-	AverageLatency time.Duration  `json:"average_latency"`
-	AnalyzedAt     time.Time      `json:"analyzed_at"`
+	CriticalPaths  []CriticalPath  `json:"critical_paths"`
+	OverallScore   float64         `json:"overall_score"`
+	MaxPathLatency models.Duration `json:"max_path_latency"`
+	AverageLatency models.Duration `json:"average_latency"`
+	AnalyzedAt     time.Time       `json:"analyzed_at"`
 }
 
 // CriticalPath represents a slow execution path through the database
 type CriticalPath struct {
 	ID           string                  `json:"id"`
 	Path         []PathNode              `json:"path"`
-	TotalLatency time.Duration           `json:"total_latency"`
+	TotalLatency models.Duration         `json:"total_latency"`
 	Frequency    int64                   `json:"frequency"`
 	Impact       float64                 `json:"impact"` // Combined latency * frequency
 	Bottlenecks  []PerformanceBottleneck `json:"bottlenecks"`
@@ -101,11 +103,11 @@ type CriticalPath struct {
 
 // PathNode represents a node in a critical path
 type PathNode struct {
-	TableName     string        `json:"table_name"`
-	Operation     string        `json:"operation"`
-	Latency       time.Duration `json:"latency"`
-	RowsProcessed int64         `json:"rows_processed"`
-	IndexUsed     string        `json:"index_used,omitempty"`
+	TableName     string          `json:"table_name"`
+	Operation     string          `json:"operation"`
+	Latency       models.Duration `json:"latency"`
+	RowsProcessed int64           `json:"rows_processed"`
+	IndexUsed     string          `json:"index_used,omitempty"`
 }
 
 // HotspotNode represents a table or relationship under heavy load
@@ -145,8 +147,8 @@ type QueryPatternGroup struct {
 	PatternID       string            `json:"pattern_id"`
 	PatternTemplate string            `json:"pattern_template"`
 	QueryCount      int64             `json:"query_count"`
-	TotalLatency    time.Duration     `json:"total_latency"`
-	AverageLatency  time.Duration     `json:"average_latency"`
+	TotalLatency    models.Duration   `json:"total_latency"`
+	AverageLatency  models.Duration   `json:"average_latency"`
 	TablesInvolved  []string          `json:"tables_involved"`
 	Performance     PerformanceRating `json:"performance"`
 	Examples        []string          `json:"examples,omitempty"`
@@ -245,11 +247,11 @@ type OptimizationImpact struct {
 
 // OptimizationImplementation provides implementation details
 type OptimizationImplementation struct {
-	Steps           []string      `json:"steps"`
-	Code            string        `json:"code,omitempty"` // SQL, configuration, etc.
-	EstimatedTime   time.Duration `json:"estimated_time"`
-	DifficultyLevel EffortLevel   `json:"difficulty_level"`
-	Rollback        string        `json:"rollback,omitempty"`
+	Steps           []string        `json:"steps"`
+	Code            string          `json:"code,omitempty"` // SQL, configuration, etc.
+	EstimatedTime   models.Duration `json:"estimated_time"`
+	DifficultyLevel EffortLevel     `json:"difficulty_level"`
+	Rollback        string          `json:"rollback,omitempty"`
 }
 
 // OptimizationValidation contains validation results for optimization
@@ -292,14 +294,14 @@ type TrendMetric struct {
 
 // PerformanceAnomaly represents unusual performance behavior
 type PerformanceAnomaly struct {
-	ID              string        `json:"id"`
-	Type            AnomalyType   `json:"type"`
-	DetectedAt      time.Time     `json:"detected_at"`
-	Duration        time.Duration `json:"duration"`
-	Severity        SeverityLevel `json:"severity"`
-	Description     string        `json:"description"`
-	MetricsAffected []string      `json:"metrics_affected"`
-	PossibleCause   string        `json:"possible_cause,omitempty"`
+	ID              string          `json:"id"`
+	Type            AnomalyType     `json:"type"`
+	DetectedAt      time.Time       `json:"detected_at"`
+	Duration        models.Duration `json:"duration"`
+	Severity        SeverityLevel   `json:"severity"`
+	Description     string          `json:"description"`
+	MetricsAffected []string        `json:"metrics_affected"`
+	PossibleCause   string          `json:"possible_cause,omitempty"`
 }
 
 // AnomalyType categorizes performance anomalies
@@ -314,11 +316,11 @@ const (
 
 // PerformancePrediction provides future performance predictions
 type PerformancePrediction struct {
-	MetricName     string        `json:"metric_name"`
-	PredictedValue float64       `json:"predicted_value"`
-	Confidence     float64       `json:"confidence"`
-	TimeHorizon    time.Duration `json:"time_horizon"`
-	Scenario       string        `json:"scenario"` // BEST, WORST, LIKELY
+	MetricName     string          `json:"metric_name"`
+	PredictedValue float64         `json:"predicted_value"`
+	Confidence     float64         `json:"confidence"`
+	TimeHorizon    models.Duration `json:"time_horizon"`
+	Scenario       string          `json:"scenario"` // BEST, WORST, LIKELY
 }
 
 // PerformanceRegression identifies performance regressions
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package ports
+
+import (
+	"context"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// PerformanceCollectorPort collects performance data from the monitored database: MySQL
+// Performance Schema or PostgreSQL's statistics views
+type PerformanceCollectorPort interface {
+	// CollectPerformanceData collects current performance data; the result may be shared
+	// with concurrent callers and must not be modified
+	CollectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error)
+	ConvertToPerformanceMetrics(data *PerformanceSchemaData) *PerformanceMetrics
+	ConvertToQueryPerformance(data *PerformanceSchemaData) []QueryPerformance
+	// CheckAvailability verifies the statistics source answers and updates IsConnected
+	CheckAvailability(ctx context.Context) error
+	IsConnected() bool
+	UsingReplica() bool
+	Close() error
+}
+
+// PerformanceSchemaData contains collected performance data
+type PerformanceSchemaData struct {
+	CollectionTime   time.Time              `json:"collection_time"`
+	GlobalStatus     *GlobalStatusData      `json:"global_status"`
+	StatementStats   []StatementStatistic   `json:"statement_stats"`
+	TableIOStats     []TableIOStatistic     `json:"table_io_stats"`
+	IndexStats       []IndexStatistic       `json:"index_stats"`
+	WaitEventStats   []WaitEventStatistic   `json:"wait_event_stats"`
+	ConnectionStats  *ConnectionStatistics  `json:"connection_stats"`
+	ReplicationStats *ReplicationStatistics `json:"replication_stats"`
+	SlowQueries      []SlowQueryInfo        `json:"slow_queries"`
+	TableLockStats   []TableLockStatistic   `json:"table_lock_stats,omitempty"`
+}
+
+// GlobalStatusData contains global MySQL status information
+type GlobalStatusData struct {
+	QueriesPerSecond        float64 `json:"queries_per_second"`
+	ConnectionsPerSecond    float64 `json:"connections_per_second"`
+	SlowQueries             int64   `json:"slow_queries"`
+	OpenTables              int64   `json:"open_tables"`
+	ThreadsRunning          int64   `json:"threads_running"`
+	ThreadsConnected        int64   `json:"threads_connected"`
+	InnodbBufferPoolHitRate float64 `json:"innodb_buffer_pool_hit_rate"`
+	KeyCacheHitRate         float64 `json:"key_cache_hit_rate"`
+	TmpTablesCreated        int64   `json:"tmp_tables_created"`
+	TmpDiskTablesCreated    int64   `json:"tmp_disk_tables_created"`
+
+	// Lock metrics: cumulative counters since server start, rates over the last interval
+	Deadlocks          int64   `json:"deadlocks"`
+	RowLockWaits       int64   `json:"row_lock_waits"`
+	CurrentLockWaits   int64   `json:"current_lock_waits"`
+	DeadlocksPerMinute float64 `json:"deadlocks_per_minute"`
+	LockWaitsPerMinute float64 `json:"lock_waits_per_minute"`
+}
+
+// StatementStatistic contains per-statement performance data
+type StatementStatistic struct {
+	SchemaName              string          `json:"schema_name"`
+	Digest                  string          `json:"digest"`
+	DigestText              string          `json:"digest_text,omitempty"`
+	DigestTextRecovered     bool            `json:"digest_text_recovered,omitempty"`
+	CountStar               int64           `json:"count_star"`
+	SumTimerWait            models.Duration `json:"sum_timer_wait"`
+	MinTimerWait            models.Duration `json:"min_timer_wait"`
+	AvgTimerWait            models.Duration `json:"avg_timer_wait"`
+	MaxTimerWait            models.Duration `json:"max_timer_wait"`
+	SumRowsAffected         int64           `json:"sum_rows_affected"`
+	SumRowsSent             int64           `json:"sum_rows_sent"`
+	SumRowsExamined         int64           `json:"sum_rows_examined"`
+	SumCreatedTmpTables     int64           `json:"sum_created_tmp_tables"`
+	SumCreatedTmpDiskTables int64           `json:"sum_created_tmp_disk_tables"`
+	SumSelectFullJoin       int64           `json:"sum_select_full_join"`
+	SumSelectScan           int64           `json:"sum_select_scan"`
+	SumSortScan             int64           `json:"sum_sort_scan"`
+	SumSortRows             int64           `json:"sum_sort_rows"`
+	SumNoIndexUsed          int64           `json:"sum_no_index_used"`
+	SumNoGoodIndexUsed      int64           `json:"sum_no_good_index_used"`
+	FirstSeen               time.Time       `json:"first_seen"`
+	LastSeen                time.Time       `json:"last_seen"`
+}
+
+// TableIOStatistic contains per-table I/O performance data
+type TableIOStatistic struct {
+	SchemaName     string          `json:"schema_name"`
+	TableName      string          `json:"table_name"`
+	CountRead      int64           `json:"count_read"`
+	SumTimerRead   models.Duration `json:"sum_timer_read"`
+	CountWrite     int64           `json:"count_write"`
+	SumTimerWrite  models.Duration `json:"sum_timer_write"`
+	CountFetch     int64           `json:"count_fetch"`
+	SumTimerFetch  models.Duration `json:"sum_timer_fetch"`
+	CountInsert    int64           `json:"count_insert"`
+	SumTimerInsert models.Duration `json:"sum_timer_insert"`
+	CountUpdate    int64           `json:"count_update"`
+	SumTimerUpdate models.Duration `json:"sum_timer_update"`
+	CountDelete    int64           `json:"count_delete"`
+	SumTimerDelete models.Duration `json:"sum_timer_delete"`
+}
+
+// IndexStatistic contains index usage statistics
+type IndexStatistic struct {
+	SchemaName     string          `json:"schema_name"`
+	TableName      string          `json:"table_name"`
+	IndexName      string          `json:"index_name"`
+	CountFetch     int64           `json:"count_fetch"`
+	SumTimerFetch  models.Duration `json:"sum_timer_fetch"`
+	CountInsert    int64           `json:"count_insert"`
+	SumTimerInsert models.Duration `json:"sum_timer_insert"`
+	CountUpdate    int64           `json:"count_update"`
+	SumTimerUpdate models.Duration `json:"sum_timer_update"`
+	CountDelete    int64           `json:"count_delete"`
+	SumTimerDelete models.Duration `json:"sum_timer_delete"`
+}
+
+// WaitEventStatistic contains wait event statistics
+type WaitEventStatistic struct {
+	EventName    string          `json:"event_name"`
+	CountStar    int64           `json:"count_star"`
+	SumTimerWait models.Duration `json:"sum_timer_wait"`
+	MinTimerWait models.Duration `json:"min_timer_wait"`
+	AvgTimerWait models.Duration `json:"avg_timer_wait"`
+	MaxTimerWait models.Duration `json:"max_timer_wait"`
+}
+
+// ConnectionStatistics contains connection-related statistics
+type ConnectionStatistics struct {
+	CurrentConnections int64   `json:"current_connections"`
+	TotalConnections   int64   `json:"total_connections"`
+	ConnectionsPerSec  float64 `json:"connections_per_sec"`
+	AbortedConnections int64   `json:"aborted_connections"`
+	AbortedClients     int64   `json:"aborted_clients"`
+	MaxUsedConnections int64   `json:"max_used_connections"`
+}
+
+// ReplicationStatistics contains replication-related statistics
+type ReplicationStatistics struct {
+	SlaveRunning        bool   `json:"slave_running"`
+	SecondsBehindMaster *int64 `json:"seconds_behind_master"`
+	MasterLogFile       string `json:"master_log_file"`
+	MasterLogPos        int64  `json:"master_log_pos"`
+	RelayLogFile        string `json:"relay_log_file"`
+	RelayLogPos         int64  `json:"relay_log_pos"`
+	LastIOError         string `json:"last_io_error,omitempty"`
+	LastSQLError        string `json:"last_sql_error,omitempty"`
+}
+
+// SlowQueryInfo contains information about slow queries
+type SlowQueryInfo struct {
+	StartTime    time.Time       `json:"start_time"`
+	UserHost     string          `json:"user_host"`
+	QueryTime    models.Duration `json:"query_time"`
+	LockTime     models.Duration `json:"lock_time"`
+	RowsSent     int64           `json:"rows_sent"`
+	RowsExamined int64           `json:"rows_examined"`
+	SQLText      string          `json:"sql_text"`
+	Schema       string          `json:"schema"`
+}
+
+// TableLockStatistic counts lock requests currently waiting on a table
+type TableLockStatistic struct {
+	SchemaName   string `json:"schema_name"`
+	TableName    string `json:"table_name"`
+	WaitingLocks int64  `json:"waiting_locks"`
+}
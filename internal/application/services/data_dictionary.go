@@ -0,0 +1,321 @@
+/*
+ * SQL Graph Visualizer - Data Dictionary
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// Data dictionary output formats
+const (
+	DictionaryFormatMarkdown = "markdown"
+	DictionaryFormatHTML     = "html"
+	DictionaryFormatJSON     = "json"
+)
+
+// ErrUnknownDictionaryFormat is returned for formats other than markdown, html and json
+var ErrUnknownDictionaryFormat = errors.New("unknown data dictionary format")
+
+// Normalized column key kinds
+const (
+	columnKeyPrimary = "PRIMARY"
+	columnKeyUnique  = "UNIQUE"
+	columnKeyIndex   = "INDEX"
+	columnKeyForeign = "FOREIGN"
+)
+
+// DataDictionary documents every analyzed table, its columns, keys and relationships
+type DataDictionary struct {
+	Database      string                   `json:"database"`
+	DatabaseType  models.DatabaseType      `json:"database_type,omitempty"`
+	GeneratedAt   time.Time                `json:"generated_at"`
+	Tables        []DictionaryTable        `json:"tables"`
+	Relationships []DictionaryRelationship `json:"relationships"`
+}
+
+// DictionaryTable documents one table
+type DictionaryTable struct {
+	Name          string             `json:"name"`
+	Schema        string             `json:"schema,omitempty"`
+	EstimatedRows int64              `json:"estimated_rows"`
+	PrimaryKey    []string           `json:"primary_key,omitempty"`
+	Columns       []DictionaryColumn `json:"columns"`
+	Indexes       []models.IndexInfo `json:"indexes,omitempty"`
+}
+
+// DictionaryColumn documents one column
+type DictionaryColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+	Key      string `json:"key,omitempty"` // PRIMARY, UNIQUE, INDEX or FOREIGN
+	Comment  string `json:"comment,omitempty"`
+}
+
+// DictionaryRelationship documents a declared foreign key or an inferred reference
+type DictionaryRelationship struct {
+	FromTable  string  `json:"from_table"`
+	FromColumn string  `json:"from_column"`
+	ToTable    string  `json:"to_table"`
+	ToColumn   string  `json:"to_column"`
+	Type       string  `json:"type,omitempty"`
+	Constraint string  `json:"constraint,omitempty"`
+	Implicit   bool    `json:"implicit"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// BuildDataDictionary documents the schema of an analysis result. Tables are sorted by name
+// and columns keep their ordinal order; relationships list declared foreign keys first, then
+// the references inferred from column names.
+func BuildDataDictionary(result *models.UniversalDatabaseAnalysisResult) *DataDictionary {
+	dictionary := &DataDictionary{
+		DatabaseType:  result.DatabaseType,
+		GeneratedAt:   time.Now(),
+		Tables:        []DictionaryTable{},
+		Relationships: []DictionaryRelationship{},
+	}
+	analysis := result.SchemaAnalysis
+	if analysis == nil {
+		return dictionary
+	}
+	dictionary.Database = analysis.DatabaseName
+
+	for _, table := range analysis.Tables {
+		foreign := make(map[string]bool, len(table.Relationships))
+		for _, rel := range table.Relationships {
+			foreign[strings.ToLower(rel.SourceColumn)] = true
+			dictionary.Relationships = append(dictionary.Relationships, DictionaryRelationship{
+				FromTable:  table.Name,
+				FromColumn: rel.SourceColumn,
+				ToTable:    rel.TargetTable,
+				ToColumn:   rel.TargetColumn,
+				Type:       rel.RelationshipType,
+				Constraint: rel.ConstraintName,
+			})
+		}
+
+		entry := DictionaryTable{
+			Name:          table.Name,
+			Schema:        table.Schema,
+			EstimatedRows: table.EstimatedRows,
+			Columns:       make([]DictionaryColumn, 0, len(table.Columns)),
+			Indexes:       table.Indexes,
+		}
+		for _, column := range table.Columns {
+			if isPrimaryKeyColumn(column) {
+				entry.PrimaryKey = append(entry.PrimaryKey, column.Name)
+			}
+			entry.Columns = append(entry.Columns, DictionaryColumn{
+				Name:     column.Name,
+				Type:     columnTypeName(column),
+				Nullable: strings.EqualFold(column.IsNullable, "YES"),
+				Default:  column.DefaultValue,
+				Key:      columnKey(column, foreign[strings.ToLower(column.Name)]),
+				Comment:  column.Comment,
+			})
+		}
+		dictionary.Tables = append(dictionary.Tables, entry)
+	}
+	sort.SliceStable(dictionary.Tables, func(i, j int) bool { return dictionary.Tables[i].Name < dictionary.Tables[j].Name })
+
+	for _, rel := range analysis.ImplicitRelationships {
+		dictionary.Relationships = append(dictionary.Relationships, DictionaryRelationship{
+			FromTable:  rel.FromTable,
+			FromColumn: rel.FromColumn,
+			ToTable:    rel.ToTable,
+			ToColumn:   rel.ToColumn,
+			Type:       rel.RelationType,
+			Implicit:   true,
+			Confidence: rel.Confidence,
+		})
+	}
+	return dictionary
+}
+
+// columnTypeName is the column's data type with its length, e.g. varchar(255)
+func columnTypeName(column *models.ColumnInfo) string {
+	if column.MaxLength > 0 && !strings.Contains(column.DataType, "(") {
+		return fmt.Sprintf("%s(%d)", column.DataType, column.MaxLength)
+	}
+	return column.DataType
+}
+
+// columnKey normalizes the engine specific key types (PRI, UNI, MUL, ...) of a column
+func columnKey(column *models.ColumnInfo, foreign bool) string {
+	switch strings.ToUpper(column.KeyType) {
+	case "PRI", "PRIMARY":
+		return columnKeyPrimary
+	case "UNI", "UNIQUE":
+		return columnKeyUnique
+	case "FOREIGN":
+		return columnKeyForeign
+	}
+	if foreign {
+		return columnKeyForeign
+	}
+	switch strings.ToUpper(column.KeyType) {
+	case "MUL", "INDEX":
+		return columnKeyIndex
+	}
+	return ""
+}
+
+// Render encodes the dictionary as markdown, html or json and returns the content type
+func (d *DataDictionary) Render(format string) ([]byte, string, error) {
+	switch format {
+	case DictionaryFormatMarkdown:
+		return []byte(d.markdown()), "text/markdown; charset=utf-8", nil
+	case DictionaryFormatHTML:
+		var buf bytes.Buffer
+		if err := dictionaryHTML.Execute(&buf, d); err != nil {
+			return nil, "", fmt.Errorf("failed to render data dictionary: %w", err)
+		}
+		return buf.Bytes(), "text/html; charset=utf-8", nil
+	case DictionaryFormatJSON:
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal data dictionary: %w", err)
+		}
+		return data, "application/json", nil
+	default:
+		return nil, "", fmt.Errorf("%w %q (expected %s, %s or %s)", ErrUnknownDictionaryFormat, format,
+			DictionaryFormatMarkdown, DictionaryFormatHTML, DictionaryFormatJSON)
+	}
+}
+
+// relationshipsFrom returns the relationships whose source is table
+func (d *DataDictionary) relationshipsFrom(table string) []DictionaryRelationship {
+	var relationships []DictionaryRelationship
+	for _, rel := range d.Relationships {
+		if rel.FromTable == table {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships
+}
+
+func (d *DataDictionary) markdown() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# Data Dictionary: %s\n\n", markdownCell(d.Database))
+	fmt.Fprintf(&out, "Generated %s", d.GeneratedAt.UTC().Format(time.RFC3339))
+	if d.DatabaseType != "" {
+		fmt.Fprintf(&out, " from %s", d.DatabaseType)
+	}
+	fmt.Fprintf(&out, ". %d tables.\n", len(d.Tables))
+
+	for _, table := range d.Tables {
+		fmt.Fprintf(&out, "\n## %s\n\n", markdownCell(table.Name))
+		if table.Schema != "" {
+			fmt.Fprintf(&out, "Schema: %s  \n", markdownCell(table.Schema))
+		}
+		fmt.Fprintf(&out, "Estimated rows: %d  \n", table.EstimatedRows)
+		if len(table.PrimaryKey) > 0 {
+			fmt.Fprintf(&out, "Primary key: %s\n", markdownCell(strings.Join(table.PrimaryKey, ", ")))
+		}
+
+		out.WriteString("\n| Column | Type | Nullable | Default | Key | Comment |\n")
+		out.WriteString("|---|---|---|---|---|---|\n")
+		for _, column := range table.Columns {
+			nullable := "NO"
+			if column.Nullable {
+				nullable = "YES"
+			}
+			fmt.Fprintf(&out, "| %s | %s | %s | %s | %s | %s |\n", markdownCell(column.Name), markdownCell(column.Type),
+				nullable, markdownCell(column.Default), column.Key, markdownCell(column.Comment))
+		}
+
+		if len(table.Indexes) > 0 {
+			out.WriteString("\nIndexes:\n\n")
+			for _, index := range table.Indexes {
+				unique := ""
+				if index.IsUnique {
+					unique = " (unique)"
+				}
+				fmt.Fprintf(&out, "- %s%s: %s\n", markdownCell(index.Name), unique, markdownCell(strings.Join(index.Columns, ", ")))
+			}
+		}
+
+		if relationships := d.relationshipsFrom(table.Name); len(relationships) > 0 {
+			out.WriteString("\nReferences:\n\n")
+			for _, rel := range relationships {
+				fmt.Fprintf(&out, "- %s -> %s.%s%s\n", markdownCell(rel.FromColumn), markdownCell(rel.ToTable),
+					markdownCell(rel.ToColumn), relationshipNote(rel))
+			}
+		}
+	}
+	return out.String()
+}
+
+// relationshipNote tells declared foreign keys from inferred references
+func relationshipNote(rel DictionaryRelationship) string {
+	if rel.Implicit {
+		return fmt.Sprintf(" (inferred, confidence %.2f)", rel.Confidence)
+	}
+	if rel.Constraint != "" {
+		return fmt.Sprintf(" (foreign key %s)", markdownCell(rel.Constraint))
+	}
+	return " (foreign key)"
+}
+
+// markdownCell escapes pipes and flattens line breaks so a value stays in its table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+var dictionaryHTML = template.Must(template.New("dictionary").Funcs(template.FuncMap{
+	"join":          strings.Join,
+	"relationships": func(d *DataDictionary, table string) []DictionaryRelationship { return d.relationshipsFrom(table) },
+	"note":          relationshipNote,
+	"rfc3339":       func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Data Dictionary: {{.Database}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f4f4f4; }
+</style>
+</head>
+<body>
+<h1>Data Dictionary: {{.Database}}</h1>
+<p>Generated {{rfc3339 .GeneratedAt}}{{with .DatabaseType}} from {{.}}{{end}}. {{len .Tables}} tables.</p>
+{{range .Tables}}{{$table := .}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+<p>{{with .Schema}}Schema: {{.}}<br>{{end}}Estimated rows: {{.EstimatedRows}}{{with .PrimaryKey}}<br>Primary key: {{join . ", "}}{{end}}</p>
+<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Key</th><th>Comment</th></tr>
+{{range .Columns}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{if .Nullable}}YES{{else}}NO{{end}}</td><td>{{.Default}}</td><td>{{.Key}}</td><td>{{.Comment}}</td></tr>
+{{end}}</table>
+{{with .Indexes}}<p>Indexes:</p>
+<ul>
+{{range .}}<li>{{.Name}}{{if .IsUnique}} (unique){{end}}: {{join .Columns ", "}}</li>
+{{end}}</ul>
+{{end}}{{with relationships $ $table.Name}}<p>References:</p>
+<ul>
+{{range .}}<li>{{.FromColumn}} &rarr; <a href="#{{.ToTable}}">{{.ToTable}}</a>.{{.ToColumn}}{{note .}}</li>
+{{end}}</ul>
+{{end}}{{end}}
+</body>
+</html>
+`))
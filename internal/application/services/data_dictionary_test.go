@@ -0,0 +1,149 @@
+/*
+ * SQL Graph Visualizer - Data Dictionary Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// newDictionaryFixture is a shop schema: orders reference customers by a foreign key and
+// order_lines reference orders by naming convention only
+func newDictionaryFixture() *models.UniversalDatabaseAnalysisResult {
+	return &models.UniversalDatabaseAnalysisResult{
+		Success:      true,
+		DatabaseType: models.DatabaseTypeMySQL,
+		SchemaAnalysis: &models.UniversalSchemaAnalysisResult{
+			DatabaseName: "shop",
+			Tables: []*models.UniversalTableInfo{
+				{
+					Name:          "orders",
+					EstimatedRows: 1200,
+					Columns: []*models.ColumnInfo{
+						{Name: "id", DataType: "int", IsNullable: "NO", IsKey: true, KeyType: "PRI", Extra: "auto_increment"},
+						{Name: "customer_id", DataType: "int", IsNullable: "NO", KeyType: "MUL", Comment: "Who placed the order"},
+						{Name: "status", DataType: "varchar", MaxLength: 20, IsNullable: "YES", DefaultValue: "new", Comment: "new | paid | shipped"},
+					},
+					Indexes: []models.IndexInfo{{Name: "idx_customer", Columns: []string{"customer_id"}}},
+					Relationships: []*models.Relationship{{
+						SourceTable: "orders", SourceColumn: "customer_id", TargetTable: "customers", TargetColumn: "id",
+						RelationshipType: "MANY_TO_ONE", ConstraintName: "fk_orders_customer",
+					}},
+				},
+				{
+					Name:          "customers",
+					EstimatedRows: 300,
+					Columns: []*models.ColumnInfo{
+						{Name: "id", DataType: "int", IsNullable: "NO", IsKey: true, KeyType: "PRI"},
+						{Name: "email", DataType: "varchar", MaxLength: 255, IsNullable: "NO", KeyType: "UNI", Comment: "Login e-mail"},
+					},
+				},
+				{
+					Name: "order_lines",
+					Columns: []*models.ColumnInfo{
+						{Name: "order_id", DataType: "integer", IsNullable: "NO", IsKey: true, KeyType: "PRIMARY"},
+						{Name: "line_no", DataType: "integer", IsNullable: "NO", IsKey: true, KeyType: "PRIMARY"},
+						{Name: "note", DataType: "text", IsNullable: "YES"},
+					},
+				},
+			},
+			ImplicitRelationships: []models.RelationshipInfo{{
+				FromTable: "order_lines", FromColumn: "order_id", ToTable: "orders", ToColumn: "id",
+				RelationType: "MANY_TO_ONE", IsImplicit: true, Confidence: 0.8,
+			}},
+		},
+	}
+}
+
+func TestDataDictionaryIncludesEveryTableAndColumn(t *testing.T) {
+	fixture := newDictionaryFixture()
+
+	dictionary := BuildDataDictionary(fixture)
+
+	assert.Equal(t, "shop", dictionary.Database)
+	require.Len(t, dictionary.Tables, len(fixture.SchemaAnalysis.Tables))
+	assert.Equal(t, []string{"customers", "order_lines", "orders"},
+		[]string{dictionary.Tables[0].Name, dictionary.Tables[1].Name, dictionary.Tables[2].Name})
+
+	tables := make(map[string]DictionaryTable, len(dictionary.Tables))
+	for _, table := range dictionary.Tables {
+		tables[table.Name] = table
+	}
+	for _, table := range fixture.SchemaAnalysis.Tables {
+		entry, ok := tables[table.Name]
+		require.True(t, ok, "table %s is documented", table.Name)
+		assert.Equal(t, table.EstimatedRows, entry.EstimatedRows)
+		require.Len(t, entry.Columns, len(table.Columns))
+		for i, column := range table.Columns {
+			assert.Equal(t, column.Name, entry.Columns[i].Name, "columns keep their order")
+			assert.Equal(t, column.IsNullable == "YES", entry.Columns[i].Nullable, column.Name)
+			assert.Equal(t, column.Comment, entry.Columns[i].Comment, column.Name)
+		}
+	}
+
+	assert.Equal(t, []DictionaryColumn{
+		{Name: "id", Type: "int", Key: "PRIMARY"},
+		{Name: "customer_id", Type: "int", Key: "FOREIGN", Comment: "Who placed the order"},
+		{Name: "status", Type: "varchar(20)", Nullable: true, Default: "new", Comment: "new | paid | shipped"},
+	}, tables["orders"].Columns)
+	assert.Equal(t, "UNIQUE", tables["customers"].Columns[1].Key)
+	assert.Equal(t, []string{"order_id", "line_no"}, tables["order_lines"].PrimaryKey, "composite keys keep column order")
+	assert.Equal(t, []models.IndexInfo{{Name: "idx_customer", Columns: []string{"customer_id"}}}, tables["orders"].Indexes)
+
+	assert.Equal(t, []DictionaryRelationship{
+		{FromTable: "orders", FromColumn: "customer_id", ToTable: "customers", ToColumn: "id", Type: "MANY_TO_ONE", Constraint: "fk_orders_customer"},
+		{FromTable: "order_lines", FromColumn: "order_id", ToTable: "orders", ToColumn: "id", Type: "MANY_TO_ONE", Implicit: true, Confidence: 0.8},
+	}, dictionary.Relationships)
+}
+
+func TestDataDictionaryRenderings(t *testing.T) {
+	dictionary := BuildDataDictionary(newDictionaryFixture())
+
+	markdown, contentType, err := dictionary.Render(DictionaryFormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown; charset=utf-8", contentType)
+	for _, expected := range []string{
+		"# Data Dictionary: shop",
+		"## customers",
+		"## order_lines",
+		"Primary key: order_id, line_no",
+		"| status | varchar(20) | YES | new |  | new \\| paid \\| shipped |",
+		"| customer_id | int | NO |  | FOREIGN | Who placed the order |",
+		"- customer_id -> customers.id (foreign key fk_orders_customer)",
+		"- order_id -> orders.id (inferred, confidence 0.80)",
+	} {
+		assert.Contains(t, string(markdown), expected)
+	}
+
+	html, contentType, err := dictionary.Render(DictionaryFormatHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", contentType)
+	assert.Contains(t, string(html), `<h2 id="orders">orders</h2>`)
+	assert.Contains(t, string(html), "<td>email</td><td>varchar(255)</td><td>NO</td><td></td><td>UNIQUE</td><td>Login e-mail</td>")
+	assert.Contains(t, string(html), `customer_id &rarr; <a href="#customers">customers</a>.id`)
+
+	data, contentType, err := dictionary.Render(DictionaryFormatJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	var decoded DataDictionary
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Tables, 3)
+
+	_, _, err = dictionary.Render("pdf")
+	assert.True(t, errors.Is(err, ErrUnknownDictionaryFormat))
+	assert.False(t, strings.Contains(string(markdown), "\n\n\n"), "no stray blank lines")
+}
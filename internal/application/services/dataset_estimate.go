@@ -0,0 +1,144 @@
+/*
+ * SQL Graph Visualizer - Pre-flight Dataset Estimation
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultEstimatedRowsPerSecond is the import throughput assumed by EstimateDataset
+const DefaultEstimatedRowsPerSecond = 2000
+
+// EstimateDataset reports what an import would involve using catalog statistics: the
+// estimated rows and megabytes per table (capped by the row limit), the order tables would be
+// processed in and the expected duration. No row data is read, unless row estimation is set to
+// sample, which counts a bounded sample of each table.
+func (s *UniversalDatabaseService) EstimateDataset(ctx context.Context) (*models.DatasetInfo, error) {
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	filters := s.config.GetDataFiltering()
+	tables, err := s.repo.GetTables(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	info := &models.DatasetInfo{
+		TotalTables: len(tables),
+		TableSizes:  make(map[string]int64, len(tables)),
+		AnalyzedAt:  time.Now(),
+	}
+
+	var bytes float64
+	references := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		size, err := s.repo.GetTableSize(ctx, table)
+		if err != nil {
+			logrus.Warnf("Failed to estimate size of table %s: %v", table, err)
+			continue
+		}
+
+		rows := s.estimateRows(ctx, table, size.RowCount)
+		available := rows
+		if filters.RowLimitPerTable > 0 && rows > int64(filters.RowLimitPerTable) {
+			rows = int64(filters.RowLimitPerTable)
+		}
+		info.TableSizes[table] = rows
+		info.TotalRows += rows
+		if available > 0 {
+			bytes += float64(size.DataSize) * float64(rows) / float64(available)
+		}
+
+		foreignKeys, err := s.repo.GetForeignKeys(ctx, table)
+		if err != nil {
+			logrus.Warnf("Failed to read foreign keys of table %s: %v", table, err)
+			continue
+		}
+		for _, fk := range foreignKeys {
+			references[table] = append(references[table], fk.ReferencedTable)
+		}
+	}
+
+	info.EstimatedSizeMB = bytes / (1024 * 1024)
+	info.ProcessingOrder = processingOrder(tables, references)
+	info.EstimatedDuration = time.Duration(float64(info.TotalRows) / DefaultEstimatedRowsPerSecond * float64(time.Second))
+
+	logrus.Infof("Pre-flight estimate: %d tables, %d rows (~%.2f MB, ~%s)",
+		info.TotalTables, info.TotalRows, info.EstimatedSizeMB, info.EstimatedDuration.Round(time.Second))
+	return info, nil
+}
+
+// processingOrder lists referenced tables before the tables that reference them, so nodes
+// exist before relationships point at them. Tables in a reference cycle follow in name order.
+func processingOrder(tables []string, references map[string][]string) []string {
+	known := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		known[table] = true
+	}
+
+	pending := make(map[string]int, len(tables))
+	dependents := make(map[string][]string)
+	for _, table := range tables {
+		for _, referenced := range references[table] {
+			if referenced == table || !known[referenced] {
+				continue
+			}
+			pending[table]++
+			dependents[referenced] = append(dependents[referenced], table)
+		}
+	}
+
+	var ready []string
+	for _, table := range tables {
+		if pending[table] == 0 {
+			ready = append(ready, table)
+		}
+	}
+
+	order := make([]string, 0, len(tables))
+	done := make(map[string]bool, len(tables))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		table := ready[0]
+		ready = ready[1:]
+		order = append(order, table)
+		done[table] = true
+
+		for _, dependent := range dependents[table] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	var cyclic []string
+	for _, table := range tables {
+		if !done[table] {
+			cyclic = append(cyclic, table)
+		}
+	}
+	sort.Strings(cyclic)
+	return append(order, cyclic...)
+}
@@ -0,0 +1,95 @@
+/*
+ * SQL Graph Visualizer - Pre-flight Dataset Estimation Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// catalogRepository serves table sizes and foreign keys from catalog statistics and fails the
+// test if anything would read rows
+type catalogRepository struct {
+	countingRepository
+	t           *testing.T
+	sizes       map[string]*models.TableSize
+	foreignKeys map[string][]models.ForeignKeyInfo
+}
+
+func (r *catalogRepository) GetTableSize(ctx context.Context, tableName string) (*models.TableSize, error) {
+	return r.sizes[tableName], nil
+}
+
+func (r *catalogRepository) GetForeignKeys(ctx context.Context, tableName string) ([]models.ForeignKeyInfo, error) {
+	return r.foreignKeys[tableName], nil
+}
+
+func (r *catalogRepository) GetTableRowCount(ctx context.Context, tableName string) (int64, error) {
+	r.t.Errorf("pre-flight estimate counted the rows of %s", tableName)
+	return 0, nil
+}
+
+func (r *catalogRepository) SampleTableData(ctx context.Context, tableName string, limit int) ([]map[string]interface{}, error) {
+	r.t.Errorf("pre-flight estimate read rows of %s", tableName)
+	return nil, nil
+}
+
+func TestEstimateDatasetUsesCatalogStatistics(t *testing.T) {
+	const mb = 1024 * 1024
+	repo := &catalogRepository{
+		countingRepository: countingRepository{tables: []string{"order_items", "orders", "products", "customers"}},
+		t:                  t,
+		sizes: map[string]*models.TableSize{
+			"customers":   models.NewTableSize("customers", 10000, 2*mb, mb),
+			"orders":      models.NewTableSize("orders", 50000, 8*mb, 4*mb),
+			"order_items": models.NewTableSize("order_items", 200000, 20*mb, 10*mb),
+			"products":    models.NewTableSize("products", 800, mb/2, 0),
+		},
+		foreignKeys: map[string][]models.ForeignKeyInfo{
+			"orders":      {{Column: "customer_id", ReferencedTable: "customers"}},
+			"order_items": {{Column: "order_id", ReferencedTable: "orders"}, {Column: "product_id", ReferencedTable: "products"}},
+		},
+	}
+
+	config := newCacheTestConfig()
+	config.DataFiltering.RowLimitPerTable = 100000
+	service := NewUniversalDatabaseService(repo, config)
+
+	info, err := service.EstimateDataset(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, info.TotalTables)
+	assert.Equal(t, map[string]int64{
+		"customers": 10000, "orders": 50000, "order_items": 100000, "products": 800,
+	}, info.TableSizes, "row estimates are capped by the row limit")
+	assert.EqualValues(t, 160800, info.TotalRows)
+	// Data size scales with the capped rows: 2 + 8 + 10 + 0.5 MB
+	assert.InDelta(t, 20.5, info.EstimatedSizeMB, 1e-9)
+	assert.Equal(t, []string{"customers", "orders", "products", "order_items"}, info.ProcessingOrder)
+	assert.Equal(t, 80400*time.Millisecond, info.EstimatedDuration, "160800 rows at 2000 rows per second")
+}
+
+func TestProcessingOrderPlacesCyclesLast(t *testing.T) {
+	order := processingOrder(
+		[]string{"employees", "departments", "audit", "tags"},
+		map[string][]string{
+			"employees":   {"departments", "employees"}, // self reference is ignored
+			"departments": {"employees"},
+			"audit":       {"missing"}, // filtered-out tables do not block
+		},
+	)
+	assert.Equal(t, []string{"audit", "tags", "departments", "employees"}, order)
+}
@@ -0,0 +1,99 @@
+/*
+ * SQL Graph Visualizer - Generated Rule Executor
+ *
+ * Copyright (c) 2024
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"fmt"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InvalidCypherError reports an auto-generated rule whose Cypher Neo4j rejected
+type InvalidCypherError struct {
+	RuleID string
+	Query  string
+	Err    error
+}
+
+func (e *InvalidCypherError) Error() string {
+	return fmt.Sprintf("generated rule %s has invalid Cypher %q: %v", e.RuleID, e.Query, e.Err)
+}
+
+func (e *InvalidCypherError) Unwrap() error {
+	return e.Err
+}
+
+// GeneratedRuleExecutor writes source rows to Neo4j through auto-generated transformation
+// rules. A rule's Cypher runs once with all rows of its source table bound to row. In safe
+// mode, the default, every rule is first planned with EXPLAIN, which checks syntax without
+// writing, and nothing is executed if any rule is invalid.
+type GeneratedRuleExecutor struct {
+	neo4jPort ports.Neo4jPort
+	safeMode  bool
+}
+
+// NewGeneratedRuleExecutor creates an executor with safe mode enabled
+func NewGeneratedRuleExecutor(neo4jPort ports.Neo4jPort) *GeneratedRuleExecutor {
+	return &GeneratedRuleExecutor{neo4jPort: neo4jPort, safeMode: true}
+}
+
+// SetSafeMode enables or disables validating all rules before the first one is executed
+func (e *GeneratedRuleExecutor) SetSafeMode(enabled bool) {
+	e.safeMode = enabled
+}
+
+// generatedRuleStatement binds the rows passed as $rows to the row variable generated
+// rules read their values from
+func generatedRuleStatement(rule *models.TransformationRule) string {
+	return "UNWIND $rows AS row " + rule.CypherQuery
+}
+
+// ValidateRules plans every rule with EXPLAIN and returns an *InvalidCypherError for the
+// first one Neo4j rejects
+func (e *GeneratedRuleExecutor) ValidateRules(rules []*models.TransformationRule) error {
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if rule.CypherQuery == "" {
+			return &InvalidCypherError{RuleID: rule.RuleID, Err: fmt.Errorf("empty query")}
+		}
+		statement := "EXPLAIN " + generatedRuleStatement(rule)
+		if _, err := e.neo4jPort.ExecuteQuery(statement, map[string]interface{}{"rows": []map[string]interface{}{}}); err != nil {
+			return &InvalidCypherError{RuleID: rule.RuleID, Query: rule.CypherQuery, Err: err}
+		}
+	}
+	return nil
+}
+
+// Execute runs every rule with the rows of its source table; rules whose table has no
+// rows are skipped
+func (e *GeneratedRuleExecutor) Execute(rules []*models.TransformationRule, rowsByTable map[string][]map[string]interface{}) error {
+	if e.safeMode {
+		if err := e.ValidateRules(rules); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range rules {
+		if rule == nil || len(rowsByTable[rule.SourceTable]) == 0 {
+			continue
+		}
+		rows := rowsByTable[rule.SourceTable]
+		if _, err := e.neo4jPort.ExecuteQuery(generatedRuleStatement(rule), map[string]interface{}{"rows": rows}); err != nil {
+			return fmt.Errorf("failed to execute generated rule %s: %w", rule.RuleID, err)
+		}
+		logrus.Infof("Generated rule %s wrote %d rows from %s", rule.RuleID, len(rows), rule.SourceTable)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+ * SQL Graph Visualizer - Generated Rule Executor Tests
+ *
+ * Copyright (c) 2024
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// explainingNeo4jPort rejects statements containing invalid while planning them, and
+// records every statement that is not an EXPLAIN as a write
+type explainingNeo4jPort struct {
+	ports.Neo4jPort
+	invalid  string
+	explains []string
+	writes   []string
+}
+
+func (p *explainingNeo4jPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if strings.Contains(query, p.invalid) {
+		return nil, errors.New("Neo.ClientError.Statement.SyntaxError: Invalid input '-'")
+	}
+	if strings.HasPrefix(query, "EXPLAIN ") {
+		p.explains = append(p.explains, query)
+		return nil, nil
+	}
+	p.writes = append(p.writes, query)
+	return nil, nil
+}
+
+// newGeneratedRules generates node rules for users and for events, whose hyphenated column
+// name produces Cypher Neo4j cannot parse
+func newGeneratedRules() []*models.TransformationRule {
+	analyzer := &SchemaAnalyzerService{}
+	return []*models.TransformationRule{
+		analyzer.generateNodeRule(&models.TableInfo{Name: "users", Columns: []*models.ColumnInfo{{Name: "id"}, {Name: "name"}}}),
+		analyzer.generateNodeRule(&models.TableInfo{Name: "events", Columns: []*models.ColumnInfo{{Name: "id"}, {Name: "created-at"}}}),
+	}
+}
+
+var generatedRuleRows = map[string][]map[string]interface{}{
+	"users":  {{"id": 1, "name": "Ada"}},
+	"events": {{"id": 7, "created-at": "2024-01-01"}},
+}
+
+func TestGeneratedRuleExecutorCatchesInvalidCypherBeforeWriting(t *testing.T) {
+	neo4j := &explainingNeo4jPort{invalid: "created-at"}
+
+	err := NewGeneratedRuleExecutor(neo4j).Execute(newGeneratedRules(), generatedRuleRows)
+
+	var invalid *InvalidCypherError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "create_events_nodes", invalid.RuleID)
+	assert.Contains(t, err.Error(), "create_events_nodes")
+	assert.Len(t, neo4j.explains, 1, "the valid rule was planned")
+	assert.Empty(t, neo4j.writes, "no rule runs while another is invalid")
+}
+
+func TestGeneratedRuleExecutorWritesValidRules(t *testing.T) {
+	neo4j := &explainingNeo4jPort{invalid: "no such text"}
+
+	require.NoError(t, NewGeneratedRuleExecutor(neo4j).Execute(newGeneratedRules()[:1], generatedRuleRows))
+
+	assert.Equal(t, []string{"EXPLAIN UNWIND $rows AS row CREATE (n:Users {id: row.id, name: row.name})"}, neo4j.explains)
+	assert.Equal(t, []string{"UNWIND $rows AS row CREATE (n:Users {id: row.id, name: row.name})"}, neo4j.writes)
+}
+
+func TestGeneratedRuleExecutorWithoutSafeMode(t *testing.T) {
+	neo4j := &explainingNeo4jPort{invalid: "created-at"}
+	executor := NewGeneratedRuleExecutor(neo4j)
+	executor.SetSafeMode(false)
+
+	err := executor.Execute(newGeneratedRules(), generatedRuleRows)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create_events_nodes")
+	assert.Empty(t, neo4j.explains)
+	assert.Len(t, neo4j.writes, 1, "rules before the invalid one have already written")
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+// ErrInvalidExportWindow is returned for from/to values that do not form a time window
+var ErrInvalidExportWindow = errors.New("invalid export window")
+
+// ExportWindow limits an export to the elements imported in [From, To). A zero bound leaves
+// that side open; a zero window exports everything. Paged reads take it as a
+// ports.ImportWindow.
+type ExportWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseExportWindow parses RFC3339 from and to values, either of which may be empty
+func ParseExportWindow(from, to string) (ExportWindow, error) {
+	var window ExportWindow
+	for _, bound := range []struct {
+		name, value string
+		target      *time.Time
+	}{{"from", from, &window.From}, {"to", to, &window.To}} {
+		if bound.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return ExportWindow{}, fmt.Errorf("%w: %s %q is not an RFC3339 time", ErrInvalidExportWindow, bound.name, bound.value)
+		}
+		*bound.target = parsed
+	}
+	if !window.From.IsZero() && !window.To.IsZero() && !window.From.Before(window.To) {
+		return ExportWindow{}, fmt.Errorf("%w: from must be before to", ErrInvalidExportWindow)
+	}
+	return window, nil
+}
+
+// IsZero reports whether the window exports everything
+func (w ExportWindow) IsZero() bool {
+	return w.From.IsZero() && w.To.IsZero()
+}
+
+// Contains reports whether an element with properties was imported within the window.
+// Elements without an import timestamp, such as those imported with provenance disabled,
+// are only part of a zero window.
+func (w ExportWindow) Contains(properties map[string]any) bool {
+	if w.IsZero() {
+		return true
+	}
+
+	var importedAt time.Time
+	switch value := properties[transform.ProvenanceImportedAt].(type) {
+	case time.Time:
+		importedAt = value
+	case string:
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return false
+		}
+		importedAt = parsed
+	default:
+		return false
+	}
+
+	if !w.From.IsZero() && importedAt.Before(w.From) {
+		return false
+	}
+	return w.To.IsZero() || importedAt.Before(w.To)
+}
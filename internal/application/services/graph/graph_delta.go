@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+)
+
+// DefaultDeltaHistory is the number of graph versions kept per view for computing deltas
+const DefaultDeltaHistory = 16
+
+// DeltaNode is a node as the visualization receives it
+type DeltaNode struct {
+	ID         string         `json:"id"`
+	Label      string         `json:"label"`
+	Properties map[string]any `json:"properties"`
+	Style      map[string]any `json:"style,omitempty"`
+}
+
+// DeltaRelationship is a relationship as the visualization receives it. ID identifies it
+// across versions; parallel relationships of one type are numbered in graph order.
+type DeltaRelationship struct {
+	ID         string         `json:"id"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Style      map[string]any `json:"style,omitempty"`
+}
+
+// GraphDelta lists what changed in a view since a version token. When the token is empty
+// or no longer known, Full is set and every element is reported as added.
+type GraphDelta struct {
+	View                 string              `json:"view"`
+	Version              string              `json:"version"`
+	Since                string              `json:"since,omitempty"`
+	Full                 bool                `json:"full"`
+	AddedNodes           []DeltaNode         `json:"added_nodes"`
+	ChangedNodes         []DeltaNode         `json:"changed_nodes"`
+	RemovedNodes         []string            `json:"removed_nodes"`
+	AddedRelationships   []DeltaRelationship `json:"added_relationships"`
+	ChangedRelationships []DeltaRelationship `json:"changed_relationships"`
+	RemovedRelationships []string            `json:"removed_relationships"`
+}
+
+// graphVersion is a snapshot of a view, keyed by element ID with a content fingerprint
+type graphVersion struct {
+	token         string
+	nodes         map[string]DeltaNode
+	nodeHashes    map[string]string
+	relationships map[string]DeltaRelationship
+	relHashes     map[string]string
+	nodeOrder     []string
+	relOrder      []string
+}
+
+// GraphDeltaTracker remembers recent versions of each view so clients can fetch only the
+// changes since the version they hold
+type GraphDeltaTracker struct {
+	mu       sync.Mutex
+	history  int
+	styler   *GraphStyler
+	versions map[string][]*graphVersion
+}
+
+// NewGraphDeltaTracker creates a tracker keeping history versions per view (DefaultDeltaHistory
+// when not positive). styler may be nil.
+func NewGraphDeltaTracker(history int, styler *GraphStyler) *GraphDeltaTracker {
+	if history <= 0 {
+		history = DefaultDeltaHistory
+	}
+	return &GraphDeltaTracker{history: history, styler: styler, versions: make(map[string][]*graphVersion)}
+}
+
+// Delta records g as the current version of view and returns its changes since the since token
+func (t *GraphDeltaTracker) Delta(view string, g *graphagg.GraphAggregate, since string) GraphDelta {
+	current := newGraphVersion(view, g, t.styler)
+
+	t.mu.Lock()
+	versions := t.versions[view]
+	if len(versions) == 0 || versions[len(versions)-1].token != current.token {
+		versions = append(versions, current)
+		if len(versions) > t.history {
+			versions = versions[len(versions)-t.history:]
+		}
+		t.versions[view] = versions
+	}
+	var previous *graphVersion
+	for i := len(versions) - 1; i >= 0 && since != ""; i-- {
+		if versions[i].token == since {
+			previous = versions[i]
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	delta := newGraphDelta(view, current.token, since)
+	if previous == nil {
+		delta.Full = true
+		previous = &graphVersion{}
+	}
+	diffVersions(&delta, previous, current)
+	return delta
+}
+
+func newGraphDelta(view, version, since string) GraphDelta {
+	return GraphDelta{
+		View:                 view,
+		Version:              version,
+		Since:                since,
+		AddedNodes:           []DeltaNode{},
+		ChangedNodes:         []DeltaNode{},
+		RemovedNodes:         []string{},
+		AddedRelationships:   []DeltaRelationship{},
+		ChangedRelationships: []DeltaRelationship{},
+		RemovedRelationships: []string{},
+	}
+}
+
+// diffVersions adds to delta the elements of current that are new or changed since previous,
+// and the IDs of those previous had that are gone
+func diffVersions(delta *GraphDelta, previous, current *graphVersion) {
+	for _, id := range current.nodeOrder {
+		hash, existed := previous.nodeHashes[id]
+		switch {
+		case !existed:
+			delta.AddedNodes = append(delta.AddedNodes, current.nodes[id])
+		case hash != current.nodeHashes[id]:
+			delta.ChangedNodes = append(delta.ChangedNodes, current.nodes[id])
+		}
+	}
+	for _, id := range previous.nodeOrder {
+		if _, exists := current.nodeHashes[id]; !exists {
+			delta.RemovedNodes = append(delta.RemovedNodes, id)
+		}
+	}
+
+	for _, id := range current.relOrder {
+		hash, existed := previous.relHashes[id]
+		switch {
+		case !existed:
+			delta.AddedRelationships = append(delta.AddedRelationships, current.relationships[id])
+		case hash != current.relHashes[id]:
+			delta.ChangedRelationships = append(delta.ChangedRelationships, current.relationships[id])
+		}
+	}
+	for _, id := range previous.relOrder {
+		if _, exists := current.relHashes[id]; !exists {
+			delta.RemovedRelationships = append(delta.RemovedRelationships, id)
+		}
+	}
+}
+
+// newGraphVersion fingerprints every element, styled by styler when it is not nil. The token
+// hashes the view name and the sorted fingerprints, so an unchanged view keeps its token even
+// across restarts.
+func newGraphVersion(view string, g *graphagg.GraphAggregate, styler *GraphStyler) *graphVersion {
+	version := &graphVersion{
+		nodes:         make(map[string]DeltaNode),
+		nodeHashes:    make(map[string]string),
+		relationships: make(map[string]DeltaRelationship),
+		relHashes:     make(map[string]string),
+	}
+
+	for _, node := range g.GetNodes() {
+		if _, seen := version.nodes[node.ID]; seen {
+			continue
+		}
+		deltaNode := DeltaNode{ID: node.ID, Label: node.Type, Properties: node.Properties}
+		if styler != nil {
+			deltaNode.Style = styler.NodeStyle(node.Type, node.Properties)
+		}
+		version.nodes[node.ID] = deltaNode
+		version.nodeHashes[node.ID] = fingerprint(node.Type, node.Properties)
+		version.nodeOrder = append(version.nodeOrder, node.ID)
+	}
+
+	parallel := make(map[string]int)
+	for _, rel := range g.GetRelationships() {
+		base := fmt.Sprintf("%s-%s->%s", rel.SourceNode.ID, rel.Type, rel.TargetNode.ID)
+		id := fmt.Sprintf("%s#%d", base, parallel[base])
+		parallel[base]++
+
+		deltaRel := DeltaRelationship{
+			ID: id, From: rel.SourceNode.ID, To: rel.TargetNode.ID, Type: rel.Type, Properties: rel.Properties,
+		}
+		if styler != nil {
+			deltaRel.Style = styler.RelationshipStyle(rel.Type, rel.Properties)
+		}
+		version.relationships[id] = deltaRel
+		version.relHashes[id] = fingerprint(rel.Type, rel.Properties)
+		version.relOrder = append(version.relOrder, id)
+	}
+
+	version.token = versionToken(view, version)
+	return version
+}
+
+// fingerprint hashes a label and its properties; encoding/json sorts map keys, so equal
+// properties always give the same fingerprint
+func fingerprint(label string, properties map[string]any) string {
+	encoded, err := json.Marshal(properties)
+	if err != nil {
+		encoded = fmt.Appendf(nil, "%v", properties)
+	}
+	sum := sha256.Sum256(append([]byte(label+"\x00"), encoded...))
+	return hex.EncodeToString(sum[:])
+}
+
+func versionToken(view string, version *graphVersion) string {
+	hash := sha256.New()
+	hash.Write([]byte(view + "\x00"))
+	for _, hashes := range []map[string]string{version.nodeHashes, version.relHashes} {
+		ids := make([]string, 0, len(hashes))
+		for id := range hashes {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			hash.Write([]byte(id + "\x00" + hashes[id] + "\n"))
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deltaTestGraph struct {
+	customers map[int64]string
+	orders    map[int64]int64 // order id -> customer id
+}
+
+func (d deltaTestGraph) build(t *testing.T) *graphagg.GraphAggregate {
+	t.Helper()
+	g := graphagg.NewGraphAggregate("")
+	for id := int64(1); id <= 3; id++ {
+		if name, ok := d.customers[id]; ok {
+			require.NoError(t, g.AddNode("Customer", map[string]any{"id": id, "name": name}))
+		}
+	}
+	for id := int64(10); id <= 13; id++ {
+		if customer, ok := d.orders[id]; ok {
+			require.NoError(t, g.AddNode("Order", map[string]any{"id": id}))
+			require.NoError(t, g.AddDirectRelationship("PLACED", customer, id, map[string]any{}))
+		}
+	}
+	return g
+}
+
+func TestGraphDeltaContainsOnlyChangedElements(t *testing.T) {
+	tracker := NewGraphDeltaTracker(0, nil)
+	before := deltaTestGraph{
+		customers: map[int64]string{1: "Ada", 2: "Grace", 3: "Linus"},
+		orders:    map[int64]int64{10: 1, 11: 2},
+	}
+
+	first := tracker.Delta(FullGraphView, before.build(t), "")
+	assert.True(t, first.Full)
+	assert.Len(t, first.AddedNodes, 5)
+	assert.Len(t, first.AddedRelationships, 2)
+	require.NotEmpty(t, first.Version)
+
+	// Rename one customer, drop one, add an order
+	after := deltaTestGraph{
+		customers: map[int64]string{1: "Ada Lovelace", 2: "Grace"},
+		orders:    map[int64]int64{10: 1, 11: 2, 12: 2},
+	}
+	second := tracker.Delta(FullGraphView, after.build(t), first.Version)
+	assert.False(t, second.Full)
+	assert.Equal(t, first.Version, second.Since)
+	assert.NotEqual(t, first.Version, second.Version)
+
+	require.Len(t, second.ChangedNodes, 1)
+	assert.Equal(t, "Customer_1", second.ChangedNodes[0].ID)
+	assert.Equal(t, "Ada Lovelace", second.ChangedNodes[0].Properties["name"])
+	assert.Equal(t, []string{"Customer_3"}, second.RemovedNodes)
+	require.Len(t, second.AddedNodes, 1)
+	assert.Equal(t, "Order_12", second.AddedNodes[0].ID)
+	require.Len(t, second.AddedRelationships, 1)
+	assert.Equal(t, DeltaRelationship{
+		ID: "Customer_2-PLACED->Order_12#0", From: "Customer_2", To: "Order_12", Type: "PLACED", Properties: map[string]any{},
+	}, second.AddedRelationships[0])
+	assert.Empty(t, second.ChangedRelationships)
+	assert.Empty(t, second.RemovedRelationships)
+
+	// The returned version is the token for the next call
+	third := tracker.Delta(FullGraphView, after.build(t), second.Version)
+	assert.Equal(t, second.Version, third.Version)
+	assert.False(t, third.Full)
+	assert.Empty(t, third.AddedNodes)
+	assert.Empty(t, third.ChangedNodes)
+	assert.Empty(t, third.RemovedNodes)
+	assert.Empty(t, third.AddedRelationships)
+
+	// An older token still diffs against its own version
+	fromFirst := tracker.Delta(FullGraphView, after.build(t), first.Version)
+	assert.Equal(t, second.ChangedNodes, fromFirst.ChangedNodes)
+}
+
+func TestGraphDeltaFallsBackToFullGraphForUnknownVersions(t *testing.T) {
+	tracker := NewGraphDeltaTracker(2, nil)
+	graphWithName := func(name string) *graphagg.GraphAggregate {
+		return deltaTestGraph{customers: map[int64]string{1: name}}.build(t)
+	}
+
+	oldest := tracker.Delta("customers", graphWithName("a"), "").Version
+	tracker.Delta("customers", graphWithName("b"), "")
+	tracker.Delta("customers", graphWithName("c"), "")
+
+	delta := tracker.Delta("customers", graphWithName("c"), oldest)
+	assert.True(t, delta.Full, "evicted versions fall back to the full graph")
+	assert.Len(t, delta.AddedNodes, 1)
+
+	other := tracker.Delta("orders", graphWithName("c"), delta.Version)
+	assert.True(t, other.Full, "versions are tracked per view")
+}
+
+func TestGraphDeltaIncludesStyles(t *testing.T) {
+	styler, err := NewGraphStyler([]StylingRule{{
+		Name: "vip", Label: "Customer",
+		When:  []StylePredicate{{Property: "name", Value: "Ada"}},
+		Style: map[string]any{"color": "gold"},
+	}})
+	require.NoError(t, err)
+
+	delta := NewGraphDeltaTracker(0, styler).Delta(FullGraphView,
+		deltaTestGraph{customers: map[int64]string{1: "Ada", 2: "Grace"}}.build(t), "")
+	require.Len(t, delta.AddedNodes, 2)
+	assert.Equal(t, map[string]any{"color": "gold"}, delta.AddedNodes[0].Style)
+	assert.Nil(t, delta.AddedNodes[1].Style)
+}
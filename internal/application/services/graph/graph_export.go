@@ -0,0 +1,406 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+)
+
+// Graph export formats
+const (
+	ExportFormatGraphML = "graphml"
+	ExportFormatCypher  = "cypher"
+)
+
+const (
+	// DefaultMaxInMemoryElements is the graph size, in nodes plus relationships, above which
+	// an export is streamed page by page
+	DefaultMaxInMemoryElements = 100000
+	// DefaultExportPageSize is how many nodes or relationships a streamed export reads at once
+	DefaultExportPageSize = 1000
+)
+
+// ErrUnsupportedExportFormat is returned for a format other than graphml or cypher
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// ExportContentType returns the content type and file extension of an export format
+func ExportContentType(format string) (string, string, error) {
+	switch format {
+	case ExportFormatGraphML:
+		return "application/graphml+xml", "graphml", nil
+	case ExportFormatCypher:
+		return "text/plain; charset=utf-8", "cypher", nil
+	}
+	return "", "", fmt.Errorf("%w: %q (use graphml or cypher)", ErrUnsupportedExportFormat, format)
+}
+
+// ExportStats describes a finished or interrupted export. Bytes counts what reached the
+// writer, so zero means nothing was written before a failure.
+type ExportStats struct {
+	Format        string `json:"format"`
+	Streamed      bool   `json:"streamed"`
+	Nodes         int64  `json:"nodes"`
+	Relationships int64  `json:"relationships"`
+	Bytes         int64  `json:"bytes"`
+}
+
+// GraphExporter writes the stored graph as GraphML or Cypher. Graphs up to maxInMemory
+// elements are exported as a whole; larger ones are streamed in pages when the port supports
+// paged reads, so memory use depends on the page size rather than the graph size.
+type GraphExporter struct {
+	neo4jPort   ports.Neo4jPort
+	maxInMemory int64
+	pageSize    int
+}
+
+// NewGraphExporter creates an exporter; zero or negative limits select the defaults
+func NewGraphExporter(neo4jPort ports.Neo4jPort, maxInMemory int64, pageSize int) *GraphExporter {
+	if maxInMemory <= 0 {
+		maxInMemory = DefaultMaxInMemoryElements
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultExportPageSize
+	}
+	return &GraphExporter{neo4jPort: neo4jPort, maxInMemory: maxInMemory, pageSize: pageSize}
+}
+
+// Export writes the whole graph to w in format. An error after output has started leaves
+// a truncated export in w.
+func (e *GraphExporter) Export(ctx context.Context, w io.Writer, format string) (ExportStats, error) {
+	return e.ExportWindowed(ctx, w, format, ExportWindow{})
+}
+
+// ExportWindowed writes the nodes and relationships imported within window, for incremental
+// exports of what recent imports changed. A relationship is written only when it and both
+// its endpoints were imported within the window, so every edge refers to a written node.
+func (e *GraphExporter) ExportWindowed(ctx context.Context, w io.Writer, format string, window ExportWindow) (ExportStats, error) {
+	stats := ExportStats{Format: format}
+	if _, _, err := ExportContentType(format); err != nil {
+		return stats, err
+	}
+
+	counter := &countingWriter{w: w}
+	err := e.export(ctx, counter, format, window, &stats)
+	stats.Bytes = counter.n
+	return stats, err
+}
+
+func (e *GraphExporter) export(ctx context.Context, w io.Writer, format string, window ExportWindow, stats *ExportStats) error {
+	buffered := bufio.NewWriterSize(w, 64*1024)
+	out := newExportWriter(format, buffered)
+
+	if reader, ok := e.neo4jPort.(ports.GraphPageReader); ok {
+		counts, err := reader.CountGraph(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count graph: %w", err)
+		}
+		if counts.Nodes+counts.Relationships > e.maxInMemory {
+			stats.Streamed = true
+			if err := e.stream(ctx, reader, out, window, stats); err != nil {
+				return err
+			}
+			return buffered.Flush()
+		}
+	}
+
+	exported, err := e.neo4jPort.ExportGraph(fullGraphQuery)
+	if err != nil {
+		return fmt.Errorf("failed to export graph: %w", err)
+	}
+	g, ok := exported.(*graphagg.GraphAggregate)
+	if !ok {
+		return fmt.Errorf("unexpected graph type %T", exported)
+	}
+	if err := writeAggregate(g, out, window, stats); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// stream copies the graph page by page, nodes first so every edge refers to a written node.
+// The window is applied by the page reads.
+func (e *GraphExporter) stream(ctx context.Context, reader ports.GraphPageReader, out exportWriter, window ExportWindow, stats *ExportStats) error {
+	if err := out.begin(); err != nil {
+		return err
+	}
+
+	for after := int64(-1); ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		nodes, err := reader.ReadNodePage(ctx, after, e.pageSize, ports.ImportWindow(window))
+		if err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			if err := out.node(node); err != nil {
+				return err
+			}
+			stats.Nodes++
+		}
+		if len(nodes) < e.pageSize {
+			break
+		}
+		after = nodes[len(nodes)-1].ID
+	}
+
+	for after := int64(-1); ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rels, err := reader.ReadRelationshipPage(ctx, after, e.pageSize, ports.ImportWindow(window))
+		if err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			if err := out.relationship(rel); err != nil {
+				return err
+			}
+			stats.Relationships++
+		}
+		if len(rels) < e.pageSize {
+			break
+		}
+		after = rels[len(rels)-1].ID
+	}
+
+	return out.end()
+}
+
+func writeAggregate(g *graphagg.GraphAggregate, out exportWriter, window ExportWindow, stats *ExportStats) error {
+	if err := out.begin(); err != nil {
+		return err
+	}
+	for _, node := range g.GetNodes() {
+		if !window.Contains(node.Properties) {
+			continue
+		}
+		if err := out.node(ports.ExportedNode{Label: node.Type, Properties: node.Properties}); err != nil {
+			return err
+		}
+		stats.Nodes++
+	}
+	for _, rel := range g.GetRelationships() {
+		if !window.Contains(rel.Properties) || !window.Contains(rel.SourceNode.Properties) || !window.Contains(rel.TargetNode.Properties) {
+			continue
+		}
+		err := out.relationship(ports.ExportedRelationship{
+			Type:        rel.Type,
+			SourceLabel: rel.SourceNode.Type,
+			SourceKey:   rel.SourceNode.Properties["id"],
+			TargetLabel: rel.TargetNode.Type,
+			TargetKey:   rel.TargetNode.Properties["id"],
+			Properties:  rel.Properties,
+		})
+		if err != nil {
+			return err
+		}
+		stats.Relationships++
+	}
+	return out.end()
+}
+
+// exportWriter renders graph elements one at a time in an export format
+type exportWriter interface {
+	begin() error
+	node(node ports.ExportedNode) error
+	relationship(rel ports.ExportedRelationship) error
+	end() error
+}
+
+func newExportWriter(format string, w *bufio.Writer) exportWriter {
+	if format == ExportFormatCypher {
+		return &cypherWriter{w: w}
+	}
+	return &graphMLWriter{w: w}
+}
+
+// graphMLWriter writes properties as one JSON-encoded attribute, since GraphML declares its
+// keys before the graph and a streamed export does not know every property in advance
+type graphMLWriter struct {
+	w *bufio.Writer
+}
+
+func (g *graphMLWriter) begin() error {
+	_, err := g.w.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="label" for="node" attr.name="label" attr.type="string"/>
+  <key id="type" for="edge" attr.name="type" attr.type="string"/>
+  <key id="properties" for="all" attr.name="properties" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+`)
+	return err
+}
+
+func (g *graphMLWriter) node(node ports.ExportedNode) error {
+	g.w.WriteString(`    <node id="`)
+	g.escape(exportNodeID(node.Label, node.Properties["id"]))
+	g.w.WriteString(`"><data key="label">`)
+	g.escape(node.Label)
+	g.w.WriteString(`</data>`)
+	if err := g.properties(node.Properties); err != nil {
+		return err
+	}
+	_, err := g.w.WriteString("</node>\n")
+	return err
+}
+
+func (g *graphMLWriter) relationship(rel ports.ExportedRelationship) error {
+	g.w.WriteString(`    <edge source="`)
+	g.escape(exportNodeID(rel.SourceLabel, rel.SourceKey))
+	g.w.WriteString(`" target="`)
+	g.escape(exportNodeID(rel.TargetLabel, rel.TargetKey))
+	g.w.WriteString(`"><data key="type">`)
+	g.escape(rel.Type)
+	g.w.WriteString(`</data>`)
+	if err := g.properties(rel.Properties); err != nil {
+		return err
+	}
+	_, err := g.w.WriteString("</edge>\n")
+	return err
+}
+
+func (g *graphMLWriter) end() error {
+	_, err := g.w.WriteString("  </graph>\n</graphml>\n")
+	return err
+}
+
+func (g *graphMLWriter) properties(properties map[string]any) error {
+	if len(properties) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(properties)
+	if err != nil {
+		return fmt.Errorf("failed to encode properties: %w", err)
+	}
+	g.w.WriteString(`<data key="properties">`)
+	g.escape(string(encoded))
+	_, err = g.w.WriteString(`</data>`)
+	return err
+}
+
+func (g *graphMLWriter) escape(text string) {
+	// Writes to a bufio.Writer only fail with its sticky error, reported by the next check
+	_ = xml.EscapeText(g.w, []byte(text))
+}
+
+// cypherWriter writes one statement per element; relationships match their endpoints by
+// label and id property, so the script can be replayed into an empty database
+type cypherWriter struct {
+	w *bufio.Writer
+}
+
+func (c *cypherWriter) begin() error { return nil }
+
+func (c *cypherWriter) node(node ports.ExportedNode) error {
+	_, err := fmt.Fprintf(c.w, "CREATE (:%s %s);\n", cypherIdentifier(node.Label), cypherMap(node.Properties))
+	return err
+}
+
+func (c *cypherWriter) relationship(rel ports.ExportedRelationship) error {
+	_, err := fmt.Fprintf(c.w, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) CREATE (a)-[:%s %s]->(b);\n",
+		cypherIdentifier(rel.SourceLabel), cypherLiteral(rel.SourceKey),
+		cypherIdentifier(rel.TargetLabel), cypherLiteral(rel.TargetKey),
+		cypherIdentifier(rel.Type), cypherMap(rel.Properties))
+	return err
+}
+
+func (c *cypherWriter) end() error { return nil }
+
+// exportNodeID identifies a node the way the graph aggregate does
+func exportNodeID(label string, key any) string {
+	return fmt.Sprintf("%s_%v", label, key)
+}
+
+func cypherIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func cypherMap(properties map[string]any) string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(cypherIdentifier(key))
+		b.WriteString(": ")
+		b.WriteString(cypherLiteral(properties[key]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func cypherLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return cypherString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return "datetime(" + cypherString(v.Format(time.RFC3339Nano)) + ")"
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = cypherLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		return cypherMap(v)
+	}
+	return cypherString(fmt.Sprint(value))
+}
+
+func cypherString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return "'" + s + "'"
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
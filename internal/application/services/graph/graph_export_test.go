@@ -0,0 +1,314 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedGraphPort generates a ring of people on demand: node i knows node i+1. Pages are
+// built when requested, so the port itself holds no graph.
+type pagedGraphPort struct {
+	recordingNeo4jPort
+	people int64
+	bio    string
+	// importedAt, when set, gives each element an import timestamp
+	importedAt func(id int64) any
+
+	exportCalls  int
+	windows      []ports.ImportWindow
+	pages        int
+	largestPage  int
+	onPageServed func()
+}
+
+func newPagedGraphPort(people int64) *pagedGraphPort {
+	return &pagedGraphPort{people: people, bio: strings.Repeat("x", 100)}
+}
+
+func (p *pagedGraphPort) person(id int64) ports.ExportedNode {
+	node := ports.ExportedNode{ID: id, Label: "Person", Properties: map[string]any{
+		"id": id, "name": "Person " + string(rune('A'+id%26)), "bio": p.bio,
+	}}
+	if p.importedAt != nil {
+		node.Properties[transform.ProvenanceImportedAt] = p.importedAt(id)
+	}
+	return node
+}
+
+func (p *pagedGraphPort) knows(id int64) ports.ExportedRelationship {
+	rel := ports.ExportedRelationship{
+		ID: id, Type: "KNOWS",
+		SourceLabel: "Person", SourceKey: id,
+		TargetLabel: "Person", TargetKey: (id + 1) % p.people,
+		Properties: map[string]any{"since": int64(2000) + id%20},
+	}
+	if p.importedAt != nil {
+		rel.Properties[transform.ProvenanceImportedAt] = p.importedAt(id)
+	}
+	return rel
+}
+
+func (p *pagedGraphPort) CountGraph(ctx context.Context) (ports.GraphCounts, error) {
+	return ports.GraphCounts{Nodes: p.people, Relationships: p.people}, nil
+}
+
+func (p *pagedGraphPort) ReadNodePage(ctx context.Context, afterID int64, limit int, window ports.ImportWindow) ([]ports.ExportedNode, error) {
+	var page []ports.ExportedNode
+	for id := afterID + 1; id < p.people && len(page) < limit; id++ {
+		if node := p.person(id); ExportWindow(window).Contains(node.Properties) {
+			page = append(page, node)
+		}
+	}
+	p.served(len(page))
+	return page, nil
+}
+
+func (p *pagedGraphPort) ReadRelationshipPage(ctx context.Context, afterID int64, limit int, window ports.ImportWindow) ([]ports.ExportedRelationship, error) {
+	var page []ports.ExportedRelationship
+	for id := afterID + 1; id < p.people && len(page) < limit; id++ {
+		rel := p.knows(id)
+		source, target := p.person(id), p.person((id+1)%p.people)
+		if ExportWindow(window).Contains(rel.Properties) && ExportWindow(window).Contains(source.Properties) && ExportWindow(window).Contains(target.Properties) {
+			page = append(page, rel)
+		}
+	}
+	p.windows = append(p.windows, window)
+	p.served(len(page))
+	return page, nil
+}
+
+func (p *pagedGraphPort) served(size int) {
+	p.pages++
+	if size > p.largestPage {
+		p.largestPage = size
+	}
+	if p.onPageServed != nil {
+		p.onPageServed()
+	}
+}
+
+func (p *pagedGraphPort) ExportGraph(query string) (any, error) {
+	p.exportCalls++
+	g := graphagg.NewGraphAggregate("")
+	for id := int64(0); id < p.people; id++ {
+		node := p.person(id)
+		if err := g.AddNode(node.Label, node.Properties); err != nil {
+			return nil, err
+		}
+	}
+	for id := int64(0); id < p.people; id++ {
+		rel := p.knows(id)
+		if err := g.AddDirectRelationship(rel.Type, rel.SourceKey, rel.TargetKey, rel.Properties); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func TestGraphExporterStreamsLargeGraphWithBoundedMemory(t *testing.T) {
+	const people, pageSize = 50000, 500
+	port := newPagedGraphPort(people)
+
+	// Sample the live heap while pages are served; a materialized graph of this size would
+	// take well over the allowed growth
+	var before, sample runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	var peak uint64
+	port.onPageServed = func() {
+		if port.pages%20 != 0 {
+			return
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&sample)
+		if sample.HeapAlloc > peak {
+			peak = sample.HeapAlloc
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "graph.graphml")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	stats, err := NewGraphExporter(port, 1000, pageSize).Export(context.Background(), file, ExportFormatGraphML)
+	require.NoError(t, err)
+
+	assert.True(t, stats.Streamed)
+	assert.Zero(t, port.exportCalls, "a large graph must not be materialized")
+	assert.Equal(t, pageSize, port.largestPage)
+	assert.EqualValues(t, people, stats.Nodes)
+	assert.EqualValues(t, people, stats.Relationships)
+	if peak > before.HeapAlloc {
+		assert.Less(t, peak-before.HeapAlloc, uint64(16<<20), "heap grew with the graph size")
+	}
+
+	// The output is well-formed and holds every node and edge
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	decoder := xml.NewDecoder(bufio.NewReader(file))
+	counts := map[string]int{}
+	var lastNode string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if start, ok := token.(xml.StartElement); ok {
+			counts[start.Name.Local]++
+			if start.Name.Local == "node" {
+				lastNode = start.Attr[0].Value
+			}
+		}
+	}
+	assert.Equal(t, people, counts["node"])
+	assert.Equal(t, people, counts["edge"])
+	assert.Equal(t, "Person_49999", lastNode)
+	assert.EqualValues(t, stats.Bytes, fileSize(t, path))
+}
+
+func TestGraphExporterStreamedOutputMatchesMaterialized(t *testing.T) {
+	for _, format := range []string{ExportFormatGraphML, ExportFormatCypher} {
+		port := newPagedGraphPort(7)
+
+		var materialized, streamed bytes.Buffer
+		stats, err := NewGraphExporter(port, 100, 3).Export(context.Background(), &materialized, format)
+		require.NoError(t, err)
+		assert.False(t, stats.Streamed)
+		assert.Equal(t, 1, port.exportCalls)
+
+		stats, err = NewGraphExporter(port, 10, 3).Export(context.Background(), &streamed, format)
+		require.NoError(t, err)
+		assert.True(t, stats.Streamed)
+		assert.Equal(t, 1, port.exportCalls)
+
+		assert.Equal(t, materialized.String(), streamed.String(), format)
+		assert.EqualValues(t, 7, stats.Nodes)
+		assert.EqualValues(t, 7, stats.Relationships)
+	}
+}
+
+func TestGraphExporterWindowExportsOnlyElementsImportedWithin(t *testing.T) {
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	port := newPagedGraphPort(10)
+	port.importedAt = func(id int64) any {
+		switch {
+		case id == 9:
+			return nil // imported with provenance disabled
+		case id%2 == 0:
+			return base.Add(time.Duration(id) * 24 * time.Hour).Format(time.RFC3339)
+		}
+		return base.Add(time.Duration(id) * 24 * time.Hour)
+	}
+	window := ExportWindow{From: base.Add(3 * 24 * time.Hour), To: base.Add(7 * 24 * time.Hour)}
+
+	exportedIDs := func(out string) []string {
+		var ids []string
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "CREATE (:`Person` {`_imported_at`") {
+				ids = append(ids, line[strings.Index(line, "`id`: ")+6:strings.Index(line, ", `name`")])
+			}
+		}
+		return ids
+	}
+
+	for name, exporter := range map[string]*GraphExporter{
+		"materialized": NewGraphExporter(port, 100, 3),
+		"streamed":     NewGraphExporter(port, 10, 3),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			stats, err := exporter.ExportWindowed(context.Background(), &out, ExportFormatCypher, window)
+			require.NoError(t, err)
+			assert.Equal(t, name == "streamed", stats.Streamed)
+
+			assert.Equal(t, []string{"3", "4", "5", "6"}, exportedIDs(out.String()), "[from, to) by import time")
+			assert.EqualValues(t, 4, stats.Nodes)
+			assert.EqualValues(t, 3, stats.Relationships)
+			assert.NotContains(t, out.String(), "{id: 2})", "relationships imported before the window are left out")
+			assert.NotContains(t, out.String(), "{id: 7})", "relationships to nodes outside the window are left out")
+		})
+	}
+
+	assert.Contains(t, port.windows, ports.ImportWindow(window), "the window is passed to the page reads")
+
+	var out bytes.Buffer
+	stats, err := NewGraphExporter(port, 100, 3).ExportWindowed(context.Background(), &out, ExportFormatCypher,
+		ExportWindow{From: base.Add(8 * 24 * time.Hour)})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, stats.Nodes, "an open end includes everything imported since from, but not untimed elements")
+}
+
+func TestParseExportWindow(t *testing.T) {
+	window, err := ParseExportWindow("", "")
+	require.NoError(t, err)
+	assert.True(t, window.IsZero())
+
+	window, err = ParseExportWindow("2025-03-01T00:00:00Z", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), window.From)
+	assert.True(t, window.To.IsZero())
+
+	_, err = ParseExportWindow("yesterday", "")
+	assert.ErrorIs(t, err, ErrInvalidExportWindow)
+	_, err = ParseExportWindow("2025-03-02T00:00:00Z", "2025-03-01T00:00:00Z")
+	assert.ErrorIs(t, err, ErrInvalidExportWindow)
+}
+
+func TestGraphExporterCypherStatements(t *testing.T) {
+	g := graphagg.NewGraphAggregate("")
+	require.NoError(t, g.AddNode("Customer", map[string]any{"id": int64(1), "name": "O'Brien", "vip": true}))
+	require.NoError(t, g.AddNode("Order", map[string]any{"id": "A-1", "total": 12.5}))
+	require.NoError(t, g.AddDirectRelationship("PLACED", int64(1), "A-1", map[string]any{"via": []any{"web", int64(2)}}))
+
+	var out bytes.Buffer
+	_, err := NewGraphExporter(&viewGraphPort{subgraphs: map[string]*graphagg.GraphAggregate{fullGraphQuery: g}}, 0, 0).
+		Export(context.Background(), &out, ExportFormatCypher)
+	require.NoError(t, err)
+
+	assert.Equal(t, "CREATE (:`Customer` {`id`: 1, `name`: 'O\\'Brien', `vip`: true});\n"+
+		"CREATE (:`Order` {`id`: 'A-1', `total`: 12.5});\n"+
+		"MATCH (a:`Customer` {id: 1}), (b:`Order` {id: 'A-1'}) CREATE (a)-[:`PLACED` {`via`: ['web', 2]}]->(b);\n",
+		out.String())
+}
+
+func TestGraphExporterRejectsUnknownFormat(t *testing.T) {
+	var out bytes.Buffer
+	stats, err := NewGraphExporter(newPagedGraphPort(1), 0, 0).Export(context.Background(), &out, "dot")
+	assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+	assert.Zero(t, stats.Bytes)
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.Size()
+}
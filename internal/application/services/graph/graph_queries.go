@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/ports"
+)
+
+const (
+	// QueryModeAllowList runs only the registered named queries
+	QueryModeAllowList = "allow_list"
+	// QueryModeOpen also runs Cypher sent by the client
+	QueryModeOpen = "open"
+)
+
+var (
+	// ErrUnknownQuery is returned when a requested named query is not registered
+	ErrUnknownQuery = errors.New("unknown graph query")
+	// ErrQueryNotAllowed is returned for client Cypher in allow-list mode
+	ErrQueryNotAllowed = errors.New("only registered queries may run")
+	// ErrInvalidQueryParams is returned when the parameters do not match the named query
+	ErrInvalidQueryParams = errors.New("invalid query parameters")
+)
+
+// NamedQuery is a registered parameterized Cypher query; clients run it by name and pass
+// values for Params, which the Cypher references as $name
+type NamedQuery struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Cypher      string   `json:"cypher"`
+	Params      []string `json:"params,omitempty"`
+}
+
+// GraphQueryService runs Cypher queries against the graph for API clients. In allow-list mode
+// only the registered named queries can run.
+type GraphQueryService struct {
+	neo4jPort ports.Neo4jPort
+	queries   []NamedQuery
+	mode      string
+}
+
+// NewGraphQueryService creates a query service; an empty mode means QueryModeAllowList
+func NewGraphQueryService(neo4jPort ports.Neo4jPort, queries []NamedQuery, mode string) (*GraphQueryService, error) {
+	switch mode {
+	case "":
+		mode = QueryModeAllowList
+	case QueryModeAllowList, QueryModeOpen:
+	default:
+		return nil, fmt.Errorf("unknown query mode %q (expected %q or %q)", mode, QueryModeAllowList, QueryModeOpen)
+	}
+
+	seen := map[string]bool{}
+	for _, query := range queries {
+		if query.Name == "" {
+			return nil, fmt.Errorf("graph query without a name")
+		}
+		if strings.TrimSpace(query.Cypher) == "" {
+			return nil, fmt.Errorf("graph query %q has no cypher", query.Name)
+		}
+		if seen[query.Name] {
+			return nil, fmt.Errorf("duplicate graph query %q", query.Name)
+		}
+		seen[query.Name] = true
+	}
+
+	return &GraphQueryService{neo4jPort: neo4jPort, queries: append([]NamedQuery(nil), queries...), mode: mode}, nil
+}
+
+// Mode returns QueryModeAllowList or QueryModeOpen
+func (s *GraphQueryService) Mode() string {
+	return s.mode
+}
+
+// Queries returns the registered named queries
+func (s *GraphQueryService) Queries() []NamedQuery {
+	return append([]NamedQuery(nil), s.queries...)
+}
+
+// Run executes the named query with params, which must provide exactly its declared parameters
+func (s *GraphQueryService) Run(name string, params map[string]any) ([]map[string]any, error) {
+	var query *NamedQuery
+	for i := range s.queries {
+		if s.queries[i].Name == name {
+			query = &s.queries[i]
+			break
+		}
+	}
+	if query == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownQuery, name)
+	}
+
+	declared := make(map[string]bool, len(query.Params))
+	for _, param := range query.Params {
+		if _, ok := params[param]; !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidQueryParams, param)
+		}
+		declared[param] = true
+	}
+	var unknown []string
+	for param := range params {
+		if !declared[param] {
+			unknown = append(unknown, param)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: query %s does not take %s", ErrInvalidQueryParams, name, strings.Join(unknown, ", "))
+	}
+
+	return s.execute(query.Cypher, params)
+}
+
+// RunCypher executes client Cypher; it fails with ErrQueryNotAllowed unless the service is in
+// open mode
+func (s *GraphQueryService) RunCypher(cypher string, params map[string]any) ([]map[string]any, error) {
+	if s.mode != QueryModeOpen {
+		return nil, ErrQueryNotAllowed
+	}
+	return s.execute(cypher, params)
+}
+
+func (s *GraphQueryService) execute(cypher string, params map[string]any) ([]map[string]any, error) {
+	if params == nil {
+		params = map[string]any{}
+	}
+	rows, err := s.neo4jPort.ExecuteQuery(cypher, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run graph query: %w", err)
+	}
+	if rows == nil {
+		rows = []map[string]any{}
+	}
+	return rows, nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryGraphPort records the queries and parameters it runs
+type queryGraphPort struct {
+	recordingNeo4jPort
+	params []map[string]any
+}
+
+func (p *queryGraphPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	p.statements = append(p.statements, query)
+	p.params = append(p.params, params)
+	return []map[string]interface{}{{"name": "Ada"}}, nil
+}
+
+const customerOrdersQuery = "MATCH (c:Customer {id: $customer_id})-[:PLACED]->(o) RETURN o"
+
+func customerOrders() NamedQuery {
+	return NamedQuery{Name: "customer_orders", Cypher: customerOrdersQuery, Params: []string{"customer_id"}}
+}
+
+func TestGraphQueryServiceRunsRegisteredQuery(t *testing.T) {
+	port := &queryGraphPort{}
+	service, err := NewGraphQueryService(port, []NamedQuery{customerOrders()}, "")
+	require.NoError(t, err)
+	assert.Equal(t, QueryModeAllowList, service.Mode())
+
+	rows, err := service.Run("customer_orders", map[string]any{"customer_id": "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"name": "Ada"}}, rows)
+	assert.Equal(t, []string{customerOrdersQuery}, port.statements)
+	assert.Equal(t, map[string]any{"customer_id": "1"}, port.params[0])
+}
+
+func TestGraphQueryServiceRejectsArbitraryCypherInAllowListMode(t *testing.T) {
+	port := &queryGraphPort{}
+	service, err := NewGraphQueryService(port, []NamedQuery{customerOrders()}, QueryModeAllowList)
+	require.NoError(t, err)
+
+	_, err = service.RunCypher("MATCH (n) DETACH DELETE n", nil)
+	assert.ErrorIs(t, err, ErrQueryNotAllowed)
+	_, err = service.Run("delete_everything", nil)
+	assert.ErrorIs(t, err, ErrUnknownQuery)
+	assert.Empty(t, port.statements)
+}
+
+func TestGraphQueryServiceChecksParams(t *testing.T) {
+	port := &queryGraphPort{}
+	service, err := NewGraphQueryService(port, []NamedQuery{customerOrders()}, "")
+	require.NoError(t, err)
+
+	_, err = service.Run("customer_orders", nil)
+	assert.ErrorIs(t, err, ErrInvalidQueryParams)
+	_, err = service.Run("customer_orders", map[string]any{"customer_id": "1", "limit": 5})
+	assert.ErrorIs(t, err, ErrInvalidQueryParams)
+	assert.Empty(t, port.statements)
+}
+
+func TestGraphQueryServiceOpenModeRunsCypher(t *testing.T) {
+	port := &queryGraphPort{}
+	service, err := NewGraphQueryService(port, nil, QueryModeOpen)
+	require.NoError(t, err)
+
+	_, err = service.RunCypher("MATCH (n) RETURN count(n)", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MATCH (n) RETURN count(n)"}, port.statements)
+}
+
+func TestNewGraphQueryServiceRejectsInvalidConfig(t *testing.T) {
+	_, err := NewGraphQueryService(nil, nil, "anything")
+	assert.Error(t, err)
+	_, err = NewGraphQueryService(nil, []NamedQuery{{Name: "empty"}}, "")
+	assert.Error(t, err)
+	_, err = NewGraphQueryService(nil, []NamedQuery{customerOrders(), customerOrders()}, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+)
+
+// DefaultSnapshotRetention is the number of transform snapshots kept
+const DefaultSnapshotRetention = 20
+
+// snapshotView names the graph snapshots are taken of in their version tokens
+const snapshotView = "transform"
+
+// ErrSnapshotNotFound is returned for snapshot IDs that were never taken or already dropped
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrSnapshotCountsOnly is returned when comparing a snapshot that keeps only counts
+var ErrSnapshotCountsOnly = errors.New("snapshot keeps only counts")
+
+// GraphSnapshot is the graph written by a completed transform run, with the run's metadata.
+// Version is the graph's content token, equal for runs producing the same graph.
+type GraphSnapshot struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"version"`
+	ports.GraphSnapshotMetadata
+	NodeCount           int            `json:"node_count"`
+	RelationshipCount   int            `json:"relationship_count"`
+	NodesByLabel        map[string]int `json:"nodes_by_label"`
+	RelationshipsByType map[string]int `json:"relationships_by_type"`
+	// CountsOnly marks a snapshot of a run that stored its graph batch by batch; it keeps
+	// no graph to compare with other snapshots
+	CountsOnly bool `json:"counts_only,omitempty"`
+
+	graph *graphVersion
+}
+
+// SnapshotDiff lists how the graph changed from one snapshot to another
+type SnapshotDiff struct {
+	From    GraphSnapshot `json:"from"`
+	To      GraphSnapshot `json:"to"`
+	Changes GraphDelta    `json:"changes"`
+}
+
+// GraphSnapshotStore keeps the graphs of the most recent transform runs, so the history of
+// the graph across imports can be listed and any two runs compared
+type GraphSnapshotStore struct {
+	mu        sync.Mutex
+	retention int
+	nextID    int
+	snapshots []*GraphSnapshot
+	now       func() time.Time
+	// tableSizes are the captured source table sizes, oldest first; see RecordTableSizes
+	tableSizes []TableSizeSnapshot
+}
+
+// NewGraphSnapshotStore creates a store keeping retention snapshots (DefaultSnapshotRetention
+// when not positive); the oldest is dropped when a new one exceeds it
+func NewGraphSnapshotStore(retention int) *GraphSnapshotStore {
+	if retention <= 0 {
+		retention = DefaultSnapshotRetention
+	}
+	return &GraphSnapshotStore{retention: retention, nextID: 1, now: time.Now}
+}
+
+// RecordSnapshot captures g as the graph of the run described by metadata
+func (s *GraphSnapshotStore) RecordSnapshot(g *graphagg.GraphAggregate, metadata ports.GraphSnapshotMetadata) {
+	version := newGraphVersion(snapshotView, g, nil)
+	snapshot := &GraphSnapshot{
+		Version:               version.token,
+		GraphSnapshotMetadata: metadata,
+		NodeCount:             len(version.nodeOrder),
+		RelationshipCount:     len(version.relOrder),
+		NodesByLabel:          make(map[string]int),
+		RelationshipsByType:   make(map[string]int),
+		graph:                 version,
+	}
+	for _, node := range version.nodes {
+		snapshot.NodesByLabel[node.Label]++
+	}
+	for _, rel := range version.relationships {
+		snapshot.RelationshipsByType[rel.Type]++
+	}
+	s.add(snapshot)
+}
+
+// RecordCounts captures the counts of a run whose graph was not kept as a whole
+func (s *GraphSnapshotStore) RecordCounts(counts ports.GraphSnapshotCounts, metadata ports.GraphSnapshotMetadata) {
+	snapshot := &GraphSnapshot{
+		GraphSnapshotMetadata: metadata,
+		NodesByLabel:          make(map[string]int),
+		RelationshipsByType:   make(map[string]int),
+		CountsOnly:            true,
+	}
+	for label, count := range counts.NodesByLabel {
+		snapshot.NodesByLabel[label] = count
+		snapshot.NodeCount += count
+	}
+	for relType, count := range counts.RelationshipsByType {
+		snapshot.RelationshipsByType[relType] = count
+		snapshot.RelationshipCount += count
+	}
+	s.add(snapshot)
+}
+
+// add numbers and keeps snapshot, dropping the oldest beyond the retention
+func (s *GraphSnapshotStore) add(snapshot *GraphSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot.ID = s.nextID
+	snapshot.CreatedAt = s.now()
+	s.nextID++
+	s.snapshots = append(s.snapshots, snapshot)
+	if len(s.snapshots) > s.retention {
+		s.snapshots = s.snapshots[len(s.snapshots)-s.retention:]
+	}
+}
+
+// List returns the kept snapshots, oldest first
+func (s *GraphSnapshotStore) List() []GraphSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshots := make([]GraphSnapshot, len(s.snapshots))
+	for i, snapshot := range s.snapshots {
+		snapshots[i] = *snapshot
+	}
+	return snapshots
+}
+
+// Get returns the snapshot with the given ID
+func (s *GraphSnapshotStore) Get(id int) (GraphSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snapshot := range s.snapshots {
+		if snapshot.ID == id {
+			return *snapshot, nil
+		}
+	}
+	return GraphSnapshot{}, fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+}
+
+// Previous returns the snapshot taken before the one with the given ID
+func (s *GraphSnapshotStore) Previous(id int) (GraphSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, snapshot := range s.snapshots {
+		if snapshot.ID == id {
+			if i == 0 {
+				return GraphSnapshot{}, fmt.Errorf("%w: no snapshot before %d", ErrSnapshotNotFound, id)
+			}
+			return *s.snapshots[i-1], nil
+		}
+	}
+	return GraphSnapshot{}, fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+}
+
+// Diff compares two snapshots: elements only in to are added, elements only in from are
+// removed and elements whose properties differ are changed
+func (s *GraphSnapshotStore) Diff(fromID, toID int) (*SnapshotDiff, error) {
+	from, err := s.Get(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Get(toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.CountsOnly || to.CountsOnly {
+		return nil, fmt.Errorf("%w: runs stored batch by batch cannot be compared", ErrSnapshotCountsOnly)
+	}
+
+	changes := newGraphDelta(snapshotView, to.Version, from.Version)
+	diffVersions(&changes, from.graph, to.graph)
+	return &SnapshotDiff{From: from, To: to, Changes: changes}, nil
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphSnapshotsRecordCountsAndMetadata(t *testing.T) {
+	store := NewGraphSnapshotStore(0)
+	g := deltaTestGraph{
+		customers: map[int64]string{1: "Ada", 2: "Grace", 3: "Linus"},
+		orders:    map[int64]int64{10: 1, 11: 2},
+	}.build(t)
+
+	store.RecordSnapshot(g, ports.GraphSnapshotMetadata{SourceFingerprint: "src1", RuleSetVersion: "rules1"})
+
+	snapshots := store.List()
+	require.Len(t, snapshots, 1)
+	snapshot := snapshots[0]
+	assert.Equal(t, 1, snapshot.ID)
+	assert.Equal(t, 5, snapshot.NodeCount)
+	assert.Equal(t, 2, snapshot.RelationshipCount)
+	assert.Equal(t, map[string]int{"Customer": 3, "Order": 2}, snapshot.NodesByLabel)
+	assert.Equal(t, map[string]int{"PLACED": 2}, snapshot.RelationshipsByType)
+	assert.Equal(t, "src1", snapshot.SourceFingerprint)
+	assert.Equal(t, "rules1", snapshot.RuleSetVersion)
+	assert.False(t, snapshot.CreatedAt.IsZero())
+	assert.NotEmpty(t, snapshot.Version)
+}
+
+func TestGraphSnapshotsDiff(t *testing.T) {
+	store := NewGraphSnapshotStore(0)
+	store.RecordSnapshot(deltaTestGraph{
+		customers: map[int64]string{1: "Ada", 2: "Grace", 3: "Linus"},
+		orders:    map[int64]int64{10: 1, 11: 2},
+	}.build(t), ports.GraphSnapshotMetadata{})
+	store.RecordSnapshot(deltaTestGraph{
+		customers: map[int64]string{1: "Ada Lovelace", 2: "Grace"},
+		orders:    map[int64]int64{10: 1, 11: 2, 12: 2},
+	}.build(t), ports.GraphSnapshotMetadata{})
+
+	diff, err := store.Diff(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.From.ID)
+	assert.Equal(t, 2, diff.To.ID)
+	assert.Equal(t, diff.From.Version, diff.Changes.Since)
+	assert.Equal(t, diff.To.Version, diff.Changes.Version)
+
+	require.Len(t, diff.Changes.AddedNodes, 1)
+	assert.Equal(t, "Order", diff.Changes.AddedNodes[0].Label)
+	require.Len(t, diff.Changes.ChangedNodes, 1)
+	assert.Equal(t, "Ada Lovelace", diff.Changes.ChangedNodes[0].Properties["name"])
+	assert.Len(t, diff.Changes.RemovedNodes, 1)
+	assert.Len(t, diff.Changes.AddedRelationships, 1)
+	assert.Empty(t, diff.Changes.RemovedRelationships)
+
+	// Comparing the other way round swaps additions and removals
+	reverse, err := store.Diff(2, 1)
+	require.NoError(t, err)
+	assert.Len(t, reverse.Changes.AddedNodes, 1)
+	assert.Len(t, reverse.Changes.RemovedNodes, 1)
+	assert.Len(t, reverse.Changes.RemovedRelationships, 1)
+
+	previous, err := store.Previous(2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, previous.ID)
+	_, err = store.Previous(1)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestGraphSnapshotsKeepRetention(t *testing.T) {
+	store := NewGraphSnapshotStore(2)
+	for i := 0; i < 3; i++ {
+		store.RecordSnapshot(deltaTestGraph{}.build(t), ports.GraphSnapshotMetadata{})
+	}
+
+	snapshots := store.List()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, 2, snapshots[0].ID)
+	assert.Equal(t, 3, snapshots[1].ID)
+	_, err := store.Diff(1, 3)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
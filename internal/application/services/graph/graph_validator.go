@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	relationshipEndpointsQuery = `MATCH (a)-[r]->(b)
+RETURN id(r) AS id, type(r) AS type,
+       labels(a) AS source_labels, a.` + nodeIdentityProperty + ` AS source_key,
+       labels(b) AS target_labels, b.` + nodeIdentityProperty + ` AS target_key`
+
+	deleteRelationshipsQuery = `MATCH ()-[r]->() WHERE id(r) IN $ids DELETE r`
+)
+
+// DanglingRelationship is a stored relationship attached to a node no node rule produced:
+// one without an identity key, or whose labels match no node rule. Neo4j never stores a
+// relationship without both endpoints; a relationship row whose source or target node was not
+// found is skipped at write time and counted in SkippedRelationships instead.
+type DanglingRelationship struct {
+	ID            int64    `json:"id"`
+	Type          string   `json:"type"`
+	SourceLabels  []string `json:"source_labels"`
+	SourceKey     any      `json:"source_key,omitempty"`
+	TargetLabels  []string `json:"target_labels"`
+	TargetKey     any      `json:"target_key,omitempty"`
+	MissingSource bool     `json:"missing_source"`
+	MissingTarget bool     `json:"missing_target"`
+}
+
+// GraphValidationReport summarizes a validation pass over the stored graph
+type GraphValidationReport struct {
+	RelationshipsChecked int                    `json:"relationships_checked"`
+	DanglingCount        int                    `json:"dangling_count"`
+	DanglingByType       map[string]int         `json:"dangling_by_type"`
+	Dangling             []DanglingRelationship `json:"dangling"`
+	Pruned               int                    `json:"pruned"`
+	SkippedRelationships int                    `json:"skipped_relationships"`
+}
+
+// GraphValidator checks the stored graph for relationships whose endpoints were not
+// produced by the transform rules
+type GraphValidator struct {
+	neo4jPort     ports.Neo4jPort
+	ruleRepo      ports.TransformRuleRepository
+	writeProgress func() ports.GraphWriteProgress
+}
+
+// NewGraphValidator creates a new graph validator
+func NewGraphValidator(neo4jPort ports.Neo4jPort, ruleRepo ports.TransformRuleRepository) *GraphValidator {
+	return &GraphValidator{
+		neo4jPort: neo4jPort,
+		ruleRepo:  ruleRepo,
+	}
+}
+
+// SetWriteProgress reports the relationships the last transform skipped for a missing
+// endpoint along with each validation
+func (v *GraphValidator) SetWriteProgress(writeProgress func() ports.GraphWriteProgress) {
+	v.writeProgress = writeProgress
+}
+
+// Validate reports relationships pointing at a node that has no identity key or whose
+// labels match no node rule. With prune set, those relationships are deleted.
+func (v *GraphValidator) Validate(ctx context.Context, prune bool) (*GraphValidationReport, error) {
+	nodeTypes, err := v.nodeTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := v.neo4jPort.ExecuteQuery(relationshipEndpointsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relationships: %w", err)
+	}
+
+	report := &GraphValidationReport{
+		RelationshipsChecked: len(rows),
+		DanglingByType:       make(map[string]int),
+		Dangling:             make([]DanglingRelationship, 0),
+	}
+	for _, row := range rows {
+		rel := DanglingRelationship{
+			Type:         fmt.Sprint(row["type"]),
+			SourceLabels: toStrings(row["source_labels"]),
+			SourceKey:    row["source_key"],
+			TargetLabels: toStrings(row["target_labels"]),
+			TargetKey:    row["target_key"],
+		}
+		if id, ok := row["id"].(int64); ok {
+			rel.ID = id
+		}
+		rel.MissingSource = !isTransformedNode(rel.SourceLabels, rel.SourceKey, nodeTypes)
+		rel.MissingTarget = !isTransformedNode(rel.TargetLabels, rel.TargetKey, nodeTypes)
+
+		if rel.MissingSource || rel.MissingTarget {
+			report.Dangling = append(report.Dangling, rel)
+			report.DanglingByType[rel.Type]++
+		}
+	}
+	report.DanglingCount = len(report.Dangling)
+	if v.writeProgress != nil {
+		report.SkippedRelationships = v.writeProgress().RelationshipsSkipped
+	}
+
+	if report.DanglingCount > 0 {
+		logrus.Warnf("Graph validation found %d dangling relationships out of %d", report.DanglingCount, report.RelationshipsChecked)
+	}
+	if report.SkippedRelationships > 0 {
+		logrus.Warnf("The last transform skipped %d relationships whose source or target node was not found", report.SkippedRelationships)
+	}
+
+	if prune && report.DanglingCount > 0 {
+		ids := make([]int64, 0, report.DanglingCount)
+		for _, rel := range report.Dangling {
+			ids = append(ids, rel.ID)
+		}
+		if _, err := v.neo4jPort.ExecuteQuery(deleteRelationshipsQuery, map[string]interface{}{"ids": ids}); err != nil {
+			return report, fmt.Errorf("failed to prune dangling relationships: %w", err)
+		}
+		report.Pruned = len(ids)
+		logrus.Infof("Pruned %d dangling relationships", report.Pruned)
+	}
+
+	return report, nil
+}
+
+// nodeTypes returns the labels produced by node rules
+func (v *GraphValidator) nodeTypes(ctx context.Context) (map[string]bool, error) {
+	rules, err := v.ruleRepo.GetAllRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transform rules: %w", err)
+	}
+
+	types := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Rule.RuleType != transform.NodeRule {
+			continue
+		}
+		for _, label := range rule.Rule.NodeLabels() {
+			types[label] = true
+		}
+	}
+	return types, nil
+}
+
+// isTransformedNode reports whether a node carries an identity key and, when node rules are
+// known, one of their labels
+func isTransformedNode(labels []string, key any, nodeTypes map[string]bool) bool {
+	if key == nil {
+		return false
+	}
+	if len(nodeTypes) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		if nodeTypes[label] {
+			return true
+		}
+	}
+	return false
+}
+
+func toStrings(value any) []string {
+	var result []string
+	switch v := value.(type) {
+	case []string:
+		result = append(result, v...)
+	case []any:
+		for _, item := range v {
+			result = append(result, fmt.Sprint(item))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedGraphPort serves relationship endpoints from memory and applies deletes to them
+type storedGraphPort struct {
+	recordingNeo4jPort
+	relationships []map[string]interface{}
+}
+
+func (p *storedGraphPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	switch query {
+	case relationshipEndpointsQuery:
+		return p.relationships, nil
+	case deleteRelationshipsQuery:
+		remove := make(map[int64]bool)
+		for _, id := range params["ids"].([]int64) {
+			remove[id] = true
+		}
+		kept := p.relationships[:0]
+		for _, rel := range p.relationships {
+			if !remove[rel["id"].(int64)] {
+				kept = append(kept, rel)
+			}
+		}
+		p.relationships = kept
+	}
+	return nil, nil
+}
+
+func storedRelationship(id int64, relType, sourceLabel string, sourceKey any, targetLabel string, targetKey any) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            id,
+		"type":          relType,
+		"source_labels": []interface{}{sourceLabel},
+		"source_key":    sourceKey,
+		"target_labels": []interface{}{targetLabel},
+		"target_key":    targetKey,
+	}
+}
+
+func newDanglingGraph() *storedGraphPort {
+	return &storedGraphPort{relationships: []map[string]interface{}{
+		storedRelationship(1, "MEMBER_OF", "User", int64(1), "Team", int64(10)),
+		storedRelationship(2, "MEMBER_OF", "User", int64(2), "Team", nil),    // team node was never created
+		storedRelationship(3, "OWNS", "Legacy", int64(7), "Team", int64(10)), // left over from a removed rule
+	}}
+}
+
+func TestGraphValidatorDetectsDanglingRelationships(t *testing.T) {
+	port := newDanglingGraph()
+	report, err := NewGraphValidator(port, newAdvisorRules()).Validate(context.Background(), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.RelationshipsChecked)
+	assert.Equal(t, 2, report.DanglingCount)
+	assert.Equal(t, map[string]int{"MEMBER_OF": 1, "OWNS": 1}, report.DanglingByType)
+	require.Len(t, report.Dangling, 2)
+
+	assert.Equal(t, int64(2), report.Dangling[0].ID)
+	assert.False(t, report.Dangling[0].MissingSource)
+	assert.True(t, report.Dangling[0].MissingTarget)
+
+	assert.Equal(t, int64(3), report.Dangling[1].ID)
+	assert.True(t, report.Dangling[1].MissingSource)
+	assert.False(t, report.Dangling[1].MissingTarget)
+
+	assert.Zero(t, report.Pruned)
+	assert.Len(t, port.relationships, 3, "validation alone does not modify the graph")
+}
+
+func TestGraphValidatorPrunesDanglingRelationships(t *testing.T) {
+	port := newDanglingGraph()
+	validator := NewGraphValidator(port, newAdvisorRules())
+
+	report, err := validator.Validate(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Pruned)
+	require.Len(t, port.relationships, 1)
+	assert.Equal(t, int64(1), port.relationships[0]["id"])
+
+	report, err = validator.Validate(context.Background(), false)
+	require.NoError(t, err)
+	assert.Zero(t, report.DanglingCount)
+}
+
+func TestGraphValidatorReportsSkippedRelationships(t *testing.T) {
+	validator := NewGraphValidator(newDanglingGraph(), newAdvisorRules())
+	validator.SetWriteProgress(func() ports.GraphWriteProgress {
+		return ports.GraphWriteProgress{RelationshipsWritten: 3, RelationshipsSkipped: 4}
+	})
+
+	report, err := validator.Validate(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.SkippedRelationships)
+	assert.Equal(t, 2, report.DanglingCount, "skipped rows were never stored and are not dangling")
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/ports"
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+)
+
+const (
+	// FullGraphView is the built-in view of every node and relationship
+	FullGraphView = "full"
+
+	fullGraphQuery = "MATCH (n)-[r]->(m) RETURN n, r, m"
+)
+
+// ErrUnknownView is returned when a requested view is not configured
+var ErrUnknownView = errors.New("unknown graph view")
+
+// GraphView is a named Cypher query with display options for the visualization
+type GraphView struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Cypher      string         `json:"cypher"`
+	Display     map[string]any `json:"display,omitempty"`
+	Default     bool           `json:"default"`
+}
+
+// GraphViewService serves the subgraphs of the configured views
+type GraphViewService struct {
+	neo4jPort   ports.Neo4jPort
+	views       []GraphView
+	defaultView string
+}
+
+// NewGraphViewService creates a view service. The built-in "full" view is always available
+// unless a configured view of the same name replaces it; defaultView must name a view.
+func NewGraphViewService(neo4jPort ports.Neo4jPort, views []GraphView, defaultView string) (*GraphViewService, error) {
+	all := []GraphView{{Name: FullGraphView, Description: "All nodes and relationships", Cypher: fullGraphQuery}}
+	seen := map[string]bool{}
+	for _, view := range views {
+		if view.Name == "" {
+			return nil, fmt.Errorf("graph view without a name")
+		}
+		if strings.TrimSpace(view.Cypher) == "" {
+			return nil, fmt.Errorf("graph view %q has no cypher query", view.Name)
+		}
+		if seen[view.Name] {
+			return nil, fmt.Errorf("duplicate graph view %q", view.Name)
+		}
+		seen[view.Name] = true
+
+		if view.Name == FullGraphView {
+			all[0] = view
+		} else {
+			all = append(all, view)
+		}
+	}
+
+	if defaultView == "" {
+		defaultView = FullGraphView
+	}
+	found := false
+	for i := range all {
+		all[i].Default = all[i].Name == defaultView
+		found = found || all[i].Default
+	}
+	if !found {
+		return nil, fmt.Errorf("default graph view %q is not defined", defaultView)
+	}
+
+	return &GraphViewService{neo4jPort: neo4jPort, views: all, defaultView: defaultView}, nil
+}
+
+// Views returns the available views, the built-in full view first
+func (s *GraphViewService) Views() []GraphView {
+	return append([]GraphView(nil), s.views...)
+}
+
+// View returns the named view, or the default view when name is empty
+func (s *GraphViewService) View(name string) (GraphView, error) {
+	if name == "" {
+		name = s.defaultView
+	}
+	for _, view := range s.views {
+		if view.Name == name {
+			return view, nil
+		}
+	}
+	return GraphView{}, fmt.Errorf("%w: %s", ErrUnknownView, name)
+}
+
+// Load runs the named view's Cypher and returns its subgraph. The unmodified full view is
+// exported as a whole so nodes without relationships are included.
+func (s *GraphViewService) Load(name string) (*graphagg.GraphAggregate, GraphView, error) {
+	view, err := s.View(name)
+	if err != nil {
+		return nil, view, err
+	}
+
+	if view.Cypher == fullGraphQuery {
+		exported, err := s.neo4jPort.ExportGraph(view.Cypher)
+		if err != nil {
+			return nil, view, fmt.Errorf("failed to export graph: %w", err)
+		}
+		g, ok := exported.(*graphagg.GraphAggregate)
+		if !ok {
+			return nil, view, fmt.Errorf("unexpected graph type %T", exported)
+		}
+		return g, view, nil
+	}
+
+	reader, ok := s.neo4jPort.(ports.GraphQueryReader)
+	if !ok {
+		return nil, view, fmt.Errorf("neo4j port cannot run graph view queries")
+	}
+	g, err := reader.QueryGraph(view.Cypher, nil)
+	if err != nil {
+		return nil, view, fmt.Errorf("failed to run graph view %q: %w", view.Name, err)
+	}
+	return g, view, nil
+}
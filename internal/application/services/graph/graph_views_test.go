@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// viewGraphPort returns a prepared subgraph per Cypher query and records what was run
+type viewGraphPort struct {
+	recordingNeo4jPort
+	subgraphs map[string]*graphagg.GraphAggregate
+	exported  []string
+	queried   []string
+}
+
+func (p *viewGraphPort) ExportGraph(query string) (any, error) {
+	p.exported = append(p.exported, query)
+	return p.subgraphs[query], nil
+}
+
+func (p *viewGraphPort) QueryGraph(query string, params map[string]any) (*graphagg.GraphAggregate, error) {
+	p.queried = append(p.queried, query)
+	return p.subgraphs[query], nil
+}
+
+const ordersViewQuery = "MATCH (c:Customer)-[r:PLACED]->(o:Order) RETURN c, r, o"
+
+func newViewGraphPort(t *testing.T) *viewGraphPort {
+	full := graphagg.NewGraphAggregate("")
+	require.NoError(t, full.AddNode("Customer", map[string]any{"id": "1", "name": "Ada"}))
+	require.NoError(t, full.AddNode("Order", map[string]any{"id": "100", "name": "Order 100"}))
+	require.NoError(t, full.AddNode("Product", map[string]any{"id": "7", "name": "Lamp"}))
+
+	orders := graphagg.NewGraphAggregate("")
+	require.NoError(t, orders.AddNode("Customer", map[string]any{"id": "1", "name": "Ada"}))
+	require.NoError(t, orders.AddNode("Order", map[string]any{"id": "100", "name": "Order 100"}))
+	require.NoError(t, orders.AddDirectRelationship("PLACED", "1", "100", nil))
+
+	return &viewGraphPort{subgraphs: map[string]*graphagg.GraphAggregate{
+		fullGraphQuery:  full,
+		ordersViewQuery: orders,
+	}}
+}
+
+func ordersOnlyView() GraphView {
+	return GraphView{
+		Name:        "orders",
+		Description: "Orders only",
+		Cypher:      ordersViewQuery,
+		Display:     map[string]any{"layout": "hierarchical"},
+	}
+}
+
+func TestGraphViewServiceRunsSelectedViewCypher(t *testing.T) {
+	port := newViewGraphPort(t)
+	service, err := NewGraphViewService(port, []GraphView{ordersOnlyView()}, "")
+	require.NoError(t, err)
+
+	g, view, err := service.Load("orders")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{ordersViewQuery}, port.queried)
+	assert.Empty(t, port.exported)
+	assert.Equal(t, "orders", view.Name)
+	assert.Equal(t, "hierarchical", view.Display["layout"])
+
+	require.Len(t, g.GetNodes(), 2)
+	for _, node := range g.GetNodes() {
+		assert.NotEqual(t, "Product", node.Type)
+	}
+	require.Len(t, g.GetRelationships(), 1)
+	assert.Equal(t, "PLACED", g.GetRelationships()[0].Type)
+}
+
+func TestGraphViewServiceDefaultsToFullGraph(t *testing.T) {
+	port := newViewGraphPort(t)
+	service, err := NewGraphViewService(port, []GraphView{ordersOnlyView()}, "")
+	require.NoError(t, err)
+
+	g, view, err := service.Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, FullGraphView, view.Name)
+	assert.Equal(t, []string{fullGraphQuery}, port.exported)
+	assert.Len(t, g.GetNodes(), 3)
+
+	views := service.Views()
+	require.Len(t, views, 2)
+	assert.Equal(t, FullGraphView, views[0].Name)
+	assert.True(t, views[0].Default)
+	assert.False(t, views[1].Default)
+}
+
+func TestGraphViewServiceConfiguredDefaultView(t *testing.T) {
+	port := newViewGraphPort(t)
+	service, err := NewGraphViewService(port, []GraphView{ordersOnlyView()}, "orders")
+	require.NoError(t, err)
+
+	_, view, err := service.Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "orders", view.Name)
+	assert.Equal(t, []string{ordersViewQuery}, port.queried)
+}
+
+func TestGraphViewServiceUnknownView(t *testing.T) {
+	service, err := NewGraphViewService(newViewGraphPort(t), nil, "")
+	require.NoError(t, err)
+
+	_, _, err = service.Load("missing")
+	assert.ErrorIs(t, err, ErrUnknownView)
+}
+
+func TestNewGraphViewServiceRejectsInvalidViews(t *testing.T) {
+	port := newViewGraphPort(t)
+
+	_, err := NewGraphViewService(port, []GraphView{{Name: "empty"}}, "")
+	assert.ErrorContains(t, err, "no cypher")
+
+	_, err = NewGraphViewService(port, []GraphView{ordersOnlyView(), ordersOnlyView()}, "")
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = NewGraphViewService(port, nil, "orders")
+	assert.ErrorContains(t, err, "not defined")
+}
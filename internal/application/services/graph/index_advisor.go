@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reasons reported for an index suggestion
+const (
+	IndexReasonIdentity     = "node identity key"
+	IndexReasonRelationship = "relationship endpoint lookup"
+	IndexReasonDisplayName  = "display name lookup"
+	IndexReasonMergeKey     = "node merge key"
+)
+
+// nodeIdentityProperty is the property every imported node is keyed and matched on
+const nodeIdentityProperty = "id"
+
+var indexNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// IndexSuggestion is a single recommended Neo4j index. Properties lists the indexed
+// properties in order; Property joins them with "," for a composite index.
+type IndexSuggestion struct {
+	Label      string   `json:"label"`
+	Property   string   `json:"property"`
+	Properties []string `json:"properties"`
+	Name       string   `json:"name"`
+	Statement  string   `json:"statement"`
+	Reasons    []string `json:"reasons"`
+}
+
+// IndexApplyResult reports the outcome of creating a suggested index
+type IndexApplyResult struct {
+	Name      string `json:"name"`
+	Statement string `json:"statement"`
+	Applied   bool   `json:"applied"`
+	Error     string `json:"error,omitempty"`
+}
+
+// IndexAdvisor derives Neo4j index suggestions from the transform rules that shaped the graph
+type IndexAdvisor struct {
+	neo4jPort ports.Neo4jPort
+	ruleRepo  ports.TransformRuleRepository
+}
+
+// NewIndexAdvisor creates a new index advisor
+func NewIndexAdvisor(neo4jPort ports.Neo4jPort, ruleRepo ports.TransformRuleRepository) *IndexAdvisor {
+	return &IndexAdvisor{
+		neo4jPort: neo4jPort,
+		ruleRepo:  ruleRepo,
+	}
+}
+
+// Suggest returns index suggestions for every node type produced by the transform rules.
+// Each node type gets an index on its identity key, plus indexes on properties used to
+// resolve relationship endpoints and on mapped display names. Node types of rules with
+// key_columns, which are merged on their key properties, get a composite index on them.
+func (a *IndexAdvisor) Suggest(ctx context.Context) ([]IndexSuggestion, error) {
+	rules, err := a.ruleRepo.GetAllRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transform rules: %w", err)
+	}
+
+	suggestions := make(map[string]*IndexSuggestion)
+	add := func(label, reason string, properties ...string) {
+		if label == "" || len(properties) == 0 || slices.Contains(properties, "") {
+			return
+		}
+		key := label + "." + strings.Join(properties, ",")
+		suggestion, exists := suggestions[key]
+		if !exists {
+			suggestion = newIndexSuggestion(label, properties)
+			suggestions[key] = suggestion
+		}
+		for _, existing := range suggestion.Reasons {
+			if existing == reason {
+				return
+			}
+		}
+		suggestion.Reasons = append(suggestion.Reasons, reason)
+	}
+
+	for _, rule := range rules {
+		switch rule.Rule.RuleType {
+		case transform.NodeRule:
+			mergeKeys := mergeKeyProperties(rule.Rule)
+			for _, label := range rule.Rule.NodeLabels() {
+				add(label, IndexReasonIdentity, nodeIdentityProperty)
+				add(label, IndexReasonMergeKey, mergeKeys...)
+				for _, targetField := range rule.Rule.FieldMappings {
+					if targetField == "name" {
+						add(label, IndexReasonDisplayName, targetField)
+					}
+				}
+			}
+		case transform.RelationshipRule:
+			if rule.Rule.SourceNode != nil {
+				add(rule.Rule.SourceNode.Type, IndexReasonRelationship, rule.Rule.SourceNode.TargetField)
+			}
+			if rule.Rule.TargetNode != nil {
+				add(rule.Rule.TargetNode.Type, IndexReasonRelationship, rule.Rule.TargetNode.TargetField)
+			}
+		}
+	}
+
+	result := make([]IndexSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result = append(result, *suggestion)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Label != result[j].Label {
+			return result[i].Label < result[j].Label
+		}
+		return result[i].Property < result[j].Property
+	})
+
+	return result, nil
+}
+
+// Apply creates the given indexes in Neo4j. Statements are idempotent, so applying
+// the same suggestions twice is harmless; a failing statement does not stop the rest.
+func (a *IndexAdvisor) Apply(ctx context.Context, suggestions []IndexSuggestion) []IndexApplyResult {
+	results := make([]IndexApplyResult, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result := IndexApplyResult{Name: suggestion.Name, Statement: suggestion.Statement}
+
+		if ctx.Err() != nil {
+			result.Error = ctx.Err().Error()
+			results = append(results, result)
+			continue
+		}
+
+		if _, err := a.neo4jPort.ExecuteQuery(suggestion.Statement, nil); err != nil {
+			logrus.Warnf("Failed to create index %s: %v", suggestion.Name, err)
+			result.Error = err.Error()
+		} else {
+			logrus.Infof("Created index %s", suggestion.Name)
+			result.Applied = true
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// mergeKeyProperties returns the properties the nodes of a rule with key_columns are merged
+// on: each key column under its mapped name, or its own name when it is not mapped
+func mergeKeyProperties(rule transform.TransformRule) []string {
+	properties := make([]string, len(rule.KeyColumns))
+	for i, column := range rule.KeyColumns {
+		properties[i] = column
+		if mapped, ok := rule.FieldMappings[column]; ok {
+			properties[i] = mapped
+		}
+	}
+	return properties
+}
+
+func newIndexSuggestion(label string, properties []string) *IndexSuggestion {
+	name := strings.ToLower(indexNameSanitizer.ReplaceAllString(label+"_"+strings.Join(properties, "_"), "_")) + "_idx"
+	indexed := make([]string, len(properties))
+	for i, property := range properties {
+		indexed[i] = "n." + quoteCypherIdentifier(property)
+	}
+	return &IndexSuggestion{
+		Label:      label,
+		Property:   strings.Join(properties, ","),
+		Properties: properties,
+		Name:       name,
+		Statement: fmt.Sprintf("CREATE INDEX %s IF NOT EXISTS FOR (n:%s) ON (%s)",
+			name, quoteCypherIdentifier(label), strings.Join(indexed, ", ")),
+	}
+}
+
+func quoteCypherIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+	transformagg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+type recordingNeo4jPort struct {
+	statements []string
+	failOn     string
+}
+
+func (p *recordingNeo4jPort) StoreGraph(g *graphagg.GraphAggregate) error { return nil }
+func (p *recordingNeo4jPort) SearchNodes(criteria string) ([]*graphagg.GraphAggregate, error) {
+	return nil, nil
+}
+func (p *recordingNeo4jPort) ExportGraph(query string) (any, error) { return nil, nil }
+func (p *recordingNeo4jPort) FetchNodes(nodeType string) ([]map[string]any, error) {
+	return nil, nil
+}
+func (p *recordingNeo4jPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if p.failOn != "" && strings.Contains(query, p.failOn) {
+		return nil, errors.New("index already exists with different options")
+	}
+	p.statements = append(p.statements, query)
+	return nil, nil
+}
+func (p *recordingNeo4jPort) Close() error { return nil }
+
+type staticRuleRepository struct {
+	rules []*transformagg.RuleAggregate
+}
+
+func (r *staticRuleRepository) GetAllRules(ctx context.Context) ([]*transformagg.RuleAggregate, error) {
+	return r.rules, nil
+}
+func (r *staticRuleRepository) SaveRule(ctx context.Context, rule *transformagg.RuleAggregate) error {
+	return nil
+}
+func (r *staticRuleRepository) DeleteRule(ctx context.Context, ruleID string) error { return nil }
+func (r *staticRuleRepository) UpdateRulePriority(ctx context.Context, ruleID string, priority int) error {
+	return nil
+}
+
+func newAdvisorRules() *staticRuleRepository {
+	return &staticRuleRepository{rules: []*transformagg.RuleAggregate{
+		{Rule: transform.TransformRule{
+			Name:          "users",
+			RuleType:      transform.NodeRule,
+			TargetType:    "User",
+			FieldMappings: map[string]string{"id": "id", "full_name": "name", "email": "email"},
+		}},
+		{Rule: transform.TransformRule{
+			Name:          "teams",
+			RuleType:      transform.NodeRule,
+			TargetType:    "Team",
+			FieldMappings: map[string]string{"id": "id", "team_code": "code"},
+		}},
+		{Rule: transform.TransformRule{
+			Name:         "membership",
+			RuleType:     transform.RelationshipRule,
+			RelationType: "MEMBER_OF",
+			SourceNode:   &transform.NodeMapping{Type: "User", Key: "user_id", TargetField: "id"},
+			TargetNode:   &transform.NodeMapping{Type: "Team", Key: "team_code", TargetField: "code"},
+		}},
+	}}
+}
+
+func TestIndexAdvisor_SuggestCoversEveryNodeKey(t *testing.T) {
+	advisor := NewIndexAdvisor(&recordingNeo4jPort{}, newAdvisorRules())
+
+	suggestions, err := advisor.Suggest(context.Background())
+	require.NoError(t, err)
+
+	byTarget := make(map[string]IndexSuggestion)
+	for _, suggestion := range suggestions {
+		byTarget[suggestion.Label+"."+suggestion.Property] = suggestion
+	}
+
+	for _, label := range []string{"User", "Team"} {
+		suggestion, ok := byTarget[label+".id"]
+		require.True(t, ok, "missing identity index for %s", label)
+		assert.Contains(t, suggestion.Reasons, IndexReasonIdentity)
+		assert.Equal(t, "CREATE INDEX "+strings.ToLower(label)+"_id_idx IF NOT EXISTS FOR (n:`"+label+"`) ON (n.`id`)", suggestion.Statement)
+	}
+
+	// The User identity key is also a relationship endpoint, so it is suggested once with both reasons
+	assert.ElementsMatch(t, []string{IndexReasonIdentity, IndexReasonRelationship}, byTarget["User.id"].Reasons)
+	assert.Contains(t, byTarget, "Team.code")
+	assert.Contains(t, byTarget, "User.name")
+	assert.NotContains(t, byTarget, "User.email")
+	assert.Len(t, suggestions, 4)
+}
+
+func TestIndexAdvisor_SuggestCompositeIndexForKeyColumns(t *testing.T) {
+	rules := &staticRuleRepository{rules: []*transformagg.RuleAggregate{
+		{Rule: transform.TransformRule{
+			Name:          "order_lines",
+			RuleType:      transform.NodeRule,
+			TargetType:    "OrderLine",
+			KeyColumns:    []string{"order_id", "line_no"},
+			FieldMappings: map[string]string{"order_id": "orderId", "quantity": "quantity"},
+		}},
+	}}
+	advisor := NewIndexAdvisor(&recordingNeo4jPort{}, rules)
+
+	suggestions, err := advisor.Suggest(context.Background())
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+
+	composite := suggestions[1]
+	assert.Equal(t, "OrderLine", composite.Label)
+	assert.Equal(t, []string{"orderId", "line_no"}, composite.Properties, "key columns are indexed under their mapped names")
+	assert.Equal(t, []string{IndexReasonMergeKey}, composite.Reasons)
+	assert.Equal(t, "CREATE INDEX orderline_orderid_line_no_idx IF NOT EXISTS FOR (n:`OrderLine`) ON (n.`orderId`, n.`line_no`)", composite.Statement)
+
+	assert.Equal(t, []string{nodeIdentityProperty}, suggestions[0].Properties, "relationships still match the node by id")
+}
+
+func TestIndexAdvisor_ApplyContinuesAfterFailure(t *testing.T) {
+	neo4jPort := &recordingNeo4jPort{failOn: "team_code_idx"}
+	advisor := NewIndexAdvisor(neo4jPort, newAdvisorRules())
+
+	suggestions, err := advisor.Suggest(context.Background())
+	require.NoError(t, err)
+
+	results := advisor.Apply(context.Background(), suggestions)
+	require.Len(t, results, len(suggestions))
+
+	failed := 0
+	for _, result := range results {
+		if !result.Applied {
+			failed++
+			assert.Equal(t, "team_code_idx", result.Name)
+			assert.NotEmpty(t, result.Error)
+		}
+	}
+	assert.Equal(t, 1, failed)
+	assert.Len(t, neo4jPort.statements, len(suggestions)-1)
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/ports"
+)
+
+const (
+	// DefaultNodeSampleSize is how many related nodes are returned per relationship type
+	DefaultNodeSampleSize = 5
+	// MaxNodeSampleSize caps the sample size a client may request
+	MaxNodeSampleSize = 50
+	// maxRelationshipGroups caps how many relationship type/direction groups are summarized
+	maxRelationshipGroups = 50
+)
+
+const (
+	// nodeLookupQuery is formatted with the quoted label so the lookup can use a :Label(id) index
+	nodeLookupQuery = "MATCH (n:%s) WHERE n.id IN $keys " +
+		"RETURN id(n) AS node_id, labels(n) AS labels, properties(n) AS properties LIMIT 1"
+	relationshipCountsQuery = "MATCH (n) WHERE id(n) = $node_id MATCH (n)-[r]-() " +
+		"RETURN type(r) AS type, startNode(r) = n AS outgoing, count(r) AS count " +
+		"ORDER BY count DESC, type LIMIT $max_groups"
+	// outgoingSamplesQuery and incomingSamplesQuery are formatted with the quoted relationship type
+	outgoingSamplesQuery = "MATCH (n)-[r:%s]->(m) WHERE id(n) = $node_id " +
+		"RETURN labels(m) AS labels, properties(m) AS properties, properties(r) AS relationship_properties LIMIT $limit"
+	incomingSamplesQuery = "MATCH (n)<-[r:%s]-(m) WHERE id(n) = $node_id " +
+		"RETURN labels(m) AS labels, properties(m) AS properties, properties(r) AS relationship_properties LIMIT $limit"
+)
+
+var (
+	// ErrNodeNotFound is returned when no node has the requested graph id
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrInvalidNodeID is returned for ids that are not of the form <Label>_<id>
+	ErrInvalidNodeID = errors.New("invalid node id")
+)
+
+// RelatedNode is a node at the other end of a sampled relationship
+type RelatedNode struct {
+	ID                     string         `json:"id"`
+	Label                  string         `json:"label"`
+	Properties             map[string]any `json:"properties"`
+	RelationshipProperties map[string]any `json:"relationship_properties,omitempty"`
+}
+
+// RelationshipSummary counts the relationships of one type in one direction and samples
+// the nodes they lead to
+type RelationshipSummary struct {
+	Type    string        `json:"type"`
+	Count   int64         `json:"count"`
+	Samples []RelatedNode `json:"samples"`
+}
+
+// NodeDetails is a node with its relationships grouped by direction and type
+type NodeDetails struct {
+	ID         string                `json:"id"`
+	Label      string                `json:"label"`
+	Labels     []string              `json:"labels"`
+	Properties map[string]any        `json:"properties"`
+	Outgoing   []RelationshipSummary `json:"outgoing"`
+	Incoming   []RelationshipSummary `json:"incoming"`
+}
+
+// NodeDetailsService looks up a single node and summarizes its connections
+type NodeDetailsService struct {
+	neo4jPort ports.Neo4jPort
+}
+
+// NewNodeDetailsService creates a node details service
+func NewNodeDetailsService(neo4jPort ports.Neo4jPort) *NodeDetailsService {
+	return &NodeDetailsService{neo4jPort: neo4jPort}
+}
+
+// NodeDetails returns the node with graph id nodeID, as used by /api/graph (<Label>_<id>),
+// with relationship counts per type and direction and up to sampleSize related nodes per
+// group. Every query is bounded: the lookup and the counts by LIMIT, the samples by
+// sampleSize, which is clamped to MaxNodeSampleSize.
+func (s *NodeDetailsService) NodeDetails(nodeID string, sampleSize int) (*NodeDetails, error) {
+	candidates := nodeIDCandidates(nodeID)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %q (expected <Label>_<id>)", ErrInvalidNodeID, nodeID)
+	}
+	if sampleSize <= 0 {
+		sampleSize = DefaultNodeSampleSize
+	}
+	if sampleSize > MaxNodeSampleSize {
+		sampleSize = MaxNodeSampleSize
+	}
+
+	var found map[string]any
+	for _, candidate := range candidates {
+		rows, err := s.neo4jPort.ExecuteQuery(fmt.Sprintf(nodeLookupQuery, quoteCypherIdentifier(candidate.label)),
+			map[string]any{"keys": candidate.keys})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up node %s: %w", nodeID, err)
+		}
+		if len(rows) > 0 {
+			found = rows[0]
+			break
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeID)
+	}
+
+	internalID := found["node_id"]
+	details := &NodeDetails{
+		ID:         nodeID,
+		Labels:     stringValues(found["labels"]),
+		Properties: mapValue(found["properties"]),
+		Outgoing:   []RelationshipSummary{},
+		Incoming:   []RelationshipSummary{},
+	}
+	if len(details.Labels) > 0 {
+		details.Label = details.Labels[0]
+	}
+
+	groups, err := s.neo4jPort.ExecuteQuery(relationshipCountsQuery,
+		map[string]any{"node_id": internalID, "max_groups": maxRelationshipGroups})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count relationships of node %s: %w", nodeID, err)
+	}
+
+	for _, group := range groups {
+		relType, _ := group["type"].(string)
+		outgoing, _ := group["outgoing"].(bool)
+		summary := RelationshipSummary{Type: relType, Count: int64Value(group["count"])}
+
+		query := incomingSamplesQuery
+		if outgoing {
+			query = outgoingSamplesQuery
+		}
+		rows, err := s.neo4jPort.ExecuteQuery(fmt.Sprintf(query, quoteCypherIdentifier(relType)),
+			map[string]any{"node_id": internalID, "limit": sampleSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample %s relationships of node %s: %w", relType, nodeID, err)
+		}
+		summary.Samples = relatedNodes(rows)
+
+		if outgoing {
+			details.Outgoing = append(details.Outgoing, summary)
+		} else {
+			details.Incoming = append(details.Incoming, summary)
+		}
+	}
+
+	sortRelationshipSummaries(details.Outgoing)
+	sortRelationshipSummaries(details.Incoming)
+	return details, nil
+}
+
+// nodeIDCandidate is one way to split a graph id into a label and an id property value
+type nodeIDCandidate struct {
+	label string
+	keys  []any
+}
+
+// nodeIDCandidates splits a graph id at every underscore, since both the label and the id
+// value may contain underscores. The id value is matched as a string and, when it parses, as
+// an integer, because imported ids keep their SQL type.
+func nodeIDCandidates(nodeID string) []nodeIDCandidate {
+	var candidates []nodeIDCandidate
+	for i := strings.Index(nodeID, "_"); i >= 0; {
+		label, key := nodeID[:i], nodeID[i+1:]
+		if label != "" && key != "" {
+			keys := []any{key}
+			if number, err := strconv.ParseInt(key, 10, 64); err == nil {
+				keys = append(keys, number)
+			}
+			candidates = append(candidates, nodeIDCandidate{label: label, keys: keys})
+		}
+		next := strings.Index(nodeID[i+1:], "_")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return candidates
+}
+
+func relatedNodes(rows []map[string]any) []RelatedNode {
+	nodes := make([]RelatedNode, 0, len(rows))
+	for _, row := range rows {
+		node := RelatedNode{
+			Properties:             mapValue(row["properties"]),
+			RelationshipProperties: mapValue(row["relationship_properties"]),
+		}
+		if labels := stringValues(row["labels"]); len(labels) > 0 {
+			node.Label = labels[0]
+		}
+		node.ID = fmt.Sprintf("%s_%v", node.Label, node.Properties[nodeIdentityProperty])
+		if len(node.RelationshipProperties) == 0 {
+			node.RelationshipProperties = nil
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// sortRelationshipSummaries orders the busiest relationship types first
+func sortRelationshipSummaries(summaries []RelationshipSummary) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Type < summaries[j].Type
+	})
+}
+
+func stringValues(value any) []string {
+	switch values := value.(type) {
+	case []string:
+		return values
+	case []any:
+		result := make([]string, 0, len(values))
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func mapValue(value any) map[string]any {
+	if m, ok := value.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{}
+}
+
+func int64Value(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixtureNode struct {
+	label string
+	props map[string]any
+}
+
+type fixtureEdge struct {
+	from, to int64
+	relType  string
+	props    map[string]any
+}
+
+// fixtureGraphPort answers the node details queries from an in-memory graph
+type fixtureGraphPort struct {
+	recordingNeo4jPort
+	nodes map[int64]fixtureNode
+	edges []fixtureEdge
+}
+
+// newFixtureGraph builds a small shop graph around User 1:
+// User 1 -PLACED-> Order 10, 11, 12; User 1 -FOLLOWS-> User 2; User 2 -FOLLOWS-> User 1;
+// Team t_1 -HAS_MEMBER-> User 1, User 2
+func newFixtureGraph() *fixtureGraphPort {
+	return &fixtureGraphPort{
+		nodes: map[int64]fixtureNode{
+			0: {label: "User", props: map[string]any{"id": int64(1), "name": "Ada"}},
+			1: {label: "User", props: map[string]any{"id": int64(2), "name": "Linus"}},
+			2: {label: "Order", props: map[string]any{"id": int64(10)}},
+			3: {label: "Order", props: map[string]any{"id": int64(11)}},
+			4: {label: "Order", props: map[string]any{"id": int64(12)}},
+			5: {label: "Team", props: map[string]any{"id": "t_1", "name": "Core"}},
+		},
+		edges: []fixtureEdge{
+			{from: 0, to: 2, relType: "PLACED", props: map[string]any{"channel": "web"}},
+			{from: 0, to: 3, relType: "PLACED"},
+			{from: 0, to: 4, relType: "PLACED"},
+			{from: 0, to: 1, relType: "FOLLOWS"},
+			{from: 1, to: 0, relType: "FOLLOWS"},
+			{from: 5, to: 0, relType: "HAS_MEMBER"},
+			{from: 5, to: 1, relType: "HAS_MEMBER"},
+		},
+	}
+}
+
+func (p *fixtureGraphPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	p.statements = append(p.statements, query)
+	switch {
+	case strings.HasPrefix(query, "MATCH (n:"):
+		label := backtickedIdentifier(query)
+		for _, id := range sortedNodeIDs(p.nodes) {
+			node := p.nodes[id]
+			if node.label != label {
+				continue
+			}
+			for _, key := range params["keys"].([]any) {
+				if node.props["id"] == key {
+					return []map[string]any{{"node_id": id, "labels": []any{node.label}, "properties": node.props}}, nil
+				}
+			}
+		}
+		return nil, nil
+	case query == relationshipCountsQuery:
+		node := params["node_id"].(int64)
+		counts := map[string]int64{}
+		for _, edge := range p.edges {
+			if edge.from == node {
+				counts["out:"+edge.relType]++
+			}
+			if edge.to == node {
+				counts["in:"+edge.relType]++
+			}
+		}
+		rows := []map[string]any{}
+		for key, count := range counts {
+			direction, relType, _ := strings.Cut(key, ":")
+			rows = append(rows, map[string]any{"type": relType, "outgoing": direction == "out", "count": count})
+		}
+		return rows, nil
+	default:
+		node, limit := params["node_id"].(int64), params["limit"].(int)
+		outgoing := strings.Contains(query, "]->(m)")
+		relType := backtickedIdentifier(query)
+		rows := []map[string]any{}
+		for _, edge := range p.edges {
+			if edge.relType != relType || len(rows) == limit {
+				continue
+			}
+			other := edge.to
+			if outgoing && edge.from != node || !outgoing && edge.to != node {
+				continue
+			}
+			if !outgoing {
+				other = edge.from
+			}
+			rows = append(rows, map[string]any{
+				"labels":                  []any{p.nodes[other].label},
+				"properties":              p.nodes[other].props,
+				"relationship_properties": edge.props,
+			})
+		}
+		return rows, nil
+	}
+}
+
+func backtickedIdentifier(query string) string {
+	start := strings.Index(query, "`")
+	end := strings.Index(query[start+1:], "`")
+	return query[start+1 : start+1+end]
+}
+
+func sortedNodeIDs(nodes map[int64]fixtureNode) []int64 {
+	ids := make([]int64, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func summaryCounts(summaries []RelationshipSummary) map[string]int64 {
+	counts := map[string]int64{}
+	for _, summary := range summaries {
+		counts[summary.Type] = summary.Count
+	}
+	return counts
+}
+
+func TestNodeDetailsGroupsRelationshipsByTypeAndDirection(t *testing.T) {
+	port := newFixtureGraph()
+
+	details, err := NewNodeDetailsService(port).NodeDetails("User_1", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "User", details.Label)
+	assert.Equal(t, "Ada", details.Properties["name"])
+
+	require.Len(t, details.Outgoing, 2)
+	assert.Equal(t, "PLACED", details.Outgoing[0].Type, "the busiest type comes first")
+	assert.Equal(t, map[string]int64{"PLACED": 3, "FOLLOWS": 1}, summaryCounts(details.Outgoing))
+	assert.Equal(t, map[string]int64{"FOLLOWS": 1, "HAS_MEMBER": 1}, summaryCounts(details.Incoming))
+
+	placed := details.Outgoing[0].Samples
+	require.Len(t, placed, 3)
+	assert.Equal(t, "Order_10", placed[0].ID)
+	assert.Equal(t, map[string]any{"channel": "web"}, placed[0].RelationshipProperties)
+	assert.Nil(t, placed[1].RelationshipProperties)
+
+	for _, summary := range details.Incoming {
+		if summary.Type == "HAS_MEMBER" {
+			require.Len(t, summary.Samples, 1)
+			assert.Equal(t, "Team_t_1", summary.Samples[0].ID)
+		}
+	}
+}
+
+func TestNodeDetailsBoundsSamples(t *testing.T) {
+	port := newFixtureGraph()
+
+	details, err := NewNodeDetailsService(port).NodeDetails("User_1", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), details.Outgoing[0].Count, "counts are not limited by the sample size")
+	assert.Len(t, details.Outgoing[0].Samples, 2)
+	for _, statement := range port.statements {
+		assert.Contains(t, statement, "LIMIT", "every query is bounded")
+	}
+}
+
+func TestNodeDetailsLabelAndIDWithUnderscores(t *testing.T) {
+	details, err := NewNodeDetailsService(newFixtureGraph()).NodeDetails("Team_t_1", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Core", details.Properties["name"])
+	assert.Empty(t, details.Incoming)
+	require.Len(t, details.Outgoing, 1)
+	assert.Equal(t, RelationshipSummary{Type: "HAS_MEMBER", Count: 2, Samples: []RelatedNode{
+		{ID: "User_1", Label: "User", Properties: map[string]any{"id": int64(1), "name": "Ada"}},
+		{ID: "User_2", Label: "User", Properties: map[string]any{"id": int64(2), "name": "Linus"}},
+	}}, details.Outgoing[0])
+}
+
+func TestNodeDetailsUnknownNode(t *testing.T) {
+	service := NewNodeDetailsService(newFixtureGraph())
+
+	_, err := service.NodeDetails("User_99", 0)
+	assert.True(t, errors.Is(err, ErrNodeNotFound), fmt.Sprint(err))
+
+	_, err = service.NodeDetails("User", 0)
+	assert.True(t, errors.Is(err, ErrInvalidNodeID), fmt.Sprint(err))
+}
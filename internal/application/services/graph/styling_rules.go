@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Styling rule targets
+const (
+	StyleTargetNode         = "node"
+	StyleTargetRelationship = "relationship"
+)
+
+// StylePredicate tests one property of a node or relationship. Operator is one of eq (the
+// default), ne, gt, gte, lt, lte, in, contains and exists.
+type StylePredicate struct {
+	Property string `json:"property"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// StylingRule attaches visual hints to the nodes or relationships matching all its predicates.
+// Label restricts a node rule to one label, or a relationship rule to one relationship type.
+type StylingRule struct {
+	Name   string           `json:"name"`
+	Target string           `json:"target"`
+	Label  string           `json:"label,omitempty"`
+	When   []StylePredicate `json:"when,omitempty"`
+	Style  map[string]any   `json:"style"`
+}
+
+// GraphStyler evaluates styling rules against graph elements
+type GraphStyler struct {
+	rules []StylingRule
+}
+
+// NewGraphStyler validates the rules and creates a styler applying them in order
+func NewGraphStyler(rules []StylingRule) (*GraphStyler, error) {
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("styling rule %d has no name", i+1)
+		}
+		switch rule.Target {
+		case StyleTargetNode, StyleTargetRelationship:
+		case "":
+			rules[i].Target = StyleTargetNode
+		default:
+			return nil, fmt.Errorf("styling rule %q has unknown target %q", rule.Name, rule.Target)
+		}
+		if len(rule.Style) == 0 {
+			return nil, fmt.Errorf("styling rule %q has no style", rule.Name)
+		}
+		for _, predicate := range rule.When {
+			if err := predicate.validate(); err != nil {
+				return nil, fmt.Errorf("styling rule %q: %w", rule.Name, err)
+			}
+		}
+	}
+	return &GraphStyler{rules: rules}, nil
+}
+
+// NodeStyle returns the merged style of every node rule matching the node, later rules
+// overriding earlier ones; nil when no rule matches
+func (s *GraphStyler) NodeStyle(label string, properties map[string]any) map[string]any {
+	return s.style(StyleTargetNode, label, properties)
+}
+
+// RelationshipStyle returns the merged style of every relationship rule matching the
+// relationship; nil when no rule matches
+func (s *GraphStyler) RelationshipStyle(relType string, properties map[string]any) map[string]any {
+	return s.style(StyleTargetRelationship, relType, properties)
+}
+
+func (s *GraphStyler) style(target, label string, properties map[string]any) map[string]any {
+	if s == nil {
+		return nil
+	}
+
+	var style map[string]any
+	for _, rule := range s.rules {
+		if rule.Target != target || (rule.Label != "" && rule.Label != label) || !rule.matches(properties) {
+			continue
+		}
+		if style == nil {
+			style = make(map[string]any, len(rule.Style))
+		}
+		for key, value := range rule.Style {
+			style[key] = value
+		}
+	}
+	return style
+}
+
+func (r StylingRule) matches(properties map[string]any) bool {
+	for _, predicate := range r.When {
+		if !predicate.matches(properties) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p StylePredicate) validate() error {
+	if p.Property == "" {
+		return fmt.Errorf("predicate without a property")
+	}
+	switch p.Operator {
+	case "", "eq", "ne", "contains", "exists":
+	case "gt", "gte", "lt", "lte":
+		if _, ok := toFloat(p.Value); !ok {
+			return fmt.Errorf("operator %s on %s needs a numeric value", p.Operator, p.Property)
+		}
+	case "in":
+		if _, ok := p.Value.([]any); !ok {
+			return fmt.Errorf("operator in on %s needs a list value", p.Property)
+		}
+	default:
+		return fmt.Errorf("unknown operator %q", p.Operator)
+	}
+	return nil
+}
+
+func (p StylePredicate) matches(properties map[string]any) bool {
+	actual, present := properties[p.Property]
+	present = present && actual != nil
+
+	switch p.Operator {
+	case "exists":
+		return present
+	case "ne":
+		return !present || !valuesEqual(actual, p.Value)
+	}
+	if !present {
+		return false
+	}
+
+	switch p.Operator {
+	case "", "eq":
+		return valuesEqual(actual, p.Value)
+	case "in":
+		values, _ := p.Value.([]any)
+		for _, value := range values {
+			if valuesEqual(actual, value) {
+				return true
+			}
+		}
+		return false
+	case "contains":
+		return strings.Contains(fmt.Sprint(actual), fmt.Sprint(p.Value))
+	}
+
+	left, ok := toFloat(actual)
+	if !ok {
+		return false
+	}
+	right, _ := toFloat(p.Value)
+	switch p.Operator {
+	case "gt":
+		return left > right
+	case "gte":
+		return left >= right
+	case "lt":
+		return left < right
+	case "lte":
+		return left <= right
+	}
+	return false
+}
+
+// valuesEqual compares numeric properties by value, whatever their Go type, and everything
+// else by its text, so a configured "1" matches an integer property 1
+func valuesEqual(actual, expected any) bool {
+	if _, isString := actual.(string); isString {
+		return actual == fmt.Sprint(expected)
+	}
+	if a, ok := toFloat(actual); ok {
+		if e, ok := toFloat(expected); ok {
+			return a == e
+		}
+	}
+	return fmt.Sprint(actual) == fmt.Sprint(expected)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
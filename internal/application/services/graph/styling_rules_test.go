@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphStylerNodeStyle(t *testing.T) {
+	styler, err := NewGraphStyler([]StylingRule{
+		{
+			Name:  "cancelled_orders",
+			Label: "Order",
+			When:  []StylePredicate{{Property: "status", Value: "cancelled"}},
+			Style: map[string]any{"color": "#D0021B"},
+		},
+		{
+			Name:  "large_orders",
+			Label: "Order",
+			When:  []StylePredicate{{Property: "total", Operator: "gte", Value: 1000}},
+			Style: map[string]any{"size": 40, "color": "#F5A623"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"color": "#D0021B"},
+		styler.NodeStyle("Order", map[string]any{"status": "cancelled", "total": int64(20)}))
+	assert.Nil(t, styler.NodeStyle("Order", map[string]any{"status": "paid", "total": int64(20)}))
+	assert.Nil(t, styler.NodeStyle("Customer", map[string]any{"status": "cancelled"}), "label must match")
+	assert.Nil(t, styler.NodeStyle("Order", map[string]any{"total": int64(20)}), "missing property does not match")
+
+	// Later rules override the keys they set
+	assert.Equal(t, map[string]any{"color": "#F5A623", "size": 40},
+		styler.NodeStyle("Order", map[string]any{"status": "cancelled", "total": 1500.5}))
+}
+
+func TestGraphStylerRelationshipStyle(t *testing.T) {
+	styler, err := NewGraphStyler([]StylingRule{{
+		Name:   "inactive_membership",
+		Target: StyleTargetRelationship,
+		When:   []StylePredicate{{Property: "role", Operator: "in", Value: []any{"former", "guest"}}},
+		Style:  map[string]any{"dashes": true},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"dashes": true}, styler.RelationshipStyle("MEMBER_OF", map[string]any{"role": "guest"}))
+	assert.Nil(t, styler.RelationshipStyle("MEMBER_OF", map[string]any{"role": "owner"}))
+	assert.Nil(t, styler.NodeStyle("Team", map[string]any{"role": "guest"}), "relationship rules do not style nodes")
+}
+
+func TestStylePredicateOperators(t *testing.T) {
+	properties := map[string]any{"id": int64(7), "name": "Acme Ltd", "code": "007", "deleted_at": nil}
+
+	tests := []struct {
+		predicate StylePredicate
+		want      bool
+	}{
+		{StylePredicate{Property: "id", Value: "7"}, true},
+		{StylePredicate{Property: "code", Value: "7"}, false},
+		{StylePredicate{Property: "id", Operator: "ne", Value: 8}, true},
+		{StylePredicate{Property: "missing", Operator: "ne", Value: 8}, true},
+		{StylePredicate{Property: "id", Operator: "lt", Value: 7}, false},
+		{StylePredicate{Property: "id", Operator: "lte", Value: 7}, true},
+		{StylePredicate{Property: "name", Operator: "contains", Value: "Acme"}, true},
+		{StylePredicate{Property: "name", Operator: "gt", Value: 1}, false},
+		{StylePredicate{Property: "name", Operator: "exists"}, true},
+		{StylePredicate{Property: "deleted_at", Operator: "exists"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.predicate.matches(properties), "%+v", tt.predicate)
+	}
+}
+
+func TestNewGraphStylerRejectsInvalidRules(t *testing.T) {
+	_, err := NewGraphStyler([]StylingRule{{Name: "a", Style: map[string]any{"color": "red"},
+		When: []StylePredicate{{Property: "x", Operator: "like"}}}})
+	assert.ErrorContains(t, err, "unknown operator")
+
+	_, err = NewGraphStyler([]StylingRule{{Name: "a", Style: map[string]any{"color": "red"},
+		When: []StylePredicate{{Property: "x", Operator: "gt", Value: "high"}}}})
+	assert.ErrorContains(t, err, "numeric value")
+
+	_, err = NewGraphStyler([]StylingRule{{Name: "a", Target: "edge", Style: map[string]any{"color": "red"}}})
+	assert.ErrorContains(t, err, "unknown target")
+
+	_, err = NewGraphStyler([]StylingRule{{Name: "a"}})
+	assert.ErrorContains(t, err, "no style")
+}
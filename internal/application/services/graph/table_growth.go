@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TableSizeSnapshot is the size of the source tables at one point in time
+type TableSizeSnapshot struct {
+	CapturedAt time.Time          `json:"captured_at"`
+	Tables     []models.TableSize `json:"tables"`
+}
+
+// TableGrowthPoint is one size of a table, with the change since its previous point
+type TableGrowthPoint struct {
+	CapturedAt time.Time `json:"captured_at"`
+	RowCount   int64     `json:"row_count"`
+	TotalSize  int64     `json:"total_size"`
+	RowDelta   int64     `json:"row_delta"`
+	SizeDelta  int64     `json:"size_delta"`
+}
+
+// TableGrowth is the size series of one table, oldest first, with its growth from the
+// first point to the last
+type TableGrowth struct {
+	Table      string             `json:"table"`
+	Points     []TableGrowthPoint `json:"points"`
+	RowDelta   int64              `json:"row_delta"`
+	SizeDelta  int64              `json:"size_delta"`
+	RowsPerDay float64            `json:"rows_per_day"`
+}
+
+// RecordTableSizes keeps the sizes of the source tables as a new point of their growth
+// series, under the same retention as the graph snapshots
+func (s *GraphSnapshotStore) RecordTableSizes(sizes []*models.TableSize) {
+	snapshot := TableSizeSnapshot{Tables: make([]models.TableSize, 0, len(sizes))}
+	for _, size := range sizes {
+		if size != nil {
+			snapshot.Tables = append(snapshot.Tables, *size)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot.CapturedAt = s.now()
+	s.tableSizes = append(s.tableSizes, snapshot)
+	if len(s.tableSizes) > s.retention {
+		s.tableSizes = s.tableSizes[len(s.tableSizes)-s.retention:]
+	}
+}
+
+// TableGrowth returns the size series of every table seen in the kept table size snapshots,
+// the fastest growing table first
+func (s *GraphSnapshotStore) TableGrowth() []TableGrowth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series := make(map[string]*TableGrowth)
+	for _, snapshot := range s.tableSizes {
+		for _, size := range snapshot.Tables {
+			growth, ok := series[size.TableName]
+			if !ok {
+				growth = &TableGrowth{Table: size.TableName, Points: []TableGrowthPoint{}}
+				series[size.TableName] = growth
+			}
+			point := TableGrowthPoint{
+				CapturedAt: snapshot.CapturedAt,
+				RowCount:   size.RowCount,
+				TotalSize:  size.TotalSize,
+			}
+			if n := len(growth.Points); n > 0 {
+				point.RowDelta = size.RowCount - growth.Points[n-1].RowCount
+				point.SizeDelta = size.TotalSize - growth.Points[n-1].TotalSize
+			}
+			growth.Points = append(growth.Points, point)
+		}
+	}
+
+	growth := make([]TableGrowth, 0, len(series))
+	for _, table := range series {
+		first, last := table.Points[0], table.Points[len(table.Points)-1]
+		table.RowDelta = last.RowCount - first.RowCount
+		table.SizeDelta = last.TotalSize - first.TotalSize
+		if elapsed := last.CapturedAt.Sub(first.CapturedAt); elapsed > 0 {
+			table.RowsPerDay = float64(table.RowDelta) / elapsed.Hours() * 24
+		}
+		growth = append(growth, *table)
+	}
+	sort.Slice(growth, func(i, j int) bool {
+		if growth[i].RowDelta != growth[j].RowDelta {
+			return growth[i].RowDelta > growth[j].RowDelta
+		}
+		return growth[i].Table < growth[j].Table
+	})
+	return growth
+}
+
+// CaptureTableSizes records the table sizes read by read now and then every interval,
+// until ctx is done. Failed reads are logged and skipped.
+func (s *GraphSnapshotStore) CaptureTableSizes(ctx context.Context, interval time.Duration, read func(ctx context.Context) ([]*models.TableSize, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if sizes, err := read(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logrus.Warnf("Failed to capture table sizes: %v", err)
+		} else {
+			s.RecordTableSizes(sizes)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
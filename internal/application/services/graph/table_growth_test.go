@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sizes(tables map[string][2]int64) []*models.TableSize {
+	out := make([]*models.TableSize, 0, len(tables))
+	for name, size := range tables {
+		out = append(out, &models.TableSize{TableName: name, RowCount: size[0], TotalSize: size[1]})
+	}
+	return out
+}
+
+func TestTableGrowthDeltaPerTable(t *testing.T) {
+	store := NewGraphSnapshotStore(0)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+
+	store.RecordTableSizes(sizes(map[string][2]int64{"users": {100, 4096}, "orders": {1000, 65536}}))
+	now = start.Add(12 * time.Hour)
+	store.RecordTableSizes(sizes(map[string][2]int64{"users": {150, 8192}, "orders": {1000, 65536}, "audit": {10, 1024}}))
+
+	growth := store.TableGrowth()
+	require.Len(t, growth, 3)
+	assert.Equal(t, []string{"users", "audit", "orders"}, []string{growth[0].Table, growth[1].Table, growth[2].Table},
+		"fastest growing table first")
+
+	users := growth[0]
+	require.Len(t, users.Points, 2)
+	assert.Equal(t, int64(50), users.RowDelta)
+	assert.Equal(t, int64(4096), users.SizeDelta)
+	assert.Equal(t, int64(0), users.Points[0].RowDelta)
+	assert.Equal(t, int64(50), users.Points[1].RowDelta)
+	assert.Equal(t, now, users.Points[1].CapturedAt)
+	assert.InDelta(t, 100.0, users.RowsPerDay, 0.001)
+
+	// A table seen only once has no growth yet
+	audit := growth[1]
+	require.Len(t, audit.Points, 1)
+	assert.Equal(t, int64(0), audit.RowDelta)
+	assert.Zero(t, audit.RowsPerDay)
+
+	assert.Equal(t, int64(0), growth[2].RowDelta)
+	assert.Equal(t, int64(0), growth[2].SizeDelta)
+}
+
+func TestTableGrowthKeepsRetention(t *testing.T) {
+	store := NewGraphSnapshotStore(2)
+	for rows := int64(1); rows <= 3; rows++ {
+		store.RecordTableSizes(sizes(map[string][2]int64{"users": {rows * 10, 0}}))
+	}
+
+	growth := store.TableGrowth()
+	require.Len(t, growth, 1)
+	require.Len(t, growth[0].Points, 2)
+	assert.Equal(t, int64(20), growth[0].Points[0].RowCount)
+	assert.Equal(t, int64(10), growth[0].RowDelta)
+}
+
+func TestCaptureTableSizesUntilCancelled(t *testing.T) {
+	store := NewGraphSnapshotStore(0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	reads := 0
+	read := func(context.Context) ([]*models.TableSize, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reads++
+		switch reads {
+		case 2:
+			return nil, errors.New("connection refused")
+		case 3:
+			cancel()
+		}
+		return sizes(map[string][2]int64{"users": {int64(reads), 0}}), nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		store.CaptureTableSizes(ctx, time.Millisecond, read)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("capture did not stop after the context was cancelled")
+	}
+
+	growth := store.TableGrowth()
+	require.Len(t, growth, 1)
+	assert.Len(t, growth[0].Points, 2, "the failed read is skipped")
+	assert.Equal(t, int64(2), growth[0].RowDelta)
+}
@@ -0,0 +1,104 @@
+/*
+ * SQL Graph Visualizer - Index Analysis
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// primaryIndexName is how MySQL names the primary key index
+const primaryIndexName = "PRIMARY"
+
+// leadingIndexes maps the lower-cased first column of every index of a table to its indexes.
+// Only an index starting with a column speeds up lookups on that column.
+func leadingIndexes(indexes []models.IndexInfo) map[string][]models.IndexInfo {
+	leading := make(map[string][]models.IndexInfo, len(indexes))
+	for _, index := range indexes {
+		if len(index.Columns) > 0 {
+			column := strings.ToLower(index.Columns[0])
+			leading[column] = append(leading[column], index)
+		}
+	}
+	return leading
+}
+
+// hasUniqueIndexOn reports whether one of indexes is a unique index on exactly column
+func hasUniqueIndexOn(indexes []models.IndexInfo, column string) bool {
+	for _, index := range indexes {
+		if index.IsUnique && len(index.Columns) == 1 && strings.EqualFold(index.Columns[0], column) {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexRecommendations suggests index changes for a table whose indexes were discovered.
+// A non-unique index whose columns lead another index is redundant: every lookup it serves
+// can use the longer index, so it is likely unused and only slows down writes. Key-like
+// columns (declared foreign keys and *_id columns) no index starts with are reported too,
+// since relationship extraction joins on them. Tables without discovered indexes get none.
+func IndexRecommendations(table *models.UniversalTableInfo) []string {
+	if len(table.Indexes) == 0 {
+		return nil
+	}
+
+	var recommendations []string
+	for i, index := range table.Indexes {
+		if index.IsUnique || strings.EqualFold(index.Name, primaryIndexName) {
+			continue
+		}
+		for j, other := range table.Indexes {
+			if i == j || !isColumnPrefix(index.Columns, other.Columns) {
+				continue
+			}
+			// Of two indexes on the same columns, only the second is reported
+			if len(index.Columns) == len(other.Columns) && !other.IsUnique && j > i {
+				continue
+			}
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Index %s (%s) is covered by %s (%s) and is likely unused - consider dropping it",
+				index.Name, strings.Join(index.Columns, ", "), other.Name, strings.Join(other.Columns, ", ")))
+			break
+		}
+	}
+
+	leading := leadingIndexes(table.Indexes)
+	declared := make(map[string]bool, len(table.Relationships))
+	for _, rel := range table.Relationships {
+		declared[strings.ToLower(rel.SourceColumn)] = true
+	}
+	for _, column := range table.Columns {
+		name := strings.ToLower(column.Name)
+		if isPrimaryKeyColumn(column) || len(leading[name]) > 0 {
+			continue
+		}
+		if declared[name] || strings.HasSuffix(name, "_id") {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Column %s references another table but no index starts with it - consider indexing it for relationship extraction", column.Name))
+		}
+	}
+	return recommendations
+}
+
+// isColumnPrefix reports whether columns are the leading columns of other
+func isColumnPrefix(columns, other []string) bool {
+	if len(columns) == 0 || len(columns) > len(other) {
+		return false
+	}
+	for i, column := range columns {
+		if !strings.EqualFold(column, other[i]) {
+			return false
+		}
+	}
+	return true
+}
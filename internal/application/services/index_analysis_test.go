@@ -0,0 +1,134 @@
+/*
+ * SQL Graph Visualizer - Index Analysis Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// indexedRepository serves customers and orders tables; orders.customer_id is indexed
+type indexedRepository struct {
+	countingRepository
+	indexLookups []string
+}
+
+func (r *indexedRepository) GetColumns(ctx context.Context, tableName string) ([]*models.ColumnInfo, error) {
+	columns := []*models.ColumnInfo{{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}}
+	if tableName == "orders" {
+		columns = append(columns, &models.ColumnInfo{Name: "customer_id", DataType: "int"})
+	}
+	return columns, nil
+}
+
+func (r *indexedRepository) GetIndexes(ctx context.Context, tableName string) ([]models.IndexInfo, error) {
+	r.indexLookups = append(r.indexLookups, tableName)
+	indexes := []models.IndexInfo{{Name: tableName + "_pkey", Columns: []string{"id"}, IsUnique: true, Type: "btree"}}
+	if tableName == "orders" {
+		indexes = append(indexes, models.IndexInfo{Name: "orders_customer_id_idx", Columns: []string{"customer_id"}, Type: "btree"})
+	}
+	return indexes, nil
+}
+
+func analyzeIndexedSchema(t *testing.T, discoverIndexes bool) (*models.UniversalDatabaseAnalysisResult, *indexedRepository) {
+	t.Helper()
+	repo := &indexedRepository{countingRepository: countingRepository{tables: []string{"customers", "orders"}}}
+	config := newCacheTestConfig()
+	config.DataFiltering.DiscoverIndexes = discoverIndexes
+
+	result, err := NewUniversalDatabaseService(repo, config).ConnectAndAnalyze(context.Background())
+	require.NoError(t, err)
+	require.True(t, result.Success, result.ErrorMessage)
+	return result, repo
+}
+
+func TestIndexDiscoveryEnabled(t *testing.T) {
+	result, repo := analyzeIndexedSchema(t, true)
+
+	assert.Equal(t, []string{"customers", "orders"}, repo.indexLookups)
+	orders := result.SchemaAnalysis.Tables[1]
+	assert.Equal(t, []models.IndexInfo{
+		{Name: "orders_pkey", Columns: []string{"id"}, IsUnique: true, Type: "btree"},
+		{Name: "orders_customer_id_idx", Columns: []string{"customer_id"}, Type: "btree"},
+	}, orders.Indexes)
+
+	require.Len(t, result.SchemaAnalysis.ImplicitRelationships, 1)
+	assert.InDelta(t, namingConfidence+matchingTypeBonus+indexedColumnBonus,
+		result.SchemaAnalysis.ImplicitRelationships[0].Confidence, 1e-9, "the indexed column is a stronger hint")
+}
+
+func TestIndexDiscoveryDisabled(t *testing.T) {
+	result, repo := analyzeIndexedSchema(t, false)
+
+	assert.Empty(t, repo.indexLookups, "indexes are not queried")
+	for _, table := range result.SchemaAnalysis.Tables {
+		assert.Empty(t, table.Indexes)
+	}
+	require.Len(t, result.SchemaAnalysis.ImplicitRelationships, 1)
+	assert.InDelta(t, namingConfidence+matchingTypeBonus, result.SchemaAnalysis.ImplicitRelationships[0].Confidence, 1e-9)
+}
+
+func TestIndexDiscoveryChangesCacheKey(t *testing.T) {
+	config := newCacheTestConfig()
+	without := SchemaFingerprint(config)
+	config.DataFiltering.DiscoverIndexes = true
+
+	assert.NotEqual(t, without, SchemaFingerprint(config))
+}
+
+func TestIndexRecommendations(t *testing.T) {
+	table := &models.UniversalTableInfo{
+		Name: "order_lines",
+		Columns: []*models.ColumnInfo{
+			{Name: "id", KeyType: "PRI"},
+			{Name: "order_id"},
+			{Name: "product_id"},
+			{Name: "warehouse_id"},
+			{Name: "quantity"},
+		},
+		Indexes: []models.IndexInfo{
+			{Name: "PRIMARY", Columns: []string{"id"}, IsUnique: true},
+			{Name: "idx_order", Columns: []string{"order_id"}},
+			{Name: "idx_order_product", Columns: []string{"order_id", "product_id"}},
+			{Name: "idx_warehouse", Columns: []string{"warehouse_id"}},
+			{Name: "idx_warehouse_copy", Columns: []string{"warehouse_id"}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"Index idx_order (order_id) is covered by idx_order_product (order_id, product_id) and is likely unused - consider dropping it",
+		"Index idx_warehouse_copy (warehouse_id) is covered by idx_warehouse (warehouse_id) and is likely unused - consider dropping it",
+		"Column product_id references another table but no index starts with it - consider indexing it for relationship extraction",
+	}, IndexRecommendations(table))
+
+	table.Indexes = nil
+	assert.Empty(t, IndexRecommendations(table), "nothing is recommended without discovered indexes")
+}
+
+func TestUniqueIndexMakesImplicitRelationshipOneToOne(t *testing.T) {
+	tables := []*models.UniversalTableInfo{
+		{Name: "users", Columns: []*models.ColumnInfo{{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}}},
+		{
+			Name:    "profiles",
+			Columns: []*models.ColumnInfo{{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}, {Name: "user_id", DataType: "int"}},
+			Indexes: []models.IndexInfo{{Name: "profiles_user_id_key", Columns: []string{"user_id"}, IsUnique: true}},
+		},
+	}
+
+	relationships := InferImplicitRelationships(tables)
+
+	require.Len(t, relationships, 1)
+	assert.Equal(t, "ONE_TO_ONE", relationships[0].RelationType)
+}
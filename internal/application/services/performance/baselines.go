@@ -0,0 +1,196 @@
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+)
+
+// ErrBaselineNotFound is returned when no baseline is stored under the requested name
+var ErrBaselineNotFound = errors.New("baseline not found")
+
+var baselineNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// PerformanceBaseline is a named snapshot of performance metrics that later collections are
+// compared against
+type PerformanceBaseline struct {
+	Name       string                    `json:"name"`
+	CapturedAt time.Time                 `json:"captured_at"`
+	Metrics    *ports.PerformanceMetrics `json:"metrics"`
+}
+
+// BaselineComparison is the result of comparing the current metrics with a baseline
+type BaselineComparison struct {
+	Baseline    *PerformanceBaseline          `json:"baseline"`
+	Current     *ports.PerformanceMetrics     `json:"current"`
+	Comparison  *ports.PerformanceComparison  `json:"comparison"`
+	Regressions []ports.PerformanceRegression `json:"regressions"`
+}
+
+// BaselineStore persists baselines as one JSON file per name, so they survive restarts and
+// can be compared against after a deployment
+type BaselineStore struct {
+	mu  sync.RWMutex
+	dir string
+	now func() time.Time
+}
+
+// NewBaselineStore creates a store rooted at dir; an empty dir uses the user config directory
+func NewBaselineStore(dir string) (*BaselineStore, error) {
+	if dir == "" {
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve user config directory: %w", err)
+		}
+		dir = filepath.Join(userConfigDir, "sql-graph-visualizer", "baselines")
+	}
+
+	return &BaselineStore{
+		dir: dir,
+		now: time.Now,
+	}, nil
+}
+
+// ValidateBaselineName checks that name can be used as a baseline name; names become file
+// names, so only letters, digits, dots, dashes and underscores are allowed
+func ValidateBaselineName(name string) error {
+	if !baselineNamePattern.MatchString(name) || strings.Trim(name, ".") == "" {
+		return fmt.Errorf("invalid baseline name %q (use letters, digits, '.', '-' and '_')", name)
+	}
+	return nil
+}
+
+// Save stores metrics under name, replacing a previous baseline of the same name
+func (s *BaselineStore) Save(name string, metrics *ports.PerformanceMetrics) (*PerformanceBaseline, error) {
+	if err := ValidateBaselineName(name); err != nil {
+		return nil, err
+	}
+	if metrics == nil {
+		return nil, fmt.Errorf("metrics are required to capture a baseline")
+	}
+
+	baseline := &PerformanceBaseline{
+		Name:       name,
+		CapturedAt: s.now(),
+		Metrics:    metrics,
+	}
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted capture never leaves a partial baseline
+	tmpPath := s.path(name) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write baseline: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path(name)); err != nil {
+		return nil, fmt.Errorf("failed to store baseline: %w", err)
+	}
+
+	return baseline, nil
+}
+
+// Get returns the baseline stored under name
+func (s *BaselineStore) Get(name string) (*PerformanceBaseline, error) {
+	if err := ValidateBaselineName(name); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrBaselineNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read baseline %s: %w", name, err)
+	}
+
+	var baseline PerformanceBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", name, err)
+	}
+	return &baseline, nil
+}
+
+// List returns the stored baselines, most recently captured first
+func (s *BaselineStore) List() ([]*PerformanceBaseline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*PerformanceBaseline{}, nil
+		}
+		return nil, fmt.Errorf("failed to list baselines: %w", err)
+	}
+
+	baselines := make([]*PerformanceBaseline, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read baseline %s: %w", entry.Name(), err)
+		}
+		var baseline PerformanceBaseline
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return nil, fmt.Errorf("failed to parse baseline %s: %w", entry.Name(), err)
+		}
+		baselines = append(baselines, &baseline)
+	}
+
+	sort.Slice(baselines, func(i, j int) bool {
+		return baselines[i].CapturedAt.After(baselines[j].CapturedAt)
+	})
+	return baselines, nil
+}
+
+// CompareWithBaseline compares current metrics with a stored baseline and reports the
+// regressions since it was captured
+func (pa *PerformanceAnalyzer) CompareWithBaseline(ctx context.Context, baseline *PerformanceBaseline, current *ports.PerformanceMetrics) (*BaselineComparison, error) {
+	if baseline == nil {
+		return nil, fmt.Errorf("baseline is required")
+	}
+
+	comparison, err := pa.ComparePerformance(ctx, baseline.Metrics, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with baseline %s: %w", baseline.Name, err)
+	}
+	regressions, err := pa.DetectRegressions(ctx, current, baseline.Metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect regressions against baseline %s: %w", baseline.Name, err)
+	}
+
+	return &BaselineComparison{
+		Baseline:    baseline,
+		Current:     current,
+		Comparison:  comparison,
+		Regressions: regressions,
+	}, nil
+}
+
+func (s *BaselineStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
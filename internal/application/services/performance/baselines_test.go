@@ -0,0 +1,86 @@
+package performance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaselineStoreSavesAndLists(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewBaselineStore(dir)
+	require.NoError(t, err)
+
+	captured := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return captured }
+	_, err = store.Save("before-release", &ports.PerformanceMetrics{QueriesPerSecond: 120, AverageLatency: 4})
+	require.NoError(t, err)
+
+	store.now = func() time.Time { return captured.Add(time.Hour) }
+	_, err = store.Save("after-index", &ports.PerformanceMetrics{QueriesPerSecond: 150, AverageLatency: 3})
+	require.NoError(t, err)
+
+	// A new store over the same directory sees the persisted baselines
+	reopened, err := NewBaselineStore(dir)
+	require.NoError(t, err)
+
+	baseline, err := reopened.Get("before-release")
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, baseline.Metrics.QueriesPerSecond)
+	assert.True(t, captured.Equal(baseline.CapturedAt))
+
+	baselines, err := reopened.List()
+	require.NoError(t, err)
+	require.Len(t, baselines, 2)
+	assert.Equal(t, "after-index", baselines[0].Name, "most recent first")
+	assert.Equal(t, "before-release", baselines[1].Name)
+}
+
+func TestBaselineStoreRejectsUnknownAndInvalidNames(t *testing.T) {
+	store, err := NewBaselineStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Get("missing")
+	assert.ErrorIs(t, err, ErrBaselineNotFound)
+
+	for _, name := range []string{"", "../etc", "a/b", ".."} {
+		_, err = store.Save(name, &ports.PerformanceMetrics{})
+		assert.Error(t, err, name)
+	}
+
+	baselines, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, baselines)
+}
+
+func TestCompareWithBaselineReportsRegressions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	analyzer := NewPerformanceAnalyzer(logger, nil)
+
+	baseline := &PerformanceBaseline{
+		Name:    "before-release",
+		Metrics: &ports.PerformanceMetrics{QueriesPerSecond: 200, AverageLatency: 5},
+	}
+	current := &ports.PerformanceMetrics{QueriesPerSecond: 100, AverageLatency: 10}
+
+	result, err := analyzer.CompareWithBaseline(context.Background(), baseline, current)
+	require.NoError(t, err)
+
+	assert.Equal(t, baseline, result.Baseline)
+	assert.Equal(t, current, result.Current)
+	require.NotNil(t, result.Comparison)
+	assert.Len(t, result.Comparison.Changes, 2)
+
+	metrics := make([]string, 0, len(result.Regressions))
+	for _, regression := range result.Regressions {
+		metrics = append(metrics, regression.MetricName)
+	}
+	assert.ElementsMatch(t, []string{"average_latency", "queries_per_second"}, metrics)
+}
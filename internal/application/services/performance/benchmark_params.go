@@ -0,0 +1,164 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParamType is the type of value a benchmark tool parameter takes
+type ParamType string
+
+const (
+	ParamString ParamType = "string"
+	ParamInt    ParamType = "int"
+	ParamFloat  ParamType = "float"
+	ParamBool   ParamType = "bool"
+)
+
+// ParamSpec describes one custom parameter a benchmark tool accepts. Values, when set,
+// lists the only strings allowed.
+type ParamSpec struct {
+	Type   ParamType
+	Values []string
+}
+
+// ParamSchema maps the custom parameters a tool accepts onto their specs
+type ParamSchema map[string]ParamSpec
+
+// SysbenchParamSchema lists the sysbench options accepted in CustomParams. Options set from
+// BenchmarkConfig fields (threads, time, table-size, tables, warmup-time and the connection
+// options) are deliberately absent so they cannot be given twice.
+var SysbenchParamSchema = ParamSchema{
+	"rand-type":           {Type: ParamString, Values: []string{"uniform", "gaussian", "special", "pareto", "zipfian"}},
+	"rand-seed":           {Type: ParamInt},
+	"rate":                {Type: ParamInt},
+	"events":              {Type: ParamInt},
+	"report-interval":     {Type: ParamInt},
+	"percentile":          {Type: ParamInt},
+	"histogram":           {Type: ParamBool},
+	"forced-shutdown":     {Type: ParamString},
+	"db-ps-mode":          {Type: ParamString, Values: []string{"auto", "disable"}},
+	"range-size":          {Type: ParamInt},
+	"point-selects":       {Type: ParamInt},
+	"simple-ranges":       {Type: ParamInt},
+	"sum-ranges":          {Type: ParamInt},
+	"order-ranges":        {Type: ParamInt},
+	"distinct-ranges":     {Type: ParamInt},
+	"index-updates":       {Type: ParamInt},
+	"non-index-updates":   {Type: ParamInt},
+	"delete-inserts":      {Type: ParamInt},
+	"range-selects":       {Type: ParamBool},
+	"skip-trx":            {Type: ParamBool},
+	"secondary":           {Type: ParamBool},
+	"create-secondary":    {Type: ParamBool},
+	"auto-inc":            {Type: ParamBool},
+	"rand-zipfian-exp":    {Type: ParamFloat},
+	"rand-pareto-h":       {Type: ParamFloat},
+	"mysql-ignore-errors": {Type: ParamString},
+	"pgsql-sslmode":       {Type: ParamString, Values: []string{"disable", "allow", "prefer", "require"}},
+}
+
+// Validate checks that every parameter is known and its value has the declared type. Keys
+// are checked in name order so the same input always reports the same error.
+func (s ParamSchema) Validate(params map[string]interface{}) error {
+	_, err := s.Args(params)
+	return err
+}
+
+// format renders a value as a command-line option value, accepting numbers and booleans
+// given as strings since parameters often arrive from query strings or YAML
+func (p ParamSpec) format(value interface{}) (string, error) {
+	switch p.Type {
+	case ParamInt:
+		switch v := value.(type) {
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			if v == math.Trunc(v) && !math.IsInf(v, 0) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		case string:
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("expected an integer, got %v", value)
+
+	case ParamFloat:
+		switch v := value.(type) {
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("expected a number, got %v", value)
+
+	case ParamBool:
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return "on", nil
+			}
+			return "off", nil
+		case string:
+			switch strings.ToLower(v) {
+			case "on", "true":
+				return "on", nil
+			case "off", "false":
+				return "off", nil
+			}
+		}
+		return "", fmt.Errorf("expected a boolean, got %v", value)
+	}
+
+	v, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, got %v", value)
+	}
+	if len(p.Values) > 0 {
+		for _, allowed := range p.Values {
+			if v == allowed {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not one of %s", v, strings.Join(p.Values, ", "))
+	}
+	return v, nil
+}
+
+// Args validates the parameters and renders them as --key=value options in name order
+func (s ParamSchema) Args(params map[string]interface{}) ([]string, error) {
+	args := make([]string, 0, len(params))
+	for _, key := range sortedParamKeys(params) {
+		spec, ok := s[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %q", key)
+		}
+		value, err := spec.format(params[key])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %w", key, err)
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", key, value))
+	}
+	return args, nil
+}
+
+func sortedParamKeys(params map[string]interface{}) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -25,8 +26,13 @@ type BenchmarkService struct {
 	tools      map[string]ports.BenchmarkToolPort
 	toolsMutex sync.RWMutex
 
+	// Result sinks notified when a run finishes
+	sinks      []BenchmarkResultSink
+	sinksMutex sync.RWMutex
+
 	// State management
 	activeRuns map[string]*BenchmarkExecution
+	queue      []*BenchmarkExecution
 	runsMutex  sync.RWMutex
 
 	// Configuration
@@ -40,6 +46,11 @@ type BenchmarkServiceConfig struct {
 	DefaultTimeout    time.Duration `yaml:"default_timeout" json:"default_timeout"`
 	CleanupInterval   time.Duration `yaml:"cleanup_interval" json:"cleanup_interval"`
 
+	// Queueing: with QueueEnabled, submissions beyond MaxConcurrentRuns wait in a FIFO
+	// queue of at most MaxQueueSize entries instead of failing
+	QueueEnabled bool `yaml:"queue_enabled" json:"queue_enabled"`
+	MaxQueueSize int  `yaml:"max_queue_size" json:"max_queue_size"`
+
 	// Storage settings
 	RetainResults      time.Duration `yaml:"retain_results" json:"retain_results"`
 	MaxResultsInMemory int           `yaml:"max_results_in_memory" json:"max_results_in_memory"`
@@ -52,12 +63,19 @@ type BenchmarkServiceConfig struct {
 	// Tool configurations
 	EnabledTools       []string               `yaml:"enabled_tools" json:"enabled_tools"`
 	ToolConfigurations map[string]interface{} `yaml:"tool_configurations" json:"tool_configurations"`
+
+	// Sinks receive every finished result, e.g. for CI archival or dashboards
+	Sinks []BenchmarkSinkConfig `yaml:"sinks" json:"sinks"`
+
+	// RawOutput bounds the tool output kept with each result
+	RawOutput RawOutputConfig `yaml:"raw_output" json:"raw_output"`
 }
 
 // BenchmarkExecution tracks a running benchmark
 type BenchmarkExecution struct {
 	ID         string
 	Config     ports.BenchmarkConfig
+	QueuedAt   time.Time
 	StartTime  time.Time
 	Status     ports.BenchmarkStatus
 	Tool       ports.BenchmarkToolPort
@@ -70,13 +88,13 @@ type BenchmarkExecution struct {
 
 // BenchmarkProgress tracks execution progress
 type BenchmarkProgress struct {
-	CurrentPhase       string        `json:"current_phase"`
-	CompletedSteps     int           `json:"completed_steps"`
-	TotalSteps         int           `json:"total_steps"`
-	ElapsedTime        time.Duration `json:"elapsed_time"`
-	EstimatedRemaining time.Duration `json:"estimated_remaining"`
-	LastUpdate         time.Time     `json:"last_update"`
-	Messages           []string      `json:"messages"`
+	CurrentPhase       string          `json:"current_phase"`
+	CompletedSteps     int             `json:"completed_steps"`
+	TotalSteps         int             `json:"total_steps"`
+	ElapsedTime        models.Duration `json:"elapsed_time"`
+	EstimatedRemaining models.Duration `json:"estimated_remaining"`
+	LastUpdate         time.Time       `json:"last_update"`
+	Messages           []string        `json:"messages"`
 }
 
 // NewBenchmarkService creates a new benchmark service instance
@@ -103,6 +121,15 @@ func NewBenchmarkService(
 		activeRuns:     make(map[string]*BenchmarkExecution),
 	}
 
+	for _, sinkConfig := range config.Sinks {
+		sink, err := NewBenchmarkResultSink(sinkConfig)
+		if err != nil {
+			logger.WithError(err).Warn("Skipping benchmark result sink")
+			continue
+		}
+		service.AddResultSink(sink)
+	}
+
 	// Start cleanup routine
 	go service.cleanupRoutine()
 
@@ -124,6 +151,15 @@ func (s *BenchmarkService) RegisterBenchmarkTool(name string, tool ports.Benchma
 	return nil
 }
 
+// AddResultSink registers a sink that receives every finished benchmark result
+func (s *BenchmarkService) AddResultSink(sink BenchmarkResultSink) {
+	s.sinksMutex.Lock()
+	defer s.sinksMutex.Unlock()
+
+	s.sinks = append(s.sinks, sink)
+	s.logger.WithField("sink", sink.Name()).Info("Registered benchmark result sink")
+}
+
 // GetAvailableTools returns list of available benchmark tools
 func (s *BenchmarkService) GetAvailableTools() []string {
 	s.toolsMutex.RLock()
@@ -146,11 +182,6 @@ func (s *BenchmarkService) ExecuteBenchmark(ctx context.Context, config ports.Be
 		return "", fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Check concurrent run limits
-	if s.getActiveRunCount() >= s.config.MaxConcurrentRuns {
-		return "", fmt.Errorf("maximum concurrent runs (%d) exceeded", s.config.MaxConcurrentRuns)
-	}
-
 	// Get benchmark tool
 	tool, err := s.getBenchmarkTool(toolName)
 	if err != nil {
@@ -162,30 +193,50 @@ func (s *BenchmarkService) ExecuteBenchmark(ctx context.Context, config ports.Be
 		return "", fmt.Errorf("tool validation failed: %w", err)
 	}
 
-	// Create execution context
 	executionID := uuid.New().String()
-	executionCtx, cancel := context.WithTimeout(ctx, s.config.DefaultTimeout)
-
 	execution := &BenchmarkExecution{
-		ID:         executionID,
-		Config:     config,
-		StartTime:  time.Now(),
-		Status:     ports.BenchmarkStatusPending,
-		Tool:       tool,
-		Context:    executionCtx,
-		CancelFunc: cancel,
+		ID:     executionID,
+		Config: config,
+		Tool:   tool,
 		Progress: &BenchmarkProgress{
-			CurrentPhase: "initializing",
-			TotalSteps:   4, // prepare, warmup, execute, analyze
-			LastUpdate:   time.Now(),
+			TotalSteps: 4, // prepare, warmup, execute, analyze
 		},
 	}
 
-	// Register execution
-	s.registerExecution(execution)
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
 
-	// Start execution asynchronously
-	go s.executeAsync(execution)
+	// Check concurrent run limits
+	if s.activeRunCountLocked() >= s.config.MaxConcurrentRuns {
+		if !s.config.QueueEnabled {
+			return "", fmt.Errorf("maximum concurrent runs (%d) exceeded", s.config.MaxConcurrentRuns)
+		}
+		if len(s.queue) >= s.maxQueueSize() {
+			return "", fmt.Errorf("maximum concurrent runs (%d) exceeded and benchmark queue is full (%d)", s.config.MaxConcurrentRuns, s.maxQueueSize())
+		}
+
+		// A queued run outlives the submitting request, so only its values are kept
+		execution.Context, execution.CancelFunc = context.WithCancel(context.WithoutCancel(ctx))
+		execution.QueuedAt = time.Now()
+		execution.Status = ports.BenchmarkStatusQueued
+		execution.Progress.CurrentPhase = "queued"
+		execution.Progress.LastUpdate = execution.QueuedAt
+
+		s.activeRuns[executionID] = execution
+		s.queue = append(s.queue, execution)
+
+		s.logger.WithFields(logrus.Fields{
+			"execution_id":   executionID,
+			"tool":           toolName,
+			"queue_position": len(s.queue),
+		}).Info("Queued benchmark execution")
+
+		return executionID, nil
+	}
+
+	execution.Context, execution.CancelFunc = context.WithCancel(ctx)
+	s.activeRuns[executionID] = execution
+	s.startExecutionLocked(execution)
 
 	s.logger.WithFields(logrus.Fields{
 		"execution_id": executionID,
@@ -197,8 +248,80 @@ func (s *BenchmarkService) ExecuteBenchmark(ctx context.Context, config ports.Be
 	return executionID, nil
 }
 
+// QueuePosition returns the 1-based position of a queued execution, or 0 when it is not queued
+func (s *BenchmarkService) QueuePosition(executionID string) int {
+	s.runsMutex.RLock()
+	defer s.runsMutex.RUnlock()
+
+	for i, execution := range s.queue {
+		if execution.ID == executionID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// startExecutionLocked applies the run timeout and starts the execution. Callers hold runsMutex.
+func (s *BenchmarkService) startExecutionLocked(execution *BenchmarkExecution) {
+	timeoutCtx, cancelTimeout := context.WithTimeout(execution.Context, s.config.DefaultTimeout)
+	cancel := execution.CancelFunc
+
+	execution.mutex.Lock()
+	execution.Context = timeoutCtx
+	execution.CancelFunc = func() {
+		cancelTimeout()
+		cancel()
+	}
+	execution.StartTime = time.Now()
+	execution.Status = ports.BenchmarkStatusPending
+	execution.Progress.CurrentPhase = "initializing"
+	execution.Progress.LastUpdate = execution.StartTime
+	execution.mutex.Unlock()
+
+	go s.executeAsync(execution)
+}
+
+// startQueued starts queued executions, oldest first, while run slots are free
+func (s *BenchmarkService) startQueued() {
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+
+	for len(s.queue) > 0 && s.activeRunCountLocked() < s.config.MaxConcurrentRuns {
+		execution := s.queue[0]
+		s.queue = s.queue[1:]
+
+		s.startExecutionLocked(execution)
+		s.logger.WithFields(logrus.Fields{
+			"execution_id": execution.ID,
+			"waited":       time.Since(execution.QueuedAt),
+		}).Info("Started queued benchmark execution")
+	}
+}
+
+// dequeue removes an execution from the queue, reporting whether it was queued
+func (s *BenchmarkService) dequeue(executionID string) bool {
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+
+	for i, execution := range s.queue {
+		if execution.ID == executionID {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *BenchmarkService) maxQueueSize() int {
+	if s.config.MaxQueueSize > 0 {
+		return s.config.MaxQueueSize
+	}
+	return defaultMaxQueueSize
+}
+
 // executeAsync runs the benchmark asynchronously
 func (s *BenchmarkService) executeAsync(execution *BenchmarkExecution) {
+	defer s.startQueued()
 	defer s.cleanupExecution(execution.ID)
 	defer execution.CancelFunc()
 
@@ -219,13 +342,14 @@ func (s *BenchmarkService) executeAsync(execution *BenchmarkExecution) {
 			TestType:  execution.Config.TestType,
 			StartTime: execution.StartTime,
 			EndTime:   time.Now(),
-			Duration:  time.Since(execution.StartTime),
+			Duration:  models.Duration(time.Since(execution.StartTime)),
 			Status:    ports.BenchmarkStatusFailed,
 			Error:     err.Error(),
 		}
 	} else {
 		result.ID = execution.ID
 		result.Status = ports.BenchmarkStatusCompleted
+		s.retainRawOutput(result)
 	}
 
 	// Store result
@@ -246,6 +370,27 @@ func (s *BenchmarkService) executeAsync(execution *BenchmarkExecution) {
 			return 0
 		}(),
 	}).Info("Benchmark execution completed")
+
+	s.publishResult(result)
+}
+
+// publishResult hands a finished result to every sink. Sink failures are logged and do not
+// affect the run.
+func (s *BenchmarkService) publishResult(result *ports.BenchmarkResult) {
+	s.sinksMutex.RLock()
+	sinks := append([]BenchmarkResultSink(nil), s.sinks...)
+	s.sinksMutex.RUnlock()
+
+	// The run's context is already done, so sinks bound their own time
+	for _, sink := range sinks {
+		if err := sink.Publish(context.Background(), result); err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"execution_id": result.ID,
+				"sink":         sink.Name(),
+				"error":        err.Error(),
+			}).Warn("Failed to publish benchmark result")
+		}
+	}
 }
 
 // GetBenchmarkResult returns the result of a benchmark execution
@@ -263,11 +408,14 @@ func (s *BenchmarkService) GetBenchmarkResult(executionID string) (*ports.Benchm
 
 	if execution.Result == nil {
 		// Return progress information
-		return &ports.BenchmarkResult{
-			ID:       executionID,
-			Status:   execution.Status,
-			Duration: time.Since(execution.StartTime),
-		}, nil
+		result := &ports.BenchmarkResult{
+			ID:     executionID,
+			Status: execution.Status,
+		}
+		if execution.Status != ports.BenchmarkStatusQueued {
+			result.Duration = models.Duration(time.Since(execution.StartTime))
+		}
+		return result, nil
 	}
 
 	return execution.Result, nil
@@ -287,7 +435,9 @@ func (s *BenchmarkService) GetBenchmarkProgress(executionID string) (*BenchmarkP
 	defer execution.mutex.RUnlock()
 
 	// Update elapsed time
-	execution.Progress.ElapsedTime = time.Since(execution.StartTime)
+	if execution.Status != ports.BenchmarkStatusQueued {
+		execution.Progress.ElapsedTime = models.Duration(time.Since(execution.StartTime))
+	}
 
 	return execution.Progress, nil
 }
@@ -302,8 +452,11 @@ func (s *BenchmarkService) CancelBenchmark(executionID string) error {
 		return fmt.Errorf("execution %s not found", executionID)
 	}
 
+	s.dequeue(executionID)
+
 	execution.mutex.Lock()
-	if execution.Status == ports.BenchmarkStatusRunning || execution.Status == ports.BenchmarkStatusPending {
+	if execution.Status == ports.BenchmarkStatusRunning || execution.Status == ports.BenchmarkStatusPending ||
+		execution.Status == ports.BenchmarkStatusQueued {
 		execution.Status = ports.BenchmarkStatusCancelled
 		execution.CancelFunc()
 	}
@@ -321,14 +474,23 @@ func (s *BenchmarkService) ListActiveRuns() []BenchmarkExecutionInfo {
 	runs := make([]BenchmarkExecutionInfo, 0, len(s.activeRuns))
 	for _, execution := range s.activeRuns {
 		execution.mutex.RLock()
-		runs = append(runs, BenchmarkExecutionInfo{
+		info := BenchmarkExecutionInfo{
 			ID:        execution.ID,
 			ToolName:  s.getToolName(execution.Tool),
 			TestType:  execution.Config.TestType,
 			Status:    execution.Status,
 			StartTime: execution.StartTime,
-			Duration:  time.Since(execution.StartTime),
-		})
+		}
+		if execution.Status == ports.BenchmarkStatusQueued {
+			for i, queued := range s.queue {
+				if queued == execution {
+					info.QueuePosition = i + 1
+				}
+			}
+		} else {
+			info.Duration = models.Duration(time.Since(execution.StartTime))
+		}
+		runs = append(runs, info)
 		execution.mutex.RUnlock()
 	}
 
@@ -342,13 +504,15 @@ type BenchmarkExecutionInfo struct {
 	TestType  string                `json:"test_type"`
 	Status    ports.BenchmarkStatus `json:"status"`
 	StartTime time.Time             `json:"start_time"`
-	Duration  time.Duration         `json:"duration"`
+	Duration  models.Duration       `json:"duration"`
+
+	QueuePosition int `json:"queue_position,omitempty"`
 }
 
 // Private helper methods
 
 func (s *BenchmarkService) validateConfig(config ports.BenchmarkConfig) error {
-	if config.Duration > s.config.MaxDuration {
+	if config.Duration.Std() > s.config.MaxDuration {
 		return fmt.Errorf("duration %v exceeds maximum allowed %v", config.Duration, s.config.MaxDuration)
 	}
 
@@ -391,12 +555,6 @@ func (s *BenchmarkService) getToolName(tool ports.BenchmarkToolPort) string {
 	return "unknown"
 }
 
-func (s *BenchmarkService) registerExecution(execution *BenchmarkExecution) {
-	s.runsMutex.Lock()
-	defer s.runsMutex.Unlock()
-	s.activeRuns[execution.ID] = execution
-}
-
 func (s *BenchmarkService) updateExecutionStatus(executionID string, status ports.BenchmarkStatus, message string) {
 	s.runsMutex.RLock()
 	execution, exists := s.activeRuns[executionID]
@@ -423,10 +581,9 @@ func (s *BenchmarkService) cleanupExecution(executionID string) {
 	// The cleanup routine will remove them after the retention period
 }
 
-func (s *BenchmarkService) getActiveRunCount() int {
-	s.runsMutex.RLock()
-	defer s.runsMutex.RUnlock()
-
+// activeRunCountLocked counts started executions; queued ones do not hold a slot.
+// Callers hold runsMutex.
+func (s *BenchmarkService) activeRunCountLocked() int {
 	count := 0
 	for _, execution := range s.activeRuns {
 		execution.mutex.RLock()
@@ -478,10 +635,14 @@ func (s *BenchmarkService) cleanupOldExecutions() {
 	}
 }
 
+// defaultMaxQueueSize bounds the benchmark queue when MaxQueueSize is not set
+const defaultMaxQueueSize = 20
+
 // defaultBenchmarkServiceConfig returns default configuration
 func defaultBenchmarkServiceConfig() *BenchmarkServiceConfig {
 	return &BenchmarkServiceConfig{
 		MaxConcurrentRuns:  5,
+		MaxQueueSize:       defaultMaxQueueSize,
 		DefaultTimeout:     30 * time.Minute,
 		CleanupInterval:    15 * time.Minute,
 		RetainResults:      2 * time.Hour,
@@ -491,6 +652,7 @@ func defaultBenchmarkServiceConfig() *BenchmarkServiceConfig {
 		MaxThreads:         64,
 		EnabledTools:       []string{"sysbench", "custom"},
 		ToolConfigurations: make(map[string]interface{}),
+		RawOutput:          RawOutputConfig{MaxSize: defaultRawOutputMaxSize},
 	}
 }
 
@@ -585,7 +747,7 @@ func (s *BenchmarkService) extractTableRelationships(queryResults []ports.QueryP
 						TargetTable: joinedTable,
 						Type:        "JOIN",
 						Frequency:   query.ExecutionCount,
-						AvgLatency:  query.AverageTime,
+						AvgLatency:  query.AverageTime.Std(),
 					})
 				}
 			}
@@ -605,7 +767,7 @@ type TableRelationship struct {
 
 // Missing methods for API compatibility
 
-// ListRunningBenchmarks returns all running benchmarks
+// ListRunningBenchmarks returns all running and queued benchmarks
 func (s *BenchmarkService) ListRunningBenchmarks(ctx context.Context) []*BenchmarkExecution {
 	s.runsMutex.RLock()
 	defer s.runsMutex.RUnlock()
@@ -613,7 +775,8 @@ func (s *BenchmarkService) ListRunningBenchmarks(ctx context.Context) []*Benchma
 	running := make([]*BenchmarkExecution, 0)
 	for _, execution := range s.activeRuns {
 		execution.mutex.RLock()
-		if execution.Status == ports.BenchmarkStatusRunning || execution.Status == ports.BenchmarkStatusPending {
+		if execution.Status == ports.BenchmarkStatusRunning || execution.Status == ports.BenchmarkStatusPending ||
+			execution.Status == ports.BenchmarkStatusQueued {
 			running = append(running, execution)
 		}
 		execution.mutex.RUnlock()
@@ -643,10 +806,13 @@ func (s *BenchmarkService) StopBenchmark(ctx context.Context, executionID string
 		return fmt.Errorf("benchmark execution not found: %s", executionID)
 	}
 
+	s.dequeue(executionID)
+
 	execution.mutex.Lock()
 	defer execution.mutex.Unlock()
 
-	if execution.Status != ports.BenchmarkStatusRunning && execution.Status != ports.BenchmarkStatusPending {
+	if execution.Status != ports.BenchmarkStatusRunning && execution.Status != ports.BenchmarkStatusPending &&
+		execution.Status != ports.BenchmarkStatusQueued {
 		return fmt.Errorf("benchmark is not running: %s", execution.Status)
 	}
 
@@ -674,8 +840,8 @@ func (s *BenchmarkService) createPerformanceNode(tableName string, query *ports.
 	return &PerformanceNode{
 		ID:              fmt.Sprintf("node-%s", tableName),
 		TableName:       tableName,
-		QueriesPerSec:   float64(query.ExecutionCount) / query.TotalTime.Seconds(),
-		AvgLatency:      float64(query.AverageTime.Milliseconds()),
+		QueriesPerSec:   safeDivide(float64(query.ExecutionCount), query.TotalTime.Std().Seconds()),
+		AvgLatency:      float64(query.AverageTime.Std().Milliseconds()),
 		TotalQueries:    query.ExecutionCount,
 		RowsProcessed:   query.RowsExamined,
 		IndexEfficiency: s.calculateIndexEfficiency(query),
@@ -690,8 +856,8 @@ func (s *BenchmarkService) updateNodeMetrics(node *PerformanceNode, query *ports
 
 	// Recalculate averages
 	totalLatency := (node.AvgLatency * float64(node.TotalQueries-query.ExecutionCount)) +
-		float64(query.TotalTime.Milliseconds())
-	node.AvgLatency = totalLatency / float64(node.TotalQueries)
+		float64(query.TotalTime.Std().Milliseconds())
+	node.AvgLatency = safeDivide(totalLatency, float64(node.TotalQueries))
 
 	// Update derived metrics
 	node.HotspotScore = s.calculateHotspotScore(query)
@@ -711,7 +877,7 @@ func (s *BenchmarkService) createPerformanceEdges(graph *PerformanceEnhancedGrap
 				TargetTable:     joinedTable,
 				RelationType:    "JOIN",
 				QueryFrequency:  float64(query.ExecutionCount),
-				AvgLatency:      float64(query.AverageTime.Milliseconds()),
+				AvgLatency:      float64(query.AverageTime.Std().Milliseconds()),
 				LoadFactor:      s.calculateLoadFactor(query),
 				PerformanceRank: s.calculatePerformanceRank(query),
 			}
@@ -727,15 +893,15 @@ func (s *BenchmarkService) calculateIndexEfficiency(query *ports.QueryPerformanc
 	if query.RowsReturned == 0 {
 		return 0.0
 	}
-	return float64(query.RowsReturned) / float64(query.RowsExamined)
+	return safeDivide(float64(query.RowsReturned), float64(query.RowsExamined))
 }
 
 func (s *BenchmarkService) calculateHotspotScore(query *ports.QueryPerformance) float64 {
 	// Simple hotspot calculation based on frequency and latency
-	frequencyScore := float64(query.ExecutionCount) / 1000.0          // normalize
-	latencyScore := float64(query.AverageTime.Milliseconds()) / 100.0 // normalize
+	frequencyScore := float64(query.ExecutionCount) / 1000.0                // normalize
+	latencyScore := float64(query.AverageTime.Std().Milliseconds()) / 100.0 // normalize
 
-	score := (frequencyScore * 0.6) + (latencyScore * 0.4)
+	score := finite((frequencyScore * 0.6) + (latencyScore * 0.4))
 	if score > 100.0 {
 		return 100.0
 	}
@@ -744,11 +910,12 @@ func (s *BenchmarkService) calculateHotspotScore(query *ports.QueryPerformance)
 
 func (s *BenchmarkService) calculateLoadFactor(query *ports.QueryPerformance) float64 {
 	// Calculate load based on frequency and resource usage
-	return float64(query.ExecutionCount*query.RowsExamined) / 10000.0
+	// Multiply as floats so large counters cannot overflow int64
+	return finite(float64(query.ExecutionCount) * float64(query.RowsExamined) / 10000.0)
 }
 
 func (s *BenchmarkService) calculatePerformanceRank(query *ports.QueryPerformance) string {
-	avgLatencyMs := float64(query.AverageTime.Milliseconds())
+	avgLatencyMs := float64(query.AverageTime.Std().Milliseconds())
 
 	if avgLatencyMs < 50 {
 		return "FAST"
@@ -0,0 +1,191 @@
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedBenchmarkTool blocks each run until it is released and records the order runs start in
+type gatedBenchmarkTool struct {
+	mutex   sync.Mutex
+	started []string
+	release map[string]chan struct{}
+}
+
+func newGatedBenchmarkTool() *gatedBenchmarkTool {
+	return &gatedBenchmarkTool{release: make(map[string]chan struct{})}
+}
+
+func (g *gatedBenchmarkTool) gate(testType string) chan struct{} {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if _, ok := g.release[testType]; !ok {
+		g.release[testType] = make(chan struct{})
+	}
+	return g.release[testType]
+}
+
+func (g *gatedBenchmarkTool) Execute(ctx context.Context, config ports.BenchmarkConfig) (*ports.BenchmarkResult, error) {
+	g.mutex.Lock()
+	g.started = append(g.started, config.TestType)
+	g.mutex.Unlock()
+
+	select {
+	case <-g.gate(config.TestType):
+		return &ports.BenchmarkResult{TestType: config.TestType, EndTime: time.Now()}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *gatedBenchmarkTool) Started() []string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return append([]string(nil), g.started...)
+}
+
+func (g *gatedBenchmarkTool) Validate(config ports.BenchmarkConfig) error { return nil }
+func (g *gatedBenchmarkTool) GetSupportedTests() []string                 { return nil }
+func (g *gatedBenchmarkTool) IsAvailable() bool                           { return true }
+func (g *gatedBenchmarkTool) GetVersion() (string, error)                 { return "test", nil }
+
+func newQueueingBenchmarkService(t *testing.T, limit int, queue bool) (*BenchmarkService, *gatedBenchmarkTool) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	config := defaultBenchmarkServiceConfig()
+	config.MaxConcurrentRuns = limit
+	config.QueueEnabled = queue
+	config.MaxQueueSize = 2
+
+	service := NewBenchmarkService(nil, nil, nil, nil, logger, config)
+	tool := newGatedBenchmarkTool()
+	require.NoError(t, service.RegisterBenchmarkTool("gated", tool))
+	return service, tool
+}
+
+func benchmarkStatus(t *testing.T, service *BenchmarkService, id string) ports.BenchmarkStatus {
+	result, err := service.GetBenchmarkResult(id)
+	require.NoError(t, err)
+	return result.Status
+}
+
+func TestExecuteBenchmark_RejectsBeyondLimitWithoutQueue(t *testing.T) {
+	service, tool := newQueueingBenchmarkService(t, 1, false)
+
+	_, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "run-1"}, "gated")
+	require.NoError(t, err)
+	_, err = service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "run-2"}, "gated")
+	assert.ErrorContains(t, err, "maximum concurrent runs (1) exceeded")
+
+	close(tool.gate("run-1"))
+}
+
+func TestExecuteBenchmark_QueuesExtraSubmissionsInOrder(t *testing.T) {
+	const limit = 2
+	service, tool := newQueueingBenchmarkService(t, limit, true)
+
+	ids := make([]string, limit+2)
+	for i := range ids {
+		id, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: fmt.Sprintf("run-%d", i+1)}, "gated")
+		require.NoError(t, err)
+		ids[i] = id
+	}
+
+	assert.Equal(t, 0, service.QueuePosition(ids[0]))
+	assert.Equal(t, 0, service.QueuePosition(ids[1]))
+	assert.Equal(t, 1, service.QueuePosition(ids[2]))
+	assert.Equal(t, 2, service.QueuePosition(ids[3]))
+	assert.Equal(t, ports.BenchmarkStatusQueued, benchmarkStatus(t, service, ids[3]))
+
+	_, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "overflow"}, "gated")
+	assert.ErrorContains(t, err, "queue is full")
+
+	require.Eventually(t, func() bool { return len(tool.Started()) == limit }, time.Second, 5*time.Millisecond)
+
+	close(tool.gate("run-1"))
+	require.Eventually(t, func() bool { return len(tool.Started()) == limit+1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 0, service.QueuePosition(ids[2]))
+	assert.Equal(t, 1, service.QueuePosition(ids[3]), "remaining entry moves up")
+
+	close(tool.gate("run-2"))
+	close(tool.gate("run-3"))
+	close(tool.gate("run-4"))
+	require.Eventually(t, func() bool {
+		for _, id := range ids {
+			if benchmarkStatus(t, service, id) != ports.BenchmarkStatusCompleted {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, []string{"run-3", "run-4"}, tool.Started()[limit:], "queued runs start in submission order")
+}
+
+func TestCancelBenchmark_RemovesQueuedRun(t *testing.T) {
+	service, tool := newQueueingBenchmarkService(t, 1, true)
+
+	first, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "run-1"}, "gated")
+	require.NoError(t, err)
+	queued, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "run-2"}, "gated")
+	require.NoError(t, err)
+
+	require.NoError(t, service.CancelBenchmark(queued))
+	assert.Equal(t, 0, service.QueuePosition(queued))
+	assert.Equal(t, ports.BenchmarkStatusCancelled, benchmarkStatus(t, service, queued))
+
+	close(tool.gate("run-1"))
+	require.Eventually(t, func() bool {
+		return benchmarkStatus(t, service, first) == ports.BenchmarkStatusCompleted
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"run-1"}, tool.Started(), "cancelled run never starts")
+}
+
+func TestCreatePerformanceGraph_ZeroDurationAndRowsStayFinite(t *testing.T) {
+	service, _ := newQueueingBenchmarkService(t, 1, false)
+
+	result := &ports.BenchmarkResult{
+		ID:      "zero",
+		Metrics: &ports.PerformanceMetrics{},
+		QueryResults: []ports.QueryPerformance{
+			{SourceTables: []string{"users"}, JoinedTables: []string{"teams"}},
+			{SourceTables: []string{"users"}, ExecutionCount: 0, RowsExamined: 0, TotalTime: 0},
+			{SourceTables: []string{"orders"}, ExecutionCount: 12, RowsReturned: 3, TotalTime: 0},
+			{SourceTables: []string{"events"}, ExecutionCount: math.MaxInt64, RowsExamined: math.MaxInt64},
+		},
+	}
+
+	graph, err := service.CreatePerformanceGraph(context.Background(), result)
+	require.NoError(t, err)
+
+	for _, node := range graph.Nodes {
+		for name, value := range map[string]float64{
+			"queries_per_sec":  node.QueriesPerSec,
+			"avg_latency":      node.AvgLatency,
+			"hotspot_score":    node.HotspotScore,
+			"index_efficiency": node.IndexEfficiency,
+		} {
+			assert.False(t, math.IsNaN(value) || math.IsInf(value, 0), "%s of %s is %v", name, node.TableName, value)
+		}
+	}
+	for _, edge := range graph.Edges {
+		assert.False(t, math.IsNaN(edge.LoadFactor) || math.IsInf(edge.LoadFactor, 0))
+	}
+
+	encoded, err := json.Marshal(graph)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(encoded))
+}
@@ -0,0 +1,226 @@
+package performance
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+)
+
+// Benchmark result sink types
+const (
+	SinkTypeCSV         = "csv"
+	SinkTypeJSON        = "json"
+	SinkTypePushgateway = "pushgateway"
+)
+
+// defaultSinkTimeout bounds how long one sink may take to publish a result
+const defaultSinkTimeout = 10 * time.Second
+
+// BenchmarkResultSink receives every finished benchmark result
+type BenchmarkResultSink interface {
+	Name() string
+	Publish(ctx context.Context, result *ports.BenchmarkResult) error
+}
+
+// BenchmarkSinkConfig configures a result sink. Path is used by the csv and json sinks,
+// URL and Job by the pushgateway sink.
+type BenchmarkSinkConfig struct {
+	Type    string        `yaml:"type" json:"type"`
+	Path    string        `yaml:"path,omitempty" json:"path,omitempty"`
+	URL     string        `yaml:"url,omitempty" json:"url,omitempty"`
+	Job     string        `yaml:"job,omitempty" json:"job,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// NewBenchmarkResultSink creates the sink described by config
+func NewBenchmarkResultSink(config BenchmarkSinkConfig) (BenchmarkResultSink, error) {
+	switch strings.ToLower(config.Type) {
+	case SinkTypeCSV, SinkTypeJSON:
+		if config.Path == "" {
+			return nil, fmt.Errorf("%s sink requires a path", config.Type)
+		}
+		return &fileResultSink{path: config.Path, format: strings.ToLower(config.Type)}, nil
+	case SinkTypePushgateway:
+		if config.URL == "" {
+			return nil, fmt.Errorf("pushgateway sink requires a url")
+		}
+		job := config.Job
+		if job == "" {
+			job = "sql_graph_benchmark"
+		}
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = defaultSinkTimeout
+		}
+		return &pushgatewaySink{
+			url:    strings.TrimRight(config.URL, "/"),
+			job:    job,
+			client: &http.Client{Timeout: timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown benchmark sink type %q", config.Type)
+	}
+}
+
+// benchmarkCSVHeader lists the columns written by the csv sink
+var benchmarkCSVHeader = []string{
+	"id", "tool_name", "test_type", "status", "start_time", "end_time", "duration_seconds",
+	"queries_per_second", "transactions_per_second", "average_latency_ms", "percentile_95_ms",
+	"percentile_99_ms", "total_errors", "error_rate", "error",
+}
+
+// fileResultSink appends results to a file: one row per result for csv, one JSON document
+// per line for json
+type fileResultSink struct {
+	mu     sync.Mutex
+	path   string
+	format string
+}
+
+func (s *fileResultSink) Name() string { return s.format + ":" + s.path }
+
+func (s *fileResultSink) Publish(ctx context.Context, result *ports.BenchmarkResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	if s.format == SinkTypeJSON {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal benchmark result: %w", err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write %s: %w", s.path, err)
+		}
+		return nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		if err := writer.Write(benchmarkCSVHeader); err != nil {
+			return fmt.Errorf("failed to write %s: %w", s.path, err)
+		}
+	}
+	if err := writer.Write(benchmarkCSVRecord(result)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func benchmarkCSVRecord(result *ports.BenchmarkResult) []string {
+	metrics := result.Metrics
+	if metrics == nil {
+		metrics = &ports.PerformanceMetrics{}
+	}
+	formatFloat := func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) }
+
+	return []string{
+		result.ID,
+		result.ToolName,
+		result.TestType,
+		string(result.Status),
+		result.StartTime.UTC().Format(time.RFC3339),
+		result.EndTime.UTC().Format(time.RFC3339),
+		formatFloat(result.Duration.Std().Seconds()),
+		formatFloat(metrics.QueriesPerSecond),
+		formatFloat(metrics.TransactionsPerSec),
+		formatFloat(metrics.AverageLatency),
+		formatFloat(metrics.Percentile95),
+		formatFloat(metrics.Percentile99),
+		strconv.Itoa(metrics.TotalErrors),
+		formatFloat(metrics.ErrorRate),
+		result.Error,
+	}
+}
+
+// pushgatewaySink pushes result metrics to a Prometheus Pushgateway, grouped by job, tool
+// and test type so each benchmark kind keeps its latest run
+type pushgatewaySink struct {
+	url    string
+	job    string
+	client *http.Client
+}
+
+func (s *pushgatewaySink) Name() string { return "pushgateway:" + s.url }
+
+func (s *pushgatewaySink) Publish(ctx context.Context, result *ports.BenchmarkResult) error {
+	endpoint := fmt.Sprintf("%s/metrics/job/%s/tool/%s/test_type/%s", s.url,
+		url.PathEscape(s.job), url.PathEscape(labelOrUnknown(result.ToolName)), url.PathEscape(labelOrUnknown(result.TestType)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewBufferString(pushgatewayPayload(result)))
+	if err != nil {
+		return fmt.Errorf("failed to create pushgateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push benchmark metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
+// pushgatewayPayload renders the result in the Prometheus text exposition format
+func pushgatewayPayload(result *ports.BenchmarkResult) string {
+	metrics := result.Metrics
+	if metrics == nil {
+		metrics = &ports.PerformanceMetrics{}
+	}
+	succeeded := 0.0
+	if result.Status == ports.BenchmarkStatusCompleted {
+		succeeded = 1
+	}
+
+	var out strings.Builder
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(&out, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n",
+			name, help, name, name, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	gauge("benchmark_success", "Whether the benchmark completed successfully", succeeded)
+	gauge("benchmark_duration_seconds", "Benchmark run duration", result.Duration.Std().Seconds())
+	gauge("benchmark_queries_per_second", "Queries per second", metrics.QueriesPerSecond)
+	gauge("benchmark_transactions_per_second", "Transactions per second", metrics.TransactionsPerSec)
+	gauge("benchmark_latency_average_ms", "Average latency in milliseconds", metrics.AverageLatency)
+	gauge("benchmark_latency_p95_ms", "95th percentile latency in milliseconds", metrics.Percentile95)
+	gauge("benchmark_latency_p99_ms", "99th percentile latency in milliseconds", metrics.Percentile99)
+	gauge("benchmark_errors_total", "Errors during the run", float64(metrics.TotalErrors))
+	gauge("benchmark_error_rate", "Errors per query", metrics.ErrorRate)
+	gauge("benchmark_end_time_seconds", "Unix time the run ended", float64(result.EndTime.Unix()))
+	return out.String()
+}
+
+func labelOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
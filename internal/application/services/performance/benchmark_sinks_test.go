@@ -0,0 +1,156 @@
+package performance
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink forwards published results to a channel
+type recordingSink struct {
+	results chan *ports.BenchmarkResult
+}
+
+func (r *recordingSink) Name() string { return "recording" }
+
+func (r *recordingSink) Publish(ctx context.Context, result *ports.BenchmarkResult) error {
+	r.results <- result
+	return nil
+}
+
+func sampleBenchmarkResult() *ports.BenchmarkResult {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &ports.BenchmarkResult{
+		ID:        "run-1",
+		ToolName:  "sysbench",
+		TestType:  "oltp_read_only",
+		StartTime: start,
+		EndTime:   start.Add(90 * time.Second),
+		Duration:  models.Duration(90 * time.Second),
+		Status:    ports.BenchmarkStatusCompleted,
+		Metrics: &ports.PerformanceMetrics{
+			QueriesPerSecond: 1520.5,
+			AverageLatency:   4.2,
+			Percentile95:     9.8,
+			TotalErrors:      3,
+		},
+	}
+}
+
+func TestCompletedBenchmarkIsPublishedToSinks(t *testing.T) {
+	service, tool := newQueueingBenchmarkService(t, 1, false)
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	sink, err := NewBenchmarkResultSink(BenchmarkSinkConfig{Type: "json", Path: path})
+	require.NoError(t, err)
+	service.AddResultSink(sink)
+	recorder := &recordingSink{results: make(chan *ports.BenchmarkResult, 1)}
+	service.AddResultSink(recorder)
+
+	id, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "oltp"}, "gated")
+	require.NoError(t, err)
+	close(tool.gate("oltp"))
+
+	var published *ports.BenchmarkResult
+	select {
+	case published = <-recorder.results:
+	case <-time.After(2 * time.Second):
+		t.Fatal("completed benchmark was not published")
+	}
+	assert.Equal(t, id, published.ID)
+	assert.Equal(t, ports.BenchmarkStatusCompleted, published.Status)
+
+	// Sinks run in order, so the json sink has written by the time the recorder is called
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var written ports.BenchmarkResult
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, id, written.ID)
+	assert.Equal(t, "oltp", written.TestType)
+	assert.Equal(t, ports.BenchmarkStatusCompleted, written.Status)
+}
+
+func TestCSVSinkWritesHeaderOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	sink, err := NewBenchmarkResultSink(BenchmarkSinkConfig{Type: "csv", Path: path})
+	require.NoError(t, err)
+
+	result := sampleBenchmarkResult()
+	require.NoError(t, sink.Publish(context.Background(), result))
+	result.ID = "run-2"
+	result.Status = ports.BenchmarkStatusFailed
+	result.Error = "connection refused, retrying"
+	require.NoError(t, sink.Publish(context.Background(), result))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, benchmarkCSVHeader, records[0])
+	assert.Equal(t, []string{
+		"run-1", "sysbench", "oltp_read_only", "completed", "2025-03-01T12:00:00Z", "2025-03-01T12:01:30Z",
+		"90", "1520.5", "0", "4.2", "9.8", "0", "3", "0", "",
+	}, records[1])
+	assert.Equal(t, "failed", records[2][3])
+	assert.Equal(t, "connection refused, retrying", records[2][14])
+}
+
+func TestPushgatewaySinkPushesTextFormat(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewBenchmarkResultSink(BenchmarkSinkConfig{Type: "pushgateway", URL: server.URL + "/", Job: "ci"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), sampleBenchmarkResult()))
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/ci/tool/sysbench/test_type/oltp_read_only", path)
+	assert.Contains(t, body, "# TYPE benchmark_queries_per_second gauge\nbenchmark_queries_per_second 1520.5\n")
+	assert.Contains(t, body, "benchmark_success 1\n")
+	assert.Contains(t, body, "benchmark_duration_seconds 90\n")
+	assert.Contains(t, body, "benchmark_errors_total 3\n")
+}
+
+func TestPushgatewaySinkReportsRejectedPush(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, err := NewBenchmarkResultSink(BenchmarkSinkConfig{Type: "pushgateway", URL: server.URL})
+	require.NoError(t, err)
+	err = sink.Publish(context.Background(), sampleBenchmarkResult())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
+
+func TestNewBenchmarkResultSinkValidatesConfig(t *testing.T) {
+	for _, config := range []BenchmarkSinkConfig{
+		{Type: "csv"},
+		{Type: "pushgateway"},
+		{Type: "s3", Path: "bucket"},
+	} {
+		_, err := NewBenchmarkResultSink(config)
+		assert.Error(t, err, config.Type)
+	}
+}
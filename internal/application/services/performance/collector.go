@@ -0,0 +1,19 @@
+package performance
+
+import (
+	"database/sql"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewPerformanceCollector creates the collector for config.Engine: a PostgreSQLStatsAdapter
+// for PostgreSQL and a PerformanceSchemaAdapter for MySQL or an unset engine
+func NewPerformanceCollector(db *sql.DB, logger *logrus.Logger, config *PerformanceSchemaConfig) ports.PerformanceCollectorPort {
+	if config != nil && config.Engine == models.DatabaseTypePostgreSQL {
+		return NewPostgreSQLStatsAdapter(db, logger, config)
+	}
+	return NewPerformanceSchemaAdapter(db, logger, config)
+}
@@ -0,0 +1,128 @@
+package performance
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statsStub answers the availability check and the PostgreSQL statistics views with fixed
+// rows, recording the queries run on each DSN
+var statsStub = struct {
+	mu      sync.Mutex
+	queries map[string][]string
+}{queries: make(map[string][]string)}
+
+func statsStubQuery(dsn, query string, args []driver.NamedValue) (driver.Rows, error) {
+	statsStub.mu.Lock()
+	statsStub.queries[dsn] = append(statsStub.queries[dsn], query)
+	statsStub.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "information_schema.tables"):
+		return availableRows(), nil
+	case strings.Contains(query, "pg_stat_statements"):
+		return &valueRows{columns: make([]string, 9), rows: [][]driver.Value{
+			{"public", "-4711", "SELECT * FROM orders WHERE customer_id = $1", int64(40), 500.0, 2.0, 12.5, 80.0, int64(120)},
+		}}, nil
+	case strings.Contains(query, "pg_stat_user_tables"):
+		return &valueRows{columns: make([]string, 6), rows: [][]driver.Value{
+			{"public", "orders", int64(900), int64(30), int64(20), int64(10)},
+		}}, nil
+	}
+	return emptyRows{}, nil
+}
+
+// newStubCollector creates the collector for engine against a DSN named after the test
+func newStubCollector(t *testing.T, engine models.DatabaseType) (ports.PerformanceCollectorPort, func() []string) {
+	db := openStubDB(t, &stubDriver{query: statsStubQuery}, t.Name())
+
+	logger, _ := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	config.Engine = engine
+	config.MinExecutionCount = 1
+	config.MinAvgLatency = 1
+	collector := NewPerformanceCollector(db, logger, config)
+	t.Cleanup(func() { collector.Close() })
+
+	return collector, func() []string {
+		statsStub.mu.Lock()
+		defer statsStub.mu.Unlock()
+		return append([]string(nil), statsStub.queries[t.Name()]...)
+	}
+}
+
+func TestNewPerformanceCollectorSelectsAdapterByDatabaseType(t *testing.T) {
+	for _, tc := range []struct {
+		engine   models.DatabaseType
+		expected any
+	}{
+		{"", &PerformanceSchemaAdapter{}},
+		{models.DatabaseTypeMySQL, &PerformanceSchemaAdapter{}},
+		{models.DatabaseTypePostgreSQL, &PostgreSQLStatsAdapter{}},
+	} {
+		t.Run(string(tc.engine), func(t *testing.T) {
+			collector, _ := newStubCollector(t, tc.engine)
+			assert.IsType(t, tc.expected, collector)
+			assert.True(t, collector.IsConnected())
+		})
+	}
+}
+
+func TestPostgreSQLCollectorReadsStatisticsViews(t *testing.T) {
+	collector, queries := newStubCollector(t, models.DatabaseTypePostgreSQL)
+
+	data, err := collector.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, data.StatementStats, 1)
+	stmt := data.StatementStats[0]
+	assert.Equal(t, "SELECT * FROM orders WHERE customer_id = $1", stmt.DigestText)
+	assert.Equal(t, int64(40), stmt.CountStar)
+	assert.Equal(t, models.Duration(12500*time.Microsecond), stmt.AvgTimerWait, "milliseconds are converted")
+	assert.Equal(t, models.Duration(500*time.Millisecond), stmt.SumTimerWait)
+
+	require.Len(t, data.TableIOStats, 1)
+	table := data.TableIOStats[0]
+	assert.Equal(t, "orders", table.TableName)
+	assert.Equal(t, int64(900), table.CountRead)
+	assert.Equal(t, int64(60), table.CountWrite)
+
+	for _, query := range queries() {
+		assert.NotContains(t, query, "performance_schema")
+	}
+}
+
+func TestMySQLCollectorReadsPerformanceSchema(t *testing.T) {
+	collector, queries := newStubCollector(t, models.DatabaseTypeMySQL)
+
+	_, err := collector.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	joined := strings.Join(queries(), "\n")
+	assert.Contains(t, joined, "events_statements_summary_by_digest")
+	assert.NotContains(t, joined, "pg_stat")
+}
+
+func TestNewPostgreSQLStatsAdapterCopiesConfig(t *testing.T) {
+	db := openStubDB(t, &stubDriver{query: statsStubQuery}, t.Name())
+
+	logger, _ := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	adapter := NewPostgreSQLStatsAdapter(db, logger, config)
+	t.Cleanup(func() { adapter.Close() })
+
+	assert.Empty(t, config.Engine, "the caller's config is left unchanged")
+	assert.Equal(t, models.DatabaseTypePostgreSQL, adapter.config.Engine)
+	assert.NotSame(t, config, adapter.config)
+}
@@ -0,0 +1,126 @@
+package performance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// truncatedDigestSuffix ends digest text that was cut off at max_digest_length
+const truncatedDigestSuffix = "..."
+
+// digestTextMissing reports whether digest text is absent or truncated, so the tables and
+// query type read from it cannot be trusted
+func digestTextMissing(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || strings.HasSuffix(text, truncatedDigestSuffix)
+}
+
+// recoverDigestTexts replaces missing or truncated digest text with the most recent statement
+// of the same digest in events_statements_history, its literals replaced by ?. Statements
+// without a recent execution keep their text. It returns how many statements were missing
+// text and how many of them were recovered.
+func (p *PerformanceSchemaAdapter) recoverDigestTexts(ctx context.Context, statements []StatementStatistic) (missing, recovered int, err error) {
+	var digests []interface{}
+	for _, stmt := range statements {
+		if digestTextMissing(stmt.DigestText) && stmt.Digest != "" {
+			digests = append(digests, stmt.Digest)
+		}
+	}
+	missing = len(digests)
+	if missing == 0 || !p.config.DigestTextFallback {
+		return missing, 0, nil
+	}
+
+	query := `
+		SELECT digest, sql_text
+		FROM performance_schema.events_statements_history
+		WHERE sql_text IS NOT NULL
+		  AND digest IN (?` + strings.Repeat(", ?", len(digests)-1) + `)
+		ORDER BY timer_start DESC`
+
+	rows, err := p.db.QueryContext(ctx, query, digests...)
+	if err != nil {
+		return missing, 0, fmt.Errorf("failed to query statement history: %w", err)
+	}
+	defer rows.Close()
+
+	texts := make(map[string]string, len(digests))
+	for rows.Next() {
+		var digest, sqlText string
+		if err := rows.Scan(&digest, &sqlText); err != nil {
+			p.logger.WithError(err).Debug("Failed to scan statement history row")
+			continue
+		}
+		// Rows are newest first; keep the latest complete statement of each digest
+		if _, ok := texts[digest]; !ok && !digestTextMissing(sqlText) {
+			texts[digest] = maskLiterals(sqlText)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return missing, 0, fmt.Errorf("failed to read statement history: %w", err)
+	}
+
+	for i := range statements {
+		if text, ok := texts[statements[i].Digest]; ok && digestTextMissing(statements[i].DigestText) {
+			statements[i].DigestText = text
+			statements[i].DigestTextRecovered = true
+			recovered++
+		}
+	}
+	return missing, recovered, nil
+}
+
+// warnDigestTextUnavailable logs, once per adapter, that statement digests came without
+// usable text, so tables cannot be mapped for them. Callers hold p.mutex.
+func (p *PerformanceSchemaAdapter) warnDigestTextUnavailable(missing, recovered int) {
+	if missing == 0 || p.digestTextWarned {
+		return
+	}
+	p.digestTextWarned = true
+
+	fields := logrus.Fields{
+		"statements": missing,
+		"recovered":  recovered,
+	}
+	if !p.config.DigestTextFallback {
+		p.logger.WithFields(fields).Warn("Statement digest text is missing or truncated; " +
+			"check performance_schema_digests_size and max_digest_length, or enable the digest text fallback")
+		return
+	}
+	p.logger.WithFields(fields).Warn("Statement digest text is missing or truncated; " +
+		"check performance_schema_digests_size and max_digest_length. " +
+		"SQL is recovered from events_statements_history where the statement ran recently")
+}
+
+// maskLiterals replaces string and numeric literals in SQL text with ? as a digest does,
+// so statements recovered from the history do not expose the values they ran with
+func maskLiterals(sqlText string) string {
+	var b strings.Builder
+	b.Grow(len(sqlText))
+
+	for i := 0; i < len(sqlText); {
+		c := sqlText[i]
+		switch {
+		case c == '\'':
+			_, next := readQuoted(sqlText, i, c)
+			b.WriteByte('?')
+			i = next
+		case c == '`' || c == '"':
+			_, next := readQuoted(sqlText, i, c)
+			b.WriteString(sqlText[i:next])
+			i = next
+		case c >= '0' && c <= '9' && (i == 0 || !isWordByte(sqlText[i-1])):
+			for i < len(sqlText) && (isWordByte(sqlText[i]) || sqlText[i] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,171 @@
+package performance
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyStub serves digest summaries and statement history rows from memory
+var historyStub struct {
+	mu             sync.Mutex
+	digests        [][]driver.Value
+	history        [][]driver.Value
+	historyQueries int
+}
+
+func historyStubQuery(dsn, query string, args []driver.NamedValue) (driver.Rows, error) {
+	historyStub.mu.Lock()
+	defer historyStub.mu.Unlock()
+	switch {
+	case strings.Contains(query, "events_statements_summary_by_digest"):
+		return &valueRows{columns: make([]string, 21), rows: historyStub.digests}, nil
+	case strings.Contains(query, "events_statements_history"):
+		historyStub.historyQueries++
+		wanted := make(map[string]bool, len(args))
+		for _, arg := range args {
+			wanted[arg.Value.(string)] = true
+		}
+		var rows [][]driver.Value
+		for _, row := range historyStub.history {
+			if wanted[row[0].(string)] {
+				rows = append(rows, row)
+			}
+		}
+		return &valueRows{columns: []string{"digest", "sql_text"}, rows: rows}, nil
+	}
+	return emptyRows{}, nil
+}
+
+func digestRow(digest string, digestText driver.Value) []driver.Value {
+	now := time.Now()
+	row := []driver.Value{"shop", digest, digestText}
+	for i := 0; i < 16; i++ {
+		row = append(row, int64(1000))
+	}
+	return append(row, now, now)
+}
+
+func newHistoryTestAdapter(t *testing.T, fallback bool) (*PerformanceSchemaAdapter, *test.Hook) {
+	historyStub.historyQueries = 0
+	historyStub.digests = [][]driver.Value{
+		digestRow("d-orders", nil),
+		digestRow("d-customers", ""),
+		digestRow("d-truncated", "SELECT `o`.`id` FROM `orders` `o` JOIN ..."),
+		digestRow("d-products", "SELECT * FROM `products` WHERE `id` = ?"),
+		digestRow("d-unknown", nil),
+	}
+	historyStub.history = [][]driver.Value{
+		{"d-orders", "SELECT * FROM orders WHERE id = 42 AND status = 'paid'"},
+		{"d-orders", "SELECT * FROM orders WHERE id = 7"},
+		{"d-customers", "UPDATE `customers` SET name = 'Ada' WHERE id = 1"},
+		{"d-truncated", "SELECT o.id FROM orders o JOIN order_items i ON i.order_id = o.id"},
+	}
+
+	db := openStubDB(t, &stubDriver{query: historyStubQuery}, "")
+
+	logger, hook := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	config.DigestTextFallback = fallback
+	return &PerformanceSchemaAdapter{statsCollector: &statsCollector{db: db, logger: logger, config: config, isConnected: true}}, hook
+}
+
+func digestWarnings(hook *test.Hook) []*logrus.Entry {
+	var warnings []*logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "digest text") {
+			warnings = append(warnings, entry)
+		}
+	}
+	return warnings
+}
+
+func statementsByDigest(statements []StatementStatistic) map[string]StatementStatistic {
+	byDigest := make(map[string]StatementStatistic, len(statements))
+	for _, stmt := range statements {
+		byDigest[stmt.Digest] = stmt
+	}
+	return byDigest
+}
+
+func TestMissingDigestTextIsRecoveredFromHistory(t *testing.T) {
+	p, hook := newHistoryTestAdapter(t, true)
+
+	data, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+	statements := statementsByDigest(data.StatementStats)
+	require.Len(t, statements, 5)
+
+	orders := statements["d-orders"]
+	assert.True(t, orders.DigestTextRecovered)
+	assert.Equal(t, "SELECT * FROM orders WHERE id = ? AND status = ?", orders.DigestText,
+		"the latest execution is used, without its literals")
+
+	truncated := statements["d-truncated"]
+	assert.True(t, truncated.DigestTextRecovered)
+	assert.Equal(t, []string{"orders", "order_items"}, ParseDigest(truncated.DigestText, false).Tables)
+
+	assert.False(t, statements["d-products"].DigestTextRecovered)
+	assert.False(t, statements["d-unknown"].DigestTextRecovered, "no recent execution to recover from")
+	assert.Empty(t, statements["d-unknown"].DigestText)
+
+	performance := p.ConvertToQueryPerformance(data)
+	types := make(map[string]string)
+	for _, perf := range performance {
+		types[perf.QueryPattern] = perf.QueryType
+	}
+	assert.Equal(t, "UPDATE", types["UPDATE `customers` SET name = ? WHERE id = ?"])
+
+	warnings := digestWarnings(hook)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, 4, warnings[0].Data["statements"])
+	assert.Equal(t, 3, warnings[0].Data["recovered"])
+	assert.Contains(t, warnings[0].Message, "performance_schema_digests_size")
+
+	// The capability warning is logged once, not on every collection
+	_, err = p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, digestWarnings(hook), 1)
+}
+
+func TestDigestTextFallbackCanBeDisabled(t *testing.T) {
+	p, hook := newHistoryTestAdapter(t, false)
+
+	data, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, historyStub.historyQueries)
+	statements := statementsByDigest(data.StatementStats)
+	assert.Empty(t, statements["d-orders"].DigestText)
+	assert.False(t, statements["d-orders"].DigestTextRecovered)
+
+	warnings := digestWarnings(hook)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, 0, warnings[0].Data["recovered"])
+	assert.Contains(t, warnings[0].Message, "enable the digest text fallback")
+}
+
+func TestRecoveredDigestTextIsFocused(t *testing.T) {
+	p, _ := newHistoryTestAdapter(t, true)
+	p.config.FocusedTables = []string{"customers"}
+
+	data, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, data.StatementStats, 1)
+	assert.Equal(t, "d-customers", data.StatementStats[0].Digest)
+}
+
+func TestMaskLiterals(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM `t1` WHERE a = ? AND b IN (?, ?) AND c = ?",
+		maskLiterals("SELECT *\n  FROM `t1` WHERE a = 'it''s' AND b IN (1, 2.5) AND c = 'x\\'y'"))
+	assert.Equal(t, "SELECT t2.col FROM t2", maskLiterals("SELECT t2.col FROM t2"))
+}
@@ -0,0 +1,397 @@
+package performance
+
+import (
+	"strings"
+)
+
+// DigestInfo is what can be learned about a statement from its normalized digest text
+type DigestInfo struct {
+	QueryType string
+	Tables    []string
+	CTENames  []string
+}
+
+type digestTokenKind int
+
+const (
+	tokenWord digestTokenKind = iota
+	tokenQuotedIdent
+	tokenString
+	tokenPunct
+	tokenOther
+)
+
+type digestToken struct {
+	kind  digestTokenKind
+	value string
+}
+
+// statementKeywords maps leading keywords to the query type reported for them
+var statementKeywords = map[string]string{
+	"select": "SELECT",
+	"insert": "INSERT",
+	"update": "UPDATE",
+	"delete": "DELETE",
+	"create": "CREATE",
+	"drop":   "DROP",
+	"alter":  "ALTER",
+}
+
+// aliasStopWords are keywords that may follow a table reference and are never an alias
+var aliasStopWords = map[string]bool{
+	"where": true, "join": true, "inner": true, "left": true, "right": true, "outer": true,
+	"cross": true, "natural": true, "straight_join": true, "on": true, "using": true,
+	"set": true, "group": true, "order": true, "limit": true, "having": true, "union": true,
+	"except": true, "intersect": true, "values": true, "value": true, "select": true,
+	"window": true, "for": true, "lock": true, "into": true, "force": true, "use": true,
+	"ignore": true, "partition": true, "procedure": true, "from": true, "default": true,
+}
+
+// clauseKeywords are words that open a parenthesized subquery or list rather than a function call
+var clauseKeywords = map[string]bool{
+	"select": true, "from": true, "join": true, "in": true, "exists": true, "as": true,
+	"on": true, "and": true, "or": true, "not": true, "any": true, "all": true, "some": true,
+	"where": true, "values": true, "value": true, "union": true, "lateral": true, "with": true,
+	"having": true, "into": true, "set": true, "then": true, "else": true, "when": true,
+	"except": true, "intersect": true, "is": true, "like": true,
+}
+
+// ParseDigest extracts the query type and referenced tables from a Performance Schema
+// digest text. Tables in JOINs, subqueries and CTE bodies are included; CTE names, derived
+// tables and DUAL are not. Table names are lower-cased; schema qualifiers are kept only
+// when qualifyTables is set.
+func ParseDigest(digestText string, qualifyTables bool) DigestInfo {
+	info := DigestInfo{QueryType: "UNKNOWN", Tables: []string{}}
+
+	tokens := tokenizeDigest(digestText)
+	if len(tokens) == 0 {
+		return info
+	}
+
+	info.CTENames = collectCTENames(tokens)
+	info.QueryType = classifyDigest(tokens)
+
+	excluded := map[string]bool{"dual": true}
+	for _, name := range info.CTENames {
+		excluded[name] = true
+	}
+
+	seen := make(map[string]bool)
+	addTable := func(schema, table string) {
+		if table == "" || (schema == "" && excluded[table]) {
+			return
+		}
+		name := table
+		if qualifyTables && schema != "" {
+			name = schema + "." + table
+		}
+		if !seen[name] {
+			seen[name] = true
+			info.Tables = append(info.Tables, name)
+		}
+	}
+
+	// Track whether each open parenthesis belongs to a function call, where FROM is an
+	// argument keyword (EXTRACT(YEAR FROM ...), TRIM(... FROM ...)) rather than a clause
+	var callStack []bool
+	inCall := func() bool { return len(callStack) > 0 && callStack[len(callStack)-1] }
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		switch {
+		case tok.kind == tokenPunct && tok.value == "(":
+			isCall := i > 0 && tokens[i-1].kind == tokenWord && !clauseKeywords[tokens[i-1].value]
+			callStack = append(callStack, isCall)
+			continue
+		case tok.kind == tokenPunct && tok.value == ")":
+			if len(callStack) > 0 {
+				callStack = callStack[:len(callStack)-1]
+			}
+			continue
+		case tok.kind != tokenWord:
+			continue
+		}
+
+		switch tok.value {
+		case "from":
+			if inCall() {
+				continue
+			}
+			i = readTableList(tokens, i+1, true, addTable)
+		case "join", "straight_join", "into":
+			i = readTableList(tokens, i+1, false, addTable)
+		case "update":
+			if i == 0 || isStatementStart(tokens, i) {
+				i = readTableList(tokens, i+1, true, addTable)
+			}
+		case "table":
+			if i > 0 && tokens[i-1].kind == tokenWord {
+				switch tokens[i-1].value {
+				case "create", "alter", "drop", "truncate", "temporary", "rename":
+					i = skipIfExists(tokens, i+1) - 1
+					i = readTableList(tokens, i+1, false, addTable)
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// readTableList reads one table reference (or a comma separated list when allowList is set)
+// starting at position i and returns the index of the last consumed token
+func readTableList(tokens []digestToken, i int, allowList bool, add func(schema, table string)) int {
+	for i < len(tokens) {
+		if isPunct(tokens[i], "(") {
+			// Derived table or parenthesized join; its contents are scanned by the caller
+			return i - 1
+		}
+
+		schema, table, next, ok := readQualifiedName(tokens, i)
+		if !ok {
+			return i - 1
+		}
+		add(schema, table)
+		i = skipAlias(tokens, next)
+
+		if !allowList || i >= len(tokens) || !isPunct(tokens[i], ",") {
+			return i - 1
+		}
+		i++
+	}
+	return i - 1
+}
+
+func readQualifiedName(tokens []digestToken, i int) (schema, table string, next int, ok bool) {
+	if i >= len(tokens) || !isIdentifier(tokens[i]) {
+		return "", "", i, false
+	}
+	table = identifierValue(tokens[i])
+	next = i + 1
+
+	if next+1 < len(tokens) && isPunct(tokens[next], ".") && isIdentifier(tokens[next+1]) {
+		schema = table
+		table = identifierValue(tokens[next+1])
+		next += 2
+	}
+	return schema, table, next, true
+}
+
+func skipAlias(tokens []digestToken, i int) int {
+	if i >= len(tokens) {
+		return i
+	}
+	if tokens[i].kind == tokenWord && tokens[i].value == "as" {
+		return i + 2
+	}
+	if tokens[i].kind == tokenQuotedIdent {
+		return i + 1
+	}
+	if tokens[i].kind == tokenWord {
+		if !aliasStopWords[tokens[i].value] {
+			return i + 1
+		}
+	}
+	return i
+}
+
+func skipIfExists(tokens []digestToken, i int) int {
+	for i < len(tokens) && tokens[i].kind == tokenWord {
+		switch tokens[i].value {
+		case "if", "not", "exists":
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// collectCTENames returns the names defined in a leading WITH clause
+func collectCTENames(tokens []digestToken) []string {
+	i := skipOpenParens(tokens, 0)
+	if i >= len(tokens) || tokens[i].kind != tokenWord || tokens[i].value != "with" {
+		return nil
+	}
+	i++
+	if i < len(tokens) && tokens[i].kind == tokenWord && tokens[i].value == "recursive" {
+		i++
+	}
+
+	var names []string
+	for i < len(tokens) && isIdentifier(tokens[i]) {
+		names = append(names, identifierValue(tokens[i]))
+		i++
+
+		// Optional column list, then AS ( body )
+		if i < len(tokens) && isPunct(tokens[i], "(") {
+			i = skipParens(tokens, i)
+		}
+		if i < len(tokens) && tokens[i].kind == tokenWord && tokens[i].value == "as" {
+			i++
+		}
+		if i < len(tokens) && isPunct(tokens[i], "(") {
+			i = skipParens(tokens, i)
+		}
+		if i < len(tokens) && isPunct(tokens[i], ",") {
+			i++
+			continue
+		}
+		break
+	}
+	return names
+}
+
+// classifyDigest reports the type of the main statement, looking past a leading WITH clause
+// and opening parentheses
+func classifyDigest(tokens []digestToken) string {
+	i := skipOpenParens(tokens, 0)
+	if i >= len(tokens) || tokens[i].kind != tokenWord {
+		return "OTHER"
+	}
+
+	if tokens[i].value == "with" {
+		// The main statement is the first statement keyword at the top nesting level
+		depth := 0
+		for j := i + 1; j < len(tokens); j++ {
+			switch {
+			case isPunct(tokens[j], "("):
+				depth++
+			case isPunct(tokens[j], ")"):
+				depth--
+			case depth == 0 && tokens[j].kind == tokenWord:
+				if queryType, ok := statementKeywords[tokens[j].value]; ok {
+					return queryType
+				}
+			}
+		}
+		return "OTHER"
+	}
+
+	if queryType, ok := statementKeywords[tokens[i].value]; ok {
+		return queryType
+	}
+	return "OTHER"
+}
+
+// isStatementStart reports whether the word at i begins a statement rather than appearing
+// inside one (e.g. ON DUPLICATE KEY UPDATE)
+func isStatementStart(tokens []digestToken, i int) bool {
+	prev := tokens[i-1]
+	if isPunct(prev, "(") || isPunct(prev, ";") {
+		return true
+	}
+	return isPunct(prev, ")") && classifyDigest(tokens) != "INSERT"
+}
+
+func skipOpenParens(tokens []digestToken, i int) int {
+	for i < len(tokens) && isPunct(tokens[i], "(") {
+		i++
+	}
+	return i
+}
+
+// skipParens returns the index just past the parenthesis group opening at i
+func skipParens(tokens []digestToken, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		if isPunct(tokens[i], "(") {
+			depth++
+		} else if isPunct(tokens[i], ")") {
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+func isPunct(tok digestToken, value string) bool {
+	return tok.kind == tokenPunct && tok.value == value
+}
+
+func isIdentifier(tok digestToken) bool {
+	return tok.kind == tokenQuotedIdent || (tok.kind == tokenWord && !clauseKeywords[tok.value] && tok.value != "?")
+}
+
+func identifierValue(tok digestToken) string {
+	return strings.ToLower(tok.value)
+}
+
+// tokenizeDigest splits digest text into words, quoted identifiers, string literals and
+// punctuation, dropping comments. Unquoted words are lower-cased.
+func tokenizeDigest(text string) []digestToken {
+	var tokens []digestToken
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(text[i:], "-- "), c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '`' || c == '"':
+			value, next := readQuoted(text, i, c)
+			tokens = append(tokens, digestToken{kind: tokenQuotedIdent, value: value})
+			i = next
+		case c == '\'':
+			value, next := readQuoted(text, i, c)
+			tokens = append(tokens, digestToken{kind: tokenString, value: value})
+			i = next
+		case c == '(' || c == ')' || c == ',' || c == '.' || c == ';':
+			tokens = append(tokens, digestToken{kind: tokenPunct, value: string(c)})
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(text) && isWordByte(text[i]) {
+				i++
+			}
+			tokens = append(tokens, digestToken{kind: tokenWord, value: strings.ToLower(text[start:i])})
+		default:
+			tokens = append(tokens, digestToken{kind: tokenOther, value: string(c)})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// readQuoted reads a quoted token starting at i, where a doubled quote is an escaped quote
+func readQuoted(text string, i int, quote byte) (string, int) {
+	var b strings.Builder
+	i++
+	for i < len(text) {
+		if text[i] == quote {
+			if i+1 < len(text) && text[i+1] == quote {
+				b.WriteByte(quote)
+				i += 2
+				continue
+			}
+			return b.String(), i + 1
+		}
+		if text[i] == '\\' && quote == '\'' && i+1 < len(text) {
+			b.WriteByte(text[i+1])
+			i += 2
+			continue
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String(), i
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '?' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
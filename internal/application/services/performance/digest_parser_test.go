@@ -0,0 +1,135 @@
+package performance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDigest(t *testing.T) {
+	tests := []struct {
+		name      string
+		digest    string
+		queryType string
+		tables    []string
+	}{
+		{
+			name:      "simple select with quoted identifiers",
+			digest:    "SELECT `id` , `name` FROM `users` WHERE `id` = ?",
+			queryType: "SELECT",
+			tables:    []string{"users"},
+		},
+		{
+			name:      "cte is classified by its main statement and not reported as a table",
+			digest:    "WITH `recent_orders` AS ( SELECT * FROM `orders` WHERE `created_at` > ? ) SELECT `u` . `name` , COUNT ( * ) FROM `recent_orders` `r` JOIN `users` `u` ON `u` . `id` = `r` . `user_id` GROUP BY `u` . `name`",
+			queryType: "SELECT",
+			tables:    []string{"orders", "users"},
+		},
+		{
+			name:      "recursive cte with column list",
+			digest:    "WITH RECURSIVE `tree` ( `id` , `parent_id` ) AS ( SELECT `id` , `parent_id` FROM `categories` WHERE `parent_id` IS NULL UNION ALL SELECT `c` . `id` , `c` . `parent_id` FROM `categories` `c` JOIN `tree` `t` ON `c` . `parent_id` = `t` . `id` ) SELECT * FROM `tree`",
+			queryType: "SELECT",
+			tables:    []string{"categories"},
+		},
+		{
+			name:      "subqueries and derived tables",
+			digest:    "SELECT `d` . `total` FROM ( SELECT `customer_id` , SUM ( `amount` ) AS `total` FROM `payments` GROUP BY `customer_id` ) AS `d` WHERE `d` . `customer_id` IN ( SELECT `id` FROM `customers` WHERE EXISTS ( SELECT ? FROM `orders` `o` WHERE `o` . `customer_id` = `customers` . `id` ) )",
+			queryType: "SELECT",
+			tables:    []string{"payments", "customers", "orders"},
+		},
+		{
+			name:      "comma joins, aliases and schema qualifiers",
+			digest:    "SELECT * FROM `shop` . `products` p , `categories` AS c LEFT OUTER JOIN `brands` b ON b . id = p . brand_id",
+			queryType: "SELECT",
+			tables:    []string{"products", "categories", "brands"},
+		},
+		{
+			name:      "from inside a function call is not a clause",
+			digest:    "SELECT EXTRACT ( YEAR FROM `created_at` ) , TRIM ( LEADING ? FROM `name` ) FROM `events`",
+			queryType: "SELECT",
+			tables:    []string{"events"},
+		},
+		{
+			name:      "parenthesized union",
+			digest:    "( SELECT `id` FROM `a` ) UNION ( SELECT `id` FROM `b` )",
+			queryType: "SELECT",
+			tables:    []string{"a", "b"},
+		},
+		{
+			name:      "insert select with upsert",
+			digest:    "INSERT INTO `order_archive` ( `id` , `total` ) SELECT `id` , `total` FROM `orders` WHERE `status` = ? ON DUPLICATE KEY UPDATE `total` = VALUES ( `total` )",
+			queryType: "INSERT",
+			tables:    []string{"order_archive", "orders"},
+		},
+		{
+			name:      "multi-table update",
+			digest:    "UPDATE `orders` `o` JOIN `customers` `c` ON `c` . `id` = `o` . `customer_id` SET `o` . `tier` = `c` . `tier`",
+			queryType: "UPDATE",
+			tables:    []string{"orders", "customers"},
+		},
+		{
+			name:      "cte driving an update",
+			digest:    "WITH `stale` AS ( SELECT `id` FROM `sessions` WHERE `seen_at` < ? ) UPDATE `users` SET `active` = ? WHERE `id` IN ( SELECT `id` FROM `stale` )",
+			queryType: "UPDATE",
+			tables:    []string{"sessions", "users"},
+		},
+		{
+			name:      "delete with comments",
+			digest:    "/* cleanup */ DELETE FROM `audit_log` WHERE `ts` < ? -- nightly",
+			queryType: "DELETE",
+			tables:    []string{"audit_log"},
+		},
+		{
+			name:      "select for update is not an update",
+			digest:    "SELECT * FROM `accounts` WHERE `id` = ? FOR UPDATE",
+			queryType: "SELECT",
+			tables:    []string{"accounts"},
+		},
+		{
+			name:      "ddl",
+			digest:    "CREATE TABLE IF NOT EXISTS `tmp_report` ( `id` INTEGER )",
+			queryType: "CREATE",
+			tables:    []string{"tmp_report"},
+		},
+		{
+			name:      "dual and string literals are ignored",
+			digest:    "SELECT 'from users' FROM DUAL",
+			queryType: "SELECT",
+			tables:    []string{},
+		},
+		{
+			name:      "other statements",
+			digest:    "SHOW VARIABLES LIKE ?",
+			queryType: "OTHER",
+			tables:    []string{},
+		},
+		{
+			name:      "empty digest",
+			digest:    "",
+			queryType: "UNKNOWN",
+			tables:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := ParseDigest(tt.digest, false)
+			assert.Equal(t, tt.queryType, info.QueryType)
+			assert.Equal(t, tt.tables, info.Tables)
+		})
+	}
+}
+
+func TestParseDigestQualifiedTables(t *testing.T) {
+	digest := "SELECT * FROM `shop` . `orders` JOIN `orders` ON ? JOIN `archive` . `orders` ON ?"
+
+	assert.Equal(t, []string{"orders"}, ParseDigest(digest, false).Tables)
+	assert.Equal(t, []string{"shop.orders", "orders", "archive.orders"}, ParseDigest(digest, true).Tables)
+}
+
+func TestParseDigestReportsCTENames(t *testing.T) {
+	info := ParseDigest("WITH `a` AS ( SELECT ? ) , `b` AS ( SELECT * FROM `a` ) SELECT * FROM `b`", false)
+
+	assert.Equal(t, []string{"a", "b"}, info.CTENames)
+	assert.Empty(t, info.Tables)
+}
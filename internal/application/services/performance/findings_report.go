@@ -0,0 +1,268 @@
+package performance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// Findings reports follow the layout of SARIF 2.1.0, so code-scanning dashboards can ingest
+// them and track each finding across runs by its fingerprint
+const (
+	FindingsReportVersion = "2.1.0"
+	FindingsToolName      = "sql-graph-visualizer"
+
+	// FindingsFingerprintKey names the fingerprint in partialFingerprints
+	FindingsFingerprintKey = "findingHash/v1"
+)
+
+// FindingCategory groups findings by the analysis that produced them
+type FindingCategory string
+
+const (
+	FindingCategoryBottleneck  FindingCategory = "bottleneck"
+	FindingCategoryAntiPattern FindingCategory = "anti_pattern"
+	FindingCategorySecurity    FindingCategory = "security"
+)
+
+// Finding levels as defined by SARIF
+const (
+	FindingLevelError   = "error"
+	FindingLevelWarning = "warning"
+	FindingLevelNote    = "note"
+)
+
+// FindingsReport is a SARIF-style log with a single run
+type FindingsReport struct {
+	Version string        `json:"version"`
+	Runs    []FindingsRun `json:"runs"`
+}
+
+// FindingsRun holds the findings of one analysis
+type FindingsRun struct {
+	Tool    FindingsTool `json:"tool"`
+	Results []Finding    `json:"results"`
+}
+
+// FindingsTool describes the analyzer and the rules its findings refer to
+type FindingsTool struct {
+	Driver FindingsDriver `json:"driver"`
+}
+
+// FindingsDriver names the analyzer
+type FindingsDriver struct {
+	Name  string        `json:"name"`
+	Rules []FindingRule `json:"rules"`
+}
+
+// FindingRule describes one kind of finding, e.g. "bottleneck/query"
+type FindingRule struct {
+	ID               string         `json:"id"`
+	ShortDescription FindingMessage `json:"shortDescription"`
+}
+
+// Finding is one issue found by the analysis
+type Finding struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             FindingMessage    `json:"message"`
+	Locations           []FindingLocation `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          FindingProperties `json:"properties"`
+}
+
+// FindingMessage is a SARIF message
+type FindingMessage struct {
+	Text string `json:"text"`
+}
+
+// FindingLocation points at the database objects a finding concerns
+type FindingLocation struct {
+	LogicalLocations []LogicalLocation `json:"logicalLocations"`
+}
+
+// LogicalLocation is a database object: a table, query, index, relationship or connection
+type LogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// FindingProperties carries the details SARIF has no dedicated field for
+type FindingProperties struct {
+	Category    FindingCategory     `json:"category"`
+	Severity    ports.SeverityLevel `json:"severity"`
+	Remediation []string            `json:"remediation,omitempty"`
+}
+
+// FindingsInput collects the analysis results exported as findings. Any of them may be empty.
+type FindingsInput struct {
+	Bottlenecks  []ports.PerformanceBottleneck
+	AntiPatterns []ports.QueryAntiPattern
+	Security     *models.SecurityValidationResult
+}
+
+// securityRemediation is the fix suggested for each failed security check
+var securityRemediation = map[string]string{
+	"host_security":           "Connect to a non-production host or allow production connections explicitly",
+	"credentials_security":    "Use strong, non-default database credentials",
+	"network_security":        "Enable SSL/TLS for connections over public networks",
+	"ssl_security":            "Enable SSL/TLS with certificate verification",
+	"authentication_security": "Create a dedicated database user with minimal required privileges",
+}
+
+// BuildFindingsReport converts analysis results into a findings report. Results are ordered
+// by category, then as given; passed security checks are not reported.
+func BuildFindingsReport(input FindingsInput) *FindingsReport {
+	results := make([]Finding, 0)
+
+	for _, bottleneck := range input.Bottlenecks {
+		results = append(results, newFinding(
+			FindingCategoryBottleneck,
+			"bottleneck/"+string(bottleneck.Type),
+			bottleneck.Severity,
+			bottleneck.Description,
+			bottleneckLocations(bottleneck.Location),
+			bottleneck.Recommendations,
+		))
+	}
+
+	for _, antiPattern := range input.AntiPatterns {
+		locations := make([]LogicalLocation, 0, len(antiPattern.Examples))
+		for _, example := range antiPattern.Examples {
+			locations = append(locations, LogicalLocation{Name: example, Kind: "query"})
+		}
+		results = append(results, newFinding(
+			FindingCategoryAntiPattern,
+			"anti-pattern/"+antiPattern.Type,
+			antiPattern.Impact,
+			antiPattern.Description,
+			locations,
+			antiPattern.Solutions,
+		))
+	}
+
+	if input.Security != nil {
+		names := make([]string, 0, len(input.Security.Validations))
+		for name := range input.Security.Validations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			check := input.Security.Validations[name]
+			if check == nil || check.Passed {
+				continue
+			}
+			var remediation []string
+			if fix, ok := securityRemediation[check.CheckName]; ok {
+				remediation = []string{fix}
+			}
+			results = append(results, newFinding(
+				FindingCategorySecurity,
+				"security/"+check.CheckName,
+				ports.SeverityLevel(strings.ToLower(check.Severity)),
+				check.Message,
+				[]LogicalLocation{{Name: check.CheckName, Kind: "connection"}},
+				remediation,
+			))
+		}
+	}
+
+	return &FindingsReport{
+		Version: FindingsReportVersion,
+		Runs: []FindingsRun{{
+			Tool: FindingsTool{Driver: FindingsDriver{
+				Name:  FindingsToolName,
+				Rules: findingRules(results),
+			}},
+			Results: results,
+		}},
+	}
+}
+
+func newFinding(category FindingCategory, ruleID string, severity ports.SeverityLevel, message string, locations []LogicalLocation, remediation []string) Finding {
+	finding := Finding{
+		RuleID:  ruleID,
+		Level:   findingLevel(severity),
+		Message: FindingMessage{Text: message},
+		PartialFingerprints: map[string]string{
+			FindingsFingerprintKey: findingFingerprint(ruleID, locations),
+		},
+		Properties: FindingProperties{
+			Category:    category,
+			Severity:    severity,
+			Remediation: remediation,
+		},
+	}
+	if len(locations) > 0 {
+		finding.Locations = []FindingLocation{{LogicalLocations: locations}}
+	}
+	return finding
+}
+
+// findingLevel maps a severity onto a SARIF level
+func findingLevel(severity ports.SeverityLevel) string {
+	switch severity {
+	case ports.SeverityCritical, ports.SeverityHigh:
+		return FindingLevelError
+	case ports.SeverityMedium:
+		return FindingLevelWarning
+	default:
+		return FindingLevelNote
+	}
+}
+
+// findingFingerprint identifies a finding by its rule and locations, which stay the same
+// across runs while messages and measurements change
+func findingFingerprint(ruleID string, locations []LogicalLocation) string {
+	hash := sha256.New()
+	hash.Write([]byte(ruleID))
+	for _, location := range locations {
+		hash.Write([]byte{0})
+		hash.Write([]byte(location.Kind + ":" + location.Name))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+func bottleneckLocations(location ports.BottleneckLocation) []LogicalLocation {
+	var locations []LogicalLocation
+	if location.TableName != "" {
+		locations = append(locations, LogicalLocation{Name: location.TableName, Kind: "table"})
+	}
+	for _, table := range location.JoinTables {
+		if table != location.TableName {
+			locations = append(locations, LogicalLocation{Name: table, Kind: "table"})
+		}
+	}
+	if location.QueryPattern != "" {
+		locations = append(locations, LogicalLocation{Name: location.QueryPattern, Kind: "query"})
+	}
+	if location.IndexName != "" {
+		locations = append(locations, LogicalLocation{Name: location.IndexName, Kind: "index"})
+	}
+	if location.Relationship != "" {
+		locations = append(locations, LogicalLocation{Name: location.Relationship, Kind: "relationship"})
+	}
+	return locations
+}
+
+// findingRules lists the distinct rules referenced by results, in order of first use
+func findingRules(results []Finding) []FindingRule {
+	rules := make([]FindingRule, 0)
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if seen[result.RuleID] {
+			continue
+		}
+		seen[result.RuleID] = true
+		rules = append(rules, FindingRule{
+			ID:               result.RuleID,
+			ShortDescription: FindingMessage{Text: strings.ReplaceAll(result.RuleID, "/", ": ")},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,140 @@
+package performance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findingsFixture() FindingsInput {
+	return FindingsInput{
+		Bottlenecks: []ports.PerformanceBottleneck{{
+			Type:            ports.BottleneckTypeQuery,
+			Severity:        ports.SeverityHigh,
+			Description:     "Slow query on orders",
+			Location:        ports.BottleneckLocation{TableName: "orders", QueryPattern: "SELECT * FROM orders WHERE status = ?"},
+			Recommendations: []string{"Add an index on orders.status"},
+		}},
+		AntiPatterns: []ports.QueryAntiPattern{{
+			Type:        "select_star",
+			Description: "Queries select every column",
+			Impact:      ports.SeverityMedium,
+			Examples:    []string{"SELECT * FROM customers"},
+			Solutions:   []string{"List the needed columns"},
+		}},
+		Security: &models.SecurityValidationResult{Validations: map[string]*models.ValidationCheck{
+			"credentials_security": {CheckName: "credentials_security", Severity: "CRITICAL", Message: "Default credentials detected"},
+			"port_security":        {CheckName: "port_security", Passed: true, Severity: "LOW", Message: "Standard MySQL port in use"},
+		}},
+	}
+}
+
+func TestBuildFindingsReportMapsEachCategory(t *testing.T) {
+	report := BuildFindingsReport(findingsFixture())
+
+	assert.Equal(t, FindingsReportVersion, report.Version)
+	require.Len(t, report.Runs, 1)
+	run := report.Runs[0]
+	assert.Equal(t, FindingsToolName, run.Tool.Driver.Name)
+	require.Len(t, run.Results, 3, "passed security checks are not findings")
+
+	tests := []struct {
+		category    FindingCategory
+		ruleID      string
+		level       string
+		severity    ports.SeverityLevel
+		message     string
+		locations   []LogicalLocation
+		remediation []string
+	}{
+		{
+			category: FindingCategoryBottleneck, ruleID: "bottleneck/query", level: FindingLevelError, severity: ports.SeverityHigh,
+			message: "Slow query on orders",
+			locations: []LogicalLocation{
+				{Name: "orders", Kind: "table"},
+				{Name: "SELECT * FROM orders WHERE status = ?", Kind: "query"},
+			},
+			remediation: []string{"Add an index on orders.status"},
+		},
+		{
+			category: FindingCategoryAntiPattern, ruleID: "anti-pattern/select_star", level: FindingLevelWarning, severity: ports.SeverityMedium,
+			message:     "Queries select every column",
+			locations:   []LogicalLocation{{Name: "SELECT * FROM customers", Kind: "query"}},
+			remediation: []string{"List the needed columns"},
+		},
+		{
+			category: FindingCategorySecurity, ruleID: "security/credentials_security", level: FindingLevelError, severity: ports.SeverityCritical,
+			message:     "Default credentials detected",
+			locations:   []LogicalLocation{{Name: "credentials_security", Kind: "connection"}},
+			remediation: []string{securityRemediation["credentials_security"]},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			finding := run.Results[i]
+			assert.Equal(t, tt.ruleID, finding.RuleID)
+			assert.Equal(t, tt.level, finding.Level)
+			assert.Equal(t, tt.message, finding.Message.Text)
+			assert.Equal(t, tt.category, finding.Properties.Category)
+			assert.Equal(t, tt.severity, finding.Properties.Severity)
+			assert.Equal(t, tt.remediation, finding.Properties.Remediation)
+			require.Len(t, finding.Locations, 1)
+			assert.Equal(t, tt.locations, finding.Locations[0].LogicalLocations)
+			assert.Len(t, finding.PartialFingerprints[FindingsFingerprintKey], 32)
+		})
+	}
+
+	ruleIDs := make([]string, 0, len(run.Tool.Driver.Rules))
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	assert.Equal(t, []string{"bottleneck/query", "anti-pattern/select_star", "security/credentials_security"}, ruleIDs)
+}
+
+func TestBuildFindingsReportIsValidSARIFShape(t *testing.T) {
+	encoded, err := json.Marshal(BuildFindingsReport(findingsFixture()))
+	require.NoError(t, err)
+
+	var log map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &log))
+	assert.Equal(t, "2.1.0", log["version"])
+
+	run := log["runs"].([]any)[0].(map[string]any)
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	assert.Equal(t, "sql-graph-visualizer", driver["name"])
+
+	for _, raw := range run["results"].([]any) {
+		result := raw.(map[string]any)
+		assert.NotEmpty(t, result["ruleId"])
+		assert.Contains(t, []any{"error", "warning", "note"}, result["level"])
+		assert.NotEmpty(t, result["message"].(map[string]any)["text"])
+		assert.NotEmpty(t, result["partialFingerprints"])
+	}
+}
+
+func TestFindingFingerprintIgnoresMessageChanges(t *testing.T) {
+	first := findingsFixture()
+	second := findingsFixture()
+	second.Bottlenecks[0].Description = "Slow query on orders (p95 2.4s)"
+	second.Bottlenecks[0].Severity = ports.SeverityCritical
+
+	a := BuildFindingsReport(first).Runs[0].Results[0]
+	b := BuildFindingsReport(second).Runs[0].Results[0]
+	assert.Equal(t, a.PartialFingerprints, b.PartialFingerprints)
+
+	second.Bottlenecks[0].Location.TableName = "customers"
+	c := BuildFindingsReport(second).Runs[0].Results[0]
+	assert.NotEqual(t, a.PartialFingerprints, c.PartialFingerprints)
+}
+
+func TestBuildFindingsReportWithoutFindings(t *testing.T) {
+	encoded, err := json.Marshal(BuildFindingsReport(FindingsInput{}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":"2.1.0","runs":[{"tool":{"driver":{"name":"sql-graph-visualizer","rules":[]}},"results":[]}]}`, string(encoded))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"sql-graph-visualizer/internal/application/ports"
 	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/sirupsen/logrus"
@@ -14,7 +15,7 @@ import (
 type GraphPerformanceMapper struct {
 	logger    *logrus.Logger
 	config    *GraphPerformanceMapperConfig
-	psAdapter *PerformanceSchemaAdapter
+	psAdapter ports.PerformanceCollectorPort
 	analyzer  *PerformanceAnalyzer
 }
 
@@ -271,7 +272,7 @@ type GraphMetadata struct {
 func NewGraphPerformanceMapper(
 	logger *logrus.Logger,
 	config *GraphPerformanceMapperConfig,
-	psAdapter *PerformanceSchemaAdapter,
+	psAdapter ports.PerformanceCollectorPort,
 	analyzer *PerformanceAnalyzer,
 ) *GraphPerformanceMapper {
 	if config == nil {
@@ -375,9 +376,51 @@ func (gpm *GraphPerformanceMapper) buildTablePerformanceMap(data *PerformanceSch
 		}
 	}
 
+	gpm.applyLockContention(tableMap, data.TableLockStats)
+
 	return tableMap
 }
 
+// applyLockContention records each table's share of the waiting locks (0-100) as its lock
+// contention. Nodes are matched by table name, so entries are keyed without the schema.
+func (gpm *GraphPerformanceMapper) applyLockContention(tableMap map[string]*TablePerformanceInfo, locks []TableLockStatistic) {
+	var total int64
+	for _, lock := range locks {
+		total += lock.WaitingLocks
+	}
+
+	for _, lock := range locks {
+		if lock.WaitingLocks <= 0 {
+			continue
+		}
+		info, exists := tableMap[lock.TableName]
+		if !exists {
+			info = &TablePerformanceInfo{TableName: lock.TableName}
+			tableMap[lock.TableName] = info
+		}
+
+		share := safeDivide(float64(lock.WaitingLocks), float64(total)) * 100
+		info.ResourceUsage.LockContention = share
+		info.Issues = append(info.Issues, NodeIssue{
+			Type:        "lock_contention",
+			Severity:    lockContentionSeverity(share),
+			Description: fmt.Sprintf("%d lock requests waiting on %s", lock.WaitingLocks, lock.TableName),
+			Impact:      share,
+		})
+	}
+}
+
+func lockContentionSeverity(share float64) string {
+	switch {
+	case share >= 75:
+		return "high"
+	case share >= 25:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
 // TablePerformanceInfo aggregates performance data for a table
 type TablePerformanceInfo struct {
 	TableName        string
@@ -528,7 +571,14 @@ func (gpm *GraphPerformanceMapper) calculateNodeVisualProperties(info *TablePerf
 	return NodeVisualProperties{}
 }
 func (gpm *GraphPerformanceMapper) mapNodePerformanceData(info *TablePerformanceInfo) NodePerformanceData {
-	return NodePerformanceData{}
+	return NodePerformanceData{
+		QueriesPerSecond: info.QueriesPerSecond,
+		AverageLatency:   info.AverageLatency,
+		TotalQueries:     info.TotalQueries,
+		ErrorRate:        info.ErrorRate,
+		IndexEfficiency:  info.IndexEfficiency,
+		ResourceUsage:    info.ResourceUsage,
+	}
 }
 func (gpm *GraphPerformanceMapper) findEdgePerformanceData(edge *models.Relation, data *PerformanceSchemaData) EdgePerformanceData {
 	return EdgePerformanceData{}
@@ -540,8 +590,23 @@ func (gpm *GraphPerformanceMapper) generateQueryPattern(edge *models.Relation) s
 func (gpm *GraphPerformanceMapper) identifyEdgeIssues(edgePerf EdgePerformanceData) []EdgeIssue {
 	return []EdgeIssue{}
 }
-func (gpm *GraphPerformanceMapper) calculateGlobalMetrics(perfGraph *PerformanceGraphData)         {}
-func (gpm *GraphPerformanceMapper) identifyHotspotsAndBottlenecks(perfGraph *PerformanceGraphData) {}
+func (gpm *GraphPerformanceMapper) calculateGlobalMetrics(perfGraph *PerformanceGraphData) {}
+
+// identifyHotspotsAndBottlenecks flags nodes whose tables have waiting locks
+func (gpm *GraphPerformanceMapper) identifyHotspotsAndBottlenecks(perfGraph *PerformanceGraphData) {
+	for _, node := range perfGraph.Nodes {
+		contention := node.Performance.ResourceUsage.LockContention
+		if contention <= 0 {
+			continue
+		}
+		perfGraph.Hotspots = append(perfGraph.Hotspots, HotspotInfo{
+			NodeID:      node.ID,
+			Score:       contention,
+			Type:        "lock_contention",
+			Description: fmt.Sprintf("%.0f%% of waiting locks are on %s", contention, node.TableName),
+		})
+	}
+}
 
 // Default configuration
 func defaultGraphPerformanceMapperConfig() *GraphPerformanceMapperConfig {
@@ -0,0 +1,216 @@
+package performance
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+)
+
+// MetricsHistoryStore persists metrics samples as one JSON document per line, so the metrics
+// history survives restarts and can be backfilled when the monitor starts again
+type MetricsHistoryStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewMetricsHistoryStore creates a store writing samples to the file at path
+func NewMetricsHistoryStore(path string) *MetricsHistoryStore {
+	return &MetricsHistoryStore{path: path}
+}
+
+// Append adds a sample to the end of the file, creating it if needed
+func (s *MetricsHistoryStore) Append(sample MetricsSample) error {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics sample: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create metrics history directory: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics history: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write metrics sample: %w", err)
+	}
+	return file.Close()
+}
+
+// Load returns the stored samples taken at or after since, oldest first. A missing file
+// holds no samples; lines that cannot be parsed, such as one cut short by a crash during
+// Append, are skipped.
+func (s *MetricsHistoryStore) Load(since time.Time) ([]MetricsSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(since)
+}
+
+// Trim rewrites the file to hold only the samples taken at or after since, so it does not
+// grow without bound
+func (s *MetricsHistoryStore) Trim(since time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples, err := s.load(since)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	for _, sample := range samples {
+		line, err := json.Marshal(sample)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metrics sample: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	// Write to a temporary file first so an interrupted trim never loses the history
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metrics history: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to store metrics history: %w", err)
+	}
+	return nil
+}
+
+func (s *MetricsHistoryStore) load(since time.Time) ([]MetricsSample, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics history: %w", err)
+	}
+	defer file.Close()
+
+	var samples []MetricsSample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample MetricsSample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		if !sample.Timestamp.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics history: %w", err)
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+	return samples, nil
+}
+
+// backfillHistory loads the persisted samples of the last BackfillWindow into the in-memory
+// history, so trend analysis has data right after a restart instead of waiting for enough
+// collection intervals.
+func (rpm *RealtimePerformanceMonitor) backfillHistory() {
+	if rpm.historyStore == nil || rpm.config.BackfillWindow <= 0 {
+		return
+	}
+
+	samples, err := rpm.historyStore.Load(time.Now().Add(-rpm.config.BackfillWindow))
+	if err != nil {
+		rpm.logger.WithError(err).Warn("Failed to backfill the metrics history")
+		return
+	}
+	for _, sample := range samples {
+		rpm.history.Record(sample)
+	}
+	rpm.logger.WithField("samples", len(samples)).Info("Backfilled the metrics history")
+}
+
+// historyTrimInterval is how often the history file is trimmed to its retention
+const historyTrimInterval = 10 * time.Minute
+
+// historyFileRetention is how long samples stay in the history file: MetricsRetention, or
+// BackfillWindow when longer so a restart can still backfill it
+func (rpm *RealtimePerformanceMonitor) historyFileRetention() time.Duration {
+	return max(rpm.config.MetricsRetention, rpm.config.BackfillWindow)
+}
+
+// trimHistory drops samples older than historyFileRetention from the history file
+func (rpm *RealtimePerformanceMonitor) trimHistory() {
+	if rpm.historyStore == nil || rpm.historyFileRetention() <= 0 {
+		return
+	}
+	if err := rpm.historyStore.Trim(time.Now().Add(-rpm.historyFileRetention())); err != nil {
+		rpm.logger.WithError(err).Warn("Failed to trim the metrics history")
+	}
+}
+
+// historyTrimLoop trims the history file every historyTrimInterval while the monitor runs,
+// since every collected sample is appended to it
+func (rpm *RealtimePerformanceMonitor) historyTrimLoop(ctx context.Context) {
+	ticker := time.NewTicker(historyTrimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-rpm.stopChannel:
+			return
+		case <-ticker.C:
+			rpm.trimHistory()
+		}
+	}
+}
+
+// recordSample adds a collected sample to the in-memory history and persists it
+func (rpm *RealtimePerformanceMonitor) recordSample(sample MetricsSample) {
+	rpm.history.Record(sample)
+	if rpm.historyStore == nil {
+		return
+	}
+	if err := rpm.historyStore.Append(sample); err != nil {
+		rpm.logger.WithError(err).Warn("Failed to persist metrics sample")
+	}
+}
+
+// TrendSnapshots returns the metrics history of [start, end) as snapshots for trend analysis
+func (rpm *RealtimePerformanceMonitor) TrendSnapshots(start, end time.Time) []ports.PerformanceSnapshot {
+	samples := rpm.history.Range(start, end)
+	snapshots := make([]ports.PerformanceSnapshot, 0, len(samples))
+	for _, sample := range samples {
+		snapshots = append(snapshots, sample.snapshot())
+	}
+	return snapshots
+}
+
+// snapshot maps a sample onto the analyzer's snapshot; metrics without a PerformanceMetrics
+// field are kept in the snapshot context
+func (s MetricsSample) snapshot() ports.PerformanceSnapshot {
+	return ports.PerformanceSnapshot{
+		Timestamp: s.Timestamp,
+		Metrics: &ports.PerformanceMetrics{
+			QueriesPerSecond: s.QueriesPerSecond,
+			AverageLatency:   s.AvgQueryLatency,
+		},
+		Context: map[string]interface{}{
+			"connections_used":      s.ConnectionsUsed,
+			"deadlocks_per_minute":  s.DeadlocksPerMinute,
+			"lock_waits_per_minute": s.LockWaitsPerMinute,
+			"current_lock_waits":    s.CurrentLockWaits,
+		},
+	}
+}
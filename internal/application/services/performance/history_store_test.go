@@ -0,0 +1,147 @@
+package performance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHistoryStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "metrics.jsonl")
+	store := NewMetricsHistoryStore(path)
+
+	samples, err := store.Load(time.Time{})
+	require.NoError(t, err, "a missing file holds no samples")
+	assert.Empty(t, samples)
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Append(MetricsSample{Timestamp: base.Add(time.Duration(i) * time.Minute), QueriesPerSecond: float64(i)}))
+	}
+
+	// A crash during Append leaves a partial last line
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"timestamp":"2025-03-01T12:`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	samples, err = store.Load(base.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 2, "older samples and unparsable lines are skipped")
+	assert.Equal(t, 1.0, samples[0].QueriesPerSecond)
+	assert.True(t, samples[1].Timestamp.Equal(base.Add(2*time.Minute)))
+
+	require.NoError(t, store.Trim(base.Add(2*time.Minute)))
+	samples, err = store.Load(time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, samples, 1)
+}
+
+func newBackfillTestMonitor(t *testing.T, path string, backfillWindow time.Duration) *RealtimePerformanceMonitor {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	config := defaultRealtimeMonitorConfig()
+	config.DataUpdateInterval = time.Hour
+	config.HistoryFile = path
+	config.BackfillWindow = backfillWindow
+	rpm := NewRealtimePerformanceMonitor(logger, config, nil, NewPerformanceAnalyzer(logger, nil), nil)
+	t.Cleanup(func() { _ = rpm.Stop() })
+	return rpm
+}
+
+func TestBackfillMakesTrendAnalysisAvailableAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	analyzer := NewPerformanceAnalyzer(logrus.New(), nil)
+	minDataPoints := defaultPerformanceAnalyzerConfig().MinDataPoints
+
+	// The previous run persisted a sample per minute, plus one outside the backfill window
+	store := NewMetricsHistoryStore(path)
+	now := time.Now()
+	require.NoError(t, store.Append(MetricsSample{Timestamp: now.Add(-3 * time.Hour), QueriesPerSecond: 1}))
+	for i := minDataPoints + 2; i > 0; i-- {
+		require.NoError(t, store.Append(MetricsSample{
+			Timestamp:        now.Add(-time.Duration(i) * time.Minute),
+			QueriesPerSecond: 100 + float64(i),
+			AvgQueryLatency:  2.5,
+		}))
+	}
+
+	tests := []struct {
+		name           string
+		backfillWindow time.Duration
+		want           int
+	}{
+		{name: "without backfill", want: 0},
+		{name: "with backfill", backfillWindow: time.Hour, want: minDataPoints + 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpm := newBackfillTestMonitor(t, path, tt.backfillWindow)
+			require.NoError(t, rpm.Start(context.Background()))
+
+			snapshots := rpm.TrendSnapshots(now.Add(-24*time.Hour), time.Now())
+			require.Len(t, snapshots, tt.want)
+
+			analysis, err := analyzer.AnalyzeTrends(context.Background(), snapshots)
+			if tt.want < minDataPoints {
+				assert.Error(t, err, "trend analysis needs MinDataPoints samples")
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, analysis.TimeRange.StartTime.Equal(snapshots[0].Timestamp))
+			assert.Equal(t, 2.5, snapshots[0].Metrics.AverageLatency)
+		})
+	}
+
+	samples, err := store.Load(time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, samples, minDataPoints+2, "samples outside the retention are dropped from the file")
+}
+
+func TestCollectedSamplesArePersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	rpm := newBackfillTestMonitor(t, path, time.Hour)
+
+	sample := MetricsSample{Timestamp: time.Now().Add(-time.Minute), QueriesPerSecond: 42}
+	rpm.recordSample(sample)
+
+	restarted := newBackfillTestMonitor(t, path, time.Hour)
+	require.NoError(t, restarted.Start(context.Background()))
+	history := restarted.MetricsHistory(time.Now().Add(-time.Hour), time.Now())
+	require.Len(t, history, 1)
+	assert.Equal(t, 42.0, history[0].QueriesPerSecond)
+}
+
+func TestHistoryFileIsTrimmedToRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	rpm := newBackfillTestMonitor(t, path, 0)
+	rpm.config.MetricsRetention = time.Hour
+
+	now := time.Now()
+	rpm.recordSample(MetricsSample{Timestamp: now.Add(-2 * time.Hour), QueriesPerSecond: 1})
+	rpm.recordSample(MetricsSample{Timestamp: now.Add(-time.Minute), QueriesPerSecond: 2})
+
+	rpm.trimHistory()
+
+	samples, err := rpm.historyStore.Load(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, samples, 1, "the file is trimmed without a backfill window")
+	assert.Equal(t, 2.0, samples[0].QueriesPerSecond)
+
+	rpm.config.BackfillWindow = 3 * time.Hour
+	rpm.recordSample(MetricsSample{Timestamp: now.Add(-2 * time.Hour), QueriesPerSecond: 3})
+	rpm.trimHistory()
+	samples, err = rpm.historyStore.Load(time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, samples, 2, "a longer backfill window keeps its samples")
+}
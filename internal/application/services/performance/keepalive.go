@@ -0,0 +1,62 @@
+package performance
+
+import (
+	"context"
+	"time"
+)
+
+// keepaliveTimeout bounds one keepalive ping or reconnection attempt
+const keepaliveTimeout = 5 * time.Second
+
+// startKeepalive pings the source database every KeepaliveInterval until Close, so idle
+// connections are not dropped by the server's wait_timeout during long monitoring sessions;
+// check is the engine's availability check used to reconnect
+func (p *statsCollector) startKeepalive(check func(context.Context) error) {
+	if p.config.KeepaliveInterval <= 0 {
+		return
+	}
+	p.stopKeepalive = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(p.config.KeepaliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), keepaliveTimeout)
+				p.keepalive(ctx, check)
+				cancel()
+			case <-p.stopKeepalive:
+				return
+			}
+		}
+	}()
+}
+
+// keepalive pings the database while it is connected. A failed ping marks the adapter
+// disconnected; while disconnected every tick tries to reconnect by checking availability
+// again, which makes database/sql open a fresh connection in place of the dropped one.
+func (p *statsCollector) keepalive(ctx context.Context, check func(context.Context) error) {
+	if !p.IsConnected() {
+		p.reconnect(ctx, check)
+		return
+	}
+
+	if err := p.db.PingContext(ctx); err != nil {
+		p.mutex.Lock()
+		p.isConnected = false
+		p.mutex.Unlock()
+		p.logger.WithError(err).Warn("Performance database keepalive failed, reconnecting")
+		p.reconnect(ctx, check)
+	}
+}
+
+// reconnect re-verifies the statistics source with check after the connection was lost
+func (p *statsCollector) reconnect(ctx context.Context, check func(context.Context) error) error {
+	if err := p.checkAvailability(ctx, check); err != nil {
+		p.logger.WithError(err).Debug("Performance database still unavailable")
+		return err
+	}
+	p.logger.Info("Reconnected to the performance database")
+	return nil
+}
@@ -0,0 +1,80 @@
+package performance
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// droppingQuery answers the availability check; the stub's setDown simulates the server
+// dropping connections
+func droppingQuery(dsn, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "information_schema.tables") {
+		return availableRows(), nil
+	}
+	return emptyRows{}, nil
+}
+
+func newKeepaliveTestAdapter(t *testing.T, interval time.Duration) (*PerformanceSchemaAdapter, *stubDriver) {
+	dropping := &stubDriver{query: droppingQuery}
+	db := openStubDB(t, dropping, "")
+
+	logger, _ := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	config.KeepaliveInterval = interval
+	p := NewPerformanceSchemaAdapter(db, logger, config)
+	t.Cleanup(func() { p.Close() })
+	require.True(t, p.IsConnected())
+	return p, dropping
+}
+
+func TestReconnectAfterDroppedConnectionRestoresCollection(t *testing.T) {
+	p, dropping := newKeepaliveTestAdapter(t, 0)
+	ctx := context.Background()
+
+	dropping.setDown(true)
+	p.keepalive(ctx, p.checkPerformanceSchema)
+	assert.False(t, p.IsConnected(), "the keepalive notices the dropped connection")
+
+	_, err := p.CollectPerformanceData(ctx)
+	require.Error(t, err, "collection fails while the server is unreachable")
+	assert.Contains(t, err.Error(), "not connected")
+
+	dropping.setDown(false)
+	data, err := p.CollectPerformanceData(ctx)
+	require.NoError(t, err, "the next collection reconnects")
+	assert.NotNil(t, data)
+	assert.True(t, p.IsConnected())
+}
+
+func TestKeepaliveReplacesConnectionDroppedWhileIdle(t *testing.T) {
+	p, dropping := newKeepaliveTestAdapter(t, 0)
+
+	// wait_timeout closed the idle connection but the server is up: database/sql discards it
+	// and the ping succeeds on a new connection
+	dropping.setDown(false)
+	p.keepalive(context.Background(), p.checkPerformanceSchema)
+
+	assert.True(t, p.IsConnected())
+	_, err := p.CollectPerformanceData(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestKeepaliveLoopReconnects(t *testing.T) {
+	p, dropping := newKeepaliveTestAdapter(t, 5*time.Millisecond)
+
+	dropping.setDown(true)
+	assert.Eventually(t, func() bool { return !p.IsConnected() }, time.Second, time.Millisecond)
+
+	dropping.setDown(false)
+	assert.Eventually(t, p.IsConnected, time.Second, time.Millisecond, "the keepalive reconnects on its own")
+
+	require.NoError(t, p.Close())
+	require.NoError(t, p.Close(), "closing twice is safe")
+}
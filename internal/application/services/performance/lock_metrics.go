@@ -0,0 +1,77 @@
+package performance
+
+import (
+	"context"
+	"fmt"
+)
+
+// applyCounters fills the rate fields of status from a new counter snapshot. Callers hold p.mutex.
+func (p *statsCollector) applyCounters(status *GlobalStatusData, counters statusCounters) {
+	previous := p.lastCounters
+	status.Deadlocks = counters.Deadlocks
+	status.RowLockWaits = counters.RowLockWaits
+	status.DeadlocksPerMinute, status.LockWaitsPerMinute = lockRates(previous, counters)
+	status.QueriesPerSecond, status.ConnectionsPerSecond = p.counterRates(counters)
+}
+
+// lockRates returns deadlocks and lock waits per minute since the previous snapshot. Unlike
+// query rates there is no lifetime fallback: deadlocks accumulated before monitoring started
+// (or before a restart) must not raise alerts, so those cases report zero.
+func lockRates(previous *statusCounters, current statusCounters) (deadlocksPerMinute, lockWaitsPerMinute float64) {
+	if previous == nil || current.Uptime <= previous.Uptime ||
+		current.Deadlocks < previous.Deadlocks || current.RowLockWaits < previous.RowLockWaits {
+		return 0, 0
+	}
+
+	minutes := float64(current.Uptime-previous.Uptime) / 60
+	deadlocksPerMinute = float64(current.Deadlocks-previous.Deadlocks) / minutes
+	lockWaitsPerMinute = float64(current.RowLockWaits-previous.RowLockWaits) / minutes
+	return deadlocksPerMinute, lockWaitsPerMinute
+}
+
+// mysqlDeadlockCount reads the InnoDB deadlock counter on MySQL, which unlike MariaDB does
+// not expose Innodb_deadlocks as a status variable
+func (p *PerformanceSchemaAdapter) mysqlDeadlockCount(ctx context.Context) (int64, error) {
+	query := `SELECT count FROM information_schema.innodb_metrics WHERE name = 'lock_deadlocks'`
+
+	var count int64
+	if err := p.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to query deadlock count: %w", err)
+	}
+	return count, nil
+}
+
+// collectTableLockStats groups waiting InnoDB row locks by the table they wait on
+func (p *PerformanceSchemaAdapter) collectTableLockStats(ctx context.Context) ([]TableLockStatistic, error) {
+	query := `
+		SELECT
+			l.object_schema,
+			l.object_name,
+			COUNT(*) AS waiting_locks
+		FROM performance_schema.data_lock_waits w
+		JOIN performance_schema.data_locks l ON l.engine_lock_id = w.requesting_engine_lock_id
+		WHERE l.object_name IS NOT NULL
+		GROUP BY l.object_schema, l.object_name
+		ORDER BY waiting_locks DESC
+		LIMIT ?`
+
+	rows, err := p.db.QueryContext(ctx, query, p.config.MaxTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table lock waits: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []TableLockStatistic
+	for rows.Next() {
+		var stat TableLockStatistic
+		if err := rows.Scan(&stat.SchemaName, &stat.TableName, &stat.WaitingLocks); err != nil {
+			p.logger.WithError(err).Debug("Failed to scan table lock row")
+			continue
+		}
+		if p.shouldIgnoreSchema(stat.SchemaName) || !p.shouldCollectTable(stat.SchemaName, stat.TableName) {
+			continue
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
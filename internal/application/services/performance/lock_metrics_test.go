@@ -0,0 +1,120 @@
+package performance
+
+import (
+	"context"
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockRates(t *testing.T) {
+	previous := &statusCounters{Deadlocks: 40, RowLockWaits: 1000, Uptime: 600}
+
+	deadlocks, waits := lockRates(previous, statusCounters{Deadlocks: 46, RowLockWaits: 1090, Uptime: 630})
+	assert.InDelta(t, 12.0, deadlocks, 1e-9, "6 deadlocks in 30s")
+	assert.InDelta(t, 180.0, waits, 1e-9)
+
+	deadlocks, _ = lockRates(nil, statusCounters{Deadlocks: 500, Uptime: 60})
+	assert.Zero(t, deadlocks, "deadlocks before monitoring started are not a rate")
+
+	deadlocks, _ = lockRates(previous, statusCounters{Deadlocks: 2, Uptime: 10})
+	assert.Zero(t, deadlocks, "a restart resets the counters")
+}
+
+// collectLockFixture feeds counter snapshots through the adapter and returns the last status
+func collectLockFixture(samples ...statusCounters) *GlobalStatusData {
+	p := newFilterTestAdapter(func(*PerformanceSchemaConfig) {})
+	var status *GlobalStatusData
+	for _, counters := range samples {
+		status = &GlobalStatusData{}
+		p.applyCounters(status, counters)
+	}
+	return status
+}
+
+func drainAlerts(rpm *RealtimePerformanceMonitor) []*PerformanceAlert {
+	var alerts []*PerformanceAlert
+	for {
+		select {
+		case alert := <-rpm.alertsChannel:
+			alerts = append(alerts, alert)
+		default:
+			return alerts
+		}
+	}
+}
+
+func TestCheckAndGenerateAlerts_DeadlockRateAboveThreshold(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.AlertThresholds.DeadlockThreshold = 5
+
+	status := collectLockFixture(
+		statusCounters{Deadlocks: 100, Uptime: 3600},
+		statusCounters{Deadlocks: 104, Uptime: 3630},
+	)
+	rpm.checkAndGenerateAlerts(&PerformanceSchemaData{
+		GlobalStatus:   status,
+		TableLockStats: []TableLockStatistic{{SchemaName: "shop", TableName: "orders", WaitingLocks: 7}},
+	})
+
+	alerts := drainAlerts(rpm)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "deadlock", alerts[0].Type)
+	assert.InDelta(t, 8.0, alerts[0].Value, 1e-9)
+	assert.Equal(t, 5.0, alerts[0].Threshold)
+	assert.Equal(t, "medium", alerts[0].Severity, "8 per minute is 1.6x the threshold")
+	assert.Equal(t, "orders", alerts[0].TableName)
+}
+
+func TestCheckAndGenerateAlerts_NoDeadlockAlertAtOrBelowThreshold(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.AlertThresholds.DeadlockThreshold = 5
+
+	tests := map[string][]statusCounters{
+		"below threshold":       {{Deadlocks: 100, Uptime: 3600}, {Deadlocks: 102, Uptime: 3630}},
+		"at threshold":          {{Deadlocks: 100, Uptime: 3600}, {Deadlocks: 105, Uptime: 3660}},
+		"first sample only":     {{Deadlocks: 5000, Uptime: 3600}},
+		"server restarted":      {{Deadlocks: 100, Uptime: 3600}, {Deadlocks: 50, Uptime: 30}},
+		"counters not reported": {{Uptime: 3600}, {Uptime: 3630}},
+	}
+	for name, samples := range tests {
+		t.Run(name, func(t *testing.T) {
+			rpm.checkAndGenerateAlerts(&PerformanceSchemaData{GlobalStatus: collectLockFixture(samples...)})
+			assert.Empty(t, drainAlerts(rpm))
+		})
+	}
+}
+
+func TestMapPerformanceToGraph_LockContentionOnHotspotNodes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	mapper := NewGraphPerformanceMapper(logger, nil, nil, nil)
+
+	baseGraph := &models.Graph{Nodes: []*models.Node{
+		{Label: "orders", Properties: map[string]any{"id": "orders"}},
+		{Label: "customers", Properties: map[string]any{"id": "customers"}},
+		{Label: "products", Properties: map[string]any{"id": "products"}},
+	}}
+	perfGraph, err := mapper.MapPerformanceToGraph(context.Background(), baseGraph, &PerformanceSchemaData{
+		TableLockStats: []TableLockStatistic{
+			{SchemaName: "shop", TableName: "orders", WaitingLocks: 6},
+			{SchemaName: "shop", TableName: "customers", WaitingLocks: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	contention := make(map[string]float64)
+	for _, node := range perfGraph.Nodes {
+		contention[node.ID] = node.Performance.ResourceUsage.LockContention
+	}
+	assert.Equal(t, map[string]float64{"orders": 75, "customers": 25, "products": 0}, contention)
+
+	require.Len(t, perfGraph.Hotspots, 2)
+	assert.Equal(t, "orders", perfGraph.Hotspots[0].NodeID)
+	assert.Equal(t, "lock_contention", perfGraph.Hotspots[0].Type)
+	assert.Equal(t, "high", perfGraph.Nodes[0].Issues[0].Severity)
+}
@@ -0,0 +1,198 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryWindows are the supported aggregation windows of the metrics history
+var HistoryWindows = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": 5 * time.Minute,
+	"1h": time.Hour,
+}
+
+// ParseHistoryWindow maps a window name onto its duration. An empty name or "raw" returns
+// zero, meaning the samples are returned as collected.
+func ParseHistoryWindow(name string) (time.Duration, error) {
+	if name == "" || name == "raw" {
+		return 0, nil
+	}
+	window, ok := HistoryWindows[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported window %q (use 1m, 5m or 1h)", name)
+	}
+	return window, nil
+}
+
+// MetricsSample is the part of one collection tick kept in the metrics history
+type MetricsSample struct {
+	Timestamp          time.Time `json:"timestamp"`
+	QueriesPerSecond   float64   `json:"queries_per_second"`
+	AvgQueryLatency    float64   `json:"avg_query_latency_ms"`
+	ConnectionsUsed    float64   `json:"connections_used"`
+	DeadlocksPerMinute float64   `json:"deadlocks_per_minute"`
+	LockWaitsPerMinute float64   `json:"lock_waits_per_minute"`
+	CurrentLockWaits   float64   `json:"current_lock_waits"`
+}
+
+// sampleFromMetrics reduces a metrics snapshot to a history sample
+func sampleFromMetrics(metrics *RealtimeMetrics) MetricsSample {
+	sample := MetricsSample{Timestamp: metrics.Timestamp}
+	if db := metrics.DatabaseMetrics; db != nil {
+		sample.QueriesPerSecond = db.QueriesPerSecond
+		sample.ConnectionsUsed = float64(db.ConnectionsUsed)
+		sample.DeadlocksPerMinute = db.DeadlocksPerMinute
+		sample.LockWaitsPerMinute = db.LockWaitsPerMinute
+		sample.CurrentLockWaits = float64(db.CurrentLockWaits)
+	}
+
+	var totalLatency float64
+	for _, query := range metrics.TopQueries {
+		totalLatency += query.AvgExecutionTime
+	}
+	sample.AvgQueryLatency = safeDivide(totalLatency, float64(len(metrics.TopQueries)))
+	return sample
+}
+
+// values lists the sample's metrics by the names used in aggregated buckets
+func (s MetricsSample) values() map[string]float64 {
+	return map[string]float64{
+		"queries_per_second":    s.QueriesPerSecond,
+		"avg_query_latency_ms":  s.AvgQueryLatency,
+		"connections_used":      s.ConnectionsUsed,
+		"deadlocks_per_minute":  s.DeadlocksPerMinute,
+		"lock_waits_per_minute": s.LockWaitsPerMinute,
+		"current_lock_waits":    s.CurrentLockWaits,
+	}
+}
+
+// MetricsHistory keeps the samples of the retention period in collection order
+type MetricsHistory struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	samples   []MetricsSample
+}
+
+// NewMetricsHistory creates a history dropping samples older than retention
+func NewMetricsHistory(retention time.Duration) *MetricsHistory {
+	return &MetricsHistory{retention: retention}
+}
+
+// Record appends a sample and drops the samples that fell out of the retention period
+func (h *MetricsHistory) Record(sample MetricsSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples = append(h.samples, sample)
+	if h.retention <= 0 {
+		return
+	}
+
+	cutoff := sample.Timestamp.Add(-h.retention)
+	first := sort.Search(len(h.samples), func(i int) bool {
+		return !h.samples[i].Timestamp.Before(cutoff)
+	})
+	if first > 0 {
+		h.samples = append([]MetricsSample(nil), h.samples[first:]...)
+	}
+}
+
+// Range returns the samples taken in [start, end)
+func (h *MetricsHistory) Range(start, end time.Time) []MetricsSample {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	samples := make([]MetricsSample, 0)
+	for _, sample := range h.samples {
+		if !sample.Timestamp.Before(start) && sample.Timestamp.Before(end) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// AggregateValue summarizes one metric over a history bucket
+type AggregateValue struct {
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+	P95 float64 `json:"p95"`
+}
+
+// HistoryBucket aggregates the samples taken in [Start, End)
+type HistoryBucket struct {
+	Start   time.Time                 `json:"start"`
+	End     time.Time                 `json:"end"`
+	Samples int                       `json:"samples"`
+	Metrics map[string]AggregateValue `json:"metrics"`
+}
+
+// AggregateHistory groups samples into buckets of window aligned to multiples of window,
+// so the same sample always lands in the same bucket whatever range is requested.
+// Buckets without samples are omitted.
+func AggregateHistory(samples []MetricsSample, window time.Duration) []HistoryBucket {
+	if window <= 0 || len(samples) == 0 {
+		return []HistoryBucket{}
+	}
+
+	grouped := make(map[time.Time][]MetricsSample)
+	var starts []time.Time
+	for _, sample := range samples {
+		start := sample.Timestamp.Truncate(window)
+		if _, ok := grouped[start]; !ok {
+			starts = append(starts, start)
+		}
+		grouped[start] = append(grouped[start], sample)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+
+	buckets := make([]HistoryBucket, 0, len(starts))
+	for _, start := range starts {
+		bucketSamples := grouped[start]
+
+		series := make(map[string][]float64)
+		for _, sample := range bucketSamples {
+			for name, value := range sample.values() {
+				series[name] = append(series[name], value)
+			}
+		}
+
+		metrics := make(map[string]AggregateValue, len(series))
+		for name, values := range series {
+			metrics[name] = aggregateValues(values)
+		}
+
+		buckets = append(buckets, HistoryBucket{
+			Start:   start,
+			End:     start.Add(window),
+			Samples: len(bucketSamples),
+			Metrics: metrics,
+		})
+	}
+	return buckets
+}
+
+// aggregateValues computes the average, maximum and nearest-rank 95th percentile
+func aggregateValues(values []float64) AggregateValue {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, value := range sorted {
+		sum += value
+	}
+
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return AggregateValue{
+		Avg: sum / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[rank],
+	}
+}
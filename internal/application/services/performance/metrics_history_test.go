@@ -0,0 +1,100 @@
+package performance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateHistoryBuckets(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Ten minutes of 10-second samples; QPS counts up within each minute
+	var samples []MetricsSample
+	for i := 0; i < 60; i++ {
+		samples = append(samples, MetricsSample{
+			Timestamp:        base.Add(time.Duration(i) * 10 * time.Second),
+			QueriesPerSecond: float64(i%6 + 1),
+			ConnectionsUsed:  float64(i / 6),
+		})
+	}
+
+	minutes := AggregateHistory(samples, time.Minute)
+	require.Len(t, minutes, 10)
+	for i, bucket := range minutes {
+		assert.Equal(t, base.Add(time.Duration(i)*time.Minute), bucket.Start)
+		assert.Equal(t, bucket.Start.Add(time.Minute), bucket.End)
+		assert.Equal(t, 6, bucket.Samples)
+
+		qps := bucket.Metrics["queries_per_second"]
+		assert.InDelta(t, 3.5, qps.Avg, 1e-9)
+		assert.Equal(t, 6.0, qps.Max)
+		assert.Equal(t, 6.0, qps.P95)
+		assert.Equal(t, float64(i), bucket.Metrics["connections_used"].Max)
+	}
+
+	fiveMinutes := AggregateHistory(samples, 5*time.Minute)
+	require.Len(t, fiveMinutes, 2)
+	assert.Equal(t, 30, fiveMinutes[0].Samples)
+	assert.InDelta(t, 2.0, fiveMinutes[0].Metrics["connections_used"].Avg, 1e-9)
+	assert.InDelta(t, 7.0, fiveMinutes[1].Metrics["connections_used"].Avg, 1e-9)
+	assert.Equal(t, 9.0, fiveMinutes[1].Metrics["connections_used"].Max)
+
+	hours := AggregateHistory(samples, time.Hour)
+	require.Len(t, hours, 1)
+	assert.Equal(t, 60, hours[0].Samples)
+}
+
+func TestAggregateHistorySkipsEmptyBuckets(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	samples := []MetricsSample{
+		{Timestamp: base.Add(30 * time.Second), QueriesPerSecond: 4},
+		{Timestamp: base.Add(5*time.Minute + 10*time.Second), QueriesPerSecond: 8},
+	}
+
+	buckets := AggregateHistory(samples, time.Minute)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, base, buckets[0].Start)
+	assert.Equal(t, base.Add(5*time.Minute), buckets[1].Start)
+}
+
+func TestAggregateValuesPercentile(t *testing.T) {
+	values := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		values = append(values, float64(i))
+	}
+
+	aggregate := aggregateValues(values)
+	assert.InDelta(t, 50.5, aggregate.Avg, 1e-9)
+	assert.Equal(t, 100.0, aggregate.Max)
+	assert.Equal(t, 95.0, aggregate.P95)
+}
+
+func TestMetricsHistoryRetentionAndRange(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := NewMetricsHistory(time.Minute)
+	for i := 0; i < 10; i++ {
+		history.Record(MetricsSample{Timestamp: base.Add(time.Duration(i) * 20 * time.Second)})
+	}
+
+	all := history.Range(base, base.Add(time.Hour))
+	require.Len(t, all, 4, "samples older than the retention are dropped")
+	assert.Equal(t, base.Add(120*time.Second), all[0].Timestamp)
+
+	assert.Len(t, history.Range(base.Add(140*time.Second), base.Add(180*time.Second)), 2)
+}
+
+func TestParseHistoryWindow(t *testing.T) {
+	window, err := ParseHistoryWindow("5m")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, window)
+
+	window, err = ParseHistoryWindow("")
+	require.NoError(t, err)
+	assert.Zero(t, window)
+
+	_, err = ParseHistoryWindow("2m")
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -103,7 +104,7 @@ func (pa *PerformanceAnalyzer) AnalyzeCriticalPath(ctx context.Context, graphDat
 	analysis := &ports.CriticalPathAnalysis{
 		CriticalPaths:  make([]ports.CriticalPath, 0),
 		AnalyzedAt:     time.Now(),
-		AverageLatency: pa.calculateAverageNodeLatency(graphData.Nodes),
+		AverageLatency: models.Duration(pa.calculateAverageNodeLatency(graphData.Nodes)),
 	}
 
 	// Build adjacency graph from performance data
@@ -379,7 +380,7 @@ func (pa *PerformanceAnalyzer) DetectRegressions(ctx context.Context, current, p
 	}
 
 	// Check latency regression
-	if current.AverageLatency > previous.AverageLatency {
+	if current.AverageLatency > previous.AverageLatency && previous.AverageLatency > 0 {
 		regressionPct := ((current.AverageLatency - previous.AverageLatency) / previous.AverageLatency) * 100
 		if regressionPct > 10.0 { // 10% threshold
 			severity := pa.classifyRegressionSeverity(regressionPct)
@@ -542,13 +543,13 @@ func (pa *PerformanceAnalyzer) ComparePerformance(ctx context.Context, baseline,
 		return nil, fmt.Errorf("failed to calculate current score: %w", err)
 	}
 
-	improvement := ((currentScore.OverallScore - baselineScore.OverallScore) / baselineScore.OverallScore) * 100
+	improvement := safeDivide(currentScore.OverallScore-baselineScore.OverallScore, baselineScore.OverallScore) * 100
 
 	changes := make([]ports.PerformanceChange, 0)
 
 	// Compare latency
 	if baseline.AverageLatency != current.AverageLatency {
-		latencyChange := ((current.AverageLatency - baseline.AverageLatency) / baseline.AverageLatency) * 100
+		latencyChange := safeDivide(current.AverageLatency-baseline.AverageLatency, baseline.AverageLatency) * 100
 		changes = append(changes, ports.PerformanceChange{
 			MetricName:      "average_latency",
 			BaselineValue:   baseline.AverageLatency,
@@ -561,7 +562,7 @@ func (pa *PerformanceAnalyzer) ComparePerformance(ctx context.Context, baseline,
 
 	// Compare throughput
 	if baseline.QueriesPerSecond != current.QueriesPerSecond {
-		throughputChange := ((current.QueriesPerSecond - baseline.QueriesPerSecond) / baseline.QueriesPerSecond) * 100
+		throughputChange := safeDivide(current.QueriesPerSecond-baseline.QueriesPerSecond, baseline.QueriesPerSecond) * 100
 		changes = append(changes, ports.PerformanceChange{
 			MetricName:      "queries_per_second",
 			BaselineValue:   baseline.QueriesPerSecond,
@@ -625,7 +626,7 @@ func (pa *PerformanceAnalyzer) analyzeGlobalBottlenecks(metrics *ports.Performan
 			Description: fmt.Sprintf("Low throughput detected: %.2f QPS", metrics.QueriesPerSecond),
 			Impact: ports.PerformanceImpact{
 				LatencyIncrease:    0,
-				ThroughputDecrease: ((pa.config.LowThroughputThreshold - metrics.QueriesPerSecond) / pa.config.LowThroughputThreshold) * 100,
+				ThroughputDecrease: safeDivide(pa.config.LowThroughputThreshold-metrics.QueriesPerSecond, pa.config.LowThroughputThreshold) * 100,
 				ResourceUsage:      15,
 				AffectedQueries:    1,
 				BusinessImpact:     "HIGH",
@@ -649,12 +650,12 @@ func (pa *PerformanceAnalyzer) analyzeQueryBottlenecks(queryResults []ports.Quer
 	bottlenecks := make([]ports.PerformanceBottleneck, 0)
 
 	for _, query := range queryResults {
-		if query.AverageTime > pa.config.HighLatencyThreshold {
+		if query.AverageTime.Std() > pa.config.HighLatencyThreshold {
 			bottleneck := ports.PerformanceBottleneck{
 				ID:   uuid.New().String(),
 				Type: ports.BottleneckTypeQuery,
 				Severity: func() ports.SeverityLevel {
-					if query.AverageTime > 2*pa.config.HighLatencyThreshold {
+					if query.AverageTime.Std() > 2*pa.config.HighLatencyThreshold {
 						return ports.SeverityCritical
 					}
 					return ports.SeverityHigh
@@ -666,7 +667,7 @@ func (pa *PerformanceAnalyzer) analyzeQueryBottlenecks(queryResults []ports.Quer
 				Description: fmt.Sprintf("Slow query detected: %s (avg: %s)",
 					query.QueryType, query.AverageTime.String()),
 				Impact: ports.PerformanceImpact{
-					LatencyIncrease:    float64(query.AverageTime.Milliseconds()),
+					LatencyIncrease:    float64(query.AverageTime.Std().Milliseconds()),
 					ThroughputDecrease: 0,
 					ResourceUsage:      float64(query.RowsExamined) / 1000.0,
 					AffectedQueries:    int(query.ExecutionCount),
@@ -0,0 +1,58 @@
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAnalyzer() *PerformanceAnalyzer {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewPerformanceAnalyzer(logger, nil)
+}
+
+func TestComparePerformance_ZeroBaselineIsFinite(t *testing.T) {
+	analyzer := newTestAnalyzer()
+
+	baseline := &ports.PerformanceMetrics{}
+	current := &ports.PerformanceMetrics{AverageLatency: 12, QueriesPerSecond: 40}
+
+	comparison, err := analyzer.ComparePerformance(context.Background(), baseline, current)
+	require.NoError(t, err)
+
+	assert.False(t, math.IsNaN(comparison.Improvement) || math.IsInf(comparison.Improvement, 0))
+	for _, change := range comparison.Changes {
+		assert.False(t, math.IsNaN(change.ChangeAmount) || math.IsInf(change.ChangeAmount, 0), change.MetricName)
+	}
+
+	_, err = json.Marshal(comparison)
+	assert.NoError(t, err)
+}
+
+func TestDetectRegressions_ZeroPreviousLatency(t *testing.T) {
+	analyzer := newTestAnalyzer()
+
+	regressions, err := analyzer.DetectRegressions(context.Background(),
+		&ports.PerformanceMetrics{AverageLatency: 25},
+		&ports.PerformanceMetrics{AverageLatency: 0})
+	require.NoError(t, err)
+
+	_, err = json.Marshal(regressions)
+	assert.NoError(t, err)
+}
+
+func TestSafeDivide(t *testing.T) {
+	assert.Equal(t, 0.0, safeDivide(5, 0))
+	assert.Equal(t, 0.0, safeDivide(0, 0))
+	assert.Equal(t, 0.0, safeDivide(math.Inf(1), 2))
+	assert.Equal(t, 2.5, safeDivide(5, 2))
+}
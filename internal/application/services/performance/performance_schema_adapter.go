@@ -6,28 +6,24 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/sirupsen/logrus"
 )
 
 // PerformanceSchemaAdapter collects performance data from MySQL Performance Schema
 type PerformanceSchemaAdapter struct {
-	db     *sql.DB
-	logger *logrus.Logger
-	config *PerformanceSchemaConfig
-
-	// Caching and state management
-	lastCollection time.Time
-	mutex          sync.RWMutex
-	isConnected    bool
-
-	// Query cache for performance schema queries
-	queryCache    map[string]*sql.Stmt
-	queryCacheMux sync.RWMutex
+	*statsCollector
+
+	// digestTextWarned is set once missing digest text has been reported
+	digestTextWarned bool
+	// lastDigestReset is when the digest summary was last truncated for DigestResetInterval
+	lastDigestReset time.Time
+	// digestResetDenied is set once the server refused to truncate the digest summary
+	digestResetDenied bool
 }
 
 // PerformanceSchemaConfig contains configuration for Performance Schema data collection
@@ -45,148 +41,84 @@ type PerformanceSchemaConfig struct {
 	CollectConnections bool `yaml:"collect_connections" json:"collect_connections"`
 	CollectReplication bool `yaml:"collect_replication" json:"collect_replication"`
 
+	// DigestResetInterval truncates the digest summary on this schedule so its counters stay
+	// bounded; zero never resets. Resets stop if the user lacks the DROP privilege on it.
+	DigestResetInterval time.Duration `yaml:"digest_reset_interval" json:"digest_reset_interval"`
+
 	// Query limits
 	MaxStatements int `yaml:"max_statements" json:"max_statements"`
 	MaxTables     int `yaml:"max_tables" json:"max_tables"`
 
+	// CollectionBudget is how long one collection query may take before a warning is
+	// logged, so monitoring does not become the load it measures; zero disables the check
+	CollectionBudget time.Duration `yaml:"collection_budget" json:"collection_budget"`
+	// AutoReduceLimits halves MaxStatements or MaxTables (down to minReducedLimit) after
+	// the query they bound exceeds CollectionBudget
+	AutoReduceLimits bool `yaml:"auto_reduce_limits" json:"auto_reduce_limits"`
+
 	// Filtering options
 	IgnoredSchemas []string `yaml:"ignored_schemas" json:"ignored_schemas"`
 	IgnoredUsers   []string `yaml:"ignored_users" json:"ignored_users"`
-	FocusedTables  []string `yaml:"focused_tables" json:"focused_tables"`
+	// IgnoredTables skips statements and table I/O that only touch these tables
+	// ("table" or "schema.table")
+	IgnoredTables []string `yaml:"ignored_tables" json:"ignored_tables"`
+	// FocusedTables restricts collection to statements and table I/O involving at least one
+	// of these tables ("table" or "schema.table"); empty collects everything
+	FocusedTables []string `yaml:"focused_tables" json:"focused_tables"`
 
 	// Advanced settings
 	EnableDigestText  bool    `yaml:"enable_digest_text" json:"enable_digest_text"`
 	MinExecutionCount int64   `yaml:"min_execution_count" json:"min_execution_count"`
 	MinAvgLatency     float64 `yaml:"min_avg_latency" json:"min_avg_latency"` // milliseconds
-}
-
-// PerformanceSchemaData contains collected performance data
-type PerformanceSchemaData struct {
-	CollectionTime   time.Time              `json:"collection_time"`
-	GlobalStatus     *GlobalStatusData      `json:"global_status"`
-	StatementStats   []StatementStatistic   `json:"statement_stats"`
-	TableIOStats     []TableIOStatistic     `json:"table_io_stats"`
-	IndexStats       []IndexStatistic       `json:"index_stats"`
-	WaitEventStats   []WaitEventStatistic   `json:"wait_event_stats"`
-	ConnectionStats  *ConnectionStatistics  `json:"connection_stats"`
-	ReplicationStats *ReplicationStatistics `json:"replication_stats"`
-	SlowQueries      []SlowQueryInfo        `json:"slow_queries"`
-}
-
-// GlobalStatusData contains global MySQL status information
-type GlobalStatusData struct {
-	QueriesPerSecond        float64 `json:"queries_per_second"`
-	ConnectionsPerSecond    float64 `json:"connections_per_second"`
-	SlowQueries             int64   `json:"slow_queries"`
-	OpenTables              int64   `json:"open_tables"`
-	ThreadsRunning          int64   `json:"threads_running"`
-	ThreadsConnected        int64   `json:"threads_connected"`
-	InnodbBufferPoolHitRate float64 `json:"innodb_buffer_pool_hit_rate"`
-	KeyCacheHitRate         float64 `json:"key_cache_hit_rate"`
-	TmpTablesCreated        int64   `json:"tmp_tables_created"`
-	TmpDiskTablesCreated    int64   `json:"tmp_disk_tables_created"`
-}
-
-// StatementStatistic contains per-statement performance data
-type StatementStatistic struct {
-	SchemaName              string        `json:"schema_name"`
-	Digest                  string        `json:"digest"`
-	DigestText              string        `json:"digest_text,omitempty"`
-	CountStar               int64         `json:"count_star"`
-	SumTimerWait            time.Duration `json:"sum_timer_wait"`
-	MinTimerWait            time.Duration `json:"min_timer_wait"`
-	AvgTimerWait            time.Duration `json:"avg_timer_wait"`
-	MaxTimerWait            time.Duration `json:"max_timer_wait"`
-	SumRowsAffected         int64         `json:"sum_rows_affected"`
-	SumRowsSent             int64         `json:"sum_rows_sent"`
-	SumRowsExamined         int64         `json:"sum_rows_examined"`
-	SumCreatedTmpTables     int64         `json:"sum_created_tmp_tables"`
-	SumCreatedTmpDiskTables int64         `json:"sum_created_tmp_disk_tables"`
-	SumSelectFullJoin       int64         `json:"sum_select_full_join"`
-	SumSelectScan           int64         `json:"sum_select_scan"`
-	SumSortScan             int64         `json:"sum_sort_scan"`
-	SumSortRows             int64         `json:"sum_sort_rows"`
-	SumNoIndexUsed          int64         `json:"sum_no_index_used"`
-	SumNoGoodIndexUsed      int64         `json:"sum_no_good_index_used"`
-	FirstSeen               time.Time     `json:"first_seen"`
-	LastSeen                time.Time     `json:"last_seen"`
-}
-
-// TableIOStatistic contains per-table I/O performance data
-type TableIOStatistic struct {
-	SchemaName     string        `json:"schema_name"`
-	TableName      string        `json:"table_name"`
-	CountRead      int64         `json:"count_read"`
-	SumTimerRead   time.Duration `json:"sum_timer_read"`
-	CountWrite     int64         `json:"count_write"`
-	SumTimerWrite  time.Duration `json:"sum_timer_write"`
-	CountFetch     int64         `json:"count_fetch"`
-	SumTimerFetch  time.Duration `json:"sum_timer_fetch"`
-	CountInsert    int64         `json:"count_insert"`
-	SumTimerInsert time.Duration `json:"sum_timer_insert"`
-	CountUpdate    int64         `json:"count_update"`
-	SumTimerUpdate time.Duration `json:"sum_timer_update"`
-	CountDelete    int64         `json:"count_delete"`
-	SumTimerDelete time.Duration `json:"sum_timer_delete"`
-}
-
-// IndexStatistic contains index usage statistics
-type IndexStatistic struct {
-	SchemaName     string        `json:"schema_name"`
-	TableName      string        `json:"table_name"`
-	IndexName      string        `json:"index_name"`
-	CountFetch     int64         `json:"count_fetch"`
-	SumTimerFetch  time.Duration `json:"sum_timer_fetch"`
-	CountInsert    int64         `json:"count_insert"`
-	SumTimerInsert time.Duration `json:"sum_timer_insert"`
-	CountUpdate    int64         `json:"count_update"`
-	SumTimerUpdate time.Duration `json:"sum_timer_update"`
-	CountDelete    int64         `json:"count_delete"`
-	SumTimerDelete time.Duration `json:"sum_timer_delete"`
-}
-
-// WaitEventStatistic contains wait event statistics
-type WaitEventStatistic struct {
-	EventName    string        `json:"event_name"`
-	CountStar    int64         `json:"count_star"`
-	SumTimerWait time.Duration `json:"sum_timer_wait"`
-	MinTimerWait time.Duration `json:"min_timer_wait"`
-	AvgTimerWait time.Duration `json:"avg_timer_wait"`
-	MaxTimerWait time.Duration `json:"max_timer_wait"`
-}
 
-// ConnectionStatistics contains connection-related statistics
-type ConnectionStatistics struct {
-	CurrentConnections int64   `json:"current_connections"`
-	TotalConnections   int64   `json:"total_connections"`
-	ConnectionsPerSec  float64 `json:"connections_per_sec"`
-	AbortedConnections int64   `json:"aborted_connections"`
-	AbortedClients     int64   `json:"aborted_clients"`
-	MaxUsedConnections int64   `json:"max_used_connections"`
-}
-
-// ReplicationStatistics contains replication-related statistics
-type ReplicationStatistics struct {
-	SlaveRunning        bool   `json:"slave_running"`
-	SecondsBehindMaster *int64 `json:"seconds_behind_master"`
-	MasterLogFile       string `json:"master_log_file"`
-	MasterLogPos        int64  `json:"master_log_pos"`
-	RelayLogFile        string `json:"relay_log_file"`
-	RelayLogPos         int64  `json:"relay_log_pos"`
-	LastIOError         string `json:"last_io_error,omitempty"`
-	LastSQLError        string `json:"last_sql_error,omitempty"`
-}
+	// DigestTextFallback recovers the SQL of digests whose text is NULL or truncated (when
+	// performance_schema_digests_size is 0 or max_digest_length is too small) from
+	// events_statements_history, with literals replaced by ?
+	DigestTextFallback bool `yaml:"digest_text_fallback" json:"digest_text_fallback"`
+
+	// QualifyTableNames keys tables referenced with a schema as schema.table instead of
+	// grouping them with unqualified references to the same table name
+	QualifyTableNames bool `yaml:"qualify_table_names" json:"qualify_table_names"`
+
+	// Engine selects the monitored database; empty means MySQL. PostgreSQL has no
+	// Performance Schema, so its statistics views are read instead (see PostgreSQLStatsAdapter).
+	Engine models.DatabaseType `yaml:"engine" json:"engine"`
+
+	// ShareWindow is how long a finished collection is handed to later callers instead of
+	// running a new one; collections still in progress are always shared
+	ShareWindow time.Duration `yaml:"share_window" json:"share_window"`
+
+	// KeepaliveInterval pings the database on this interval so idle connections survive the
+	// server's wait_timeout; a failed ping marks the adapter disconnected until a later ping or
+	// collection reconnects. Zero disables the keepalive.
+	KeepaliveInterval time.Duration `yaml:"keepalive_interval" json:"keepalive_interval"`
+
+	// ReplicaDSN, when set, is a read replica of the same engine that collection queries
+	// run against so they do not load the primary
+	ReplicaDSN string `yaml:"replica_dsn" json:"-"`
+}
+
+// The collected data is declared with ports.PerformanceCollectorPort
+type (
+	PerformanceSchemaData = ports.PerformanceSchemaData
+	GlobalStatusData      = ports.GlobalStatusData
+	StatementStatistic    = ports.StatementStatistic
+	TableIOStatistic      = ports.TableIOStatistic
+	IndexStatistic        = ports.IndexStatistic
+	WaitEventStatistic    = ports.WaitEventStatistic
+	ConnectionStatistics  = ports.ConnectionStatistics
+	ReplicationStatistics = ports.ReplicationStatistics
+	SlowQueryInfo         = ports.SlowQueryInfo
+	TableLockStatistic    = ports.TableLockStatistic
+)
 
-// SlowQueryInfo contains information about slow queries
-type SlowQueryInfo struct {
-	StartTime    time.Time     `json:"start_time"`
-	UserHost     string        `json:"user_host"`
-	QueryTime    time.Duration `json:"query_time"`
-	LockTime     time.Duration `json:"lock_time"`
-	RowsSent     int64         `json:"rows_sent"`
-	RowsExamined int64         `json:"rows_examined"`
-	SQLText      string        `json:"sql_text"`
-	Schema       string        `json:"schema"`
+// statusCounters is a snapshot of the cumulative global status counters used for rates
+type statusCounters struct {
+	Queries      int64
+	Connections  int64
+	Deadlocks    int64
+	RowLockWaits int64
+	Uptime       int64 // seconds
 }
 
 // NewPerformanceSchemaAdapter creates a new Performance Schema adapter
@@ -195,23 +127,27 @@ func NewPerformanceSchemaAdapter(db *sql.DB, logger *logrus.Logger, config *Perf
 		config = defaultPerformanceSchemaConfig()
 	}
 
-	adapter := &PerformanceSchemaAdapter{
-		db:         db,
-		logger:     logger,
-		config:     config,
-		queryCache: make(map[string]*sql.Stmt),
-	}
+	adapter := &PerformanceSchemaAdapter{statsCollector: newStatsCollector(db, logger, config)}
 
 	// Test connection and Performance Schema availability
 	adapter.testConnection()
+	adapter.startKeepalive(adapter.checkPerformanceSchema)
 
 	return adapter
 }
 
-// CollectPerformanceData collects current performance data from Performance Schema
+// CollectPerformanceData collects current performance data from Performance Schema. The
+// result may be shared with concurrent callers and must not be modified.
 func (p *PerformanceSchemaAdapter) CollectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error) {
-	if !p.isConnected {
-		return nil, fmt.Errorf("not connected to MySQL Performance Schema")
+	return p.shareCollection(ctx, p.collectPerformanceData)
+}
+
+// collectPerformanceData runs the collection queries
+func (p *PerformanceSchemaAdapter) collectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error) {
+	if !p.IsConnected() {
+		if err := p.reconnect(ctx, p.checkPerformanceSchema); err != nil {
+			return nil, fmt.Errorf("not connected to MySQL Performance Schema: %w", err)
+		}
 	}
 
 	p.mutex.Lock()
@@ -222,28 +158,35 @@ func (p *PerformanceSchemaAdapter) CollectPerformanceData(ctx context.Context) (
 	}
 
 	// Collect global status
-	if globalStatus, err := p.collectGlobalStatus(ctx); err != nil {
-		p.logger.WithError(err).Warn("Failed to collect global status")
-	} else {
-		data.GlobalStatus = globalStatus
-	}
+	p.withinBudget("global_status", func() {
+		if globalStatus, err := p.collectGlobalStatus(ctx); err != nil {
+			p.logger.WithError(err).Warn("Failed to collect global status")
+		} else {
+			data.GlobalStatus = globalStatus
+		}
+	})
 
 	// Collect statement statistics
 	if p.config.CollectStatements {
-		if statements, err := p.collectStatementStats(ctx); err != nil {
-			p.logger.WithError(err).Warn("Failed to collect statement statistics")
-		} else {
-			data.StatementStats = statements
-		}
+		p.withinBudget(collectionStatements, func() {
+			if statements, err := p.collectStatementStats(ctx); err != nil {
+				p.logger.WithError(err).Warn("Failed to collect statement statistics")
+			} else {
+				data.StatementStats = statements
+			}
+		})
+		p.resetDigestsIfDue(ctx)
 	}
 
 	// Collect table I/O statistics
 	if p.config.CollectTableIO {
-		if tableIO, err := p.collectTableIOStats(ctx); err != nil {
-			p.logger.WithError(err).Warn("Failed to collect table I/O statistics")
-		} else {
-			data.TableIOStats = tableIO
-		}
+		p.withinBudget(collectionTableIO, func() {
+			if tableIO, err := p.collectTableIOStats(ctx); err != nil {
+				p.logger.WithError(err).Warn("Failed to collect table I/O statistics")
+			} else {
+				data.TableIOStats = tableIO
+			}
+		})
 	}
 
 	// Collect index statistics
@@ -283,10 +226,23 @@ func (p *PerformanceSchemaAdapter) CollectPerformanceData(ctx context.Context) (
 	}
 
 	// Collect slow queries
-	if slowQueries, err := p.collectSlowQueries(ctx); err != nil {
-		p.logger.WithError(err).Warn("Failed to collect slow queries")
-	} else {
-		data.SlowQueries = slowQueries
+	p.withinBudget("slow_queries", func() {
+		if slowQueries, err := p.collectSlowQueries(ctx); err != nil {
+			p.logger.WithError(err).Warn("Failed to collect slow queries")
+		} else {
+			data.SlowQueries = slowQueries
+		}
+	})
+
+	// Collect tables with waiting row locks
+	if p.config.CollectWaitEvents {
+		p.withinBudget("table_locks", func() {
+			if tableLocks, err := p.collectTableLockStats(ctx); err != nil {
+				p.logger.WithError(err).Debug("Failed to collect table lock waits (requires MySQL 8.0)")
+			} else {
+				data.TableLockStats = tableLocks
+			}
+		})
 	}
 
 	p.lastCollection = data.CollectionTime
@@ -300,128 +256,44 @@ func (p *PerformanceSchemaAdapter) CollectPerformanceData(ctx context.Context) (
 	return data, nil
 }
 
-// ConvertToPerformanceMetrics converts Performance Schema data to standard performance metrics
-func (p *PerformanceSchemaAdapter) ConvertToPerformanceMetrics(data *PerformanceSchemaData) *ports.PerformanceMetrics {
-	metrics := &ports.PerformanceMetrics{}
-
-	if data.GlobalStatus != nil {
-		metrics.QueriesPerSecond = data.GlobalStatus.QueriesPerSecond
-		// Additional global metrics mapping
-	}
-
-	// Aggregate statement statistics
-	if len(data.StatementStats) > 0 {
-		var totalQueries int64
-		var totalLatency time.Duration
-		var minLatency, maxLatency time.Duration = time.Hour, 0
-
-		for _, stmt := range data.StatementStats {
-			totalQueries += stmt.CountStar
-			totalLatency += stmt.SumTimerWait
+// Private implementation methods
 
-			if stmt.MinTimerWait < minLatency {
-				minLatency = stmt.MinTimerWait
-			}
-			if stmt.MaxTimerWait > maxLatency {
-				maxLatency = stmt.MaxTimerWait
-			}
-		}
+func (p *PerformanceSchemaAdapter) testConnection() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-		if totalQueries > 0 {
-			metrics.AverageLatency = float64(totalLatency.Milliseconds()) / float64(totalQueries)
-			metrics.MinLatency = float64(minLatency.Milliseconds())
-			metrics.MaxLatency = float64(maxLatency.Milliseconds())
-		}
+	if err := p.CheckAvailability(ctx); err != nil {
+		p.logger.WithError(err).Error("Performance Schema is not available")
+		return
 	}
 
-	return metrics
+	p.logger.Info("Connected to MySQL Performance Schema")
 }
 
-// ConvertToQueryPerformance converts statement statistics to query performance data
-func (p *PerformanceSchemaAdapter) ConvertToQueryPerformance(data *PerformanceSchemaData) []ports.QueryPerformance {
-	queryPerformance := make([]ports.QueryPerformance, 0, len(data.StatementStats))
-
-	for _, stmt := range data.StatementStats {
-		// Extract table names from digest text (basic implementation)
-		tables := p.extractTableNames(stmt.DigestText)
-
-		perf := ports.QueryPerformance{
-			QueryPattern:      stmt.DigestText,
-			QueryType:         p.identifyQueryType(stmt.DigestText),
-			ExecutionCount:    stmt.CountStar,
-			TotalTime:         stmt.SumTimerWait,
-			AverageTime:       stmt.AvgTimerWait,
-			MinTime:           stmt.MinTimerWait,
-			MaxTime:           stmt.MaxTimerWait,
-			SourceTables:      tables,
-			RowsExamined:      stmt.SumRowsExamined,
-			RowsReturned:      stmt.SumRowsSent,
-			IndexUsed:         stmt.SumNoIndexUsed == 0,
-			RelationshipType:  p.determineRelationshipType(stmt),
-			PerformanceImpact: p.classifyPerformanceImpact(stmt.AvgTimerWait),
-		}
-
-		queryPerformance = append(queryPerformance, perf)
-	}
-
-	return queryPerformance
+// CheckAvailability pings the database and verifies Performance Schema is present,
+// updating the connection status accordingly
+func (p *PerformanceSchemaAdapter) CheckAvailability(ctx context.Context) error {
+	return p.checkAvailability(ctx, p.checkPerformanceSchema)
 }
 
-// Private implementation methods
-
-func (p *PerformanceSchemaAdapter) testConnection() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
+func (p *PerformanceSchemaAdapter) checkPerformanceSchema(ctx context.Context) error {
 	// Test basic connection
 	if err := p.db.PingContext(ctx); err != nil {
-		p.logger.WithError(err).Error("Failed to ping MySQL database")
-		p.isConnected = false
-		return
+		return fmt.Errorf("failed to ping MySQL database: %w", err)
 	}
 
 	// Test Performance Schema availability
 	var psEnabled int
 	query := "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = 'performance_schema'"
 	if err := p.db.QueryRowContext(ctx, query).Scan(&psEnabled); err != nil {
-		p.logger.WithError(err).Error("Failed to check Performance Schema availability")
-		p.isConnected = false
-		return
+		return fmt.Errorf("failed to check Performance Schema availability: %w", err)
 	}
 
 	if psEnabled == 0 {
-		p.logger.Error("Performance Schema is not available")
-		p.isConnected = false
-		return
-	}
-
-	p.isConnected = true
-	p.logger.Info("Connected to MySQL Performance Schema")
-}
-
-func (p *PerformanceSchemaAdapter) getOrCreateStatement(query string) (*sql.Stmt, error) {
-	p.queryCacheMux.RLock()
-	if stmt, exists := p.queryCache[query]; exists {
-		p.queryCacheMux.RUnlock()
-		return stmt, nil
+		return fmt.Errorf("performance_schema is not enabled")
 	}
-	p.queryCacheMux.RUnlock()
 
-	p.queryCacheMux.Lock()
-	defer p.queryCacheMux.Unlock()
-
-	// Double-check after acquiring write lock
-	if stmt, exists := p.queryCache[query]; exists {
-		return stmt, nil
-	}
-
-	stmt, err := p.db.Prepare(query)
-	if err != nil {
-		return nil, err
-	}
-
-	p.queryCache[query] = stmt
-	return stmt, nil
+	return nil
 }
 
 func (p *PerformanceSchemaAdapter) collectGlobalStatus(ctx context.Context) (*GlobalStatusData, error) {
@@ -431,11 +303,12 @@ func (p *PerformanceSchemaAdapter) collectGlobalStatus(ctx context.Context) (*Gl
 			variable_value 
 		FROM performance_schema.global_status 
 		WHERE variable_name IN (
-			'Queries', 'Connections', 'Slow_queries', 'Open_tables',
+			'Queries', 'Connections', 'Uptime', 'Slow_queries', 'Open_tables',
 			'Threads_running', 'Threads_connected',
 			'Innodb_buffer_pool_read_requests', 'Innodb_buffer_pool_reads',
 			'Key_read_requests', 'Key_reads',
-			'Created_tmp_tables', 'Created_tmp_disk_tables'
+			'Created_tmp_tables', 'Created_tmp_disk_tables',
+			'Innodb_deadlocks', 'Innodb_row_lock_waits', 'Innodb_row_lock_current_waits'
 		)`
 
 	rows, err := p.db.QueryContext(ctx, query)
@@ -456,18 +329,20 @@ func (p *PerformanceSchemaAdapter) collectGlobalStatus(ctx context.Context) (*Gl
 	status := &GlobalStatusData{}
 
 	// Parse numeric values
-	if val, exists := statusMap["Queries"]; exists {
-		if queries, err := strconv.ParseInt(val, 10, 64); err == nil {
-			// Calculate QPS based on uptime (simplified)
-			status.QueriesPerSecond = float64(queries) / 60.0 // Rough estimate
-		}
-	}
-
-	if val, exists := statusMap["Connections"]; exists {
-		if connections, err := strconv.ParseInt(val, 10, 64); err == nil {
-			status.ConnectionsPerSecond = float64(connections) / 60.0
-		}
+	var counters statusCounters
+	counters.Queries, _ = strconv.ParseInt(statusMap["Queries"], 10, 64)
+	counters.Connections, _ = strconv.ParseInt(statusMap["Connections"], 10, 64)
+	counters.Uptime, _ = strconv.ParseInt(statusMap["Uptime"], 10, 64)
+	if value, exists := statusMap["Innodb_deadlocks"]; exists {
+		counters.Deadlocks, _ = strconv.ParseInt(value, 10, 64)
+	} else if deadlocks, err := p.mysqlDeadlockCount(ctx); err != nil {
+		p.logger.WithError(err).Debug("Deadlock counter unavailable")
+	} else {
+		counters.Deadlocks = deadlocks
 	}
+	counters.RowLockWaits, _ = strconv.ParseInt(statusMap["Innodb_row_lock_waits"], 10, 64)
+	status.CurrentLockWaits, _ = strconv.ParseInt(statusMap["Innodb_row_lock_current_waits"], 10, 64)
+	p.applyCounters(status, counters)
 
 	if val, exists := statusMap["Slow_queries"]; exists {
 		if slowQueries, err := strconv.ParseInt(val, 10, 64); err == nil {
@@ -496,6 +371,9 @@ func (p *PerformanceSchemaAdapter) collectGlobalStatus(ctx context.Context) (*Gl
 }
 
 func (p *PerformanceSchemaAdapter) collectStatementStats(ctx context.Context) ([]StatementStatistic, error) {
+	// Narrow the digest scan to focused tables so LIMIT does not cut them off
+	focusCondition, focusArgs := p.focusedDigestCondition()
+
 	query := `
 		SELECT 
 			COALESCE(schema_name, 'NULL') as schema_name,
@@ -521,16 +399,14 @@ func (p *PerformanceSchemaAdapter) collectStatementStats(ctx context.Context) ([
 			last_seen
 		FROM performance_schema.events_statements_summary_by_digest 
 		WHERE count_star >= ?
-		  AND avg_timer_wait >= ?
+		  AND avg_timer_wait >= ?` + focusCondition + `
 		ORDER BY sum_timer_wait DESC
 		LIMIT ?`
 
 	minLatencyNanos := int64(p.config.MinAvgLatency * 1000000) // Convert ms to nanoseconds
 
-	rows, err := p.db.QueryContext(ctx, query,
-		p.config.MinExecutionCount,
-		minLatencyNanos,
-		p.config.MaxStatements)
+	args := append([]interface{}{p.config.MinExecutionCount, minLatencyNanos}, focusArgs...)
+	rows, err := p.db.QueryContext(ctx, query, append(args, p.config.MaxStatements)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query statement statistics: %w", err)
 	}
@@ -574,15 +450,23 @@ func (p *PerformanceSchemaAdapter) collectStatementStats(ctx context.Context) ([
 			stmt.DigestText = digestText.String
 		}
 
-		// Skip ignored schemas
-		if p.shouldIgnoreSchema(stmt.SchemaName) {
-			continue
-		}
-
 		statements = append(statements, stmt)
 	}
 
-	return statements, nil
+	missing, recovered, err := p.recoverDigestTexts(ctx, statements)
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to recover missing digest text")
+	}
+	p.warnDigestTextUnavailable(missing, recovered)
+
+	// Skip ignored schemas and tables, and statements outside the focus
+	collected := statements[:0]
+	for _, stmt := range statements {
+		if p.shouldCollectStatement(stmt) {
+			collected = append(collected, stmt)
+		}
+	}
+	return collected, nil
 }
 
 func (p *PerformanceSchemaAdapter) collectTableIOStats(ctx context.Context) ([]TableIOStatistic, error) {
@@ -640,7 +524,7 @@ func (p *PerformanceSchemaAdapter) collectTableIOStats(ctx context.Context) ([]T
 			continue
 		}
 
-		if p.shouldIgnoreSchema(stat.SchemaName) {
+		if p.shouldIgnoreSchema(stat.SchemaName) || !p.shouldCollectTable(stat.SchemaName, stat.TableName) {
 			continue
 		}
 
@@ -679,110 +563,42 @@ func (p *PerformanceSchemaAdapter) collectSlowQueries(ctx context.Context) ([]Sl
 
 // Helper methods
 
-func (p *PerformanceSchemaAdapter) shouldIgnoreSchema(schema string) bool {
-	for _, ignored := range p.config.IgnoredSchemas {
-		if schema == ignored {
-			return true
-		}
-	}
-	return false
-}
-
-func (p *PerformanceSchemaAdapter) extractTableNames(digestText string) []string {
-	// Simple table name extraction from SQL text
-	// This is a basic implementation - could be enhanced with proper SQL parsing
-	tables := make([]string, 0)
-
-	if digestText == "" {
-		return tables
+// focusedDigestCondition returns a coarse SQL filter matching digests that mention a
+// focused table; shouldCollectStatement makes the exact decision
+func (p *PerformanceSchemaAdapter) focusedDigestCondition() (string, []interface{}) {
+	if len(p.config.FocusedTables) == 0 {
+		return "", nil
 	}
 
-	// Look for FROM clauses and JOIN clauses
-	lowerText := strings.ToLower(digestText)
-	words := strings.Fields(lowerText)
-
-	for i, word := range words {
-		if word == "from" || word == "join" || word == "update" || word == "into" {
-			if i+1 < len(words) && !strings.Contains(words[i+1], "(") {
-				tableName := strings.Trim(words[i+1], "`,")
-				if tableName != "" && !strings.Contains(tableName, " ") {
-					tables = append(tables, tableName)
-				}
-			}
+	conditions := make([]string, 0, len(p.config.FocusedTables))
+	args := make([]interface{}, 0, len(p.config.FocusedTables))
+	for _, focused := range p.config.FocusedTables {
+		name := focused
+		if i := strings.LastIndex(focused, "."); i >= 0 {
+			name = focused[i+1:]
 		}
+		conditions = append(conditions, "digest_text LIKE ?")
+		args = append(args, "%"+name+"%")
 	}
-
-	return tables
-}
-
-func (p *PerformanceSchemaAdapter) identifyQueryType(digestText string) string {
-	if digestText == "" {
-		return "UNKNOWN"
-	}
-
-	lowerText := strings.ToLower(strings.TrimSpace(digestText))
-
-	if strings.HasPrefix(lowerText, "select") {
-		return "SELECT"
-	} else if strings.HasPrefix(lowerText, "insert") {
-		return "INSERT"
-	} else if strings.HasPrefix(lowerText, "update") {
-		return "UPDATE"
-	} else if strings.HasPrefix(lowerText, "delete") {
-		return "DELETE"
-	} else if strings.HasPrefix(lowerText, "create") {
-		return "CREATE"
-	} else if strings.HasPrefix(lowerText, "drop") {
-		return "DROP"
-	} else if strings.HasPrefix(lowerText, "alter") {
-		return "ALTER"
+	if p.config.DigestTextFallback {
+		// Digests without text may turn out to be focused once their SQL is recovered
+		conditions = append(conditions, "digest_text IS NULL", "digest_text = ''")
 	}
-
-	return "OTHER"
+	return "\n\t\t  AND (" + strings.Join(conditions, " OR ") + ")", args
 }
 
-func (p *PerformanceSchemaAdapter) determineRelationshipType(stmt StatementStatistic) string {
-	if stmt.SumSelectFullJoin > 0 {
-		return "FULL_JOIN"
-	}
-	if strings.Contains(strings.ToLower(stmt.DigestText), "join") {
-		return "JOIN"
-	}
-	if strings.Contains(strings.ToLower(stmt.DigestText), "where") {
-		return "FILTERED"
-	}
-	return "SINGLE_TABLE"
-}
-
-func (p *PerformanceSchemaAdapter) classifyPerformanceImpact(avgLatency time.Duration) string {
-	latencyMs := float64(avgLatency.Milliseconds())
-
-	if latencyMs < 10 {
-		return "LOW"
-	} else if latencyMs < 100 {
-		return "MEDIUM"
-	}
-	return "HIGH"
-}
-
-// IsConnected returns the connection status
-func (p *PerformanceSchemaAdapter) IsConnected() bool {
-	p.mutex.RLock()
-	defer p.mutex.RUnlock()
-	return p.isConnected
-}
-
-// Close closes the adapter and cleans up resources
-func (p *PerformanceSchemaAdapter) Close() error {
-	p.queryCacheMux.Lock()
-	defer p.queryCacheMux.Unlock()
-
-	for _, stmt := range p.queryCache {
-		stmt.Close()
+// matchesTable reports whether schema.table is listed, either qualified or by table name alone
+func matchesTable(list []string, schema, table string) bool {
+	for _, entry := range list {
+		if i := strings.LastIndex(entry, "."); i >= 0 {
+			if strings.EqualFold(entry[:i], schema) && strings.EqualFold(entry[i+1:], table) {
+				return true
+			}
+		} else if strings.EqualFold(entry, table) {
+			return true
+		}
 	}
-	p.queryCache = make(map[string]*sql.Stmt)
-
-	return nil
+	return false
 }
 
 // defaultPerformanceSchemaConfig returns default configuration
@@ -802,12 +618,13 @@ func defaultPerformanceSchemaConfig() *PerformanceSchemaConfig {
 		MaxStatements: 100,
 		MaxTables:     50,
 
-		IgnoredSchemas: []string{"mysql", "information_schema", "performance_schema", "sys"},
+		IgnoredSchemas: models.DefaultSystemSchemas(models.DatabaseTypeMySQL),
 		IgnoredUsers:   []string{"root", "mysql.sys", "mysql.session"},
 		FocusedTables:  []string{},
 
-		EnableDigestText:  true,
-		MinExecutionCount: 10,
-		MinAvgLatency:     10.0, // 10ms
+		EnableDigestText:   true,
+		DigestTextFallback: true,
+		MinExecutionCount:  10,
+		MinAvgLatency:      10.0, // 10ms
 	}
 }
@@ -0,0 +1,319 @@
+package performance
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFilterTestAdapter(configure func(*PerformanceSchemaConfig)) *PerformanceSchemaAdapter {
+	config := defaultPerformanceSchemaConfig()
+	configure(config)
+	return &PerformanceSchemaAdapter{statsCollector: &statsCollector{logger: logrus.New(), config: config}}
+}
+
+func collectedDigests(p *PerformanceSchemaAdapter, statements []StatementStatistic) []string {
+	var digests []string
+	for _, stmt := range statements {
+		if p.shouldCollectStatement(stmt) {
+			digests = append(digests, stmt.Digest)
+		}
+	}
+	return digests
+}
+
+var filterTestStatements = []StatementStatistic{
+	{Digest: "orders", SchemaName: "shop", DigestText: "SELECT * FROM `orders` WHERE `id` = ?"},
+	{Digest: "orders-join", SchemaName: "shop", DigestText: "SELECT * FROM `orders` `o` JOIN `customers` `c` ON `o`.`customer_id` = `c`.`id`"},
+	{Digest: "products", SchemaName: "shop", DigestText: "SELECT * FROM `products`"},
+	{Digest: "audit-only", SchemaName: "shop", DigestText: "INSERT INTO `audit`.`events` VALUES (...)"},
+	{Digest: "cross-schema", SchemaName: "shop", DigestText: "SELECT * FROM `customers` JOIN `sys`.`session` ON ?"},
+	{Digest: "set", SchemaName: "shop", DigestText: "SET `autocommit` = ?"},
+	{Digest: "mysql", SchemaName: "mysql", DigestText: "SELECT * FROM `user`"},
+}
+
+func TestStatementFilteringWithoutFocus(t *testing.T) {
+	p := newFilterTestAdapter(func(c *PerformanceSchemaConfig) {
+		c.IgnoredSchemas = append(c.IgnoredSchemas, "audit")
+		c.IgnoredTables = []string{"products"}
+	})
+
+	assert.Equal(t, []string{"orders", "orders-join", "cross-schema", "set"}, collectedDigests(p, filterTestStatements),
+		"statements only touching ignored schemas or tables are dropped; a cross-schema join with a collected table is kept")
+}
+
+func TestFocusedTablesRestrictCollection(t *testing.T) {
+	p := newFilterTestAdapter(func(c *PerformanceSchemaConfig) {
+		c.FocusedTables = []string{"shop.customers"}
+	})
+
+	assert.Equal(t, []string{"orders-join", "cross-schema"}, collectedDigests(p, filterTestStatements))
+	assert.True(t, p.shouldCollectTable("shop", "customers"))
+	assert.False(t, p.shouldCollectTable("shop", "orders"), "focus overrides broad table I/O collection")
+	assert.False(t, p.shouldCollectTable("archive", "customers"), "qualified focus is schema specific")
+
+	condition, args := p.focusedDigestCondition()
+	assert.Contains(t, condition, "digest_text LIKE ?")
+	assert.Equal(t, []interface{}{"%customers%"}, args)
+}
+
+func TestIgnoredTablesWinOverFocus(t *testing.T) {
+	p := newFilterTestAdapter(func(c *PerformanceSchemaConfig) {
+		c.FocusedTables = []string{"orders"}
+		c.IgnoredTables = []string{"shop.orders"}
+	})
+
+	assert.Empty(t, collectedDigests(p, filterTestStatements))
+}
+
+func TestCounterRates_UsesDeltaOverUptimeInterval(t *testing.T) {
+	p := newFilterTestAdapter(func(*PerformanceSchemaConfig) {})
+
+	qps, cps := p.counterRates(statusCounters{Queries: 50000, Connections: 1000, Uptime: 1000})
+	assert.InDelta(t, 50.0, qps, 1e-9, "first sample averages over server uptime")
+	assert.InDelta(t, 1.0, cps, 1e-9)
+
+	qps, cps = p.counterRates(statusCounters{Queries: 53000, Connections: 1030, Uptime: 1030})
+	assert.InDelta(t, 3000.0/30.0, qps, 1e-9, "rate is counter delta over elapsed uptime")
+	assert.InDelta(t, 30.0/30.0, cps, 1e-9)
+
+	qps, _ = p.counterRates(statusCounters{Queries: 53600, Connections: 1030, Uptime: 1060})
+	assert.InDelta(t, 600.0/30.0, qps, 1e-9)
+}
+
+func TestCounterRates_RestartFallsBackToUptimeAverage(t *testing.T) {
+	p := newFilterTestAdapter(func(*PerformanceSchemaConfig) {})
+
+	p.counterRates(statusCounters{Queries: 90000, Uptime: 900})
+	qps, _ := p.counterRates(statusCounters{Queries: 400, Uptime: 20})
+	assert.InDelta(t, 20.0, qps, 1e-9)
+
+	qps, _ = p.counterRates(statusCounters{Queries: 1400, Uptime: 30})
+	assert.InDelta(t, 100.0, qps, 1e-9, "rates resume from the post-restart sample")
+}
+
+// slowDigest answers every query with no rows, taking delay for the statement digest scan
+// and recording the LIMIT it was given
+var slowDigest struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	limits []int64
+}
+
+func slowDigestQuery(dsn, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "events_statements_summary_by_digest") {
+		slowDigest.mu.Lock()
+		slowDigest.limits = append(slowDigest.limits, args[len(args)-1].Value.(int64))
+		slowDigest.mu.Unlock()
+		time.Sleep(slowDigest.delay)
+	}
+	return emptyRows{}, nil
+}
+
+func newBudgetTestAdapter(t *testing.T, autoReduce bool) (*PerformanceSchemaAdapter, *test.Hook) {
+	slowDigest.delay = 30 * time.Millisecond
+	slowDigest.limits = nil
+	db := openStubDB(t, &stubDriver{query: slowDigestQuery}, "")
+
+	logger, hook := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	config.CollectionBudget = 10 * time.Millisecond
+	config.AutoReduceLimits = autoReduce
+	return &PerformanceSchemaAdapter{statsCollector: &statsCollector{db: db, logger: logger, config: config, isConnected: true}}, hook
+}
+
+func budgetWarnings(hook *test.Hook) []*logrus.Entry {
+	var warnings []*logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "time budget") {
+			warnings = append(warnings, entry)
+		}
+	}
+	return warnings
+}
+
+func TestOverBudgetCollectionLogsWarning(t *testing.T) {
+	p, hook := newBudgetTestAdapter(t, false)
+
+	_, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	warnings := budgetWarnings(hook)
+	require.Len(t, warnings, 1, "only the slow digest scan is over budget")
+	assert.Equal(t, collectionStatements, warnings[0].Data["collection"])
+	assert.Equal(t, 100, p.config.MaxStatements, "limits stay unless auto-reduction is enabled")
+}
+
+func TestAutoReduceLowersMaxStatementsOnNextRun(t *testing.T) {
+	p, hook := newBudgetTestAdapter(t, true)
+
+	for i := 0; i < 4; i++ {
+		_, err := p.CollectPerformanceData(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []int64{100, 50, 25, 12}, slowDigest.limits)
+	assert.Equal(t, 10, p.config.MaxStatements, "limits are not reduced below the floor")
+	assert.Equal(t, 50, p.config.MaxTables, "table I/O stayed within budget")
+	assert.Equal(t, 12, budgetWarnings(hook)[2].Data["max_statements"])
+}
+
+func newReplicaTestAdapter(t *testing.T, replicaDSN string) (*PostgreSQLStatsAdapter, *sql.DB, []string) {
+	var opened []string
+	original := openReplicaDB
+	openReplicaDB = func(driverName, dsn string) (*sql.DB, error) {
+		opened = append(opened, driverName+" "+dsn)
+		// Every connection to the DSN "unreachable" is refused
+		return sql.OpenDB(stubConnector{&stubDriver{down: dsn == "unreachable"}, dsn}), nil
+	}
+	t.Cleanup(func() { openReplicaDB = original })
+
+	primary := openStubDB(t, &stubDriver{}, "primary")
+
+	logger, _ := test.NewNullLogger()
+	config := defaultPostgreSQLStatsConfig()
+	config.ReplicaDSN = replicaDSN
+	p := NewPostgreSQLStatsAdapter(primary, logger, config)
+	t.Cleanup(func() { p.Close() })
+	return p, primary, opened
+}
+
+func TestAdapterCollectsFromReplicaWhenConfigured(t *testing.T) {
+	p, primary, opened := newReplicaTestAdapter(t, "host=replica dbname=shop")
+
+	assert.Equal(t, []string{"postgres host=replica dbname=shop"}, opened)
+	assert.True(t, p.UsingReplica())
+	assert.NotSame(t, primary, p.db, "collection queries go to the replica")
+	assert.True(t, p.IsConnected())
+
+	require.NoError(t, p.Close())
+	assert.NoError(t, primary.PingContext(context.Background()), "closing the adapter leaves the primary open")
+}
+
+func TestAdapterUsesPrimaryWithoutReplica(t *testing.T) {
+	p, primary, opened := newReplicaTestAdapter(t, "")
+
+	assert.Empty(t, opened)
+	assert.False(t, p.UsingReplica())
+	assert.Same(t, primary, p.db)
+}
+
+func TestAdapterFallsBackToPrimaryWhenReplicaIsUnreachable(t *testing.T) {
+	p, primary, opened := newReplicaTestAdapter(t, "unreachable")
+
+	assert.Len(t, opened, 1)
+	assert.False(t, p.UsingReplica())
+	assert.Same(t, primary, p.db)
+	assert.True(t, p.IsConnected())
+}
+
+func TestConcurrentCollectionsShareOneRun(t *testing.T) {
+	p, _ := newBudgetTestAdapter(t, false)
+
+	const callers = 8
+	results := make([]*PerformanceSchemaData, callers)
+	var start, wg sync.WaitGroup
+	start.Add(1)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start.Wait()
+			data, err := p.CollectPerformanceData(context.Background())
+			assert.NoError(t, err)
+			results[i] = data
+		}(i)
+	}
+	start.Done()
+	wg.Wait()
+
+	assert.Len(t, slowDigest.limits, 1, "the statement digests are queried once")
+	for _, data := range results {
+		assert.Same(t, results[0], data)
+	}
+
+	// Without a share window the next call collects again
+	next, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+	assert.NotSame(t, results[0], next)
+	assert.Len(t, slowDigest.limits, 2)
+}
+
+func TestShareWindowReusesFinishedCollection(t *testing.T) {
+	p, _ := newBudgetTestAdapter(t, false)
+	p.config.ShareWindow = time.Minute
+
+	first, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+	second, err := p.CollectPerformanceData(context.Background())
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Len(t, slowDigest.limits, 1)
+}
+
+func TestWaitingCollectorStopsOnCancel(t *testing.T) {
+	p, _ := newBudgetTestAdapter(t, false)
+	slowDigest.delay = 200 * time.Millisecond
+
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, _ = p.CollectPerformanceData(context.Background())
+	}()
+	require.Eventually(t, func() bool {
+		p.flightMutex.Lock()
+		defer p.flightMutex.Unlock()
+		return p.flight != nil
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.CollectPerformanceData(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	<-leaderDone
+}
+
+func TestSharedCollectionOutlivesLeaderCancel(t *testing.T) {
+	p, hook := newBudgetTestAdapter(t, false)
+	p.config.CollectionBudget = time.Second
+	slowDigest.delay = 100 * time.Millisecond
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := p.CollectPerformanceData(leaderCtx)
+		leaderErr <- err
+	}()
+	require.Eventually(t, func() bool {
+		p.flightMutex.Lock()
+		defer p.flightMutex.Unlock()
+		return p.flight != nil
+	}, time.Second, time.Millisecond)
+
+	followerData := make(chan *PerformanceSchemaData, 1)
+	go func() {
+		data, err := p.CollectPerformanceData(context.Background())
+		assert.NoError(t, err)
+		followerData <- data
+	}()
+
+	// The client that started the collection goes away
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	assert.NotNil(t, <-followerData, "the follower still gets the shared collection")
+	assert.Len(t, slowDigest.limits, 1)
+	for _, entry := range hook.AllEntries() {
+		assert.NotContains(t, entry.Message, "Failed to collect", "no query was cancelled with the leader")
+	}
+}
@@ -0,0 +1,301 @@
+package performance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PostgreSQLStatsAdapter collects performance data from PostgreSQL's statistics views:
+// pg_stat_database, pg_stat_statements, pg_stat_user_tables and pg_locks. Concurrent
+// collections, filtering and conversion are shared with PerformanceSchemaAdapter.
+type PostgreSQLStatsAdapter struct {
+	*statsCollector
+}
+
+// NewPostgreSQLStatsAdapter creates a collector for a PostgreSQL database. config is copied
+// with its Engine set to PostgreSQL; a nil config uses defaults ignoring the PostgreSQL
+// system schemas.
+func NewPostgreSQLStatsAdapter(db *sql.DB, logger *logrus.Logger, config *PerformanceSchemaConfig) *PostgreSQLStatsAdapter {
+	pgConfig := defaultPostgreSQLStatsConfig()
+	if config != nil {
+		copied := *config
+		copied.Engine = models.DatabaseTypePostgreSQL
+		pgConfig = &copied
+	}
+
+	adapter := &PostgreSQLStatsAdapter{statsCollector: newStatsCollector(db, logger, pgConfig)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := adapter.CheckAvailability(ctx); err != nil {
+		logger.WithError(err).Error("PostgreSQL statistics views are not available")
+	} else {
+		logger.Info("Connected to PostgreSQL statistics views")
+	}
+	adapter.startKeepalive(adapter.checkDatabase)
+
+	return adapter
+}
+
+// defaultPostgreSQLStatsConfig returns the default configuration for PostgreSQL
+func defaultPostgreSQLStatsConfig() *PerformanceSchemaConfig {
+	config := defaultPerformanceSchemaConfig()
+	config.Engine = models.DatabaseTypePostgreSQL
+	config.IgnoredSchemas = models.DefaultSystemSchemas(models.DatabaseTypePostgreSQL)
+	config.IgnoredUsers = []string{"postgres"}
+	return config
+}
+
+// CollectPerformanceData collects what PostgreSQL's statistics views offer: database-wide
+// counters from pg_stat_database, statements from pg_stat_statements, table activity from
+// pg_stat_user_tables and waiting locks from pg_locks. The result may be shared with
+// concurrent callers and must not be modified.
+func (p *PostgreSQLStatsAdapter) CollectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error) {
+	return p.shareCollection(ctx, p.collectPerformanceData)
+}
+
+// collectPerformanceData runs the statistics view queries
+func (p *PostgreSQLStatsAdapter) collectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error) {
+	if !p.IsConnected() {
+		if err := p.reconnect(ctx, p.checkDatabase); err != nil {
+			return nil, fmt.Errorf("not connected to PostgreSQL: %w", err)
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	data := &PerformanceSchemaData{
+		CollectionTime: time.Now(),
+	}
+
+	if status, err := p.collectPostgreSQLStatus(ctx); err != nil {
+		p.logger.WithError(err).Warn("Failed to collect PostgreSQL database statistics")
+	} else {
+		data.GlobalStatus = status
+	}
+
+	if p.config.CollectStatements {
+		p.withinBudget(collectionStatements, func() {
+			if statements, err := p.collectPostgreSQLStatements(ctx); err != nil {
+				p.logger.WithError(err).Debug("Failed to collect statement statistics (requires the pg_stat_statements extension)")
+			} else {
+				data.StatementStats = statements
+			}
+		})
+	}
+
+	if p.config.CollectTableIO {
+		p.withinBudget(collectionTableIO, func() {
+			if tableIO, err := p.collectPostgreSQLTableIO(ctx); err != nil {
+				p.logger.WithError(err).Warn("Failed to collect PostgreSQL table statistics")
+			} else {
+				data.TableIOStats = tableIO
+			}
+		})
+	}
+
+	if p.config.CollectWaitEvents {
+		if tableLocks, err := p.collectPostgreSQLTableLocks(ctx); err != nil {
+			p.logger.WithError(err).Warn("Failed to collect PostgreSQL lock waits")
+		} else {
+			data.TableLockStats = tableLocks
+		}
+	}
+
+	p.lastCollection = data.CollectionTime
+	return data, nil
+}
+
+// CheckAvailability pings the database, updating the connection status accordingly. The
+// statistics views are always present; pg_stat_statements is checked when it is queried.
+func (p *PostgreSQLStatsAdapter) CheckAvailability(ctx context.Context) error {
+	return p.checkAvailability(ctx, p.checkDatabase)
+}
+
+func (p *PostgreSQLStatsAdapter) checkDatabase(ctx context.Context) error {
+	if err := p.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping PostgreSQL database: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgreSQLStatsAdapter) collectPostgreSQLStatus(ctx context.Context) (*GlobalStatusData, error) {
+	query := `
+		SELECT
+			d.xact_commit + d.xact_rollback,
+			d.numbackends,
+			d.deadlocks,
+			(SELECT COUNT(*) FROM pg_locks WHERE NOT granted),
+			EXTRACT(EPOCH FROM now() - pg_postmaster_start_time())::bigint
+		FROM pg_stat_database d
+		WHERE d.datname = current_database()`
+
+	var counters statusCounters
+	status := &GlobalStatusData{}
+	if err := p.db.QueryRowContext(ctx, query).Scan(
+		&counters.Queries,
+		&status.ThreadsConnected,
+		&counters.Deadlocks,
+		&status.CurrentLockWaits,
+		&counters.Uptime,
+	); err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_database: %w", err)
+	}
+
+	// PostgreSQL keeps no cumulative lock wait counter, so only current waits are reported
+	p.applyCounters(status, counters)
+	return status, nil
+}
+
+// collectPostgreSQLStatements reads the normalized statements of the current database from
+// pg_stat_statements (PostgreSQL 13 or later). Statements carry no schema, so they are
+// attributed to the current schema and unqualified tables resolve against it.
+func (p *PostgreSQLStatsAdapter) collectPostgreSQLStatements(ctx context.Context) ([]StatementStatistic, error) {
+	query := `
+		SELECT
+			current_schema(),
+			s.queryid::text,
+			s.query,
+			s.calls,
+			s.total_exec_time,
+			s.min_exec_time,
+			s.mean_exec_time,
+			s.max_exec_time,
+			s.rows
+		FROM pg_stat_statements s
+		JOIN pg_database d ON d.oid = s.dbid
+		WHERE d.datname = current_database()
+		  AND s.calls >= $1
+		  AND s.mean_exec_time >= $2
+		ORDER BY s.total_exec_time DESC
+		LIMIT $3`
+
+	rows, err := p.db.QueryContext(ctx, query, p.config.MinExecutionCount, p.config.MinAvgLatency, p.config.MaxStatements)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_statements: %w", err)
+	}
+	defer rows.Close()
+
+	// pg_stat_statements reports times as fractional milliseconds
+	milliseconds := func(ms float64) models.Duration {
+		return models.Duration(ms * float64(time.Millisecond))
+	}
+
+	var statements []StatementStatistic
+	for rows.Next() {
+		var stmt StatementStatistic
+		var total, minimum, mean, maximum float64
+		if err := rows.Scan(
+			&stmt.SchemaName,
+			&stmt.Digest,
+			&stmt.DigestText,
+			&stmt.CountStar,
+			&total,
+			&minimum,
+			&mean,
+			&maximum,
+			&stmt.SumRowsSent,
+		); err != nil {
+			p.logger.WithError(err).Debug("Failed to scan pg_stat_statements row")
+			continue
+		}
+		stmt.SumTimerWait = milliseconds(total)
+		stmt.MinTimerWait = milliseconds(minimum)
+		stmt.AvgTimerWait = milliseconds(mean)
+		stmt.MaxTimerWait = milliseconds(maximum)
+
+		if p.shouldCollectStatement(stmt) {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements, rows.Err()
+}
+
+// collectPostgreSQLTableIO reads row activity per table from pg_stat_user_tables. PostgreSQL
+// counts rows rather than timing table access, so the timer fields stay zero.
+func (p *PostgreSQLStatsAdapter) collectPostgreSQLTableIO(ctx context.Context) ([]TableIOStatistic, error) {
+	query := `
+		SELECT
+			schemaname,
+			relname,
+			seq_tup_read + COALESCE(idx_tup_fetch, 0) AS rows_read,
+			n_tup_ins,
+			n_tup_upd,
+			n_tup_del
+		FROM pg_stat_user_tables
+		WHERE seq_tup_read + COALESCE(idx_tup_fetch, 0) + n_tup_ins + n_tup_upd + n_tup_del > 0
+		ORDER BY seq_tup_read + COALESCE(idx_tup_fetch, 0) + n_tup_ins + n_tup_upd + n_tup_del DESC
+		LIMIT $1`
+
+	rows, err := p.db.QueryContext(ctx, query, p.config.MaxTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_user_tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tableStats []TableIOStatistic
+	for rows.Next() {
+		var stat TableIOStatistic
+		if err := rows.Scan(
+			&stat.SchemaName,
+			&stat.TableName,
+			&stat.CountRead,
+			&stat.CountInsert,
+			&stat.CountUpdate,
+			&stat.CountDelete,
+		); err != nil {
+			p.logger.WithError(err).Debug("Failed to scan table statistics row")
+			continue
+		}
+		stat.CountFetch = stat.CountRead
+		stat.CountWrite = stat.CountInsert + stat.CountUpdate + stat.CountDelete
+
+		if p.shouldIgnoreSchema(stat.SchemaName) || !p.shouldCollectTable(stat.SchemaName, stat.TableName) {
+			continue
+		}
+		tableStats = append(tableStats, stat)
+	}
+	return tableStats, rows.Err()
+}
+
+func (p *PostgreSQLStatsAdapter) collectPostgreSQLTableLocks(ctx context.Context) ([]TableLockStatistic, error) {
+	query := `
+		SELECT
+			n.nspname,
+			c.relname,
+			COUNT(*) AS waiting_locks
+		FROM pg_locks l
+		JOIN pg_class c ON c.oid = l.relation
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT l.granted
+		GROUP BY n.nspname, c.relname
+		ORDER BY waiting_locks DESC
+		LIMIT $1`
+
+	rows, err := p.db.QueryContext(ctx, query, p.config.MaxTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_locks: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []TableLockStatistic
+	for rows.Next() {
+		var stat TableLockStatistic
+		if err := rows.Scan(&stat.SchemaName, &stat.TableName, &stat.WaitingLocks); err != nil {
+			p.logger.WithError(err).Debug("Failed to scan lock row")
+			continue
+		}
+		if !p.shouldCollectTable(stat.SchemaName, stat.TableName) {
+			continue
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
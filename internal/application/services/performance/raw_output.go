@@ -0,0 +1,121 @@
+package performance
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RawOutputEncodingGzip marks raw output stored gzipped and base64 encoded
+const RawOutputEncodingGzip = "gzip+base64"
+
+// defaultRawOutputMaxSize is how much raw output a result keeps by default
+const defaultRawOutputMaxSize = 64 * 1024
+
+// RawOutputConfig bounds the raw tool output kept with every benchmark result. Metrics are
+// parsed from the complete output before it is reduced.
+type RawOutputConfig struct {
+	// MaxSize is how many bytes of output a result keeps, taken from the end where tools
+	// print their summary; zero keeps all of it
+	MaxSize int `yaml:"max_size" json:"max_size"`
+	// Compress stores the kept output gzipped and base64 encoded (RawOutputEncodingGzip)
+	Compress bool `yaml:"compress" json:"compress"`
+	// SpillDir, when set, receives the complete output of every run as <id>.log, and the
+	// result references the file
+	SpillDir string `yaml:"spill_dir" json:"spill_dir"`
+}
+
+// retainRawOutput spills, truncates and compresses the raw output of a result as
+// configured. A failed spill is logged and the output is still reduced.
+func (s *BenchmarkService) retainRawOutput(result *ports.BenchmarkResult) {
+	config := s.config.RawOutput
+	output := result.RawOutput
+	if output == "" {
+		return
+	}
+	result.RawOutputSize = len(output)
+
+	if config.SpillDir != "" {
+		path := filepath.Join(config.SpillDir, result.ID+".log")
+		if err := os.MkdirAll(config.SpillDir, 0o755); err != nil {
+			s.logger.WithError(err).Warn("Failed to create benchmark output directory")
+		} else if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+			s.logger.WithError(err).Warn("Failed to spill benchmark output to disk")
+		} else {
+			result.RawOutputFile = path
+		}
+	}
+
+	if config.MaxSize > 0 && len(output) > config.MaxSize {
+		start := len(output) - config.MaxSize
+		for start < len(output) && !utf8.RuneStart(output[start]) {
+			start++
+		}
+		output = output[start:]
+		result.RawOutputTruncated = true
+	}
+
+	if config.Compress {
+		compressed, err := compressRawOutput(output)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to compress benchmark output")
+		} else {
+			output = compressed
+			result.RawOutputEncoding = RawOutputEncodingGzip
+		}
+	}
+	result.RawOutput = output
+
+	s.logger.WithFields(logrus.Fields{
+		"execution_id": result.ID,
+		"output_size":  result.RawOutputSize,
+		"kept_size":    len(result.RawOutput),
+		"output_file":  result.RawOutputFile,
+	}).Debug("Retained benchmark output")
+}
+
+func compressRawOutput(output string) (string, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := io.WriteString(writer, output); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// DecodeRawOutput returns the raw output kept with result as text, decompressing it if needed
+func DecodeRawOutput(result *ports.BenchmarkResult) (string, error) {
+	switch result.RawOutputEncoding {
+	case "":
+		return result.RawOutput, nil
+	case RawOutputEncodingGzip:
+		compressed, err := base64.StdEncoding.DecodeString(result.RawOutput)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode raw output: %w", err)
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return "", fmt.Errorf("failed to decompress raw output: %w", err)
+		}
+		defer reader.Close()
+		decoded, err := io.ReadAll(reader)
+		if err != nil {
+			return "", fmt.Errorf("failed to decompress raw output: %w", err)
+		}
+		return string(decoded), nil
+	default:
+		return "", fmt.Errorf("unknown raw output encoding %q", result.RawOutputEncoding)
+	}
+}
@@ -0,0 +1,104 @@
+package performance
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sysbenchSummary is the end of a sysbench run, printed after its progress reports
+const sysbenchSummary = "SQL statistics:\n    queries performed:\n        total: 120000\n    transactions: 6000 (100.00 per sec.)\n"
+
+// outputBenchmarkTool finishes every run at once with a long raw output and parsed metrics
+type outputBenchmarkTool struct {
+	*gatedBenchmarkTool
+	output string
+}
+
+func (o *outputBenchmarkTool) Execute(ctx context.Context, config ports.BenchmarkConfig) (*ports.BenchmarkResult, error) {
+	return &ports.BenchmarkResult{
+		TestType:  config.TestType,
+		EndTime:   time.Now(),
+		Metrics:   &ports.PerformanceMetrics{QueriesPerSecond: 2000, TransactionsPerSec: 100, Percentile95: 12.5},
+		RawOutput: o.output,
+	}, nil
+}
+
+func runWithRawOutput(t *testing.T, rawOutput RawOutputConfig) (*ports.BenchmarkResult, string) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	config := defaultBenchmarkServiceConfig()
+	config.RawOutput = rawOutput
+	service := NewBenchmarkService(nil, nil, nil, nil, logger, config)
+
+	output := strings.Repeat("[ 1s ] thds: 8 tps: 100.00 qps: 2000.00 lat (ms,95%): 12.52\n", 500) + sysbenchSummary
+	require.NoError(t, service.RegisterBenchmarkTool("output", &outputBenchmarkTool{newGatedBenchmarkTool(), output}))
+
+	id, err := service.ExecuteBenchmark(context.Background(), ports.BenchmarkConfig{TestType: "oltp_read_only"}, "output")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return benchmarkStatus(t, service, id) == ports.BenchmarkStatusCompleted
+	}, time.Second, 5*time.Millisecond)
+
+	result, err := service.GetBenchmarkResult(id)
+	require.NoError(t, err)
+	return result, output
+}
+
+func TestRawOutputBeyondMaxSizeIsTruncated(t *testing.T) {
+	result, output := runWithRawOutput(t, RawOutputConfig{MaxSize: 1024})
+
+	assert.True(t, result.RawOutputTruncated)
+	assert.Len(t, result.RawOutput, 1024)
+	assert.Equal(t, len(output), result.RawOutputSize)
+	assert.True(t, strings.HasSuffix(result.RawOutput, sysbenchSummary), "the summary at the end is kept")
+	assert.Equal(t, 2000.0, result.Metrics.QueriesPerSecond, "metrics parsed from the full output are unchanged")
+	assert.Equal(t, 12.5, result.Metrics.Percentile95)
+}
+
+func TestRawOutputIsCompressed(t *testing.T) {
+	result, output := runWithRawOutput(t, RawOutputConfig{Compress: true})
+
+	assert.Equal(t, RawOutputEncodingGzip, result.RawOutputEncoding)
+	assert.False(t, result.RawOutputTruncated)
+	assert.Less(t, len(result.RawOutput), len(output)/10, "repetitive progress lines compress well")
+
+	decoded, err := DecodeRawOutput(result)
+	require.NoError(t, err)
+	assert.Equal(t, output, decoded)
+	assert.Equal(t, 100.0, result.Metrics.TransactionsPerSec)
+}
+
+func TestRawOutputSpillsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	result, output := runWithRawOutput(t, RawOutputConfig{MaxSize: 256, Compress: true, SpillDir: dir})
+
+	assert.Equal(t, filepath.Join(dir, result.ID+".log"), result.RawOutputFile)
+	spilled, err := os.ReadFile(result.RawOutputFile)
+	require.NoError(t, err)
+	assert.Equal(t, output, string(spilled), "the file holds the complete output")
+
+	decoded, err := DecodeRawOutput(result)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 256)
+	assert.True(t, strings.HasSuffix(decoded, sysbenchSummary))
+}
+
+func TestRawOutputKeptWithoutLimits(t *testing.T) {
+	result, output := runWithRawOutput(t, RawOutputConfig{})
+
+	assert.Equal(t, output, result.RawOutput)
+	assert.False(t, result.RawOutputTruncated)
+	assert.Empty(t, result.RawOutputEncoding)
+}
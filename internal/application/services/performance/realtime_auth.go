@@ -0,0 +1,221 @@
+package performance
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// AllowAll in Topics or Tables grants every topic or table
+const AllowAll = "*"
+
+// ClientCapabilities is what a WebSocket client may see. Topics lists the topics it may
+// subscribe to and receive; Tables limits table-scoped data (alerts and graph nodes) and
+// allows every table when empty.
+type ClientCapabilities struct {
+	Role   string   `json:"role"`
+	Topics []string `json:"topics"`
+	Tables []string `json:"tables,omitempty"`
+}
+
+// WebSocketAuthConfig enables the auth handshake. Clients send {"type": "auth", "token": ...}
+// and get the capabilities of that token; until then they have the Anonymous capabilities,
+// and no topics at all when Anonymous is nil.
+type WebSocketAuthConfig struct {
+	Tokens    map[string]ClientCapabilities
+	Anonymous *ClientCapabilities
+}
+
+// WebSocketError is the data of an "error" message sent to a client
+type WebSocketError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// unrestricted is reported to clients that authenticate while auth is disabled
+var unrestricted = ClientCapabilities{Role: "admin", Topics: []string{AllowAll}}
+
+func (c *ClientCapabilities) allowsTopic(topic string) bool {
+	return c == nil || containsOrAll(c.Topics, topic)
+}
+
+func (c *ClientCapabilities) allowsTable(table string) bool {
+	return c == nil || len(c.Tables) == 0 || containsOrAll(c.Tables, table)
+}
+
+func (c *ClientCapabilities) tableRestricted() bool {
+	return c != nil && len(c.Tables) > 0 && !containsOrAll(c.Tables, AllowAll)
+}
+
+func containsOrAll(values []string, value string) bool {
+	for _, v := range values {
+		if v == AllowAll || strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// restrict returns the part of a broadcast the client may see, or false when nothing is left
+func (c *ClientCapabilities) restrict(data interface{}) (interface{}, bool) {
+	if !c.tableRestricted() {
+		return data, true
+	}
+
+	switch v := data.(type) {
+	case *PerformanceAlert:
+		return v, v.TableName == "" || c.allowsTable(v.TableName)
+	case *PerformanceGraphData:
+		return c.restrictGraph(v), true
+	}
+	return data, true
+}
+
+// restrictGraph drops nodes of other tables together with their edges, hotspots and bottlenecks
+func (c *ClientCapabilities) restrictGraph(data *PerformanceGraphData) *PerformanceGraphData {
+	restricted := *data
+	restricted.Nodes = nil
+	restricted.Edges = nil
+	restricted.Hotspots = nil
+	restricted.Bottlenecks = nil
+
+	kept := make(map[string]bool)
+	for _, node := range data.Nodes {
+		if c.allowsTable(node.TableName) {
+			kept[node.ID] = true
+			restricted.Nodes = append(restricted.Nodes, node)
+		}
+	}
+	for _, edge := range data.Edges {
+		if kept[edge.SourceID] && kept[edge.TargetID] {
+			restricted.Edges = append(restricted.Edges, edge)
+		}
+	}
+	for _, hotspot := range data.Hotspots {
+		if kept[hotspot.NodeID] {
+			restricted.Hotspots = append(restricted.Hotspots, hotspot)
+		}
+	}
+	for _, bottleneck := range data.Bottlenecks {
+		if kept[bottleneck.Location] || c.allowsTable(bottleneck.Location) {
+			restricted.Bottlenecks = append(restricted.Bottlenecks, bottleneck)
+		}
+	}
+	return &restricted
+}
+
+// initialCapabilities returns the capabilities of a newly connected client; nil when auth is disabled
+func (rpm *RealtimePerformanceMonitor) initialCapabilities() *ClientCapabilities {
+	auth := rpm.config.Auth
+	if auth == nil {
+		return nil
+	}
+	if auth.Anonymous == nil {
+		return &ClientCapabilities{Role: "anonymous"}
+	}
+	anonymous := *auth.Anonymous
+	return &anonymous
+}
+
+// authenticate looks up the capabilities for token, comparing every configured token in
+// constant time
+func (rpm *RealtimePerformanceMonitor) authenticate(token string) (*ClientCapabilities, bool) {
+	auth := rpm.config.Auth
+	if auth == nil {
+		return &unrestricted, true
+	}
+
+	var granted *ClientCapabilities
+	for candidate, capabilities := range auth.Tokens {
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+			granted = &capabilities
+		}
+	}
+	return granted, granted != nil
+}
+
+func (rpm *RealtimePerformanceMonitor) authenticateClient(conn *websocket.Conn, clientInfo *ClientInfo, token string) {
+	capabilities, ok := rpm.authenticate(token)
+	if !ok {
+		rpm.logger.WithField("client_id", clientInfo.ID).Warn("WebSocket client sent an invalid auth token")
+		rpm.sendError(conn, clientInfo, "", "unauthorized", "invalid auth token")
+		return
+	}
+
+	if rpm.config.Auth != nil {
+		clientInfo.Capabilities = capabilities
+	}
+	// Default topics withheld from the anonymous client are subscribed once they are allowed
+	topics := clientInfo.SubscribedTopics
+	for _, topic := range defaultClientTopics {
+		if !rpm.clientSubscribedToTopic(clientInfo, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	clientInfo.SubscribedTopics = allowedTopics(clientInfo.Capabilities, topics)
+
+	rpm.sendMessageToClient(conn, clientInfo, &WebSocketMessage{
+		Type:      "auth",
+		Data:      capabilities,
+		Timestamp: time.Now(),
+		ID:        "auth",
+	})
+}
+
+// allowedTopics keeps the topics the capabilities permit
+func allowedTopics(capabilities *ClientCapabilities, topics []string) []string {
+	allowed := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if capabilities.allowsTopic(topic) {
+			allowed = append(allowed, topic)
+		}
+	}
+	return allowed
+}
+
+func (rpm *RealtimePerformanceMonitor) sendError(conn *websocket.Conn, clientInfo *ClientInfo, topic, code, message string) {
+	rpm.sendMessageToClient(conn, clientInfo, &WebSocketMessage{
+		Type:      "error",
+		Topic:     topic,
+		Data:      WebSocketError{Code: code, Message: message},
+		Timestamp: time.Now(),
+		ID:        fmt.Sprintf("error-%d", time.Now().UnixNano()),
+	})
+}
+
+// FilterForAnonymous returns the parts of messages an unauthenticated client may see, so
+// the long-polling fallback does not bypass WebSocket auth
+func (rpm *RealtimePerformanceMonitor) FilterForAnonymous(messages []*WebSocketMessage) []*WebSocketMessage {
+	capabilities := rpm.initialCapabilities()
+	if capabilities == nil {
+		return messages
+	}
+
+	visible := make([]*WebSocketMessage, 0, len(messages))
+	for _, message := range messages {
+		if restricted, ok := restrictMessage(capabilities, message); ok {
+			visible = append(visible, restricted)
+		}
+	}
+	return visible
+}
+
+// restrictMessage returns the message as the client may see it, sharing it when unchanged
+func restrictMessage(capabilities *ClientCapabilities, message *WebSocketMessage) (*WebSocketMessage, bool) {
+	if !capabilities.allowsTopic(message.Topic) {
+		return nil, false
+	}
+	data, ok := capabilities.restrict(message.Data)
+	if !ok {
+		return nil, false
+	}
+	if !capabilities.tableRestricted() {
+		return message, true
+	}
+	restricted := *message
+	restricted.Data = data
+	return &restricted, true
+}
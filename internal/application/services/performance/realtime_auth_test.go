@@ -0,0 +1,165 @@
+package performance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthTestMonitor(t *testing.T) *RealtimePerformanceMonitor {
+	t.Helper()
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.Auth = &WebSocketAuthConfig{
+		Tokens: map[string]ClientCapabilities{
+			"reader-token": {Role: "read-only", Topics: []string{"performance", "alerts"}, Tables: []string{"orders"}},
+			"admin-token":  {Role: "admin", Topics: []string{AllowAll}},
+		},
+	}
+	return rpm
+}
+
+// readMessage reads the next frame, decoding the data into data when it is not nil
+func readMessage(t *testing.T, conn *websocket.Conn, data interface{}) WebSocketMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var raw struct {
+		WebSocketMessage
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&raw))
+	if data != nil {
+		require.NoError(t, json.Unmarshal(raw.Data, data))
+	}
+	return raw.WebSocketMessage
+}
+
+// send writes a client message and waits for the monitor to process it, using a ping as a
+// barrier. It returns the reply sent before the pong, if any.
+func send(t *testing.T, conn *websocket.Conn, msg map[string]string, reply interface{}) WebSocketMessage {
+	t.Helper()
+	require.NoError(t, conn.WriteJSON(msg))
+	require.NoError(t, conn.WriteJSON(map[string]string{"type": "ping"}))
+
+	var message WebSocketMessage
+	for {
+		var data json.RawMessage
+		next := readMessage(t, conn, &data)
+		if next.Type == "pong" {
+			return message
+		}
+		message = next
+		if reply != nil {
+			require.NoError(t, json.Unmarshal(data, reply))
+		}
+	}
+}
+
+func TestRestrictedClientCannotSubscribeToAdminTopic(t *testing.T) {
+	rpm := newAuthTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	var granted ClientCapabilities
+	message := send(t, conn, map[string]string{"type": "auth", "token": "reader-token"}, &granted)
+	assert.Equal(t, "auth", message.Type)
+	assert.Equal(t, "read-only", granted.Role)
+
+	var rejection WebSocketError
+	message = send(t, conn, map[string]string{"type": "subscribe", "topic": "metrics"}, &rejection)
+	assert.Equal(t, "error", message.Type)
+	assert.Equal(t, "metrics", message.Topic)
+	assert.Equal(t, "forbidden", rejection.Code)
+
+	client := rpm.GetConnectedClients()[0]
+	assert.Equal(t, []string{"performance", "alerts"}, client.SubscribedTopics)
+	_, recipients := rpm.broadcastToClients("metrics", &RealtimeMetrics{})
+	assert.Zero(t, recipients)
+}
+
+func TestAdminClientCanSubscribeToAnyTopic(t *testing.T) {
+	rpm := newAuthTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	send(t, conn, map[string]string{"type": "auth", "token": "admin-token"}, nil)
+	send(t, conn, map[string]string{"type": "subscribe", "topic": "metrics"}, nil)
+
+	_, recipients := rpm.broadcastToClients("metrics", &RealtimeMetrics{})
+	assert.Equal(t, 1, recipients)
+	assert.Equal(t, "metrics", readMessage(t, conn, nil).Topic)
+}
+
+func TestUnauthenticatedClientSeesOnlyAnonymousTopics(t *testing.T) {
+	rpm := newAuthTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	assert.Empty(t, rpm.GetConnectedClients()[0].SubscribedTopics, "no anonymous capabilities are configured")
+
+	var rejection WebSocketError
+	message := send(t, conn, map[string]string{"type": "auth", "token": "guessed"}, &rejection)
+	assert.Equal(t, "error", message.Type)
+	assert.Equal(t, "unauthorized", rejection.Code)
+
+	message = send(t, conn, map[string]string{"type": "subscribe", "topic": "alerts"}, &rejection)
+	assert.Equal(t, "forbidden", rejection.Code)
+
+	_, recipients := rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "alert-1"})
+	assert.Zero(t, recipients)
+}
+
+func TestTableRestrictedClientReceivesOnlyItsTables(t *testing.T) {
+	rpm := newAuthTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+	send(t, conn, map[string]string{"type": "auth", "token": "reader-token"}, nil)
+
+	_, recipients := rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "hidden", TableName: "payments"})
+	assert.Zero(t, recipients)
+
+	rpm.broadcastToClients("performance", &PerformanceGraphData{
+		ID: "graph",
+		Nodes: []PerformanceGraphNode{
+			{ID: "n1", TableName: "orders"},
+			{ID: "n2", TableName: "payments"},
+		},
+		Edges:    []PerformanceGraphEdge{{ID: "e1", SourceID: "n1", TargetID: "n2"}},
+		Hotspots: []HotspotInfo{{NodeID: "n1"}, {NodeID: "n2"}},
+	})
+
+	var graph PerformanceGraphData
+	readMessage(t, conn, &graph)
+	require.Len(t, graph.Nodes, 1)
+	assert.Equal(t, "orders", graph.Nodes[0].TableName)
+	assert.Empty(t, graph.Edges)
+	assert.Equal(t, []HotspotInfo{{NodeID: "n1"}}, graph.Hotspots)
+}
+
+func TestAuthWithoutAuthConfigGrantsEverything(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	var granted ClientCapabilities
+	send(t, conn, map[string]string{"type": "auth", "token": "anything"}, &granted)
+	assert.Equal(t, []string{AllowAll}, granted.Topics)
+	assert.Nil(t, rpm.GetConnectedClients()[0].Capabilities)
+}
+
+func TestFilterForAnonymous(t *testing.T) {
+	messages := []*WebSocketMessage{
+		{Topic: "alerts", Data: &PerformanceAlert{ID: "a1", TableName: "orders"}},
+		{Topic: "alerts", Data: &PerformanceAlert{ID: "a2", TableName: "payments"}},
+		{Topic: "metrics", Data: &RealtimeMetrics{}},
+	}
+
+	rpm := newTestRealtimeMonitor(t)
+	assert.Equal(t, messages, rpm.FilterForAnonymous(messages), "auth disabled")
+
+	rpm = newAuthTestMonitor(t)
+	assert.Empty(t, rpm.FilterForAnonymous(messages))
+
+	rpm.config.Auth.Anonymous = &ClientCapabilities{Role: "public", Topics: []string{"alerts"}, Tables: []string{"orders"}}
+	visible := rpm.FilterForAnonymous(messages)
+	require.Len(t, visible, 1)
+	assert.Equal(t, "a1", visible[0].Data.(*PerformanceAlert).ID)
+}
@@ -2,11 +2,13 @@ package performance
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
+	"sql-graph-visualizer/internal/application/ports"
 	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/gorilla/websocket"
@@ -17,7 +19,7 @@ import (
 type RealtimePerformanceMonitor struct {
 	logger      *logrus.Logger
 	config      *RealtimeMonitorConfig
-	psAdapter   *PerformanceSchemaAdapter
+	psAdapter   ports.PerformanceCollectorPort
 	analyzer    *PerformanceAnalyzer
 	graphMapper *GraphPerformanceMapper
 
@@ -31,6 +33,11 @@ type RealtimePerformanceMonitor struct {
 	runningMutex sync.RWMutex
 	stopChannel  chan struct{}
 
+	// Pausing skips collection ticks while keeping clients connected
+	paused     bool
+	pausedAt   time.Time
+	pauseMutex sync.RWMutex
+
 	// Data channels
 	performanceData chan *PerformanceGraphData
 	alertsChannel   chan *PerformanceAlert
@@ -39,8 +46,32 @@ type RealtimePerformanceMonitor struct {
 	lastGraphData *PerformanceGraphData
 	lastUpdate    time.Time
 	stateMutex    sync.RWMutex
+
+	// Long-polling fallback for clients that cannot upgrade to WebSocket
+	pollBuffer []*WebSocketMessage
+	pollNotify chan struct{}
+	pollMutex  sync.Mutex
+
+	// Broadcasts within CoalesceWindow are queued per client and flushed as one frame
+	pending      map[*websocket.Conn]*pendingBatch
+	pendingTimer *time.Timer
+	pendingMutex sync.Mutex
+
+	// statementDeltas is the baseline of StatementDeltas, advanced only by collection ticks
+	statementDeltas statementDeltaTracker
+
+	// history keeps collected metrics for MetricsRetention; historyStore, when HistoryFile
+	// is set, persists them for backfilling after a restart
+	history      *MetricsHistory
+	historyStore *MetricsHistoryStore
 }
 
+// defaultClientTopics are subscribed for every new client
+var defaultClientTopics = []string{"performance", "alerts"}
+
+// defaultPollBufferSize bounds the long-polling buffer when no size is configured
+const defaultPollBufferSize = 100
+
 // RealtimeMonitorConfig contains configuration for real-time .monitoring
 type RealtimeMonitorConfig struct {
 	// Update intervals
@@ -54,11 +85,38 @@ type RealtimeMonitorConfig struct {
 	PingTimeout    time.Duration `yaml:"ping_timeout" json:"ping_timeout"`
 	MaxMessageSize int64         `yaml:"max_message_size" json:"max_message_size"`
 
+	// Auth, when set, restricts each client to the topics and tables its auth token grants
+	Auth *WebSocketAuthConfig `yaml:"-" json:"-"`
+
+	// MaxOutboundMessageSize is the largest frame sent to a client; larger messages
+	// are split into "chunk" messages. Zero uses the default, a negative value disables chunking.
+	MaxOutboundMessageSize int `yaml:"max_outbound_message_size" json:"max_outbound_message_size"`
+
+	// CoalesceWindow batches the broadcasts a client receives within the window into one
+	// "batch" message. Zero sends every broadcast as its own frame.
+	CoalesceWindow time.Duration `yaml:"coalesce_window" json:"coalesce_window"`
+
+	// Long-polling fallback
+	PollBufferSize  int           `yaml:"poll_buffer_size" json:"poll_buffer_size"`
+	MaxPollDuration time.Duration `yaml:"max_poll_duration" json:"max_poll_duration"`
+
+	// StatementDeltas reports statement statistics per collection interval: the digest
+	// summary is cumulative since its last reset, so each digest's counters at the previous
+	// tick are subtracted
+	StatementDeltas bool `yaml:"statement_deltas" json:"statement_deltas"`
+
 	// Performance .monitoring
 	AlertThresholds    AlertThresholds `yaml:"alert_thresholds" json:"alert_thresholds"`
 	MetricsRetention   time.Duration   `yaml:"metrics_retention" json:"metrics_retention"`
 	CompressionEnabled bool            `yaml:"compression_enabled" json:"compression_enabled"`
 
+	// HistoryFile persists every metrics sample; empty keeps the history in memory only. The
+	// file is trimmed to MetricsRetention (or BackfillWindow, if longer) on Start and
+	// periodically while running. BackfillWindow loads the samples of that period from the
+	// file on Start, zero disables it.
+	HistoryFile    string        `yaml:"history_file" json:"history_file"`
+	BackfillWindow time.Duration `yaml:"backfill_window" json:"backfill_window"`
+
 	// Resource limits
 	MaxConcurrentQueries int     `yaml:"max_concurrent_queries" json:"max_concurrent_queries"`
 	MemoryLimitMB        int     `yaml:"memory_limit_mb" json:"memory_limit_mb"`
@@ -83,6 +141,11 @@ type ClientInfo struct {
 	SubscribedTopics []string               `json:"subscribed_topics"`
 	Filters          map[string]interface{} `json:"filters"`
 	Compression      bool                   `json:"compression"`
+	// Capabilities is nil when WebSocket auth is disabled
+	Capabilities *ClientCapabilities `json:"capabilities,omitempty"`
+
+	// writeMutex keeps the chunks of one message contiguous on the connection
+	writeMutex sync.Mutex
 }
 
 // WebSocketMessage represents a WebSocket message structure
@@ -94,6 +157,30 @@ type WebSocketMessage struct {
 	ID        string      `json:"id"`
 }
 
+// WebSocketChunk is the data of a "chunk" message. Clients concatenate the payloads of
+// all chunks with the same MessageID in Sequence order and decode the result as the
+// original WebSocketMessage.
+type WebSocketChunk struct {
+	MessageID string `json:"message_id"`
+	Sequence  int    `json:"sequence"`
+	Total     int    `json:"total"`
+	Payload   []byte `json:"payload"` // base64 encoded in JSON
+}
+
+// pendingBatch holds the messages queued for one client during a coalesce window
+type pendingBatch struct {
+	client   *ClientInfo
+	messages []*WebSocketMessage
+}
+
+const (
+	// defaultMaxOutboundMessageSize keeps frames well below common client limits
+	defaultMaxOutboundMessageSize = 1 << 20
+
+	// chunkEnvelopeSize is reserved in each chunk frame for the message and chunk headers
+	chunkEnvelopeSize = 512
+)
+
 // PerformanceAlert represents a performance alert
 type PerformanceAlert struct {
 	ID          string                 `json:"id"`
@@ -116,6 +203,7 @@ type RealtimeMetrics struct {
 	DatabaseMetrics   *DatabaseMetrics         `json:"database_metrics"`
 	ActiveConnections int                      `json:"active_connections"`
 	TopQueries        []QueryPerformanceMetric `json:"top_queries"`
+	ContendedTables   []TableLockStatistic     `json:"contended_tables,omitempty"`
 	Alerts            []PerformanceAlert       `json:"alerts"`
 	GraphData         *PerformanceGraphData    `json:"graph_data,omitempty"`
 }
@@ -137,7 +225,11 @@ type DatabaseMetrics struct {
 	SlowQueries      int64   `json:"slow_queries"`
 	ConnectionsUsed  int     `json:"connections_used"`
 	ConnectionsMax   int     `json:"connections_max"`
-	InnoDBBufferPool struct {
+	// Lock contention over the last collection interval
+	DeadlocksPerMinute float64 `json:"deadlocks_per_minute"`
+	LockWaitsPerMinute float64 `json:"lock_waits_per_minute"`
+	CurrentLockWaits   int64   `json:"current_lock_waits"`
+	InnoDBBufferPool   struct {
 		HitRatio     float64 `json:"hit_ratio"`
 		Usage        float64 `json:"usage"`
 		PagesRead    int64   `json:"pages_read"`
@@ -160,7 +252,7 @@ type QueryPerformanceMetric struct {
 func NewRealtimePerformanceMonitor(
 	logger *logrus.Logger,
 	config *RealtimeMonitorConfig,
-	psAdapter *PerformanceSchemaAdapter,
+	psAdapter ports.PerformanceCollectorPort,
 	analyzer *PerformanceAnalyzer,
 	graphMapper *GraphPerformanceMapper,
 ) *RealtimePerformanceMonitor {
@@ -168,7 +260,7 @@ func NewRealtimePerformanceMonitor(
 		config = defaultRealtimeMonitorConfig()
 	}
 
-	return &RealtimePerformanceMonitor{
+	rpm := &RealtimePerformanceMonitor{
 		logger:      logger,
 		config:      config,
 		psAdapter:   psAdapter,
@@ -186,7 +278,14 @@ func NewRealtimePerformanceMonitor(
 		performanceData: make(chan *PerformanceGraphData, 100),
 		alertsChannel:   make(chan *PerformanceAlert, 200),
 		stopChannel:     make(chan struct{}),
+		pollNotify:      make(chan struct{}),
+		history:         NewMetricsHistory(config.MetricsRetention),
+	}
+	if config.HistoryFile != "" {
+		rpm.historyStore = NewMetricsHistoryStore(config.HistoryFile)
 	}
+
+	return rpm
 }
 
 // Start begins real-time .monitoring
@@ -200,11 +299,16 @@ func (rpm *RealtimePerformanceMonitor) Start(ctx context.Context) error {
 	rpm.runningMutex.Unlock()
 
 	rpm.logger.Info("Starting real-time performance monitor")
+	rpm.backfillHistory()
+	rpm.trimHistory()
 
 	// Start .monitoring goroutines
 	go rpm.performanceCollectionLoop(ctx)
 	go rpm.alertProcessingLoop(ctx)
 	go rpm.clientCleanupLoop(ctx)
+	if rpm.historyStore != nil {
+		go rpm.historyTrimLoop(ctx)
+	}
 
 	return nil
 }
@@ -223,6 +327,15 @@ func (rpm *RealtimePerformanceMonitor) Stop() error {
 	// Signal stop
 	close(rpm.stopChannel)
 
+	// Queued broadcasts are dropped with the connections they were meant for
+	rpm.pendingMutex.Lock()
+	if rpm.pendingTimer != nil {
+		rpm.pendingTimer.Stop()
+		rpm.pendingTimer = nil
+	}
+	rpm.pending = nil
+	rpm.pendingMutex.Unlock()
+
 	// Close all WebSocket connections
 	rpm.clientMutex.Lock()
 	for conn := range rpm.clients {
@@ -235,6 +348,50 @@ func (rpm *RealtimePerformanceMonitor) Stop() error {
 	return nil
 }
 
+// Pause stops performance data collection without disconnecting clients.
+// It returns false if collection was already paused.
+func (rpm *RealtimePerformanceMonitor) Pause() bool {
+	rpm.pauseMutex.Lock()
+	defer rpm.pauseMutex.Unlock()
+
+	if rpm.paused {
+		return false
+	}
+	rpm.paused = true
+	rpm.pausedAt = time.Now()
+	rpm.logger.Info("Real-time performance collection paused")
+	return true
+}
+
+// Resume restarts performance data collection after Pause.
+// It returns false if collection was not paused.
+func (rpm *RealtimePerformanceMonitor) Resume() bool {
+	rpm.pauseMutex.Lock()
+	defer rpm.pauseMutex.Unlock()
+
+	if !rpm.paused {
+		return false
+	}
+	rpm.paused = false
+	rpm.pausedAt = time.Time{}
+	rpm.logger.Info("Real-time performance collection resumed")
+	return true
+}
+
+// IsPaused reports whether collection is paused
+func (rpm *RealtimePerformanceMonitor) IsPaused() bool {
+	rpm.pauseMutex.RLock()
+	defer rpm.pauseMutex.RUnlock()
+	return rpm.paused
+}
+
+// PausedSince returns when collection was paused, or the zero time if it is running
+func (rpm *RealtimePerformanceMonitor) PausedSince() time.Time {
+	rpm.pauseMutex.RLock()
+	defer rpm.pauseMutex.RUnlock()
+	return rpm.pausedAt
+}
+
 // HandleWebSocket handles new WebSocket connections
 func (rpm *RealtimePerformanceMonitor) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Check connection limit
@@ -255,13 +412,15 @@ func (rpm *RealtimePerformanceMonitor) HandleWebSocket(w http.ResponseWriter, r
 	}
 
 	// Create client info
+	capabilities := rpm.initialCapabilities()
 	clientInfo := &ClientInfo{
 		ID:               fmt.Sprintf("client-%d", time.Now().UnixNano()),
 		ConnectedAt:      time.Now(),
 		LastPingAt:       time.Now(),
-		SubscribedTopics: []string{"performance", "alerts"},
+		SubscribedTopics: allowedTopics(capabilities, defaultClientTopics),
 		Filters:          make(map[string]interface{}),
 		Compression:      false,
+		Capabilities:     capabilities,
 	}
 
 	// Register client
@@ -280,6 +439,7 @@ func (rpm *RealtimePerformanceMonitor) HandleWebSocket(w http.ResponseWriter, r
 
 	// Handle client messages
 	go rpm.handleClientMessages(conn, clientInfo)
+	go rpm.heartbeatLoop(conn, clientInfo)
 }
 
 // Private methods for .monitoring loops and client handling
@@ -295,6 +455,9 @@ func (rpm *RealtimePerformanceMonitor) performanceCollectionLoop(ctx context.Con
 		case <-rpm.stopChannel:
 			return
 		case <-ticker.C:
+			if rpm.IsPaused() {
+				continue
+			}
 			if err := rpm.collectAndBroadcastPerformanceData(ctx); err != nil {
 				rpm.logger.WithError(err).Error("Failed to collect performance data")
 			}
@@ -337,6 +500,9 @@ func (rpm *RealtimePerformanceMonitor) collectAndBroadcastPerformanceData(ctx co
 	if err != nil {
 		return fmt.Errorf("failed to collect performance data: %w", err)
 	}
+	if rpm.config.StatementDeltas {
+		perfData = rpm.intervalStatements(perfData)
+	}
 
 	// TODO: Get base graph from graph service
 	var baseGraph *models.Graph // This would come from the graph service
@@ -359,6 +525,7 @@ func (rpm *RealtimePerformanceMonitor) collectAndBroadcastPerformanceData(ctx co
 
 	// Generate metrics summary
 	metrics := rpm.generateRealtimeMetrics(perfData)
+	rpm.recordSample(sampleFromMetrics(metrics))
 	rpm.broadcastToClients("metrics", metrics)
 
 	// Check for alerts
@@ -390,6 +557,7 @@ func (rpm *RealtimePerformanceMonitor) generateRealtimeMetrics(perfData *Perform
 		SystemMetrics:   rpm.collectSystemMetrics(),
 		DatabaseMetrics: rpm.collectDatabaseMetrics(perfData),
 		TopQueries:      topQueries,
+		ContendedTables: perfData.TableLockStats,
 		Alerts:          make([]PerformanceAlert, 0),
 	}
 }
@@ -410,12 +578,18 @@ func (rpm *RealtimePerformanceMonitor) collectDatabaseMetrics(perfData *Performa
 		totalQueries += stmt.CountStar
 	}
 
-	return &DatabaseMetrics{
-		QueriesPerSecond: float64(totalQueries) / rpm.config.DataUpdateInterval.Seconds(),
+	metrics := &DatabaseMetrics{
+		QueriesPerSecond: safeDivide(float64(totalQueries), rpm.config.DataUpdateInterval.Seconds()),
 		SlowQueries:      0,    // TODO: Calculate from perfData
 		ConnectionsUsed:  1,    // ConnectionStats is a struct, not slice - use 1
 		ConnectionsMax:   1000, // TODO: Get from MySQL configuration
 	}
+	if status := perfData.GlobalStatus; status != nil {
+		metrics.DeadlocksPerMinute = status.DeadlocksPerMinute
+		metrics.LockWaitsPerMinute = status.LockWaitsPerMinute
+		metrics.CurrentLockWaits = status.CurrentLockWaits
+	}
+	return metrics
 }
 
 func (rpm *RealtimePerformanceMonitor) checkAndGenerateAlerts(perfData *PerformanceSchemaData) {
@@ -434,17 +608,59 @@ func (rpm *RealtimePerformanceMonitor) checkAndGenerateAlerts(perfData *Performa
 				Threshold:   rpm.config.AlertThresholds.SlowQueryThreshold,
 				Timestamp:   time.Now(),
 			}
-			select {
-			case rpm.alertsChannel <- alert:
-			default:
-				rpm.logger.Warn("Alert channel full, dropping alert")
-			}
+			rpm.queueAlert(alert)
 		}
 	}
+
+	if alert := rpm.deadlockAlert(perfData); alert != nil {
+		rpm.queueAlert(alert)
+	}
+}
+
+// deadlockAlert reports a deadlock rate above DeadlockThreshold (deadlocks per minute), naming
+// the tables with the most waiting locks
+func (rpm *RealtimePerformanceMonitor) deadlockAlert(perfData *PerformanceSchemaData) *PerformanceAlert {
+	threshold := float64(rpm.config.AlertThresholds.DeadlockThreshold)
+	if perfData.GlobalStatus == nil || threshold <= 0 || perfData.GlobalStatus.DeadlocksPerMinute <= threshold {
+		return nil
+	}
+
+	rate := perfData.GlobalStatus.DeadlocksPerMinute
+	alert := &PerformanceAlert{
+		ID:          fmt.Sprintf("deadlock-%d", time.Now().UnixNano()),
+		Type:        "deadlock",
+		Severity:    severityForRatio(rate / threshold),
+		Title:       "Deadlocks Detected",
+		Description: fmt.Sprintf("%.1f deadlocks per minute exceeds threshold of %.0f", rate, threshold),
+		Value:       rate,
+		Threshold:   threshold,
+		Timestamp:   time.Now(),
+		Metadata: map[string]interface{}{
+			"current_lock_waits": perfData.GlobalStatus.CurrentLockWaits,
+		},
+	}
+	if len(perfData.TableLockStats) > 0 {
+		top := perfData.TableLockStats[0]
+		alert.TableName = top.TableName
+		alert.Metadata["contended_tables"] = perfData.TableLockStats
+	}
+	return alert
+}
+
+func (rpm *RealtimePerformanceMonitor) queueAlert(alert *PerformanceAlert) {
+	select {
+	case rpm.alertsChannel <- alert:
+	default:
+		rpm.logger.Warn("Alert channel full, dropping alert")
+	}
 }
 
 func (rpm *RealtimePerformanceMonitor) determineSeverity(value float64) string {
-	ratio := value / rpm.config.AlertThresholds.SlowQueryThreshold
+	return severityForRatio(value / rpm.config.AlertThresholds.SlowQueryThreshold)
+}
+
+// severityForRatio grades how far a value is above its alert threshold
+func severityForRatio(ratio float64) string {
 	if ratio > 3.0 {
 		return "critical"
 	} else if ratio > 2.0 {
@@ -455,7 +671,13 @@ func (rpm *RealtimePerformanceMonitor) determineSeverity(value float64) string {
 	return "low"
 }
 
-func (rpm *RealtimePerformanceMonitor) broadcastToClients(topic string, data interface{}) {
+// Broadcast sends data on topic through the regular broadcast path, including the
+// long-polling buffer, and returns the message ID and the number of subscribed clients
+func (rpm *RealtimePerformanceMonitor) Broadcast(topic string, data interface{}) (string, int) {
+	return rpm.broadcastToClients(topic, data)
+}
+
+func (rpm *RealtimePerformanceMonitor) broadcastToClients(topic string, data interface{}) (string, int) {
 	message := &WebSocketMessage{
 		Type:      "data",
 		Topic:     topic,
@@ -464,14 +686,76 @@ func (rpm *RealtimePerformanceMonitor) broadcastToClients(topic string, data int
 		ID:        fmt.Sprintf("msg-%d", time.Now().UnixNano()),
 	}
 
+	rpm.recordForPolling(message)
+
 	rpm.clientMutex.RLock()
 	defer rpm.clientMutex.RUnlock()
 
+	recipients := 0
 	for conn, clientInfo := range rpm.clients {
-		if rpm.clientSubscribedToTopic(clientInfo, topic) {
-			go rpm.sendMessageToClient(conn, clientInfo, message)
+		if !rpm.clientSubscribedToTopic(clientInfo, topic) {
+			continue
+		}
+		if visible, ok := restrictMessage(clientInfo.Capabilities, message); ok {
+			recipients++
+			if rpm.config.CoalesceWindow > 0 {
+				rpm.queueForClient(conn, clientInfo, visible)
+				continue
+			}
+			go rpm.sendMessageToClient(conn, clientInfo, visible)
 		}
 	}
+	return message.ID, recipients
+}
+
+// queueForClient adds a message to the client's pending batch, arming the flush when it
+// opens a new coalesce window
+func (rpm *RealtimePerformanceMonitor) queueForClient(conn *websocket.Conn, clientInfo *ClientInfo, message *WebSocketMessage) {
+	rpm.pendingMutex.Lock()
+	defer rpm.pendingMutex.Unlock()
+
+	if rpm.pending == nil {
+		rpm.pending = make(map[*websocket.Conn]*pendingBatch)
+	}
+	batch, ok := rpm.pending[conn]
+	if !ok {
+		batch = &pendingBatch{client: clientInfo}
+		rpm.pending[conn] = batch
+	}
+	batch.messages = append(batch.messages, message)
+
+	if rpm.pendingTimer == nil {
+		rpm.pendingTimer = time.AfterFunc(rpm.config.CoalesceWindow, rpm.flushPending)
+	}
+}
+
+// flushPending sends each client its queued messages. When more than one was queued they
+// go out as a single "batch" message whose data lists the messages in broadcast order.
+func (rpm *RealtimePerformanceMonitor) flushPending() {
+	rpm.pendingMutex.Lock()
+	pending := rpm.pending
+	rpm.pending = nil
+	rpm.pendingTimer = nil
+	rpm.pendingMutex.Unlock()
+
+	rpm.clientMutex.RLock()
+	defer rpm.clientMutex.RUnlock()
+
+	for conn, batch := range pending {
+		if _, connected := rpm.clients[conn]; !connected {
+			continue
+		}
+		message := batch.messages[0]
+		if len(batch.messages) > 1 {
+			message = &WebSocketMessage{
+				Type:      "batch",
+				Data:      batch.messages,
+				Timestamp: time.Now(),
+				ID:        fmt.Sprintf("batch-%d", time.Now().UnixNano()),
+			}
+		}
+		go rpm.sendMessageToClient(conn, batch.client, message)
+	}
 }
 
 func (rpm *RealtimePerformanceMonitor) broadcastAlert(alert *PerformanceAlert) {
@@ -491,7 +775,9 @@ func (rpm *RealtimePerformanceMonitor) sendInitialData(conn *websocket.Conn, cli
 			Timestamp: time.Now(),
 			ID:        "initial-data",
 		}
-		rpm.sendMessageToClient(conn, clientInfo, message)
+		if visible, ok := restrictMessage(clientInfo.Capabilities, message); ok {
+			rpm.sendMessageToClient(conn, clientInfo, visible)
+		}
 	}
 }
 
@@ -507,7 +793,9 @@ func (rpm *RealtimePerformanceMonitor) handleClientMessages(conn *websocket.Conn
 	conn.SetReadDeadline(time.Now().Add(rpm.config.ReadTimeout))
 	conn.SetPongHandler(func(string) error {
 		conn.SetReadDeadline(time.Now().Add(rpm.config.ReadTimeout))
+		rpm.clientMutex.Lock()
 		clientInfo.LastPingAt = time.Now()
+		rpm.clientMutex.Unlock()
 		return nil
 	})
 
@@ -526,6 +814,40 @@ func (rpm *RealtimePerformanceMonitor) handleClientMessages(conn *websocket.Conn
 	}
 }
 
+// heartbeatLoop sends a ping frame to the client every HeartbeatInterval and closes the
+// connection once the client has not answered with a pong for PingTimeout. Closing the
+// connection ends handleClientMessages, which unregisters the client.
+func (rpm *RealtimePerformanceMonitor) heartbeatLoop(conn *websocket.Conn, clientInfo *ClientInfo) {
+	if rpm.config.HeartbeatInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(rpm.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rpm.stopChannel:
+			return
+		case <-ticker.C:
+			rpm.clientMutex.RLock()
+			lastPong := clientInfo.LastPingAt
+			rpm.clientMutex.RUnlock()
+
+			if rpm.config.PingTimeout > 0 && time.Since(lastPong) > rpm.config.PingTimeout {
+				rpm.logger.WithField("client_id", clientInfo.ID).Info("Closing WebSocket client that stopped answering pings")
+				conn.Close()
+				return
+			}
+			// WriteControl may run alongside the message writes of sendMessageToClient
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(rpm.config.WriteTimeout)); err != nil {
+				rpm.logger.WithError(err).WithField("client_id", clientInfo.ID).Debug("Failed to ping WebSocket client")
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
 func (rpm *RealtimePerformanceMonitor) processClientMessage(conn *websocket.Conn, clientInfo *ClientInfo, msg map[string]interface{}) {
 	msgType, ok := msg["type"].(string)
 	if !ok {
@@ -533,8 +855,15 @@ func (rpm *RealtimePerformanceMonitor) processClientMessage(conn *websocket.Conn
 	}
 
 	switch msgType {
+	case "auth":
+		token, _ := msg["token"].(string)
+		rpm.authenticateClient(conn, clientInfo, token)
 	case "subscribe":
 		if topic, ok := msg["topic"].(string); ok {
+			if !clientInfo.Capabilities.allowsTopic(topic) {
+				rpm.sendError(conn, clientInfo, topic, "forbidden", fmt.Sprintf("not permitted to subscribe to %q", topic))
+				return
+			}
 			rpm.subscribeClientToTopic(clientInfo, topic)
 		}
 	case "unsubscribe":
@@ -551,10 +880,75 @@ func (rpm *RealtimePerformanceMonitor) processClientMessage(conn *websocket.Conn
 }
 
 func (rpm *RealtimePerformanceMonitor) sendMessageToClient(conn *websocket.Conn, clientInfo *ClientInfo, message *WebSocketMessage) {
-	conn.SetWriteDeadline(time.Now().Add(rpm.config.WriteTimeout))
-	if err := conn.WriteJSON(message); err != nil {
-		rpm.logger.WithError(err).WithField("client_id", clientInfo.ID).Error("Failed to send message to client")
+	frames, err := rpm.encodeFrames(message)
+	if err != nil {
+		rpm.logger.WithError(err).WithField("client_id", clientInfo.ID).Error("Failed to encode message for client")
+		return
+	}
+
+	clientInfo.writeMutex.Lock()
+	defer clientInfo.writeMutex.Unlock()
+
+	for _, frame := range frames {
+		conn.SetWriteDeadline(time.Now().Add(rpm.config.WriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+			rpm.logger.WithError(err).WithField("client_id", clientInfo.ID).Error("Failed to send message to client")
+			return
+		}
+	}
+}
+
+// encodeFrames marshals a message into one frame, or into a sequence of chunk frames
+// when it exceeds MaxOutboundMessageSize
+func (rpm *RealtimePerformanceMonitor) encodeFrames(message *WebSocketMessage) ([][]byte, error) {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	limit := rpm.config.MaxOutboundMessageSize
+	if limit == 0 {
+		limit = defaultMaxOutboundMessageSize
+	}
+	if limit < 0 || len(encoded) <= limit {
+		return [][]byte{encoded}, nil
+	}
+
+	// Payloads are base64 encoded, so each chunk carries 3 raw bytes per 4 bytes of frame
+	chunkSize := (limit - chunkEnvelopeSize) / 4 * 3
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("max outbound message size %d is too small for chunking", limit)
 	}
+
+	total := (len(encoded) + chunkSize - 1) / chunkSize
+	frames := make([][]byte, 0, total)
+	for sequence := 0; sequence < total; sequence++ {
+		end := min((sequence+1)*chunkSize, len(encoded))
+		frame, err := json.Marshal(&WebSocketMessage{
+			Type:  "chunk",
+			Topic: message.Topic,
+			Data: WebSocketChunk{
+				MessageID: message.ID,
+				Sequence:  sequence,
+				Total:     total,
+				Payload:   encoded[sequence*chunkSize : end],
+			},
+			Timestamp: message.Timestamp,
+			ID:        fmt.Sprintf("%s-chunk-%d", message.ID, sequence),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal chunk %d: %w", sequence, err)
+		}
+		frames = append(frames, frame)
+	}
+
+	rpm.logger.WithFields(logrus.Fields{
+		"message_id": message.ID,
+		"size":       len(encoded),
+		"chunks":     total,
+	}).Debug("Chunked oversized WebSocket message")
+
+	return frames, nil
 }
 
 func (rpm *RealtimePerformanceMonitor) clientSubscribedToTopic(clientInfo *ClientInfo, topic string) bool {
@@ -623,21 +1017,98 @@ func (rpm *RealtimePerformanceMonitor) GetLastGraphData() *PerformanceGraphData
 	return rpm.lastGraphData
 }
 
+// MetricsHistory returns the metrics samples collected in [start, end)
+func (rpm *RealtimePerformanceMonitor) MetricsHistory(start, end time.Time) []MetricsSample {
+	return rpm.history.Range(start, end)
+}
+
+// recordForPolling keeps broadcast messages so long-polling clients see the same stream
+func (rpm *RealtimePerformanceMonitor) recordForPolling(message *WebSocketMessage) {
+	rpm.pollMutex.Lock()
+	defer rpm.pollMutex.Unlock()
+
+	limit := rpm.config.PollBufferSize
+	if limit <= 0 {
+		limit = defaultPollBufferSize
+	}
+
+	rpm.pollBuffer = append(rpm.pollBuffer, message)
+	if len(rpm.pollBuffer) > limit {
+		rpm.pollBuffer = append([]*WebSocketMessage(nil), rpm.pollBuffer[len(rpm.pollBuffer)-limit:]...)
+	}
+
+	// Wake up any waiting pollers
+	close(rpm.pollNotify)
+	rpm.pollNotify = make(chan struct{})
+}
+
+// MessagesSince returns buffered broadcast messages with a timestamp after since
+func (rpm *RealtimePerformanceMonitor) MessagesSince(since time.Time) []*WebSocketMessage {
+	rpm.pollMutex.Lock()
+	defer rpm.pollMutex.Unlock()
+	return rpm.messagesSinceLocked(since)
+}
+
+func (rpm *RealtimePerformanceMonitor) messagesSinceLocked(since time.Time) []*WebSocketMessage {
+	messages := make([]*WebSocketMessage, 0)
+	for _, message := range rpm.pollBuffer {
+		if message.Timestamp.After(since) {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// WaitForMessages blocks until messages newer than since are available, the wait
+// duration elapses or the context is cancelled. An empty slice means no new data.
+func (rpm *RealtimePerformanceMonitor) WaitForMessages(ctx context.Context, since time.Time, wait time.Duration) []*WebSocketMessage {
+	if rpm.config.MaxPollDuration > 0 && wait > rpm.config.MaxPollDuration {
+		wait = rpm.config.MaxPollDuration
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		rpm.pollMutex.Lock()
+		messages := rpm.messagesSinceLocked(since)
+		notify := rpm.pollNotify
+		rpm.pollMutex.Unlock()
+
+		if len(messages) > 0 {
+			return messages
+		}
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return messages
+		case <-ctx.Done():
+			return messages
+		case <-rpm.stopChannel:
+			return messages
+		}
+	}
+}
+
 // Default configuration
 func defaultRealtimeMonitorConfig() *RealtimeMonitorConfig {
 	return &RealtimeMonitorConfig{
-		DataUpdateInterval:   5 * time.Second,
-		HeartbeatInterval:    30 * time.Second,
-		MaxConnections:       100,
-		WriteTimeout:         10 * time.Second,
-		ReadTimeout:          60 * time.Second,
-		PingTimeout:          90 * time.Second,
-		MaxMessageSize:       512,
-		MetricsRetention:     1 * time.Hour,
-		CompressionEnabled:   true,
-		MaxConcurrentQueries: 10,
-		MemoryLimitMB:        100,
-		CPUThreshold:         80.0,
+		DataUpdateInterval:     5 * time.Second,
+		HeartbeatInterval:      30 * time.Second,
+		MaxConnections:         100,
+		WriteTimeout:           10 * time.Second,
+		ReadTimeout:            60 * time.Second,
+		PingTimeout:            90 * time.Second,
+		MaxMessageSize:         512,
+		MaxOutboundMessageSize: defaultMaxOutboundMessageSize,
+		PollBufferSize:         defaultPollBufferSize,
+		MaxPollDuration:        30 * time.Second,
+		MetricsRetention:       1 * time.Hour,
+		CompressionEnabled:     true,
+		MaxConcurrentQueries:   10,
+		MemoryLimitMB:          100,
+		CPUThreshold:           80.0,
 		AlertThresholds: AlertThresholds{
 			HighLatency:        1000.0, // 1 second
 			HighErrorRate:      5.0,    // 5%
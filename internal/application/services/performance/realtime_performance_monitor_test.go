@@ -0,0 +1,323 @@
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRealtimeMonitor(t *testing.T) *RealtimePerformanceMonitor {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return NewRealtimePerformanceMonitor(logger, nil, nil, nil, nil)
+}
+
+func dialTestMonitor(t *testing.T, rpm *RealtimePerformanceMonitor) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(rpm.HandleWebSocket))
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.Eventually(t, func() bool {
+		return len(rpm.GetConnectedClients()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	return conn
+}
+
+func TestPollReturnsBroadcastMessages(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	alert := &PerformanceAlert{ID: "alert-1", Type: "slow_query", Value: 250, Threshold: 200}
+	rpm.broadcastToClients("alerts", alert)
+
+	var received WebSocketMessage
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, conn.ReadJSON(&received))
+
+	polled := rpm.MessagesSince(time.Time{})
+	require.Len(t, polled, 1)
+
+	wsPayload, err := json.Marshal(received)
+	require.NoError(t, err)
+	pollPayload, err := json.Marshal(polled[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wsPayload), string(pollPayload))
+}
+
+func TestPollSinceCursor(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+
+	rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "first"})
+	first := rpm.MessagesSince(time.Time{})
+	require.Len(t, first, 1)
+	cursor := first[0].Timestamp
+
+	time.Sleep(time.Millisecond)
+	rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "second"})
+
+	next := rpm.MessagesSince(cursor)
+	require.Len(t, next, 1)
+	assert.Equal(t, "second", next[0].Data.(*PerformanceAlert).ID)
+
+	assert.Empty(t, rpm.MessagesSince(next[0].Timestamp))
+}
+
+func TestWaitForMessagesWakesOnBroadcast(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	since := time.Now()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "late"})
+	}()
+
+	messages := rpm.WaitForMessages(context.Background(), since, 2*time.Second)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "alerts", messages[0].Topic)
+}
+
+func TestWaitForMessagesTimesOut(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+
+	start := time.Now()
+	messages := rpm.WaitForMessages(context.Background(), time.Now(), 30*time.Millisecond)
+	assert.Empty(t, messages)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestPollBufferIsBounded(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.PollBufferSize = 3
+
+	for i := 0; i < 10; i++ {
+		rpm.broadcastToClients("alerts", &PerformanceAlert{})
+	}
+
+	assert.Len(t, rpm.MessagesSince(time.Time{}), 3)
+}
+
+// countingCollector is a collector answering every collection with empty data
+type countingCollector struct {
+	ports.PerformanceCollectorPort
+	collections atomic.Int64
+}
+
+func (c *countingCollector) CollectPerformanceData(ctx context.Context) (*PerformanceSchemaData, error) {
+	c.collections.Add(1)
+	return &PerformanceSchemaData{}, nil
+}
+
+func TestPauseStopsCollectionAndResumeRestartsIt(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	config := defaultRealtimeMonitorConfig()
+	config.DataUpdateInterval = 5 * time.Millisecond
+	collector := &countingCollector{}
+	rpm := NewRealtimePerformanceMonitor(logger, config, collector, nil, nil)
+	ticks := &collector.collections
+
+	dialTestMonitor(t, rpm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, rpm.Start(ctx))
+
+	require.Eventually(t, func() bool { return ticks.Load() > 0 }, time.Second, time.Millisecond)
+
+	assert.True(t, rpm.Pause())
+	assert.False(t, rpm.Pause(), "pausing twice is a no-op")
+	assert.True(t, rpm.IsPaused())
+	assert.False(t, rpm.PausedSince().IsZero())
+
+	// Allow a tick that was already in flight to finish before sampling
+	time.Sleep(20 * time.Millisecond)
+	paused := ticks.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, paused, ticks.Load(), "no collection while paused")
+	assert.Len(t, rpm.GetConnectedClients(), 1, "clients stay connected while paused")
+
+	assert.True(t, rpm.Resume())
+	assert.False(t, rpm.Resume())
+	assert.True(t, rpm.PausedSince().IsZero())
+	require.Eventually(t, func() bool { return ticks.Load() > paused }, time.Second, time.Millisecond)
+}
+
+func largeGraphData(nodes int) *PerformanceGraphData {
+	data := &PerformanceGraphData{ID: "graph-large"}
+	for i := 0; i < nodes; i++ {
+		data.Nodes = append(data.Nodes, PerformanceGraphNode{ID: fmt.Sprintf("node-%d", i)})
+	}
+	return data
+}
+
+func TestLargeMessagesAreChunked(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.MaxOutboundMessageSize = 2048
+	conn := dialTestMonitor(t, rpm)
+
+	rpm.broadcastToClients("performance", largeGraphData(200))
+
+	var payload []byte
+	for sequence, total := 0, 1; sequence < total; sequence++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		_, frame, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(frame), rpm.config.MaxOutboundMessageSize)
+
+		var message struct {
+			Type  string         `json:"type"`
+			Topic string         `json:"topic"`
+			Data  WebSocketChunk `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(frame, &message))
+		require.Equal(t, "chunk", message.Type)
+		assert.Equal(t, "performance", message.Topic)
+		assert.Equal(t, sequence, message.Data.Sequence)
+
+		total = message.Data.Total
+		payload = append(payload, message.Data.Payload...)
+	}
+
+	var original struct {
+		Type string               `json:"type"`
+		Data PerformanceGraphData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &original), "chunks reassemble to the original message")
+	assert.Equal(t, "data", original.Type)
+	assert.Equal(t, "graph-large", original.Data.ID)
+	assert.Len(t, original.Data.Nodes, 200)
+}
+
+func TestSmallMessagesAreNotChunked(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	rpm.broadcastToClients("performance", largeGraphData(2))
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var message WebSocketMessage
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, "data", message.Type)
+}
+
+func TestEncodeFramesRejectsTinyLimit(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.MaxOutboundMessageSize = 256
+
+	_, err := rpm.encodeFrames(&WebSocketMessage{Type: "data", Data: largeGraphData(50)})
+	assert.Error(t, err)
+}
+
+func TestBroadcastsWithinCoalesceWindowAreBatched(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.CoalesceWindow = 50 * time.Millisecond
+	conn := dialTestMonitor(t, rpm)
+	send(t, conn, map[string]string{"type": "subscribe", "topic": "metrics"}, nil)
+
+	rpm.broadcastToClients("metrics", &RealtimeMetrics{ActiveConnections: 3})
+	rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "alert-1"})
+	_, recipients := rpm.broadcastToClients("performance", largeGraphData(2))
+	assert.Equal(t, 1, recipients)
+
+	var parts []WebSocketMessage
+	message := readMessage(t, conn, &parts)
+	assert.Equal(t, "batch", message.Type)
+	require.Len(t, parts, 3)
+	assert.Equal(t, []string{"metrics", "alerts", "performance"}, []string{parts[0].Topic, parts[1].Topic, parts[2].Topic})
+
+	// The batch was the only frame: the next one is the answer to a ping
+	require.NoError(t, conn.WriteJSON(map[string]string{"type": "ping"}))
+	assert.Equal(t, "pong", readMessage(t, conn, nil).Type)
+}
+
+func TestSingleCoalescedBroadcastIsSentUnwrapped(t *testing.T) {
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.CoalesceWindow = 20 * time.Millisecond
+	conn := dialTestMonitor(t, rpm)
+
+	rpm.broadcastToClients("alerts", &PerformanceAlert{ID: "alert-1"})
+
+	var alert PerformanceAlert
+	message := readMessage(t, conn, &alert)
+	assert.Equal(t, "data", message.Type)
+	assert.Equal(t, "alert-1", alert.ID)
+}
+
+func newHeartbeatTestMonitor(t *testing.T) *RealtimePerformanceMonitor {
+	t.Helper()
+	rpm := newTestRealtimeMonitor(t)
+	rpm.config.HeartbeatInterval = 20 * time.Millisecond
+	rpm.config.PingTimeout = 100 * time.Millisecond
+	return rpm
+}
+
+// readUntilClosed keeps reading from conn, which lets the client answer pings, and
+// reports the read error once the connection ends
+func readUntilClosed(conn *websocket.Conn) <-chan error {
+	closed := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				closed <- err
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+func TestClientIgnoringPingsIsEvicted(t *testing.T) {
+	rpm := newHeartbeatTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+
+	var pings atomic.Int32
+	conn.SetPingHandler(func(string) error {
+		pings.Add(1)
+		return nil // never answer with a pong
+	})
+	closed := readUntilClosed(conn)
+
+	select {
+	case err := <-closed:
+		assert.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection of a client ignoring pings was not closed")
+	}
+	assert.Positive(t, pings.Load())
+	require.Eventually(t, func() bool {
+		return len(rpm.GetConnectedClients()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClientAnsweringPingsStaysConnected(t *testing.T) {
+	rpm := newHeartbeatTestMonitor(t)
+	conn := dialTestMonitor(t, rpm)
+	closed := readUntilClosed(conn)
+
+	select {
+	case err := <-closed:
+		t.Fatalf("connection of a responsive client was closed: %v", err)
+	case <-time.After(3 * rpm.config.PingTimeout):
+	}
+	assert.Len(t, rpm.GetConnectedClients(), 1)
+}
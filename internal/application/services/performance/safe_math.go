@@ -0,0 +1,20 @@
+package performance
+
+import "math"
+
+// safeDivide returns numerator/denominator, or 0 when the denominator is zero or the result
+// is not finite. Performance results are JSON-encoded, and encoding/json rejects NaN and ±Inf.
+func safeDivide(numerator, denominator float64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return finite(numerator / denominator)
+}
+
+// finite replaces NaN and ±Inf with 0
+func finite(value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return value
+}
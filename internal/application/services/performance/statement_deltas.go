@@ -0,0 +1,157 @@
+package performance
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// truncateDigestSummary resets the cumulative statement counters; it needs the DROP privilege
+// on the table
+const truncateDigestSummary = "TRUNCATE TABLE performance_schema.events_statements_summary_by_digest"
+
+// MySQL errors returned when the collecting user may not truncate Performance Schema tables
+const (
+	errDBAccessDenied       = 1044
+	errTableAccessDenied    = 1142
+	errSpecificAccessDenied = 1227
+)
+
+// digestKey identifies a digest row; the same digest is summarized separately per schema
+type digestKey struct {
+	schema string
+	digest string
+}
+
+// statementDeltaTracker turns the cumulative rows of events_statements_summary_by_digest into
+// the activity of the interval since the previous collection
+type statementDeltaTracker struct {
+	primed   bool
+	previous map[digestKey]StatementStatistic
+	// since is the server time of the previous collection (its latest last_seen); digests
+	// first seen after it are new and all of their counters belong to the interval
+	since time.Time
+}
+
+// Deltas returns the per-interval statistics of current, busiest first. The first call has no
+// previous counters and returns current unchanged (cumulative since the last reset). Digests
+// whose counters went down or whose first_seen changed were reset and count from zero; digests
+// without previous counters that were seen before the previous collection (they were outside
+// the statement limit) only become the baseline of the next interval, as do digests that did
+// not run in the interval. Min and max timers cannot be split into intervals and stay
+// cumulative.
+func (t *statementDeltaTracker) Deltas(current []StatementStatistic) []StatementStatistic {
+	previous, since, primed := t.previous, t.since, t.primed
+	t.remember(current)
+	if !primed {
+		return current
+	}
+
+	deltas := make([]StatementStatistic, 0, len(current))
+	for _, stmt := range current {
+		prev, ok := previous[digestKey{stmt.SchemaName, stmt.Digest}]
+		switch {
+		case ok && stmt.CountStar >= prev.CountStar && stmt.FirstSeen.Equal(prev.FirstSeen):
+			if delta := statementDelta(stmt, prev); delta.CountStar > 0 {
+				deltas = append(deltas, delta)
+			}
+		case ok || stmt.FirstSeen.After(since):
+			deltas = append(deltas, stmt)
+		}
+	}
+
+	sort.SliceStable(deltas, func(i, j int) bool { return deltas[i].SumTimerWait > deltas[j].SumTimerWait })
+	return deltas
+}
+
+func (t *statementDeltaTracker) remember(current []StatementStatistic) {
+	t.primed = true
+	t.previous = make(map[digestKey]StatementStatistic, len(current))
+	for _, stmt := range current {
+		t.previous[digestKey{stmt.SchemaName, stmt.Digest}] = stmt
+		if stmt.LastSeen.After(t.since) {
+			t.since = stmt.LastSeen
+		}
+	}
+}
+
+// intervalStatements returns perfData with its statements reduced to the interval since the
+// previous tick. Only the monitor's ticks advance the baseline, so collections requested
+// through the API neither shorten the interval nor see deltas. perfData may be shared with
+// other callers and is copied rather than modified.
+func (rpm *RealtimePerformanceMonitor) intervalStatements(perfData *PerformanceSchemaData) *PerformanceSchemaData {
+	interval := *perfData
+	interval.StatementStats = rpm.statementDeltas.Deltas(perfData.StatementStats)
+	return &interval
+}
+
+// statementDelta subtracts the previous counters of a digest from its current ones
+func statementDelta(current, previous StatementStatistic) StatementStatistic {
+	delta := current
+	delta.CountStar = current.CountStar - previous.CountStar
+	delta.SumTimerWait = current.SumTimerWait - previous.SumTimerWait
+	delta.SumRowsAffected = current.SumRowsAffected - previous.SumRowsAffected
+	delta.SumRowsSent = current.SumRowsSent - previous.SumRowsSent
+	delta.SumRowsExamined = current.SumRowsExamined - previous.SumRowsExamined
+	delta.SumCreatedTmpTables = current.SumCreatedTmpTables - previous.SumCreatedTmpTables
+	delta.SumCreatedTmpDiskTables = current.SumCreatedTmpDiskTables - previous.SumCreatedTmpDiskTables
+	delta.SumSelectFullJoin = current.SumSelectFullJoin - previous.SumSelectFullJoin
+	delta.SumSelectScan = current.SumSelectScan - previous.SumSelectScan
+	delta.SumSortScan = current.SumSortScan - previous.SumSortScan
+	delta.SumSortRows = current.SumSortRows - previous.SumSortRows
+	delta.SumNoIndexUsed = current.SumNoIndexUsed - previous.SumNoIndexUsed
+	delta.SumNoGoodIndexUsed = current.SumNoGoodIndexUsed - previous.SumNoGoodIndexUsed
+	if delta.CountStar > 0 {
+		delta.AvgTimerWait = delta.SumTimerWait / models.Duration(delta.CountStar)
+	}
+	return delta
+}
+
+// resetDigestsIfDue truncates the digest summary once DigestResetInterval has passed since the
+// last reset (or since the first collection). When the server denies the truncate, resets are
+// switched off with a single warning. Callers hold p.mutex.
+func (p *PerformanceSchemaAdapter) resetDigestsIfDue(ctx context.Context) {
+	if p.config.DigestResetInterval <= 0 || p.digestResetDenied {
+		return
+	}
+	now := time.Now()
+	if p.lastDigestReset.IsZero() {
+		p.lastDigestReset = now
+		return
+	}
+	if now.Sub(p.lastDigestReset) < p.config.DigestResetInterval {
+		return
+	}
+
+	if _, err := p.db.ExecContext(ctx, truncateDigestSummary); err != nil {
+		if isPermissionDenied(err) {
+			p.digestResetDenied = true
+			p.logger.WithError(err).Warn("Not allowed to truncate the statement digest summary, " +
+				"disabling digest resets (grant DROP on performance_schema.events_statements_summary_by_digest)")
+			return
+		}
+		p.logger.WithError(err).Warn("Failed to reset the statement digest summary")
+		return
+	}
+
+	p.lastDigestReset = now
+	p.logger.Debug("Statement digest summary reset")
+}
+
+// isPermissionDenied reports whether err is a MySQL access denied error
+func isPermissionDenied(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	switch mysqlErr.Number {
+	case errDBAccessDenied, errTableAccessDenied, errSpecificAccessDenied:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package performance
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deltaTestStart = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func cumulativeDigest(digest string, count int64, sumWait time.Duration, rowsExamined int64, lastSeen time.Duration) StatementStatistic {
+	return StatementStatistic{
+		SchemaName:      "shop",
+		Digest:          digest,
+		CountStar:       count,
+		SumTimerWait:    models.Duration(sumWait),
+		AvgTimerWait:    models.Duration(sumWait / time.Duration(count)),
+		SumRowsExamined: rowsExamined,
+		FirstSeen:       deltaTestStart,
+		LastSeen:        deltaTestStart.Add(lastSeen),
+	}
+}
+
+func TestStatementDeltasBetweenCumulativeSnapshots(t *testing.T) {
+	var tracker statementDeltaTracker
+
+	first := []StatementStatistic{
+		cumulativeDigest("orders", 1000, 10*time.Second, 50000, time.Minute),
+		cumulativeDigest("customers", 200, 4*time.Second, 2000, time.Minute),
+		cumulativeDigest("idle", 50, time.Second, 50, 30*time.Second),
+	}
+	assert.Equal(t, first, tracker.Deltas(first), "the first collection is the cumulative baseline")
+
+	late := cumulativeDigest("late", 5, 500*time.Millisecond, 5, 90*time.Second)
+	late.FirstSeen = deltaTestStart.Add(75 * time.Second)
+	second := []StatementStatistic{
+		cumulativeDigest("orders", 1100, 12*time.Second, 55000, 2*time.Minute),
+		cumulativeDigest("customers", 260, 7*time.Second, 2600, 2*time.Minute),
+		cumulativeDigest("idle", 50, time.Second, 50, 30*time.Second),
+		late,
+	}
+
+	deltas := tracker.Deltas(second)
+
+	require.Len(t, deltas, 3, "digests that did not run in the interval are left out")
+	assert.Equal(t, "customers", deltas[0].Digest, "ordered by time spent in the interval")
+	assert.Equal(t, int64(60), deltas[0].CountStar)
+	assert.Equal(t, models.Duration(3*time.Second), deltas[0].SumTimerWait)
+	assert.Equal(t, models.Duration(50*time.Millisecond), deltas[0].AvgTimerWait)
+	assert.Equal(t, int64(600), deltas[0].SumRowsExamined)
+
+	assert.Equal(t, "orders", deltas[1].Digest)
+	assert.Equal(t, int64(100), deltas[1].CountStar)
+	assert.Equal(t, models.Duration(2*time.Second), deltas[1].SumTimerWait)
+	assert.Equal(t, models.Duration(20*time.Millisecond), deltas[1].AvgTimerWait)
+	assert.Equal(t, int64(5000), deltas[1].SumRowsExamined)
+
+	assert.Equal(t, late, deltas[2], "a digest first seen in the interval counts in full")
+}
+
+func TestStatementDeltasAfterServerReset(t *testing.T) {
+	var tracker statementDeltaTracker
+	tracker.Deltas([]StatementStatistic{
+		cumulativeDigest("orders", 1000, 10*time.Second, 50000, time.Minute),
+		cumulativeDigest("customers", 200, 4*time.Second, 2000, time.Minute),
+	})
+
+	// The summary was truncated elsewhere: orders restarted, customers was outside the limit
+	restarted := cumulativeDigest("orders", 40, 400*time.Millisecond, 2000, 2*time.Minute)
+	restarted.FirstSeen = deltaTestStart.Add(90 * time.Second)
+	unseen := cumulativeDigest("products", 900, 9*time.Second, 900, 2*time.Minute)
+
+	deltas := tracker.Deltas([]StatementStatistic{restarted, unseen})
+
+	assert.Equal(t, []StatementStatistic{restarted}, deltas,
+		"a restarted digest counts from zero; one seen before the previous collection only becomes the baseline")
+}
+
+func TestStatementDeltasAfterOwnReset(t *testing.T) {
+	var tracker statementDeltaTracker
+	tracker.Deltas([]StatementStatistic{cumulativeDigest("orders", 1000, 10*time.Second, 50000, time.Minute)})
+
+	// The monitor truncated the summary, so orders restarted and products is new
+	restarted := cumulativeDigest("orders", 30, 300*time.Millisecond, 1500, 2*time.Minute)
+	restarted.FirstSeen = deltaTestStart.Add(90 * time.Second)
+	added := cumulativeDigest("products", 5, 50*time.Millisecond, 5, 2*time.Minute)
+	added.FirstSeen = deltaTestStart.Add(100 * time.Second)
+
+	current := []StatementStatistic{restarted, added}
+	assert.Equal(t, current, tracker.Deltas(current), "after a truncate every digest counts from zero")
+}
+
+func TestMonitorTicksOwnTheStatementBaseline(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	config := defaultRealtimeMonitorConfig()
+	config.StatementDeltas = true
+	rpm := NewRealtimePerformanceMonitor(logger, config, nil, NewPerformanceAnalyzer(logger, nil), nil)
+
+	first := &PerformanceSchemaData{StatementStats: []StatementStatistic{
+		cumulativeDigest("orders", 1000, 10*time.Second, 50000, time.Minute),
+	}}
+	assert.Equal(t, first.StatementStats, rpm.intervalStatements(first).StatementStats)
+
+	// The next tick is reduced to the interval since the previous one
+	second := &PerformanceSchemaData{StatementStats: []StatementStatistic{
+		cumulativeDigest("orders", 1100, 12*time.Second, 55000, 2*time.Minute),
+	}}
+	interval := rpm.intervalStatements(second)
+	require.Len(t, interval.StatementStats, 1)
+	assert.Equal(t, int64(100), interval.StatementStats[0].CountStar)
+	assert.Equal(t, int64(1100), second.StatementStats[0].CountStar, "the shared collection is not modified")
+}
+
+// truncateStub records executed statements and fails them with err
+var truncateStub struct {
+	mu       sync.Mutex
+	err      error
+	executed []string
+}
+
+func truncateStubExec(query string, args []driver.NamedValue) (driver.Result, error) {
+	truncateStub.mu.Lock()
+	defer truncateStub.mu.Unlock()
+	truncateStub.executed = append(truncateStub.executed, query)
+	if truncateStub.err != nil {
+		return nil, truncateStub.err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func newResetTestAdapter(t *testing.T, err error) (*PerformanceSchemaAdapter, *test.Hook) {
+	truncateStub.err = err
+	truncateStub.executed = nil
+
+	db := openStubDB(t, &stubDriver{exec: truncateStubExec}, "")
+
+	logger, hook := test.NewNullLogger()
+	config := defaultPerformanceSchemaConfig()
+	config.DigestResetInterval = time.Minute
+	p := &PerformanceSchemaAdapter{statsCollector: &statsCollector{db: db, logger: logger, config: config}}
+	return p, hook
+}
+
+func TestDigestResetRunsOnSchedule(t *testing.T) {
+	p, _ := newResetTestAdapter(t, nil)
+
+	p.resetDigestsIfDue(context.Background())
+	p.resetDigestsIfDue(context.Background())
+	assert.Empty(t, truncateStub.executed, "the first collection starts the schedule")
+
+	p.lastDigestReset = time.Now().Add(-2 * time.Minute)
+	p.resetDigestsIfDue(context.Background())
+
+	assert.Equal(t, []string{truncateDigestSummary}, truncateStub.executed)
+}
+
+func TestDigestResetStopsWhenPermissionDenied(t *testing.T) {
+	p, hook := newResetTestAdapter(t, &mysql.MySQLError{Number: errTableAccessDenied, Message: "DROP command denied to user 'monitor'"})
+	p.lastDigestReset = time.Now().Add(-2 * time.Minute)
+
+	p.resetDigestsIfDue(context.Background())
+	p.lastDigestReset = time.Now().Add(-2 * time.Minute)
+	p.resetDigestsIfDue(context.Background())
+
+	assert.Len(t, truncateStub.executed, 1, "resets are not retried without the privilege")
+	assert.True(t, p.digestResetDenied)
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+}
@@ -0,0 +1,439 @@
+package performance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// statsCollector is the engine-independent part of a performance collector: connection and
+// replica handling, shared collections, time budgets, filters and conversion to metrics.
+// PerformanceSchemaAdapter and PostgreSQLStatsAdapter embed it and supply the queries.
+type statsCollector struct {
+	db     *sql.DB
+	logger *logrus.Logger
+	config *PerformanceSchemaConfig
+
+	// Caching and state management
+	lastCollection time.Time
+	lastCounters   *statusCounters
+	mutex          sync.RWMutex
+	isConnected    bool
+
+	// Query cache for prepared statistics queries
+	queryCache    map[string]*sql.Stmt
+	queryCacheMux sync.RWMutex
+
+	// replica is the connection opened for ReplicaDSN; nil when collecting from the primary
+	replica *sql.DB
+
+	// stopKeepalive ends the keepalive loop started for KeepaliveInterval
+	stopKeepalive chan struct{}
+	closeOnce     sync.Once
+
+	// flight is the latest collection; concurrent callers share it instead of querying again
+	flight      *collectionCall
+	flightMutex sync.Mutex
+}
+
+// newStatsCollector connects the collector to db, or to config.ReplicaDSN when it is set and
+// reachable
+func newStatsCollector(db *sql.DB, logger *logrus.Logger, config *PerformanceSchemaConfig) *statsCollector {
+	collector := &statsCollector{
+		db:         db,
+		logger:     logger,
+		config:     config,
+		queryCache: make(map[string]*sql.Stmt),
+	}
+
+	if config.ReplicaDSN != "" {
+		if replica, err := openReplica(config.Engine, config.ReplicaDSN); err != nil {
+			logger.WithError(err).Warn("Performance replica unavailable, collecting from the primary database")
+		} else {
+			collector.db = replica
+			collector.replica = replica
+			logger.Info("Collecting performance data from the read replica")
+		}
+	}
+	return collector
+}
+
+// collectionCall is one collection whose result is shared by every caller waiting for it
+type collectionCall struct {
+	done     chan struct{}
+	data     *PerformanceSchemaData
+	err      error
+	finished time.Time
+}
+
+// shareCollection runs collect for callers of CollectPerformanceData. Callers arriving while
+// a collection runs, or within ShareWindow after a successful one, get that collection's
+// result instead of issuing the queries again, so the returned data must not be modified.
+func (p *statsCollector) shareCollection(ctx context.Context, collect func(context.Context) (*PerformanceSchemaData, error)) (*PerformanceSchemaData, error) {
+	p.flightMutex.Lock()
+	if call := p.flight; call != nil {
+		select {
+		case <-call.done:
+			if call.err == nil && time.Since(call.finished) < p.config.ShareWindow {
+				p.flightMutex.Unlock()
+				return call.data, nil
+			}
+		default:
+			p.flightMutex.Unlock()
+			select {
+			case <-call.done:
+				return call.data, call.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	call := &collectionCall{done: make(chan struct{})}
+	p.flight = call
+	p.flightMutex.Unlock()
+
+	// The collection is shared, so it must not end with the request that happened to start
+	// it; it is bounded by its own timeout, while this caller waits only as long as ctx
+	go func() {
+		collectCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.collectionTimeout())
+		defer cancel()
+		call.data, call.err = collect(collectCtx)
+		call.finished = time.Now()
+		close(call.done)
+	}()
+
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// defaultCollectionTimeout bounds a shared collection when no CollectionBudget is set
+const defaultCollectionTimeout = time.Minute
+
+// collectionSteps is the number of queries a collection runs at most, each allowed
+// CollectionBudget
+const collectionSteps = 10
+
+// collectionTimeout is how long one shared collection may run in all
+func (p *statsCollector) collectionTimeout() time.Duration {
+	if p.config.CollectionBudget <= 0 {
+		return defaultCollectionTimeout
+	}
+	return p.config.CollectionBudget * collectionSteps
+}
+
+// Collections whose row limit AutoReduceLimits lowers
+const (
+	collectionStatements = "statements"
+	collectionTableIO    = "table_io"
+)
+
+// minReducedLimit is the lowest value AutoReduceLimits lowers a row limit to
+const minReducedLimit = 10
+
+// withinBudget runs one collection and warns when it took longer than CollectionBudget.
+// With AutoReduceLimits the limit bounding an over-budget query is halved for the next run.
+func (p *statsCollector) withinBudget(collection string, collect func()) {
+	started := time.Now()
+	collect()
+	elapsed := time.Since(started)
+
+	if p.config.CollectionBudget <= 0 || elapsed <= p.config.CollectionBudget {
+		return
+	}
+
+	fields := logrus.Fields{
+		"collection": collection,
+		"elapsed":    elapsed,
+		"budget":     p.config.CollectionBudget,
+	}
+	if p.config.AutoReduceLimits {
+		switch collection {
+		case collectionStatements:
+			p.config.MaxStatements = reducedLimit(p.config.MaxStatements)
+			fields["max_statements"] = p.config.MaxStatements
+		case collectionTableIO:
+			p.config.MaxTables = reducedLimit(p.config.MaxTables)
+			fields["max_tables"] = p.config.MaxTables
+		}
+	}
+	p.logger.WithFields(fields).Warn("Performance Schema collection exceeded its time budget")
+}
+
+func reducedLimit(limit int) int {
+	if limit/2 < minReducedLimit {
+		return min(limit, minReducedLimit)
+	}
+	return limit / 2
+}
+
+// ConvertToPerformanceMetrics converts Performance Schema data to standard performance metrics
+func (p *statsCollector) ConvertToPerformanceMetrics(data *PerformanceSchemaData) *ports.PerformanceMetrics {
+	metrics := &ports.PerformanceMetrics{}
+
+	if data.GlobalStatus != nil {
+		metrics.QueriesPerSecond = data.GlobalStatus.QueriesPerSecond
+		// Additional global metrics mapping
+	}
+
+	// Aggregate statement statistics
+	if len(data.StatementStats) > 0 {
+		var totalQueries int64
+		var totalLatency time.Duration
+		var minLatency, maxLatency time.Duration = time.Hour, 0
+
+		for _, stmt := range data.StatementStats {
+			totalQueries += stmt.CountStar
+			totalLatency += stmt.SumTimerWait.Std()
+
+			if stmt.MinTimerWait.Std() < minLatency {
+				minLatency = stmt.MinTimerWait.Std()
+			}
+			if stmt.MaxTimerWait.Std() > maxLatency {
+				maxLatency = stmt.MaxTimerWait.Std()
+			}
+		}
+
+		if totalQueries > 0 {
+			metrics.AverageLatency = float64(totalLatency.Milliseconds()) / float64(totalQueries)
+			metrics.MinLatency = float64(minLatency.Milliseconds())
+			metrics.MaxLatency = float64(maxLatency.Milliseconds())
+		}
+	}
+
+	return metrics
+}
+
+// ConvertToQueryPerformance converts statement statistics to query performance data
+func (p *statsCollector) ConvertToQueryPerformance(data *PerformanceSchemaData) []ports.QueryPerformance {
+	queryPerformance := make([]ports.QueryPerformance, 0, len(data.StatementStats))
+
+	for _, stmt := range data.StatementStats {
+		digest := ParseDigest(stmt.DigestText, p.config.QualifyTableNames)
+
+		perf := ports.QueryPerformance{
+			QueryPattern:      stmt.DigestText,
+			QueryType:         digest.QueryType,
+			ExecutionCount:    stmt.CountStar,
+			TotalTime:         stmt.SumTimerWait,
+			AverageTime:       stmt.AvgTimerWait,
+			MinTime:           stmt.MinTimerWait,
+			MaxTime:           stmt.MaxTimerWait,
+			SourceTables:      digest.Tables,
+			RowsExamined:      stmt.SumRowsExamined,
+			RowsReturned:      stmt.SumRowsSent,
+			IndexUsed:         stmt.SumNoIndexUsed == 0,
+			RelationshipType:  p.determineRelationshipType(stmt),
+			PerformanceImpact: p.classifyPerformanceImpact(stmt.AvgTimerWait.Std()),
+		}
+
+		queryPerformance = append(queryPerformance, perf)
+	}
+
+	return queryPerformance
+}
+
+// checkAvailability runs the engine's availability check and updates the connection status
+func (p *statsCollector) checkAvailability(ctx context.Context, check func(context.Context) error) error {
+	err := check(ctx)
+
+	p.mutex.Lock()
+	p.isConnected = err == nil
+	p.mutex.Unlock()
+
+	return err
+}
+
+func (p *statsCollector) getOrCreateStatement(query string) (*sql.Stmt, error) {
+	p.queryCacheMux.RLock()
+	if stmt, exists := p.queryCache[query]; exists {
+		p.queryCacheMux.RUnlock()
+		return stmt, nil
+	}
+	p.queryCacheMux.RUnlock()
+
+	p.queryCacheMux.Lock()
+	defer p.queryCacheMux.Unlock()
+
+	// Double-check after acquiring write lock
+	if stmt, exists := p.queryCache[query]; exists {
+		return stmt, nil
+	}
+
+	stmt, err := p.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p.queryCache[query] = stmt
+	return stmt, nil
+}
+
+// counterRates turns cumulative counters into per-second rates over the interval since the
+// previous collection, measured by the server's Uptime. Without a usable previous sample
+// (first collection, server restart, or no time elapsed) it falls back to the average since
+// server start. Callers hold p.mutex.
+func (p *statsCollector) counterRates(current statusCounters) (queriesPerSecond, connectionsPerSecond float64) {
+	previous := p.lastCounters
+	if previous != nil && current.Uptime > previous.Uptime &&
+		current.Queries >= previous.Queries && current.Connections >= previous.Connections {
+		elapsed := float64(current.Uptime - previous.Uptime)
+		queriesPerSecond = float64(current.Queries-previous.Queries) / elapsed
+		connectionsPerSecond = float64(current.Connections-previous.Connections) / elapsed
+		p.lastCounters = &current
+		return queriesPerSecond, connectionsPerSecond
+	}
+
+	if previous == nil || current.Uptime != previous.Uptime {
+		p.lastCounters = &current
+	}
+	if current.Uptime > 0 {
+		queriesPerSecond = float64(current.Queries) / float64(current.Uptime)
+		connectionsPerSecond = float64(current.Connections) / float64(current.Uptime)
+	}
+	return queriesPerSecond, connectionsPerSecond
+}
+
+func (p *statsCollector) shouldIgnoreSchema(schema string) bool {
+	return models.IsSystemSchema(schema, p.config.IgnoredSchemas)
+}
+
+// shouldCollectStatement applies schema, table and focus filters to a statement. Each table
+// the digest references is resolved against its own schema, so a cross-schema join is only
+// ignored when every table it touches is ignored.
+func (p *statsCollector) shouldCollectStatement(stmt StatementStatistic) bool {
+	if p.shouldIgnoreSchema(stmt.SchemaName) {
+		return false
+	}
+
+	tables := ParseDigest(stmt.DigestText, true).Tables
+	if len(tables) == 0 {
+		// Nothing to match a focus against (e.g. SET or SHOW statements)
+		return len(p.config.FocusedTables) == 0
+	}
+
+	collected := false
+	for _, table := range tables {
+		schema, name := stmt.SchemaName, table
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			schema, name = table[:i], table[i+1:]
+		}
+		if p.shouldIgnoreSchema(schema) || matchesTable(p.config.IgnoredTables, schema, name) {
+			continue
+		}
+		if len(p.config.FocusedTables) == 0 || matchesTable(p.config.FocusedTables, schema, name) {
+			collected = true
+		}
+	}
+	return collected
+}
+
+// shouldCollectTable applies the table ignore list and focus to a single table
+func (p *statsCollector) shouldCollectTable(schema, table string) bool {
+	if matchesTable(p.config.IgnoredTables, schema, table) {
+		return false
+	}
+	return len(p.config.FocusedTables) == 0 || matchesTable(p.config.FocusedTables, schema, table)
+}
+
+func (p *statsCollector) extractTableNames(digestText string) []string {
+	return ParseDigest(digestText, p.config.QualifyTableNames).Tables
+}
+
+func (p *statsCollector) identifyQueryType(digestText string) string {
+	return ParseDigest(digestText, false).QueryType
+}
+
+func (p *statsCollector) determineRelationshipType(stmt StatementStatistic) string {
+	if stmt.SumSelectFullJoin > 0 {
+		return "FULL_JOIN"
+	}
+	if strings.Contains(strings.ToLower(stmt.DigestText), "join") {
+		return "JOIN"
+	}
+	if strings.Contains(strings.ToLower(stmt.DigestText), "where") {
+		return "FILTERED"
+	}
+	return "SINGLE_TABLE"
+}
+
+func (p *statsCollector) classifyPerformanceImpact(avgLatency time.Duration) string {
+	latencyMs := float64(avgLatency.Milliseconds())
+
+	if latencyMs < 10 {
+		return "LOW"
+	} else if latencyMs < 100 {
+		return "MEDIUM"
+	}
+	return "HIGH"
+}
+
+// IsConnected returns the connection status
+func (p *statsCollector) IsConnected() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.isConnected
+}
+
+// Close closes the adapter and cleans up resources
+func (p *statsCollector) Close() error {
+	p.closeOnce.Do(func() {
+		if p.stopKeepalive != nil {
+			close(p.stopKeepalive)
+		}
+	})
+
+	p.queryCacheMux.Lock()
+	defer p.queryCacheMux.Unlock()
+
+	for _, stmt := range p.queryCache {
+		stmt.Close()
+	}
+	p.queryCache = make(map[string]*sql.Stmt)
+
+	// The primary belongs to the caller; only the replica connection is ours to close
+	if p.replica != nil {
+		return p.replica.Close()
+	}
+	return nil
+}
+
+// UsingReplica reports whether collection runs against the configured read replica
+func (p *statsCollector) UsingReplica() bool {
+	return p.replica != nil
+}
+
+// openReplicaDB opens a database/sql connection; replaced in tests
+var openReplicaDB = sql.Open
+
+// openReplica connects to the read replica at dsn and verifies it answers
+func openReplica(engine models.DatabaseType, dsn string) (*sql.DB, error) {
+	driverName := "mysql"
+	if engine == models.DatabaseTypePostgreSQL {
+		driverName = "postgres"
+	}
+
+	db, err := openReplicaDB(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replica connection: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping replica: %w", err)
+	}
+	return db, nil
+}
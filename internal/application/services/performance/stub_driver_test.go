@@ -0,0 +1,133 @@
+package performance
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+// stubDriver is a database/sql driver whose connections answer from a script, standing in
+// for the monitored server in collector tests
+type stubDriver struct {
+	// query answers a query sent over a connection to dsn; nil answers every query with no rows
+	query func(dsn, query string, args []driver.NamedValue) (driver.Rows, error)
+	// exec answers a statement; nil leaves statements unsupported
+	exec func(query string, args []driver.NamedValue) (driver.Result, error)
+
+	mu sync.Mutex
+	// down refuses new connections; generation is bumped to drop the open ones
+	down       bool
+	generation int
+}
+
+// openStubDB opens a pool of stub connections to dsn, closed when the test ends
+func openStubDB(t *testing.T, stub *stubDriver, dsn string) *sql.DB {
+	db := sql.OpenDB(stubConnector{stub, dsn})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// setDown drops every open connection (as wait_timeout or a restart would) and refuses new
+// ones until setDown(false)
+func (d *stubDriver) setDown(down bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.down = down
+	d.generation++
+}
+
+func (d *stubDriver) Open(dsn string) (driver.Conn, error) {
+	return stubConnector{d, dsn}.Connect(context.Background())
+}
+
+type stubConnector struct {
+	driver *stubDriver
+	dsn    string
+}
+
+func (c stubConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	if c.driver.down {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	return &stubConn{driver: c.driver, dsn: c.dsn, generation: c.driver.generation}, nil
+}
+
+func (c stubConnector) Driver() driver.Driver { return c.driver }
+
+type stubConn struct {
+	driver     *stubDriver
+	dsn        string
+	generation int
+}
+
+func (c *stubConn) alive() bool {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	return c.generation == c.driver.generation
+}
+
+func (c *stubConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *stubConn) Close() error                              { return nil }
+func (c *stubConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+func (c *stubConn) Ping(ctx context.Context) error {
+	if !c.alive() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *stubConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !c.alive() {
+		return nil, driver.ErrBadConn
+	}
+	if c.driver.query == nil {
+		return emptyRows{}, nil
+	}
+	return c.driver.query(c.dsn, query, args)
+}
+
+func (c *stubConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !c.alive() {
+		return nil, driver.ErrBadConn
+	}
+	if c.driver.exec == nil {
+		return nil, driver.ErrSkip
+	}
+	return c.driver.exec(query, args)
+}
+
+// valueRows returns fixed rows
+type valueRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *valueRows) Columns() []string { return r.columns }
+func (r *valueRows) Close() error      { return nil }
+
+func (r *valueRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// availableRows answers the Performance Schema availability check
+func availableRows() driver.Rows {
+	return &valueRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+}
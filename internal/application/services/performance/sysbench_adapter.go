@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
 
 	"github.com/sirupsen/logrus"
 )
@@ -123,7 +124,7 @@ func (s *SysbenchAdapter) Execute(ctx context.Context, config ports.BenchmarkCon
 		TestType:     config.TestType,
 		StartTime:    startTime,
 		EndTime:      endTime,
-		Duration:     duration,
+		Duration:     models.Duration(duration),
 		Metrics:      metrics,
 		QueryResults: queryResults,
 		RawOutput:    output,
@@ -170,6 +171,10 @@ func (s *SysbenchAdapter) Validate(config ports.BenchmarkConfig) error {
 		return fmt.Errorf("duration must be positive")
 	}
 
+	if err := SysbenchParamSchema.Validate(config.CustomParams); err != nil {
+		return fmt.Errorf("invalid custom_params: %w", err)
+	}
+
 	// Validate test-specific parameters
 	switch config.TestType {
 	case "oltp_read_write", "oltp_read_only", "oltp_write_only":
@@ -299,21 +304,21 @@ func (s *SysbenchAdapter) buildCommand(config ports.BenchmarkConfig) (*exec.Cmd,
 
 	// Add common parameters
 	args = append(args, fmt.Sprintf("--threads=%d", config.Threads))
-	args = append(args, fmt.Sprintf("--time=%d", int(config.Duration.Seconds())))
+	args = append(args, fmt.Sprintf("--time=%d", int(config.Duration.Std().Seconds())))
 
 	// Add warmup if specified
 	if config.WarmupTime > 0 {
-		args = append(args, fmt.Sprintf("--warmup-time=%d", int(config.WarmupTime.Seconds())))
+		args = append(args, fmt.Sprintf("--warmup-time=%d", int(config.WarmupTime.Std().Seconds())))
 	} else if s.config.DefaultWarmupTime > 0 {
 		args = append(args, fmt.Sprintf("--warmup-time=%d", int(s.config.DefaultWarmupTime.Seconds())))
 	}
 
 	// Add custom parameters
-	for key, value := range config.CustomParams {
-		if strValue, ok := value.(string); ok {
-			args = append(args, fmt.Sprintf("--%s=%s", key, strValue))
-		}
+	customArgs, err := SysbenchParamSchema.Args(config.CustomParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom_params: %w", err)
 	}
+	args = append(args, customArgs...)
 
 	// Add test type and run command
 	args = append(args, config.TestType, "run")
@@ -556,10 +561,10 @@ func (s *SysbenchAdapter) createQueryPerformance(queryType, tableName string, me
 		QueryPattern:      fmt.Sprintf("%s operation on %s", queryType, tableName),
 		QueryType:         queryType,
 		ExecutionCount:    execCount,
-		TotalTime:         avgTime * time.Duration(execCount),
-		AverageTime:       avgTime,
-		MinTime:           time.Duration(metrics.MinLatency*weight) * time.Millisecond,
-		MaxTime:           time.Duration(metrics.MaxLatency*weight) * time.Millisecond,
+		TotalTime:         models.Duration(avgTime * time.Duration(execCount)),
+		AverageTime:       models.Duration(avgTime),
+		MinTime:           models.Duration(time.Duration(metrics.MinLatency*weight) * time.Millisecond),
+		MaxTime:           models.Duration(time.Duration(metrics.MaxLatency*weight) * time.Millisecond),
 		SourceTables:      []string{tableName},
 		RowsExamined:      execCount * 1, // Assume 1 row per query on average
 		RowsReturned:      execCount * 1,
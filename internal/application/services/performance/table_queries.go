@@ -0,0 +1,43 @@
+package performance
+
+import (
+	"sort"
+	"strings"
+)
+
+// StatementsForTable returns the statements whose digest references table, ordered by
+// impact: total execution time, then execution count. table is matched case-insensitively
+// and may be qualified as schema.table; unqualified references in a digest belong to the
+// statement's default schema.
+func StatementsForTable(stmts []StatementStatistic, table string) []StatementStatistic {
+	wantSchema, wantTable := splitTableName(strings.ToLower(table))
+
+	matched := make([]StatementStatistic, 0)
+	for _, stmt := range stmts {
+		for _, name := range ParseDigest(stmt.DigestText, true).Tables {
+			schema, bare := splitTableName(name)
+			if schema == "" {
+				schema = strings.ToLower(stmt.SchemaName)
+			}
+			if bare == wantTable && (wantSchema == "" || schema == wantSchema) {
+				matched = append(matched, stmt)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if matched[i].SumTimerWait != matched[j].SumTimerWait {
+			return matched[i].SumTimerWait > matched[j].SumTimerWait
+		}
+		return matched[i].CountStar > matched[j].CountStar
+	})
+	return matched
+}
+
+func splitTableName(name string) (schema, table string) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
@@ -0,0 +1,45 @@
+package performance
+
+import (
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementsForTable(t *testing.T) {
+	stmts := []StatementStatistic{
+		{Digest: "a", SchemaName: "shop", DigestText: "SELECT * FROM `orders` WHERE `id` = ?", SumTimerWait: models.Duration(2 * time.Second)},
+		{Digest: "b", SchemaName: "shop", DigestText: "SELECT * FROM `customers` WHERE `id` = ?", SumTimerWait: models.Duration(9 * time.Second)},
+		{Digest: "c", SchemaName: "shop", DigestText: "SELECT * FROM `customers` `c` JOIN `orders` `o` ON `o` . `customer_id` = `c` . `id`", SumTimerWait: models.Duration(5 * time.Second)},
+		{Digest: "d", SchemaName: "shop", DigestText: "UPDATE `order_items` SET `qty` = ? WHERE `order_id` = ?", SumTimerWait: models.Duration(7 * time.Second)},
+		{Digest: "e", SchemaName: "shop", DigestText: "SELECT * FROM `archive` . `orders`", SumTimerWait: models.Duration(time.Second)},
+		{Digest: "f", SchemaName: "shop", DigestText: "SELECT `orders` FROM `stats`", SumTimerWait: models.Duration(8 * time.Second)},
+	}
+
+	digests := func(matched []StatementStatistic) []string {
+		var out []string
+		for _, stmt := range matched {
+			out = append(out, stmt.Digest)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"c", "a", "e"}, digests(StatementsForTable(stmts, "Orders")))
+	assert.Equal(t, []string{"c", "a"}, digests(StatementsForTable(stmts, "shop.orders")))
+	assert.Equal(t, []string{"e"}, digests(StatementsForTable(stmts, "archive.orders")))
+	assert.Empty(t, StatementsForTable(stmts, "payments"))
+}
+
+func TestStatementsForTableBreaksTiesByCount(t *testing.T) {
+	stmts := []StatementStatistic{
+		{Digest: "rare", DigestText: "SELECT * FROM `orders`", SumTimerWait: models.Duration(time.Second), CountStar: 1},
+		{Digest: "frequent", DigestText: "DELETE FROM `orders` WHERE `id` = ?", SumTimerWait: models.Duration(time.Second), CountStar: 40},
+	}
+
+	matched := StatementsForTable(stmts, "orders")
+	assert.Equal(t, "frequent", matched[0].Digest)
+	assert.Equal(t, "rare", matched[1].Digest)
+}
@@ -0,0 +1,67 @@
+/*
+ * SQL Graph Visualizer - Primary Key Analysis
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// MissingPrimaryKeyFindings reports the tables without a primary key. Rows of such tables have
+// no reliable identity, so their nodes are silently duplicated or merged unless the rule names
+// a key. Each table gets a warning and a suggestion; a unique NOT NULL column, which MySQL
+// uses as the implicit primary key, is suggested as the rule's key.
+func MissingPrimaryKeyFindings(tables []*models.TableInfo) (warnings, suggestions []string) {
+	for _, table := range tables {
+		if table.GraphType == "RELATIONSHIP" || hasPrimaryKey(table.Columns) {
+			continue
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"Table %s has no primary key - its rows cannot be reliably deduplicated into nodes", table.Name))
+
+		if candidates := implicitKeyColumns(table.Columns); len(candidates) > 0 {
+			suggestions = append(suggestions, fmt.Sprintf(
+				"Table %s: unique NOT NULL column %s acts as an implicit primary key - add it as the primary key or map it to id (or key_columns: [%s]) in the table's node rule",
+				table.Name, candidates[0], candidates[0]))
+		} else {
+			suggestions = append(suggestions, fmt.Sprintf(
+				"Table %s: add a primary key, or set key_columns to the columns identifying a row in the table's node rule",
+				table.Name))
+		}
+	}
+	return warnings, suggestions
+}
+
+// hasPrimaryKey reports whether any column is part of the primary key
+func hasPrimaryKey(columns []*models.ColumnInfo) bool {
+	for _, column := range columns {
+		if isPrimaryKeyColumn(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// implicitKeyColumns lists the unique NOT NULL columns in table order
+func implicitKeyColumns(columns []*models.ColumnInfo) []string {
+	var candidates []string
+	for _, column := range columns {
+		switch column.KeyType {
+		case "UNI", "UNIQUE":
+			if strings.EqualFold(column.IsNullable, "NO") {
+				candidates = append(candidates, column.Name)
+			}
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,63 @@
+/*
+ * SQL Graph Visualizer - Primary Key Analysis Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+func primaryKeyFixtureTables() []*models.TableInfo {
+	return []*models.TableInfo{
+		{Name: "customers", GraphType: "NODE", Columns: []*models.ColumnInfo{
+			{Name: "id", KeyType: "PRI", IsNullable: "NO"},
+			{Name: "name", IsNullable: "YES"},
+		}},
+		{Name: "audit_log", GraphType: "NODE", Columns: []*models.ColumnInfo{
+			{Name: "event", IsNullable: "YES"},
+			{Name: "logged_at", KeyType: "MUL", IsNullable: "NO"},
+		}},
+		{Name: "legacy_products", GraphType: "NODE", Columns: []*models.ColumnInfo{
+			{Name: "barcode", KeyType: "UNI", IsNullable: "YES"},
+			{Name: "sku", KeyType: "UNI", IsNullable: "NO"},
+			{Name: "title", IsNullable: "YES"},
+		}},
+		{Name: "customer_tags", GraphType: "RELATIONSHIP", Columns: []*models.ColumnInfo{
+			{Name: "customer_id", KeyType: "MUL", IsNullable: "NO"},
+			{Name: "tag_id", KeyType: "MUL", IsNullable: "NO"},
+		}},
+	}
+}
+
+func TestMissingPrimaryKeyFindings(t *testing.T) {
+	warnings, suggestions := MissingPrimaryKeyFindings(primaryKeyFixtureTables())
+
+	assert.Equal(t, []string{
+		"Table audit_log has no primary key - its rows cannot be reliably deduplicated into nodes",
+		"Table legacy_products has no primary key - its rows cannot be reliably deduplicated into nodes",
+	}, warnings, "tables with a primary key and junction tables are not reported")
+
+	require.Len(t, suggestions, 2)
+	assert.Contains(t, suggestions[0], "add a primary key, or set key_columns")
+	assert.Contains(t, suggestions[1], "unique NOT NULL column sku acts as an implicit primary key",
+		"nullable unique columns cannot identify a row")
+	assert.Contains(t, suggestions[1], "key_columns: [sku]")
+}
+
+func TestMissingPrimaryKeyFindingsWithKeyedTables(t *testing.T) {
+	warnings, suggestions := MissingPrimaryKeyFindings(primaryKeyFixtureTables()[:1])
+
+	assert.Empty(t, warnings)
+	assert.Empty(t, suggestions)
+}
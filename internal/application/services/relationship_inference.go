@@ -0,0 +1,262 @@
+/*
+ * SQL Graph Visualizer - Implicit Relationship Inference
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRelationshipConfidenceThreshold is the minimum confidence for an implicit relationship to be reported
+	DefaultRelationshipConfidenceThreshold = 0.5
+
+	// DefaultProfileSampleSize is how many distinct candidate values are checked per relationship
+	DefaultProfileSampleSize = 500
+
+	// DefaultMaxCandidatesPerColumn links each column to its best matching table only
+	DefaultMaxCandidatesPerColumn = 1
+
+	// DefaultMaxInferredRelationships caps the implicit relationships reported for one schema
+	DefaultMaxInferredRelationships = 5000
+
+	// namingConfidence is the confidence of a relationship found by naming convention alone
+	namingConfidence = 0.6
+	// matchingTypeBonus is added when the candidate column has the referenced key's data type
+	matchingTypeBonus = 0.1
+	// indexedColumnBonus is added when an index starts with the candidate column, as foreign
+	// keys usually are indexed; it only applies when indexes were discovered
+	indexedColumnBonus = 0.1
+	// profileWeight scales how far the measured overlap moves confidence away from 50/50
+	profileWeight = 0.8
+)
+
+// InferenceLimits bounds implicit relationship inference on wide schemas
+type InferenceLimits struct {
+	// MaxCandidatesPerColumn is how many referenced tables one column may be linked to, best
+	// name match first; non-positive uses DefaultMaxCandidatesPerColumn
+	MaxCandidatesPerColumn int
+	// MaxRelationships caps the result; non-positive uses DefaultMaxInferredRelationships
+	MaxRelationships int
+}
+
+// candidateKey is a table a column stem may refer to, with its key column. Rank orders the
+// ways a table name can match a stem: exact, then plurals in the order they are tried.
+type candidateKey struct {
+	table *models.UniversalTableInfo
+	key   *models.ColumnInfo
+	rank  int
+}
+
+// InferImplicitRelationships finds columns that look like foreign keys by naming convention
+// (customer_id -> customers.id) but are not declared as such
+func InferImplicitRelationships(tables []*models.UniversalTableInfo) []models.RelationshipInfo {
+	return InferImplicitRelationshipsWithLimits(tables, InferenceLimits{})
+}
+
+// InferImplicitRelationshipsWithLimits is InferImplicitRelationships with explicit bounds. Tables
+// are indexed once by every stem their name can stand for, so each column costs one lookup
+// however many tables the schema has.
+func InferImplicitRelationshipsWithLimits(tables []*models.UniversalTableInfo, limits InferenceLimits) []models.RelationshipInfo {
+	maxCandidates := limits.MaxCandidatesPerColumn
+	if maxCandidates <= 0 {
+		maxCandidates = DefaultMaxCandidatesPerColumn
+	}
+	maxRelationships := limits.MaxRelationships
+	if maxRelationships <= 0 {
+		maxRelationships = DefaultMaxInferredRelationships
+	}
+
+	index := indexReferencedKeys(tables)
+
+	var relationships []models.RelationshipInfo
+	for _, table := range tables {
+		indexed := leadingIndexes(table.Indexes)
+		declared := make(map[string]bool, len(table.Relationships))
+		for _, rel := range table.Relationships {
+			declared[strings.ToLower(rel.SourceColumn)] = true
+		}
+
+		for _, column := range table.Columns {
+			name := strings.ToLower(column.Name)
+			if declared[name] || (column.IsKey && column.KeyType == "PRIMARY") || !strings.HasSuffix(name, "_id") {
+				continue
+			}
+
+			linked := 0
+			for _, candidate := range index[strings.TrimSuffix(name, "_id")] {
+				if linked == maxCandidates {
+					break
+				}
+				target, key := candidate.table, candidate.key
+				if target == table && strings.EqualFold(key.Name, column.Name) {
+					continue
+				}
+
+				confidence := namingConfidence
+				if strings.EqualFold(column.DataType, key.DataType) {
+					confidence += matchingTypeBonus
+				}
+				if len(indexed[name]) > 0 {
+					confidence += indexedColumnBonus
+				}
+
+				relationships = append(relationships, models.RelationshipInfo{
+					FromTable:    table.Name,
+					FromColumn:   column.Name,
+					ToTable:      target.Name,
+					ToColumn:     key.Name,
+					RelationType: implicitRelationType(column, table.Indexes),
+					IsImplicit:   true,
+					Confidence:   confidence,
+				})
+				linked++
+			}
+		}
+	}
+
+	sort.SliceStable(relationships, func(i, j int) bool {
+		if relationships[i].FromTable != relationships[j].FromTable {
+			return relationships[i].FromTable < relationships[j].FromTable
+		}
+		return relationships[i].FromColumn < relationships[j].FromColumn
+	})
+
+	if len(relationships) > maxRelationships {
+		logrus.Warnf("Found %d implicit relationships, keeping the first %d", len(relationships), maxRelationships)
+		relationships = relationships[:maxRelationships]
+	}
+	return relationships
+}
+
+// implicitRelationType is ONE_TO_ONE for a foreign key column with a unique key or unique
+// index, whose values each reference a different row, and ONE_TO_MANY otherwise
+func implicitRelationType(column *models.ColumnInfo, indexes []models.IndexInfo) string {
+	if (column.IsKey && column.KeyType == "UNIQUE") || hasUniqueIndexOn(indexes, column.Name) {
+		return "ONE_TO_ONE"
+	}
+	return "ONE_TO_MANY"
+}
+
+// indexReferencedKeys maps every column stem a table name can stand for (category for
+// category, categories, ...) onto the tables with a key to reference, best match first
+func indexReferencedKeys(tables []*models.UniversalTableInfo) map[string][]candidateKey {
+	index := make(map[string][]candidateKey, len(tables))
+	for _, table := range tables {
+		key := referencedKeyColumn(table)
+		if key == nil {
+			continue
+		}
+
+		name := strings.ToLower(table.Name)
+		stems := []string{name}
+		for _, suffix := range []string{"s", "es"} {
+			if strings.HasSuffix(name, suffix) {
+				stems = append(stems, strings.TrimSuffix(name, suffix))
+			} else {
+				stems = append(stems, "")
+			}
+		}
+		if strings.HasSuffix(name, "ies") {
+			stems = append(stems, strings.TrimSuffix(name, "ies")+"y")
+		}
+
+		for rank, stem := range stems {
+			if stem != "" {
+				index[stem] = append(index[stem], candidateKey{table: table, key: key, rank: rank})
+			}
+		}
+	}
+
+	for _, candidates := range index {
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].rank < candidates[j].rank })
+	}
+	return index
+}
+
+// referencedKeyColumn returns the table's primary key, or its id column when no primary key
+// is declared
+func referencedKeyColumn(table *models.UniversalTableInfo) *models.ColumnInfo {
+	var idColumn *models.ColumnInfo
+	for _, column := range table.Columns {
+		if column.IsKey && column.KeyType == "PRIMARY" {
+			return column
+		}
+		if strings.EqualFold(column.Name, "id") {
+			idColumn = column
+		}
+	}
+	return idColumn
+}
+
+// ProfileRelationshipConfidence samples values of each candidate foreign key column and
+// checks how many exist in the referenced key. High overlap raises confidence, low overlap
+// lowers it; relationships whose column has no values are left unchanged.
+func ProfileRelationshipConfidence(ctx context.Context, profiler repository.ColumnProfiler, relationships []models.RelationshipInfo, sampleSize int) []models.RelationshipInfo {
+	if sampleSize <= 0 {
+		sampleSize = DefaultProfileSampleSize
+	}
+
+	profiled := make([]models.RelationshipInfo, len(relationships))
+	copy(profiled, relationships)
+
+	for i := range profiled {
+		rel := &profiled[i]
+
+		values, err := profiler.SampleColumnValues(ctx, rel.FromTable, rel.FromColumn, sampleSize)
+		if err != nil {
+			logrus.Warnf("Failed to sample %s.%s: %v", rel.FromTable, rel.FromColumn, err)
+			continue
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		matched, err := profiler.CountMatchingValues(ctx, rel.ToTable, rel.ToColumn, values)
+		if err != nil {
+			logrus.Warnf("Failed to match %s.%s against %s.%s: %v", rel.FromTable, rel.FromColumn, rel.ToTable, rel.ToColumn, err)
+			continue
+		}
+
+		rel.Profiled = true
+		rel.SampledRows = len(values)
+		rel.MatchRatio = float64(matched) / float64(len(values))
+		rel.Confidence = clampConfidence(rel.Confidence + (rel.MatchRatio-0.5)*profileWeight)
+	}
+
+	return profiled
+}
+
+// FilterRelationshipsByConfidence keeps relationships at or above the threshold
+func FilterRelationshipsByConfidence(relationships []models.RelationshipInfo, threshold float64) []models.RelationshipInfo {
+	filtered := make([]models.RelationshipInfo, 0, len(relationships))
+	for _, rel := range relationships {
+		if rel.Confidence >= threshold {
+			filtered = append(filtered, rel)
+		}
+	}
+	return filtered
+}
+
+func clampConfidence(confidence float64) float64 {
+	if confidence < 0 {
+		return 0
+	}
+	if confidence > 1 {
+		return 1
+	}
+	return confidence
+}
@@ -0,0 +1,212 @@
+/*
+ * SQL Graph Visualizer - Implicit Relationship Inference Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// tableProfiler serves column values from in-memory tables
+type tableProfiler struct {
+	columns map[string][]interface{} // "table.column" -> values
+}
+
+func (p *tableProfiler) SampleColumnValues(ctx context.Context, tableName, columnName string, limit int) ([]interface{}, error) {
+	values := p.columns[tableName+"."+columnName]
+	if len(values) > limit {
+		values = values[:limit]
+	}
+	return values, nil
+}
+
+func (p *tableProfiler) CountMatchingValues(ctx context.Context, tableName, columnName string, values []interface{}) (int, error) {
+	existing := make(map[string]bool)
+	for _, value := range p.columns[tableName+"."+columnName] {
+		existing[fmt.Sprint(value)] = true
+	}
+
+	matched := 0
+	for _, value := range values {
+		if existing[fmt.Sprint(value)] {
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+func intValues(from, to int) []interface{} {
+	values := make([]interface{}, 0, to-from)
+	for i := from; i < to; i++ {
+		values = append(values, int64(i))
+	}
+	return values
+}
+
+func newShopTables() []*models.UniversalTableInfo {
+	pk := &models.ColumnInfo{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}
+	return []*models.UniversalTableInfo{
+		{Name: "customers", Columns: []*models.ColumnInfo{pk, {Name: "name", DataType: "varchar"}}},
+		{Name: "categories", Columns: []*models.ColumnInfo{pk}},
+		{Name: "orders", Columns: []*models.ColumnInfo{
+			pk,
+			{Name: "customer_id", DataType: "int"},
+			{Name: "category_id", DataType: "int"},
+			{Name: "external_id", DataType: "varchar"},
+		}},
+	}
+}
+
+func TestInferImplicitRelationships(t *testing.T) {
+	relationships := InferImplicitRelationships(newShopTables())
+	require.Len(t, relationships, 2)
+
+	assert.Equal(t, "category_id", relationships[0].FromColumn)
+	assert.Equal(t, "categories", relationships[0].ToTable, "irregular plural is resolved")
+	assert.Equal(t, "customer_id", relationships[1].FromColumn)
+	assert.Equal(t, "customers", relationships[1].ToTable)
+	assert.Equal(t, "id", relationships[1].ToColumn)
+
+	for _, rel := range relationships {
+		assert.True(t, rel.IsImplicit)
+		assert.False(t, rel.Profiled)
+		assert.InDelta(t, namingConfidence+matchingTypeBonus, rel.Confidence, 1e-9)
+	}
+}
+
+func TestInferImplicitRelationshipCardinality(t *testing.T) {
+	tables := newShopTables()
+	pk := &models.ColumnInfo{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}
+	tables = append(tables, &models.UniversalTableInfo{Name: "loyalty_cards", Columns: []*models.ColumnInfo{
+		pk,
+		{Name: "customer_id", DataType: "int", IsKey: true, KeyType: "UNIQUE"},
+	}})
+
+	types := make(map[string]string)
+	for _, rel := range InferImplicitRelationships(tables) {
+		types[rel.FromTable+"."+rel.FromColumn] = rel.RelationType
+	}
+	assert.Equal(t, "ONE_TO_ONE", types["loyalty_cards.customer_id"], "a unique foreign key")
+	assert.Equal(t, "ONE_TO_MANY", types["orders.customer_id"])
+}
+
+func TestProfileRelationshipConfidence(t *testing.T) {
+	profiler := &tableProfiler{columns: map[string][]interface{}{
+		"customers.id":       intValues(1, 101),
+		"orders.customer_id": intValues(1, 101), // every value references a customer
+		"categories.id":      intValues(1, 11),
+		"orders.category_id": intValues(500, 600), // a legacy code that only shares the name
+	}}
+
+	named := InferImplicitRelationships(newShopTables())
+	profiled := ProfileRelationshipConfidence(context.Background(), profiler, named, 50)
+
+	byColumn := make(map[string]models.RelationshipInfo)
+	for _, rel := range profiled {
+		byColumn[rel.FromColumn] = rel
+	}
+
+	customer := byColumn["customer_id"]
+	assert.True(t, customer.Profiled)
+	assert.Equal(t, 50, customer.SampledRows)
+	assert.InDelta(t, 1.0, customer.MatchRatio, 1e-9)
+	assert.Greater(t, customer.Confidence, named[1].Confidence, "high overlap raises confidence")
+
+	category := byColumn["category_id"]
+	assert.True(t, category.Profiled)
+	assert.InDelta(t, 0.0, category.MatchRatio, 1e-9)
+	assert.Less(t, category.Confidence, DefaultRelationshipConfidenceThreshold, "no overlap drops below the threshold")
+
+	kept := FilterRelationshipsByConfidence(profiled, DefaultRelationshipConfidenceThreshold)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "customer_id", kept[0].FromColumn)
+
+	assert.False(t, named[0].Profiled, "input relationships are not modified")
+}
+
+func TestProfileRelationshipConfidenceSkipsEmptyColumns(t *testing.T) {
+	named := InferImplicitRelationships(newShopTables())
+	profiled := ProfileRelationshipConfidence(context.Background(), &tableProfiler{}, named, 0)
+
+	for i, rel := range profiled {
+		assert.False(t, rel.Profiled)
+		assert.Equal(t, named[i].Confidence, rel.Confidence)
+	}
+}
+
+// wideSchema builds tables entity_0 ... entity_{n-1}; each references the previous one
+// through entity_<i-1>_id and carries filler columns that match no table
+func wideSchema(n int) []*models.UniversalTableInfo {
+	tables := make([]*models.UniversalTableInfo, 0, n)
+	for i := 0; i < n; i++ {
+		columns := []*models.ColumnInfo{{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}}
+		if i > 0 {
+			columns = append(columns, &models.ColumnInfo{Name: fmt.Sprintf("entity_%d_id", i-1), DataType: "int"})
+		}
+		for c := 0; c < 30; c++ {
+			columns = append(columns, &models.ColumnInfo{Name: fmt.Sprintf("attribute_%d_id", c), DataType: "int"})
+		}
+		tables = append(tables, &models.UniversalTableInfo{Name: fmt.Sprintf("entity_%ds", i), Columns: columns})
+	}
+	return tables
+}
+
+func TestInferImplicitRelationshipsOnWideSchema(t *testing.T) {
+	tables := wideSchema(200)
+
+	start := time.Now()
+	relationships := InferImplicitRelationships(tables)
+	assert.Less(t, time.Since(start), time.Second, "inference must not compare every column with every table")
+
+	require.Len(t, relationships, 199)
+	for _, rel := range relationships {
+		assert.Equal(t, strings.TrimSuffix(rel.FromColumn, "_id")+"s", rel.ToTable)
+		assert.Equal(t, "id", rel.ToColumn)
+	}
+}
+
+func TestInferImplicitRelationshipsLimits(t *testing.T) {
+	pk := &models.ColumnInfo{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}
+	tables := []*models.UniversalTableInfo{
+		{Name: "status", Columns: []*models.ColumnInfo{pk}},
+		{Name: "statuses", Columns: []*models.ColumnInfo{pk}},
+		{Name: "orders", Columns: []*models.ColumnInfo{pk, {Name: "status_id", DataType: "int"}, {Name: "customer_id"}}},
+		{Name: "customers", Columns: []*models.ColumnInfo{pk}},
+	}
+
+	best := InferImplicitRelationships(tables)
+	require.Len(t, best, 2)
+	assert.Equal(t, "status", best[1].ToTable, "an exact table name is the best match")
+
+	both := InferImplicitRelationshipsWithLimits(tables, InferenceLimits{MaxCandidatesPerColumn: 2})
+	require.Len(t, both, 3)
+	assert.Equal(t, "status", both[1].ToTable)
+	assert.Equal(t, "statuses", both[2].ToTable)
+
+	capped := InferImplicitRelationshipsWithLimits(tables, InferenceLimits{MaxCandidatesPerColumn: 2, MaxRelationships: 1})
+	require.Len(t, capped, 1)
+	assert.Equal(t, "customer_id", capped[0].FromColumn)
+}
+
+func BenchmarkInferImplicitRelationships(b *testing.B) {
+	tables := wideSchema(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		InferImplicitRelationships(tables)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ * SQL Graph Visualizer - Row Count Estimation
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"math"
+
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSamplePercent is the share of a table sampled to estimate its rows
+	DefaultSamplePercent = 1.0
+	// DefaultMaxSampleRows bounds the rows a sample query counts
+	DefaultMaxSampleRows = 10000
+)
+
+// estimateRows returns the row estimate of a table. With the sample method it counts a bounded
+// random sample and extrapolates; otherwise, or when the repository cannot sample or sampling
+// fails, the catalog estimate is kept. A sample cut off at its row bound only shows the table
+// holds at least the extrapolated rows, so the larger of both estimates is used then.
+func (s *UniversalDatabaseService) estimateRows(ctx context.Context, table string, catalog int64) int64 {
+	settings := s.config.GetDataFiltering().RowEstimation
+	if settings.Method != models.RowEstimationSample {
+		return catalog
+	}
+	sampler, ok := s.repo.(repository.RowCountSampler)
+	if !ok {
+		logrus.Warnf("Row sampling is not supported for %s, using catalog statistics", s.dbType)
+		return catalog
+	}
+
+	percent := settings.SamplePercent
+	if percent <= 0 || percent > 100 {
+		percent = DefaultSamplePercent
+	}
+	limit := settings.MaxSampleRows
+	if limit <= 0 {
+		limit = DefaultMaxSampleRows
+	}
+
+	rows, fraction, err := sampler.SampleRowCount(ctx, table, percent, limit)
+	if err != nil || fraction <= 0 {
+		logrus.Warnf("Failed to sample rows of table %s, using catalog statistics: %v", table, err)
+		return catalog
+	}
+
+	estimate := int64(math.Round(float64(rows) / fraction))
+	if rows >= int64(limit) && catalog > estimate {
+		return catalog
+	}
+	logrus.Debugf("Sampled row estimate of table %s: %d (catalog: %d)", table, estimate, catalog)
+	return estimate
+}
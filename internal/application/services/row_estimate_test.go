@@ -0,0 +1,107 @@
+/*
+ * SQL Graph Visualizer - Row Count Estimation Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// samplingRepository serves stale catalog statistics and counts table samples from sampled,
+// recording the bounds of every sample query
+type samplingRepository struct {
+	catalogRepository
+	sampled map[string]int64
+	percent []float64
+	limits  []int
+}
+
+func (r *samplingRepository) SampleRowCount(ctx context.Context, tableName string, percent float64, limit int) (int64, float64, error) {
+	r.percent = append(r.percent, percent)
+	r.limits = append(r.limits, limit)
+	rows, ok := r.sampled[tableName]
+	if !ok {
+		return 0, 0, errors.New("sampling failed")
+	}
+	if rows > int64(limit) {
+		rows = int64(limit)
+	}
+	return rows, percent / 100, nil
+}
+
+// newSamplingFixture has catalog statistics that lag far behind the orders table, which grew
+// to about 60000 rows since the last ANALYZE
+func newSamplingFixture(t *testing.T) *samplingRepository {
+	const mb = 1024 * 1024
+	return &samplingRepository{
+		catalogRepository: catalogRepository{
+			countingRepository: countingRepository{tables: []string{"customers", "orders"}},
+			t:                  t,
+			sizes: map[string]*models.TableSize{
+				"customers": models.NewTableSize("customers", 10000, 2*mb, 0),
+				"orders":    models.NewTableSize("orders", 1000, 12*mb, 0),
+			},
+		},
+		sampled: map[string]int64{"customers": 101, "orders": 598},
+	}
+}
+
+func estimateWith(t *testing.T, repo *samplingRepository, estimation models.RowEstimationConfig) *models.DatasetInfo {
+	t.Helper()
+	config := newCacheTestConfig()
+	config.DataFiltering.RowEstimation = estimation
+	info, err := NewUniversalDatabaseService(repo, config).EstimateDataset(context.Background())
+	require.NoError(t, err)
+	return info
+}
+
+func TestEstimateDatasetCatalogVersusSampledRows(t *testing.T) {
+	catalogRepo := newSamplingFixture(t)
+	catalog := estimateWith(t, catalogRepo, models.RowEstimationConfig{})
+	assert.Equal(t, map[string]int64{"customers": 10000, "orders": 1000}, catalog.TableSizes)
+	assert.Empty(t, catalogRepo.limits, "the catalog method runs no sample queries")
+
+	sampledRepo := newSamplingFixture(t)
+	sampled := estimateWith(t, sampledRepo, models.RowEstimationConfig{Method: models.RowEstimationSample})
+	assert.Equal(t, map[string]int64{"customers": 10100, "orders": 59800}, sampled.TableSizes)
+	assert.EqualValues(t, 69900, sampled.TotalRows)
+	// The storage size is the catalog's, however many rows it holds: 2 + 12 MB
+	assert.InDelta(t, 14.0, sampled.EstimatedSizeMB, 1e-9)
+
+	assert.Equal(t, []float64{DefaultSamplePercent, DefaultSamplePercent}, sampledRepo.percent)
+	assert.Equal(t, []int{DefaultMaxSampleRows, DefaultMaxSampleRows}, sampledRepo.limits, "every sample query is bounded")
+}
+
+func TestEstimateDatasetSampleCutOffAtRowBound(t *testing.T) {
+	repo := newSamplingFixture(t)
+	info := estimateWith(t, repo, models.RowEstimationConfig{Method: models.RowEstimationSample, SamplePercent: 10, MaxSampleRows: 500})
+
+	assert.Equal(t, []int{500, 500}, repo.limits)
+	// customers: the bound of 500 was not reached, so the 101 sampled rows extrapolate to 1010
+	// orders: the sample stopped at 500 rows, so orders holds at least 5000 rows
+	assert.Equal(t, map[string]int64{"customers": 1010, "orders": 5000}, info.TableSizes)
+
+	repo.sizes["orders"].RowCount = 80000
+	info = estimateWith(t, repo, models.RowEstimationConfig{Method: models.RowEstimationSample, SamplePercent: 10, MaxSampleRows: 500})
+	assert.EqualValues(t, 80000, info.TableSizes["orders"], "a larger catalog estimate beats a cut-off sample")
+}
+
+func TestEstimateDatasetFallsBackToCatalogWhenSamplingFails(t *testing.T) {
+	repo := newSamplingFixture(t)
+	delete(repo.sampled, "orders")
+	info := estimateWith(t, repo, models.RowEstimationConfig{Method: models.RowEstimationSample})
+	assert.Equal(t, map[string]int64{"customers": 10100, "orders": 1000}, info.TableSizes)
+}
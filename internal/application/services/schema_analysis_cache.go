@@ -0,0 +1,165 @@
+/*
+ * SQL Graph Visualizer - Schema Analysis Cache
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// DefaultSchemaCacheTTL is how long a discovered schema is reused before re-analysis
+const DefaultSchemaCacheTTL = 15 * time.Minute
+
+// schemaCacheVersion is bumped when entries gain data older entries lack; version 2 added
+// the declared foreign keys of every table
+const schemaCacheVersion = 2
+
+// SchemaCacheEntry is a discovered schema persisted together with its connection info
+type SchemaCacheEntry struct {
+	Version        int                                   `json:"version"`
+	Fingerprint    string                                `json:"fingerprint"`
+	CachedAt       time.Time                             `json:"cached_at"`
+	DatabaseType   models.DatabaseType                   `json:"database_type"`
+	DatabaseInfo   *models.DatabaseConnectionInfo        `json:"database_info"`
+	SchemaAnalysis *models.UniversalSchemaAnalysisResult `json:"schema_analysis"`
+}
+
+// SchemaAnalysisCache stores discovered schemas on disk keyed by a connection+schema
+// fingerprint, so repeated CLI runs and server restarts within the TTL skip schema
+// discovery queries
+type SchemaAnalysisCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSchemaAnalysisCache creates a cache rooted at dir; an empty dir uses the user cache directory
+func NewSchemaAnalysisCache(dir string, ttl time.Duration) (*SchemaAnalysisCache, error) {
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, "sql-graph-visualizer", "schema")
+	}
+	if ttl <= 0 {
+		ttl = DefaultSchemaCacheTTL
+	}
+
+	return &SchemaAnalysisCache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}, nil
+}
+
+// SchemaFingerprint derives a stable cache key from the connection target and filtering.
+// Credentials other than the username are deliberately excluded.
+func SchemaFingerprint(config models.DatabaseConfig) string {
+	filtering := config.GetDataFiltering()
+
+	whitelist := append([]string(nil), filtering.TableWhitelist...)
+	blacklist := append([]string(nil), filtering.TableBlacklist...)
+	sort.Strings(whitelist)
+	sort.Strings(blacklist)
+
+	schema := ""
+	if pgConfig, ok := config.(*models.PostgreSQLConfig); ok {
+		schema = pgConfig.Schema
+	}
+
+	parts := []string{
+		string(config.GetDatabaseType()),
+		config.GetHost(),
+		fmt.Sprintf("%d", config.GetPort()),
+		config.GetUsername(),
+		config.GetDatabase(),
+		schema,
+		strings.Join(whitelist, ","),
+		strings.Join(blacklist, ","),
+		fmt.Sprintf("%d", filtering.RowLimitPerTable),
+	}
+	// Appended only when set, so keys of analyses without indexes stay valid
+	if filtering.DiscoverIndexes {
+		parts = append(parts, "indexes")
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns a cached entry if present and not older than the TTL
+func (c *SchemaAnalysisCache) Get(fingerprint string) (*SchemaCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(fingerprint))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry SchemaCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if entry.Version != schemaCacheVersion || entry.Fingerprint != fingerprint || entry.SchemaAnalysis == nil {
+		return nil, false
+	}
+
+	if c.now().Sub(entry.CachedAt) > c.ttl {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Put stores an entry, replacing any previous one for the same fingerprint
+func (c *SchemaAnalysisCache) Put(entry *SchemaCacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create schema cache directory: %w", err)
+	}
+
+	entry.Version = schemaCacheVersion
+	entry.CachedAt = c.now()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema cache entry: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted run never leaves a partial entry
+	tmpPath := c.path(entry.Fingerprint) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write schema cache entry: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path(entry.Fingerprint)); err != nil {
+		return fmt.Errorf("failed to store schema cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Invalidate removes the cached entry for a fingerprint
+func (c *SchemaAnalysisCache) Invalidate(fingerprint string) error {
+	if err := os.Remove(c.path(fingerprint)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to invalidate schema cache entry: %w", err)
+	}
+	return nil
+}
+
+func (c *SchemaAnalysisCache) path(fingerprint string) string {
+	return filepath.Join(c.dir, fingerprint+".json")
+}
@@ -0,0 +1,283 @@
+/*
+ * SQL Graph Visualizer - Schema Analysis Cache Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// stubDriver provides *sql.DB handles that accept pings but run no queries
+type stubDriver struct{}
+
+type stubConn struct{}
+
+func (stubDriver) Open(name string) (driver.Conn, error) { return stubConn{}, nil }
+
+func (stubConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("stub connection does not execute queries")
+}
+func (stubConn) Close() error { return nil }
+func (stubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("stub connection has no transactions")
+}
+
+var registerStubDriver sync.Once
+
+// countingRepository is a DatabaseRepository fake that records how often the database is touched
+type countingRepository struct {
+	calls       int
+	tables      []string
+	foreignKeys map[string][]models.ForeignKeyInfo
+}
+
+func (r *countingRepository) Connect(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error) {
+	r.calls++
+	registerStubDriver.Do(func() { sql.Register("schema-cache-stub", stubDriver{}) })
+	return sql.Open("schema-cache-stub", "")
+}
+func (r *countingRepository) Close() error                             { return nil }
+func (r *countingRepository) TestConnection(ctx context.Context) error { return nil }
+func (r *countingRepository) GetTables(ctx context.Context, filters models.DataFilteringConfig) ([]string, error) {
+	r.calls++
+	return r.tables, nil
+}
+func (r *countingRepository) GetColumns(ctx context.Context, tableName string) ([]*models.ColumnInfo, error) {
+	r.calls++
+	return []*models.ColumnInfo{{Name: "id", DataType: "int", IsKey: true, KeyType: "PRIMARY"}}, nil
+}
+func (r *countingRepository) GetForeignKeys(ctx context.Context, tableName string) ([]models.ForeignKeyInfo, error) {
+	r.calls++
+	return r.foreignKeys[tableName], nil
+}
+func (r *countingRepository) GetIndexes(ctx context.Context, tableName string) ([]models.IndexInfo, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) GetConstraints(ctx context.Context, tableName string) ([]models.Constraint, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) GetDatabaseName(ctx context.Context) (string, error) {
+	r.calls++
+	return "shop", nil
+}
+func (r *countingRepository) GetDatabaseVersion(ctx context.Context) (string, error) {
+	r.calls++
+	return "8.0.36", nil
+}
+func (r *countingRepository) GetSchemaNames(ctx context.Context) ([]string, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) GetTableRowCount(ctx context.Context, tableName string) (int64, error) {
+	r.calls++
+	return 42, nil
+}
+func (r *countingRepository) SampleTableData(ctx context.Context, tableName string, limit int) ([]map[string]interface{}, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) AnalyzeColumnStatistics(ctx context.Context, tableName, columnName string) (*models.ColumnStatistics, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) GetTableSize(ctx context.Context, tableName string) (*models.TableSize, error) {
+	r.calls++
+	return nil, nil
+}
+func (r *countingRepository) GetQueryExecutionPlan(ctx context.Context, query string) (string, error) {
+	r.calls++
+	return "", nil
+}
+func (r *countingRepository) ValidatePermissions(ctx context.Context, requiredPerms []string) error {
+	return nil
+}
+func (r *countingRepository) CheckUserPrivileges(ctx context.Context) (*models.UserPrivileges, error) {
+	return nil, nil
+}
+func (r *countingRepository) EscapeIdentifier(identifier string) string { return identifier }
+func (r *countingRepository) GetQuoteChar() string                      { return "`" }
+func (r *countingRepository) GetDatabaseType() models.DatabaseType {
+	return models.DatabaseTypePostgreSQL
+}
+func (r *countingRepository) GetConnectionString(config models.DatabaseConfig) string {
+	return ""
+}
+
+func newCacheTestConfig() *models.PostgreSQLConfig {
+	return &models.PostgreSQLConfig{
+		Host:     "localhost",
+		Port:     5432,
+		Username: "analyst",
+		Password: "not-a-real-password",
+		Database: "shop",
+		Schema:   "public",
+	}
+}
+
+func TestUniversalDatabaseService_SchemaCacheHitSkipsDatabase(t *testing.T) {
+	cache, err := NewSchemaAnalysisCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	repo := &countingRepository{tables: []string{"customers", "orders"}}
+	service := NewUniversalDatabaseService(repo, newCacheTestConfig())
+	service.SetSchemaCache(cache, false)
+
+	first, err := service.ConnectAndAnalyze(context.Background())
+	require.NoError(t, err)
+	require.True(t, first.Success, first.ErrorMessage)
+	assert.False(t, first.FromCache)
+	assert.Positive(t, repo.calls)
+
+	repo.calls = 0
+	second, err := service.ConnectAndAnalyze(context.Background())
+	require.NoError(t, err)
+	require.True(t, second.Success, second.ErrorMessage)
+	assert.True(t, second.FromCache)
+	assert.Zero(t, repo.calls, "cache hit must not query the database")
+	assert.Len(t, second.SchemaAnalysis.Tables, 2)
+	assert.Equal(t, "8.0.36", second.DatabaseInfo.Version)
+}
+
+func TestUniversalDatabaseService_SchemaCacheRefreshBypassesCache(t *testing.T) {
+	cache, err := NewSchemaAnalysisCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	repo := &countingRepository{tables: []string{"customers"}}
+	service := NewUniversalDatabaseService(repo, newCacheTestConfig())
+	service.SetSchemaCache(cache, false)
+
+	_, err = service.ConnectAndAnalyze(context.Background())
+	require.NoError(t, err)
+
+	repo.calls = 0
+	repo.tables = []string{"customers", "invoices", "payments"}
+	service.SetSchemaCache(cache, true)
+
+	refreshed, err := service.ConnectAndAnalyze(context.Background())
+	require.NoError(t, err)
+	assert.False(t, refreshed.FromCache)
+	assert.Positive(t, repo.calls)
+	assert.Len(t, refreshed.SchemaAnalysis.Tables, 3)
+
+	// The refreshed analysis replaces the previous entry
+	entry, ok := cache.Get(SchemaFingerprint(newCacheTestConfig()))
+	require.True(t, ok)
+	assert.Len(t, entry.SchemaAnalysis.Tables, 3)
+}
+
+func TestUniversalDatabaseService_SchemaLookupsUseCache(t *testing.T) {
+	dir := t.TempDir()
+	newService := func(repo *countingRepository) *UniversalDatabaseService {
+		cache, err := NewSchemaAnalysisCache(dir, time.Hour)
+		require.NoError(t, err)
+		service := NewUniversalDatabaseService(repo, newCacheTestConfig())
+		service.SetSchemaCache(cache, false)
+		return service
+	}
+
+	repo := &countingRepository{
+		tables: []string{"customers", "orders"},
+		foreignKeys: map[string][]models.ForeignKeyInfo{
+			"orders": {{Name: "fk_orders_customer", Column: "customer_id", ReferencedTable: "customers", ReferencedColumn: "id"}},
+		},
+	}
+	primaryKeys, err := newService(repo).PrimaryKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"customers": {"id"}, "orders": {"id"}}, primaryKeys)
+	assert.Positive(t, repo.calls)
+
+	// A later start within the TTL answers every lookup from the cached analysis
+	repo.calls = 0
+	service := newService(repo)
+
+	references, err := service.TableReferences(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"orders": {"customers"}}, references)
+
+	columnReferences, err := service.ColumnReferences(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []models.ColumnReference{{
+		Table: "orders", Column: "customer_id", ReferencedTable: "customers", ReferencedColumn: "id", Constraint: "fk_orders_customer",
+	}}, columnReferences)
+
+	columns, err := service.SchemaColumns(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"customers": {"id"}, "orders": {"id"}}, columns)
+	assert.Zero(t, repo.calls, "lookups must not query the database while the cache is fresh")
+}
+
+func TestSchemaAnalysisCache_IgnoresEntriesOfOlderVersions(t *testing.T) {
+	cache, err := NewSchemaAnalysisCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	entry := &SchemaCacheEntry{Fingerprint: "abc", SchemaAnalysis: &models.UniversalSchemaAnalysisResult{}}
+	require.NoError(t, cache.Put(entry))
+	_, ok := cache.Get("abc")
+	require.True(t, ok)
+
+	entry.Version = schemaCacheVersion - 1
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cache.path("abc"), data, 0600))
+	_, ok = cache.Get("abc")
+	assert.False(t, ok, "entries without foreign keys must be re-analyzed")
+}
+
+func TestSchemaAnalysisCache_ExpiresAfterTTL(t *testing.T) {
+	cache, err := NewSchemaAnalysisCache(t.TempDir(), time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	fingerprint := SchemaFingerprint(newCacheTestConfig())
+	require.NoError(t, cache.Put(&SchemaCacheEntry{
+		Fingerprint:    fingerprint,
+		SchemaAnalysis: &models.UniversalSchemaAnalysisResult{DatabaseName: "shop"},
+	}))
+
+	_, ok := cache.Get(fingerprint)
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = cache.Get(fingerprint)
+	assert.False(t, ok)
+}
+
+func TestSchemaFingerprint(t *testing.T) {
+	base := newCacheTestConfig()
+	assert.Equal(t, SchemaFingerprint(base), SchemaFingerprint(newCacheTestConfig()))
+
+	otherPassword := newCacheTestConfig()
+	otherPassword.Password = "rotated"
+	assert.Equal(t, SchemaFingerprint(base), SchemaFingerprint(otherPassword), "password must not affect the key")
+
+	otherSchema := newCacheTestConfig()
+	otherSchema.Schema = "billing"
+	assert.NotEqual(t, SchemaFingerprint(base), SchemaFingerprint(otherSchema))
+
+	filtered := newCacheTestConfig()
+	filtered.DataFiltering.TableWhitelist = []string{"orders"}
+	assert.NotEqual(t, SchemaFingerprint(base), SchemaFingerprint(filtered))
+}
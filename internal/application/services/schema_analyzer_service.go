@@ -18,6 +18,8 @@ import (
 
 	"sql-graph-visualizer/internal/application/ports"
 	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
 )
 
 // SchemaAnalyzerService provides advanced database schema analysis
@@ -64,6 +66,11 @@ func (s *SchemaAnalyzerService) AnalyzeSchemaFromConnection(
 		return nil, fmt.Errorf("relationship analysis failed: %w", err)
 	}
 
+	// Tables without a primary key would silently duplicate nodes downstream
+	warnings, suggestions := MissingPrimaryKeyFindings(result.Tables)
+	result.Warnings = append(result.Warnings, warnings...)
+	result.Suggestions = append(result.Suggestions, suggestions...)
+
 	// Step 4: Generate transformation rules
 	err = s.generateTransformationRules(result)
 	if err != nil {
@@ -90,6 +97,14 @@ func (s *SchemaAnalyzerService) enrichSchemaWithRelationshipAnalysis(
 
 	// Analyze foreign key relationships
 	for _, table := range result.Tables {
+		for _, column := range table.Columns {
+			column.AuditRole = models.DetectAuditRole(column.Name)
+			if column.AuditRole == models.AuditRoleDeleted {
+				table.Recommendations = append(table.Recommendations, fmt.Sprintf(
+					"Column %s marks soft-deleted rows - set transform.exclude_soft_deleted to skip them", column.Name))
+			}
+		}
+
 		relationships, err := s.analyzeForeignKeyRelationships(ctx, db, table.Name)
 		if err != nil {
 			return fmt.Errorf("failed to analyze relationships for table %s: %w", table.Name, err)
@@ -104,6 +119,15 @@ func (s *SchemaAnalyzerService) enrichSchemaWithRelationshipAnalysis(
 		} else {
 			table.GraphType = "NODE"
 		}
+
+		if len(table.Columns) >= WideTableColumns {
+			sample, err := s.sampleNullPatterns(ctx, db, table)
+			if err != nil {
+				// Prefix grouping still works without the sample
+				logrus.Warnf("Failed to sample NULL values of wide table %s: %v", table.Name, err)
+			}
+			table.Recommendations = append(table.Recommendations, WideTableRecommendations(table.Columns, sample)...)
+		}
 	}
 
 	// Identify graph patterns
@@ -112,6 +136,40 @@ func (s *SchemaAnalyzerService) enrichSchemaWithRelationshipAnalysis(
 	return nil
 }
 
+// sampleNullPatterns reads which columns are NULL in up to WideTableSampleRows rows of a table
+func (s *SchemaAnalyzerService) sampleNullPatterns(ctx context.Context, db *sql.DB, table *models.TableInfo) ([]map[string]bool, error) {
+	checks := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		checks[i] = fmt.Sprintf("`%s` IS NULL", strings.ReplaceAll(column.Name, "`", "``"))
+	}
+	query := fmt.Sprintf("SELECT %s FROM `%s` LIMIT %d",
+		strings.Join(checks, ", "), strings.ReplaceAll(table.Name, "`", "``"), WideTableSampleRows)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sample []map[string]bool
+	values := make([]int64, len(table.Columns))
+	targets := make([]any, len(table.Columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return nil, err
+		}
+		nulls := make(map[string]bool, len(table.Columns))
+		for i, column := range table.Columns {
+			nulls[column.Name] = values[i] != 0
+		}
+		sample = append(sample, nulls)
+	}
+	return sample, rows.Err()
+}
+
 // analyzeForeignKeyRelationships discovers foreign key constraints
 func (s *SchemaAnalyzerService) analyzeForeignKeyRelationships(
 	ctx context.Context,
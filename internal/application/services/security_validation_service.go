@@ -108,7 +108,7 @@ func (s *SecurityValidationService) validateConnectionParameters(
 		Description: "Database port security validation",
 	}
 
-	if dbConfig.Port != 3306 && dbConfig.Port != 3307 {
+	if dbConfig.Port != models.DefaultMySQLPort && dbConfig.Port != 3307 {
 		portCheck.Message = "Non-standard MySQL port detected"
 		portCheck.Severity = "LOW"
 	} else {
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/entities"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetRelationshipCardinality annotates every imported relationship with the cardinality of
+// its type (1:1, 1:N or N:M) in the transform.CardinalityProperty property
+func (s *TransformService) SetRelationshipCardinality(enabled bool) {
+	s.relationshipCardinality = enabled
+}
+
+// cardinalityGroup is the relationships of one type between two labels
+type cardinalityGroup struct {
+	relType, sourceType, targetType string
+}
+
+// annotateCardinality sets the cardinality of every relationship of the graph. It is inferred
+// per relationship type and endpoint labels from the relationships themselves: a source
+// linked to several targets means the target key is not unique per foreign key value, a
+// target linked from several sources means the foreign key column is not unique. The
+// relationships of junction rules are many-to-many whatever the rows hold.
+func (s *TransformService) annotateCardinality(graphAggregate *graph.GraphAggregate, rules []*transform_agg.RuleAggregate) {
+	junctionTypes := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.IsJunctionRule() {
+			for _, relType := range rule.Rule.RelationTypes() {
+				junctionTypes[relType] = true
+			}
+		}
+	}
+
+	type degrees struct {
+		targets map[*entities.Node]map[*entities.Node]bool
+		sources map[*entities.Node]map[*entities.Node]bool
+	}
+	link := func(links map[*entities.Node]map[*entities.Node]bool, from, to *entities.Node) {
+		if links[from] == nil {
+			links[from] = make(map[*entities.Node]bool)
+		}
+		links[from][to] = true
+	}
+
+	relationships := graphAggregate.GetRelationships()
+	groups := make(map[cardinalityGroup]*degrees)
+	for _, relationship := range relationships {
+		group := cardinalityGroup{relationship.Type, relationship.SourceNode.Type, relationship.TargetNode.Type}
+		d, ok := groups[group]
+		if !ok {
+			d = &degrees{
+				targets: make(map[*entities.Node]map[*entities.Node]bool),
+				sources: make(map[*entities.Node]map[*entities.Node]bool),
+			}
+			groups[group] = d
+		}
+		link(d.targets, relationship.SourceNode, relationship.TargetNode)
+		link(d.sources, relationship.TargetNode, relationship.SourceNode)
+	}
+
+	unique := func(links map[*entities.Node]map[*entities.Node]bool) bool {
+		for _, linked := range links {
+			if len(linked) > 1 {
+				return false
+			}
+		}
+		return true
+	}
+	cardinalities := make(map[cardinalityGroup]transform.Cardinality, len(groups))
+	for group, d := range groups {
+		cardinality := transform.CardinalityManyToMany
+		if !junctionTypes[group.relType] {
+			cardinality = transform.InferCardinality(unique(d.targets), unique(d.sources))
+		}
+		cardinalities[group] = cardinality
+		logrus.Infof("Relationship %s from %s to %s is %s", group.relType, group.sourceType, group.targetType, cardinality)
+	}
+
+	for i := range relationships {
+		relationship := &relationships[i]
+		group := cardinalityGroup{relationship.Type, relationship.SourceNode.Type, relationship.TargetNode.Type}
+		if relationship.Properties == nil {
+			relationship.Properties = make(map[string]any)
+		}
+		relationship.Properties[transform.CardinalityProperty] = string(cardinalities[group])
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	profilesQuery = "SELECT id, user_id FROM profiles"
+	ordersQuery   = "SELECT id, user_id FROM orders"
+)
+
+// newCardinalityFixture links users to profiles through a unique foreign key, to orders
+// through a repeated one, and to teams through a junction table whose rows happen to pair
+// every user with one team
+func newCardinalityFixture() (*fakeDatabasePort, *fakeRuleRepository) {
+	db := &fakeDatabasePort{
+		rows: []map[string]any{
+			{"_table": "users", "id": int64(1), "name": "Ada"},
+			{"_table": "users", "id": int64(2), "name": "Grace"},
+			{"_table": "profiles", "id": int64(100), "name": "ada"},
+			{"_table": "profiles", "id": int64(200), "name": "grace"},
+			{"_table": "orders", "id": int64(10), "name": "first"},
+			{"_table": "orders", "id": int64(11), "name": "second"},
+			{"_table": "orders", "id": int64(12), "name": "third"},
+			{"_table": "teams", "id": int64(7), "name": "Core"},
+			{"_table": "teams", "id": int64(8), "name": "Docs"},
+			{"_table": "memberships", "user_id": int64(1), "team_id": int64(7)},
+			{"_table": "memberships", "user_id": int64(2), "team_id": int64(8)},
+		},
+		queries: map[string][]map[string]any{
+			profilesQuery: {
+				{"id": int64(100), "user_id": int64(1)},
+				{"id": int64(200), "user_id": int64(2)},
+			},
+			ordersQuery: {
+				{"id": int64(10), "user_id": int64(1)},
+				{"id": int64(11), "user_id": int64(1)},
+				{"id": int64(12), "user_id": int64(2)},
+			},
+		},
+	}
+
+	foreignKey := func(name, query, relationType, sourceType string) *transform_agg.RuleAggregate {
+		return &transform_agg.RuleAggregate{Name: name, Rule: transform.TransformRule{
+			Name:         name,
+			SourceSQL:    query,
+			RuleType:     transform.RelationshipRule,
+			RelationType: relationType,
+			Direction:    transform.Outgoing,
+			SourceNode:   &transform.NodeMapping{Type: sourceType, Key: "id", TargetField: "id"},
+			TargetNode:   &transform.NodeMapping{Type: "User", Key: "user_id", TargetField: "id"},
+		}}
+	}
+	memberships := &transform_agg.RuleAggregate{Name: "memberships", Rule: transform.TransformRule{
+		Name:         "memberships",
+		SourceTable:  "memberships",
+		RuleType:     transform.RelationshipRule,
+		RelationType: "MEMBER_OF",
+		Direction:    transform.Outgoing,
+		SourceNode:   &transform.NodeMapping{Type: "User", Key: "user_id", TargetField: "id"},
+		TargetNode:   &transform.NodeMapping{Type: "Team", Key: "team_id", TargetField: "id"},
+	}}
+
+	return db, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("users", "users", "User"),
+		nodeRule("profiles", "profiles", "Profile"),
+		nodeRule("orders", "orders", "Order"),
+		nodeRule("teams", "teams", "Team"),
+		foreignKey("profile_owner", profilesQuery, "PROFILE_OF", "Profile"),
+		foreignKey("order_owner", ordersQuery, "PLACED_BY", "Order"),
+		memberships,
+	}}
+}
+
+// storedCardinalities maps each stored relationship type to the cardinalities its
+// relationships were annotated with
+func storedCardinalities(neo4j *fakeNeo4jPort) map[string][]any {
+	cardinalities := make(map[string][]any)
+	for _, rel := range neo4j.stored.GetRelationships() {
+		cardinalities[rel.Type] = append(cardinalities[rel.Type], rel.Properties[transform.CardinalityProperty])
+	}
+	return cardinalities
+}
+
+func TestRelationshipCardinality(t *testing.T) {
+	db, rules := newCardinalityFixture()
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetRelationshipCardinality(true)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	cardinalities := storedCardinalities(neo4j)
+	assert.Equal(t, []any{"1:1", "1:1"}, cardinalities["PROFILE_OF"], "a unique foreign key")
+	assert.Equal(t, []any{"1:N", "1:N", "1:N"}, cardinalities["PLACED_BY"], "a repeated foreign key")
+	assert.Equal(t, []any{"N:M", "N:M"}, cardinalities["MEMBER_OF"], "a junction table")
+}
+
+func TestRelationshipCardinalityDisabled(t *testing.T) {
+	db, rules := newCardinalityFixture()
+	neo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(db, neo4j, rules).TransformAndStore(context.Background()))
+
+	require.Len(t, neo4j.stored.GetRelationships(), 7)
+	for _, rel := range neo4j.stored.GetRelationships() {
+		assert.NotContains(t, rel.Properties, transform.CardinalityProperty)
+	}
+}
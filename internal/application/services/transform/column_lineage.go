@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ColumnLabel labels the column nodes of column-level lineage
+	ColumnLabel = "Column"
+	// ColumnReferenceType links a foreign key column to the column it references
+	ColumnReferenceType = "REFERENCES"
+)
+
+// addColumnLineage adds a Column node, identified as "table.column", for both sides of every
+// column reference, and a REFERENCES relationship from the foreign key column to the
+// referenced column
+func (s *TransformService) addColumnLineage(graphAggregate *graph.GraphAggregate) {
+	added := make(map[string]bool)
+	addColumn := func(table, column string) string {
+		id := table + "." + column
+		if !added[id] {
+			added[id] = true
+			if err := graphAggregate.AddNode(ColumnLabel, map[string]any{"id": id, "table": table, "name": column}); err != nil {
+				logrus.Warnf("Warning adding column node %s: %v (continuing)", id, err)
+			}
+		}
+		return id
+	}
+
+	for _, reference := range s.columnReferences {
+		source := addColumn(reference.Table, reference.Column)
+		target := addColumn(reference.ReferencedTable, reference.ReferencedColumn)
+		properties := map[string]any{}
+		if reference.Constraint != "" {
+			properties["constraint"] = reference.Constraint
+		}
+		if err := graphAggregate.AddRelationship(ColumnReferenceType, transform.Outgoing,
+			ColumnLabel, source, "id", ColumnLabel, target, "id", properties); err != nil {
+			logrus.Warnf("Warning linking column %s to %s: %v (continuing)", source, target, err)
+		}
+	}
+	logrus.Infof("Added column lineage: %d columns, %d references", len(added), len(s.columnReferences))
+}
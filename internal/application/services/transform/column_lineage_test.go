@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enrollmentReferences are the foreign keys of the enrollments table
+func enrollmentReferences() []models.ColumnReference {
+	return []models.ColumnReference{
+		{Table: "enrollments", Column: "student_id", ReferencedTable: "students", ReferencedColumn: "id", Constraint: "fk_enrollment_student"},
+		{Table: "enrollments", Column: "course_id", ReferencedTable: "courses", ReferencedColumn: "id", Constraint: "fk_enrollment_course"},
+	}
+}
+
+func TestTransformAndStore_ColumnLineage(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("students", "students", "Student")}}
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	service.SetColumnLineage(enrollmentReferences())
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	columns := map[string]map[string]any{}
+	for _, node := range neo4j.stored.GetNodes() {
+		if node.Type == ColumnLabel {
+			columns[node.Properties["id"].(string)] = node.Properties
+		}
+	}
+	assert.Equal(t, map[string]map[string]any{
+		"enrollments.student_id": {"id": "enrollments.student_id", "table": "enrollments", "name": "student_id"},
+		"students.id":            {"id": "students.id", "table": "students", "name": "id"},
+		"enrollments.course_id":  {"id": "enrollments.course_id", "table": "enrollments", "name": "course_id"},
+		"courses.id":             {"id": "courses.id", "table": "courses", "name": "id"},
+	}, columns)
+
+	var references []string
+	for _, rel := range neo4j.stored.GetRelationships() {
+		if rel.Type == ColumnReferenceType {
+			references = append(references, rel.SourceNode.Properties["id"].(string)+"->"+rel.TargetNode.Properties["id"].(string))
+		}
+	}
+	assert.Equal(t, []string{"enrollments.student_id->students.id", "enrollments.course_id->courses.id"}, references)
+	assert.Equal(t, "fk_enrollment_student", neo4j.stored.GetRelationships()[0].Properties["constraint"])
+}
+
+func TestTransformAndStore_ColumnLineageSharesReferencedColumns(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(newEnrollmentFixture(), neo4j, &fakeRuleRepository{})
+	service.SetColumnLineage([]models.ColumnReference{
+		{Table: "orders", Column: "customer_id", ReferencedTable: "customers", ReferencedColumn: "id"},
+		{Table: "invoices", Column: "customer_id", ReferencedTable: "customers", ReferencedColumn: "id"},
+	})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Len(t, neo4j.stored.GetNodes(), 3)
+	require.Len(t, neo4j.stored.GetRelationships(), 2)
+	assert.Same(t, neo4j.stored.GetRelationships()[0].TargetNode, neo4j.stored.GetRelationships()[1].TargetNode)
+}
+
+func TestTransformAndStore_NoColumnLineageByDefault(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("students", "students", "Student")}}
+	require.NoError(t, NewTransformService(newEnrollmentFixture(), neo4j, rules).TransformAndStore(context.Background()))
+
+	for _, node := range neo4j.stored.GetNodes() {
+		assert.NotEqual(t, ColumnLabel, node.Type)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// resolveIdentity returns the rule to apply for a node rule: unchanged when it has an
+// explicit key or detection is off, otherwise a copy keyed by the table's primary key or by
+// the identity fallback. The configured rule itself is never modified.
+func (s *TransformService) resolveIdentity(rule *transform_agg.RuleAggregate) *transform_agg.RuleAggregate {
+	if !s.detectIdentity || rule.Rule.HasExplicitKey() {
+		return rule
+	}
+
+	resolved := *rule
+	if keys := s.primaryKeys[rule.Rule.SourceTable]; rule.Rule.SourceSQL == "" && len(keys) > 0 {
+		logrus.Infof("Node rule %s has no key; identifying nodes by primary key %v of table %s", rule.Rule.Name, keys, rule.Rule.SourceTable)
+		resolved.Rule.KeyColumns = append([]string(nil), keys...)
+		return &resolved
+	}
+
+	source := "table " + rule.Rule.SourceTable
+	if rule.Rule.SourceSQL != "" {
+		source = "its SQL query"
+	}
+	switch s.identityFallback {
+	case transform.IdentityFallbackGenerate:
+		logrus.Warnf("Node rule %s has no key and %s has no primary key; every row gets a new id, so re-runs duplicate its nodes", rule.Rule.Name, source)
+		return rule
+	default:
+		logrus.Warnf("Node rule %s has no key and %s has no primary key; identifying nodes by a hash of all columns, so identical rows become one node", rule.Rule.Name, source)
+		resolved.Rule.HashIdentity = true
+		return &resolved
+	}
+}
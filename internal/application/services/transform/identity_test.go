@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keylessRule maps only a name, leaving the node's identity to detection
+func keylessRule(table, targetType string) *transform_agg.RuleAggregate {
+	return &transform_agg.RuleAggregate{
+		Name: table,
+		Rule: transform.TransformRule{
+			Name:          table,
+			SourceTable:   table,
+			RuleType:      transform.NodeRule,
+			TargetType:    targetType,
+			FieldMappings: map[string]string{"name": "name"},
+		},
+	}
+}
+
+func newIdentityFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "customers", "id": int64(1), "name": "Ada"},
+		{"_table": "customers", "id": int64(2), "name": "Grace"},
+		{"_table": "order_lines", "order_id": int64(7), "line_no": int64(1), "name": "Keyboard"},
+		{"_table": "order_lines", "order_id": int64(7), "line_no": int64(2), "name": "Mouse"},
+		{"_table": "order_lines", "order_id": int64(8), "line_no": int64(1), "name": "Keyboard"},
+		{"_table": "visits", "page": "/home", "name": "visit"},
+		{"_table": "visits", "page": "/home", "name": "visit"},
+		{"_table": "visits", "page": "/docs", "name": "visit"},
+	}}
+}
+
+// nodeIDs runs the transform and returns the sorted ids of the stored nodes of nodeType
+func nodeIDs(t *testing.T, service *TransformService, neo4j *fakeNeo4jPort, nodeType string) []string {
+	t.Helper()
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	var ids []string
+	for _, node := range neo4j.stored.GetNodes() {
+		if node.Type == nodeType {
+			ids = append(ids, node.Properties["id"].(string))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newIdentityService(fallback transform.IdentityFallback) (*TransformService, *fakeNeo4jPort) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		keylessRule("customers", "Customer"),
+		keylessRule("order_lines", "OrderLine"),
+		keylessRule("visits", "Visit"),
+	}}
+	service := NewTransformService(newIdentityFixture(), neo4j, rules)
+	service.SetIdentityDetection(map[string][]string{
+		"customers":   {"id"},
+		"order_lines": {"order_id", "line_no"},
+	}, fallback)
+	return service, neo4j
+}
+
+func TestIdentityDetection_SinglePrimaryKey(t *testing.T) {
+	service, neo4j := newIdentityService(transform.IdentityFallbackHash)
+
+	assert.Equal(t, []string{"1", "2"}, nodeIDs(t, service, neo4j, "Customer"))
+	// A second run produces the same nodes instead of new ones
+	assert.Equal(t, []string{"1", "2"}, nodeIDs(t, service, neo4j, "Customer"))
+}
+
+func TestIdentityDetection_CompositePrimaryKey(t *testing.T) {
+	service, neo4j := newIdentityService(transform.IdentityFallbackHash)
+
+	assert.Equal(t, []string{"7|1", "7|2", "8|1"}, nodeIDs(t, service, neo4j, "OrderLine"))
+	for _, node := range neo4j.stored.GetNodes() {
+		if node.Type == "OrderLine" {
+			assert.Contains(t, node.MergeKeys, "order_id")
+			assert.Contains(t, node.MergeKeys, "line_no")
+		}
+	}
+}
+
+func TestIdentityDetection_NoPrimaryKeyHashesAllColumns(t *testing.T) {
+	service, neo4j := newIdentityService(transform.IdentityFallbackHash)
+
+	first := nodeIDs(t, service, neo4j, "Visit")
+	require.Len(t, first, 2, "identical rows become one node")
+	assert.Equal(t, first, nodeIDs(t, service, neo4j, "Visit"), "ids are stable across runs")
+	assert.Contains(t, first, transform.HashID(map[string]any{"page": "/docs", "name": "visit"}))
+}
+
+func TestIdentityDetection_GenerateFallbackKeepsEveryRow(t *testing.T) {
+	service, neo4j := newIdentityService(transform.IdentityFallbackGenerate)
+
+	assert.Len(t, nodeIDs(t, service, neo4j, "Visit"), 3)
+	assert.Equal(t, []string{"1", "2"}, nodeIDs(t, service, neo4j, "Customer"), "primary keys are still used")
+}
+
+func TestIdentityDetection_ExplicitKeyWins(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rule := keylessRule("order_lines", "OrderLine")
+	rule.Rule.KeyColumns = []string{"order_id"}
+	service := NewTransformService(newIdentityFixture(), neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	service.SetIdentityDetection(map[string][]string{"order_lines": {"order_id", "line_no"}}, transform.IdentityFallbackHash)
+
+	assert.Equal(t, []string{"7", "8"}, nodeIDs(t, service, neo4j, "OrderLine"))
+	assert.Equal(t, []string{"order_id"}, rule.Rule.KeyColumns, "the configured rule is not modified")
+}
+
+func TestIDStrategies_StableAcrossRuns(t *testing.T) {
+	for _, strategy := range []transform.IDStrategyName{transform.IDStrategyComposite, transform.IDStrategyHash, transform.IDStrategyUUID} {
+		t.Run(string(strategy), func(t *testing.T) {
+			run := func() ([]string, int) {
+				students := nodeRule("students", "students", "Student")
+				students.Rule.IDStrategy = strategy
+				enrollments := enrollmentRule(nil)
+				enrollments.Rule.SourceNode.IDStrategy = strategy
+
+				neo4j := &fakeNeo4jPort{}
+				rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+					students, nodeRule("courses", "courses", "Course"), enrollments,
+				}}
+				service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+				return nodeIDs(t, service, neo4j, "Student"), len(neo4j.stored.GetRelationships())
+			}
+
+			first, relationships := run()
+			second, _ := run()
+			require.Len(t, first, 2)
+			assert.Equal(t, first, second, "the same key gives the same id in every run")
+			assert.Equal(t, 2, relationships, "relationship endpoints derive the same ids")
+		})
+	}
+}
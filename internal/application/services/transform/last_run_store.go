@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FileRunStore keeps the metadata of the last successful transform run in a JSON file
+type FileRunStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileRunStore creates a store writing to the file at path; an empty path uses
+// last_run.json in the user config directory
+func NewFileRunStore(path string) (*FileRunStore, error) {
+	if path == "" {
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve user config directory: %w", err)
+		}
+		path = filepath.Join(userConfigDir, "sql-graph-visualizer", "last_run.json")
+	}
+	return &FileRunStore{path: path}, nil
+}
+
+// LoadLastRun returns the stored run, or nil when the file does not exist yet
+func (s *FileRunStore) LoadLastRun() (*ports.GraphSnapshotMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last transform run: %w", err)
+	}
+	var metadata ports.GraphSnapshotMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse last transform run: %w", err)
+	}
+	return &metadata, nil
+}
+
+// SaveLastRun replaces the stored run with metadata
+func (s *FileRunStore) SaveLastRun(metadata ports.GraphSnapshotMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal last transform run: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create transform state directory: %w", err)
+	}
+	// Write to a temporary file first so an interrupted save never loses the last run
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write last transform run: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to store last transform run: %w", err)
+	}
+	return nil
+}
+
+// SetRunStore persists the start of every successful run in store and resumes from the run
+// stored there, so "@last_run" query parameters cover only rows changed since that run even
+// in a new process
+func (s *TransformService) SetRunStore(store ports.TransformRunStore) error {
+	last, err := store.LoadLastRun()
+	if err != nil {
+		return err
+	}
+
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	s.runStore = store
+	if last != nil {
+		s.lastRun = last.RunStartedAt
+		logrus.Infof("Resuming from the transform run started at %s", last.RunStartedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// saveLastRun records a successful run started at startedAt in the run store
+func (s *TransformService) saveLastRun(startedAt time.Time) {
+	if s.runStore == nil {
+		return
+	}
+	if err := s.runStore.SaveLastRun(ports.GraphSnapshotMetadata{RunStartedAt: startedAt}); err != nil {
+		logrus.Warnf("Failed to persist the last transform run: %v", err)
+	}
+}
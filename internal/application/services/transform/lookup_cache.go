@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultLookupCacheTotalRows bounds the rows held by the lookup cache when no total is set
+const DefaultLookupCacheTotalRows = 50000
+
+// lookupCache keeps the results of small source queries for the duration of one run, so
+// rules reading the same lookup table do not query it again
+type lookupCache struct {
+	mu           sync.Mutex
+	maxRows      int
+	maxTotalRows int
+	totalRows    int
+	entries      map[string][]map[string]any
+	hits         int
+	misses       int
+}
+
+func newLookupCache(maxRows, maxTotalRows int) *lookupCache {
+	return &lookupCache{
+		maxRows:      maxRows,
+		maxTotalRows: maxTotalRows,
+		entries:      make(map[string][]map[string]any),
+	}
+}
+
+// get returns a copy of the cached result of query run with args
+func (c *lookupCache) get(query string, args []any) ([]map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rows, ok := c.entries[cacheKey(query, args)]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	return copyRows(rows), true
+}
+
+// put caches a copy of the result of query run with args when it fits the row limits
+func (c *lookupCache) put(query string, args []any, rows []map[string]any) {
+	if len(rows) > c.maxRows {
+		return
+	}
+	key := cacheKey(query, args)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok || c.totalRows+len(rows) > c.maxTotalRows {
+		return
+	}
+	c.entries[key] = copyRows(rows)
+	c.totalRows += len(rows)
+}
+
+// cacheKey identifies the result of query run with args; values of different types, such
+// as "1" and 1, give different keys
+func cacheKey(query string, args []any) string {
+	if len(args) == 0 {
+		return query
+	}
+	return query + "\x00" + fmt.Sprintf("%#v", args)
+}
+
+// copyRows copies the rows and their column maps; callers filter and convert rows in place
+func copyRows(rows []map[string]any) []map[string]any {
+	copied := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		copied[i] = maps.Clone(row)
+	}
+	return copied
+}
+
+// SetLookupCache caches, within each run, the results of source queries returning up to
+// maxRows rows, holding at most maxTotalRows rows in all (DefaultLookupCacheTotalRows when
+// not positive). Rules that read the same small lookup table then query it once per run.
+// A maxRows of 0 disables the cache.
+func (s *TransformService) SetLookupCache(maxRows, maxTotalRows int) {
+	if maxTotalRows <= 0 {
+		maxTotalRows = DefaultLookupCacheTotalRows
+	}
+	s.lookupCacheRows = maxRows
+	s.lookupCacheTotalRows = maxTotalRows
+}
+
+// startLookupCache gives a run an empty cache, so every run reads current source data
+func (s *TransformService) startLookupCache() {
+	s.lookups = nil
+	if s.lookupCacheRows > 0 {
+		s.lookups = newLookupCache(s.lookupCacheRows, s.lookupCacheTotalRows)
+	}
+}
+
+// finishLookupCache reports the cache use of the run and drops the cached rows
+func (s *TransformService) finishLookupCache() {
+	if s.lookups == nil {
+		return
+	}
+	logrus.Infof("Lookup cache: %d queries answered from cache, %d read from the source (%d rows cached)",
+		s.lookups.hits, s.lookups.misses, s.lookups.totalRows)
+	s.lookups = nil
+}
+
+// querySource runs a source query of the current run with args, answering repeated small
+// queries from the run's lookup cache
+func (s *TransformService) querySource(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	cache := s.lookups
+	if cache == nil {
+		return s.runQuery(ctx, query, args)
+	}
+	if rows, ok := cache.get(query, args); ok {
+		return rows, nil
+	}
+	rows, err := s.runQuery(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	cache.put(query, args, rows)
+	return rows, nil
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const countriesQuery = "SELECT id, name, region_id FROM countries"
+
+// countingDatabasePort counts the queries run against a fakeDatabasePort
+type countingDatabasePort struct {
+	*fakeDatabasePort
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *countingDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) {
+	c.mu.Lock()
+	c.counts[query]++
+	c.mu.Unlock()
+	return c.fakeDatabasePort.ExecuteQuery(query)
+}
+
+// newLookupFixture has a small countries lookup read by a node rule and two relationship
+// rules, next to a larger customers query
+func newLookupFixture(customers int) (*countingDatabasePort, *fakeRuleRepository) {
+	countries := []map[string]any{
+		{"id": int64(1), "name": "Czechia", "region_id": int64(10)},
+		{"id": int64(2), "name": "Austria", "region_id": int64(10)},
+		{"id": int64(3), "name": "Japan", "region_id": int64(20)},
+	}
+	customerRows := make([]map[string]any, customers)
+	for i := range customerRows {
+		customerRows[i] = map[string]any{"id": int64(i + 1), "name": fmt.Sprintf("customer %d", i+1), "country_id": int64(i%3 + 1)}
+	}
+	db := &countingDatabasePort{
+		fakeDatabasePort: &fakeDatabasePort{
+			rows: []map[string]any{
+				{"_table": "regions", "id": int64(10), "name": "Europe"},
+				{"_table": "regions", "id": int64(20), "name": "Asia"},
+			},
+			queries: map[string][]map[string]any{
+				countriesQuery: countries,
+				"SELECT id, name, country_id FROM customers": customerRows,
+			},
+		},
+		counts: make(map[string]int),
+	}
+
+	countryNodes := nodeRule("countries", "", "Country")
+	countryNodes.Rule.SourceSQL = countriesQuery
+	customerNodes := nodeRule("customers", "", "Customer")
+	customerNodes.Rule.SourceSQL = "SELECT id, name, country_id FROM customers"
+
+	link := func(name, relationType string, source, target *transform.NodeMapping) *transform_agg.RuleAggregate {
+		return &transform_agg.RuleAggregate{Name: name, Rule: transform.TransformRule{
+			Name:         name,
+			SourceSQL:    countriesQuery,
+			RuleType:     transform.RelationshipRule,
+			RelationType: relationType,
+			Direction:    transform.Outgoing,
+			SourceNode:   source,
+			TargetNode:   target,
+		}}
+	}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("regions", "regions", "Region"),
+		countryNodes,
+		customerNodes,
+		link("country_regions", "IN_REGION",
+			&transform.NodeMapping{Type: "Country", Key: "id", TargetField: "id"},
+			&transform.NodeMapping{Type: "Region", Key: "region_id", TargetField: "id"}),
+		link("region_countries", "CONTAINS",
+			&transform.NodeMapping{Type: "Region", Key: "region_id", TargetField: "id"},
+			&transform.NodeMapping{Type: "Country", Key: "id", TargetField: "id"}),
+	}}
+	return db, rules
+}
+
+// graphSummary lists the stored nodes and relationships in a comparable form
+func graphSummary(neo4j *fakeNeo4jPort) []string {
+	var summary []string
+	for _, node := range neo4j.stored.GetNodes() {
+		summary = append(summary, fmt.Sprintf("%s %v %v", node.Type, node.Properties["id"], node.Properties["name"]))
+	}
+	for _, rel := range neo4j.stored.GetRelationships() {
+		summary = append(summary, fmt.Sprintf("%s %v->%v", rel.Type, rel.SourceNode.Properties["id"], rel.TargetNode.Properties["id"]))
+	}
+	sort.Strings(summary)
+	return summary
+}
+
+func runLookupFixture(t testing.TB, customers int, configure func(*TransformService)) (*countingDatabasePort, []string) {
+	db, rules := newLookupFixture(customers)
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	configure(service)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	return db, graphSummary(neo4j)
+}
+
+func TestLookupCacheMatchesDirectReads(t *testing.T) {
+	direct, directGraph := runLookupFixture(t, 30, func(*TransformService) {})
+	cached, cachedGraph := runLookupFixture(t, 30, func(s *TransformService) { s.SetLookupCache(10, 0) })
+
+	assert.Equal(t, directGraph, cachedGraph, "cached lookups build the same graph")
+	assert.Contains(t, cachedGraph, "IN_REGION 3->20")
+	assert.Equal(t, 3, direct.counts[countriesQuery])
+	assert.Equal(t, 1, cached.counts[countriesQuery], "the lookup is read once per run")
+	assert.Equal(t, 1, cached.counts["SELECT id, name, country_id FROM customers"])
+}
+
+func TestLookupCacheSkipsLargeResults(t *testing.T) {
+	db, rules := newLookupFixture(30)
+	rules.rules = append(rules.rules, rules.rules[2])
+
+	service := NewTransformService(db, &fakeNeo4jPort{}, rules)
+	service.SetLookupCache(10, 0)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	assert.Equal(t, 2, db.counts["SELECT id, name, country_id FROM customers"], "results over the row limit are not cached")
+
+	// The total bound leaves no room for the lookup either
+	db, rules = newLookupFixture(0)
+	service = NewTransformService(db, &fakeNeo4jPort{}, rules)
+	service.SetLookupCache(10, 2)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	assert.Equal(t, 3, db.counts[countriesQuery])
+}
+
+func TestLookupCacheIsPerRun(t *testing.T) {
+	db, rules := newLookupFixture(3)
+	service := NewTransformService(db, &fakeNeo4jPort{}, rules)
+	service.SetLookupCache(10, 0)
+
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	assert.Equal(t, 2, db.counts[countriesQuery], "every run reads current data")
+}
+
+func BenchmarkTransformLookupCache(b *testing.B) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	for _, bench := range []struct {
+		name      string
+		configure func(*TransformService)
+	}{
+		{"direct", func(*TransformService) {}},
+		{"cached", func(s *TransformService) { s.SetLookupCache(100, 0) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			queries := 0
+			for i := 0; i < b.N; i++ {
+				db, _ := runLookupFixture(b, 200, bench.configure)
+				queries += db.counts[countriesQuery]
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "lookup-queries/op")
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetPropertyNaming renames the properties of imported nodes and relationships, such as
+// created_at to createdAt. Rules keep referring to the source names: properties are renamed
+// once the graph is built, just before it is stored.
+func (s *TransformService) SetPropertyNaming(naming transform.PropertyNaming) {
+	s.propertyNaming = naming
+}
+
+// renameProperties applies the property naming to every node and relationship of the graph
+func (s *TransformService) renameProperties(graphAggregate *graph.GraphAggregate) {
+	if s.propertyNaming.IsZero() {
+		return
+	}
+
+	conflicts := make(map[string]bool)
+	for _, node := range graphAggregate.GetNodes() {
+		for _, name := range s.propertyNaming.RenameProperties(node.Properties) {
+			conflicts[name] = true
+		}
+		if node.MergeKeys != nil {
+			s.propertyNaming.RenameProperties(node.MergeKeys)
+		}
+	}
+	for _, relationship := range graphAggregate.GetRelationships() {
+		if relationship.Properties == nil {
+			continue
+		}
+		for _, name := range s.propertyNaming.RenameProperties(relationship.Properties) {
+			conflicts[name] = true
+		}
+		if relationship.MergeKeys != nil {
+			s.propertyNaming.RenameProperties(relationship.MergeKeys)
+		}
+	}
+
+	for name := range conflicts {
+		logrus.Warnf("Property %s keeps its name: its renamed name is already used by another property", name)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/entities"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNamingFixture() (*fakeDatabasePort, *fakeRuleRepository) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "users", "id": int64(1), "name": "Ada", "created_at": "2025-03-01", "signup_source": "web"},
+		{"_table": "teams", "id": int64(10), "name": "Core"},
+		{"_table": "memberships", "user_id": int64(1), "team_id": int64(10), "joined_at": "2025-03-02"},
+	}}
+
+	users := nodeRule("users", "users", "User")
+	users.Rule.FieldMappings["created_at"] = "created_at"
+	users.Rule.FieldMappings["signup_source"] = "signup_source"
+	memberships := &transform_agg.RuleAggregate{Name: "memberships", Rule: transform.TransformRule{
+		Name:         "memberships",
+		SourceTable:  "memberships",
+		RuleType:     transform.RelationshipRule,
+		RelationType: "MEMBER_OF",
+		Direction:    transform.Outgoing,
+		SourceNode:   &transform.NodeMapping{Type: "User", Key: "user_id", TargetField: "id"},
+		TargetNode:   &transform.NodeMapping{Type: "Team", Key: "team_id", TargetField: "id"},
+		Properties:   map[string]string{"joined_at": "joined_at"},
+	}}
+	return db, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{users, nodeRule("teams", "teams", "Team"), memberships}}
+}
+
+func TestPropertyNamingCamelCase(t *testing.T) {
+	db, rules := newNamingFixture()
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetPropertyNaming(transform.PropertyNaming{Case: transform.NamingCaseCamel})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	user := storedNode(t, neo4j, "User")
+	assert.Equal(t, "2025-03-01", user.Properties["createdAt"])
+	assert.Equal(t, "web", user.Properties["signupSource"])
+	assert.NotContains(t, user.Properties, "created_at")
+	assert.Equal(t, "1", user.Properties["id"])
+
+	relationships := neo4j.stored.GetRelationships()
+	require.Len(t, relationships, 1, "endpoints still match by the source names")
+	assert.Equal(t, "2025-03-02", relationships[0].Properties["joinedAt"])
+}
+
+func TestPropertyNamingDisabled(t *testing.T) {
+	db, rules := newNamingFixture()
+	neo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(db, neo4j, rules).TransformAndStore(context.Background()))
+
+	user := storedNode(t, neo4j, "User")
+	assert.Equal(t, "2025-03-01", user.Properties["created_at"])
+	assert.NotContains(t, user.Properties, "createdAt")
+}
+
+func TestPropertyNamingStripAndRename(t *testing.T) {
+	db, rules := newNamingFixture()
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetPropertyNaming(transform.PropertyNaming{
+		StripPrefixes: []string{"signup_"},
+		Rename:        map[string]string{"created_at": "registered"},
+	})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	user := storedNode(t, neo4j, "User")
+	assert.Equal(t, "2025-03-01", user.Properties["registered"])
+	assert.Equal(t, "web", user.Properties["source"])
+}
+
+// storedNode returns the only stored node of a type
+func storedNode(t *testing.T, neo4j *fakeNeo4jPort, nodeType string) *entities.Node {
+	t.Helper()
+	var found []*entities.Node
+	for _, node := range neo4j.stored.GetNodes() {
+		if node.Type == nodeType {
+			found = append(found, node)
+		}
+	}
+	require.Len(t, found, 1)
+	return found[0]
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"time"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+// SetProvenance tags every node and relationship a run imports with where it came from:
+// the source database, the source table of its rule (omitted for query rules without one),
+// the run id and the time the run started
+func (s *TransformService) SetProvenance(database string) {
+	s.provenance = true
+	s.sourceDatabase = database
+}
+
+// provenanceOf returns the provenance properties of the items produced by rule in the
+// active run, or nil when provenance is disabled
+func (s *TransformService) provenanceOf(rule *transform_agg.RuleAggregate) map[string]any {
+	if !s.provenance {
+		return nil
+	}
+
+	s.runMutex.Lock()
+	runID, startedAt := s.progress.RunID, s.progress.StartedAt
+	s.runMutex.Unlock()
+
+	properties := map[string]any{
+		transform.ProvenanceRunID:      runID,
+		transform.ProvenanceImportedAt: startedAt.UTC().Format(time.RFC3339),
+	}
+	if s.sourceDatabase != "" {
+		properties[transform.ProvenanceDatabase] = s.sourceDatabase
+	}
+	if rule.Rule.SourceTable != "" {
+		properties[transform.ProvenanceTable] = rule.Rule.SourceTable
+	}
+	return properties
+}
+
+// tagProvenance adds provenance to a transformed node, or to the properties of a
+// transformed relationship
+func tagProvenance(item map[string]any, provenance map[string]any) {
+	if provenance == nil {
+		return
+	}
+	target := item
+	if _, isRelationship := item["source"]; isRelationship {
+		properties, ok := item["properties"].(map[string]any)
+		if !ok {
+			if item["properties"] != nil {
+				// Properties given as JSON text are left as they are
+				return
+			}
+			properties = make(map[string]any)
+			item["properties"] = properties
+		}
+		target = properties
+	}
+	for key, value := range provenance {
+		target[key] = value
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvenanceService(neo4j *fakeNeo4jPort) *TransformService {
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(nil),
+	}}
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	service.SetProvenance("school")
+	return service
+}
+
+func assertProvenance(t *testing.T, stored *graph.GraphAggregate, runID string, startedAt time.Time) {
+	t.Helper()
+	importedAt := startedAt.UTC().Format(time.RFC3339)
+
+	require.NotEmpty(t, stored.GetNodes())
+	for _, node := range stored.GetNodes() {
+		assert.Equal(t, "school", node.Properties[transform.ProvenanceDatabase])
+		assert.Equal(t, map[string]string{"Student": "students", "Course": "courses"}[node.Type], node.Properties[transform.ProvenanceTable])
+		assert.Equal(t, runID, node.Properties[transform.ProvenanceRunID])
+		assert.Equal(t, importedAt, node.Properties[transform.ProvenanceImportedAt])
+	}
+
+	require.NotEmpty(t, stored.GetRelationships())
+	for _, rel := range stored.GetRelationships() {
+		assert.Equal(t, "school", rel.Properties[transform.ProvenanceDatabase])
+		assert.Equal(t, "enrollments", rel.Properties[transform.ProvenanceTable])
+		assert.Equal(t, runID, rel.Properties[transform.ProvenanceRunID])
+		assert.Equal(t, importedAt, rel.Properties[transform.ProvenanceImportedAt])
+		assert.Contains(t, rel.Properties, "grade", "junction columns are kept")
+	}
+}
+
+func TestTransformAndStore_Provenance(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	service := newProvenanceService(neo4j)
+
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	first := service.Progress()
+	require.NotEmpty(t, first.RunID)
+	assertProvenance(t, neo4j.stored, first.RunID, first.StartedAt)
+
+	// A second run replaces the run id and import time of every element
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	second := service.Progress()
+	assert.NotEqual(t, first.RunID, second.RunID)
+	assert.False(t, second.StartedAt.Before(first.StartedAt))
+	assertProvenance(t, neo4j.stored, second.RunID, second.StartedAt)
+}
+
+func TestTransformAndStore_NoProvenanceByDefault(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		enrollmentRule(nil),
+	}}
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	for _, node := range neo4j.stored.GetNodes() {
+		assert.NotContains(t, node.Properties, transform.ProvenanceRunID)
+		assert.NotContains(t, node.Properties, transform.ProvenanceImportedAt)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultAnnotationLabel marks nodes holding manual annotations, which reconciling keeps
+const DefaultAnnotationLabel = "Annotation"
+
+// SetReconcile makes runs write only the differences between the transformed graph and the
+// graph already stored, instead of the whole graph. Nodes labelled annotationLabel (default
+// "Annotation") are never changed, so manual annotations survive restarts.
+func (s *TransformService) SetReconcile(annotationLabel string) {
+	if annotationLabel == "" {
+		annotationLabel = DefaultAnnotationLabel
+	}
+	s.reconcile = true
+	s.annotationLabel = annotationLabel
+}
+
+// reconcileGraph reads the stored part of the graph the rules own and applies the delta
+func (s *TransformService) reconcileGraph(ctx context.Context, graphAggregate *graph.GraphAggregate, rules []*transform_agg.RuleAggregate) error {
+	reconciler, ok := s.neo4jPort.(ports.GraphReconciler)
+	if !ok {
+		return fmt.Errorf("neo4j port does not support reconciling")
+	}
+
+	storedNodes, storedRels, err := reconciler.ReadManagedGraph(ctx, reconcileScope(rules, graphAggregate, s.annotationLabel))
+	if err != nil {
+		return fmt.Errorf("failed to read stored graph: %w", err)
+	}
+
+	delta := diffGraph(storedNodes, storedRels, graphAggregate)
+	logrus.WithFields(logrus.Fields{
+		"nodes_created":         len(delta.CreateNodes),
+		"nodes_updated":         len(delta.UpdateNodes),
+		"nodes_deleted":         len(delta.DeleteNodes),
+		"relationships_created": len(delta.CreateRelationships),
+		"relationships_updated": len(delta.UpdateRelationships),
+		"relationships_deleted": len(delta.DeleteRelationships),
+	}).Info("Reconciled graph with source data")
+	if delta.Empty() {
+		return nil
+	}
+	return reconciler.ApplyGraphDelta(ctx, delta, s.recordCommit)
+}
+
+// reconcileScope covers the node and relationship types the rules produce, and those in
+// the transformed graph, such as bucket nodes for NULL keys
+func reconcileScope(rules []*transform_agg.RuleAggregate, graphAggregate *graph.GraphAggregate, annotationLabel string) ports.ReconcileScope {
+	labels := make(map[string]bool)
+	types := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Rule.RuleType == transform.NodeRule && rule.Rule.TargetType != "" {
+			labels[rule.Rule.TargetType] = true
+		}
+		if rule.Rule.RuleType == transform.RelationshipRule {
+			for _, relType := range rule.Rule.RelationTypes() {
+				types[relType] = true
+			}
+		}
+	}
+	for _, node := range graphAggregate.GetNodes() {
+		labels[node.Type] = true
+	}
+	for _, rel := range graphAggregate.GetRelationships() {
+		types[rel.Type] = true
+	}
+
+	return ports.ReconcileScope{
+		NodeLabels:        sortedKeys(labels),
+		RelationshipTypes: sortedKeys(types),
+		AnnotationLabel:   annotationLabel,
+	}
+}
+
+// diffGraph compares the stored graph with the transformed one. Nodes are matched on label
+// and id property, relationships on type and endpoints; matched elements whose properties
+// differ, or nodes whose labels differ, are updated.
+func diffGraph(storedNodes []ports.ExportedNode, storedRels []ports.ExportedRelationship, desired *graph.GraphAggregate) *ports.GraphDelta {
+	delta := &ports.GraphDelta{}
+
+	stored := make(map[string]ports.ExportedNode, len(storedNodes))
+	for _, node := range storedNodes {
+		stored[elementKey(node.Label, node.Properties["id"])] = node
+	}
+	for _, node := range desired.GetNodes() {
+		key := elementKey(node.Type, node.Properties["id"])
+		existing, ok := stored[key]
+		labels := node.Labels()
+		switch {
+		case !ok:
+			delta.CreateNodes = append(delta.CreateNodes, ports.ExportedNode{Label: node.Type, Labels: labels, Properties: node.Properties})
+		case !sameProperties(existing.Properties, node.Properties) || !sameLabels(existing.AllLabels(), labels):
+			delta.UpdateNodes = append(delta.UpdateNodes, ports.ExportedNode{ID: existing.ID, Label: node.Type, Labels: labels, Properties: node.Properties})
+			if removed := missingLabels(existing.AllLabels(), labels); len(removed) > 0 {
+				if delta.RemoveLabels == nil {
+					delta.RemoveLabels = make(map[int64][]string)
+				}
+				delta.RemoveLabels[existing.ID] = removed
+			}
+		}
+		delete(stored, key)
+	}
+	for _, node := range storedNodes {
+		if _, removed := stored[elementKey(node.Label, node.Properties["id"])]; removed {
+			delta.DeleteNodes = append(delta.DeleteNodes, node)
+		}
+	}
+
+	// Several relationships may share type and endpoints, so matching is done in two passes:
+	// first those with equal properties, then the rest pairwise as updates
+	remaining := make(map[string][]ports.ExportedRelationship)
+	for _, rel := range storedRels {
+		key := relationshipKey(rel)
+		remaining[key] = append(remaining[key], rel)
+	}
+	var unmatched []ports.ExportedRelationship
+	for _, rel := range desired.GetRelationships() {
+		wanted := ports.ExportedRelationship{
+			Type:        rel.Type,
+			SourceLabel: rel.SourceNode.Type,
+			SourceKey:   rel.SourceNode.Properties["id"],
+			TargetLabel: rel.TargetNode.Type,
+			TargetKey:   rel.TargetNode.Properties["id"],
+			Properties:  rel.Properties,
+		}
+		key := relationshipKey(wanted)
+		candidates := remaining[key]
+		match := -1
+		for i, candidate := range candidates {
+			if sameProperties(candidate.Properties, wanted.Properties) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			unmatched = append(unmatched, wanted)
+			continue
+		}
+		remaining[key] = append(candidates[:match], candidates[match+1:]...)
+	}
+	for _, wanted := range unmatched {
+		key := relationshipKey(wanted)
+		if candidates := remaining[key]; len(candidates) > 0 {
+			wanted.ID = candidates[0].ID
+			delta.UpdateRelationships = append(delta.UpdateRelationships, wanted)
+			remaining[key] = candidates[1:]
+			continue
+		}
+		delta.CreateRelationships = append(delta.CreateRelationships, wanted)
+	}
+	for _, rel := range storedRels {
+		for _, left := range remaining[relationshipKey(rel)] {
+			if left.ID == rel.ID {
+				delta.DeleteRelationships = append(delta.DeleteRelationships, rel)
+				break
+			}
+		}
+	}
+	return delta
+}
+
+func elementKey(label string, id any) string {
+	return label + "\x00" + fmt.Sprint(id)
+}
+
+func relationshipKey(rel ports.ExportedRelationship) string {
+	return rel.Type + "\x00" + elementKey(rel.SourceLabel, rel.SourceKey) + "\x00" + elementKey(rel.TargetLabel, rel.TargetKey)
+}
+
+// sameProperties compares stored and transformed properties by their text form, since
+// Neo4j returns numbers and lists with other Go types than the transform produces. Nil
+// values are not stored by Neo4j and are ignored.
+func sameProperties(stored, desired map[string]any) bool {
+	count := 0
+	for key, value := range desired {
+		if value == nil {
+			continue
+		}
+		count++
+		storedValue, ok := stored[key]
+		if !ok || fmt.Sprint(storedValue) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return count == len(stored)
+}
+
+// sameLabels reports whether two nodes carry the same labels, in any order
+func sameLabels(stored, desired []string) bool {
+	return len(missingLabels(stored, desired)) == 0 && len(missingLabels(desired, stored)) == 0
+}
+
+// missingLabels returns the labels of stored that desired does not have
+func missingLabels(stored, desired []string) []string {
+	var missing []string
+	for _, label := range stored {
+		if !slices.Contains(desired, label) {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
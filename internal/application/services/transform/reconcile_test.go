@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reconcilingNeo4jPort keeps a stored graph in memory and applies deltas to it
+type reconcilingNeo4jPort struct {
+	fakeNeo4jPort
+	nodes  []ports.ExportedNode
+	rels   []ports.ExportedRelationship
+	nextID int64
+	deltas []*ports.GraphDelta
+	scope  ports.ReconcileScope
+}
+
+func (p *reconcilingNeo4jPort) ReadManagedGraph(ctx context.Context, scope ports.ReconcileScope) ([]ports.ExportedNode, []ports.ExportedRelationship, error) {
+	p.scope = scope
+	var nodes []ports.ExportedNode
+	for _, node := range p.nodes {
+		if node.Label != scope.AnnotationLabel {
+			nodes = append(nodes, node)
+		}
+	}
+	var rels []ports.ExportedRelationship
+	for _, rel := range p.rels {
+		if rel.SourceLabel != scope.AnnotationLabel && rel.TargetLabel != scope.AnnotationLabel {
+			rels = append(rels, rel)
+		}
+	}
+	return nodes, rels, nil
+}
+
+func (p *reconcilingNeo4jPort) ApplyGraphDelta(ctx context.Context, delta *ports.GraphDelta, onCommit func(ports.GraphWriteProgress)) error {
+	p.deltas = append(p.deltas, delta)
+	for _, deleted := range delta.DeleteRelationships {
+		p.rels = removeRelationship(p.rels, deleted.ID)
+	}
+	for _, deleted := range delta.DeleteNodes {
+		for i, node := range p.nodes {
+			if node.ID == deleted.ID {
+				p.nodes = append(p.nodes[:i], p.nodes[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, node := range delta.CreateNodes {
+		p.nextID++
+		node.ID = p.nextID
+		p.nodes = append(p.nodes, node)
+	}
+	for _, updated := range delta.UpdateNodes {
+		for i := range p.nodes {
+			if p.nodes[i].ID == updated.ID {
+				p.nodes[i].Properties = updated.Properties
+				p.nodes[i].Labels = updated.Labels
+			}
+		}
+	}
+	for _, rel := range delta.CreateRelationships {
+		p.nextID++
+		rel.ID = p.nextID
+		p.rels = append(p.rels, rel)
+	}
+	for _, updated := range delta.UpdateRelationships {
+		for i := range p.rels {
+			if p.rels[i].ID == updated.ID {
+				p.rels[i].Properties = updated.Properties
+			}
+		}
+	}
+	return nil
+}
+
+func removeRelationship(rels []ports.ExportedRelationship, id int64) []ports.ExportedRelationship {
+	for i, rel := range rels {
+		if rel.ID == id {
+			return append(rels[:i], rels[i+1:]...)
+		}
+	}
+	return rels
+}
+
+func newReconcileService(db *fakeDatabasePort, neo4j *reconcilingNeo4jPort) *TransformService {
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(nil),
+	}})
+	service.SetReconcile("")
+	return service
+}
+
+func TestReconcile_UnchangedSourceWritesNothing(t *testing.T) {
+	neo4j := &reconcilingNeo4jPort{}
+	db := newEnrollmentFixture()
+
+	require.NoError(t, newReconcileService(db, neo4j).TransformAndStore(context.Background()))
+	require.Len(t, neo4j.deltas, 1)
+	assert.Len(t, neo4j.deltas[0].CreateNodes, 3)
+	assert.Len(t, neo4j.deltas[0].CreateRelationships, 2)
+	assert.Equal(t, []string{"Course", "Student"}, neo4j.scope.NodeLabels)
+	assert.Equal(t, []string{"ENROLLED_IN"}, neo4j.scope.RelationshipTypes)
+	assert.Equal(t, DefaultAnnotationLabel, neo4j.scope.AnnotationLabel)
+
+	// A restart with the same source data finds nothing to write
+	require.NoError(t, newReconcileService(db, neo4j).TransformAndStore(context.Background()))
+	assert.Len(t, neo4j.deltas, 1, "no delta is applied when the graph is up to date")
+}
+
+func TestReconcile_ChangedSourceWritesOnlyTheDelta(t *testing.T) {
+	neo4j := &reconcilingNeo4jPort{}
+	require.NoError(t, newReconcileService(newEnrollmentFixture(), neo4j).TransformAndStore(context.Background()))
+
+	// A manual annotation linked to a student survives reconciling
+	neo4j.nodes = append(neo4j.nodes, ports.ExportedNode{ID: 100, Label: DefaultAnnotationLabel, Properties: map[string]any{"note": "star pupil"}})
+	neo4j.rels = append(neo4j.rels, ports.ExportedRelationship{ID: 101, Type: "ANNOTATES", SourceLabel: DefaultAnnotationLabel, SourceKey: nil, TargetLabel: "Student", TargetKey: "1"})
+
+	// Ada is renamed, Linus leaves, Grace enrolls and Ada's grade changes
+	changed := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "students", "id": int64(1), "name": "Ada Lovelace"},
+		{"_table": "students", "id": int64(3), "name": "Grace"},
+		{"_table": "courses", "id": int64(10), "name": "Databases"},
+		{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "A+", "enrolled_at": "2025-09-01"},
+		{"_table": "enrollments", "student_id": int64(3), "course_id": int64(10), "grade": "B", "enrolled_at": "2025-09-05"},
+	}}
+	require.NoError(t, newReconcileService(changed, neo4j).TransformAndStore(context.Background()))
+	require.Len(t, neo4j.deltas, 2)
+	delta := neo4j.deltas[1]
+
+	require.Len(t, delta.CreateNodes, 1)
+	assert.Equal(t, "Grace", delta.CreateNodes[0].Properties["name"])
+	require.Len(t, delta.UpdateNodes, 1)
+	assert.Equal(t, "Ada Lovelace", delta.UpdateNodes[0].Properties["name"])
+	require.Len(t, delta.DeleteNodes, 1)
+	assert.Equal(t, "Linus", delta.DeleteNodes[0].Properties["name"])
+
+	require.Len(t, delta.CreateRelationships, 1)
+	assert.Equal(t, "3", delta.CreateRelationships[0].SourceKey)
+	require.Len(t, delta.UpdateRelationships, 1)
+	assert.Equal(t, "A+", delta.UpdateRelationships[0].Properties["grade"])
+	require.Len(t, delta.DeleteRelationships, 1)
+	assert.Equal(t, "2", delta.DeleteRelationships[0].SourceKey)
+
+	for _, deleted := range delta.DeleteNodes {
+		assert.NotEqual(t, DefaultAnnotationLabel, deleted.Label)
+	}
+	assert.Contains(t, neo4j.nodes, ports.ExportedNode{ID: 100, Label: DefaultAnnotationLabel, Properties: map[string]any{"note": "star pupil"}})
+}
+
+func TestReconcile_ComparesLabelSets(t *testing.T) {
+	neo4j := &reconcilingNeo4jPort{}
+	service := newReconcileService(newEnrollmentFixture(), neo4j)
+	rules := service.ruleRepo.(*fakeRuleRepository).rules
+	rules[0].Rule.Labels = []string{"Person", "Alumni"}
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.Len(t, neo4j.deltas, 1)
+	for _, node := range neo4j.deltas[0].CreateNodes {
+		if node.Label == "Student" {
+			assert.ElementsMatch(t, []string{"Student", "Person", "Alumni"}, node.Labels)
+		}
+	}
+
+	// The same labels in another order are no change
+	rules[0].Rule.Labels = []string{"Alumni", "Person"}
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.Len(t, neo4j.deltas, 1)
+
+	// Students stop being alumni: every student is updated and loses the label
+	rules[0].Rule.Labels = []string{"Person"}
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.Len(t, neo4j.deltas, 2)
+	delta := neo4j.deltas[1]
+	require.Len(t, delta.UpdateNodes, 2)
+	for _, node := range delta.UpdateNodes {
+		assert.ElementsMatch(t, []string{"Student", "Person"}, node.Labels)
+		assert.Equal(t, []string{"Alumni"}, delta.RemoveLabels[node.ID])
+	}
+	assert.Empty(t, delta.CreateNodes)
+	assert.Empty(t, delta.DeleteNodes)
+}
+
+func TestSameProperties(t *testing.T) {
+	assert.True(t, sameProperties(
+		map[string]any{"id": "1", "tags": []any{"a", "b"}, "count": int64(3)},
+		map[string]any{"id": "1", "tags": []string{"a", "b"}, "count": 3, "deleted_at": nil},
+	), "Neo4j value types and NULL properties do not count as changes")
+	assert.False(t, sameProperties(map[string]any{"id": "1", "legacy": "x"}, map[string]any{"id": "1"}))
+	assert.False(t, sameProperties(map[string]any{"id": "1"}, map[string]any{"id": "2"}))
+}
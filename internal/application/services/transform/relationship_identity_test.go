@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	assignmentsQuery = "SELECT user_id, project_id, role FROM assignments"
+	ownersQuery      = "SELECT owner_id AS user_id, id AS project_id FROM projects"
+)
+
+// newWorksOnFixture has two rules producing WORKS_ON relationships: assignments link users
+// to projects with a role, and project owners link the owner to the project. User 1 is both
+// assigned to and owner of project 10.
+func newWorksOnFixture(identity *transform.RelationshipIdentity) (*fakeDatabasePort, *fakeRuleRepository) {
+	db := &fakeDatabasePort{
+		rows: []map[string]any{
+			{"_table": "users", "id": int64(1), "name": "Ada"},
+			{"_table": "users", "id": int64(2), "name": "Grace"},
+			{"_table": "projects", "id": int64(10), "name": "Engine"},
+			{"_table": "projects", "id": int64(20), "name": "Compiler"},
+		},
+		queries: map[string][]map[string]any{
+			assignmentsQuery: {
+				{"user_id": int64(1), "project_id": int64(10), "role": "lead"},
+				{"user_id": int64(1), "project_id": int64(10), "role": "reviewer"},
+				{"user_id": int64(2), "project_id": int64(20), "role": "dev"},
+			},
+			ownersQuery: {
+				{"user_id": int64(1), "project_id": int64(10)},
+				{"user_id": int64(2), "project_id": int64(20)},
+			},
+		},
+	}
+
+	worksOn := func(name, query string, properties map[string]string) *transform_agg.RuleAggregate {
+		return &transform_agg.RuleAggregate{Name: name, Rule: transform.TransformRule{
+			Name:                 name,
+			SourceSQL:            query,
+			RuleType:             transform.RelationshipRule,
+			RelationType:         "WORKS_ON",
+			Direction:            transform.Outgoing,
+			SourceNode:           &transform.NodeMapping{Type: "User", Key: "user_id", TargetField: "id"},
+			TargetNode:           &transform.NodeMapping{Type: "Project", Key: "project_id", TargetField: "id"},
+			Properties:           properties,
+			RelationshipIdentity: identity,
+		}}
+	}
+
+	return db, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("users", "users", "User"),
+		nodeRule("projects", "projects", "Project"),
+		worksOn("assignments", assignmentsQuery, map[string]string{"role": "role"}),
+		worksOn("owners", ownersQuery, nil),
+	}}
+}
+
+// storedWorksOn maps the user and project ids of each stored WORKS_ON relationship to how
+// often the pair was stored
+func storedWorksOn(t *testing.T, identity *transform.RelationshipIdentity) (map[[2]any]int, *fakeNeo4jPort) {
+	t.Helper()
+	db, rules := newWorksOnFixture(identity)
+	neo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(db, neo4j, rules).TransformAndStore(context.Background()))
+
+	pairs := make(map[[2]any]int)
+	for _, rel := range neo4j.stored.GetRelationships() {
+		pairs[[2]any{rel.SourceNode.Properties["id"], rel.TargetNode.Properties["id"]}]++
+	}
+	return pairs, neo4j
+}
+
+func TestRelationshipIdentityMergesEdgesAcrossRules(t *testing.T) {
+	pairs, neo4j := storedWorksOn(t, &transform.RelationshipIdentity{})
+
+	assert.Equal(t, map[[2]any]int{{"1", "10"}: 1, {"2", "20"}: 1}, pairs,
+		"rows and rules producing the same edge store it once")
+	for _, rel := range neo4j.stored.GetRelationships() {
+		assert.True(t, rel.Merged, "merged relationships are merged in the store too")
+		assert.Empty(t, rel.MergeKeys)
+	}
+}
+
+func TestRelationshipIdentityDiscriminatorKeepsEdgesApart(t *testing.T) {
+	pairs, neo4j := storedWorksOn(t, &transform.RelationshipIdentity{Discriminator: "role"})
+
+	assert.Equal(t, 3, pairs[[2]any{"1", "10"}], "lead, reviewer and the owner edge without a role")
+	assert.Equal(t, 2, pairs[[2]any{"2", "20"}], "dev and the owner edge without a role")
+
+	roles := make(map[any]bool)
+	for _, rel := range neo4j.stored.GetRelationships() {
+		roles[rel.MergeKeys["role"]] = true
+	}
+	assert.Equal(t, map[any]bool{"lead": true, "reviewer": true, "dev": true, nil: true}, roles)
+}
+
+func TestRelationshipsWithoutIdentityAreNotMerged(t *testing.T) {
+	pairs, _ := storedWorksOn(t, nil)
+
+	assert.Equal(t, 3, pairs[[2]any{"1", "10"}])
+	assert.Equal(t, 2, pairs[[2]any{"2", "20"}])
+}
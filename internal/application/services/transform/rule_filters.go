@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+// readsSource reports whether a rule reads its rows with a query of its own: rules with a
+// source query, and rules whose source table is filtered
+func readsSource(rule transform.TransformRule) bool {
+	return rule.SourceSQL != "" || len(rule.Filters) > 0
+}
+
+// ruleSource returns the query reading a rule's rows, with the rule's filters applied, and
+// the parameter and filter values to pass with it
+func (s *TransformService) ruleSource(rule transform.TransformRule) (string, []any, error) {
+	query, args, err := s.sourceQuery(rule)
+	if err != nil {
+		return "", nil, err
+	}
+	return s.filteredSource(rule, query, args)
+}
+
+// filteredSource applies a rule's filters to its bound source query, which takes args, or
+// to its source table when query is empty
+func (s *TransformService) filteredSource(rule transform.TransformRule, query string, args []any) (string, []any, error) {
+	if len(rule.Filters) == 0 {
+		return query, args, nil
+	}
+	executor, ok := s.databasePort.(ports.ParameterizedQueryExecutor)
+	if !ok {
+		return "", nil, fmt.Errorf("filters need a database that accepts query arguments")
+	}
+	return rule.FilteredQuery(query, args, executor.QueryPlaceholder)
+}
+
+// executeQueryWithArgs runs a filtered or parameterized source query, passing its values
+// to the database as query arguments
+func (s *TransformService) executeQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	executor, ok := s.databasePort.(ports.ParameterizedQueryExecutor)
+	if !ok {
+		return nil, fmt.Errorf("query arguments need a database that accepts them")
+	}
+	return executor.ExecuteQueryWithArgs(ctx, query, args)
+}
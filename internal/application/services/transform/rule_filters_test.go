@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parameterizedDatabasePort serves filtered reads of its tables, and previews of them, the
+// way a database binding arguments would: every "column = ?" condition compares the column
+// with the next argument as a value, whatever the argument contains
+type parameterizedDatabasePort struct {
+	fakeDatabasePort
+	tables  map[string][]map[string]any
+	queries []string
+	args    [][]any
+}
+
+var (
+	filteredTablePattern = regexp.MustCompile(`^(?:SELECT \* FROM \()?SELECT \* FROM (\w+) WHERE (.+?)(?:\) AS rule_preview LIMIT \d+)?$`)
+	equalsPattern        = regexp.MustCompile(`^(\w+) = \?$`)
+)
+
+func (f *parameterizedDatabasePort) QueryPlaceholder(int) string { return "?" }
+
+func (f *parameterizedDatabasePort) ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	f.queries = append(f.queries, query)
+	f.args = append(f.args, args)
+
+	match := filteredTablePattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	var columns []string
+	for _, condition := range regexp.MustCompile(` AND `).Split(match[2], -1) {
+		equals := equalsPattern.FindStringSubmatch(condition)
+		if equals == nil {
+			return nil, fmt.Errorf("unsupported condition: %s", condition)
+		}
+		columns = append(columns, equals[1])
+	}
+	if len(columns) != len(args) {
+		return nil, fmt.Errorf("query has %d placeholders for %d arguments", len(columns), len(args))
+	}
+
+	var rows []map[string]any
+	for _, row := range f.tables[match[1]] {
+		matches := true
+		for i, column := range columns {
+			matches = matches && row[column] == args[i]
+		}
+		if matches {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func newFilterFixture(value any) (*parameterizedDatabasePort, *fakeRuleRepository) {
+	db := &parameterizedDatabasePort{tables: map[string][]map[string]any{
+		"users": {
+			{"id": int64(1), "name": "Ada", "status": "active"},
+			{"id": int64(2), "name": "Brian", "status": "disabled"},
+			{"id": int64(3), "name": "Cleo", "status": "active"},
+		},
+	}}
+	users := nodeRule("users", "users", "User")
+	users.Rule.Filters = []transform.RuleFilter{{Column: "status", Operator: "=", Value: value}}
+	return db, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{users}}
+}
+
+func TestRuleFiltersRestrictImportedRows(t *testing.T) {
+	db, rules := newFilterFixture("active")
+	neo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(db, neo4j, rules).TransformAndStore(context.Background()))
+
+	var names []any
+	for _, node := range neo4j.stored.GetNodes() {
+		names = append(names, node.Properties["name"])
+	}
+	assert.ElementsMatch(t, []any{"Ada", "Cleo"}, names)
+	assert.Equal(t, []string{"SELECT * FROM users WHERE status = ?"}, db.queries)
+}
+
+func TestRuleFiltersParameterizeInjectionAttempt(t *testing.T) {
+	injection := "active' OR '1'='1"
+	db, rules := newFilterFixture(injection)
+	neo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(db, neo4j, rules).TransformAndStore(context.Background()))
+
+	assert.Empty(t, neo4j.stored.GetNodes(), "the value is compared as a whole, not run as SQL")
+	require.Len(t, db.queries, 1)
+	assert.NotContains(t, db.queries[0], injection)
+	assert.Equal(t, []any{injection}, db.args[0])
+}
+
+func TestRuleFiltersNeedParameterizedPort(t *testing.T) {
+	users := nodeRule("users", "users", "User")
+	users.Rule.Filters = []transform.RuleFilter{{Column: "status", Operator: "=", Value: "active"}}
+	service := NewTransformService(&fakeDatabasePort{}, &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{users}})
+
+	err := service.TransformAndStore(context.Background())
+	assert.ErrorContains(t, err, "filters need a database that accepts query arguments")
+}
+
+func TestPreviewRuleAppliesFilters(t *testing.T) {
+	db, rules := newFilterFixture("active")
+	service := NewTransformService(db, &fakeNeo4jPort{}, rules)
+
+	preview, err := service.PreviewRule(context.Background(), "users", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, preview.SampledRows)
+	require.Len(t, db.queries, 1)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM users WHERE status = ?) AS rule_preview LIMIT 10", db.queries[0])
+	assert.Equal(t, []any{"active"}, db.args[0])
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+var (
+	// ErrRuleNotFound is returned when previewing a rule that is not loaded
+	ErrRuleNotFound = errors.New("rule not found")
+	// ErrRuleNotPreviewable is returned for rules that only link nodes already in the graph
+	ErrRuleNotPreviewable = errors.New("rule has no source rows to preview")
+)
+
+// previewTablePattern matches table names that are safe to splice into the sample query
+var previewTablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// RulePreview is what one rule produces from a sample of its source rows
+type RulePreview struct {
+	Rule          string                `json:"rule"`
+	RuleType      string                `json:"rule_type"`
+	Query         string                `json:"query"`
+	SampledRows   int                   `json:"sampled_rows"`
+	Nodes         []PreviewNode         `json:"nodes"`
+	Relationships []PreviewRelationship `json:"relationships"`
+	// Warnings lists the rows the transform would skip, with the reason
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PreviewNode is a node a rule would write
+type PreviewNode struct {
+	Type       string         `json:"type"`
+	ID         any            `json:"id"`
+	Properties map[string]any `json:"properties"`
+	MergeKeys  map[string]any `json:"merge_keys,omitempty"`
+}
+
+// PreviewRelationship is a relationship a rule would write, with the keys its endpoints
+// are matched on
+type PreviewRelationship struct {
+	Type       string          `json:"type"`
+	Direction  string          `json:"direction"`
+	Source     PreviewEndpoint `json:"source"`
+	Target     PreviewEndpoint `json:"target"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+// PreviewEndpoint identifies a relationship endpoint by node type and key
+type PreviewEndpoint struct {
+	Type  string `json:"type"`
+	Field string `json:"field"`
+	Key   any    `json:"key"`
+}
+
+// PreviewRule runs a single rule against at most limit source rows and returns the nodes
+// or relationships it would produce. Nothing is written to Neo4j.
+func (s *TransformService) PreviewRule(ctx context.Context, name string, limit int) (*RulePreview, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("preview limit must be positive, got %d", limit)
+	}
+
+	rules, err := s.ruleRepo.GetAllRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+	var rule *transform_agg.RuleAggregate
+	for _, candidate := range rules {
+		if candidate.ID == name || candidate.Name == name || candidate.Rule.Name == name {
+			rule = candidate
+			break
+		}
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, name)
+	}
+
+	query, args, err := s.previewQuery(rule, limit)
+	if err != nil {
+		return nil, err
+	}
+	var items []map[string]any
+	if len(args) > 0 {
+		items, err = s.executeQueryWithArgs(ctx, query, args)
+	} else {
+		items, err = s.executeQuery(ctx, query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample rule %s: %w", rule.Rule.Name, err)
+	}
+	items = s.excludeSoftDeleted(items)
+	for i, item := range items {
+		items[i] = s.convertMapProperties(item)
+	}
+
+	preview := &RulePreview{
+		Rule:          rule.Rule.Name,
+		RuleType:      string(rule.Rule.RuleType),
+		Query:         query,
+		SampledRows:   len(items),
+		Nodes:         make([]PreviewNode, 0),
+		Relationships: make([]PreviewRelationship, 0),
+	}
+
+	transformed := rule.ApplyRules(items)
+	if rule.Rule.RuleType == transform.NodeRule {
+		s.previewNodes(preview, transformed)
+	} else {
+		previewRelationships(preview, transformed)
+	}
+	return preview, nil
+}
+
+// previewQuery wraps the rule's source in a query returning at most limit rows, and returns
+// the values of the rule's parameters and filters to pass with it
+func (s *TransformService) previewQuery(rule *transform_agg.RuleAggregate, limit int) (string, []any, error) {
+	query := ""
+	var args []any
+	if rule.Rule.SourceSQL != "" {
+		var err error
+		if query, args, err = s.sourceQueryAt(rule.Rule, time.Now()); err != nil {
+			return "", nil, fmt.Errorf("invalid SQL query for rule %s: %w", rule.Rule.Name, err)
+		}
+	}
+	if len(rule.Rule.Filters) > 0 {
+		filtered, args, err := s.filteredSource(rule.Rule, query, args)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid filters for rule %s: %w", rule.Rule.Name, err)
+		}
+		return fmt.Sprintf("SELECT * FROM (%s) AS rule_preview LIMIT %d", filtered, limit), args, nil
+	}
+	if query != "" {
+		query = strings.TrimRight(strings.TrimSpace(query), ";")
+		return fmt.Sprintf("SELECT * FROM (%s) AS rule_preview LIMIT %d", query, limit), args, nil
+	}
+
+	// Relationship rules without a query or junction table link nodes created by other rules
+	if rule.Rule.RuleType != transform.NodeRule && !rule.IsJunctionRule() {
+		return "", nil, fmt.Errorf("%w: %s links existing nodes", ErrRuleNotPreviewable, rule.Rule.Name)
+	}
+	if !previewTablePattern.MatchString(rule.Rule.SourceTable) {
+		return "", nil, fmt.Errorf("%w: invalid source table %q", ErrRuleNotPreviewable, rule.Rule.SourceTable)
+	}
+	return fmt.Sprintf("SELECT * FROM %s LIMIT %d", rule.Rule.SourceTable, limit), nil, nil
+}
+
+// previewNodes adds transformed rows to an empty graph the same way a run does, so ids,
+// names and merge keys match what would be stored
+func (s *TransformService) previewNodes(preview *RulePreview, transformed []any) {
+	graphAggregate := graph.NewGraphAggregate("")
+	for i, item := range transformed {
+		mapItem, ok := item.(map[string]any)
+		if !ok {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("row %d: unexpected data format %T", i+1, item))
+			continue
+		}
+		if err := s.updateGraph(s.convertMapProperties(mapItem), graphAggregate); err != nil {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("row %d: %v", i+1, err))
+		}
+	}
+
+	for _, node := range graphAggregate.GetNodes() {
+		preview.Nodes = append(preview.Nodes, PreviewNode{
+			Type:       node.Type,
+			ID:         node.Key,
+			Properties: node.Properties,
+			MergeKeys:  node.MergeKeys,
+		})
+	}
+	sort.SliceStable(preview.Nodes, func(i, j int) bool {
+		return fmt.Sprint(preview.Nodes[i].ID) < fmt.Sprint(preview.Nodes[j].ID)
+	})
+}
+
+// previewRelationships reads the transformed rows without resolving their endpoints, which
+// belong to other rules
+func previewRelationships(preview *RulePreview, transformed []any) {
+	for i, item := range transformed {
+		relationship, err := previewRelationship(item)
+		if err != nil {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf("row %d: %v", i+1, err))
+			continue
+		}
+		preview.Relationships = append(preview.Relationships, relationship)
+	}
+}
+
+func previewRelationship(item any) (PreviewRelationship, error) {
+	data, ok := item.(map[string]any)
+	if !ok {
+		return PreviewRelationship{}, fmt.Errorf("unexpected data format %T", item)
+	}
+	relType, ok := data["_type"].(string)
+	if !ok {
+		return PreviewRelationship{}, fmt.Errorf("relationship missing _type field")
+	}
+	direction, _ := data["_direction"].(transform.Direction)
+
+	endpoints := make([]PreviewEndpoint, 2)
+	for i, field := range []string{"source", "target"} {
+		endpoint, err := relationshipField(data, field)
+		if err != nil {
+			return PreviewRelationship{}, err
+		}
+		if endpoint == nil {
+			return PreviewRelationship{}, fmt.Errorf("relationship missing %s field", field)
+		}
+		endpoints[i].Type, _ = endpoint["type"].(string)
+		endpoints[i].Field, _ = endpoint["field"].(string)
+		endpoints[i].Key = endpoint["key"]
+	}
+
+	properties, err := relationshipField(data, "properties")
+	if err != nil {
+		return PreviewRelationship{}, err
+	}
+	return PreviewRelationship{
+		Type:       relType,
+		Direction:  direction.String(),
+		Source:     endpoints[0],
+		Target:     endpoints[1],
+		Properties: properties,
+	}, nil
+}
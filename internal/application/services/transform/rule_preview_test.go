@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPreviewFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{queries: map[string][]map[string]any{
+		"SELECT * FROM students LIMIT 2": {
+			{"id": int64(1), "name": "Ada", "email": "ada@example.com"},
+			{"id": int64(2), "name": "Linus", "email": "linus@example.com"},
+		},
+		"SELECT * FROM enrollments LIMIT 10": {
+			{"student_id": int64(1), "course_id": int64(10), "grade": "A"},
+		},
+		"SELECT * FROM (SELECT id, title AS name FROM courses WHERE active = 1) AS rule_preview LIMIT 5": {
+			{"id": int64(10), "name": "Databases"},
+		},
+	}}
+}
+
+func TestPreviewRule_NodeRuleFromTable(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+	}}
+	service := NewTransformService(newPreviewFixture(), neo4j, rules)
+
+	preview, err := service.PreviewRule(context.Background(), "students", 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "students", preview.Rule)
+	assert.Equal(t, "node", preview.RuleType)
+	assert.Equal(t, 2, preview.SampledRows)
+	assert.Empty(t, preview.Relationships)
+	assert.Empty(t, preview.Warnings)
+	require.Len(t, preview.Nodes, 2)
+	assert.Equal(t, PreviewNode{Type: "Student", ID: "1", Properties: map[string]any{"id": "1", "name": "Ada"}}, preview.Nodes[0])
+	assert.Equal(t, "Linus", preview.Nodes[1].Properties["name"])
+	assert.NotContains(t, preview.Nodes[0].Properties, "email", "only mapped fields are kept")
+
+	assert.Nil(t, neo4j.stored, "a preview never writes to Neo4j")
+}
+
+func TestPreviewRule_CustomQueryIsLimited(t *testing.T) {
+	rule := nodeRule("active_courses", "", "Course")
+	rule.Rule.SourceSQL = "SELECT id, title AS name FROM courses WHERE active = 1;"
+	service := NewTransformService(newPreviewFixture(), &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+
+	preview, err := service.PreviewRule(context.Background(), "active_courses", 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT * FROM (SELECT id, title AS name FROM courses WHERE active = 1) AS rule_preview LIMIT 5", preview.Query)
+	require.Len(t, preview.Nodes, 1)
+	assert.Equal(t, "Databases", preview.Nodes[0].Properties["name"])
+}
+
+func TestPreviewRule_JunctionRuleReportsEndpoints(t *testing.T) {
+	service := NewTransformService(newPreviewFixture(), &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		enrollmentRule(map[string]string{"grade": "final_grade"}),
+	}})
+
+	preview, err := service.PreviewRule(context.Background(), "enrollments", 10)
+	require.NoError(t, err)
+
+	assert.Empty(t, preview.Nodes)
+	require.Len(t, preview.Relationships, 1)
+	relationship := preview.Relationships[0]
+	assert.Equal(t, "ENROLLED_IN", relationship.Type)
+	assert.Equal(t, "OUTGOING", relationship.Direction)
+	assert.Equal(t, PreviewEndpoint{Type: "Student", Field: "id", Key: int64(1)}, relationship.Source)
+	assert.Equal(t, PreviewEndpoint{Type: "Course", Field: "id", Key: int64(10)}, relationship.Target)
+	assert.Equal(t, map[string]any{"final_grade": "A"}, relationship.Properties)
+}
+
+func TestPreviewRule_Errors(t *testing.T) {
+	linkRule := &transform_agg.RuleAggregate{
+		Name: "student_courses",
+		Rule: transform.TransformRule{
+			Name:         "student_courses",
+			RuleType:     transform.RelationshipRule,
+			RelationType: "TAKES",
+			SourceNode:   &transform.NodeMapping{Type: "Student", Key: "course_id", TargetField: "id"},
+			TargetNode:   &transform.NodeMapping{Type: "Course", Key: "id", TargetField: "id"},
+		},
+	}
+	service := NewTransformService(newPreviewFixture(), &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{linkRule}})
+
+	_, err := service.PreviewRule(context.Background(), "missing", 10)
+	assert.ErrorIs(t, err, ErrRuleNotFound)
+
+	_, err = service.PreviewRule(context.Background(), "student_courses", 10)
+	assert.ErrorIs(t, err, ErrRuleNotPreviewable)
+
+	_, err = service.PreviewRule(context.Background(), "student_courses", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// recordSnapshot hands the stored graph of this run to the snapshot recorder
+func (s *TransformService) recordSnapshot(graphAggregate *graph.GraphAggregate, rows []map[string]any, rules []*transform_agg.RuleAggregate) {
+	metadata := s.snapshotMetadata(rows, rules)
+	s.snapshots.RecordSnapshot(graphAggregate, metadata)
+	logSnapshot(metadata)
+}
+
+// recordSnapshotCounts hands the counts of a run that stored its graph batch by batch to
+// the snapshot recorder
+func (s *TransformService) recordSnapshotCounts(counts ports.GraphSnapshotCounts, rows []map[string]any, rules []*transform_agg.RuleAggregate) {
+	metadata := s.snapshotMetadata(rows, rules)
+	s.snapshots.RecordCounts(counts, metadata)
+	logSnapshot(metadata)
+}
+
+func (s *TransformService) snapshotMetadata(rows []map[string]any, rules []*transform_agg.RuleAggregate) ports.GraphSnapshotMetadata {
+	s.runMutex.Lock()
+	startedAt := s.progress.StartedAt
+	s.runMutex.Unlock()
+
+	return ports.GraphSnapshotMetadata{
+		RunStartedAt:      startedAt,
+		SourceFingerprint: sourceFingerprint(rows),
+		RuleSetVersion:    ruleSetVersion(rules),
+	}
+}
+
+func logSnapshot(metadata ports.GraphSnapshotMetadata) {
+	logrus.WithFields(logrus.Fields{
+		"source_fingerprint": metadata.SourceFingerprint,
+		"rule_set_version":   metadata.RuleSetVersion,
+	}).Info("Recorded graph snapshot")
+}
+
+// sourceFingerprint hashes the source rows in the order they were read; encoding/json sorts
+// map keys, so equal rows always hash the same
+func sourceFingerprint(rows []map[string]any) string {
+	hash := sha256.New()
+	for _, row := range rows {
+		writeHashed(hash, row)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// ruleSetVersion hashes the definitions of the rules in order
+func ruleSetVersion(rules []*transform_agg.RuleAggregate) string {
+	hash := sha256.New()
+	for _, rule := range rules {
+		writeHashed(hash, rule.Rule)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func writeHashed(hash hash.Hash, value any) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded = fmt.Appendf(nil, "%+v", value)
+	}
+	hash.Write(encoded)
+	hash.Write([]byte{'\n'})
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	graphservice "sql-graph-visualizer/internal/application/services/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformAndStore_RecordsSnapshotOfEachRun(t *testing.T) {
+	snapshots := graphservice.NewGraphSnapshotStore(0)
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(map[string]string{"grade": "grade"}),
+	}}
+	db := newEnrollmentFixture()
+
+	service := NewTransformService(db, &fakeNeo4jPort{}, rules)
+	service.SetSnapshotRecorder(snapshots)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	// Ada's grade changes before the next run
+	db.rows[3] = map[string]any{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "A+", "enrolled_at": "2025-09-01"}
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	list := snapshots.List()
+	require.Len(t, list, 2)
+	first, second := list[0], list[1]
+	assert.Equal(t, 3, first.NodeCount)
+	assert.Equal(t, 2, first.RelationshipCount)
+	assert.Equal(t, map[string]int{"Student": 2, "Course": 1}, first.NodesByLabel)
+	assert.Equal(t, map[string]int{"ENROLLED_IN": 2}, first.RelationshipsByType)
+	assert.False(t, first.RunStartedAt.IsZero())
+	assert.NotEmpty(t, first.RuleSetVersion)
+
+	assert.Equal(t, first.RuleSetVersion, second.RuleSetVersion, "the rules did not change")
+	assert.NotEqual(t, first.SourceFingerprint, second.SourceFingerprint, "the source rows changed")
+	assert.NotEqual(t, first.Version, second.Version)
+
+	diff, err := snapshots.Diff(first.ID, second.ID)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Changes.AddedNodes)
+	assert.Empty(t, diff.Changes.ChangedNodes)
+	require.Len(t, diff.Changes.ChangedRelationships, 1)
+	assert.Equal(t, "A+", diff.Changes.ChangedRelationships[0].Properties["grade"])
+}
+
+func TestTransformAndStore_FailedRunRecordsNoSnapshot(t *testing.T) {
+	snapshots := graphservice.NewGraphSnapshotStore(0)
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("students", "students", "Student")}}
+
+	service := NewTransformService(newEnrollmentFixture(), &fakeNeo4jPort{}, rules)
+	service.SetSnapshotRecorder(snapshots)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, service.TransformAndStore(ctx))
+	assert.Empty(t, snapshots.List())
+}
+
+func TestTransformAndStore_StreamedRunRecordsSnapshotCounts(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	snapshots := graphservice.NewGraphSnapshotStore(0)
+	db := &streamingDatabasePort{fakeDatabasePort: teamRows(), rows: 2500}
+	service := NewTransformService(db, &batchedNeo4jPort{ids: make(map[string]bool)}, playerRules())
+	service.SetStreamBatchSize(128)
+	service.SetSnapshotRecorder(snapshots)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	list := snapshots.List()
+	require.Len(t, list, 2, "streamed runs record a snapshot too")
+	first := list[0]
+	assert.True(t, first.CountsOnly)
+	assert.Equal(t, 2507, first.NodeCount)
+	assert.Equal(t, 2500, first.RelationshipCount)
+	assert.NotEmpty(t, first.RuleSetVersion)
+	assert.False(t, first.RunStartedAt.IsZero())
+
+	_, err := snapshots.Diff(first.ID, list[1].ID)
+	assert.ErrorIs(t, err, graphservice.ErrSnapshotCountsOnly, "a streamed run keeps no graph to compare")
+}
@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetStreamBatchSize makes rules read their source queries batchSize rows at a time instead
+// of loading whole results, so large tables do not have to fit in memory. Ports that stream
+// rows (ports.RowStreamer, and ports.ParameterizedRowStreamer for queries with arguments)
+// read each query once; others are paged by the rule's key column.
+// A batchSize of 0 reads whole results.
+func (s *TransformService) SetStreamBatchSize(batchSize int) {
+	s.streamBatchSize = batchSize
+}
+
+// forEachSourceBatch reads the rows of a rule's source query, run with args, and passes them
+// to fn, in batches of at most streamBatchSize rows when streaming is enabled. fn may modify
+// the rows.
+func (s *TransformService) forEachSourceBatch(ctx context.Context, rule transform.TransformRule, query string, args []any, fn func(items []map[string]any) error) error {
+	if s.streamBatchSize <= 0 {
+		items, err := s.querySource(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		return fn(items)
+	}
+
+	cache := s.lookups
+	if cache != nil {
+		if rows, ok := cache.get(query, args); ok {
+			return fn(rows)
+		}
+	}
+
+	if stream, ok := s.rowStream(query, args); ok {
+		return s.streamBatches(ctx, stream, query, args, cache, fn)
+	}
+
+	key, ok := rule.StreamKey()
+	if !ok {
+		logrus.Warnf("Rule %s has no single key column to page its query by; reading all rows at once", rule.Name)
+		items, err := s.runQuery(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		return fn(items)
+	}
+	return s.pageBatches(ctx, query, args, key, fn)
+}
+
+// rowStream returns a function streaming the rows of query with args, when the database
+// port can stream them
+func (s *TransformService) rowStream(query string, args []any) (func(ctx context.Context, fn func(row map[string]any) error) error, bool) {
+	if len(args) == 0 {
+		if streamer, ok := s.databasePort.(ports.RowStreamer); ok {
+			return func(ctx context.Context, fn func(row map[string]any) error) error {
+				return streamer.StreamQuery(ctx, query, fn)
+			}, true
+		}
+	}
+	if streamer, ok := s.databasePort.(ports.ParameterizedRowStreamer); ok {
+		return func(ctx context.Context, fn func(row map[string]any) error) error {
+			return streamer.StreamQueryWithArgs(ctx, query, args, fn)
+		}, true
+	}
+	return nil, false
+}
+
+// runQuery runs query, passing args to the database when there are any
+func (s *TransformService) runQuery(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	if len(args) > 0 {
+		return s.executeQueryWithArgs(ctx, query, args)
+	}
+	return s.executeQuery(ctx, query)
+}
+
+// streamBatches reads the rows of query with args through stream, holding at most one batch
+// of rows at a time. Results small enough for the lookup cache are cached on the way.
+func (s *TransformService) streamBatches(ctx context.Context, stream func(ctx context.Context, fn func(row map[string]any) error) error, query string, args []any, cache *lookupCache, fn func(items []map[string]any) error) error {
+	batch := make([]map[string]any, 0, s.streamBatchSize)
+	var cached []map[string]any
+	caching := cache != nil
+
+	err := stream(ctx, func(row map[string]any) error {
+		if caching {
+			if len(cached) < cache.maxRows {
+				cached = append(cached, row)
+			} else {
+				cached, caching = nil, false
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) < s.streamBatchSize {
+			return nil
+		}
+		ready := batch
+		if caching {
+			// The cache keeps these rows, which fn may change
+			ready = copyRows(batch)
+		}
+		if err := fn(ready); err != nil {
+			return err
+		}
+		batch = make([]map[string]any, 0, s.streamBatchSize)
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+	if caching {
+		cache.put(query, args, cached)
+		batch = copyRows(batch)
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// pageBatches reads query a batch at a time in key order, each page starting after the last
+// key of the previous one, for ports that cannot stream rows. With args, or a port taking
+// query arguments, the last key is passed as an argument.
+func (s *TransformService) pageBatches(ctx context.Context, query string, args []any, key string, fn func(items []map[string]any) error) error {
+	executor, parameterized := s.databasePort.(ports.ParameterizedQueryExecutor)
+	var after any
+	for {
+		var page string
+		var pageArgs []any
+		var err error
+		if parameterized {
+			page, pageArgs, err = transform.KeysetPageQueryWithArgs(query, args, key, after, s.streamBatchSize, executor.QueryPlaceholder)
+		} else {
+			page, err = transform.KeysetPageQuery(query, key, after, s.streamBatchSize)
+		}
+		if err != nil {
+			return err
+		}
+		items, err := s.runQuery(ctx, page, pageArgs)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		last := items[len(items)-1][key]
+		if err := fn(items); err != nil {
+			return err
+		}
+		if len(items) < s.streamBatchSize {
+			return nil
+		}
+		if last == nil {
+			return fmt.Errorf("cannot page by key column %s: the query returned rows without it", key)
+		}
+		after = last
+	}
+}
+
+// batchFlusher writes the graph of a streamed run one source batch at a time, so the run
+// holds no more than a batch of nodes and relationships. Nodes are merged on their id, as
+// the same node may come from several batches; relationships match their endpoints among
+// the nodes already written. It counts what it stores by label and type, for the run's
+// snapshot.
+type batchFlusher struct {
+	service       *TransformService
+	nodes         ports.BatchedGraphStore
+	relationships ports.RelationshipGraphStore
+
+	mutex   sync.Mutex
+	written ports.GraphWriteProgress
+	stored  ports.GraphSnapshotCounts
+}
+
+// batchFlusher returns the flusher for a run of rules, or nil when the run has to build the
+// whole graph before storing it: streaming is off, the Neo4j port cannot write batches, or
+// a feature or rule needs every node and relationship at once
+func (s *TransformService) batchFlusher(rules []*transform_agg.RuleAggregate) *batchFlusher {
+	if s.streamBatchSize <= 0 {
+		return nil
+	}
+	nodes, ok := s.neo4jPort.(ports.BatchedGraphStore)
+	if !ok {
+		return nil
+	}
+	relationships, ok := s.neo4jPort.(ports.RelationshipGraphStore)
+	if !ok {
+		return nil
+	}
+	if s.reconcile || s.relationshipsOnly || s.relationshipCardinality || s.columnLineage {
+		return nil
+	}
+	for _, rule := range rules {
+		if reason := wholeGraphReason(rule); reason != "" {
+			logrus.Infof("Rule %s %s; the graph is stored once it is complete", rule.Rule.Name, reason)
+			return nil
+		}
+	}
+	return &batchFlusher{
+		service:       s,
+		nodes:         nodes,
+		relationships: relationships,
+		stored:        ports.GraphSnapshotCounts{NodesByLabel: make(map[string]int), RelationshipsByType: make(map[string]int)},
+	}
+}
+
+// wholeGraphReason tells why rule can only be applied to the whole graph, or returns ""
+func wholeGraphReason(rule *transform_agg.RuleAggregate) string {
+	switch {
+	case len(rule.Rule.MergeStrategies) > 0:
+		return "merges rows with merge strategies"
+	case rule.Rule.WeightProperty != "":
+		return "counts rows in a relationship weight"
+	case rule.Rule.KeyMatch != nil:
+		return "normalizes keys to match endpoints"
+	case rule.Rule.NullKeys == transform.NullKeyBucket:
+		return "links NULL keys to an unknown node"
+	case rule.Rule.RuleType == transform.RelationshipRule && !readsSource(rule.Rule) && !rule.IsJunctionRule():
+		return "links nodes already in the graph"
+	}
+	return ""
+}
+
+// storeNodes writes the nodes of batch, merging them on their id
+func (f *batchFlusher) storeNodes(ctx context.Context, batch *graph.GraphAggregate) error {
+	f.service.renameProperties(batch)
+	for _, node := range batch.GetNodes() {
+		if len(node.MergeKeys) == 0 {
+			node.MergeKeys = map[string]any{"id": node.Properties["id"]}
+		}
+	}
+	if err := f.nodes.StoreGraphInBatches(ctx, batch, f.onCommit()); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, node := range batch.GetNodes() {
+		f.stored.NodesByLabel[node.Type]++
+	}
+	return nil
+}
+
+// storeRelationships writes the relationships of batch; its nodes only stand for the
+// endpoints written before and are not stored
+func (f *batchFlusher) storeRelationships(ctx context.Context, batch *graph.GraphAggregate) error {
+	f.service.renameProperties(batch)
+	if err := f.relationships.StoreRelationshipsInBatches(ctx, batch, f.onCommit()); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, rel := range batch.GetRelationships() {
+		f.stored.RelationshipsByType[rel.Type]++
+	}
+	return nil
+}
+
+// counts returns what the flusher handed to the store by label and type; nodes merged from
+// several batches count once per batch
+func (f *batchFlusher) counts() ports.GraphSnapshotCounts {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.stored
+}
+
+// onCommit adds the progress of one store call to the run's progress. Each call counts
+// from zero, and node rules may store batches concurrently.
+func (f *batchFlusher) onCommit() func(ports.GraphWriteProgress) {
+	var previous ports.GraphWriteProgress
+	return func(committed ports.GraphWriteProgress) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.written.NodesWritten += committed.NodesWritten - previous.NodesWritten
+		f.written.RelationshipsWritten += committed.RelationshipsWritten - previous.RelationshipsWritten
+		f.written.RelationshipsSkipped += committed.RelationshipsSkipped - previous.RelationshipsSkipped
+		f.written.BatchesCommitted += committed.BatchesCommitted - previous.BatchesCommitted
+		previous = committed
+		f.service.recordCommit(f.written)
+	}
+}
+
+// addEndpoints adds a node holding only the id for each endpoint of a transformed
+// relationship that is not in graphAggregate, standing for the node a batch wrote before
+func addEndpoints(item map[string]any, graphAggregate *graph.GraphAggregate) {
+	for _, field := range []string{"source", "target"} {
+		endpoint, err := relationshipField(item, field)
+		if err != nil || endpoint == nil {
+			continue
+		}
+		nodeType, _ := endpoint["type"].(string)
+		if nodeType == "" || endpoint["key"] == nil {
+			continue
+		}
+		id := storedID(endpoint["key"])
+		if !graphAggregate.HasNode(nodeType, id) {
+			_ = graphAggregate.AddNode(nodeType, map[string]any{"id": id})
+		}
+	}
+}
+
+// storedID converts a key the way createNode converts the id it stores
+func storedID(key any) any {
+	switch v := key.(type) {
+	case []byte:
+		return string(v)
+	case int64:
+		return fmt.Sprintf("%d", v)
+	default:
+		return key
+	}
+}
@@ -0,0 +1,350 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamedQuery = "SELECT id, name, team_id FROM players"
+
+func generatedRow(i int) map[string]any {
+	return map[string]any{"id": int64(i), "name": fmt.Sprintf("player %d", i), "team_id": int64(i % 7)}
+}
+
+// streamingDatabasePort generates the rows of streamedQuery as they are read. onRow, when
+// set, runs before each row is handed out.
+type streamingDatabasePort struct {
+	fakeDatabasePort
+	rows    int
+	streams int
+	onRow   func(delivered int)
+}
+
+func (f *streamingDatabasePort) StreamQuery(ctx context.Context, query string, fn func(row map[string]any) error) error {
+	if query != streamedQuery {
+		return fmt.Errorf("unexpected query: %s", query)
+	}
+	f.streams++
+	for i := 1; i <= f.rows; i++ {
+		if f.onRow != nil {
+			f.onRow(i)
+		}
+		if err := fn(generatedRow(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// argsStreamingDatabasePort streams filtered queries with their arguments, generating the
+// rows of players whose team_id equals the first argument
+type argsStreamingDatabasePort struct {
+	fakeDatabasePort
+	rows    int
+	streams int
+	args    [][]any
+}
+
+func (f *argsStreamingDatabasePort) QueryPlaceholder(n int) string { return "?" }
+
+func (f *argsStreamingDatabasePort) ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	return nil, fmt.Errorf("query read whole: %s", query)
+}
+
+func (f *argsStreamingDatabasePort) StreamQueryWithArgs(ctx context.Context, query string, args []any, fn func(row map[string]any) error) error {
+	f.streams++
+	f.args = append(f.args, args)
+	for i := 1; i <= f.rows; i++ {
+		if row := generatedRow(i); row["team_id"] == args[0] {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pagingDatabasePort answers keyset pages of streamedQuery and records the page queries
+type pagingDatabasePort struct {
+	fakeDatabasePort
+	rows  int
+	pages []string
+}
+
+var keysetPagePattern = regexp.MustCompile(`^SELECT \* FROM \((.*)\) AS keyset_page(?: WHERE id > (\d+))? ORDER BY id LIMIT (\d+)$`)
+
+func (f *pagingDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) {
+	match := keysetPagePattern.FindStringSubmatch(query)
+	if match == nil || match[1] != streamedQuery {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	f.pages = append(f.pages, query)
+	after, _ := strconv.Atoi(match[2])
+	limit, _ := strconv.Atoi(match[3])
+
+	var rows []map[string]any
+	for i := after + 1; i <= f.rows && len(rows) < limit; i++ {
+		rows = append(rows, generatedRow(i))
+	}
+	return rows, nil
+}
+
+// batchedNeo4jPort writes graphs batch by batch, keeping only what the test asks for. Each
+// write calls onWrite with the number of nodes and relationships it holds.
+type batchedNeo4jPort struct {
+	fakeNeo4jPort
+	nodes, relationships int
+	// ids, when set, collects the ids of written nodes; missing counts relationships whose
+	// endpoints were not written before them
+	ids     map[string]bool
+	missing int
+	onWrite func(elements int)
+}
+
+func (f *batchedNeo4jPort) StoreGraphInBatches(ctx context.Context, g *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
+	for _, node := range g.GetNodes() {
+		if f.ids != nil {
+			f.ids[fmt.Sprint(node.Properties["id"])] = true
+		}
+	}
+	f.nodes += len(g.GetNodes())
+	return f.write(g, len(g.GetNodes()), onCommit)
+}
+
+func (f *batchedNeo4jPort) StoreRelationshipsInBatches(ctx context.Context, g *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
+	return f.write(g, 0, onCommit)
+}
+
+func (f *batchedNeo4jPort) write(g *graph.GraphAggregate, nodes int, onCommit func(ports.GraphWriteProgress)) error {
+	for _, rel := range g.GetRelationships() {
+		if f.ids != nil && (!f.ids[fmt.Sprint(rel.SourceNode.Properties["id"])] || !f.ids[fmt.Sprint(rel.TargetNode.Properties["id"])]) {
+			f.missing++
+		}
+	}
+	f.relationships += len(g.GetRelationships())
+	onCommit(ports.GraphWriteProgress{NodesWritten: nodes, RelationshipsWritten: len(g.GetRelationships()), BatchesCommitted: 1})
+	if f.onWrite != nil {
+		f.onWrite(len(g.GetNodes()) + len(g.GetRelationships()))
+	}
+	return nil
+}
+
+func playerRules() *fakeRuleRepository {
+	players := nodeRule("players", "", "Player")
+	players.Rule.SourceSQL = streamedQuery
+	teams := &transform_agg.RuleAggregate{Name: "player_teams", Rule: transform.TransformRule{
+		Name:         "player_teams",
+		SourceSQL:    streamedQuery,
+		RuleType:     transform.RelationshipRule,
+		RelationType: "PLAYS_FOR",
+		Direction:    transform.Outgoing,
+		SourceNode:   &transform.NodeMapping{Type: "Player", Key: "id", TargetField: "id"},
+		TargetNode:   &transform.NodeMapping{Type: "Team", Key: "team_id", TargetField: "id"},
+	}}
+	return &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("teams", "teams", "Team"), players, teams}}
+}
+
+// teamRows are the source_table rows of the seven teams players belong to
+func teamRows() fakeDatabasePort {
+	var rows []map[string]any
+	for i := 0; i < 7; i++ {
+		rows = append(rows, map[string]any{"_table": "teams", "id": int64(i), "name": fmt.Sprintf("team %d", i)})
+	}
+	return fakeDatabasePort{rows: rows, queries: map[string][]map[string]any{}}
+}
+
+func TestStreamingKeepsBufferedRowsBounded(t *testing.T) {
+	const total, batchSize = 100000, 1000
+
+	processed := 0
+	maxBuffered := 0
+	var baseline, peak uint64
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	baseline = stats.HeapAlloc
+
+	db := &streamingDatabasePort{rows: total}
+	db.onRow = func(delivered int) {
+		maxBuffered = max(maxBuffered, delivered-processed)
+	}
+	service := NewTransformService(db, &fakeNeo4jPort{}, &fakeRuleRepository{})
+	service.SetStreamBatchSize(batchSize)
+
+	batches := 0
+	err := service.forEachSourceBatch(context.Background(), transform.TransformRule{}, streamedQuery, nil, func(items []map[string]any) error {
+		require.LessOrEqual(t, len(items), batchSize)
+		processed += len(items)
+		if batches++; batches%50 == 0 {
+			runtime.GC()
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, stats.HeapAlloc)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, total, processed, "every row is processed")
+	assert.Equal(t, 1, db.streams, "the query is read once")
+	assert.LessOrEqual(t, maxBuffered, batchSize, "no more than one batch is held at a time")
+	// All 200000 rows would take tens of megabytes; one batch takes well under one
+	assert.Less(t, int64(peak)-int64(baseline), int64(16<<20), "heap growth stays bounded")
+}
+
+func TestStreamingBuildsSameGraph(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	whole := teamRows()
+	var rows []map[string]any
+	for i := 1; i <= 2500; i++ {
+		rows = append(rows, generatedRow(i))
+	}
+	whole.queries[streamedQuery] = rows
+	wholeNeo4j := &fakeNeo4jPort{}
+	require.NoError(t, NewTransformService(&whole, wholeNeo4j, playerRules()).TransformAndStore(context.Background()))
+
+	streamed := &streamingDatabasePort{fakeDatabasePort: teamRows(), rows: 2500}
+	streamedNeo4j := &fakeNeo4jPort{}
+	service := NewTransformService(streamed, streamedNeo4j, playerRules())
+	service.SetStreamBatchSize(128)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Equal(t, 2, streamed.streams, "the node and relationship rules each stream the query")
+	assert.Len(t, streamedNeo4j.stored.GetNodes(), 2507)
+	assert.Len(t, streamedNeo4j.stored.GetRelationships(), 2500)
+	assert.Equal(t, graphSummary(wholeNeo4j), graphSummary(streamedNeo4j))
+}
+
+func TestStreamingWritesEachBatch(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	db := &streamingDatabasePort{fakeDatabasePort: teamRows(), rows: 2500}
+	neo4j := &batchedNeo4jPort{ids: make(map[string]bool)}
+	largest := 0
+	neo4j.onWrite = func(elements int) { largest = max(largest, elements) }
+	service := NewTransformService(db, neo4j, playerRules())
+	service.SetStreamBatchSize(128)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Nil(t, neo4j.stored, "the whole graph is never stored at once")
+	assert.Equal(t, 2507, neo4j.nodes)
+	assert.Equal(t, 2500, neo4j.relationships)
+	assert.Zero(t, neo4j.missing, "relationships are written after their endpoints")
+	// A relationship batch holds its relationships and the endpoints they match by id
+	assert.LessOrEqual(t, largest, 3*128)
+	assert.Equal(t, 2507, service.Progress().NodesWritten)
+	assert.Equal(t, 2500, service.Progress().RelationshipsWritten)
+}
+
+func TestStreamingPipelineKeepsMemoryBounded(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	const total, batchSize = 100000, 100
+
+	var baseline, peak uint64
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	baseline = stats.HeapAlloc
+
+	writes := 0
+	neo4j := &batchedNeo4jPort{}
+	neo4j.onWrite = func(int) {
+		if writes++; writes%50 == 0 {
+			runtime.GC()
+			runtime.ReadMemStats(&stats)
+			peak = max(peak, stats.HeapAlloc)
+		}
+	}
+	db := &streamingDatabasePort{fakeDatabasePort: teamRows(), rows: total}
+	service := NewTransformService(db, neo4j, playerRules())
+	service.SetStreamBatchSize(batchSize)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Equal(t, total+7, neo4j.nodes)
+	assert.Equal(t, total, neo4j.relationships)
+	// The graph of 100000 players and their relationships would take tens of megabytes;
+	// a batch of it takes well under one
+	assert.Less(t, int64(peak)-int64(baseline), int64(16<<20), "heap growth stays bounded")
+}
+
+func TestStreamingPagesByKeyWithoutStreamer(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	db := &pagingDatabasePort{fakeDatabasePort: teamRows(), rows: 1000}
+	rules := playerRules()
+	rules.rules = rules.rules[:len(rules.rules)-1]
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetStreamBatchSize(300)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Len(t, neo4j.stored.GetNodes(), 1007)
+	require.Len(t, db.pages, 4)
+	assert.Equal(t, "SELECT * FROM ("+streamedQuery+") AS keyset_page ORDER BY id LIMIT 300", db.pages[0])
+	assert.Equal(t, "SELECT * FROM ("+streamedQuery+") AS keyset_page WHERE id > 900 ORDER BY id LIMIT 300", db.pages[3])
+}
+
+func TestStreamingFilteredRulesStreamWithArguments(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	db := &argsStreamingDatabasePort{fakeDatabasePort: teamRows(), rows: 700}
+	rules := playerRules()
+	for _, rule := range rules.rules[1:] {
+		rule.Rule.Filters = []transform.RuleFilter{{Column: "team_id", Operator: transform.FilterEqual, Value: int64(3)}}
+	}
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetStreamBatchSize(30)
+	service.SetLookupCache(200, 0)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Equal(t, 1, db.streams, "the relationship rule reads the cached rows of the same query and arguments")
+	assert.Equal(t, []any{int64(3)}, db.args[0])
+	assert.Len(t, neo4j.stored.GetNodes(), 107)
+	assert.Len(t, neo4j.stored.GetRelationships(), 100)
+}
+
+func TestStreamingCachesSmallResults(t *testing.T) {
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(logrus.InfoLevel)
+
+	db := &streamingDatabasePort{fakeDatabasePort: teamRows(), rows: 50}
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, playerRules())
+	service.SetStreamBatchSize(20)
+	service.SetLookupCache(100, 0)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Equal(t, 1, db.streams, "the relationship rule reads the cached rows")
+	assert.Len(t, neo4j.stored.GetRelationships(), 50)
+}
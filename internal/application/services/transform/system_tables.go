@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetSystemSchemas skips the rules that read tables of the database's internal schemas,
+// such as mysql.user or pg_catalog.pg_class; see models.DataFilteringConfig.SystemSchemaList
+func (s *TransformService) SetSystemSchemas(schemas []string) {
+	s.systemSchemas = schemas
+}
+
+// excludeSystemRules drops the rules whose source table, or a table their query reads, is
+// qualified with a system schema
+func (s *TransformService) excludeSystemRules(rules []*transform_agg.RuleAggregate) []*transform_agg.RuleAggregate {
+	if len(s.systemSchemas) == 0 {
+		return rules
+	}
+	pattern := systemSchemaReference(s.systemSchemas)
+
+	kept := make([]*transform_agg.RuleAggregate, 0, len(rules))
+	for _, rule := range rules {
+		if models.IsSystemTable(rule.Rule.SourceTable, s.systemSchemas) ||
+			(rule.Rule.SourceSQL != "" && pattern.MatchString(rule.Rule.SourceSQL)) {
+			logrus.Warnf("Skipping rule %s: it reads a system table; set data_filtering.include_system_tables to import it", rule.Rule.Name)
+			continue
+		}
+		kept = append(kept, rule)
+	}
+	return kept
+}
+
+// systemSchemaReference matches a FROM or JOIN of a table qualified with one of schemas
+func systemSchemaReference(schemas []string) *regexp.Regexp {
+	quoted := make([]string, len(schemas))
+	for i, schema := range schemas {
+		quoted[i] = regexp.QuoteMeta(schema)
+	}
+	return regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+[` + "`" + `"]?(?:` + strings.Join(quoted, "|") + `)[` + "`" + `"]?\s*\.`)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedTypes runs a transform of the given rules and returns the stored node types
+func storedTypes(t *testing.T, db *fakeDatabasePort, rules []*transform_agg.RuleAggregate, systemSchemas []string) map[string]int {
+	t.Helper()
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: rules})
+	service.SetSystemSchemas(systemSchemas)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	types := make(map[string]int)
+	for _, node := range neo4j.stored.GetNodes() {
+		types[node.Type]++
+	}
+	return types
+}
+
+func TestSystemTablesAreNotImported(t *testing.T) {
+	const catalogSQL = "SELECT oid AS id, relname AS name FROM pg_catalog.pg_class"
+	db := &fakeDatabasePort{
+		rows: []map[string]any{
+			{"_table": "users", "id": int64(1), "name": "Ada"},
+			{"_table": "mysql.user", "id": int64(2), "name": "root"},
+		},
+		queries: map[string][]map[string]any{catalogSQL: {{"id": int64(3), "name": "pg_class"}}},
+	}
+	catalogQuery := nodeRule("catalog", "", "CatalogEntry")
+	catalogQuery.Rule.SourceSQL = catalogSQL
+
+	rules := func() []*transform_agg.RuleAggregate {
+		return []*transform_agg.RuleAggregate{
+			nodeRule("users", "users", "User"),
+			nodeRule("mysql_users", "mysql.user", "Account"),
+			catalogQuery,
+		}
+	}
+
+	mysqlTypes := storedTypes(t, db, rules(), models.DefaultSystemSchemas(models.DatabaseTypeMySQL))
+	assert.Equal(t, 1, mysqlTypes["User"])
+	assert.Zero(t, mysqlTypes["Account"], "tables of the mysql schema are skipped")
+	assert.Equal(t, 1, mysqlTypes["CatalogEntry"], "pg_catalog is not a MySQL system schema")
+
+	pgTypes := storedTypes(t, db, rules(), models.DefaultSystemSchemas(models.DatabaseTypePostgreSQL))
+	assert.Zero(t, pgTypes["CatalogEntry"], "queries reading pg_catalog are skipped")
+	assert.Equal(t, 1, pgTypes["Account"])
+
+	included := models.DataFilteringConfig{IncludeSystemTables: true}
+	allTypes := storedTypes(t, db, rules(), included.SystemSchemaList(models.DatabaseTypeMySQL))
+	assert.Equal(t, 1, allTypes["Account"], "include_system_tables imports them")
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"sort"
+)
+
+// scheduleTables runs task once for every table, starting a table only after the tables it
+// references have finished and keeping at most limit tasks running. This follows the
+// processing order of the pre-flight estimate while independent tables run side by side.
+// When only tables in a reference cycle are left, they are released one at a time in name
+// order. The first error stops new tasks from starting and cancels the ctx of running ones.
+func scheduleTables(ctx context.Context, tables []string, references map[string][]string, limit int, task func(ctx context.Context, table string) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	known := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		known[table] = true
+	}
+	pending := make(map[string]int, len(tables))
+	dependents := make(map[string][]string)
+	for _, table := range tables {
+		for _, referenced := range references[table] {
+			if referenced == table || !known[referenced] {
+				continue
+			}
+			pending[table]++
+			dependents[referenced] = append(dependents[referenced], table)
+		}
+	}
+
+	var ready []string
+	for _, table := range tables {
+		if pending[table] == 0 {
+			ready = append(ready, table)
+		}
+	}
+
+	type result struct {
+		table string
+		err   error
+	}
+	results := make(chan result)
+	started := make(map[string]bool, len(tables))
+	running, finished := 0, 0
+	var firstErr error
+
+	for finished < len(tables) {
+		if firstErr == nil && ctx.Err() != nil {
+			firstErr = ctx.Err()
+		}
+		sort.Strings(ready)
+		for firstErr == nil && running < limit && len(ready) > 0 {
+			table := ready[0]
+			ready = ready[1:]
+			if started[table] {
+				continue
+			}
+			started[table] = true
+			running++
+			go func() {
+				results <- result{table: table, err: task(ctx, table)}
+			}()
+		}
+
+		if running == 0 {
+			if firstErr != nil {
+				return firstErr
+			}
+			ready = append(ready, firstUnstarted(tables, started))
+			continue
+		}
+
+		done := <-results
+		running--
+		finished++
+		if done.err != nil && firstErr == nil {
+			firstErr = done.err
+			cancel()
+		}
+		for _, dependent := range dependents[done.table] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return firstErr
+}
+
+// firstUnstarted returns the first table by name that has not been started
+func firstUnstarted(tables []string, started map[string]bool) string {
+	first := ""
+	for _, table := range tables {
+		if !started[table] && (first == "" || table < first) {
+			first = table
+		}
+	}
+	return first
+}
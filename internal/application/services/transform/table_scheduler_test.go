@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scheduleLog records when tasks start and finish, and how many ran at once
+type scheduleLog struct {
+	mu         sync.Mutex
+	events     []string
+	running    int
+	maxRunning int
+}
+
+func (l *scheduleLog) task(ctx context.Context, table string) error {
+	l.mu.Lock()
+	l.events = append(l.events, "start "+table)
+	l.running++
+	l.maxRunning = max(l.maxRunning, l.running)
+	l.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	l.mu.Lock()
+	l.events = append(l.events, "finish "+table)
+	l.running--
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *scheduleLog) index(event string) int {
+	for i, e := range l.events {
+		if e == event {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestScheduleTables_ParentsFinishBeforeChildrenStart(t *testing.T) {
+	log := &scheduleLog{}
+	references := map[string][]string{
+		"orders":      {"customers"},
+		"order_items": {"orders", "products"},
+		"reviews":     {"customers", "products"},
+	}
+	tables := []string{"order_items", "reviews", "orders", "customers", "products"}
+
+	require.NoError(t, scheduleTables(context.Background(), tables, references, 4, log.task))
+	require.Len(t, log.events, 2*len(tables))
+	for child, parents := range references {
+		for _, parent := range parents {
+			assert.Less(t, log.index("finish "+parent), log.index("start "+child), "%s must finish before %s starts", parent, child)
+		}
+	}
+}
+
+func TestScheduleTables_IndependentTablesRunConcurrently(t *testing.T) {
+	// Each task waits until the other has started, which only succeeds if both run at once
+	var started sync.WaitGroup
+	started.Add(2)
+	task := func(ctx context.Context, table string) error {
+		started.Done()
+		waited := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New(table + " ran alone")
+		}
+	}
+
+	assert.NoError(t, scheduleTables(context.Background(), []string{"customers", "products"}, nil, 2, task))
+}
+
+func TestScheduleTables_RespectsConcurrencyLimit(t *testing.T) {
+	log := &scheduleLog{}
+	tables := []string{"a", "b", "c", "d", "e", "f"}
+
+	require.NoError(t, scheduleTables(context.Background(), tables, nil, 2, log.task))
+	assert.Equal(t, 2, log.maxRunning)
+}
+
+func TestScheduleTables_CyclesRunAfterTheRest(t *testing.T) {
+	log := &scheduleLog{}
+	references := map[string][]string{
+		"employees":   {"departments", "employees"},
+		"departments": {"employees"},
+		"audit":       {"missing"}, // filtered-out tables do not block
+	}
+
+	require.NoError(t, scheduleTables(context.Background(), []string{"employees", "departments", "audit", "tags"}, references, 1, log.task))
+	assert.Equal(t, []string{
+		"start audit", "finish audit",
+		"start tags", "finish tags",
+		"start departments", "finish departments",
+		"start employees", "finish employees",
+	}, log.events)
+}
+
+func TestScheduleTables_ErrorStopsDependents(t *testing.T) {
+	var mu sync.Mutex
+	var ran []string
+	failure := errors.New("customers failed")
+	task := func(ctx context.Context, table string) error {
+		mu.Lock()
+		ran = append(ran, table)
+		mu.Unlock()
+		if table == "customers" {
+			return failure
+		}
+		return nil
+	}
+
+	err := scheduleTables(context.Background(), []string{"customers", "orders"}, map[string][]string{"orders": {"customers"}}, 2, task)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"customers"}, ran)
+}
+
+func TestTransformAndStore_ConcurrentTablesCreateAllNodes(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		nodeRule("course_titles", "courses", "Title"),
+		enrollmentRule(nil),
+	}}
+
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	service.SetTableConcurrency(4, map[string][]string{"enrollments": {"students", "courses"}})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	require.NotNil(t, neo4j.stored)
+	assert.Len(t, neo4j.stored.GetNodes(), 4)
+	assert.Len(t, neo4j.stored.GetRelationships(), 2)
+}
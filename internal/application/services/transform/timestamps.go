@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import "time"
+
+// SetTimezone stores timestamp properties as Neo4j temporal values in timezone instead of
+// as text. sourceTimezone, when set, is the zone the source's zone-less timestamps (e.g.
+// MySQL DATETIME, read as UTC) were written in: their wall-clock time is taken as local time
+// there before converting.
+func (s *TransformService) SetTimezone(timezone, sourceTimezone *time.Location) {
+	s.timezone = timezone
+	s.sourceTimezone = sourceTimezone
+}
+
+// normalizeTimestamp converts a source timestamp into the configured timezone
+func (s *TransformService) normalizeTimestamp(value time.Time) time.Time {
+	if s.sourceTimezone != nil {
+		value = time.Date(value.Year(), value.Month(), value.Day(),
+			value.Hour(), value.Minute(), value.Second(), value.Nanosecond(), s.sourceTimezone)
+	}
+	return value.In(s.timezone)
+}
+
+// normalizeTimestamps converts the timestamp values of properties in place; without a
+// configured timezone they are left for the usual text conversion
+func (s *TransformService) normalizeTimestamps(properties map[string]any) {
+	if s.timezone == nil {
+		return
+	}
+	for key, value := range properties {
+		if timestamp, ok := value.(time.Time); ok {
+			properties[key] = s.normalizeTimestamp(timestamp)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	pragueTime  = time.FixedZone("CET", 3600)
+	newYorkTime = time.FixedZone("EST", -5*3600)
+)
+
+// storeSignups imports one user whose signed_up_at is value and returns the stored value
+func storeSignups(t *testing.T, value time.Time, configure func(*TransformService)) any {
+	t.Helper()
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "users", "id": int64(1), "name": "Ada", "signed_up_at": value},
+	}}
+	rule := nodeRule("users", "users", "User")
+	rule.Rule.FieldMappings["signed_up_at"] = "signed_up_at"
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	configure(service)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	nodes := neo4j.stored.GetNodes()
+	require.Len(t, nodes, 1)
+	return nodes[0].Properties["signed_up_at"]
+}
+
+func TestTimezoneNormalizesTimestamps(t *testing.T) {
+	// A DATETIME written by a server in Prague, read by the driver as UTC
+	zoneless := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+	// A timestamp with its zone, as PostgreSQL timestamptz values are read
+	zoned := time.Date(2026, 1, 15, 9, 30, 0, 0, newYorkTime)
+
+	tests := []struct {
+		name           string
+		value          time.Time
+		timezone       *time.Location
+		sourceTimezone *time.Location
+		want           time.Time
+	}{
+		{
+			name:           "zone-less source values are local time of the source zone",
+			value:          zoneless,
+			timezone:       time.UTC,
+			sourceTimezone: pragueTime,
+			want:           time.Date(2026, 1, 15, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "zoned values convert to the configured zone",
+			value:    zoned,
+			timezone: time.UTC,
+			want:     time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "non-UTC target zone",
+			value:    zoned,
+			timezone: pragueTime,
+			want:     time.Date(2026, 1, 15, 15, 30, 0, 0, pragueTime),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := storeSignups(t, tt.value, func(s *TransformService) {
+				s.SetTimezone(tt.timezone, tt.sourceTimezone)
+			})
+
+			timestamp, ok := stored.(time.Time)
+			require.True(t, ok, "timestamps are stored as temporal values, got %T", stored)
+			assert.True(t, tt.want.Equal(timestamp), "want %s, got %s", tt.want, timestamp)
+			assert.Equal(t, tt.timezone, timestamp.Location())
+			assert.Equal(t, tt.want.Format(time.RFC3339), timestamp.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestTimestampsStayTextWithoutTimezone(t *testing.T) {
+	value := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	stored := storeSignups(t, value, func(*TransformService) {})
+	assert.Equal(t, value.String(), stored)
+}
+
+func TestTimezoneNormalizesRelationshipProperties(t *testing.T) {
+	db := newEnrollmentFixture()
+	for _, row := range db.rows {
+		if row["_table"] == "enrollments" {
+			row["enrolled_at"] = time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)
+		}
+	}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(map[string]string{"enrolled_at": "enrolled_at"}),
+	}}
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, rules)
+	service.SetTimezone(newYorkTime, nil)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	relationships := neo4j.stored.GetRelationships()
+	require.NotEmpty(t, relationships)
+	for _, relationship := range relationships {
+		enrolledAt, ok := relationship.Properties["enrolled_at"].(time.Time)
+		require.True(t, ok)
+		assert.Equal(t, "2026-02-01T03:00:00-05:00", enrolledAt.Format(time.RFC3339))
+	}
+}
@@ -14,13 +14,18 @@ package transform
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sql-graph-visualizer/internal/application/ports"
 	"sql-graph-visualizer/internal/domain/aggregates/graph"
 	"sql-graph-visualizer/internal/domain/aggregates/serialization"
 	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
 	"sql-graph-visualizer/internal/domain/entities"
+	"sql-graph-visualizer/internal/domain/models"
 	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -29,6 +34,118 @@ type TransformService struct {
 	databasePort ports.DatabasePort
 	neo4jPort    ports.Neo4jPort
 	ruleRepo     ports.TransformRuleRepository
+	timeout      time.Duration
+
+	// relationshipsOnly skips node rules and links nodes already stored in Neo4j
+	relationshipsOnly bool
+	// softDeleteColumn, when set, excludes source rows where that column is set
+	softDeleteColumn string
+	// reconcile writes only the differences to the stored graph, keeping annotationLabel nodes
+	reconcile       bool
+	annotationLabel string
+	// tableConcurrency runs the node rules of that many source tables at once, after the
+	// tables they reference (tableReferences) are done
+	tableConcurrency int
+	tableReferences  map[string][]string
+	// snapshots, when set, keeps the graph of every completed run
+	snapshots ports.GraphSnapshotRecorder
+	// detectIdentity gives node rules without a key the primary key of their table, or
+	// identityFallback when the table has none
+	detectIdentity   bool
+	primaryKeys      map[string][]string
+	identityFallback transform.IdentityFallback
+	// columnLineage adds Column nodes linked by REFERENCES for columnReferences
+	columnLineage    bool
+	columnReferences []models.ColumnReference
+	// provenance tags imported nodes and relationships with sourceDatabase, their source
+	// table and the run
+	provenance     bool
+	sourceDatabase string
+	// timezone, when set, stores timestamps as temporal values in that zone; zone-less
+	// source timestamps are read as local time in sourceTimezone
+	timezone       *time.Location
+	sourceTimezone *time.Location
+	// systemSchemas are the database's internal schemas; rules reading them are skipped
+	systemSchemas []string
+	// lookupCacheRows enables a per-run cache (lookups) of source query results up to that
+	// many rows, holding lookupCacheTotalRows rows in all
+	lookupCacheRows      int
+	lookupCacheTotalRows int
+	lookups              *lookupCache
+	// streamBatchSize, when set, reads source queries that many rows at a time
+	streamBatchSize int
+	// propertyNaming renames node and relationship properties before the graph is stored
+	propertyNaming transform.PropertyNaming
+	// relationshipCardinality annotates relationships with the cardinality of their type
+	relationshipCardinality bool
+
+	// State of the active (or last) run, used to report progress and cancel it
+	runMutex sync.Mutex
+	progress TransformProgress
+	cancel   context.CancelCauseFunc
+	done     chan struct{}
+
+	// lastRun is when the last successful run started; bound to "@last_run" query params
+	// and kept in runStore, when set, across restarts
+	lastRun  time.Time
+	runStore ports.TransformRunStore
+}
+
+var (
+	// ErrTransformCancelled is the cause reported when a run is stopped through Cancel
+	ErrTransformCancelled = errors.New("transform cancelled")
+	// ErrNoActiveTransform is returned by Cancel when no transform is running
+	ErrNoActiveTransform = errors.New("no transform is running")
+	// ErrTransformRunning is returned when a transform is started while another is active
+	ErrTransformRunning = errors.New("a transform is already running")
+)
+
+// TransformProgress reports how far the active or last transform run got
+type TransformProgress struct {
+	RunID      string     `json:"run_id,omitempty"`
+	Running    bool       `json:"running"`
+	Cancelled  bool       `json:"cancelled"`
+	Phase      string     `json:"phase,omitempty"`
+	Rule       string     `json:"rule,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+
+	// What has been committed to Neo4j; an interrupted batch is rolled back and not counted
+	ports.GraphWriteProgress
+}
+
+// Phases reported when a transform is aborted
+const (
+	PhaseFetchSourceData = "fetching source data"
+	PhaseLoadNodes       = "loading existing nodes"
+	PhaseNodeRules       = "node rules"
+	PhaseRelationships   = "relationship rules"
+	PhaseStoreGraph      = "storing graph"
+)
+
+// TransformTimeoutError reports that a transform was aborted because its context ended,
+// together with the phase (and rule, if any) that was running at the time
+type TransformTimeoutError struct {
+	Phase   string
+	Rule    string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TransformTimeoutError) Error() string {
+	where := e.Phase
+	if e.Rule != "" {
+		where = fmt.Sprintf("%s (rule %s)", e.Phase, e.Rule)
+	}
+	if errors.Is(e.Err, context.DeadlineExceeded) && e.Timeout > 0 {
+		return fmt.Sprintf("transform timed out after %s while %s", e.Timeout, where)
+	}
+	return fmt.Sprintf("transform aborted while %s: %v", where, e.Err)
+}
+
+func (e *TransformTimeoutError) Unwrap() error {
+	return e.Err
 }
 
 func NewTransformService(
@@ -43,12 +160,136 @@ func NewTransformService(
 	}
 }
 
+// SetTimeout bounds the whole TransformAndStore run; zero disables the limit
+func (s *TransformService) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SetRelationshipsOnly makes runs skip node rules and create relationships between the
+// nodes already stored in Neo4j, leaving those nodes and their properties unchanged
+func (s *TransformService) SetRelationshipsOnly(relationshipsOnly bool) {
+	s.relationshipsOnly = relationshipsOnly
+}
+
+// SetTableConcurrency runs the node rules of up to limit source tables at once. references
+// lists the tables each table references, such as its foreign key parents; a table's rules
+// start only once those tables are done. A limit of 0 or 1 keeps rules in rule order.
+func (s *TransformService) SetTableConcurrency(limit int, references map[string][]string) {
+	s.tableConcurrency = limit
+	s.tableReferences = references
+}
+
+// SetSnapshotRecorder captures the graph of every completed run in recorder, together with
+// fingerprints of the source rows and the rules it was built from
+func (s *TransformService) SetSnapshotRecorder(recorder ports.GraphSnapshotRecorder) {
+	s.snapshots = recorder
+}
+
+// SetIdentityDetection identifies the nodes of rules that map no id and set no key_columns
+// by the primary key of their source table, taken from primaryKeys (composite keys become
+// key columns). Rows of tables without a primary key, and of query rules, are identified as
+// fallback says.
+func (s *TransformService) SetIdentityDetection(primaryKeys map[string][]string, fallback transform.IdentityFallback) {
+	s.detectIdentity = true
+	s.primaryKeys = primaryKeys
+	s.identityFallback = fallback
+}
+
+// SetColumnLineage adds column-level lineage to every run: a Column node for each foreign key
+// column and the column it references, linked by a REFERENCES relationship
+func (s *TransformService) SetColumnLineage(references []models.ColumnReference) {
+	s.columnLineage = true
+	s.columnReferences = references
+}
+
+// SetSoftDeleteColumn excludes source rows whose column is set (e.g. "deleted_at") from
+// every rule; an empty column includes all rows
+func (s *TransformService) SetSoftDeleteColumn(column string) {
+	s.softDeleteColumn = column
+}
+
+// Progress returns the state of the active or last transform run
+func (s *TransformService) Progress() TransformProgress {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	return s.progress
+}
+
+// Cancel stops the active transform and waits until it has stopped (or ctx ends),
+// returning what was written before cancellation
+func (s *TransformService) Cancel(ctx context.Context) (TransformProgress, error) {
+	s.runMutex.Lock()
+	cancel, done := s.cancel, s.done
+	s.runMutex.Unlock()
+
+	if cancel == nil {
+		return s.Progress(), ErrNoActiveTransform
+	}
+
+	logrus.Warnf("Cancelling active transform")
+	cancel(ErrTransformCancelled)
+
+	select {
+	case <-done:
+		return s.Progress(), nil
+	case <-ctx.Done():
+		return s.Progress(), ctx.Err()
+	}
+}
+
 func (s *TransformService) TransformAndStore(ctx context.Context) error {
-	data, err := s.databasePort.FetchData()
-	if err != nil {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if err := s.beginRun(cancel); err != nil {
+		return err
+	}
+
+	err := s.transformAndStore(runCtx)
+	s.finishRun(runCtx, err)
+	return err
+}
+
+// Start begins a transform in the background and returns once it is running, or
+// ErrTransformRunning when another transform is active. Its outcome is reported by Progress.
+func (s *TransformService) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	if err := s.beginRun(cancel); err != nil {
+		cancel(nil)
 		return err
 	}
 
+	go func() {
+		defer cancel(nil)
+		err := s.transformAndStore(runCtx)
+		s.finishRun(runCtx, err)
+		if err != nil {
+			logrus.Errorf("Transform failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *TransformService) transformAndStore(ctx context.Context) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	s.startLookupCache()
+	defer s.finishLookupCache()
+
+	s.setPhase(PhaseFetchSourceData, "")
+	var data []map[string]any
+	err := awaitWithContext(ctx, func() error {
+		var fetchErr error
+		data, fetchErr = s.databasePort.FetchData()
+		return fetchErr
+	})
+	if err != nil {
+		return s.abortError(ctx, PhaseFetchSourceData, "", err)
+	}
+
 	logrus.Infof("Loaded %d records from database", len(data))
 
 	rules, err := s.ruleRepo.GetAllRules(ctx)
@@ -56,6 +297,7 @@ func (s *TransformService) TransformAndStore(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	rules = s.excludeSystemRules(rules)
 
 	graphAggregate := graph.NewGraphAggregate("")
 
@@ -77,65 +319,37 @@ func (s *TransformService) TransformAndStore(ctx context.Context) error {
 	}
 
 	tableData := make(map[string][]map[string]any)
-	for _, item := range data {
+	for _, item := range s.excludeSoftDeleted(data) {
 		if tableName, ok := item["_table"].(string); ok {
 			convertedItem := convertMapValues(item)
 			tableData[tableName] = append(tableData[tableName], convertedItem)
 		}
 	}
 
-	// First pass: Process all node rules to create nodes
-	logrus.Infof("First pass: Creating nodes")
-	for _, rule := range rules {
-		if rule.Rule.RuleType != transform.NodeRule {
-			continue
-		}
-
-		logrus.Infof("Processing node rule: %s", rule.Rule.Name)
-
-		var items []map[string]any
-		var err error
-
-		if rule.Rule.SourceSQL != "" {
-			// Rule has custom SQL query
-			logrus.Infof("Executing SQL query: %s", rule.Rule.SourceSQL)
-			items, err = s.databasePort.ExecuteQuery(rule.Rule.SourceSQL)
-			if err != nil {
-				return fmt.Errorf("error executing SQL query for rule %s: %v", rule.Rule.Name, err)
-			}
-		} else {
-			// Rule uses table data (legacy approach)
-			sourceTable := rule.Rule.SourceTable
-			logrus.Infof("Applying rule to table: %s", sourceTable)
-			var ok bool
-			items, ok = tableData[sourceTable]
-			if !ok {
-				items = []map[string]any{}
-			}
+	if s.relationshipsOnly {
+		s.setPhase(PhaseLoadNodes, "")
+		if err := s.loadExistingNodes(rules, graphAggregate); err != nil {
+			return s.abortError(ctx, PhaseLoadNodes, "", err)
 		}
+	}
 
-		logrus.Infof("Data returned for node rule %s: %d records", rule.Rule.Name, len(items))
+	flusher := s.batchFlusher(rules)
 
-		// Convert map properties to supported types before transformation
-		for i, item := range items {
-			items[i] = s.convertMapProperties(item)
+	// First pass: Process all node rules to create nodes
+	logrus.Infof("First pass: Creating nodes")
+	if !s.relationshipsOnly {
+		if err := s.runNodeRules(ctx, rules, tableData, graphAggregate, flusher); err != nil {
+			return err
 		}
-
-		// Apply transformation rules
-		transformedData := rule.ApplyRules(items)
-		logrus.Infof("Transformed %d records for node rule %s", len(transformedData), rule.Rule.Name)
-
-		// Add transformed data to graph
-		for _, item := range transformedData {
-			if mapItem, ok := item.(map[string]any); ok {
-				mapItem = s.convertMapProperties(mapItem)
-				if err := s.updateGraph(mapItem, graphAggregate); err != nil {
-					logrus.Warnf("Warning updating graph for node rule %s: %v (continuing)", rule.Rule.Name, err)
-				}
-			} else {
-				logrus.Warnf("Unexpected data format for node rule %s: %T", rule.Rule.Name, item)
-			}
+	}
+	if flusher != nil {
+		// Streamed nodes are written already; the nodes of source tables follow, and
+		// relationships match all of them by id from here on
+		s.setPhase(PhaseStoreGraph, "")
+		if err := flusher.storeNodes(ctx, graphAggregate); err != nil {
+			return s.abortError(ctx, PhaseStoreGraph, "", err)
 		}
+		graphAggregate = graph.NewGraphAggregate("")
 	}
 
 	// Second pass: Process relationship rules to create relationships
@@ -144,35 +358,60 @@ func (s *TransformService) TransformAndStore(ctx context.Context) error {
 		if rule.Rule.RuleType != transform.RelationshipRule {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return s.abortError(ctx, PhaseRelationships, rule.Rule.Name, err)
+		}
+		s.setPhase(PhaseRelationships, rule.Rule.Name)
 
 		logrus.Infof("Processing relationship rule: %s", rule.Rule.Name)
 
-		if rule.Rule.SourceSQL != "" {
-			// Rule has custom SQL query - process like before
-			logrus.Infof("Executing SQL query for relationship: %s", rule.Rule.SourceSQL)
-			items, err := s.databasePort.ExecuteQuery(rule.Rule.SourceSQL)
+		if readsSource(rule.Rule) {
+			// Rule has custom SQL query, or a filtered source table
+			query, args, err := s.ruleSource(rule.Rule)
 			if err != nil {
-				logrus.Warnf("Error executing SQL query for relationship rule %s: %v (continuing)", rule.Rule.Name, err)
+				logrus.Warnf("Invalid SQL query for relationship rule %s: %v (continuing)", rule.Rule.Name, err)
 				continue
 			}
-
-			// Convert map properties to supported types before transformation
-			for i, item := range items {
-				items[i] = s.convertMapProperties(item)
+			logrus.Infof("Executing SQL query for relationship: %s", query)
+			transformed := 0
+			var storeErr error
+			err = s.forEachSourceBatch(ctx, rule.Rule, query, args, func(items []map[string]any) error {
+				if flusher == nil {
+					transformed += s.addRelationshipRows(rule, s.excludeSoftDeleted(items), graphAggregate, false)
+					return nil
+				}
+				batch := graph.NewGraphAggregate("")
+				transformed += s.addRelationshipRows(rule, s.excludeSoftDeleted(items), batch, true)
+				storeErr = flusher.storeRelationships(ctx, batch)
+				return storeErr
+			})
+			if storeErr != nil {
+				// A failed write is not a query error to skip the rule for
+				return s.abortError(ctx, PhaseStoreGraph, rule.Rule.Name, storeErr)
 			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return s.abortError(ctx, PhaseRelationships, rule.Rule.Name, err)
+				}
+				logrus.Warnf("Error executing SQL query for relationship rule %s: %v (continuing)", rule.Rule.Name, err)
+				continue
+			}
+			logrus.Infof("Transformed %d records for relationship rule %s", transformed, rule.Rule.Name)
+		} else if rule.IsJunctionRule() {
+			// Junction table rows link two entities; extra columns become relationship properties
+			items := tableData[rule.Rule.SourceTable]
+			logrus.Infof("Applying junction rule %s to table %s: %d records", rule.Rule.Name, rule.Rule.SourceTable, len(items))
 
-			// Apply transformation rules
-			transformedData := rule.ApplyRules(items)
-			logrus.Infof("Transformed %d records for relationship rule %s", len(transformedData), rule.Rule.Name)
-
-			// Add transformed relationships to graph
-			for _, item := range transformedData {
+			provenance := s.provenanceOf(rule)
+			for _, item := range rule.ApplyRules(items) {
 				if mapItem, ok := item.(map[string]any); ok {
+					tagProvenance(mapItem, provenance)
+					if flusher != nil {
+						addEndpoints(mapItem, graphAggregate)
+					}
 					if err := s.updateGraph(mapItem, graphAggregate); err != nil {
-						logrus.Warnf("Warning updating graph for relationship rule %s: %v (continuing)", rule.Rule.Name, err)
+						logrus.Warnf("Warning updating graph for junction rule %s: %v (continuing)", rule.Rule.Name, err)
 					}
-				} else {
-					logrus.Warnf("Unexpected data format for relationship rule %s: %T", rule.Rule.Name, item)
 				}
 			}
 		} else {
@@ -185,9 +424,424 @@ func (s *TransformService) TransformAndStore(ctx context.Context) error {
 		}
 	}
 
+	if flusher != nil {
+		s.setPhase(PhaseStoreGraph, "")
+		if err := flusher.storeRelationships(ctx, graphAggregate); err != nil {
+			return s.abortError(ctx, PhaseStoreGraph, "", err)
+		}
+		if s.snapshots != nil {
+			s.recordSnapshotCounts(flusher.counts(), data, rules)
+		}
+		return nil
+	}
+
+	if s.relationshipCardinality {
+		s.annotateCardinality(graphAggregate, rules)
+	}
+	if s.columnLineage {
+		s.addColumnLineage(graphAggregate)
+	}
+
+	s.renameProperties(graphAggregate)
+
 	logrus.Infof("Number of nodes to save: %d", len(graphAggregate.GetNodes()))
 	logrus.Infof("Saving graph to Neo4j")
-	return s.neo4jPort.StoreGraph(graphAggregate)
+	s.setPhase(PhaseStoreGraph, "")
+	if err := s.storeGraph(ctx, graphAggregate, rules); err != nil {
+		return s.abortError(ctx, PhaseStoreGraph, "", err)
+	}
+	if s.snapshots != nil {
+		s.recordSnapshot(graphAggregate, data, rules)
+	}
+	return nil
+}
+
+// addRelationshipRows adds the relationships a relationship rule produces from items to the
+// graph and returns how many rows were transformed. With endpoints set, endpoints missing
+// from the graph are added by id, for nodes already written by a batchFlusher.
+func (s *TransformService) addRelationshipRows(rule *transform_agg.RuleAggregate, items []map[string]any, graphAggregate *graph.GraphAggregate, endpoints bool) int {
+	// Convert map properties to supported types before transformation
+	for i, item := range items {
+		items[i] = s.convertMapProperties(item)
+	}
+
+	// Apply transformation rules
+	transformedData := rule.ApplyRules(items)
+
+	// Add transformed relationships to graph
+	provenance := s.provenanceOf(rule)
+	for _, item := range transformedData {
+		if mapItem, ok := item.(map[string]any); ok {
+			tagProvenance(mapItem, provenance)
+			if endpoints {
+				addEndpoints(mapItem, graphAggregate)
+			}
+			if err := s.updateGraph(mapItem, graphAggregate); err != nil {
+				logrus.Warnf("Warning updating graph for relationship rule %s: %v (continuing)", rule.Rule.Name, err)
+			}
+		} else {
+			logrus.Warnf("Unexpected data format for relationship rule %s: %T", rule.Rule.Name, item)
+		}
+	}
+	return len(transformedData)
+}
+
+// runNodeRules applies the node rules in order, or by source table through scheduleTables
+// when a table concurrency is set. Rules sharing a source table always run one after the
+// other, since they work on the same rows.
+func (s *TransformService) runNodeRules(ctx context.Context, rules []*transform_agg.RuleAggregate, tableData map[string][]map[string]any, graphAggregate *graph.GraphAggregate, flusher *batchFlusher) error {
+	var graphMutex sync.Mutex
+	if s.tableConcurrency <= 1 {
+		for _, rule := range rules {
+			if rule.Rule.RuleType != transform.NodeRule {
+				continue
+			}
+			if err := s.applyNodeRule(ctx, rule, tableData, graphAggregate, &graphMutex, flusher); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var tables []string
+	tableRules := make(map[string][]*transform_agg.RuleAggregate)
+	for _, rule := range rules {
+		if rule.Rule.RuleType != transform.NodeRule {
+			continue
+		}
+		// Query rules without a source table do not depend on other tables
+		table := rule.Rule.SourceTable
+		if table == "" {
+			table = "rule:" + rule.Rule.Name
+		}
+		if _, ok := tableRules[table]; !ok {
+			tables = append(tables, table)
+		}
+		tableRules[table] = append(tableRules[table], rule)
+	}
+
+	logrus.Infof("Processing node rules of %d tables, up to %d at once", len(tables), s.tableConcurrency)
+	return scheduleTables(ctx, tables, s.tableReferences, s.tableConcurrency, func(ctx context.Context, table string) error {
+		for _, rule := range tableRules[table] {
+			if err := s.applyNodeRule(ctx, rule, tableData, graphAggregate, &graphMutex, flusher); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyNodeRule reads the rows of a node rule and adds the nodes it produces to the graph,
+// holding graphMutex while the graph is updated. With a flusher, the nodes of each source
+// batch are written as soon as the batch is transformed instead.
+func (s *TransformService) applyNodeRule(ctx context.Context, rule *transform_agg.RuleAggregate, tableData map[string][]map[string]any, graphAggregate *graph.GraphAggregate, graphMutex *sync.Mutex, flusher *batchFlusher) error {
+	if err := ctx.Err(); err != nil {
+		return s.abortError(ctx, PhaseNodeRules, rule.Rule.Name, err)
+	}
+	s.setPhase(PhaseNodeRules, rule.Rule.Name)
+
+	logrus.Infof("Processing node rule: %s", rule.Rule.Name)
+	rule = s.resolveIdentity(rule)
+
+	if readsSource(rule.Rule) {
+		// Rule has custom SQL query, or a filtered source table
+		query, args, bindErr := s.ruleSource(rule.Rule)
+		if bindErr != nil {
+			return fmt.Errorf("invalid SQL query for rule %s: %w", rule.Rule.Name, bindErr)
+		}
+		logrus.Infof("Executing SQL query: %s", query)
+		rows, transformed := 0, 0
+		err := s.forEachSourceBatch(ctx, rule.Rule, query, args, func(items []map[string]any) error {
+			items = s.excludeSoftDeleted(items)
+			rows += len(items)
+			if flusher == nil {
+				transformed += s.addNodeRows(rule, items, graphAggregate, graphMutex)
+				return nil
+			}
+			batch := graph.NewGraphAggregate("")
+			transformed += s.addNodeRows(rule, items, batch, &sync.Mutex{})
+			return flusher.storeNodes(ctx, batch)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return s.abortError(ctx, PhaseNodeRules, rule.Rule.Name, err)
+			}
+			return fmt.Errorf("error executing SQL query for rule %s: %v", rule.Rule.Name, err)
+		}
+		logrus.Infof("Data returned for node rule %s: %d records", rule.Rule.Name, rows)
+		logrus.Infof("Transformed %d records for node rule %s", transformed, rule.Rule.Name)
+		return nil
+	}
+
+	// Rule uses table data (legacy approach)
+	sourceTable := rule.Rule.SourceTable
+	logrus.Infof("Applying rule to table: %s", sourceTable)
+	items, ok := tableData[sourceTable]
+	if !ok {
+		items = []map[string]any{}
+	}
+	logrus.Infof("Data returned for node rule %s: %d records", rule.Rule.Name, len(items))
+	transformed := s.addNodeRows(rule, items, graphAggregate, graphMutex)
+	logrus.Infof("Transformed %d records for node rule %s", transformed, rule.Rule.Name)
+	return nil
+}
+
+// addNodeRows adds the nodes a node rule produces from items to the graph, holding
+// graphMutex while the graph is updated, and returns how many rows were transformed
+func (s *TransformService) addNodeRows(rule *transform_agg.RuleAggregate, items []map[string]any, graphAggregate *graph.GraphAggregate, graphMutex *sync.Mutex) int {
+	// Convert map properties to supported types before transformation
+	for i, item := range items {
+		items[i] = s.convertMapProperties(item)
+	}
+
+	// Apply transformation rules
+	transformedData := rule.ApplyRules(items)
+
+	// Add transformed data to graph
+	provenance := s.provenanceOf(rule)
+	graphMutex.Lock()
+	defer graphMutex.Unlock()
+	for _, item := range transformedData {
+		if mapItem, ok := item.(map[string]any); ok {
+			mapItem = s.convertMapProperties(mapItem)
+			tagProvenance(mapItem, provenance)
+			if err := s.updateGraph(mapItem, graphAggregate); err != nil {
+				logrus.Warnf("Warning updating graph for node rule %s: %v (continuing)", rule.Rule.Name, err)
+			}
+		} else {
+			logrus.Warnf("Unexpected data format for node rule %s: %T", rule.Rule.Name, item)
+		}
+	}
+	return len(transformedData)
+}
+
+// sourceQuery binds the rule's query parameters, resolving runtime values against this run
+func (s *TransformService) sourceQuery(rule transform.TransformRule) (string, []any, error) {
+	s.runMutex.Lock()
+	startedAt := s.progress.StartedAt
+	s.runMutex.Unlock()
+	return s.sourceQueryAt(rule, startedAt)
+}
+
+// sourceQueryAt binds the rule's query parameters with startedAt as the run time, returning
+// the query with a placeholder for every parameter and the values to pass with it
+func (s *TransformService) sourceQueryAt(rule transform.TransformRule, startedAt time.Time) (string, []any, error) {
+	if len(rule.SourceParams) == 0 {
+		return rule.SourceSQL, nil, nil
+	}
+	executor, ok := s.databasePort.(ports.ParameterizedQueryExecutor)
+	if !ok {
+		return "", nil, fmt.Errorf("query parameters need a database that accepts query arguments")
+	}
+
+	s.runMutex.Lock()
+	lastRun := s.lastRun
+	s.runMutex.Unlock()
+
+	if lastRun.IsZero() {
+		lastRun = time.Unix(0, 0)
+	}
+	return transform.BindQueryParams(rule.SourceSQL, rule.SourceParams, map[string]string{
+		transform.RuntimeParamNow:     startedAt.UTC().Format(time.DateTime),
+		transform.RuntimeParamLastRun: lastRun.UTC().Format(time.DateTime),
+	}, executor.QueryPlaceholder)
+}
+
+// executeQuery runs a source query, cancelling it with ctx when the port supports that
+func (s *TransformService) executeQuery(ctx context.Context, query string) ([]map[string]any, error) {
+	if executor, ok := s.databasePort.(ports.ContextQueryExecutor); ok {
+		return executor.ExecuteQueryWithContext(ctx, query)
+	}
+
+	var items []map[string]any
+	err := awaitWithContext(ctx, func() error {
+		var queryErr error
+		items, queryErr = s.databasePort.ExecuteQuery(query)
+		return queryErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *TransformService) storeGraph(ctx context.Context, graphAggregate *graph.GraphAggregate, rules []*transform_agg.RuleAggregate) error {
+	if s.reconcile {
+		return s.reconcileGraph(ctx, graphAggregate, rules)
+	}
+	if s.relationshipsOnly {
+		store, ok := s.neo4jPort.(ports.RelationshipGraphStore)
+		if !ok {
+			return fmt.Errorf("neo4j port does not support relationship-only writes")
+		}
+		return store.StoreRelationshipsInBatches(ctx, graphAggregate, s.recordCommit)
+	}
+	if store, ok := s.neo4jPort.(ports.BatchedGraphStore); ok {
+		return store.StoreGraphInBatches(ctx, graphAggregate, s.recordCommit)
+	}
+	if store, ok := s.neo4jPort.(ports.ContextGraphStore); ok {
+		return store.StoreGraphWithContext(ctx, graphAggregate)
+	}
+	return awaitWithContext(ctx, func() error {
+		return s.neo4jPort.StoreGraph(graphAggregate)
+	})
+}
+
+// excludeSoftDeleted drops rows marked as deleted by the soft-delete column
+func (s *TransformService) excludeSoftDeleted(items []map[string]any) []map[string]any {
+	if s.softDeleteColumn == "" {
+		return items
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if !s.isSoftDeleted(item) {
+			kept = append(kept, item)
+		}
+	}
+	if excluded := len(items) - len(kept); excluded > 0 {
+		logrus.Infof("Excluded %d soft-deleted rows (%s is set)", excluded, s.softDeleteColumn)
+	}
+	return kept
+}
+
+// isSoftDeleted reports whether the row's soft-delete column holds a value. NULL, empty
+// strings and MySQL zero dates count as not deleted.
+func (s *TransformService) isSoftDeleted(item map[string]any) bool {
+	if s.softDeleteColumn == "" {
+		return false
+	}
+	switch v := item[s.softDeleteColumn].(type) {
+	case nil:
+		return false
+	case []byte:
+		return !isZeroTimestamp(string(v))
+	case string:
+		return !isZeroTimestamp(v)
+	case time.Time:
+		return !v.IsZero()
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+func isZeroTimestamp(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || strings.HasPrefix(value, "0000-00-00")
+}
+
+// loadExistingNodes adds the stored nodes of every type a relationship rule links, so
+// relationships can be matched against them without running node rules
+func (s *TransformService) loadExistingNodes(rules []*transform_agg.RuleAggregate, graphAggregate *graph.GraphAggregate) error {
+	loaded := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Rule.RuleType != transform.RelationshipRule {
+			continue
+		}
+		for _, mapping := range []*transform.NodeMapping{rule.Rule.SourceNode, rule.Rule.TargetNode} {
+			if mapping == nil || mapping.Type == "" || loaded[mapping.Type] {
+				continue
+			}
+			loaded[mapping.Type] = true
+
+			nodes, err := s.neo4jPort.FetchNodes(mapping.Type)
+			if err != nil {
+				return fmt.Errorf("failed to load existing %s nodes: %w", mapping.Type, err)
+			}
+			for _, properties := range nodes {
+				if err := graphAggregate.AddNode(mapping.Type, properties); err != nil {
+					return fmt.Errorf("failed to add existing %s node: %w", mapping.Type, err)
+				}
+			}
+			logrus.Infof("Loaded %d existing %s nodes", len(nodes), mapping.Type)
+		}
+	}
+	return nil
+}
+
+// abortError wraps err in a TransformTimeoutError when ctx has ended, so callers learn
+// which phase was reached; other errors are returned unchanged
+func (s *TransformService) abortError(ctx context.Context, phase, rule string, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	cause := context.Cause(ctx)
+	logrus.Errorf("Transform aborted while %s: %v", phase, cause)
+	return &TransformTimeoutError{
+		Phase:   phase,
+		Rule:    rule,
+		Timeout: s.timeout,
+		Err:     cause,
+	}
+}
+
+func (s *TransformService) beginRun(cancel context.CancelCauseFunc) error {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+
+	if s.cancel != nil {
+		return ErrTransformRunning
+	}
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.progress = TransformProgress{RunID: serialization.GenerateUniqueID(), Running: true, StartedAt: time.Now()}
+	return nil
+}
+
+func (s *TransformService) finishRun(ctx context.Context, err error) {
+	if err == nil {
+		s.saveLastRun(s.Progress().StartedAt)
+	}
+
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+
+	finishedAt := time.Now()
+	s.progress.Running = false
+	s.progress.FinishedAt = &finishedAt
+	s.progress.Cancelled = errors.Is(context.Cause(ctx), ErrTransformCancelled)
+	if err != nil {
+		s.progress.Error = err.Error()
+	} else {
+		s.lastRun = s.progress.StartedAt
+	}
+
+	close(s.done)
+	s.cancel = nil
+}
+
+func (s *TransformService) setPhase(phase, rule string) {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	s.progress.Phase = phase
+	s.progress.Rule = rule
+}
+
+func (s *TransformService) recordCommit(committed ports.GraphWriteProgress) {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	s.progress.GraphWriteProgress = committed
+}
+
+// awaitWithContext runs fn and returns ctx's error if ctx ends first. Ports without
+// context support cannot be interrupted, so fn is left to finish in the background.
+func awaitWithContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (s *TransformService) updateGraph(data any, graph *graph.GraphAggregate) error {
@@ -211,9 +865,6 @@ func (s *TransformService) updateGraph(data any, graph *graph.GraphAggregate) er
 	return fmt.Errorf("invalid transform result format")
 }
 
-// Define a maximum length for text properties
-const maxTextLength = 10000
-
 func (s *TransformService) createNode(nodeType string, data map[string]any, graph *graph.GraphAggregate) error {
 	if _, hasID := data["id"]; !hasID {
 		return fmt.Errorf("node data missing required 'id' field")
@@ -222,20 +873,29 @@ func (s *TransformService) createNode(nodeType string, data map[string]any, grap
 		return fmt.Errorf("node data missing required 'name' field")
 	}
 
+	mergeKeys, _ := data[transform_agg.MergeKeysField].([]string)
+	delete(data, transform_agg.MergeKeysField)
+	strategies, _ := data[transform_agg.MergeStrategiesField].(map[string]transform.MergeStrategy)
+	delete(data, transform_agg.MergeStrategiesField)
+	labels, _ := data[transform_agg.LabelsField].([]string)
+	delete(data, transform_agg.LabelsField)
+
+	s.normalizeTimestamps(data)
 	for key, value := range data {
 		logrus.Infof("Key: %s, Value: %v, Type: %T", key, value, value)
 		switch v := value.(type) {
 		case []byte:
 			data[key] = string(v)
 		case string:
-			if len(v) > maxTextLength {
-				logrus.Warnf("Truncating long string for key %s to %d characters", key, maxTextLength)
-				data[key] = v[:maxTextLength]
-			}
+			// Text length is capped per rule when the rule is applied
 		case int64:
 			data[key] = fmt.Sprintf("%d", v)
-		case int, float64, bool:
-			// Primitive types are fine
+		case int, float64, bool, []string:
+			// Primitive types and string lists (e.g. the truncation marker) are fine
+		case time.Time:
+			if s.timezone == nil {
+				data[key] = fmt.Sprintf("%v", v)
+			}
 		case map[string]any:
 			logrus.Warnf("Converting map to string for key %s", key)
 			data[key] = fmt.Sprintf("%v", v)
@@ -249,7 +909,27 @@ func (s *TransformService) createNode(nodeType string, data map[string]any, grap
 
 	delete(data, "_type")
 	logrus.Infof("Saving node to graph: type=%s, data=%+v", nodeType, data)
-	return graph.AddNode(nodeType, data)
+	var keys map[string]any
+	if len(mergeKeys) > 0 {
+		// Key values are taken after conversion so they match the stored properties
+		keys = make(map[string]any, len(mergeKeys))
+		for _, property := range mergeKeys {
+			keys[property] = data[property]
+		}
+	}
+	var err error
+	switch {
+	case len(strategies) > 0:
+		err = graph.MergeNode(nodeType, data, strategies, keys)
+	case keys != nil:
+		err = graph.AddNodeWithMergeKeys(nodeType, data, keys)
+	default:
+		err = graph.AddNode(nodeType, data)
+	}
+	if err != nil || len(labels) == 0 {
+		return err
+	}
+	return graph.AddNodeLabels(nodeType, data["id"], labels)
 }
 
 func (s *TransformService) createRelationship(data map[string]any, graph *graph.GraphAggregate) error {
@@ -263,66 +943,29 @@ func (s *TransformService) createRelationship(data map[string]any, graph *graph.
 		return fmt.Errorf("relationship missing _direction field")
 	}
 
-	// Handle source field - might be map or JSON string
-	var source map[string]any
-	if sourceRaw, exists := data["source"]; exists {
-		if sourceMap, ok := sourceRaw.(map[string]any); ok {
-			source = sourceMap
-		} else if sourceStr, ok := sourceRaw.(string); ok {
-			// Try to parse JSON string
-			var sourceJSON map[string]any
-			if err := json.Unmarshal([]byte(sourceStr), &sourceJSON); err == nil {
-				source = sourceJSON
-			} else {
-				return fmt.Errorf("failed to parse source JSON: %v", err)
-			}
-		} else {
-			return fmt.Errorf("source field has invalid type: %T", sourceRaw)
-		}
-	} else {
+	source, err := relationshipField(data, "source")
+	if err != nil {
+		return err
+	}
+	if source == nil {
 		return fmt.Errorf("relationship missing source field")
 	}
-
-	// Handle target field - might be map or JSON string
-	var target map[string]any
-	if targetRaw, exists := data["target"]; exists {
-		if targetMap, ok := targetRaw.(map[string]any); ok {
-			target = targetMap
-		} else if targetStr, ok := targetRaw.(string); ok {
-			// Try to parse JSON string
-			var targetJSON map[string]any
-			if err := json.Unmarshal([]byte(targetStr), &targetJSON); err == nil {
-				target = targetJSON
-			} else {
-				return fmt.Errorf("failed to parse target JSON: %v", err)
-			}
-		} else {
-			return fmt.Errorf("target field has invalid type: %T", targetRaw)
-		}
-	} else {
+	target, err := relationshipField(data, "target")
+	if err != nil {
+		return err
+	}
+	if target == nil {
 		return fmt.Errorf("relationship missing target field")
 	}
-
-	// Handle properties field - might be map or JSON string
-	var properties map[string]any
-	if propRaw, exists := data["properties"]; exists {
-		if propMap, ok := propRaw.(map[string]any); ok {
-			properties = propMap
-		} else if propStr, ok := propRaw.(string); ok {
-			// Try to parse JSON string
-			var propJSON map[string]any
-			if err := json.Unmarshal([]byte(propStr), &propJSON); err == nil {
-				properties = propJSON
-			} else {
-				return fmt.Errorf("failed to parse properties JSON: %v", err)
-			}
-		} else {
-			return fmt.Errorf("properties field has invalid type: %T", propRaw)
-		}
-	} else {
-		// Properties are optional
+	// Properties are optional
+	properties, err := relationshipField(data, "properties")
+	if err != nil {
+		return err
+	}
+	if properties == nil {
 		properties = make(map[string]any)
 	}
+	s.normalizeTimestamps(properties)
 
 	logrus.Infof("Saving relationship to graph: type=%s, direction=%s, source=%+v, target=%+v, properties=%+v", relType, direction, source, target, properties)
 
@@ -344,7 +987,49 @@ func (s *TransformService) createRelationship(data map[string]any, graph *graph.
 		return fmt.Errorf("target missing field")
 	}
 
-	return graph.AddRelationship(
+	keyMatch, _ := data["_key_match"].(*transform.KeyMatch)
+
+	for _, endpoint := range []map[string]any{source, target} {
+		if bucket, _ := endpoint["bucket"].(bool); bucket {
+			if err := addUnknownNode(graph, endpoint["type"].(string)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if identity, _ := data["_relationship_identity"].(*transform.RelationshipIdentity); identity != nil {
+		return graph.MergeRelationship(
+			relType,
+			direction,
+			sourceType,
+			source["key"],
+			sourceField,
+			targetType,
+			target["key"],
+			targetField,
+			properties,
+			keyMatch,
+			*identity,
+		)
+	}
+
+	if weightProperty, _ := data["_weight_property"].(string); weightProperty != "" {
+		return graph.AddWeightedRelationship(
+			relType,
+			direction,
+			sourceType,
+			source["key"],
+			sourceField,
+			targetType,
+			target["key"],
+			targetField,
+			properties,
+			keyMatch,
+			weightProperty,
+		)
+	}
+
+	return graph.AddRelationshipWithKeyMatch(
 		relType,
 		direction,
 		sourceType,
@@ -354,9 +1039,40 @@ func (s *TransformService) createRelationship(data map[string]any, graph *graph.
 		target["key"],
 		targetField,
 		properties,
+		keyMatch,
 	)
 }
 
+// relationshipField reads the source, target or properties of a transformed relationship,
+// which may be a map or a JSON string; it returns nil when the field is missing
+func relationshipField(data map[string]any, field string) (map[string]any, error) {
+	raw, exists := data[field]
+	if !exists {
+		return nil, nil
+	}
+	switch value := raw.(type) {
+	case map[string]any:
+		return value, nil
+	case string:
+		var parsed map[string]any
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse %s JSON: %v", field, err)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("%s field has invalid type: %T", field, raw)
+	}
+}
+
+// addUnknownNode adds the node that relationships with a NULL key of nodeType are linked
+// to; adding it again leaves a single node
+func addUnknownNode(graph *graph.GraphAggregate, nodeType string) error {
+	return graph.AddNode(nodeType, map[string]any{
+		"id":   transform.UnknownNodeID,
+		"name": "Unknown " + nodeType,
+	})
+}
+
 // Create relationships from existing nodes in the graph based on rule definitions
 func (s *TransformService) createRelationshipsFromExistingNodes(rule *transform_agg.RuleAggregate, graph *graph.GraphAggregate) error {
 	if rule.Rule.SourceNode == nil || rule.Rule.TargetNode == nil {
@@ -369,6 +1085,8 @@ func (s *TransformService) createRelationshipsFromExistingNodes(rule *transform_
 	logrus.Infof("Target node type: %s, key: %s, target_field: %s",
 		rule.Rule.TargetNode.Type, rule.Rule.TargetNode.Key, rule.Rule.TargetNode.TargetField)
 
+	provenance := s.provenanceOf(rule)
+
 	// Get all existing nodes
 	existingNodes := graph.GetNodes()
 	logrus.Infof("Found %d existing nodes in graph", len(existingNodes))
@@ -397,6 +1115,19 @@ func (s *TransformService) createRelationshipsFromExistingNodes(rule *transform_
 			continue
 		}
 
+		// Rules typing relationships by a column read it from the source node's properties
+		relType := rule.Rule.RelationType
+		if rule.Rule.RelationTypeFromColumn != "" {
+			value := sourceNode.Properties[rule.Rule.RelationTypeFromColumn]
+			resolved, ok := rule.Rule.ResolveRelationType(value)
+			if !ok {
+				logrus.Warnf("Skipping source node %s in rule %s: %s value %v is not an allowed relationship type",
+					sourceNode.ID, rule.Rule.Name, rule.Rule.RelationTypeFromColumn, value)
+				continue
+			}
+			relType = resolved
+		}
+
 		for _, targetNode := range targetNodes {
 			// Get the key value from target node
 			targetKeyValue, exists := targetNode.Properties[rule.Rule.TargetNode.Key]
@@ -405,13 +1136,16 @@ func (s *TransformService) createRelationshipsFromExistingNodes(rule *transform_
 				continue
 			}
 
-			// Check if keys match (convert to strings for comparison)
-			sourceKeyStr := fmt.Sprintf("%v", sourceKeyValue)
-			targetKeyStr := fmt.Sprintf("%v", targetKeyValue)
+			// Check if keys match (normalized to strings for comparison)
+			sourceKeyStr := rule.Rule.KeyMatch.Normalize(sourceKeyValue)
+			targetKeyStr := rule.Rule.KeyMatch.Normalize(targetKeyValue)
 
 			if sourceKeyStr == targetKeyStr {
 				// Create relationship properties
 				properties := make(map[string]any)
+				for key, value := range provenance {
+					properties[key] = value
+				}
 				for srcProp, tgtProp := range rule.Rule.Properties {
 					if value, exists := sourceNode.Properties[srcProp]; exists {
 						properties[tgtProp] = value
@@ -422,19 +1156,19 @@ func (s *TransformService) createRelationshipsFromExistingNodes(rule *transform_
 
 				// Add the relationship
 				err := graph.AddDirectRelationship(
-					rule.Rule.RelationType,
+					relType,
 					sourceNode.ID,
 					targetNode.ID,
 					properties,
 				)
 				if err != nil {
 					logrus.Warnf("Failed to create relationship %s between %s and %s: %v",
-						rule.Rule.RelationType, sourceNode.ID, targetNode.ID, err)
+						relType, sourceNode.ID, targetNode.ID, err)
 					continue
 				}
 				relationshipCount++
 				logrus.Infof("Created relationship %s: %s(%s) -> %s(%s)",
-					rule.Rule.RelationType, sourceNode.Type, sourceKeyStr, targetNode.Type, targetKeyStr)
+					relType, sourceNode.Type, sourceKeyStr, targetNode.Type, targetKeyStr)
 			}
 		}
 	}
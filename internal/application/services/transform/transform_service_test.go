@@ -0,0 +1,1062 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	transform_agg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+type fakeDatabasePort struct {
+	rows    []map[string]any
+	queries map[string][]map[string]any
+}
+
+func (f *fakeDatabasePort) FetchData() ([]map[string]any, error) { return f.rows, nil }
+func (f *fakeDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) {
+	if rows, ok := f.queries[query]; ok {
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+func (f *fakeDatabasePort) Close() error { return nil }
+
+type fakeNeo4jPort struct {
+	stored *graph.GraphAggregate
+}
+
+func (f *fakeNeo4jPort) StoreGraph(g *graph.GraphAggregate) error {
+	f.stored = g
+	return nil
+}
+func (f *fakeNeo4jPort) SearchNodes(criteria string) ([]*graph.GraphAggregate, error) {
+	return nil, nil
+}
+func (f *fakeNeo4jPort) ExportGraph(query string) (any, error) { return nil, nil }
+func (f *fakeNeo4jPort) FetchNodes(nodeType string) ([]map[string]any, error) {
+	return nil, nil
+}
+func (f *fakeNeo4jPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+func (f *fakeNeo4jPort) Close() error { return nil }
+
+type fakeRuleRepository struct {
+	rules []*transform_agg.RuleAggregate
+}
+
+func (f *fakeRuleRepository) GetAllRules(ctx context.Context) ([]*transform_agg.RuleAggregate, error) {
+	return f.rules, nil
+}
+func (f *fakeRuleRepository) SaveRule(ctx context.Context, rule *transform_agg.RuleAggregate) error {
+	f.rules = append(f.rules, rule)
+	return nil
+}
+func (f *fakeRuleRepository) DeleteRule(ctx context.Context, ruleID string) error { return nil }
+func (f *fakeRuleRepository) UpdateRulePriority(ctx context.Context, ruleID string, priority int) error {
+	return nil
+}
+
+func nodeRule(name, table, targetType string) *transform_agg.RuleAggregate {
+	return &transform_agg.RuleAggregate{
+		Name: name,
+		Rule: transform.TransformRule{
+			Name:          name,
+			SourceTable:   table,
+			RuleType:      transform.NodeRule,
+			TargetType:    targetType,
+			FieldMappings: map[string]string{"id": "id", "name": "name"},
+		},
+	}
+}
+
+func enrollmentRule(properties map[string]string) *transform_agg.RuleAggregate {
+	return &transform_agg.RuleAggregate{
+		Name: "enrollments",
+		Rule: transform.TransformRule{
+			Name:         "enrollments",
+			SourceTable:  "enrollments",
+			RuleType:     transform.RelationshipRule,
+			RelationType: "ENROLLED_IN",
+			Direction:    transform.Outgoing,
+			SourceNode:   &transform.NodeMapping{Type: "Student", Key: "student_id", TargetField: "id"},
+			TargetNode:   &transform.NodeMapping{Type: "Course", Key: "course_id", TargetField: "id"},
+			Properties:   properties,
+		},
+	}
+}
+
+func newEnrollmentFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "students", "id": int64(1), "name": "Ada"},
+		{"_table": "students", "id": int64(2), "name": "Linus"},
+		{"_table": "courses", "id": int64(10), "name": "Databases"},
+		{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "A", "enrolled_at": "2025-09-01"},
+		{"_table": "enrollments", "student_id": int64(2), "course_id": int64(10), "grade": "B", "enrolled_at": "2025-09-03"},
+	}}
+}
+
+func TestTransformAndStore_JunctionColumnsBecomeRelationshipProperties(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(nil),
+	}}
+
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.NotNil(t, neo4j.stored)
+
+	relationships := neo4j.stored.GetRelationships()
+	require.Len(t, relationships, 2)
+
+	grades := make(map[string]map[string]any)
+	for _, rel := range relationships {
+		assert.Equal(t, "ENROLLED_IN", rel.Type)
+		assert.Equal(t, "Student", rel.SourceNode.Type)
+		assert.Equal(t, "Course", rel.TargetNode.Type)
+		grades[fmt.Sprintf("%v", rel.SourceNode.Properties["name"])] = rel.Properties
+	}
+
+	assert.Equal(t, map[string]any{"grade": "A", "enrolled_at": "2025-09-01"}, grades["Ada"])
+	assert.Equal(t, map[string]any{"grade": "B", "enrolled_at": "2025-09-03"}, grades["Linus"])
+}
+
+func TestTransformAndStore_JunctionPropertiesCanBeRenamed(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("students", "students", "Student"),
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(map[string]string{"enrolled_at": "since"}),
+	}}
+
+	service := NewTransformService(newEnrollmentFixture(), neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	relationships := neo4j.stored.GetRelationships()
+	require.NotEmpty(t, relationships)
+	for _, rel := range relationships {
+		assert.Contains(t, rel.Properties, "grade")
+		assert.Contains(t, rel.Properties, "since")
+		assert.NotContains(t, rel.Properties, "enrolled_at")
+		assert.NotContains(t, rel.Properties, "student_id")
+		assert.NotContains(t, rel.Properties, "_table")
+	}
+}
+
+// slowDatabasePort simulates a stuck source query on a port without context support
+type slowDatabasePort struct {
+	fakeDatabasePort
+	delay time.Duration
+}
+
+func (p *slowDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) {
+	time.Sleep(p.delay)
+	return nil, nil
+}
+
+// cancellableDatabasePort blocks until its query context is cancelled
+type cancellableDatabasePort struct {
+	fakeDatabasePort
+	cancelled chan error
+}
+
+func (p *cancellableDatabasePort) ExecuteQueryWithContext(ctx context.Context, query string) ([]map[string]any, error) {
+	<-ctx.Done()
+	p.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func slowQueryRule() *transform_agg.RuleAggregate {
+	return &transform_agg.RuleAggregate{
+		Name: "slow_users",
+		Rule: transform.TransformRule{
+			Name:       "slow_users",
+			SourceSQL:  "SELECT SLEEP(3600)",
+			RuleType:   transform.NodeRule,
+			TargetType: "User",
+		},
+	}
+}
+
+func TestTransformAndStore_AbortsAtTimeout(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{slowQueryRule()}}
+
+	service := NewTransformService(&slowDatabasePort{delay: time.Hour}, neo4j, rules)
+	service.SetTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	err := service.TransformAndStore(context.Background())
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.Less(t, elapsed, time.Second, "transform must return at the deadline")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var timeoutErr *TransformTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, PhaseNodeRules, timeoutErr.Phase)
+	assert.Equal(t, "slow_users", timeoutErr.Rule)
+	assert.Contains(t, err.Error(), "timed out after 50ms")
+	assert.Nil(t, neo4j.stored, "nothing is stored after a timeout")
+}
+
+func TestTransformAndStore_TimeoutCancelsInFlightQuery(t *testing.T) {
+	db := &cancellableDatabasePort{cancelled: make(chan error, 1)}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{slowQueryRule()}}
+
+	service := NewTransformService(db, &fakeNeo4jPort{}, rules)
+	service.SetTimeout(20 * time.Millisecond)
+
+	err := service.TransformAndStore(context.Background())
+	require.Error(t, err)
+
+	select {
+	case cause := <-db.cancelled:
+		assert.ErrorIs(t, cause, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("source query context was not cancelled")
+	}
+}
+
+func documentRule(maxTextLength int, includeBinary bool) *transform_agg.RuleAggregate {
+	return &transform_agg.RuleAggregate{
+		Name: "documents",
+		Rule: transform.TransformRule{
+			Name:          "documents",
+			SourceTable:   "documents",
+			RuleType:      transform.NodeRule,
+			TargetType:    "Document",
+			FieldMappings: map[string]string{"id": "id", "title": "name", "body": "body", "thumbnail": "thumbnail"},
+			MaxTextLength: maxTextLength,
+			IncludeBinary: includeBinary,
+		},
+	}
+}
+
+func newDocumentFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{rows: []map[string]any{
+		{
+			"_table":    "documents",
+			"id":        int64(1),
+			"title":     "Quarterly report",
+			"body":      strings.Repeat("lorem ipsum ", 100),
+			"thumbnail": []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a},
+		},
+	}}
+}
+
+func TestTransformAndStore_OmitsBinaryAndTruncatesText(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{documentRule(64, false)}}
+
+	service := NewTransformService(newDocumentFixture(), neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	nodes := neo4j.stored.GetNodes()
+	require.Len(t, nodes, 1)
+	props := nodes[0].Properties
+
+	assert.NotContains(t, props, "thumbnail", "BLOB columns are omitted by default")
+	assert.Len(t, props["body"], 64)
+	assert.Equal(t, []string{"body"}, props[transform.TruncatedMarker])
+	assert.Equal(t, "Quarterly report", props["name"], "short text is untouched")
+}
+
+func TestTransformAndStore_IncludeBinaryAndDefaultLimit(t *testing.T) {
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{documentRule(0, true)}}
+
+	service := NewTransformService(newDocumentFixture(), neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	props := neo4j.stored.GetNodes()[0].Properties
+	assert.Equal(t, "iVBORw0KGgo=", props["thumbnail"], "included binary is base64 encoded")
+	assert.Len(t, props["body"], 1200, "body is below the default limit")
+	assert.NotContains(t, props, transform.TruncatedMarker)
+}
+
+// batchingNeo4jPort commits nodes in batches like the Neo4j repository and holds after the
+// first commit until the transform is cancelled; nodes of an unfinished batch are discarded
+type batchingNeo4jPort struct {
+	fakeNeo4jPort
+	batchSize int
+	committed []string
+	commits   chan ports.GraphWriteProgress
+}
+
+func (p *batchingNeo4jPort) StoreGraphInBatches(ctx context.Context, g *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
+	var pending []string
+	var progress ports.GraphWriteProgress
+
+	for _, node := range g.GetNodes() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pending = append(pending, fmt.Sprint(node.Properties["name"]))
+		if len(pending) < p.batchSize {
+			continue
+		}
+
+		p.committed = append(p.committed, pending...)
+		pending = nil
+		progress.NodesWritten += p.batchSize
+		progress.BatchesCommitted++
+		onCommit(progress)
+
+		p.commits <- progress
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+
+	p.committed = append(p.committed, pending...)
+	return nil
+}
+
+func newStudentFixture(count int) *fakeDatabasePort {
+	rows := make([]map[string]any, 0, count)
+	for i := 1; i <= count; i++ {
+		rows = append(rows, map[string]any{"_table": "students", "id": int64(i), "name": fmt.Sprintf("student-%d", i)})
+	}
+	return &fakeDatabasePort{rows: rows}
+}
+
+func TestCancel_KeepsLastCommittedBatch(t *testing.T) {
+	neo4j := &batchingNeo4jPort{batchSize: 2, commits: make(chan ports.GraphWriteProgress, 1)}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("students", "students", "Student")}}
+	service := NewTransformService(newStudentFixture(5), neo4j, rules)
+
+	result := make(chan error, 1)
+	go func() { result <- service.TransformAndStore(context.Background()) }()
+
+	select {
+	case <-neo4j.commits:
+	case <-time.After(time.Second):
+		t.Fatal("first batch was not committed")
+	}
+
+	progress, err := service.Cancel(context.Background())
+	require.NoError(t, err)
+	assert.False(t, progress.Running)
+	assert.True(t, progress.Cancelled)
+	assert.Equal(t, PhaseStoreGraph, progress.Phase)
+	assert.Equal(t, 2, progress.NodesWritten)
+	assert.Equal(t, 1, progress.BatchesCommitted)
+
+	err = <-result
+	assert.ErrorIs(t, err, ErrTransformCancelled)
+	assert.Len(t, neo4j.committed, 2, "only the committed batch remains")
+	assert.Equal(t, progress, service.Progress())
+}
+
+func TestCancel_StopsSourceQuery(t *testing.T) {
+	db := &cancellableDatabasePort{cancelled: make(chan error, 1)}
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{slowQueryRule(), nodeRule("students", "students", "Student")}}
+	service := NewTransformService(db, neo4j, rules)
+
+	result := make(chan error, 1)
+	go func() { result <- service.TransformAndStore(context.Background()) }()
+
+	require.Eventually(t, func() bool {
+		return service.Progress().Rule == "slow_users"
+	}, time.Second, 5*time.Millisecond)
+
+	progress, err := service.Cancel(context.Background())
+	require.NoError(t, err)
+	assert.True(t, progress.Cancelled)
+	assert.ErrorIs(t, <-db.cancelled, context.Canceled)
+
+	err = <-result
+	assert.ErrorIs(t, err, ErrTransformCancelled)
+	var abortErr *TransformTimeoutError
+	require.ErrorAs(t, err, &abortErr)
+	assert.Equal(t, "slow_users", abortErr.Rule)
+	assert.Nil(t, neo4j.stored, "no later rule runs and nothing is stored")
+}
+
+func TestCancel_WithoutActiveTransform(t *testing.T) {
+	service := NewTransformService(&fakeDatabasePort{}, &fakeNeo4jPort{}, &fakeRuleRepository{})
+
+	_, err := service.Cancel(context.Background())
+	assert.ErrorIs(t, err, ErrNoActiveTransform)
+}
+
+// recordingDatabasePort remembers the source queries it was asked to run, and their arguments
+type recordingDatabasePort struct {
+	fakeDatabasePort
+	executed []string
+	args     [][]any
+}
+
+func (p *recordingDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) {
+	p.executed = append(p.executed, query)
+	return []map[string]any{{"id": int64(1), "name": "order"}}, nil
+}
+
+func (p *recordingDatabasePort) QueryPlaceholder(int) string { return "?" }
+
+func (p *recordingDatabasePort) ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	p.args = append(p.args, args)
+	return p.ExecuteQuery(query)
+}
+
+func TestTransformAndStore_BindsQueryParametersAtRunTime(t *testing.T) {
+	rule := nodeRule("recent_orders", "", "Order")
+	rule.Rule.SourceSQL = "SELECT id, name FROM orders WHERE status = :status AND total > :min_total AND updated_at > :since"
+	rule.Rule.SourceParams = map[string]string{"status": "paid", "min_total": "100", "since": transform.RuntimeParamLastRun}
+
+	db := &recordingDatabasePort{}
+	service := NewTransformService(db, &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	firstRun := service.Progress().StartedAt
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	require.Len(t, db.executed, 2)
+	assert.Equal(t, "SELECT id, name FROM orders WHERE status = ? AND total > ? AND updated_at > ?", db.executed[0])
+	assert.Equal(t, []any{"paid", "100", "1970-01-01 00:00:00"}, db.args[0])
+	assert.Equal(t, firstRun.UTC().Format(time.DateTime), db.args[1][2], "second run only fetches rows changed since the first")
+}
+
+func TestTransformAndStore_QueryParametersNeverBecomeSQL(t *testing.T) {
+	injection := `paid\' OR 1=1 -- `
+	rule := nodeRule("orders", "", "Order")
+	rule.Rule.SourceSQL = "SELECT id, name FROM orders WHERE status = :status"
+	rule.Rule.SourceParams = map[string]string{"status": injection}
+
+	db := &recordingDatabasePort{}
+	service := NewTransformService(db, &fakeNeo4jPort{}, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	require.Len(t, db.executed, 1)
+	assert.Equal(t, "SELECT id, name FROM orders WHERE status = ?", db.executed[0])
+	assert.Equal(t, []any{injection}, db.args[0])
+}
+
+func TestTransformAndStore_LastRunSurvivesRestart(t *testing.T) {
+	rule := nodeRule("recent_orders", "", "Order")
+	rule.Rule.SourceSQL = "SELECT id, name FROM orders WHERE updated_at > :since"
+	rule.Rule.SourceParams = map[string]string{"since": transform.RuntimeParamLastRun}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}}
+	statePath := filepath.Join(t.TempDir(), "last_run.json")
+
+	store, err := NewFileRunStore(statePath)
+	require.NoError(t, err)
+	first := NewTransformService(&recordingDatabasePort{}, &fakeNeo4jPort{}, rules)
+	require.NoError(t, first.SetRunStore(store))
+	require.NoError(t, first.TransformAndStore(context.Background()))
+	firstRun := first.Progress().StartedAt
+
+	// A new process reads the last run back from the state file
+	store, err = NewFileRunStore(statePath)
+	require.NoError(t, err)
+	db := &recordingDatabasePort{}
+	restarted := NewTransformService(db, &fakeNeo4jPort{}, rules)
+	require.NoError(t, restarted.SetRunStore(store))
+	require.NoError(t, restarted.TransformAndStore(context.Background()))
+
+	require.Len(t, db.args, 1)
+	assert.Equal(t, []any{firstRun.UTC().Format(time.DateTime)}, db.args[0])
+}
+
+func newUserEnteredKeysFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "students", "id": "ADA-1", "name": "Ada"},
+		{"_table": "courses", "id": "db-101", "name": "Databases"},
+		{"_table": "enrollments", "student_id": "ada-1 ", "course_id": "DB-101"},
+	}}
+}
+
+func TestTransformAndStore_KeyMatchNormalizesBothSides(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyMatch *transform.KeyMatch
+		want     int
+	}{
+		{name: "exact match by default", keyMatch: nil, want: 0},
+		{name: "trim and ignore case", keyMatch: &transform.KeyMatch{Trim: true, CaseInsensitive: true}, want: 1},
+		{name: "case-insensitive pad space collation", keyMatch: &transform.KeyMatch{Collation: "utf8mb4_general_ci"}, want: 1},
+		{name: "no pad collation keeps trailing space", keyMatch: &transform.KeyMatch{Collation: "utf8mb4_0900_ai_ci"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollments := enrollmentRule(nil)
+			enrollments.Rule.KeyMatch = tt.keyMatch
+
+			neo4j := &fakeNeo4jPort{}
+			rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+				nodeRule("students", "students", "Student"),
+				nodeRule("courses", "courses", "Course"),
+				enrollments,
+			}}
+
+			service := NewTransformService(newUserEnteredKeysFixture(), neo4j, rules)
+			require.NoError(t, service.TransformAndStore(context.Background()))
+			assert.Len(t, neo4j.stored.GetRelationships(), tt.want)
+		})
+	}
+}
+
+func TestTransformAndStore_ArrayColumnCreatesRelationshipPerElement(t *testing.T) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "tags", "id": int64(1), "name": "go"},
+		{"_table": "tags", "id": int64(2), "name": "sql"},
+		{"_table": "tags", "id": int64(3), "name": "graphs"},
+		{"_table": "tags", "id": int64(4), "name": "unused"},
+		{"_table": "posts", "id": int64(100), "name": "Hello", "tag_ids": []byte("{1,2,3}")},
+	}}
+	tagged := &transform_agg.RuleAggregate{
+		Name: "post_tags",
+		Rule: transform.TransformRule{
+			Name:         "post_tags",
+			SourceTable:  "posts",
+			RuleType:     transform.RelationshipRule,
+			RelationType: "TAGGED",
+			Direction:    transform.Outgoing,
+			SourceNode:   &transform.NodeMapping{Type: "Post", Key: "id", TargetField: "id"},
+			TargetNode:   &transform.NodeMapping{Type: "Tag", Key: "tag_ids", TargetField: "id", Array: true},
+		},
+	}
+
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("tags", "tags", "Tag"),
+		nodeRule("posts", "posts", "Post"),
+		tagged,
+	}}
+
+	service := NewTransformService(db, neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	relationships := neo4j.stored.GetRelationships()
+	require.Len(t, relationships, 3)
+
+	var tags []string
+	for _, rel := range relationships {
+		assert.Equal(t, "TAGGED", rel.Type)
+		assert.Equal(t, "Hello", rel.SourceNode.Properties["name"])
+		assert.Equal(t, "Tag", rel.TargetNode.Type)
+		tags = append(tags, fmt.Sprintf("%v", rel.TargetNode.Properties["name"]))
+	}
+	assert.ElementsMatch(t, []string{"go", "sql", "graphs"}, tags)
+}
+
+// storedNodesPort serves nodes already stored in Neo4j and records relationship-only writes
+type storedNodesPort struct {
+	fakeNeo4jPort
+	nodes              map[string][]map[string]any
+	relationshipsGraph *graph.GraphAggregate
+}
+
+func (p *storedNodesPort) FetchNodes(nodeType string) ([]map[string]any, error) {
+	return p.nodes[nodeType], nil
+}
+
+func (p *storedNodesPort) StoreRelationshipsInBatches(ctx context.Context, g *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
+	p.relationshipsGraph = g
+	return nil
+}
+
+func TestTransformAndStore_RelationshipsOnlyLinksExistingNodes(t *testing.T) {
+	neo4j := &storedNodesPort{nodes: map[string][]map[string]any{
+		"Student": {{"id": "1", "name": "Ada", "email": "ada@example.com"}},
+		"Course":  {{"id": "10", "name": "Databases"}},
+	}}
+	studentRule := nodeRule("students", "students", "Student")
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		studentRule,
+		nodeRule("courses", "courses", "Course"),
+		enrollmentRule(nil),
+	}}
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "students", "id": int64(1), "name": "Renamed"},
+		{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "A"},
+	}}
+
+	service := NewTransformService(db, neo4j, rules)
+	service.SetRelationshipsOnly(true)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Nil(t, neo4j.stored, "nodes are not rewritten")
+	require.NotNil(t, neo4j.relationshipsGraph)
+
+	relationships := neo4j.relationshipsGraph.GetRelationships()
+	require.Len(t, relationships, 1)
+	assert.Equal(t, "ENROLLED_IN", relationships[0].Type)
+	assert.Equal(t, map[string]any{"id": "1", "name": "Ada", "email": "ada@example.com"}, relationships[0].SourceNode.Properties,
+		"source data for node rules is ignored")
+	assert.Equal(t, "Databases", relationships[0].TargetNode.Properties["name"])
+}
+
+func TestTransformAndStore_RelationshipsOnlyRequiresRelationshipStore(t *testing.T) {
+	service := NewTransformService(&fakeDatabasePort{}, &fakeNeo4jPort{}, &fakeRuleRepository{})
+	service.SetRelationshipsOnly(true)
+	assert.ErrorContains(t, service.TransformAndStore(context.Background()), "relationship-only writes")
+}
+
+func newSoftDeleteFixture() *fakeDatabasePort {
+	return &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "students", "id": int64(1), "name": "Ada", "deleted_at": nil},
+		{"_table": "students", "id": int64(2), "name": "Linus", "deleted_at": []byte("2025-06-01 10:00:00")},
+		{"_table": "students", "id": int64(3), "name": "Grace", "deleted_at": []byte("0000-00-00 00:00:00")},
+		{"_table": "students", "id": int64(4), "name": "Barbara", "removed_on": time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}}
+}
+
+func storedNames(g *graph.GraphAggregate) []string {
+	var names []string
+	for _, node := range g.GetNodes() {
+		names = append(names, fmt.Sprintf("%v", node.Properties["name"]))
+	}
+	return names
+}
+
+func TestTransformAndStore_SoftDeletedRowsExcludedWhenEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		column string
+		want   []string
+	}{
+		{name: "filtering disabled", column: "", want: []string{"Ada", "Linus", "Grace", "Barbara"}},
+		{name: "deleted_at", column: "deleted_at", want: []string{"Ada", "Grace", "Barbara"}},
+		{name: "custom column", column: "removed_on", want: []string{"Ada", "Linus", "Grace"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			neo4j := &fakeNeo4jPort{}
+			rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{nodeRule("students", "students", "Student")}}
+
+			service := NewTransformService(newSoftDeleteFixture(), neo4j, rules)
+			service.SetSoftDeleteColumn(tt.column)
+			require.NoError(t, service.TransformAndStore(context.Background()))
+
+			assert.ElementsMatch(t, tt.want, storedNames(neo4j.stored))
+		})
+	}
+}
+
+func TestTransformAndStore_SoftDeleteAppliesToQueryRules(t *testing.T) {
+	query := "SELECT id, name, deleted_at FROM students"
+	db := &fakeDatabasePort{queries: map[string][]map[string]any{query: {
+		{"id": int64(1), "name": "Ada", "deleted_at": nil},
+		{"id": int64(2), "name": "Linus", "deleted_at": "2025-06-01 10:00:00"},
+	}}}
+	rule := nodeRule("students", "", "Student")
+	rule.Rule.SourceSQL = query
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	service.SetSoftDeleteColumn("deleted_at")
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	assert.Equal(t, []string{"Ada"}, storedNames(neo4j.stored))
+}
+
+func TestTransformAndStore_LabelFromColumnProducesLabelPerDiscriminator(t *testing.T) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "parties", "id": int64(1), "name": "Ada", "entity_type": "person"},
+		{"_table": "parties", "id": int64(2), "name": "Acme", "entity_type": []byte("Organization")},
+		{"_table": "parties", "id": int64(3), "name": "Robot", "entity_type": "Machine"},
+		{"_table": "parties", "id": int64(4), "name": "Unknown", "entity_type": nil},
+	}}
+
+	tests := []struct {
+		name     string
+		fallback string
+		want     map[string]string
+	}{
+		{
+			name: "disallowed values are skipped",
+			want: map[string]string{"Ada": "Person", "Acme": "Organization"},
+		},
+		{
+			name:     "disallowed values use the fallback label",
+			fallback: "Party",
+			want:     map[string]string{"Ada": "Person", "Acme": "Organization", "Robot": "Party", "Unknown": "Party"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := nodeRule("parties", "parties", tt.fallback)
+			rule.Rule.LabelFromColumn = "entity_type"
+			rule.Rule.AllowedLabels = []string{"Person", "Organization"}
+
+			neo4j := &fakeNeo4jPort{}
+			service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+			require.NoError(t, service.TransformAndStore(context.Background()))
+
+			labels := make(map[string]string)
+			for _, node := range neo4j.stored.GetNodes() {
+				labels[fmt.Sprintf("%v", node.Properties["name"])] = node.Type
+			}
+			assert.Equal(t, tt.want, labels)
+		})
+	}
+}
+
+func TestTransformAndStore_MultipleLabelsPerNode(t *testing.T) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "parties", "id": int64(1), "name": "Ada", "entity_type": "customer"},
+		{"_table": "parties", "id": int64(2), "name": "Acme", "entity_type": "supplier"},
+	}}
+	rule := nodeRule("parties", "parties", "")
+	rule.Rule.LabelFromColumn = "entity_type"
+	rule.Rule.AllowedLabels = []string{"Customer", "Supplier"}
+	rule.Rule.Labels = []string{"Person"}
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	withLabel := func(label string) []string {
+		var names []string
+		for _, node := range neo4j.stored.GetNodes() {
+			if node.HasLabel(label) {
+				names = append(names, fmt.Sprintf("%v", node.Properties["name"]))
+			}
+		}
+		return names
+	}
+	assert.Equal(t, []string{"Ada", "Acme"}, withLabel("Person"), "every node carries the static label")
+	assert.Equal(t, []string{"Ada"}, withLabel("Customer"))
+	assert.Equal(t, []string{"Acme"}, withLabel("Supplier"))
+
+	for _, node := range neo4j.stored.GetNodes() {
+		assert.NotContains(t, node.Properties, transform_agg.LabelsField)
+	}
+}
+
+func TestTransformAndStore_WeightPropertyCountsRepeatedRows(t *testing.T) {
+	db := newEnrollmentFixture()
+	db.rows = append(db.rows,
+		map[string]any{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "C", "enrolled_at": "2026-02-01"},
+		map[string]any{"_table": "enrollments", "student_id": int64(1), "course_id": int64(10), "grade": "B", "enrolled_at": "2026-09-01"},
+	)
+
+	tests := []struct {
+		name           string
+		weightProperty string
+		want           map[string][]any
+	}{
+		{
+			name: "one relationship per row by default",
+			want: map[string][]any{"Ada": {nil, nil, nil}, "Linus": {nil}},
+		},
+		{
+			name:           "repeated rows increment the weight",
+			weightProperty: "weight",
+			want:           map[string][]any{"Ada": {int64(3)}, "Linus": {int64(1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollments := enrollmentRule(nil)
+			enrollments.Rule.WeightProperty = tt.weightProperty
+
+			neo4j := &fakeNeo4jPort{}
+			rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+				nodeRule("students", "students", "Student"),
+				nodeRule("courses", "courses", "Course"),
+				enrollments,
+			}}
+
+			service := NewTransformService(db, neo4j, rules)
+			require.NoError(t, service.TransformAndStore(context.Background()))
+
+			weights := make(map[string][]any)
+			for _, rel := range neo4j.stored.GetRelationships() {
+				name := fmt.Sprintf("%v", rel.SourceNode.Properties["name"])
+				weights[name] = append(weights[name], rel.Properties["weight"])
+				if tt.weightProperty != "" && name == "Ada" {
+					assert.Equal(t, "A", rel.Properties["grade"], "the first row's properties are kept")
+				}
+			}
+			assert.Equal(t, tt.want, weights)
+		})
+	}
+}
+
+func TestTransformAndStore_RelationTypeFromColumn(t *testing.T) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "people", "id": int64(1), "name": "Ada"},
+		{"_table": "people", "id": int64(2), "name": "Grace"},
+		{"_table": "people", "id": int64(3), "name": "Linus"},
+		{"_table": "person_links", "person_id": int64(1), "related_id": int64(2), "relation_type": "manages", "since": "2024"},
+		{"_table": "person_links", "person_id": int64(1), "related_id": int64(3), "relation_type": "mentors", "since": "2025"},
+		{"_table": "person_links", "person_id": int64(3), "related_id": int64(2), "relation_type": "follows"},
+	}}
+
+	links := &transform_agg.RuleAggregate{
+		Name: "person_links",
+		Rule: transform.TransformRule{
+			Name:                   "person_links",
+			SourceTable:            "person_links",
+			RuleType:               transform.RelationshipRule,
+			RelationTypeFromColumn: "relation_type",
+			AllowedRelationTypes:   []string{"MANAGES", "MENTORS"},
+			Direction:              transform.Outgoing,
+			SourceNode:             &transform.NodeMapping{Type: "Person", Key: "person_id", TargetField: "id"},
+			TargetNode:             &transform.NodeMapping{Type: "Person", Key: "related_id", TargetField: "id"},
+		},
+	}
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("people", "people", "Person"),
+		links,
+	}}
+
+	service := NewTransformService(db, neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	edges := make(map[string]string)
+	for _, rel := range neo4j.stored.GetRelationships() {
+		edges[fmt.Sprintf("%v->%v", rel.SourceNode.Properties["name"], rel.TargetNode.Properties["name"])] = rel.Type
+		assert.NotContains(t, rel.Properties, "relation_type", "the discriminator becomes the type, not a property")
+	}
+	assert.Equal(t, map[string]string{"Ada->Grace": "MANAGES", "Ada->Linus": "MENTORS"}, edges,
+		"rows whose type is not allowed are skipped")
+}
+
+func TestTransformAndStore_NullKeysSkipOrBucket(t *testing.T) {
+	db := newEnrollmentFixture()
+	db.rows = append(db.rows,
+		map[string]any{"_table": "enrollments", "student_id": int64(1), "course_id": nil, "grade": "A"},
+		map[string]any{"_table": "enrollments", "student_id": nil, "course_id": int64(10), "grade": "C"},
+		map[string]any{"_table": "enrollments", "student_id": int64(2), "course_id": nil},
+	)
+
+	endpoints := func(g *graph.GraphAggregate) []string {
+		var links []string
+		for _, rel := range g.GetRelationships() {
+			links = append(links, fmt.Sprintf("%v->%v", rel.SourceNode.Properties["id"], rel.TargetNode.Properties["id"]))
+		}
+		return links
+	}
+
+	tests := []struct {
+		name   string
+		policy transform.NullKeyPolicy
+		want   []string
+		nodes  int
+	}{
+		{name: "skipped by default", want: []string{"1->10", "2->10"}, nodes: 3},
+		{name: "skip", policy: transform.NullKeySkip, want: []string{"1->10", "2->10"}, nodes: 3},
+		{
+			name:   "bucket",
+			policy: transform.NullKeyBucket,
+			want:   []string{"1->10", "2->10", "1->__unknown__", "__unknown__->10", "2->__unknown__"},
+			nodes:  5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollments := enrollmentRule(nil)
+			enrollments.Rule.NullKeys = tt.policy
+
+			neo4j := &fakeNeo4jPort{}
+			rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+				nodeRule("students", "students", "Student"),
+				nodeRule("courses", "courses", "Course"),
+				enrollments,
+			}}
+
+			service := NewTransformService(db, neo4j, rules)
+			require.NoError(t, service.TransformAndStore(context.Background()))
+
+			assert.Equal(t, tt.want, endpoints(neo4j.stored))
+			assert.Len(t, neo4j.stored.GetNodes(), tt.nodes, "one unknown node per missing type")
+		})
+	}
+}
+
+func TestTransformAndStore_CompositeKeyMergesRows(t *testing.T) {
+	db := &fakeDatabasePort{rows: []map[string]any{
+		{"_table": "order_lines", "order_id": int64(1), "line_no": int64(1), "name": "first"},
+		{"_table": "order_lines", "order_id": int64(1), "line_no": int64(1), "name": "first, updated"},
+		{"_table": "order_lines", "order_id": int64(1), "line_no": int64(2), "name": "second"},
+		{"_table": "order_lines", "order_id": int64(2), "line_no": int64(1), "name": "other order"},
+		{"_table": "replacements", "order_id": int64(1), "line_no": int64(2), "replacement_line_no": int64(1)},
+	}}
+
+	lines := nodeRule("order_lines", "order_lines", "OrderLine")
+	lines.Rule.FieldMappings = map[string]string{"name": "name"}
+	lines.Rule.KeyColumns = []string{"order_id", "line_no"}
+
+	replacements := &transform_agg.RuleAggregate{
+		Name: "replacements",
+		Rule: transform.TransformRule{
+			Name:         "replacements",
+			SourceTable:  "replacements",
+			RuleType:     transform.RelationshipRule,
+			RelationType: "REPLACED_BY",
+			Direction:    transform.Outgoing,
+			SourceNode:   &transform.NodeMapping{Type: "OrderLine", Keys: []string{"order_id", "line_no"}},
+			TargetNode:   &transform.NodeMapping{Type: "OrderLine", Keys: []string{"order_id", "replacement_line_no"}},
+		},
+	}
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(db, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{lines, replacements}})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	names := make(map[any]any)
+	for _, node := range neo4j.stored.GetNodes() {
+		names[node.Properties["id"]] = node.Properties["name"]
+	}
+	assert.Equal(t, map[any]any{"1|1": "first, updated", "1|2": "second", "2|1": "other order"}, names,
+		"rows sharing the composite key merge, differing keys stay distinct")
+
+	node := neo4j.stored.GetNodes()[0]
+	assert.Equal(t, map[string]any{"order_id": "1", "line_no": "1"}, node.MergeKeys)
+
+	relationships := neo4j.stored.GetRelationships()
+	require.Len(t, relationships, 1)
+	assert.Equal(t, "1|2", relationships[0].SourceNode.Properties["id"])
+	assert.Equal(t, "1|1", relationships[0].TargetNode.Properties["id"])
+}
+
+func TestTransformAndStore_JoinPathLinksEndpointsDirectly(t *testing.T) {
+	rule, err := transform.TransformRule{
+		Name:         "employee_locations",
+		SourceTable:  "employees",
+		RuleType:     transform.RelationshipRule,
+		RelationType: "WORKS_AT",
+		Direction:    transform.Outgoing,
+		SourceNode:   &transform.NodeMapping{Type: "Employee", Key: "id", TargetField: "id"},
+		TargetNode:   &transform.NodeMapping{Type: "Location", Key: "id", TargetField: "id"},
+		Properties:   map[string]string{"departments.name": "department"},
+		JoinPath: []transform.JoinStep{
+			{Table: "departments", From: "department_id", To: "id"},
+			{Table: "locations", From: "location_id", To: "id"},
+		},
+	}.ExpandJoinPath()
+	require.NoError(t, err)
+
+	db := &fakeDatabasePort{
+		rows: []map[string]any{
+			{"_table": "employees", "id": int64(1), "name": "Ada", "department_id": int64(10)},
+			{"_table": "employees", "id": int64(2), "name": "Linus", "department_id": int64(20)},
+			{"_table": "departments", "id": int64(10), "name": "Research", "location_id": int64(100)},
+			{"_table": "departments", "id": int64(20), "name": "Support", "location_id": int64(200)},
+			{"_table": "locations", "id": int64(100), "name": "Prague"},
+			{"_table": "locations", "id": int64(200), "name": "Brno"},
+		},
+		queries: map[string][]map[string]any{rule.SourceSQL: {
+			{"source_key": int64(1), "target_key": int64(100), "property_0": "Research"},
+			{"source_key": int64(2), "target_key": int64(200), "property_0": "Support"},
+		}},
+	}
+	neo4j := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{
+		nodeRule("employees", "employees", "Employee"),
+		nodeRule("departments", "departments", "Department"),
+		nodeRule("locations", "locations", "Location"),
+		{Name: rule.Name, Rule: rule},
+	}}
+
+	service := NewTransformService(db, neo4j, rules)
+	require.NoError(t, service.TransformAndStore(context.Background()))
+	require.NotNil(t, neo4j.stored)
+
+	relationships := neo4j.stored.GetRelationships()
+	require.Len(t, relationships, 2)
+
+	locations := make(map[string]string)
+	for _, rel := range relationships {
+		assert.Equal(t, "WORKS_AT", rel.Type)
+		assert.Equal(t, "Employee", rel.SourceNode.Type)
+		assert.Equal(t, "Location", rel.TargetNode.Type)
+		locations[fmt.Sprintf("%v", rel.SourceNode.Properties["name"])] = fmt.Sprintf("%v/%v", rel.TargetNode.Properties["name"], rel.Properties["department"])
+	}
+	assert.Equal(t, map[string]string{"Ada": "Prague/Research", "Linus": "Brno/Support"}, locations)
+}
+
+func TestTransformAndStore_MergeStrategies(t *testing.T) {
+	// Two rows of the same product, e.g. from two price lists
+	rows := []map[string]any{
+		{"_table": "prices", "id": int64(7), "name": "Lamp", "price": 25.5, "updated": "2025-03-01", "region": "eu"},
+		{"_table": "prices", "id": int64(7), "name": "Desk lamp", "price": 19.0, "updated": "2025-01-15", "region": "us"},
+	}
+
+	tests := []struct {
+		strategy transform.MergeStrategy
+		property string
+		want     any
+	}{
+		{strategy: transform.MergeLastWins, property: "name", want: "Desk lamp"},
+		{strategy: transform.MergeFirstWins, property: "name", want: "Lamp"},
+		{strategy: transform.MergeMax, property: "price", want: 25.5},
+		{strategy: transform.MergeMin, property: "price", want: 19.0},
+		{strategy: transform.MergeMax, property: "updated", want: "2025-03-01"},
+		{strategy: transform.MergeMin, property: "updated", want: "2025-01-15"},
+		{strategy: transform.MergeConcat, property: "region", want: "eu, us"},
+		{strategy: transform.MergeArrayAppend, property: "region", want: []string{"eu", "us"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.strategy, tt.property), func(t *testing.T) {
+			rule := nodeRule("prices", "prices", "Product")
+			rule.Rule.FieldMappings = map[string]string{"id": "id", "name": "name", "price": "price", "updated": "updated", "region": "region"}
+			rule.Rule.MergeStrategies = map[string]transform.MergeStrategy{tt.property: tt.strategy}
+
+			neo4j := &fakeNeo4jPort{}
+			service := NewTransformService(&fakeDatabasePort{rows: rows}, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+			require.NoError(t, service.TransformAndStore(context.Background()))
+
+			nodes := neo4j.stored.GetNodes()
+			require.Len(t, nodes, 1)
+			assert.Equal(t, tt.want, nodes[0].Properties[tt.property])
+		})
+	}
+}
+
+func TestTransformAndStore_MergeStrategiesKeepOtherProperties(t *testing.T) {
+	rows := []map[string]any{
+		{"_table": "people", "id": int64(1), "name": "Ada", "email": "ada@example.com"},
+		{"_table": "people", "id": int64(1), "name": "Ada Lovelace"},
+	}
+	rule := nodeRule("people", "people", "Person")
+	rule.Rule.FieldMappings = map[string]string{"id": "id", "name": "name", "email": "email"}
+	rule.Rule.MergeStrategies = map[string]transform.MergeStrategy{"name": transform.MergeFirstWins}
+
+	neo4j := &fakeNeo4jPort{}
+	service := NewTransformService(&fakeDatabasePort{rows: rows}, neo4j, &fakeRuleRepository{rules: []*transform_agg.RuleAggregate{rule}})
+	require.NoError(t, service.TransformAndStore(context.Background()))
+
+	nodes := neo4j.stored.GetNodes()
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "Ada", nodes[0].Properties["name"])
+	assert.Equal(t, "ada@example.com", nodes[0].Properties["email"], "a property missing from a later row is kept")
+	assert.NotContains(t, nodes[0].Properties, transform_agg.MergeStrategiesField)
+}
@@ -12,6 +12,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,6 +29,17 @@ type UniversalDatabaseService struct {
 	config            models.DatabaseConfig
 	dbType            models.DatabaseType
 	securityValidator *SecurityValidationService
+
+	// Optional schema cache; refreshCache forces re-analysis and overwrites the entry
+	schemaCache  *SchemaAnalysisCache
+	refreshCache bool
+
+	// Optional data profiling of implicit relationships (costly: queries every candidate column)
+	profileRelationships bool
+	profileSampleSize    int
+
+	// Bounds on naming-convention inference for wide schemas
+	inferenceLimits InferenceLimits
 }
 
 // NewUniversalDatabaseService creates a new universal database service
@@ -57,6 +69,25 @@ func NewUniversalDatabaseService(
 	}
 }
 
+// SetSchemaCache enables reuse of previously discovered schemas. With refresh set,
+// the cached entry is ignored and replaced by a fresh analysis.
+func (s *UniversalDatabaseService) SetSchemaCache(cache *SchemaAnalysisCache, refresh bool) {
+	s.schemaCache = cache
+	s.refreshCache = refresh
+}
+
+// SetRelationshipProfiling enables sampling candidate foreign key values to adjust the
+// confidence of implicit relationships by their actual overlap with the referenced key
+func (s *UniversalDatabaseService) SetRelationshipProfiling(enabled bool, sampleSize int) {
+	s.profileRelationships = enabled
+	s.profileSampleSize = sampleSize
+}
+
+// SetInferenceLimits bounds how many implicit relationships are inferred per column and in total
+func (s *UniversalDatabaseService) SetInferenceLimits(limits InferenceLimits) {
+	s.inferenceLimits = limits
+}
+
 // ConnectAndAnalyze performs the complete workflow for any database type:
 // 1. Security validation of connection parameters
 // 2. Connection to existing database
@@ -111,6 +142,24 @@ func (s *UniversalDatabaseService) ConnectAndAnalyze(ctx context.Context) (*mode
 		logrus.Infof("Security validation passed")
 	}
 
+	fingerprint := SchemaFingerprint(s.config)
+	if s.schemaCache != nil && !s.refreshCache && !s.profileRelationships {
+		if entry, ok := s.schemaCache.Get(fingerprint); ok {
+			logrus.Infof("Using cached schema analysis from %s", entry.CachedAt.Format(time.RFC3339))
+			if entry.DatabaseInfo != nil {
+				result.DatabaseInfo = entry.DatabaseInfo
+			}
+			result.SchemaAnalysis = entry.SchemaAnalysis
+			result.FromCache = true
+
+			s.generateAnalysisSummary(result)
+			result.Success = true
+			result.EndTime = time.Now()
+			result.ProcessingDuration = result.EndTime.Sub(result.StartTime)
+			return result, nil
+		}
+	}
+
 	// Step 2: Establish database connection
 	logrus.Infof("Step 2: Connecting to %s database", s.dbType)
 	db, err := s.repo.Connect(ctx, s.config)
@@ -166,14 +215,29 @@ func (s *UniversalDatabaseService) ConnectAndAnalyze(ctx context.Context) (*mode
 
 	logrus.Infof("Schema analysis completed: %d tables analyzed", len(schemaResult.Tables))
 
-	// Step 5: Generate summary and recommendations
-	logrus.Infof("Step 5: Generating analysis summary")
+	// Step 5: Infer implicit relationships
+	logrus.Infof("Step 5: Inferring implicit relationships")
+	schemaResult.ImplicitRelationships = s.inferRelationships(ctx, schemaResult.Tables)
+
+	// Step 6: Generate summary and recommendations
+	logrus.Infof("Step 6: Generating analysis summary")
 	s.generateAnalysisSummary(result)
 
 	result.Success = true
 	result.EndTime = time.Now()
 	result.ProcessingDuration = result.EndTime.Sub(result.StartTime)
 
+	if s.schemaCache != nil {
+		if err := s.schemaCache.Put(&SchemaCacheEntry{
+			Fingerprint:    fingerprint,
+			DatabaseType:   s.dbType,
+			DatabaseInfo:   result.DatabaseInfo,
+			SchemaAnalysis: result.SchemaAnalysis,
+		}); err != nil {
+			logrus.Warnf("Failed to cache schema analysis: %v", err)
+		}
+	}
+
 	logrus.Infof("Universal database analysis completed successfully in %v", result.ProcessingDuration)
 
 	return result, nil
@@ -248,6 +312,23 @@ func (s *UniversalDatabaseService) TestConnection(ctx context.Context) (*models.
 	return testResult, nil
 }
 
+// inferRelationships finds implicit relationships by naming convention and, when profiling
+// is enabled, re-scores them by sampled data overlap before applying the confidence threshold
+func (s *UniversalDatabaseService) inferRelationships(ctx context.Context, tables []*models.UniversalTableInfo) []models.RelationshipInfo {
+	relationships := InferImplicitRelationshipsWithLimits(tables, s.inferenceLimits)
+
+	if s.profileRelationships && len(relationships) > 0 {
+		if profiler, ok := s.repo.(repository.ColumnProfiler); ok {
+			logrus.Infof("Profiling %d candidate relationships", len(relationships))
+			relationships = ProfileRelationshipConfidence(ctx, profiler, relationships, s.profileSampleSize)
+		} else {
+			logrus.Warnf("Relationship profiling is not supported for %s", s.dbType)
+		}
+	}
+
+	return FilterRelationshipsByConfidence(relationships, DefaultRelationshipConfidenceThreshold)
+}
+
 // analyzeSchema performs schema analysis using the generic repository interface
 func (s *UniversalDatabaseService) analyzeSchema(ctx context.Context, db *sql.DB) (*models.UniversalSchemaAnalysisResult, error) {
 	result := &models.UniversalSchemaAnalysisResult{
@@ -300,14 +381,39 @@ func (s *UniversalDatabaseService) analyzeTable(ctx context.Context, tableName s
 		return nil, fmt.Errorf("failed to get columns for table %s: %w", tableName, err)
 	}
 	tableInfo.Columns = columns
+	tableInfo.Recommendations = append(tableInfo.Recommendations, WideTableRecommendations(columns, nil)...)
 
 	// Get row count estimate
 	rowCount, err := s.repo.GetTableRowCount(ctx, tableName)
-	if err == nil {
-		tableInfo.EstimatedRows = rowCount
-	} else {
+	if err != nil {
 		logrus.Warnf("Failed to get row count for table %s: %v", tableName, err)
 	}
+	tableInfo.EstimatedRows = s.estimateRows(ctx, tableName, rowCount)
+
+	foreignKeys, err := s.repo.GetForeignKeys(ctx, tableName)
+	if err != nil {
+		logrus.Warnf("Failed to get foreign keys for table %s: %v", tableName, err)
+	}
+	for _, fk := range foreignKeys {
+		tableInfo.Relationships = append(tableInfo.Relationships, &models.Relationship{
+			SourceTable:      tableName,
+			SourceColumn:     fk.Column,
+			TargetTable:      fk.ReferencedTable,
+			TargetColumn:     fk.ReferencedColumn,
+			RelationshipType: "MANY_TO_ONE",
+			ConstraintName:   fk.Name,
+		})
+	}
+
+	if s.config.GetDataFiltering().DiscoverIndexes {
+		indexes, err := s.repo.GetIndexes(ctx, tableName)
+		if err != nil {
+			// The analysis stays useful without indexes
+			logrus.Warnf("Failed to get indexes for table %s: %v", tableName, err)
+		}
+		tableInfo.Indexes = indexes
+		tableInfo.Recommendations = append(tableInfo.Recommendations, IndexRecommendations(tableInfo)...)
+	}
 
 	return tableInfo, nil
 }
@@ -359,6 +465,263 @@ func (s *UniversalDatabaseService) generateAnalysisSummary(result *models.Univer
 		summary.TotalTables, len(summary.Warnings), len(summary.Recommendations))
 }
 
+// cachedSchema returns the schema analysis the lookups below answer from when a schema
+// cache is set, analyzing and caching the schema on a miss; without a cache it returns nil
+// and the lookups query the catalog directly
+func (s *UniversalDatabaseService) cachedSchema(ctx context.Context) (*models.UniversalSchemaAnalysisResult, error) {
+	if s.schemaCache == nil {
+		return nil, nil
+	}
+	result, err := s.ConnectAndAnalyze(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, errors.New(result.ErrorMessage)
+	}
+	return result.SchemaAnalysis, nil
+}
+
+// SchemaColumns connects to the database and lists the column names of every table the
+// configured filters allow, keyed by table name
+func (s *UniversalDatabaseService) SchemaColumns(ctx context.Context) (map[string][]string, error) {
+	analysis, err := s.cachedSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if analysis != nil {
+		schema := make(map[string][]string, len(analysis.Tables))
+		for _, table := range analysis.Tables {
+			names := make([]string, 0, len(table.Columns))
+			for _, column := range table.Columns {
+				names = append(names, column.Name)
+			}
+			schema[table.Name] = names
+		}
+		return schema, nil
+	}
+
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tables, err := s.repo.GetTables(ctx, s.config.GetDataFiltering())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	schema := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		columns, err := s.repo.GetColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns for table %s: %w", table, err)
+		}
+		names := make([]string, 0, len(columns))
+		for _, column := range columns {
+			names = append(names, column.Name)
+		}
+		schema[table] = names
+	}
+	return schema, nil
+}
+
+// TableReferences connects to the database and lists, for every table the configured
+// filters allow, the tables its foreign keys reference
+func (s *UniversalDatabaseService) TableReferences(ctx context.Context) (map[string][]string, error) {
+	analysis, err := s.cachedSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if analysis != nil {
+		references := make(map[string][]string, len(analysis.Tables))
+		for _, table := range analysis.Tables {
+			for _, rel := range table.Relationships {
+				references[table.Name] = append(references[table.Name], rel.TargetTable)
+			}
+		}
+		return references, nil
+	}
+
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tables, err := s.repo.GetTables(ctx, s.config.GetDataFiltering())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	references := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		foreignKeys, err := s.repo.GetForeignKeys(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get foreign keys for table %s: %w", table, err)
+		}
+		for _, fk := range foreignKeys {
+			references[table] = append(references[table], fk.ReferencedTable)
+		}
+	}
+	return references, nil
+}
+
+// ColumnReferences connects to the database and lists the foreign key columns of every table
+// the configured filters allow, each with the column it references
+func (s *UniversalDatabaseService) ColumnReferences(ctx context.Context) ([]models.ColumnReference, error) {
+	analysis, err := s.cachedSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if analysis != nil {
+		var references []models.ColumnReference
+		for _, table := range analysis.Tables {
+			for _, rel := range table.Relationships {
+				references = append(references, models.ColumnReference{
+					Table:            table.Name,
+					Column:           rel.SourceColumn,
+					ReferencedTable:  rel.TargetTable,
+					ReferencedColumn: rel.TargetColumn,
+					Constraint:       rel.ConstraintName,
+				})
+			}
+		}
+		return references, nil
+	}
+
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tables, err := s.repo.GetTables(ctx, s.config.GetDataFiltering())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	var references []models.ColumnReference
+	for _, table := range tables {
+		foreignKeys, err := s.repo.GetForeignKeys(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get foreign keys for table %s: %w", table, err)
+		}
+		for _, fk := range foreignKeys {
+			references = append(references, models.ColumnReference{
+				Table:            table,
+				Column:           fk.Column,
+				ReferencedTable:  fk.ReferencedTable,
+				ReferencedColumn: fk.ReferencedColumn,
+				Constraint:       fk.Name,
+			})
+		}
+	}
+	return references, nil
+}
+
+// PrimaryKeys connects to the database and lists the primary key columns of every table the
+// configured filters allow, in column order; tables without a primary key are left out
+func (s *UniversalDatabaseService) PrimaryKeys(ctx context.Context) (map[string][]string, error) {
+	analysis, err := s.cachedSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if analysis != nil {
+		primaryKeys := make(map[string][]string, len(analysis.Tables))
+		for _, table := range analysis.Tables {
+			for _, column := range table.Columns {
+				if isPrimaryKeyColumn(column) {
+					primaryKeys[table.Name] = append(primaryKeys[table.Name], column.Name)
+				}
+			}
+		}
+		return primaryKeys, nil
+	}
+
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tables, err := s.repo.GetTables(ctx, s.config.GetDataFiltering())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	primaryKeys := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		columns, err := s.repo.GetColumns(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns for table %s: %w", table, err)
+		}
+		for _, column := range columns {
+			if isPrimaryKeyColumn(column) {
+				primaryKeys[table] = append(primaryKeys[table], column.Name)
+			}
+		}
+	}
+	return primaryKeys, nil
+}
+
+// TableSizes connects to the database and reads the catalog size of every table the
+// configured filters allow; tables whose size cannot be read are left out
+func (s *UniversalDatabaseService) TableSizes(ctx context.Context) ([]*models.TableSize, error) {
+	db, err := s.repo.Connect(ctx, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("database connection failed: %w", err)
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			logrus.Warnf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tables, err := s.repo.GetTables(ctx, s.config.GetDataFiltering())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	sizes := make([]*models.TableSize, 0, len(tables))
+	for _, table := range tables {
+		size, err := s.repo.GetTableSize(ctx, table)
+		if err != nil {
+			logrus.Warnf("Failed to read size of table %s: %v", table, err)
+			continue
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// isPrimaryKeyColumn recognizes the key types the repositories report for primary keys
+func isPrimaryKeyColumn(column *models.ColumnInfo) bool {
+	switch column.KeyType {
+	case "PRI", "PRIMARY":
+		return true
+	default:
+		return false
+	}
+}
+
 // ValidateConfiguration validates the service configuration
 func (s *UniversalDatabaseService) ValidateConfiguration() error {
 	return s.config.Validate()
@@ -0,0 +1,165 @@
+/*
+ * SQL Graph Visualizer - Wide Table Analysis
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+const (
+	// WideTableColumns is the column count from which a table is analyzed for a split
+	WideTableColumns = 30
+	// WideTableSampleRows is how many rows are read to find columns that are NULL together
+	WideTableSampleRows = 500
+
+	// minColumnGroupSize is the smallest set of columns worth its own node
+	minColumnGroupSize = 3
+)
+
+// Bases of a ColumnGroup
+const (
+	ColumnGroupPrefix = "prefix"
+	ColumnGroupNulls  = "null_correlation"
+)
+
+// ColumnGroup is a set of columns of a wide table that likely describe a separate entity
+type ColumnGroup struct {
+	// Name is the shared column prefix; empty for groups found by their NULL values
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Basis   string   `json:"basis"`
+}
+
+// SuggestColumnGroups groups the non-key columns of a table by a shared name prefix (e.g.
+// billing_street, billing_city, billing_zip). Columns left over are grouped when they are NULL
+// in exactly the same rows of nullSample, which maps each column to whether it is NULL in one
+// sampled row. A prefix shared by more than half the columns says nothing and is ignored.
+func SuggestColumnGroups(columns []*models.ColumnInfo, nullSample []map[string]bool) []ColumnGroup {
+	var candidates []string
+	for _, column := range columns {
+		if isPrimaryKeyColumn(column) || strings.EqualFold(column.Name, "id") {
+			continue
+		}
+		candidates = append(candidates, column.Name)
+	}
+
+	var prefixes []string
+	byPrefix := make(map[string][]string)
+	for _, column := range candidates {
+		prefix, rest, found := strings.Cut(strings.ToLower(column), "_")
+		if !found || len(prefix) < 2 || rest == "" {
+			continue
+		}
+		if _, seen := byPrefix[prefix]; !seen {
+			prefixes = append(prefixes, prefix)
+		}
+		byPrefix[prefix] = append(byPrefix[prefix], column)
+	}
+
+	var groups []ColumnGroup
+	grouped := make(map[string]bool)
+	for _, prefix := range prefixes {
+		members := byPrefix[prefix]
+		if len(members) < minColumnGroupSize || 2*len(members) > len(candidates) {
+			continue
+		}
+		groups = append(groups, ColumnGroup{Name: prefix, Columns: members, Basis: ColumnGroupPrefix})
+		for _, column := range members {
+			grouped[column] = true
+		}
+	}
+
+	return append(groups, nullCorrelatedGroups(candidates, grouped, nullSample)...)
+}
+
+// nullCorrelatedGroups groups the ungrouped columns sharing the same NULL pattern across the
+// sample. Columns NULL in every or in no sampled row have no pattern to share.
+func nullCorrelatedGroups(candidates []string, grouped map[string]bool, nullSample []map[string]bool) []ColumnGroup {
+	if len(nullSample) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	byPattern := make(map[string][]string)
+	for _, column := range candidates {
+		if grouped[column] {
+			continue
+		}
+		pattern := make([]byte, len(nullSample))
+		nulls := 0
+		for i, row := range nullSample {
+			pattern[i] = '0'
+			if row[column] {
+				pattern[i] = '1'
+				nulls++
+			}
+		}
+		if nulls == 0 || nulls == len(nullSample) {
+			continue
+		}
+		key := string(pattern)
+		if _, seen := byPattern[key]; !seen {
+			patterns = append(patterns, key)
+		}
+		byPattern[key] = append(byPattern[key], column)
+	}
+
+	var groups []ColumnGroup
+	for _, pattern := range patterns {
+		if members := byPattern[pattern]; len(members) >= minColumnGroupSize {
+			groups = append(groups, ColumnGroup{Columns: members, Basis: ColumnGroupNulls})
+		}
+	}
+	return groups
+}
+
+// WideTableRecommendations suggests splitting a table of at least WideTableColumns columns
+// into related nodes, naming the column groups SuggestColumnGroups finds. Narrower tables get
+// no recommendation.
+func WideTableRecommendations(columns []*models.ColumnInfo, nullSample []map[string]bool) []string {
+	if len(columns) < WideTableColumns {
+		return nil
+	}
+
+	groups := SuggestColumnGroups(columns, nullSample)
+	if len(groups) == 0 {
+		return []string{fmt.Sprintf(
+			"Wide table (%d columns) - consider grouping related properties or splitting it into related nodes", len(columns))}
+	}
+
+	recommendations := make([]string, 0, len(groups))
+	for _, group := range groups {
+		members := append([]string(nil), group.Columns...)
+		sort.Strings(members)
+		switch group.Basis {
+		case ColumnGroupPrefix:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Wide table (%d columns) - columns %s share the %s_ prefix; consider splitting them into a related %s node",
+				len(columns), strings.Join(members, ", "), group.Name, nodeLabel(group.Name)))
+		default:
+			recommendations = append(recommendations, fmt.Sprintf(
+				"Wide table (%d columns) - columns %s are NULL in the same rows; consider splitting them into a related node",
+				len(columns), strings.Join(members, ", ")))
+		}
+	}
+	return recommendations
+}
+
+// nodeLabel turns a column prefix such as "billing" into a label such as "Billing"
+func nodeLabel(prefix string) string {
+	runes := []rune(prefix)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
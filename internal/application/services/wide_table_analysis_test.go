@@ -0,0 +1,95 @@
+/*
+ * SQL Graph Visualizer - Wide Table Analysis Tests
+ *
+ * Copyright (c) 2025
+ * Licensed under Dual License: AGPL-3.0 OR Commercial License
+ * See LICENSE file for details
+ * Patent Pending - Application filed for innovative database transformation techniques
+ */
+
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// wideCustomerColumns describes a customers table holding billing and shipping addresses and
+// optional referral details next to its own columns
+func wideCustomerColumns() []*models.ColumnInfo {
+	names := []string{"id", "name", "email", "phone", "status", "created_at"}
+	for _, part := range []string{"street", "city", "zip", "country"} {
+		names = append(names, "billing_"+part, "shipping_"+part)
+	}
+	names = append(names, "referrer", "campaign", "coupon")
+	for i := len(names); i < 34; i++ {
+		names = append(names, fmt.Sprintf("attribute%d", i))
+	}
+
+	columns := make([]*models.ColumnInfo, len(names))
+	for i, name := range names {
+		columns[i] = &models.ColumnInfo{Name: name, DataType: "varchar"}
+	}
+	columns[0].KeyType = "PRI"
+	return columns
+}
+
+// referralSample marks referrer, campaign and coupon NULL in the same rows; attribute30 is NULL
+// in other rows
+func referralSample() []map[string]bool {
+	return []map[string]bool{
+		{"referrer": true, "campaign": true, "coupon": true},
+		{"attribute30": true},
+		{"referrer": true, "campaign": true, "coupon": true, "attribute30": true},
+		{},
+	}
+}
+
+func TestSuggestColumnGroupsOnWideTable(t *testing.T) {
+	groups := SuggestColumnGroups(wideCustomerColumns(), referralSample())
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, ColumnGroup{
+		Name:    "billing",
+		Columns: []string{"billing_street", "billing_city", "billing_zip", "billing_country"},
+		Basis:   ColumnGroupPrefix,
+	}, groups[0])
+	assert.Equal(t, "shipping", groups[1].Name)
+	assert.Len(t, groups[1].Columns, 4)
+	assert.Equal(t, ColumnGroup{
+		Columns: []string{"referrer", "campaign", "coupon"},
+		Basis:   ColumnGroupNulls,
+	}, groups[2])
+}
+
+func TestWideTableRecommendationsSuggestSplit(t *testing.T) {
+	recommendations := WideTableRecommendations(wideCustomerColumns(), referralSample())
+
+	require.Len(t, recommendations, 3)
+	assert.Equal(t, "Wide table (34 columns) - columns billing_city, billing_country, billing_street, billing_zip "+
+		"share the billing_ prefix; consider splitting them into a related Billing node", recommendations[0])
+	assert.Contains(t, recommendations[2], "campaign, coupon, referrer are NULL in the same rows")
+
+	// Without a sample only the prefix groups are found
+	assert.Len(t, WideTableRecommendations(wideCustomerColumns(), nil), 2)
+}
+
+func TestWideTableRecommendationsIgnoreNarrowTables(t *testing.T) {
+	columns := wideCustomerColumns()[:WideTableColumns-1]
+	assert.Empty(t, WideTableRecommendations(columns, referralSample()))
+}
+
+func TestWideTableRecommendationsWithoutGroups(t *testing.T) {
+	columns := make([]*models.ColumnInfo, WideTableColumns)
+	for i := range columns {
+		columns[i] = &models.ColumnInfo{Name: fmt.Sprintf("c%d", i)}
+	}
+	assert.Equal(t, []string{
+		"Wide table (30 columns) - consider grouping related properties or splitting it into related nodes",
+	}, WideTableRecommendations(columns, nil))
+}
@@ -25,6 +25,22 @@ type GraphAggregate struct {
 	nodes         []*entities.Node
 	events        []events.DomainEvent
 	relationships []Relationship
+	// weighted indexes the relationships added with a weight by type and endpoints
+	weighted map[weightedKey]int
+	// merged indexes the relationships added by MergeRelationship by their identity
+	merged map[mergedKey]int
+}
+
+type weightedKey struct {
+	relType        string
+	source, target *entities.Node
+}
+
+// mergedKey is the identity of a merged relationship: its type, endpoints and discriminator value
+type mergedKey struct {
+	relType        string
+	source, target *entities.Node
+	discriminator  string
 }
 
 type Relationship struct {
@@ -33,6 +49,10 @@ type Relationship struct {
 	SourceNode *entities.Node
 	TargetNode *entities.Node
 	Properties map[string]any
+	// Merged relationships are identified in the graph store by type, endpoints and
+	// MergeKeys, so storing one again updates it instead of adding a parallel relationship
+	Merged    bool
+	MergeKeys map[string]any
 }
 
 func NewGraphAggregate(id string) *GraphAggregate {
@@ -44,7 +64,7 @@ func NewGraphAggregate(id string) *GraphAggregate {
 }
 
 func (g *GraphAggregate) AddNode(nodeType string, properties map[string]any) error {
-	existingNode := g.findNode(nodeType, properties["id"], "id")
+	existingNode := g.findNode(nodeType, properties["id"], "id", nil)
 	if existingNode != nil {
 		existingNode.Properties = properties
 		return nil
@@ -58,6 +78,70 @@ func (g *GraphAggregate) AddNode(nodeType string, properties map[string]any) err
 	return nil
 }
 
+// AddNodeWithMergeKeys adds a node identified in the graph store by the mergeKeys
+// properties rather than by id alone. Nodes sharing an id are still merged in the graph.
+func (g *GraphAggregate) AddNodeWithMergeKeys(nodeType string, properties map[string]any, mergeKeys map[string]any) error {
+	if err := g.AddNode(nodeType, properties); err != nil {
+		return err
+	}
+	g.findNode(nodeType, properties["id"], "id", nil).MergeKeys = mergeKeys
+	return nil
+}
+
+// MergeNode adds a node like AddNode, except that a node already added with the same id
+// keeps its properties and takes the new ones property by property: strategies decides how
+// a property's values combine, and properties without a strategy take the new value.
+// mergeKeys, when set, identify the node in the graph store as in AddNodeWithMergeKeys.
+func (g *GraphAggregate) MergeNode(nodeType string, properties map[string]any, strategies map[string]transform.MergeStrategy, mergeKeys map[string]any) error {
+	existingNode := g.findNode(nodeType, properties["id"], "id", nil)
+	if existingNode == nil {
+		initial := make(map[string]any, len(properties))
+		for key, value := range properties {
+			initial[key] = value
+			if strategy, ok := strategies[key]; ok && key != "id" {
+				initial[key] = strategy.Initial(value)
+			}
+		}
+		if err := g.AddNode(nodeType, initial); err != nil {
+			return err
+		}
+		existingNode = g.findNode(nodeType, properties["id"], "id", nil)
+	} else {
+		for key, value := range properties {
+			strategy, ok := strategies[key]
+			if !ok || key == "id" {
+				existingNode.Properties[key] = value
+				continue
+			}
+			existingNode.Properties[key] = strategy.Merge(existingNode.Properties[key], value)
+		}
+	}
+	if len(mergeKeys) > 0 {
+		existingNode.MergeKeys = mergeKeys
+	}
+	return nil
+}
+
+// AddNodeLabels adds labels to the node of nodeType with the given id; labels it already
+// carries are skipped
+func (g *GraphAggregate) AddNodeLabels(nodeType string, id any, labels []string) error {
+	node := g.findNode(nodeType, id, "id", nil)
+	if node == nil {
+		return fmt.Errorf("node %s %v not found", nodeType, id)
+	}
+	for _, label := range labels {
+		if !node.HasLabel(label) {
+			node.ExtraLabels = append(node.ExtraLabels, label)
+		}
+	}
+	return nil
+}
+
+// HasNode reports whether a node of nodeType with the given id was added
+func (g *GraphAggregate) HasNode(nodeType string, id any) bool {
+	return g.findNode(nodeType, id, "id", nil) != nil
+}
+
 func (g *GraphAggregate) GetNodes() []*entities.Node {
 	return g.nodes
 }
@@ -81,8 +165,25 @@ func (g *GraphAggregate) AddRelationship(
 	targetField string,
 	properties map[string]any,
 ) error {
-	sourceNode := g.findNode(sourceType, sourceKey, sourceField)
-	targetNode := g.findNode(targetType, targetKey, targetField)
+	return g.AddRelationshipWithKeyMatch(relType, direction, sourceType, sourceKey, sourceField, targetType, targetKey, targetField, properties, nil)
+}
+
+// AddRelationshipWithKeyMatch adds a relationship whose endpoints are found by comparing
+// keys normalized with keyMatch; a nil keyMatch compares keys exactly
+func (g *GraphAggregate) AddRelationshipWithKeyMatch(
+	relType string,
+	direction transform.Direction,
+	sourceType string,
+	sourceKey any,
+	sourceField string,
+	targetType string,
+	targetKey any,
+	targetField string,
+	properties map[string]any,
+	keyMatch *transform.KeyMatch,
+) error {
+	sourceNode := g.findNode(sourceType, sourceKey, sourceField, keyMatch)
+	targetNode := g.findNode(targetType, targetKey, targetField, keyMatch)
 
 	if sourceNode == nil || targetNode == nil {
 		logrus.Warnf("Could not find nodes for relationship: source=%s/%v target=%s/%v", sourceType, sourceKey, targetType, targetKey)
@@ -101,28 +202,127 @@ func (g *GraphAggregate) AddRelationship(
 	return nil
 }
 
+// AddWeightedRelationship adds a relationship like AddRelationshipWithKeyMatch, except that
+// a relationship of the same type between the same nodes is added only once: weightProperty
+// counts how often it was added, and the properties of the first addition are kept.
+func (g *GraphAggregate) AddWeightedRelationship(
+	relType string,
+	direction transform.Direction,
+	sourceType string,
+	sourceKey any,
+	sourceField string,
+	targetType string,
+	targetKey any,
+	targetField string,
+	properties map[string]any,
+	keyMatch *transform.KeyMatch,
+	weightProperty string,
+) error {
+	sourceNode := g.findNode(sourceType, sourceKey, sourceField, keyMatch)
+	targetNode := g.findNode(targetType, targetKey, targetField, keyMatch)
+
+	if sourceNode == nil || targetNode == nil {
+		logrus.Warnf("Could not find nodes for relationship: source=%s/%v target=%s/%v", sourceType, sourceKey, targetType, targetKey)
+		return fmt.Errorf("source or target node not found")
+	}
+
+	key := weightedKey{relType: relType, source: sourceNode, target: targetNode}
+	if index, exists := g.weighted[key]; exists {
+		weight, _ := g.relationships[index].Properties[weightProperty].(int64)
+		g.relationships[index].Properties[weightProperty] = weight + 1
+		return nil
+	}
+
+	if properties == nil {
+		properties = make(map[string]any)
+	}
+	properties[weightProperty] = int64(1)
+
+	if g.weighted == nil {
+		g.weighted = make(map[weightedKey]int)
+	}
+	g.weighted[key] = len(g.relationships)
+	g.relationships = append(g.relationships, Relationship{
+		Type:       relType,
+		Direction:  direction,
+		SourceNode: sourceNode,
+		TargetNode: targetNode,
+		Properties: properties,
+	})
+	return nil
+}
+
+// MergeRelationship adds a relationship like AddRelationshipWithKeyMatch, except that a
+// relationship with the same identity (type, endpoints and the value of the identity's
+// discriminator property) is added only once and takes the properties of later additions.
+// The relationship is also merged, not created, in the graph store.
+func (g *GraphAggregate) MergeRelationship(
+	relType string,
+	direction transform.Direction,
+	sourceType string,
+	sourceKey any,
+	sourceField string,
+	targetType string,
+	targetKey any,
+	targetField string,
+	properties map[string]any,
+	keyMatch *transform.KeyMatch,
+	identity transform.RelationshipIdentity,
+) error {
+	sourceNode := g.findNode(sourceType, sourceKey, sourceField, keyMatch)
+	targetNode := g.findNode(targetType, targetKey, targetField, keyMatch)
+
+	if sourceNode == nil || targetNode == nil {
+		logrus.Warnf("Could not find nodes for relationship: source=%s/%v target=%s/%v", sourceType, sourceKey, targetType, targetKey)
+		return fmt.Errorf("source or target node not found")
+	}
+
+	if properties == nil {
+		properties = make(map[string]any)
+	}
+	mergeKeys := make(map[string]any)
+	key := mergedKey{relType: relType, source: sourceNode, target: targetNode}
+	// A missing discriminator identifies the relationship by type and endpoints alone, as a
+	// NULL property cannot be merged on
+	if value := properties[identity.Discriminator]; identity.Discriminator != "" && value != nil {
+		mergeKeys[identity.Discriminator] = value
+		key.discriminator = fmt.Sprintf("%v", value)
+	}
+
+	if index, exists := g.merged[key]; exists {
+		existing := g.relationships[index].Properties
+		for name, value := range properties {
+			existing[name] = value
+		}
+		return nil
+	}
+
+	if g.merged == nil {
+		g.merged = make(map[mergedKey]int)
+	}
+	g.merged[key] = len(g.relationships)
+	g.relationships = append(g.relationships, Relationship{
+		Type:       relType,
+		Direction:  direction,
+		SourceNode: sourceNode,
+		TargetNode: targetNode,
+		Properties: properties,
+		Merged:     true,
+		MergeKeys:  mergeKeys,
+	})
+	return nil
+}
+
 func (g *GraphAggregate) ToCypher() string {
 	return ""
 }
 
-func (g *GraphAggregate) findNode(nodeType string, key any, field string) *entities.Node {
-	var keyStr string
-	switch v := key.(type) {
-	case []uint8:
-		keyStr = string(v)
-	default:
-		keyStr = fmt.Sprintf("%v", key)
-	}
+func (g *GraphAggregate) findNode(nodeType string, key any, field string, keyMatch *transform.KeyMatch) *entities.Node {
+	keyStr := keyMatch.Normalize(key)
 
 	for _, node := range g.nodes {
 		if node.Type == nodeType {
-			var nodeKeyStr string
-			switch v := node.Key.(type) {
-			case []uint8:
-				nodeKeyStr = string(v)
-			default:
-				nodeKeyStr = fmt.Sprintf("%v", node.Key)
-			}
+			nodeKeyStr := keyMatch.Normalize(node.Key)
 
 			if node.Type == nodeType && nodeKeyStr == keyStr && node.Field == field {
 				return node
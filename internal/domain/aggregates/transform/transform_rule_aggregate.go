@@ -12,9 +12,13 @@
 package transform
 
 import (
+	"encoding/base64"
 	"fmt"
+	"sort"
 	"sql-graph-visualizer/internal/domain/entities"
 	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
@@ -28,6 +32,8 @@ type RuleAggregate struct {
 	IsActive    bool
 	Conditions  map[string]any
 	Actions     map[string]any
+	// Origin is the rule file the rule came from, for tracing large split rule sets
+	Origin string
 }
 
 type NodeMapping struct {
@@ -36,7 +42,21 @@ type NodeMapping struct {
 	TargetField string
 }
 
+// MergeKeysField lists, on a transformed node, the properties that identify it in the graph
+// store when its rule has composite KeyColumns
+const MergeKeysField = "_merge_keys"
+
+// MergeStrategiesField carries, on a transformed node, the rule's MergeStrategies
+const MergeStrategiesField = "_merge_strategies"
+
+// LabelsField lists, on a transformed node, the labels it carries besides its _type
+const LabelsField = "_labels"
+
 func (t *RuleAggregate) ApplyRules(data []map[string]any) []any {
+	if t.expandsArray() {
+		data = t.expandArrayRecords(data)
+	}
+
 	var results []any
 	for _, record := range data {
 		logrus.Infof("Applying rule to record: %+v", record)
@@ -71,6 +91,17 @@ func (t *RuleAggregate) ApplyRule(data map[string]any) (any, error) {
 func (t *RuleAggregate) transformToNode(data map[string]any) (map[string]any, error) {
 	result := make(map[string]any)
 	result["_type"] = t.Rule.TargetType
+	if t.Rule.LabelFromColumn != "" {
+		label, ok := t.Rule.ResolveLabel(data[t.Rule.LabelFromColumn])
+		if !ok {
+			logrus.Warnf("Skipping row in rule %s: %s value %v is not an allowed label", t.Rule.Name, t.Rule.LabelFromColumn, data[t.Rule.LabelFromColumn])
+			return nil, fmt.Errorf("label %v from column %s is not allowed", data[t.Rule.LabelFromColumn], t.Rule.LabelFromColumn)
+		}
+		result["_type"] = label
+	}
+	if labels := t.Rule.AdditionalLabels(result["_type"].(string)); len(labels) > 0 {
+		result[LabelsField] = labels
+	}
 
 	logrus.Infof("FieldMappings: %+v", t.Rule.FieldMappings)
 
@@ -83,33 +114,334 @@ func (t *RuleAggregate) transformToNode(data map[string]any) (map[string]any, er
 		}
 	}
 
+	if len(t.Rule.KeyColumns) > 0 {
+		if err := t.setCompositeIdentity(data, result); err != nil {
+			logrus.Warnf("Skipping row in rule %s: %v", t.Rule.Name, err)
+			return nil, err
+		}
+	} else if t.Rule.HashIdentity {
+		result["id"] = transform.HashID(data)
+	} else if id, ok := result["id"]; ok && t.Rule.IDStrategy != "" {
+		result["id"] = t.nodeIDStrategy().NodeID([]any{id})
+	}
+
+	t.capValues(result)
+	if len(t.Rule.MergeStrategies) > 0 {
+		result[MergeStrategiesField] = t.Rule.MergeStrategies
+	}
 	return result, nil
 }
 
+// setCompositeIdentity gives a node the CompositeID of its key columns as id and lists the
+// key properties under MergeKeysField. Key columns are stored under their mapped names, or
+// their own names when the rule does not map them.
+func (t *RuleAggregate) setCompositeIdentity(data map[string]any, result map[string]any) error {
+	values := make([]any, len(t.Rule.KeyColumns))
+	properties := make([]string, len(t.Rule.KeyColumns))
+	for i, column := range t.Rule.KeyColumns {
+		value := data[column]
+		if value == nil {
+			return fmt.Errorf("NULL value in key column %s", column)
+		}
+		property := column
+		if mapped, ok := t.Rule.FieldMappings[column]; ok {
+			property = mapped
+		}
+		values[i] = value
+		properties[i] = property
+		result[property] = value
+	}
+
+	result["id"] = t.nodeIDStrategy().NodeID(values)
+	result[MergeKeysField] = properties
+	return nil
+}
+
+// nodeIDStrategy returns the rule's id strategy; rules are validated on load, so an invalid
+// strategy falls back to composite ids
+func (t *RuleAggregate) nodeIDStrategy() transform.IDStrategy {
+	strategy, err := t.Rule.NodeIDStrategy()
+	if err != nil {
+		return transform.CompositeIDStrategy{}
+	}
+	return strategy
+}
+
 func (t *RuleAggregate) transformToRelationship(data map[string]any) (map[string]any, error) {
 	result := make(map[string]any)
 	result["_type"] = t.Rule.RelationType
+	if t.Rule.RelationTypeFromColumn != "" {
+		relType, ok := t.Rule.ResolveRelationType(data[t.Rule.RelationTypeFromColumn])
+		if !ok {
+			logrus.Warnf("Skipping row in rule %s: %s value %v is not an allowed relationship type", t.Rule.Name, t.Rule.RelationTypeFromColumn, data[t.Rule.RelationTypeFromColumn])
+			return nil, fmt.Errorf("relationship type %v from column %s is not allowed", data[t.Rule.RelationTypeFromColumn], t.Rule.RelationTypeFromColumn)
+		}
+		result["_type"] = relType
+	}
 	result["_direction"] = t.Rule.Direction
-
-	result["source"] = map[string]any{
-		"type":  t.Rule.SourceNode.Type,
-		"key":   data[t.Rule.SourceNode.Key],
-		"field": t.Rule.SourceNode.TargetField,
+	if t.Rule.KeyMatch != nil {
+		result["_key_match"] = t.Rule.KeyMatch
+	}
+	if t.Rule.WeightProperty != "" {
+		result["_weight_property"] = t.Rule.WeightProperty
+	}
+	if t.Rule.RelationshipIdentity != nil {
+		result["_relationship_identity"] = t.Rule.RelationshipIdentity
 	}
 
-	result["target"] = map[string]any{
-		"type":  t.Rule.TargetNode.Type,
-		"key":   data[t.Rule.TargetNode.Key],
-		"field": t.Rule.TargetNode.TargetField,
+	sourceKey, targetKey := endpointKey(t.Rule.SourceNode, data), endpointKey(t.Rule.TargetNode, data)
+	if (sourceKey == nil || targetKey == nil) && t.Rule.NullKeys != transform.NullKeyBucket {
+		logrus.Debugf("Skipping row in rule %s: NULL relationship key", t.Rule.Name)
+		return nil, fmt.Errorf("NULL relationship key")
 	}
 
+	result["source"] = relationshipEndpoint(t.Rule.SourceNode, sourceKey)
+	result["target"] = relationshipEndpoint(t.Rule.TargetNode, targetKey)
+
 	properties := make(map[string]any)
+	if t.IsJunctionRule() && !t.expandsArray() {
+		for column, value := range t.junctionColumns(data) {
+			properties[column] = value
+		}
+	}
 	for sourceField, targetField := range t.Rule.Properties {
 		if value, exists := data[sourceField]; exists {
+			delete(properties, sourceField)
 			properties[targetField] = value
 		}
 	}
+	t.capValues(properties)
 	result["properties"] = properties
 
 	return result, nil
 }
+
+// capValues drops binary values (unless the rule includes them) and shortens long strings,
+// recording shortened property names under TruncatedMarker
+func (t *RuleAggregate) capValues(values map[string]any) {
+	limit := t.Rule.TextLimit()
+	var truncated []string
+
+	for key, value := range values {
+		switch v := value.(type) {
+		case []byte:
+			if !t.Rule.IncludeBinary {
+				logrus.Debugf("Omitting binary property %s (%d bytes) in rule %s", key, len(v), t.Rule.Name)
+				delete(values, key)
+				continue
+			}
+			values[key] = base64.StdEncoding.EncodeToString(v)
+		case string:
+			if limit > 0 && len(v) > limit {
+				values[key] = truncateUTF8(v, limit)
+				truncated = append(truncated, key)
+			}
+		}
+	}
+
+	if len(truncated) > 0 {
+		sort.Strings(truncated)
+		logrus.Warnf("Truncated properties %v to %d bytes in rule %s", truncated, limit, t.Rule.Name)
+		values[transform.TruncatedMarker] = truncated
+	}
+}
+
+// truncateUTF8 cuts s to at most limit bytes without splitting a multi-byte character
+func truncateUTF8(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// IsJunctionRule reports whether a relationship rule reads its rows straight from a
+// many-to-many junction table rather than from a custom query
+func (t *RuleAggregate) IsJunctionRule() bool {
+	return t.Rule.RuleType == transform.RelationshipRule &&
+		t.Rule.SourceTable != "" &&
+		t.Rule.SourceSQL == "" &&
+		t.Rule.SourceNode != nil &&
+		t.Rule.TargetNode != nil
+}
+
+// endpointKey reads the key of a relationship endpoint from a row. A composite key is the
+// CompositeID of its columns, or nil when any of them is NULL.
+func endpointKey(mapping *transform.NodeMapping, data map[string]any) any {
+	columns := mapping.Keys
+	if len(columns) == 0 {
+		if mapping.IDStrategy == "" {
+			return data[mapping.Key]
+		}
+		columns = []string{mapping.Key}
+	}
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		if data[column] == nil {
+			return nil
+		}
+		values[i] = data[column]
+	}
+	strategy, err := mapping.NodeIDStrategy()
+	if err != nil {
+		strategy = transform.CompositeIDStrategy{}
+	}
+	return strategy.NodeID(values)
+}
+
+// relationshipEndpoint describes one end of a relationship; a NULL key is replaced by the
+// unknown bucket node of the mapping's type. Composite keys and derived ids are matched
+// against node ids.
+func relationshipEndpoint(mapping *transform.NodeMapping, key any) map[string]any {
+	endpoint := map[string]any{
+		"type":  mapping.Type,
+		"key":   key,
+		"field": mapping.TargetField,
+	}
+	if len(mapping.Keys) > 0 || mapping.IDStrategy != "" {
+		endpoint["field"] = "id"
+	}
+	if key == nil {
+		endpoint["key"] = transform.UnknownNodeID
+		endpoint["bucket"] = true
+	}
+	return endpoint
+}
+
+// junctionColumns returns the columns of a junction row that describe the link itself,
+// i.e. everything except the two foreign keys and internal metadata such as _table
+func (t *RuleAggregate) junctionColumns(data map[string]any) map[string]any {
+	keyColumns := map[string]bool{t.Rule.SourceNode.Key: true, t.Rule.TargetNode.Key: true}
+	if t.Rule.RelationTypeFromColumn != "" {
+		keyColumns[t.Rule.RelationTypeFromColumn] = true
+	}
+	for _, column := range append(append([]string(nil), t.Rule.SourceNode.Keys...), t.Rule.TargetNode.Keys...) {
+		keyColumns[column] = true
+	}
+
+	columns := make(map[string]any)
+	for column, value := range data {
+		if keyColumns[column] {
+			continue
+		}
+		if strings.HasPrefix(column, "_") || value == nil {
+			continue
+		}
+		columns[column] = value
+	}
+	return columns
+}
+
+// expandsArray reports whether a relationship rule reads one of its keys from an array column
+func (t *RuleAggregate) expandsArray() bool {
+	return t.Rule.RuleType == transform.RelationshipRule &&
+		((t.Rule.SourceNode != nil && t.Rule.SourceNode.Array) ||
+			(t.Rule.TargetNode != nil && t.Rule.TargetNode.Array))
+}
+
+// expandArrayRecords replaces each record with one copy per element of its array key
+// columns, so every element becomes a separate relationship. Records with an empty or
+// NULL array produce no relationships.
+func (t *RuleAggregate) expandArrayRecords(data []map[string]any) []map[string]any {
+	var expanded []map[string]any
+	for _, record := range data {
+		records := []map[string]any{record}
+		for _, mapping := range []*transform.NodeMapping{t.Rule.SourceNode, t.Rule.TargetNode} {
+			if mapping == nil || !mapping.Array {
+				continue
+			}
+
+			var next []map[string]any
+			for _, r := range records {
+				for _, element := range arrayElements(r[mapping.Key]) {
+					copied := make(map[string]any, len(r))
+					for k, v := range r {
+						copied[k] = v
+					}
+					copied[mapping.Key] = element
+					next = append(next, copied)
+				}
+			}
+			records = next
+		}
+		expanded = append(expanded, records...)
+	}
+
+	logrus.Debugf("Expanded %d records into %d array elements for rule %s", len(data), len(expanded), t.Rule.Name)
+	return expanded
+}
+
+// arrayElements splits an array column value into its elements. It accepts Go slices,
+// Postgres array literals such as {1,2,3} or {"a b",c}, and MySQL SET values such as a,b,c.
+func arrayElements(value any) []any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	case []string:
+		elements := make([]any, len(v))
+		for i, element := range v {
+			elements[i] = element
+		}
+		return elements
+	case []int64:
+		elements := make([]any, len(v))
+		for i, element := range v {
+			elements[i] = element
+		}
+		return elements
+	case []byte:
+		return parseArrayLiteral(string(v))
+	case string:
+		return parseArrayLiteral(v)
+	default:
+		return []any{v}
+	}
+}
+
+func parseArrayLiteral(literal string) []any {
+	literal = strings.TrimSpace(literal)
+	if strings.HasPrefix(literal, "{") && strings.HasSuffix(literal, "}") {
+		literal = literal[1 : len(literal)-1]
+	}
+	if literal == "" {
+		return nil
+	}
+
+	var elements []any
+	var current strings.Builder
+	quoted, inQuotes := false, false
+	flush := func() {
+		element := current.String()
+		if !quoted {
+			element = strings.TrimSpace(element)
+		}
+		if quoted || (element != "" && !strings.EqualFold(element, "NULL")) {
+			elements = append(elements, element)
+		}
+		current.Reset()
+		quoted = false
+	}
+
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '\\' && inQuotes && i+1 < len(literal):
+			i++
+			current.WriteByte(literal[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case c == ',' && !inQuotes:
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return elements
+}
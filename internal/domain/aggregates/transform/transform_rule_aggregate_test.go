@@ -0,0 +1,594 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		limit    int
+		expected string
+	}{
+		{name: "shorter than limit", input: "abc", limit: 10, expected: "abc"},
+		{name: "ascii", input: "abcdef", limit: 4, expected: "abcd"},
+		{name: "does not split a rune", input: "žluťoučký", limit: 2, expected: "ž"},
+		{name: "cuts before multi-byte rune", input: "až", limit: 2, expected: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := truncateUTF8(tt.input, tt.limit)
+			assert.Equal(t, tt.expected, result)
+			assert.True(t, utf8.ValidString(result))
+		})
+	}
+}
+
+func TestArrayElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []any
+	}{
+		{name: "nil", input: nil, expected: nil},
+		{name: "postgres int array", input: []byte("{1,2,3}"), expected: []any{"1", "2", "3"}},
+		{name: "empty postgres array", input: "{}", expected: nil},
+		{name: "quoted elements", input: `{"a b",c,"d,e","f\"g"}`, expected: []any{"a b", "c", "d,e", `f"g`}},
+		{name: "null elements are skipped", input: "{1,NULL,2}", expected: []any{"1", "2"}},
+		{name: "mysql set", input: "read,write", expected: []any{"read", "write"}},
+		{name: "go slice", input: []int64{7, 8}, expected: []any{int64(7), int64(8)}},
+		{name: "scalar", input: int64(5), expected: []any{int64(5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, arrayElements(tt.input))
+		})
+	}
+}
+
+func TestApplyRules_LabelFromColumn(t *testing.T) {
+	rule := &RuleAggregate{Rule: transform.TransformRule{
+		Name:            "parties",
+		RuleType:        transform.NodeRule,
+		FieldMappings:   map[string]string{"id": "id"},
+		LabelFromColumn: "kind",
+		AllowedLabels:   []string{"Person", "Organization"},
+	}}
+
+	results := rule.ApplyRules([]map[string]any{
+		{"id": 1, "kind": " PERSON "},
+		{"id": 2, "kind": "Organization"},
+		{"id": 3, "kind": "Person) DETACH DELETE (n"},
+	})
+
+	require.Len(t, results, 2, "values outside the allowed labels are rejected")
+	assert.Equal(t, "Person", results[0].(map[string]any)["_type"])
+	assert.Equal(t, "Organization", results[1].(map[string]any)["_type"])
+}
+
+func TestApplyRules_AdditionalLabels(t *testing.T) {
+	rule := &RuleAggregate{Rule: transform.TransformRule{
+		Name:            "parties",
+		RuleType:        transform.NodeRule,
+		FieldMappings:   map[string]string{"id": "id"},
+		LabelFromColumn: "kind",
+		AllowedLabels:   []string{"Customer", "Supplier"},
+		Labels:          []string{"Person", "Customer"},
+	}}
+
+	results := rule.ApplyRules([]map[string]any{
+		{"id": 1, "kind": "customer"},
+		{"id": 2, "kind": "supplier"},
+	})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "Customer", results[0].(map[string]any)["_type"])
+	assert.Equal(t, []string{"Person"}, results[0].(map[string]any)[LabelsField], "the primary label is not repeated")
+	assert.Equal(t, "Supplier", results[1].(map[string]any)["_type"])
+	assert.Equal(t, []string{"Person", "Customer"}, results[1].(map[string]any)[LabelsField])
+	assert.Equal(t, []string{"Customer", "Supplier", "Person"}, rule.Rule.NodeLabels())
+}
+
+func TestValidateLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    transform.TransformRule
+		wantErr string
+	}{
+		{name: "fixed label", rule: transform.TransformRule{RuleType: transform.NodeRule, TargetType: "Person"}},
+		{name: "allowed labels", rule: transform.TransformRule{RuleType: transform.NodeRule, LabelFromColumn: "kind", AllowedLabels: []string{"Person", "Org_Unit"}}},
+		{name: "missing allowed labels", rule: transform.TransformRule{RuleType: transform.NodeRule, LabelFromColumn: "kind"}, wantErr: "requires allowed_labels"},
+		{name: "unsafe label", rule: transform.TransformRule{RuleType: transform.NodeRule, LabelFromColumn: "kind", AllowedLabels: []string{"Person:Admin"}}, wantErr: "not a valid node label"},
+		{name: "relationship rule", rule: transform.TransformRule{RuleType: transform.RelationshipRule, LabelFromColumn: "kind", AllowedLabels: []string{"Person"}}, wantErr: "only supported on node rules"},
+		{name: "additional labels", rule: transform.TransformRule{RuleType: transform.NodeRule, TargetType: "Customer", Labels: []string{"Person"}}},
+		{name: "unsafe additional label", rule: transform.TransformRule{RuleType: transform.NodeRule, TargetType: "Customer", Labels: []string{"Person`"}}, wantErr: "not a valid node label"},
+		{name: "labels on relationship rule", rule: transform.TransformRule{RuleType: transform.RelationshipRule, Labels: []string{"Person"}}, wantErr: "only supported on node rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.ValidateLabels()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplyRules_RelationTypeFromColumn(t *testing.T) {
+	rule := &RuleAggregate{Rule: transform.TransformRule{
+		Name:                   "person_links",
+		SourceSQL:              "SELECT person_id, related_id, relation_type FROM person_links",
+		RuleType:               transform.RelationshipRule,
+		RelationTypeFromColumn: "relation_type",
+		AllowedRelationTypes:   []string{"MANAGES", "MENTORS", "REPORTS_TO"},
+		SourceNode:             &transform.NodeMapping{Type: "Person", Key: "person_id", TargetField: "id"},
+		TargetNode:             &transform.NodeMapping{Type: "Person", Key: "related_id", TargetField: "id"},
+	}}
+
+	results := rule.ApplyRules([]map[string]any{
+		{"person_id": 1, "related_id": 2, "relation_type": "manages"},
+		{"person_id": 1, "related_id": 3, "relation_type": []byte("MENTORS")},
+		{"person_id": 2, "related_id": 1, "relation_type": " reports-to "},
+		{"person_id": 3, "related_id": 1, "relation_type": "KNOWS]->() DETACH DELETE (n"},
+		{"person_id": 3, "related_id": 2, "relation_type": nil},
+	})
+
+	require.Len(t, results, 3, "values outside the allowed types are rejected")
+	assert.Equal(t, "MANAGES", results[0].(map[string]any)["_type"])
+	assert.Equal(t, "MENTORS", results[1].(map[string]any)["_type"])
+	assert.Equal(t, "REPORTS_TO", results[2].(map[string]any)["_type"])
+
+	rule.Rule.RelationType = "RELATED_TO"
+	results = rule.ApplyRules([]map[string]any{{"person_id": 3, "related_id": 2, "relation_type": "knows"}})
+	require.Len(t, results, 1)
+	assert.Equal(t, "RELATED_TO", results[0].(map[string]any)["_type"], "other values fall back to relationship_type")
+	assert.Equal(t, []string{"RELATED_TO", "MANAGES", "MENTORS", "REPORTS_TO"}, rule.Rule.RelationTypes())
+}
+
+func TestValidateRelationTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    transform.TransformRule
+		wantErr string
+	}{
+		{name: "fixed type", rule: transform.TransformRule{RuleType: transform.RelationshipRule, RelationType: "KNOWS"}},
+		{name: "allowed types", rule: transform.TransformRule{RuleType: transform.RelationshipRule, RelationTypeFromColumn: "relation_type", AllowedRelationTypes: []string{"MANAGES", "REPORTS_TO"}}},
+		{name: "missing allowed types", rule: transform.TransformRule{RuleType: transform.RelationshipRule, RelationTypeFromColumn: "relation_type"}, wantErr: "requires allowed_relationship_types"},
+		{name: "unsafe type", rule: transform.TransformRule{RuleType: transform.RelationshipRule, RelationTypeFromColumn: "relation_type", AllowedRelationTypes: []string{"MANAGES|KNOWS"}}, wantErr: "not a valid relationship type"},
+		{name: "unsafe fallback", rule: transform.TransformRule{RuleType: transform.RelationshipRule, RelationType: "RELATED TO", RelationTypeFromColumn: "relation_type", AllowedRelationTypes: []string{"MANAGES"}}, wantErr: "not a valid relationship type"},
+		{name: "node rule", rule: transform.TransformRule{RuleType: transform.NodeRule, RelationTypeFromColumn: "relation_type", AllowedRelationTypes: []string{"MANAGES"}}, wantErr: "only supported on relationship rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.ValidateRelationTypes()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplyRules_CompositeKeyColumns(t *testing.T) {
+	rule := &RuleAggregate{Rule: transform.TransformRule{
+		Name:          "order_lines",
+		RuleType:      transform.NodeRule,
+		TargetType:    "OrderLine",
+		FieldMappings: map[string]string{"line_no": "line", "sku": "sku"},
+		KeyColumns:    []string{"order_id", "line_no"},
+	}}
+
+	results := rule.ApplyRules([]map[string]any{
+		{"order_id": int64(7), "line_no": int64(1), "sku": "A"},
+		{"order_id": int64(7), "line_no": int64(2), "sku": "B"},
+		{"order_id": nil, "line_no": int64(3), "sku": "C"},
+	})
+
+	require.Len(t, results, 2, "rows with a NULL key column are skipped")
+	first := results[0].(map[string]any)
+	assert.Equal(t, "7|1", first["id"])
+	assert.Equal(t, int64(7), first["order_id"], "unmapped key columns keep their name")
+	assert.Equal(t, int64(1), first["line"])
+	assert.Equal(t, []string{"order_id", "line"}, first[MergeKeysField])
+	assert.Equal(t, "7|2", results[1].(map[string]any)["id"])
+}
+
+func TestCompositeID(t *testing.T) {
+	assert.Equal(t, "7|1", transform.CompositeID([]any{int64(7), 1}))
+	assert.Equal(t, "ab|c", transform.CompositeID([]any{[]byte("ab"), "c"}))
+	assert.NotEqual(t,
+		transform.CompositeID([]any{"a|b", "c"}),
+		transform.CompositeID([]any{"a", "b|c"}),
+		"separators inside values are escaped")
+}
+
+func TestIDStrategiesAreStable(t *testing.T) {
+	uuidStrategy, err := transform.NewIDStrategy(transform.IDStrategyUUID, "OrderLine")
+	require.NoError(t, err)
+	otherNamespace, err := transform.NewIDStrategy(transform.IDStrategyUUID, "Invoice")
+	require.NoError(t, err)
+
+	strategies := map[transform.IDStrategyName]transform.IDStrategy{
+		transform.IDStrategyComposite: transform.CompositeIDStrategy{},
+		transform.IDStrategyHash:      transform.HashIDStrategy{},
+		transform.IDStrategyUUID:      uuidStrategy,
+	}
+	for name, strategy := range strategies {
+		t.Run(string(name), func(t *testing.T) {
+			id := strategy.NodeID([]any{int64(7), "A-1"})
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, strategy.NodeID([]any{int64(7), "A-1"}), "the same key gives the same id")
+			assert.Equal(t, id, strategy.NodeID([]any{int64(7), []byte("A-1")}), "byte slices are read as text")
+			assert.NotEqual(t, id, strategy.NodeID([]any{int64(7), "A-2"}))
+		})
+	}
+
+	assert.Len(t, transform.HashIDStrategy{}.NodeID([]any{"x"}), 32)
+	// Version 5 UUIDs are standard: uuid5(uuid5(NAMESPACE_URL, "OrderLine"), "7|A-1")
+	assert.Equal(t, "6d9d7c15-b3e9-590d-acf6-53637ff570bd", uuidStrategy.NodeID([]any{int64(7), "A-1"}))
+	assert.NotEqual(t, uuidStrategy.NodeID([]any{int64(7)}), otherNamespace.NodeID([]any{int64(7)}),
+		"namespaces keep equal keys of different node types apart")
+
+	explicit, err := transform.NewIDStrategy(transform.IDStrategyUUID, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	require.NoError(t, err)
+	assert.Equal(t, uuid.NameSpaceDNS, explicit.(transform.UUIDIDStrategy).Namespace)
+}
+
+func TestApplyRules_IDStrategy(t *testing.T) {
+	rule := &RuleAggregate{Rule: transform.TransformRule{
+		Name:          "order_lines",
+		RuleType:      transform.NodeRule,
+		TargetType:    "OrderLine",
+		FieldMappings: map[string]string{"line_no": "line"},
+		KeyColumns:    []string{"order_id", "line_no"},
+		IDStrategy:    transform.IDStrategyUUID,
+	}}
+	row := map[string]any{"order_id": int64(7), "line_no": int64(1)}
+
+	first := rule.ApplyRules([]map[string]any{row})
+	again := rule.ApplyRules([]map[string]any{row})
+	require.Len(t, first, 1)
+	id := first[0].(map[string]any)["id"]
+	assert.Equal(t, id, again[0].(map[string]any)["id"])
+
+	strategy, err := transform.NewIDStrategy(transform.IDStrategyUUID, "OrderLine")
+	require.NoError(t, err)
+	assert.Equal(t, strategy.NodeID([]any{int64(7), int64(1)}), id)
+	assert.Equal(t, int64(1), first[0].(map[string]any)["line"], "key columns stay properties")
+
+	// A relationship endpoint with the same strategy derives the same id
+	link := &RuleAggregate{Rule: transform.TransformRule{
+		Name:         "shipments",
+		RuleType:     transform.RelationshipRule,
+		RelationType: "SHIPS",
+		SourceNode:   &transform.NodeMapping{Type: "Shipment", Key: "shipment_id", TargetField: "id"},
+		TargetNode: &transform.NodeMapping{
+			Type: "OrderLine", Keys: []string{"order_id", "line_no"}, IDStrategy: transform.IDStrategyUUID,
+		},
+	}}
+	results := link.ApplyRules([]map[string]any{{"shipment_id": int64(3), "order_id": int64(7), "line_no": int64(1)}})
+	require.Len(t, results, 1)
+	target := results[0].(map[string]any)["target"].(map[string]any)
+	assert.Equal(t, id, target["key"])
+	assert.Equal(t, "id", target["field"])
+}
+
+func TestValidateIDStrategy(t *testing.T) {
+	valid := transform.TransformRule{RuleType: transform.NodeRule, TargetType: "User", IDStrategy: transform.IDStrategyHash}
+	assert.NoError(t, valid.ValidateIDStrategy())
+
+	unknown := transform.TransformRule{RuleType: transform.NodeRule, TargetType: "User", IDStrategy: "random"}
+	assert.ErrorContains(t, unknown.ValidateIDStrategy(), "unknown id strategy")
+
+	relationship := transform.TransformRule{RuleType: transform.RelationshipRule, IDStrategy: transform.IDStrategyHash}
+	assert.ErrorContains(t, relationship.ValidateIDStrategy(), "only applies to node rules")
+
+	array := transform.TransformRule{
+		RuleType:   transform.RelationshipRule,
+		SourceNode: &transform.NodeMapping{Type: "Tag", Key: "tags", Array: true, IDStrategy: transform.IDStrategyHash},
+	}
+	assert.ErrorContains(t, array.ValidateIDStrategy(), "array key")
+}
+
+func joinPathRule() transform.TransformRule {
+	return transform.TransformRule{
+		Name:         "employee_locations",
+		SourceTable:  "employees",
+		RuleType:     transform.RelationshipRule,
+		RelationType: "WORKS_AT",
+		SourceNode:   &transform.NodeMapping{Type: "Employee", Key: "id", TargetField: "id"},
+		TargetNode:   &transform.NodeMapping{Type: "Location", Key: "id", TargetField: "id"},
+		Properties:   map[string]string{"departments.name": "department", "role": "role"},
+		JoinPath: []transform.JoinStep{
+			{Table: "departments", From: "department_id", To: "id"},
+			{Table: "locations", From: "location_id", To: "id"},
+		},
+	}
+}
+
+func TestExpandJoinPath(t *testing.T) {
+	expanded, err := joinPathRule().ExpandJoinPath()
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT j0.id AS source_key, j2.id AS target_key, j1.name AS property_0, j0.role AS property_1 "+
+		"FROM employees j0 JOIN departments j1 ON j0.department_id = j1.id JOIN locations j2 ON j1.location_id = j2.id",
+		expanded.SourceSQL)
+	assert.Equal(t, transform.JoinSourceKey, expanded.SourceNode.Key)
+	assert.Equal(t, transform.JoinTargetKey, expanded.TargetNode.Key)
+	assert.Equal(t, map[string]string{"property_0": "department", "property_1": "role"}, expanded.Properties)
+
+	// The original rule keeps its own node mappings
+	original := joinPathRule()
+	_, err = original.ExpandJoinPath()
+	require.NoError(t, err)
+	assert.Equal(t, "id", original.SourceNode.Key)
+}
+
+func TestExpandJoinPath_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(rule *transform.TransformRule)
+		wantErr string
+	}{
+		{name: "node rule", modify: func(rule *transform.TransformRule) { rule.RuleType = transform.NodeRule }, wantErr: "only supported on relationship rules"},
+		{name: "source query", modify: func(rule *transform.TransformRule) { rule.SourceSQL = "SELECT 1" }, wantErr: "cannot be combined"},
+		{name: "missing target", modify: func(rule *transform.TransformRule) { rule.TargetNode = nil }, wantErr: "requires both"},
+		{name: "composite key", modify: func(rule *transform.TransformRule) { rule.SourceNode.Keys = []string{"a", "b"} }, wantErr: "single key column"},
+		{name: "unsafe table", modify: func(rule *transform.TransformRule) { rule.JoinPath[0].Table = "departments; DROP TABLE x" }, wantErr: "invalid table"},
+		{name: "unknown property table", modify: func(rule *transform.TransformRule) { rule.Properties = map[string]string{"offices.name": "office"} }, wantErr: "not on the join path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := joinPathRule()
+			tt.modify(&rule)
+			_, err := rule.ExpandJoinPath()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMergeStrategy(t *testing.T) {
+	tests := []struct {
+		strategy transform.MergeStrategy
+		existing any
+		incoming any
+		want     any
+	}{
+		{strategy: transform.MergeLastWins, existing: "a", incoming: "b", want: "b"},
+		{strategy: transform.MergeFirstWins, existing: "a", incoming: "b", want: "a"},
+		{strategy: transform.MergeFirstWins, existing: nil, incoming: "b", want: "b"},
+		{strategy: transform.MergeMax, existing: "9", incoming: "10", want: "10"},
+		{strategy: transform.MergeMin, existing: "9", incoming: "10", want: "9"},
+		{strategy: transform.MergeMax, existing: "b", incoming: "a", want: "b"},
+		{strategy: transform.MergeConcat, existing: "a", incoming: 2, want: "a, 2"},
+		{strategy: transform.MergeConcat, existing: "a", incoming: nil, want: "a"},
+		{strategy: transform.MergeArrayAppend, existing: []string{"a"}, incoming: "b", want: []string{"a", "b"}},
+		{strategy: transform.MergeArrayAppend, existing: nil, incoming: "b", want: []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.strategy.Merge(tt.existing, tt.incoming), "%s(%v, %v)", tt.strategy, tt.existing, tt.incoming)
+	}
+}
+
+func TestValidateMergeStrategies(t *testing.T) {
+	rule := transform.TransformRule{RuleType: transform.NodeRule, MergeStrategies: map[string]transform.MergeStrategy{"name": transform.MergeFirstWins}}
+	assert.NoError(t, rule.ValidateMergeStrategies())
+
+	rule.MergeStrategies["name"] = "newest"
+	assert.ErrorContains(t, rule.ValidateMergeStrategies(), `unknown merge strategy "newest"`)
+
+	rule = transform.TransformRule{RuleType: transform.RelationshipRule, MergeStrategies: map[string]transform.MergeStrategy{"since": transform.MergeMin}}
+	assert.ErrorContains(t, rule.ValidateMergeStrategies(), "only apply to node rules")
+}
+
+func TestStreamKey(t *testing.T) {
+	rule := transform.TransformRule{RuleType: transform.NodeRule, FieldMappings: map[string]string{"user_id": "id", "name": "name"}}
+	key, ok := rule.StreamKey()
+	assert.True(t, ok)
+	assert.Equal(t, "user_id", key)
+
+	rule.KeyColumns = []string{"order_id"}
+	key, ok = rule.StreamKey()
+	assert.True(t, ok)
+	assert.Equal(t, "order_id", key)
+
+	rule.KeyColumns = []string{"order_id", "line_no"}
+	_, ok = rule.StreamKey()
+	assert.False(t, ok, "composite keys cannot page a query")
+
+	_, ok = transform.TransformRule{RuleType: transform.NodeRule, FieldMappings: map[string]string{"u.id": "id"}}.StreamKey()
+	assert.False(t, ok)
+}
+
+func TestKeysetPageQuery(t *testing.T) {
+	query, err := transform.KeysetPageQuery("SELECT id, name FROM users;", "id", nil, 500)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT id, name FROM users) AS keyset_page ORDER BY id LIMIT 500", query)
+
+	query, err = transform.KeysetPageQuery("SELECT code FROM countries", "code", "O'K", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT code FROM countries) AS keyset_page WHERE code > 'O''K' ORDER BY code LIMIT 10", query)
+
+	_, err = transform.KeysetPageQuery("SELECT 1", "id; DROP", nil, 10)
+	assert.Error(t, err)
+}
+
+func TestKeysetPageQueryWithArgs(t *testing.T) {
+	placeholder := func(n int) string { return fmt.Sprintf("$%d", n) }
+	args := []any{"active"}
+
+	query, pageArgs, err := transform.KeysetPageQueryWithArgs("SELECT id FROM users WHERE status = $1", args, "id", nil, 100, placeholder)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT id FROM users WHERE status = $1) AS keyset_page ORDER BY id LIMIT 100", query)
+	assert.Equal(t, []any{"active"}, pageArgs)
+
+	query, pageArgs, err = transform.KeysetPageQueryWithArgs("SELECT id FROM users WHERE status = $1", args, "id", "x' OR 1=1", 100, placeholder)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT id FROM users WHERE status = $1) AS keyset_page WHERE id > $2 ORDER BY id LIMIT 100", query)
+	assert.Equal(t, []any{"active", "x' OR 1=1"}, pageArgs)
+	assert.Equal(t, []any{"active"}, args, "the rule's arguments are left as they are")
+}
+
+func TestPropertyNamingApply(t *testing.T) {
+	camel := transform.PropertyNaming{Case: transform.NamingCaseCamel}
+	assert.Equal(t, "createdAt", camel.Apply("created_at"))
+	assert.Equal(t, "orderLineNo", camel.Apply("order__line_no"))
+	assert.Equal(t, "id", camel.Apply("id"))
+	assert.Equal(t, "_source_table", camel.Apply("_source_table"), "internal properties keep their names")
+
+	snake := transform.PropertyNaming{Case: transform.NamingCaseSnake}
+	assert.Equal(t, "created_at", snake.Apply("createdAt"))
+	assert.Equal(t, "http_server_id", snake.Apply("HTTPServerId"))
+
+	custom := transform.PropertyNaming{
+		Case:          transform.NamingCaseCamel,
+		StripPrefixes: []string{"usr_", "tbl_"},
+		Rename:        map[string]string{"usr_email": "mail"},
+	}
+	assert.Equal(t, "mail", custom.Apply("usr_email"), "renames win over other rules")
+	assert.Equal(t, "firstName", custom.Apply("usr_first_name"))
+	assert.Equal(t, "usr_", transform.PropertyNaming{StripPrefixes: []string{"usr_"}}.Apply("usr_"), "a prefix is not stripped to nothing")
+
+	assert.Equal(t, "created_at", transform.PropertyNaming{}.Apply("created_at"))
+}
+
+func TestPropertyNamingRenameProperties(t *testing.T) {
+	naming := transform.PropertyNaming{Case: transform.NamingCaseCamel}
+	properties := map[string]any{"id": 1, "created_at": "2025-01-01", "createdAt": "kept", "first_name": "Ada"}
+
+	conflicts := naming.RenameProperties(properties)
+	assert.Equal(t, []string{"created_at"}, conflicts)
+	assert.Equal(t, map[string]any{"id": 1, "created_at": "2025-01-01", "createdAt": "kept", "firstName": "Ada"}, properties)
+}
+
+func TestPropertyNamingValidate(t *testing.T) {
+	assert.NoError(t, transform.PropertyNaming{Case: transform.NamingCaseSnake}.Validate())
+	assert.ErrorContains(t, transform.PropertyNaming{Case: "kebab"}.Validate(), `unknown property naming case "kebab"`)
+	assert.ErrorContains(t, transform.PropertyNaming{Rename: map[string]string{"user_id": "id"}}.Validate(), "id property")
+}
+
+func TestFilteredQuery(t *testing.T) {
+	rule := transform.TransformRule{
+		RuleType:    transform.NodeRule,
+		SourceTable: "users",
+		Filters: []transform.RuleFilter{
+			{Column: "status", Operator: "=", Value: "active"},
+			{Column: "role", Operator: "NOT  IN", Value: []any{"bot", "system"}},
+			{Column: "deleted_at", Operator: "is null"},
+		},
+	}
+	question := func(int) string { return "?" }
+
+	query, args, err := rule.FilteredQuery("", nil, question)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE status = ? AND role NOT IN (?, ?) AND deleted_at IS NULL", query)
+	assert.Equal(t, []any{"active", "bot", "system"}, args)
+
+	rule.SourceSQL = "SELECT * FROM users u JOIN teams t ON t.id = u.team_id;"
+	query, _, err = rule.FilteredQuery(rule.SourceSQL, nil, func(n int) string { return fmt.Sprintf("$%d", n) })
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM users u JOIN teams t ON t.id = u.team_id) AS filtered_source WHERE status = $1 AND role NOT IN ($2, $3) AND deleted_at IS NULL", query)
+}
+
+func TestFilteredQueryKeepsValuesOutOfSQL(t *testing.T) {
+	injection := "active' OR '1'='1"
+	rule := transform.TransformRule{
+		RuleType:    transform.NodeRule,
+		SourceTable: "users",
+		Filters:     []transform.RuleFilter{{Column: "status", Operator: "!=", Value: injection}},
+	}
+
+	query, args, err := rule.FilteredQuery("", nil, func(int) string { return "?" })
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE status <> ?", query)
+	assert.NotContains(t, query, "OR")
+	assert.Equal(t, []any{injection}, args)
+}
+
+func TestBindQueryParamsPassesValuesAsArguments(t *testing.T) {
+	injection := `x\' OR 1=1 -- `
+	params := map[string]string{"status": injection, "since": transform.RuntimeParamLastRun}
+	runtime := map[string]string{transform.RuntimeParamLastRun: "2025-01-02 03:04:05"}
+	dollar := func(n int) string { return fmt.Sprintf("$%d", n) }
+	rule := transform.TransformRule{
+		RuleType:  transform.NodeRule,
+		SourceSQL: "SELECT * FROM orders WHERE status = :status AND updated_at > :since",
+		Filters:   []transform.RuleFilter{{Column: "region", Value: "eu"}},
+	}
+
+	query, args, err := transform.BindQueryParams(rule.SourceSQL, params, runtime, dollar)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE status = $1 AND updated_at > $2", query)
+	assert.Equal(t, []any{injection, "2025-01-02 03:04:05"}, args)
+
+	query, args, err = rule.FilteredQuery(query, args, dollar)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM orders WHERE status = $1 AND updated_at > $2) AS filtered_source WHERE region = $3", query)
+	assert.Equal(t, []any{injection, "2025-01-02 03:04:05", "eu"}, args)
+}
+
+func TestValidateFilters(t *testing.T) {
+	base := transform.TransformRule{RuleType: transform.NodeRule, SourceTable: "users"}
+	tests := []struct {
+		filter transform.RuleFilter
+		err    string
+	}{
+		{transform.RuleFilter{Column: "status", Value: "active"}, ""},
+		{transform.RuleFilter{Column: "status = 1 OR 1", Operator: "=", Value: 1}, "plain column name"},
+		{transform.RuleFilter{Column: "status", Operator: "between", Value: 1}, `unknown operator "between"`},
+		{transform.RuleFilter{Column: "status", Operator: "="}, "needs a value"},
+		{transform.RuleFilter{Column: "status", Operator: "in", Value: "active"}, "non-empty list"},
+		{transform.RuleFilter{Column: "status", Operator: "is null", Value: "x"}, "takes no value"},
+	}
+	for _, tt := range tests {
+		rule := base
+		rule.Filters = []transform.RuleFilter{tt.filter}
+		if tt.err == "" {
+			assert.NoError(t, rule.ValidateFilters(), "%+v", tt.filter)
+		} else {
+			assert.ErrorContains(t, rule.ValidateFilters(), tt.err, "%+v", tt.filter)
+		}
+	}
+
+	rule := transform.TransformRule{RuleType: transform.RelationshipRule, Filters: []transform.RuleFilter{{Column: "a", Value: 1}}}
+	assert.ErrorContains(t, rule.ValidateFilters(), "need a source table or query")
+}
+
+func TestInferCardinality(t *testing.T) {
+	assert.Equal(t, transform.CardinalityOneToOne, transform.InferCardinality(true, true))
+	assert.Equal(t, transform.CardinalityOneToMany, transform.InferCardinality(true, false), "a repeated foreign key")
+	assert.Equal(t, transform.CardinalityOneToMany, transform.InferCardinality(false, true))
+	assert.Equal(t, transform.CardinalityManyToMany, transform.InferCardinality(false, false))
+}
@@ -11,6 +11,8 @@
 
 package entities
 
+import "slices"
+
 type Node struct {
 	BaseEntity
 	Type       string
@@ -18,6 +20,21 @@ type Node struct {
 	Field      string
 	Label      string
 	Properties map[string]any
+	// MergeKeys identifies a node with a composite key in the graph store; nodes without
+	// it are identified by their id property
+	MergeKeys map[string]any
+	// ExtraLabels are the labels the node carries besides Type
+	ExtraLabels []string
+}
+
+// Labels returns Type followed by the node's extra labels
+func (n *Node) Labels() []string {
+	return append([]string{n.Type}, n.ExtraLabels...)
+}
+
+// HasLabel reports whether the node carries label
+func (n *Node) HasLabel(label string) bool {
+	return slices.Contains(n.Labels(), label)
 }
 
 func NewNode(id string, label string) *Node {
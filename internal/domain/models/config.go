@@ -11,6 +11,8 @@
 
 package models
 
+import "time"
+
 // TransformationConfig represents a single transformation rule configuration.
 type TransformationConfig struct {
 	Name          string            `yaml:"name"`
@@ -26,6 +28,64 @@ type TransformationConfig struct {
 	Direction     string            `yaml:"direction,omitempty"`
 	Properties    map[string]string `yaml:"properties,omitempty"`
 	Priority      int               `yaml:"priority,omitempty"`
+	MaxTextLength int               `yaml:"max_text_length,omitempty"`
+	IncludeBinary bool              `yaml:"include_binary,omitempty"`
+	KeyMatch      *KeyMatchConfig   `yaml:"key_match,omitempty"`
+	// LabelFromColumn takes node labels from a column, limited to AllowedLabels
+	LabelFromColumn string   `yaml:"label_from_column,omitempty"`
+	AllowedLabels   []string `yaml:"allowed_labels,omitempty"`
+	// RelationTypeFromColumn takes relationship types from a column, limited to
+	// AllowedRelationTypes; RelationType is the fallback for other values
+	RelationTypeFromColumn string   `yaml:"relationship_type_from_column,omitempty"`
+	AllowedRelationTypes   []string `yaml:"allowed_relationship_types,omitempty"`
+	// Labels adds labels to every node of the rule, e.g. [Person] for :Customer:Person
+	Labels []string `yaml:"labels,omitempty"`
+	// WeightProperty merges relationships repeated across source rows, counting the rows in
+	// this property (e.g. "weight")
+	WeightProperty string `yaml:"weight_property,omitempty"`
+	// RelationshipIdentity merges relationships with the same type, endpoints and optional
+	// discriminator property instead of creating parallel relationships
+	RelationshipIdentity *RelationshipIdentityConfig `yaml:"relationship_identity,omitempty"`
+	// NullKeys is "skip" (default) to drop relationship rows with a NULL key, or "bucket" to
+	// link them to an unknown node instead
+	NullKeys string `yaml:"null_keys,omitempty"`
+	// KeyColumns gives node rules a composite identity, e.g. [order_id, line_no]
+	KeyColumns []string `yaml:"key_columns,omitempty"`
+	// IDStrategy derives node ids from the key: composite (default), hash or uuid. IDNamespace
+	// is the UUID namespace, by default the target type.
+	IDStrategy  string `yaml:"id_strategy,omitempty"`
+	IDNamespace string `yaml:"id_namespace,omitempty"`
+	// JoinPath joins a relationship rule's source table through bridge tables, so the
+	// relationship links the endpoints directly, e.g. employees -> departments -> locations
+	JoinPath []JoinStepConfig `yaml:"join_path,omitempty"`
+	// MergeStrategies combines the values of rows producing the same node, per property:
+	// last_wins (default), first_wins, max, min, concat or array_append
+	MergeStrategies map[string]string `yaml:"merge_strategies,omitempty"`
+	// Filters keep only the source rows matching every filter, e.g. status = active
+	Filters []RuleFilterConfig `yaml:"filters,omitempty"`
+
+	// Origin names the rule file the rule was loaded from; empty for the main config file
+	Origin string `yaml:"-"`
+}
+
+// TransformRuleFile is a standalone YAML file of transform rules inside TransformRulesDir.
+// Name identifies the file in logs and validation errors; it defaults to the file name.
+type TransformRuleFile struct {
+	Name           string                 `yaml:"name,omitempty"`
+	TransformRules []TransformationConfig `yaml:"transform_rules"`
+}
+
+// RuleBundleVersion is the bundle format version written by rule exports
+const RuleBundleVersion = 1
+
+// RuleBundle is a portable export of a complete transform rule set. SchemaFingerprint
+// identifies the table and column layout the rules were written against.
+type RuleBundle struct {
+	Version           int                    `yaml:"version"`
+	ExportedAt        time.Time              `yaml:"exported_at"`
+	DatabaseType      DatabaseType           `yaml:"database_type,omitempty"`
+	SchemaFingerprint string                 `yaml:"schema_fingerprint,omitempty"`
+	TransformRules    []TransformationConfig `yaml:"transform_rules"`
 }
 
 // NodeConfig represents node configuration for transformation rules.
@@ -52,13 +112,60 @@ type RelationNode struct {
 	Type        string `yaml:"type"`
 	Key         string `yaml:"key"`
 	TargetField string `yaml:"target_field"`
+	Array       bool   `yaml:"array,omitempty"`
+	// Keys references a node with a composite identity by these columns of the row
+	Keys []string `yaml:"keys,omitempty"`
+	// IDStrategy and IDNamespace must match the node rule of the referenced nodes
+	IDStrategy  string `yaml:"id_strategy,omitempty"`
+	IDNamespace string `yaml:"id_namespace,omitempty"`
+}
+
+// RuleFilterConfig compares Column with Value: operator is one of =, !=, <, <=, >, >=, like,
+// in and not in (with a list value), or is null and is not null (without a value)
+type RuleFilterConfig struct {
+	Column   string `yaml:"column"`
+	Operator string `yaml:"operator,omitempty"`
+	Value    any    `yaml:"value,omitempty"`
+}
+
+// JoinStepConfig joins Table onto the previous table of a join path, matching the From
+// column of the previous table to the To column of Table
+type JoinStepConfig struct {
+	Table string `yaml:"table"`
+	From  string `yaml:"from"`
+	To    string `yaml:"to"`
 }
 
 // SourceConfig represents data source configuration for transformations.
+// KeyMatchConfig normalizes relationship keys before endpoints are matched
+type KeyMatchConfig struct {
+	Trim            bool   `yaml:"trim,omitempty"`
+	CaseInsensitive bool   `yaml:"case_insensitive,omitempty"`
+	Collation       string `yaml:"collation,omitempty"`
+}
+
+// RelationshipIdentityConfig names the optional property that tells apart relationships of
+// one type between the same nodes
+type RelationshipIdentityConfig struct {
+	Discriminator string `yaml:"discriminator,omitempty"`
+}
+
 type SourceConfig struct {
 	Type        string `yaml:"type"`
 	Value       string `yaml:"value"`
 	SourceTable string `yaml:"source_table"`
+
+	// Query names an entry of the shared query library to use instead of Value
+	Query string `yaml:"query,omitempty"`
+	// Params binds the :name placeholders of the query; library defaults apply to unset names
+	Params map[string]string `yaml:"params,omitempty"`
+}
+
+// QueryDefinition is a reusable source query shared by transform rules
+type QueryDefinition struct {
+	SQL         string            `yaml:"sql"`
+	Description string            `yaml:"description,omitempty"`
+	Params      map[string]string `yaml:"params,omitempty"` // default parameter values
 }
 
 // ConnectionMode represents different database connection modes
@@ -72,12 +179,36 @@ const (
 
 // DataFilteringConfig represents options for filtering and limiting data
 type DataFilteringConfig struct {
-	SchemaDiscovery  bool              `yaml:"schema_discovery"`
-	TableWhitelist   []string          `yaml:"table_whitelist,omitempty"`
-	TableBlacklist   []string          `yaml:"table_blacklist,omitempty"`
-	RowLimitPerTable int               `yaml:"row_limit_per_table,omitempty"`
-	WhereConditions  map[string]string `yaml:"where_conditions,omitempty"`
-	QueryTimeout     int               `yaml:"query_timeout,omitempty"` // seconds
+	SchemaDiscovery  bool                `yaml:"schema_discovery"`
+	TableWhitelist   []string            `yaml:"table_whitelist,omitempty"`
+	TableBlacklist   []string            `yaml:"table_blacklist,omitempty"`
+	RowLimitPerTable int                 `yaml:"row_limit_per_table,omitempty"`
+	WhereConditions  map[string]string   `yaml:"where_conditions,omitempty"`
+	QueryTimeout     int                 `yaml:"query_timeout,omitempty"` // seconds
+	RowEstimation    RowEstimationConfig `yaml:"row_estimation,omitempty"`
+	// SystemSchemas replaces the internal schemas excluded by default (e.g. mysql, sys,
+	// pg_catalog); IncludeSystemTables turns the exclusion off
+	SystemSchemas       []string `yaml:"system_schemas,omitempty"`
+	IncludeSystemTables bool     `yaml:"include_system_tables,omitempty"`
+	// DiscoverIndexes reads the indexes of every analyzed table, which informs relationship
+	// inference and index recommendations but costs a catalog query per table
+	DiscoverIndexes bool `yaml:"discover_indexes,omitempty"`
+}
+
+// Row estimation methods
+const (
+	RowEstimationCatalog = "catalog"
+	RowEstimationSample  = "sample"
+)
+
+// RowEstimationConfig chooses how table row counts are estimated: from catalog statistics
+// (default), or by counting a bounded random sample and extrapolating
+type RowEstimationConfig struct {
+	Method string `yaml:"method,omitempty"`
+	// SamplePercent is the share of the table sampled (default 1)
+	SamplePercent float64 `yaml:"sample_percent,omitempty"`
+	// MaxSampleRows bounds the rows a sample query counts (default 10000)
+	MaxSampleRows int `yaml:"max_sample_rows,omitempty"`
 }
 
 // SecurityConfig represents security settings for database connections
@@ -108,12 +239,16 @@ type MySQLConfig struct {
 	User               string                   `yaml:"user"`
 	Username           string                   `yaml:"username,omitempty"` // alias for User
 	Password           string                   `yaml:"password"`
+	PasswordFile       string                   `yaml:"password_file,omitempty"` // overrides Password
 	Database           string                   `yaml:"database"`
 	ConnectionMode     ConnectionMode           `yaml:"connection_mode,omitempty"`
 	DataFiltering      DataFilteringConfig      `yaml:"data_filtering,omitempty"`
 	Security           SecurityConfig           `yaml:"security,omitempty"`
 	SSLConfig          SSLConfig                `yaml:"ssl,omitempty"`
 	AutoGeneratedRules AutoGeneratedRulesConfig `yaml:"auto_generated_rules,omitempty"`
+	// ParseTime reads DATETIME and TIMESTAMP columns as time values instead of the text
+	// MySQL sends; it is on unless set to false, and transform.timezone needs it
+	ParseTime *bool `yaml:"parse_time,omitempty"`
 }
 
 // BatchProcessingConfig represents settings for working with large datasets
@@ -121,6 +256,12 @@ type BatchProcessingConfig struct {
 	BatchSize       int `yaml:"batch_size"`
 	CommitFrequency int `yaml:"commit_frequency"`
 	MemoryLimitMB   int `yaml:"memory_limit_mb"`
+	// Write transactions are committed early before they exceed MaxTransactionEntities nodes
+	// and relationships or MaxTransactionBytes of parameters; TransactionTimeout (e.g. "2m")
+	// caps how long Neo4j runs one transaction
+	MaxTransactionEntities int    `yaml:"max_transaction_entities,omitempty"`
+	MaxTransactionBytes    int64  `yaml:"max_transaction_bytes,omitempty"`
+	TransactionTimeout     string `yaml:"transaction_timeout,omitempty"`
 }
 
 // Neo4jConfig represents Neo4j database connection configuration.
@@ -128,6 +269,7 @@ type Neo4jConfig struct {
 	URI             string                 `yaml:"uri"`
 	User            string                 `yaml:"user"`
 	Password        string                 `yaml:"password"`
+	PasswordFile    string                 `yaml:"password_file,omitempty"` // overrides Password
 	BatchProcessing *BatchProcessingConfig `yaml:"batch_processing,omitempty"`
 }
 
@@ -178,8 +320,197 @@ type Config struct {
 	// Performance .monitoring and benchmarking (Issue #12)
 	Performance *PerformanceConfig `yaml:"performance,omitempty"`
 
-	TransformRules     []TransformationConfig    `yaml:"transform_rules"`
-	AutoGeneratedRules *AutoGeneratedRulesConfig `yaml:"auto_generated_rules,omitempty"`
+	TransformRules     []TransformationConfig     `yaml:"transform_rules"`
+	Queries            map[string]QueryDefinition `yaml:"queries,omitempty"`
+	TransformRulesDir  string                     `yaml:"transform_rules_dir,omitempty"`
+	AutoGeneratedRules *AutoGeneratedRulesConfig  `yaml:"auto_generated_rules,omitempty"`
+
+	// Transform run settings
+	Transform *TransformRunConfig `yaml:"transform,omitempty"`
+
+	// Graph visualization views
+	Graph *GraphConfig `yaml:"graph,omitempty"`
+
+	// Startup behaviour while dependencies come up
+	Startup *StartupConfig `yaml:"startup,omitempty"`
+
+	// API server settings
+	API *APIConfig `yaml:"api,omitempty"`
+
+	// Visualization server settings
+	VisualizationServer *VisualizationServerConfig `yaml:"visualization_server,omitempty"`
+}
+
+// VisualizationServerConfig holds settings of the visualization web server
+type VisualizationServerConfig struct {
+	// BindAddress is the host:port the server listens on; by default all interfaces on the
+	// PORT environment variable or 3000. VISUALIZATION_ADDR overrides it.
+	BindAddress string `yaml:"bind_address,omitempty"`
+	// FallbackToFreePort serves on a free port of the same host when the address is in use,
+	// instead of failing to start
+	FallbackToFreePort bool `yaml:"fallback_to_free_port,omitempty"`
+}
+
+// APIConfig holds settings of the REST API server
+type APIConfig struct {
+	Auth *APIAuthConfig `yaml:"auth,omitempty"`
+}
+
+// APIAuthConfig lists bearer tokens by scope: read tokens may only call GET endpoints,
+// admin tokens may also trigger transforms, change configuration and other mutations.
+// The API is open when no tokens are set.
+type APIAuthConfig struct {
+	ReadTokens  []string `yaml:"read_tokens,omitempty"`
+	AdminTokens []string `yaml:"admin_tokens,omitempty"`
+	// PublicPaths are served without a token (health probes by default); a trailing "/"
+	// covers the paths below it
+	PublicPaths []string `yaml:"public_paths,omitempty"`
+	// AdminPaths require an admin token even for GET requests
+	AdminPaths []string `yaml:"admin_paths,omitempty"`
+}
+
+// Enabled reports whether any token is configured
+func (c APIAuthConfig) Enabled() bool {
+	return len(c.ReadTokens) > 0 || len(c.AdminTokens) > 0
+}
+
+// StartupConfig controls how long startup waits for the source database and Neo4j
+type StartupConfig struct {
+	// ConnectMaxWait bounds the total retry time per dependency (e.g. "2m"); "0s" fails on the first error
+	ConnectMaxWait string `yaml:"connect_max_wait,omitempty"`
+	// ConnectInitialBackoff is the first retry delay; it doubles up to ConnectMaxBackoff
+	ConnectInitialBackoff string `yaml:"connect_initial_backoff,omitempty"`
+	ConnectMaxBackoff     string `yaml:"connect_max_backoff,omitempty"`
+}
+
+// GraphConfig holds the saved views offered by the visualization
+type GraphConfig struct {
+	// DefaultView names the view served when none is requested; empty means the full graph
+	DefaultView string            `yaml:"default_view,omitempty"`
+	Views       []GraphViewConfig `yaml:"views,omitempty"`
+	// StylingRules set visual hints on the nodes and relationships matching their predicates
+	StylingRules []StylingRuleConfig `yaml:"styling_rules,omitempty"`
+	// Export bounds the memory used by GraphML and Cypher exports
+	Export *GraphExportConfig `yaml:"export,omitempty"`
+	// Queries are the named Cypher queries API clients may run; QueryMode "allow_list"
+	// (default) runs only these, "open" also accepts Cypher from the client
+	Queries   []GraphQueryConfig `yaml:"queries,omitempty"`
+	QueryMode string             `yaml:"query_mode,omitempty"`
+}
+
+// GraphQueryConfig is a named Cypher query whose Params are passed by the client and
+// referenced in the query as $name
+type GraphQueryConfig struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Cypher      string   `yaml:"cypher"`
+	Params      []string `yaml:"params,omitempty"`
+}
+
+// GraphExportConfig decides when a graph export is streamed instead of built in memory
+type GraphExportConfig struct {
+	// MaxInMemoryElements is the node plus relationship count above which the export is
+	// streamed in pages; zero means the default of 100000
+	MaxInMemoryElements int64 `yaml:"max_in_memory_elements,omitempty"`
+	// PageSize is how many nodes or relationships a streamed export reads per query
+	PageSize int `yaml:"page_size,omitempty"`
+}
+
+// StylingRuleConfig styles the nodes (or relationships) whose properties match every predicate
+type StylingRuleConfig struct {
+	Name   string                 `yaml:"name"`
+	Target string                 `yaml:"target,omitempty"`
+	Label  string                 `yaml:"label,omitempty"`
+	When   []StylePredicateConfig `yaml:"when,omitempty"`
+	Style  map[string]any         `yaml:"style"`
+}
+
+// StylePredicateConfig compares one property against Value with Operator (default eq)
+type StylePredicateConfig struct {
+	Property string `yaml:"property"`
+	Operator string `yaml:"operator,omitempty"`
+	Value    any    `yaml:"value,omitempty"`
+}
+
+// GraphViewConfig is a named Cypher query with display options for the frontend
+type GraphViewConfig struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	Cypher      string         `yaml:"cypher"`
+	Display     map[string]any `yaml:"display,omitempty"`
+}
+
+// TransformRunConfig controls how a transform run is executed
+type TransformRunConfig struct {
+	// Timeout bounds the whole transform (e.g. "10m"); empty means no limit
+	Timeout string `yaml:"timeout,omitempty"`
+	// PruneDanglingRelationships deletes relationships to nodes the transform did not create
+	// when the graph is validated after a run
+	PruneDanglingRelationships bool `yaml:"prune_dangling_relationships,omitempty"`
+	// RelationshipsOnly keeps the stored nodes and rebuilds only relationships, matching
+	// them against existing nodes; node rules are skipped
+	RelationshipsOnly bool `yaml:"relationships_only,omitempty"`
+	// ExcludeSoftDeleted skips source rows whose SoftDeleteColumn (default "deleted_at") is set
+	ExcludeSoftDeleted bool   `yaml:"exclude_soft_deleted,omitempty"`
+	SoftDeleteColumn   string `yaml:"soft_delete_column,omitempty"`
+	// Reconcile keeps the stored graph across restarts and writes only what changed in the
+	// source; nodes labelled AnnotationLabel (default "Annotation") are never touched
+	Reconcile       bool   `yaml:"reconcile,omitempty"`
+	AnnotationLabel string `yaml:"annotation_label,omitempty"`
+	// MaxConcurrentTables runs the node rules of up to that many source tables at once,
+	// each after the tables its foreign keys reference; 0 or 1 runs rules one by one
+	MaxConcurrentTables int `yaml:"max_concurrent_tables,omitempty"`
+	// SnapshotRetention is the number of completed runs whose graph snapshot is kept for
+	// comparison (default 20)
+	SnapshotRetention int `yaml:"snapshot_retention,omitempty"`
+	// TableGrowthInterval captures the size of every source table this often (e.g. "1h"),
+	// kept with the snapshots to chart table growth; empty disables the capture
+	TableGrowthInterval string `yaml:"table_growth_interval,omitempty"`
+	// IdentityFallback identifies the rows of node rules without a key whose table has no
+	// primary key: "hash" (default) hashes all columns, "generate" gives every row a new id
+	IdentityFallback string `yaml:"identity_fallback,omitempty"`
+	// ColumnLineage adds a Column node for every foreign key column and the column it
+	// references, linked by REFERENCES; off by default since it grows the graph considerably
+	ColumnLineage bool `yaml:"column_lineage,omitempty"`
+	// Provenance tags imported nodes and relationships with the source database, source
+	// table, run id and import time
+	Provenance bool `yaml:"provenance,omitempty"`
+	// RelationshipCardinality annotates imported relationships with the cardinality of their
+	// type ("1:1", "1:N" or "N:M"), inferred from the relationships and junction rules
+	RelationshipCardinality bool `yaml:"relationship_cardinality,omitempty"`
+	// Timezone stores timestamps as Neo4j temporal values in this IANA zone (e.g. "UTC");
+	// empty keeps them as text as read from the source
+	Timezone string `yaml:"timezone,omitempty"`
+	// SourceTimezone is the zone of source timestamps stored without one (MySQL DATETIME);
+	// empty takes them as read
+	SourceTimezone string `yaml:"source_timezone,omitempty"`
+	// LookupCacheRows caches, within a run, source query results of up to this many rows, so
+	// rules reading the same small lookup table query it once; 0 disables the cache.
+	// LookupCacheTotalRows bounds all cached rows (default 50000).
+	LookupCacheRows      int `yaml:"lookup_cache_rows,omitempty"`
+	LookupCacheTotalRows int `yaml:"lookup_cache_total_rows,omitempty"`
+	// StreamBatchSize reads source queries this many rows at a time instead of loading whole
+	// results, for tables too large to fit in memory; 0 reads whole results
+	StreamBatchSize int `yaml:"stream_batch_size,omitempty"`
+	// PropertyNaming renames imported properties, e.g. from snake_case to camelCase
+	PropertyNaming *PropertyNamingConfig `yaml:"property_naming,omitempty"`
+	// StateFile keeps the start of the last successful run, so "@last_run" query parameters
+	// resume from it after a restart (default: last_run.json in the user config directory)
+	StateFile string `yaml:"state_file,omitempty"`
+	// SchemaCacheTTL reuses the source schema discovered at startup for this long (e.g. "15m"),
+	// from SchemaCacheDir (default: the user cache directory, shared with the analyze command);
+	// empty reads the schema on every start
+	SchemaCacheTTL string `yaml:"schema_cache_ttl,omitempty"`
+	SchemaCacheDir string `yaml:"schema_cache_dir,omitempty"`
+}
+
+// PropertyNamingConfig renames node and relationship properties: names listed in Rename
+// take the given name, others lose the first matching StripPrefixes entry and are converted
+// to Case ("camel" or "snake"). id and internal properties keep their names.
+type PropertyNamingConfig struct {
+	Case          string            `yaml:"case,omitempty"`
+	StripPrefixes []string          `yaml:"strip_prefixes,omitempty"`
+	Rename        map[string]string `yaml:"rename,omitempty"`
 }
 
 // GetDatabaseConfig returns the active database configuration
@@ -212,6 +543,12 @@ type PerformanceConfig struct {
 	Realtime      *RealtimeConfig      `yaml:"realtime,omitempty"`
 	Benchmarks    *BenchmarksConfig    `yaml:"benchmarks,omitempty"`
 	Visualization *VisualizationConfig `yaml:"visualization,omitempty"`
+	// MetricPrecision is the number of decimal places metrics are rounded to in API responses (default 2)
+	MetricPrecision *int `yaml:"metric_precision,omitempty"`
+	// DiagnosticsToken is the bearer token for diagnostic endpoints; they are disabled when empty
+	DiagnosticsToken string `yaml:"diagnostics_token,omitempty"`
+	// BaselineDir is where captured performance baselines are stored (default: user config directory)
+	BaselineDir string `yaml:"baseline_dir,omitempty"`
 }
 
 // MonitoringConfig contains performance .monitoring settings
@@ -232,6 +569,39 @@ type PerformanceSchemaConfig struct {
 	IndexLimit      int    `yaml:"index_limit"`
 	ConnectionLimit int    `yaml:"connection_limit"`
 	CacheDuration   string `yaml:"cache_duration"`
+
+	// FocusedTables limits collection to statements involving these tables ("table" or "schema.table")
+	FocusedTables []string `yaml:"focused_tables,omitempty"`
+	// IgnoredTables skips statements that only touch these tables
+	IgnoredTables []string `yaml:"ignored_tables,omitempty"`
+
+	// CollectionBudget warns when one collection query runs longer (e.g. "2s")
+	CollectionBudget string `yaml:"collection_budget,omitempty"`
+	// AutoReduceLimits halves statement_limit or table_io_limit after an over-budget query
+	AutoReduceLimits bool `yaml:"auto_reduce_limits,omitempty"`
+
+	// ShareWindow hands a collection's result to callers arriving shortly after it finished
+	// (e.g. "1s") instead of querying again; concurrent callers always share one collection
+	ShareWindow string `yaml:"share_window,omitempty"`
+
+	// KeepaliveInterval pings the monitored database on this interval (e.g. "1m") so idle
+	// connections outlive the server's wait_timeout, reconnecting after a failed ping
+	KeepaliveInterval string `yaml:"keepalive_interval,omitempty"`
+
+	// ReplicaDSN points collection at a read replica instead of the transform source, in the
+	// driver's DSN format; collection falls back to the source when the replica is unreachable
+	ReplicaDSN string `yaml:"replica_dsn,omitempty"`
+
+	// StatementDeltas reports the real-time monitor's statement statistics per tick instead
+	// of cumulative since the digest summary was last reset
+	StatementDeltas bool `yaml:"statement_deltas,omitempty"`
+	// DigestResetInterval truncates the digest summary on this schedule (e.g. "1h"); it needs
+	// the DROP privilege on it and is switched off with a warning without it
+	DigestResetInterval string `yaml:"digest_reset_interval,omitempty"`
+
+	// DisableDigestTextFallback stops recovering the SQL of digests without text from
+	// events_statements_history; statements without digest text are then not mapped to tables
+	DisableDigestTextFallback bool `yaml:"disable_digest_text_fallback,omitempty"`
 }
 
 // AnalysisConfig contains performance analysis settings
@@ -252,8 +622,39 @@ type RealtimeConfig struct {
 	ReadTimeout        string       `yaml:"read_timeout"`
 	PingTimeout        string       `yaml:"ping_timeout"`
 	MaxMessageSize     int64        `yaml:"max_message_size"`
+	MaxOutboundSize    int          `yaml:"max_outbound_message_size"`
+	CoalesceWindow     string       `yaml:"coalesce_window"`
 	CompressionEnabled bool         `yaml:"compression_enabled"`
+	PollBufferSize     int          `yaml:"poll_buffer_size"`
+	MaxPollDuration    string       `yaml:"max_poll_duration"`
 	Alerts             *AlertConfig `yaml:"alerts,omitempty"`
+	// HistoryFile persists the metrics history; BackfillWindow is how much of it is loaded
+	// back on startup so trend analysis works right after a restart
+	HistoryFile    string `yaml:"history_file,omitempty"`
+	BackfillWindow string `yaml:"backfill_window,omitempty"`
+	// Auth enables the WebSocket auth handshake; every client sees every topic when unset
+	Auth *RealtimeAuthConfig `yaml:"auth,omitempty"`
+}
+
+// RealtimeAuthConfig maps WebSocket auth tokens onto what the client may see
+type RealtimeAuthConfig struct {
+	Tokens []RealtimeTokenConfig `yaml:"tokens"`
+	// Anonymous applies before a client authenticates; such clients see nothing when unset
+	Anonymous *RealtimeCapabilities `yaml:"anonymous,omitempty"`
+}
+
+// RealtimeTokenConfig grants capabilities to clients presenting Token
+type RealtimeTokenConfig struct {
+	Token                string `yaml:"token"`
+	RealtimeCapabilities `yaml:",inline"`
+}
+
+// RealtimeCapabilities lists the topics a client may subscribe to ("*" for all) and the
+// tables whose alerts and graph nodes it receives (all when empty)
+type RealtimeCapabilities struct {
+	Role   string   `yaml:"role"`
+	Topics []string `yaml:"topics"`
+	Tables []string `yaml:"tables,omitempty"`
 }
 
 // AlertConfig contains alert threshold settings
@@ -274,6 +675,29 @@ type BenchmarksConfig struct {
 	ResultsRetention string          `yaml:"results_retention"`
 	Sysbench         *SysbenchConfig `yaml:"sysbench,omitempty"`
 	Limits           *LimitsConfig   `yaml:"limits,omitempty"`
+	// Sinks receive every finished benchmark result
+	Sinks []BenchmarkSinkConfig `yaml:"sinks,omitempty"`
+	// RawOutput bounds the tool output kept with every result
+	RawOutput *BenchmarkRawOutputConfig `yaml:"raw_output,omitempty"`
+}
+
+// BenchmarkRawOutputConfig limits the raw tool output retained per benchmark: the last
+// MaxSize bytes are kept (0 keeps all), optionally gzip compressed, and the complete output
+// can be spilled to files in SpillDir
+type BenchmarkRawOutputConfig struct {
+	MaxSize  int    `yaml:"max_size"`
+	Compress bool   `yaml:"compress"`
+	SpillDir string `yaml:"spill_dir,omitempty"`
+}
+
+// BenchmarkSinkConfig describes where finished benchmark results are written: a csv or json
+// file at Path, or a Prometheus Pushgateway at URL under Job
+type BenchmarkSinkConfig struct {
+	Type    string `yaml:"type"`
+	Path    string `yaml:"path,omitempty"`
+	URL     string `yaml:"url,omitempty"`
+	Job     string `yaml:"job,omitempty"`
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 // SysbenchConfig contains Sysbench-specific settings
@@ -297,6 +721,9 @@ type LimitsConfig struct {
 	MaxConcurrentBenchmarks int     `yaml:"max_concurrent_benchmarks"`
 	MemoryLimitMB           int     `yaml:"memory_limit_mb"`
 	CPUThreshold            float64 `yaml:"cpu_threshold"`
+	// QueueBenchmarks makes submissions beyond MaxConcurrentBenchmarks wait instead of failing
+	QueueBenchmarks     bool `yaml:"queue_benchmarks,omitempty"`
+	MaxQueuedBenchmarks int  `yaml:"max_queued_benchmarks,omitempty"`
 }
 
 // VisualizationConfig contains graph visualization settings
@@ -19,6 +19,24 @@ const (
 	DatabaseTypePostgreSQL DatabaseType = "postgresql"
 )
 
+// Default server ports per database type
+const (
+	DefaultMySQLPort      = 3306
+	DefaultPostgreSQLPort = 5432
+)
+
+// DefaultPort returns the standard port for a database type, or 0 if unknown
+func DefaultPort(dbType DatabaseType) int {
+	switch dbType {
+	case DatabaseTypeMySQL:
+		return DefaultMySQLPort
+	case DatabaseTypePostgreSQL:
+		return DefaultPostgreSQLPort
+	default:
+		return 0
+	}
+}
+
 // DatabaseConfig represents generic database configuration interface
 type DatabaseConfig interface {
 	GetDatabaseType() DatabaseType
@@ -42,6 +60,7 @@ type PostgreSQLConfig struct {
 	User               string                   `yaml:"user"`
 	Username           string                   `yaml:"username,omitempty"` // alias for User
 	Password           string                   `yaml:"password"`
+	PasswordFile       string                   `yaml:"password_file,omitempty"` // overrides Password
 	Database           string                   `yaml:"database"`
 	Schema             string                   `yaml:"schema,omitempty"` // PostgreSQL-specific schema
 	ConnectionMode     ConnectionMode           `yaml:"connection_mode,omitempty"`
@@ -95,6 +114,11 @@ func (c *MySQLConfig) GetDatabase() string {
 	return c.Database
 }
 
+// ParsesTime reports whether DATETIME and TIMESTAMP columns are read as time values
+func (c *MySQLConfig) ParsesTime() bool {
+	return c.ParseTime == nil || *c.ParseTime
+}
+
 func (c *MySQLConfig) GetConnectionMode() ConnectionMode {
 	return c.ConnectionMode
 }
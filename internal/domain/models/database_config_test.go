@@ -0,0 +1,24 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPort(t *testing.T) {
+	assert.Equal(t, 3306, DefaultPort(DatabaseTypeMySQL))
+	assert.Equal(t, 5432, DefaultPort(DatabaseTypePostgreSQL))
+	assert.Equal(t, 0, DefaultPort(DatabaseType("sqlite")))
+}
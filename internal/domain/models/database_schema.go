@@ -13,6 +13,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -27,6 +28,42 @@ type ColumnInfo struct {
 	KeyType      string `json:"key_type,omitempty"` // PRIMARY, UNIQUE, INDEX, FOREIGN
 	Extra        string `json:"extra,omitempty"`    // auto_increment, etc.
 	Comment      string `json:"comment,omitempty"`
+	AuditRole    string `json:"audit_role,omitempty"` // created, updated or deleted timestamp
+}
+
+// Audit roles of temporal columns
+const (
+	AuditRoleCreated = "created"
+	AuditRoleUpdated = "updated"
+	AuditRoleDeleted = "deleted"
+)
+
+// auditColumnNames maps conventional audit column names (snake_case and camelCase) to roles
+var auditColumnNames = map[string]string{
+	"created_at":    AuditRoleCreated,
+	"createdat":     AuditRoleCreated,
+	"created_on":    AuditRoleCreated,
+	"creation_date": AuditRoleCreated,
+	"date_created":  AuditRoleCreated,
+	"inserted_at":   AuditRoleCreated,
+	"updated_at":    AuditRoleUpdated,
+	"updatedat":     AuditRoleUpdated,
+	"updated_on":    AuditRoleUpdated,
+	"modified_at":   AuditRoleUpdated,
+	"modifiedat":    AuditRoleUpdated,
+	"last_modified": AuditRoleUpdated,
+	"date_modified": AuditRoleUpdated,
+	"deleted_at":    AuditRoleDeleted,
+	"deletedat":     AuditRoleDeleted,
+	"deleted_on":    AuditRoleDeleted,
+	"removed_at":    AuditRoleDeleted,
+	"archived_at":   AuditRoleDeleted,
+}
+
+// DetectAuditRole returns the audit role a column name conventionally carries, or "" for
+// ordinary columns
+func DetectAuditRole(columnName string) string {
+	return auditColumnNames[strings.ToLower(columnName)]
 }
 
 // IndexInfo represents information about a database index
@@ -47,6 +84,15 @@ type ForeignKeyInfo struct {
 	OnUpdate         string `json:"on_update,omitempty"`
 }
 
+// ColumnReference links a foreign key column to the column it references
+type ColumnReference struct {
+	Table            string `json:"table"`
+	Column           string `json:"column"`
+	ReferencedTable  string `json:"referenced_table"`
+	ReferencedColumn string `json:"referenced_column"`
+	Constraint       string `json:"constraint,omitempty"`
+}
+
 // Constraint represents a database constraint
 type Constraint struct {
 	Name              string   `json:"name"`
@@ -101,6 +147,11 @@ type RelationshipInfo struct {
 	IsForeignKey bool    `json:"is_foreign_key"`
 	IsImplicit   bool    `json:"is_implicit"` // Discovered by naming convention
 	Confidence   float64 `json:"confidence"`  // 0.0 - 1.0 for implicit relationships
+
+	// Data profiling results, set when candidate values were sampled
+	Profiled    bool    `json:"profiled,omitempty"`
+	SampledRows int     `json:"sampled_rows,omitempty"`
+	MatchRatio  float64 `json:"match_ratio,omitempty"` // share of sampled values found in the referenced key
 }
 
 // SchemaAnalysisResult represents the result of database schema analysis
@@ -277,6 +328,7 @@ type UniversalDatabaseAnalysisResult struct {
 	Success            bool                           `json:"success"`
 	DatabaseType       DatabaseType                   `json:"database_type"`
 	ErrorMessage       string                         `json:"error_message,omitempty"`
+	FromCache          bool                           `json:"from_cache,omitempty"`
 	DatabaseInfo       *DatabaseConnectionInfo        `json:"database_info"`
 	SecurityValidation *SecurityValidationResult      `json:"security_validation,omitempty"`
 	SchemaAnalysis     *UniversalSchemaAnalysisResult `json:"schema_analysis"`
@@ -304,6 +356,9 @@ type UniversalSchemaAnalysisResult struct {
 	DiscoveredAt time.Time             `json:"discovered_at"`
 	Suggestions  []string              `json:"suggestions,omitempty"`
 	Warnings     []string              `json:"warnings,omitempty"`
+
+	// Relationships inferred from column naming conventions
+	ImplicitRelationships []RelationshipInfo `json:"implicit_relationships,omitempty"`
 }
 
 // UniversalTableInfo represents table information for any database type
@@ -311,6 +366,7 @@ type UniversalTableInfo struct {
 	Name            string          `json:"name"`
 	Schema          string          `json:"schema,omitempty"`
 	Columns         []*ColumnInfo   `json:"columns"`
+	Indexes         []IndexInfo     `json:"indexes,omitempty"`
 	Relationships   []*Relationship `json:"relationships,omitempty"`
 	EstimatedRows   int64           `json:"estimated_rows"`
 	Recommendations []string        `json:"recommendations,omitempty"`
@@ -368,6 +424,21 @@ type TableSize struct {
 	Fragmentation float64 `json:"fragmentation,omitempty"` // fragmentation percentage
 }
 
+// NewTableSize fills in the totals and megabyte figures from catalog byte counts
+func NewTableSize(tableName string, rowCount, dataSize, indexSize int64) *TableSize {
+	const bytesPerMB = 1024 * 1024
+	return &TableSize{
+		TableName:   tableName,
+		RowCount:    rowCount,
+		DataSize:    dataSize,
+		IndexSize:   indexSize,
+		TotalSize:   dataSize + indexSize,
+		DataSizeMB:  float64(dataSize) / bytesPerMB,
+		IndexSizeMB: float64(indexSize) / bytesPerMB,
+		TotalSizeMB: float64(dataSize+indexSize) / bytesPerMB,
+	}
+}
+
 // UserPrivileges contains information about database user privileges
 type UserPrivileges struct {
 	UserName      string                         `json:"user_name"`
@@ -0,0 +1,28 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAuditRole(t *testing.T) {
+	assert.Equal(t, AuditRoleCreated, DetectAuditRole("created_at"))
+	assert.Equal(t, AuditRoleCreated, DetectAuditRole("createdAt"))
+	assert.Equal(t, AuditRoleUpdated, DetectAuditRole("UPDATED_AT"))
+	assert.Equal(t, AuditRoleUpdated, DetectAuditRole("modified_at"))
+	assert.Equal(t, AuditRoleDeleted, DetectAuditRole("deleted_at"))
+	assert.Equal(t, "", DetectAuditRole("name"))
+	assert.Equal(t, "", DetectAuditRole("created_by"))
+}
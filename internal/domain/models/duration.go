@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that encodes in JSON and YAML as a readable string such as
+// "1.5s" instead of integer nanoseconds. It decodes from such a string or from nanoseconds.
+type Duration time.Duration
+
+// Std returns the duration as a time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration like time.Duration
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON encodes the duration as a string such as "1.5s"
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string such as "1.5s" or integer nanoseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", text, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var nanoseconds int64
+	if err := json.Unmarshal(data, &nanoseconds); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = Duration(nanoseconds)
+	return nil
+}
+
+// MarshalYAML encodes the duration as a string such as "1.5s"
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+// UnmarshalYAML decodes a duration string such as "1.5s" or integer nanoseconds
+func (d *Duration) UnmarshalYAML(unmarshal func(any) error) error {
+	var nanoseconds int64
+	if err := unmarshal(&nanoseconds); err == nil {
+		*d = Duration(nanoseconds)
+		return nil
+	}
+
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", text, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
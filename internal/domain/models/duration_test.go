@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDurationMarshalsAsString(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Elapsed Duration `json:"elapsed"`
+		Wait    Duration `json:"wait"`
+		None    Duration `json:"none"`
+	}{Duration(1500 * time.Millisecond), Duration(250 * time.Microsecond), 0})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"elapsed": "1.5s", "wait": "250µs", "none": "0s"}`, string(data))
+}
+
+func TestDurationUnmarshalsStringOrNanoseconds(t *testing.T) {
+	var parsed struct {
+		Text        Duration `json:"text"`
+		Nanoseconds Duration `json:"nanoseconds"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"text": "2m30s", "nanoseconds": 1500000000}`), &parsed))
+	assert.Equal(t, 150*time.Second, parsed.Text.Std())
+	assert.Equal(t, 1500*time.Millisecond, parsed.Nanoseconds.Std())
+
+	var invalid Duration
+	assert.Error(t, json.Unmarshal([]byte(`"soon"`), &invalid))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &invalid))
+}
+
+func TestDurationRoundTripsThroughYAML(t *testing.T) {
+	var parsed struct {
+		Text        Duration `yaml:"text"`
+		Nanoseconds Duration `yaml:"nanoseconds"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("text: 2m\nnanoseconds: 1500000000\n"), &parsed))
+	assert.Equal(t, 2*time.Minute, parsed.Text.Std())
+	assert.Equal(t, 1500*time.Millisecond, parsed.Nanoseconds.Std())
+
+	data, err := yaml.Marshal(parsed)
+	require.NoError(t, err)
+	assert.Equal(t, "text: 2m0s\nnanoseconds: 1.5s\n", string(data))
+
+	var invalid Duration
+	assert.Error(t, yaml.Unmarshal([]byte(`soon`), &invalid))
+}
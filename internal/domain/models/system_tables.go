@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import "strings"
+
+// defaultSystemSchemas are the internal schemas of each database type
+var defaultSystemSchemas = map[DatabaseType][]string{
+	DatabaseTypeMySQL:      {"information_schema", "mysql", "performance_schema", "sys"},
+	DatabaseTypePostgreSQL: {"information_schema", "pg_catalog", "pg_toast"},
+}
+
+// DefaultSystemSchemas returns the internal schemas of a database type, which discovery and
+// transform leave out unless configured otherwise
+func DefaultSystemSchemas(dbType DatabaseType) []string {
+	return append([]string(nil), defaultSystemSchemas[dbType]...)
+}
+
+// SystemSchemaList returns the schemas excluded as internal to the database: SystemSchemas
+// when configured, otherwise the defaults of dbType, and none with IncludeSystemTables
+func (f DataFilteringConfig) SystemSchemaList(dbType DatabaseType) []string {
+	if f.IncludeSystemTables {
+		return nil
+	}
+	if f.SystemSchemas != nil {
+		return f.SystemSchemas
+	}
+	return DefaultSystemSchemas(dbType)
+}
+
+// IsSystemSchema reports whether schema is excluded as internal to the database
+func (f DataFilteringConfig) IsSystemSchema(dbType DatabaseType, schema string) bool {
+	return IsSystemSchema(schema, f.SystemSchemaList(dbType))
+}
+
+// IsSystemSchema reports whether schema is one of systemSchemas. Schema names compare
+// case-insensitively, as MySQL reports INFORMATION_SCHEMA in upper case.
+func IsSystemSchema(schema string, systemSchemas []string) bool {
+	for _, system := range systemSchemas {
+		if strings.EqualFold(schema, system) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSystemTable reports whether a table name is qualified with one of systemSchemas, such as
+// mysql.user or pg_catalog.pg_class. Identifier quotes are ignored.
+func IsSystemTable(table string, systemSchemas []string) bool {
+	schema, _, qualified := strings.Cut(table, ".")
+	if !qualified {
+		return false
+	}
+	return IsSystemSchema(strings.Trim(strings.TrimSpace(schema), "`\""), systemSchemas)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemSchemasExcludedByDefault(t *testing.T) {
+	var filtering DataFilteringConfig
+
+	for _, schema := range []string{"mysql", "sys", "performance_schema", "INFORMATION_SCHEMA"} {
+		assert.True(t, filtering.IsSystemSchema(DatabaseTypeMySQL, schema), schema)
+	}
+	assert.False(t, filtering.IsSystemSchema(DatabaseTypeMySQL, "shop"))
+
+	for _, schema := range []string{"pg_catalog", "information_schema", "pg_toast"} {
+		assert.True(t, filtering.IsSystemSchema(DatabaseTypePostgreSQL, schema), schema)
+	}
+	assert.False(t, filtering.IsSystemSchema(DatabaseTypePostgreSQL, "public"))
+	assert.False(t, filtering.IsSystemSchema(DatabaseTypePostgreSQL, "mysql"), "lists are per database type")
+}
+
+func TestSystemSchemasOverride(t *testing.T) {
+	replaced := DataFilteringConfig{SystemSchemas: []string{"audit"}}
+	assert.True(t, replaced.IsSystemSchema(DatabaseTypeMySQL, "audit"))
+	assert.False(t, replaced.IsSystemSchema(DatabaseTypeMySQL, "mysql"), "the configured list replaces the defaults")
+
+	emptied := DataFilteringConfig{SystemSchemas: []string{}}
+	assert.Empty(t, emptied.SystemSchemaList(DatabaseTypeMySQL))
+
+	included := DataFilteringConfig{IncludeSystemTables: true, SystemSchemas: []string{"audit"}}
+	assert.Nil(t, included.SystemSchemaList(DatabaseTypePostgreSQL))
+	assert.False(t, included.IsSystemSchema(DatabaseTypePostgreSQL, "pg_catalog"))
+}
+
+func TestIsSystemTable(t *testing.T) {
+	schemas := DefaultSystemSchemas(DatabaseTypeMySQL)
+
+	assert.True(t, IsSystemTable("mysql.user", schemas))
+	assert.True(t, IsSystemTable("`sys`.`session`", schemas))
+	assert.False(t, IsSystemTable("shop.orders", schemas))
+	assert.False(t, IsSystemTable("user", schemas), "unqualified tables belong to the configured database")
+
+	// The defaults are copied, so callers cannot change them
+	schemas[0] = "shop"
+	assert.NotContains(t, DefaultSystemSchemas(DatabaseTypeMySQL), "shop")
+}
@@ -15,7 +15,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -41,6 +43,18 @@ func Load() (*models.Config, error) {
 		configPath = findProjectRoot() + "/config/config.yml"
 	}
 
+	return LoadProfile(configPath, ActiveProfile(os.Args[1:]))
+}
+
+// LoadFile loads the configuration file at configPath together with the rule files of its
+// transform_rules_dir, which is resolved relative to the file
+func LoadFile(configPath string) (*models.Config, error) {
+	return LoadProfile(configPath, "")
+}
+
+// LoadProfile loads the configuration file at configPath overlaid with the profile of the
+// given environment (see ProfilePath); an empty env loads the base file alone
+func LoadProfile(configPath, env string) (*models.Config, error) {
 	logrus.Infof("Loading configuration from YAML file: %s", configPath)
 
 	// Validate path to prevent directory traversal
@@ -55,12 +69,51 @@ func Load() (*models.Config, error) {
 		return nil, err
 	}
 
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		logrus.Errorf("Error parsing YAML: %v", err)
+		return nil, err
+	}
+
+	if env != "" {
+		if err := applyProfile(&root, cleanPath, env); err != nil {
+			return nil, err
+		}
+	}
+
 	var config models.Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := decodeWithEnv(&root, &config); err != nil {
 		logrus.Errorf("Error parsing YAML: %v", err)
 		return nil, err
 	}
 
+	if err := ResolveSecretFiles(&config, filepath.Dir(cleanPath)); err != nil {
+		return nil, err
+	}
+
+	if config.TransformRulesDir != "" {
+		rulesDir := config.TransformRulesDir
+		if !filepath.IsAbs(rulesDir) {
+			rulesDir = filepath.Join(filepath.Dir(cleanPath), rulesDir)
+		}
+
+		dirRules, err := LoadTransformRulesDir(rulesDir)
+		if err != nil {
+			return nil, err
+		}
+		config.TransformRules = append(config.TransformRules, dirRules...)
+		// Keep the resolved directory so rule bundle imports land next to the loaded rules
+		config.TransformRulesDir = rulesDir
+	}
+
+	if err := ValidateUniqueRuleNames(config.TransformRules); err != nil {
+		return nil, err
+	}
+
+	if err := ResolveRuleQueries(&config); err != nil {
+		return nil, err
+	}
+
 	logrus.Infof("Configuration loaded successfully:")
 	logrus.Infof("- MySQL: %s:%d/%s", config.MySQL.Host, config.MySQL.Port, config.MySQL.Database)
 	logrus.Infof("- Neo4j: %s", config.Neo4j.URI)
@@ -74,6 +127,198 @@ func Load() (*models.Config, error) {
 	return &config, nil
 }
 
+// LoadTransformRulesDir reads every .yml/.yaml file in dir as a TransformRuleFile and returns
+// the merged rules in file name order. Each rule is tagged with the name of its file.
+func LoadTransformRulesDir(dir string) ([]models.TransformationConfig, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transform rules directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yml", ".yaml":
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+
+	var rules []models.TransformationConfig
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule file %s: %w", file, err)
+		}
+
+		var ruleFile models.TransformRuleFile
+		if err := unmarshalWithEnv(data, &ruleFile); err != nil {
+			return nil, fmt.Errorf("failed to parse rule file %s: %w", file, err)
+		}
+
+		origin := ruleFile.Name
+		if origin == "" {
+			origin = file
+		}
+
+		logrus.Infof("Loaded %d transform rules from %s (%s)", len(ruleFile.TransformRules), file, origin)
+		for _, rule := range ruleFile.TransformRules {
+			rule.Origin = origin
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// ValidateUniqueRuleNames rejects rule sets where two rules share a name, reporting
+// where each copy was defined
+func ValidateUniqueRuleNames(rules []models.TransformationConfig) error {
+	seen := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		origin := rule.Origin
+		if origin == "" {
+			origin = "main config"
+		}
+		if previous, exists := seen[rule.Name]; exists {
+			return fmt.Errorf("duplicate transform rule %q defined in %s and %s", rule.Name, previous, origin)
+		}
+		seen[rule.Name] = origin
+	}
+	return nil
+}
+
+// ResolveRuleQueries replaces query library references in rule sources with the library SQL,
+// fills in default parameters and validates every parameterized query. Rules with inline SQL
+// and no params are left as they are.
+func ResolveRuleQueries(config *models.Config) error {
+	for i := range config.TransformRules {
+		rule := &config.TransformRules[i]
+		source := &rule.Source
+
+		if source.Query != "" {
+			definition, ok := config.Queries[source.Query]
+			if !ok {
+				return fmt.Errorf("transform rule %q references unknown query %q", rule.Name, source.Query)
+			}
+
+			params := make(map[string]string, len(definition.Params)+len(source.Params))
+			for name, value := range definition.Params {
+				params[name] = value
+			}
+			for name, value := range source.Params {
+				params[name] = value
+			}
+
+			source.Type = "query"
+			source.Value = definition.SQL
+			source.Params = params
+		}
+
+		if source.Type != "query" || (source.Query == "" && len(source.Params) == 0) {
+			continue
+		}
+		if err := transform.ValidateQueryParams(source.Value, source.Params); err != nil {
+			return fmt.Errorf("transform rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalWithEnv decodes YAML after resolving ${VAR} and ${VAR:-default} references in
+// scalar values from the environment. Comments are not interpolated.
+func unmarshalWithEnv(data []byte, out any) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	return decodeWithEnv(&root, out)
+}
+
+// decodeWithEnv decodes a parsed YAML document after resolving environment references
+func decodeWithEnv(root *yaml.Node, out any) error {
+	if err := interpolateNode(root); err != nil {
+		return err
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	return root.Decode(out)
+}
+
+func interpolateNode(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, "$") {
+		value, err := InterpolateEnv(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if value != node.Value {
+			node.Value = value
+			// Let plain scalars resolve to their real type (e.g. port: ${DB_PORT:-3306})
+			if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				node.Tag = ""
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		if err := interpolateNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InterpolateEnv replaces ${VAR} with the value of the environment variable VAR and
+// ${VAR:-default} with default when VAR is unset or empty. $${ escapes a literal ${.
+// A reference to an unset variable without a default is an error.
+func InterpolateEnv(value string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if strings.HasPrefix(value[i:], "$${") {
+			b.WriteString("${")
+			i += 2
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			b.WriteByte('$')
+			continue
+		}
+
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", value)
+		}
+		expr := value[i+2 : i+end]
+		i += end
+
+		name, fallback, hasDefault := strings.Cut(expr, ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable reference in %q", value)
+		}
+
+		envValue, set := os.LookupEnv(name)
+		switch {
+		case set && (envValue != "" || !hasDefault):
+			b.WriteString(envValue)
+		case hasDefault:
+			b.WriteString(fallback)
+		default:
+			return "", fmt.Errorf("environment variable %s is not set and has no default", name)
+		}
+	}
+
+	return b.String(), nil
+}
+
 func findProjectRoot() string {
 	wd, err := os.Getwd()
 	if err != nil {
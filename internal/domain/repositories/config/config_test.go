@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const usersRuleFile = `name: "identity"
+transform_rules:
+  - name: "users_to_nodes"
+    rule_type: "node"
+    target_type: "User"
+    field_mappings:
+      id: "id"
+`
+
+const projectsRuleFile = `transform_rules:
+  - name: "projects_to_nodes"
+    rule_type: "node"
+    target_type: "Project"
+  - name: "user_projects"
+    rule_type: "relationship"
+    relationship_type: "WORKS_ON"
+`
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
+
+func TestLoadTransformRulesDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-users.yml", usersRuleFile)
+	writeFile(t, dir, "20-projects.yaml", projectsRuleFile)
+	writeFile(t, dir, "README.md", "not a rule file")
+
+	rules, err := LoadTransformRulesDir(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, "users_to_nodes", rules[0].Name)
+	assert.Equal(t, "identity", rules[0].Origin, "file-level name is used when set")
+	assert.Equal(t, "projects_to_nodes", rules[1].Name)
+	assert.Equal(t, "20-projects.yaml", rules[1].Origin, "file name is used otherwise")
+	assert.Equal(t, "WORKS_ON", rules[2].RelationType)
+}
+
+func TestLoadTransformRulesDirRejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "10-users.yml", usersRuleFile)
+	writeFile(t, dir, "20-more-users.yml", `transform_rules:
+  - name: "users_to_nodes"
+    rule_type: "node"
+    target_type: "Person"
+`)
+
+	rules, err := LoadTransformRulesDir(dir)
+	require.NoError(t, err)
+
+	err = ValidateUniqueRuleNames(rules)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"users_to_nodes"`)
+	assert.Contains(t, err.Error(), "identity")
+	assert.Contains(t, err.Error(), "20-more-users.yml")
+}
+
+func TestLoadMergesTransformRulesDir(t *testing.T) {
+	dir := t.TempDir()
+	rulesDir := filepath.Join(dir, "rules")
+	require.NoError(t, os.Mkdir(rulesDir, 0700))
+	writeFile(t, rulesDir, "projects.yml", projectsRuleFile)
+
+	writeFile(t, dir, "config.yml", `transform_rules_dir: "rules"
+transform_rules:
+  - name: "users_to_nodes"
+    rule_type: "node"
+    target_type: "User"
+`)
+	t.Setenv("CONFIG_PATH", filepath.Join(dir, "config.yml"))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.TransformRules, 3)
+	assert.Empty(t, cfg.TransformRules[0].Origin)
+	assert.Equal(t, "projects.yml", cfg.TransformRules[2].Origin)
+
+	// A directory rule clashing with the main config is rejected at load time
+	writeFile(t, rulesDir, "users.yml", usersRuleFile)
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "main config")
+}
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("SGV_TEST_HOST", "db.internal")
+	t.Setenv("SGV_TEST_EMPTY", "")
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"${SGV_TEST_HOST}", "db.internal"},
+		{"mysql://${SGV_TEST_HOST}:3306", "mysql://db.internal:3306"},
+		{"${SGV_TEST_UNSET:-fallback}", "fallback"},
+		{"${SGV_TEST_EMPTY:-fallback}", "fallback"},
+		{"${SGV_TEST_EMPTY}", ""},
+		{"${SGV_TEST_HOST:-ignored}", "db.internal"},
+		{"${SGV_TEST_UNSET:-}", ""},
+		{"cost: $5", "cost: $5"},
+		{"$${SGV_TEST_HOST}", "${SGV_TEST_HOST}"},
+	}
+
+	for _, tt := range tests {
+		got, err := InterpolateEnv(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestInterpolateEnvMissingVariable(t *testing.T) {
+	_, err := InterpolateEnv("${SGV_TEST_UNSET}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SGV_TEST_UNSET is not set")
+
+	_, err = InterpolateEnv("${SGV_TEST_UNSET")
+	assert.Error(t, err)
+}
+
+func TestLoadInterpolatesEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", `# password: ${NOT_INTERPOLATED_IN_COMMENTS}
+mysql:
+  host: "${SGV_TEST_MYSQL_HOST:-localhost}"
+  port: ${SGV_TEST_MYSQL_PORT:-3306}
+  password: ${SGV_TEST_MYSQL_PASSWORD}
+neo4j:
+  uri: "bolt://${SGV_TEST_NEO4J_HOST}:7687"
+`)
+	t.Setenv("CONFIG_PATH", filepath.Join(dir, "config.yml"))
+	t.Setenv("SGV_TEST_MYSQL_PASSWORD", "s3cret")
+	t.Setenv("SGV_TEST_NEO4J_HOST", "graph")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.MySQL.Host)
+	assert.Equal(t, 3306, cfg.MySQL.Port, "plain scalars keep their type after interpolation")
+	assert.Equal(t, "s3cret", cfg.MySQL.Password)
+	assert.Equal(t, "bolt://graph:7687", cfg.Neo4j.URI)
+
+	t.Setenv("SGV_TEST_MYSQL_PORT", "3307")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3307, cfg.MySQL.Port)
+
+	require.NoError(t, os.Unsetenv("SGV_TEST_MYSQL_PASSWORD"))
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SGV_TEST_MYSQL_PASSWORD")
+	assert.Contains(t, err.Error(), "line 5")
+}
+
+func TestResolveRuleQueriesFromLibrary(t *testing.T) {
+	cfg := &models.Config{
+		Queries: map[string]models.QueryDefinition{
+			"orders_since": {
+				SQL:    "SELECT * FROM orders WHERE status = :status AND created_at > :since",
+				Params: map[string]string{"status": "paid", "since": "@last_run"},
+			},
+		},
+		TransformRules: []models.TransformationConfig{
+			{Name: "paid_orders", Source: models.SourceConfig{Query: "orders_since"}},
+			{Name: "refunds", Source: models.SourceConfig{Query: "orders_since", Params: map[string]string{"status": "refunded"}}},
+			{Name: "legacy", Source: models.SourceConfig{Type: "query", Value: "SELECT id::text FROM t WHERE a = ':x'"}},
+		},
+	}
+
+	require.NoError(t, ResolveRuleQueries(cfg))
+
+	paid := cfg.TransformRules[0].Source
+	assert.Equal(t, "query", paid.Type)
+	assert.Equal(t, cfg.Queries["orders_since"].SQL, paid.Value)
+	assert.Equal(t, map[string]string{"status": "paid", "since": "@last_run"}, paid.Params)
+
+	assert.Equal(t, "refunded", cfg.TransformRules[1].Source.Params["status"], "rule params override library defaults")
+	assert.Equal(t, "@last_run", cfg.TransformRules[1].Source.Params["since"])
+	assert.Nil(t, cfg.TransformRules[2].Source.Params, "inline SQL without params is untouched")
+}
+
+func TestResolveRuleQueriesRejectsInvalidReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  models.SourceConfig
+		wantErr string
+	}{
+		{
+			name:    "unknown query",
+			source:  models.SourceConfig{Query: "missing"},
+			wantErr: `unknown query "missing"`,
+		},
+		{
+			name:    "undefined parameter",
+			source:  models.SourceConfig{Type: "query", Value: "SELECT * FROM orders WHERE id > :from AND id < :to", Params: map[string]string{"from": "1"}},
+			wantErr: "undefined parameter :to",
+		},
+		{
+			name:    "unused parameter",
+			source:  models.SourceConfig{Type: "query", Value: "SELECT * FROM orders WHERE id > :from", Params: map[string]string{"from": "1", "form": "2"}},
+			wantErr: `parameter "form" is not used`,
+		},
+		{
+			name:    "unknown runtime value",
+			source:  models.SourceConfig{Type: "query", Value: "SELECT * FROM orders WHERE created_at > :since", Params: map[string]string{"since": "@yesterday"}},
+			wantErr: "unknown runtime value @yesterday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &models.Config{TransformRules: []models.TransformationConfig{{Name: "orders", Source: tt.source}}}
+			err := ResolveRuleQueries(cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `transform rule "orders"`)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
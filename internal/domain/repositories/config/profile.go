@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ProfileEnvVar selects the config profile when no --env flag is given
+const ProfileEnvVar = "APP_ENV"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ActiveProfile returns the environment given by --env (or --env=) in args, falling back to
+// APP_ENV; empty means no profile
+func ActiveProfile(args []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--env="); ok {
+			return value
+		}
+		if arg == "--env" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(ProfileEnvVar)
+}
+
+// ProfilePath returns the profile of env for the config file at configPath: the file next to
+// it with the environment before the extension, e.g. config/config.prod.yml
+func ProfilePath(configPath, env string) string {
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + env + ext
+}
+
+// applyProfile overlays the profile of env onto the parsed base config. A selected profile
+// must exist, so a mistyped environment does not silently run with the base settings.
+func applyProfile(root *yaml.Node, configPath, env string) error {
+	if !profileNamePattern.MatchString(env) {
+		return fmt.Errorf("invalid config profile %q: use letters, digits, '-' and '_'", env)
+	}
+
+	profilePath := ProfilePath(configPath, env)
+	data, err := os.ReadFile(profilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read config profile %s: %w", env, err)
+	}
+
+	var profile yaml.Node
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return fmt.Errorf("failed to parse config profile %s: %w", profilePath, err)
+	}
+
+	logrus.Infof("Applying config profile %s from %s", env, profilePath)
+	if profile.Kind == 0 {
+		return nil
+	}
+	if root.Kind == 0 {
+		*root = profile
+		return nil
+	}
+	root.Content[0] = mergeNodes(root.Content[0], profile.Content[0])
+	return nil
+}
+
+// mergeNodes overlays a profile value onto a base value. Mappings merge key by key, so a
+// profile only lists what differs. Lists of named entries (such as transform_rules) merge
+// entry by entry on their name, with new entries appended; other lists and scalars are
+// replaced by the profile's value.
+func mergeNodes(base, overlay *yaml.Node) *yaml.Node {
+	switch {
+	case base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(overlay.Content); i += 2 {
+			key, value := overlay.Content[i], overlay.Content[i+1]
+			if index := mappingIndex(base, key.Value); index >= 0 {
+				base.Content[index+1] = mergeNodes(base.Content[index+1], value)
+			} else {
+				base.Content = append(base.Content, key, value)
+			}
+		}
+		return base
+	case base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode &&
+		namedEntries(base) && namedEntries(overlay):
+		for _, entry := range overlay.Content {
+			name := entryName(entry)
+			merged := false
+			for i, existing := range base.Content {
+				if entryName(existing) == name {
+					base.Content[i] = mergeNodes(existing, entry)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				base.Content = append(base.Content, entry)
+			}
+		}
+		return base
+	default:
+		return overlay
+	}
+}
+
+// mappingIndex returns the position of key in a mapping node, or -1
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// namedEntries reports whether every entry of a list is a mapping with a name
+func namedEntries(list *yaml.Node) bool {
+	for _, entry := range list.Content {
+		if entryName(entry) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func entryName(entry *yaml.Node) string {
+	if entry.Kind != yaml.MappingNode {
+		return ""
+	}
+	if index := mappingIndex(entry, "name"); index >= 0 && entry.Content[index+1].Kind == yaml.ScalarNode {
+		return entry.Content[index+1].Value
+	}
+	return ""
+}
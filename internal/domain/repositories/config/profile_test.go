@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseProfileConfig = `mysql:
+  host: "localhost"
+  port: 3306
+  user: "dev"
+  database: "shop"
+  data_filtering:
+    table_whitelist: ["customers", "orders", "products"]
+neo4j:
+  uri: "bolt://localhost:7687"
+  user: "neo4j"
+transform:
+  timeout: "10m"
+transform_rules:
+  - name: "customers"
+    rule_type: "node"
+    target_type: "Customer"
+    field_mappings: { id: "id", name: "name" }
+  - name: "orders"
+    rule_type: "node"
+    target_type: "Order"
+`
+
+const prodProfileConfig = `mysql:
+  host: "db.prod.internal"
+  user: "${SGV_TEST_PROD_USER:-reader}"
+  data_filtering:
+    table_whitelist: ["customers"]
+neo4j:
+  uri: "bolt://graph.prod.internal:7687"
+transform_rules:
+  - name: "customers"
+    target_type: "Client"
+  - name: "invoices"
+    rule_type: "node"
+    target_type: "Invoice"
+`
+
+func TestLoadProfileOverridesBase(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", baseProfileConfig)
+	writeFile(t, dir, "config.prod.yml", prodProfileConfig)
+
+	cfg, err := LoadProfile(filepath.Join(dir, "config.yml"), "prod")
+	require.NoError(t, err)
+
+	assert.Equal(t, "db.prod.internal", cfg.MySQL.Host)
+	assert.Equal(t, "reader", cfg.MySQL.User, "profiles are interpolated like the base file")
+	assert.Equal(t, []string{"customers"}, cfg.MySQL.DataFiltering.TableWhitelist, "lists of values are replaced")
+	assert.Equal(t, "bolt://graph.prod.internal:7687", cfg.Neo4j.URI)
+
+	// Unspecified values are inherited from the base
+	assert.Equal(t, 3306, cfg.MySQL.Port)
+	assert.Equal(t, "shop", cfg.MySQL.Database)
+	assert.Equal(t, "neo4j", cfg.Neo4j.User)
+	require.NotNil(t, cfg.Transform)
+	assert.Equal(t, "10m", cfg.Transform.Timeout)
+
+	// Named rules merge by name and new ones are appended
+	require.Len(t, cfg.TransformRules, 3)
+	assert.Equal(t, "customers", cfg.TransformRules[0].Name)
+	assert.Equal(t, "Client", cfg.TransformRules[0].TargetType)
+	assert.Equal(t, "node", cfg.TransformRules[0].RuleType)
+	assert.Equal(t, map[string]string{"id": "id", "name": "name"}, cfg.TransformRules[0].FieldMappings)
+	assert.Equal(t, "Order", cfg.TransformRules[1].TargetType)
+	assert.Equal(t, "invoices", cfg.TransformRules[2].Name)
+}
+
+func TestLoadProfileWithoutEnvironmentUsesBase(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", baseProfileConfig)
+	writeFile(t, dir, "config.prod.yml", prodProfileConfig)
+
+	cfg, err := LoadFile(filepath.Join(dir, "config.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.MySQL.Host)
+	assert.Len(t, cfg.TransformRules, 2)
+}
+
+func TestLoadProfileRejectsMissingOrInvalidProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", baseProfileConfig)
+
+	_, err := LoadProfile(filepath.Join(dir, "config.yml"), "staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config profile staging")
+
+	_, err = LoadProfile(filepath.Join(dir, "config.yml"), "../secrets")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid config profile")
+}
+
+func TestLoadSelectsProfileFromEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", baseProfileConfig)
+	writeFile(t, dir, "config.prod.yml", prodProfileConfig)
+	t.Setenv("CONFIG_PATH", filepath.Join(dir, "config.yml"))
+	t.Setenv(ProfileEnvVar, "prod")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "db.prod.internal", cfg.MySQL.Host)
+}
+
+func TestActiveProfile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "staging")
+
+	assert.Equal(t, "prod", ActiveProfile([]string{"--env", "prod"}))
+	assert.Equal(t, "dev", ActiveProfile([]string{"-v", "--env=dev"}))
+	assert.Equal(t, "staging", ActiveProfile([]string{"--env"}), "a flag without value falls back to APP_ENV")
+	assert.Equal(t, "staging", ActiveProfile(nil))
+}
+
+func TestProfilePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("config", "config.prod.yml"), ProfilePath(filepath.Join("config", "config.yml"), "prod"))
+	assert.Equal(t, "cloud-config.dev.yaml", ProfilePath("cloud-config.yaml", "dev"))
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ErrSchemaMismatch is returned when a rule bundle references tables or columns the
+// target schema does not have
+var ErrSchemaMismatch = errors.New("rule bundle does not match the target schema")
+
+// SchemaColumns maps each table of a database to its column names
+type SchemaColumns map[string][]string
+
+// Fingerprint hashes the table and column names, ignoring order and case, so two
+// databases with the same layout share a fingerprint regardless of where they run
+func (s SchemaColumns) Fingerprint() string {
+	var entries []string
+	for table, columns := range s {
+		for _, column := range columns {
+			entries = append(entries, strings.ToLower(table)+"."+strings.ToLower(column))
+		}
+	}
+	sort.Strings(entries)
+
+	sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// lookup returns the column set of table, matching names case-insensitively
+func (s SchemaColumns) lookup(table string) (map[string]bool, bool) {
+	for name, columns := range s {
+		if !strings.EqualFold(name, table) {
+			continue
+		}
+		set := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			set[strings.ToLower(column)] = true
+		}
+		return set, true
+	}
+	return nil, false
+}
+
+// NewRuleBundle packages rules for export. The schema fingerprint is left empty when
+// schema is nil, e.g. when exporting without a database connection.
+func NewRuleBundle(rules []models.TransformationConfig, dbType models.DatabaseType, schema SchemaColumns) *models.RuleBundle {
+	bundle := &models.RuleBundle{
+		Version:        models.RuleBundleVersion,
+		ExportedAt:     time.Now().UTC().Truncate(time.Second),
+		DatabaseType:   dbType,
+		TransformRules: append([]models.TransformationConfig(nil), rules...),
+	}
+	if schema != nil {
+		bundle.SchemaFingerprint = schema.Fingerprint()
+	}
+	return bundle
+}
+
+// MarshalRuleBundle encodes a bundle as "yaml" or "json"
+func MarshalRuleBundle(bundle *models.RuleBundle, format string) ([]byte, error) {
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rule bundle: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "", "yaml", "yml":
+		return data, nil
+	case "json":
+		// Rule configs only carry yaml tags, so JSON goes through the YAML document to
+		// keep the same field names in both formats
+		var document any
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("failed to encode rule bundle: %w", err)
+		}
+		return json.MarshalIndent(document, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported rule bundle format %q", format)
+	}
+}
+
+// ParseRuleBundle decodes a YAML or JSON rule bundle and checks that it is self-consistent.
+// ${VAR} references are kept as written so they resolve on the importing side.
+func ParseRuleBundle(data []byte) (*models.RuleBundle, error) {
+	var bundle models.RuleBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse rule bundle: %w", err)
+	}
+
+	switch {
+	case bundle.Version == 0:
+		return nil, fmt.Errorf("rule bundle has no version")
+	case bundle.Version > models.RuleBundleVersion:
+		return nil, fmt.Errorf("rule bundle version %d is newer than the supported version %d", bundle.Version, models.RuleBundleVersion)
+	case len(bundle.TransformRules) == 0:
+		return nil, fmt.Errorf("rule bundle contains no transform rules")
+	}
+
+	if err := ValidateUniqueRuleNames(bundle.TransformRules); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// ValidateRuleBundle checks every table and column referenced by the bundle's table-sourced
+// rules against schema. Rules reading from a query are not checked, as their columns are
+// only known once the query runs, and neither are rules joining along a join path.
+func ValidateRuleBundle(bundle *models.RuleBundle, schema SchemaColumns) error {
+	var problems []string
+	for _, rule := range bundle.TransformRules {
+		table := ruleSourceTable(rule)
+		if table == "" || len(rule.JoinPath) > 0 {
+			continue
+		}
+
+		columns, ok := schema.lookup(table)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("rule %q: table %s not found", rule.Name, table))
+			continue
+		}
+
+		var missing []string
+		for _, column := range ruleColumns(rule) {
+			if !columns[strings.ToLower(column)] {
+				missing = append(missing, table+"."+column)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("rule %q: columns %s not found", rule.Name, strings.Join(missing, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func ruleSourceTable(rule models.TransformationConfig) string {
+	if rule.Source.Type != "table" {
+		return ""
+	}
+	if rule.Source.SourceTable != "" {
+		return rule.Source.SourceTable
+	}
+	return rule.Source.Value
+}
+
+// ruleColumns lists the distinct source columns a rule reads, in sorted order
+func ruleColumns(rule models.TransformationConfig) []string {
+	seen := make(map[string]bool)
+	for column := range rule.FieldMappings {
+		seen[column] = true
+	}
+	for column := range rule.Properties {
+		seen[column] = true
+	}
+	for _, column := range []string{rule.SourceNode.Key, rule.TargetNode.Key, rule.LabelFromColumn, rule.RelationTypeFromColumn} {
+		if column != "" {
+			seen[column] = true
+		}
+	}
+	for _, keys := range [][]string{rule.KeyColumns, rule.SourceNode.Keys, rule.TargetNode.Keys} {
+		for _, column := range keys {
+			seen[column] = true
+		}
+	}
+
+	columns := make([]string, 0, len(seen))
+	for column := range seen {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+var bundleFileNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// WriteRuleBundleFile stores the bundle's rules in dir as a rule file named after name,
+// ready to be picked up through transform_rules_dir. Existing files are not overwritten.
+func WriteRuleBundleFile(dir, name string, bundle *models.RuleBundle) (string, error) {
+	fileName := strings.Trim(bundleFileNameUnsafe.ReplaceAllString(name, "_"), "_")
+	if fileName == "" {
+		return "", fmt.Errorf("invalid rule file name %q", name)
+	}
+	path := filepath.Join(dir, fileName+".yml")
+
+	data, err := yaml.Marshal(models.TransformRuleFile{
+		Name:           name,
+		TransformRules: bundle.TransformRules,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rule file: %w", err)
+	}
+
+	// #nosec G304 - path is built from a sanitized file name inside the rules directory
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create rule file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("failed to write rule file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write rule file: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func bundleTestRules() []models.TransformationConfig {
+	return []models.TransformationConfig{
+		{
+			Name:          "users_to_nodes",
+			RuleType:      "node",
+			TargetType:    "User",
+			Source:        models.SourceConfig{Type: "table", Value: "users"},
+			FieldMappings: map[string]string{"id": "id", "email": "email"},
+		},
+		{
+			Name:         "user_team",
+			RuleType:     "relationship",
+			RelationType: "MEMBER_OF",
+			Source:       models.SourceConfig{Type: "table", Value: "memberships"},
+			SourceNode:   models.RelationNode{Type: "User", Key: "user_id", TargetField: "id"},
+			TargetNode:   models.RelationNode{Type: "Team", Key: "team_id", TargetField: "id"},
+		},
+		{
+			Name:       "active_users",
+			RuleType:   "node",
+			TargetType: "ActiveUser",
+			Source:     models.SourceConfig{Type: "query", Value: "SELECT id, anything FROM users"},
+		},
+	}
+}
+
+func bundleTestSchema() SchemaColumns {
+	return SchemaColumns{
+		"users":       {"id", "email", "created_at"},
+		"memberships": {"user_id", "team_id"},
+	}
+}
+
+func TestRuleBundleRoundTrip(t *testing.T) {
+	bundle := NewRuleBundle(bundleTestRules(), models.DatabaseTypeMySQL, bundleTestSchema())
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := MarshalRuleBundle(bundle, format)
+			require.NoError(t, err)
+
+			parsed, err := ParseRuleBundle(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, models.RuleBundleVersion, parsed.Version)
+			assert.Equal(t, models.DatabaseTypeMySQL, parsed.DatabaseType)
+			assert.Equal(t, bundleTestSchema().Fingerprint(), parsed.SchemaFingerprint)
+			assert.True(t, bundle.ExportedAt.Equal(parsed.ExportedAt))
+			// Compare the encoded rules, as JSON turns absent collections into empty ones
+			expected, err := yaml.Marshal(bundle.TransformRules)
+			require.NoError(t, err)
+			actual, err := yaml.Marshal(parsed.TransformRules)
+			require.NoError(t, err)
+			assert.YAMLEq(t, string(expected), string(actual))
+			assert.NoError(t, ValidateRuleBundle(parsed, bundleTestSchema()))
+		})
+	}
+}
+
+func TestValidateRuleBundleRejectsMissingColumns(t *testing.T) {
+	bundle := NewRuleBundle(bundleTestRules(), models.DatabaseTypeMySQL, nil)
+	schema := SchemaColumns{
+		"USERS":       {"ID"},
+		"memberships": {"user_id"},
+	}
+
+	err := ValidateRuleBundle(bundle, schema)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.Contains(t, err.Error(), "users.email")
+	assert.Contains(t, err.Error(), "memberships.team_id")
+	assert.NotContains(t, err.Error(), "users.id")
+	assert.NotContains(t, err.Error(), "active_users")
+
+	err = ValidateRuleBundle(bundle, SchemaColumns{"memberships": {"user_id", "team_id"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table users not found")
+}
+
+func TestSchemaFingerprintIgnoresOrderAndCase(t *testing.T) {
+	a := SchemaColumns{"users": {"id", "email"}, "teams": {"id"}}
+	b := SchemaColumns{"Teams": {"ID"}, "users": {"email", "id"}}
+	c := SchemaColumns{"users": {"id"}, "teams": {"id"}}
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.NotEqual(t, a.Fingerprint(), c.Fingerprint())
+}
+
+func TestParseRuleBundleRejectsInvalidBundles(t *testing.T) {
+	_, err := ParseRuleBundle([]byte("transform_rules: []\n"))
+	assert.ErrorContains(t, err, "no version")
+
+	_, err = ParseRuleBundle([]byte("version: 99\ntransform_rules:\n  - name: a\n"))
+	assert.ErrorContains(t, err, "newer than the supported version")
+
+	_, err = ParseRuleBundle([]byte("version: 1\ntransform_rules:\n  - name: a\n  - name: a\n"))
+	assert.ErrorContains(t, err, "duplicate transform rule")
+}
+
+func TestWriteRuleBundleFile(t *testing.T) {
+	dir := t.TempDir()
+	bundle := NewRuleBundle(bundleTestRules(), models.DatabaseTypeMySQL, nil)
+
+	path, err := WriteRuleBundleFile(dir, "team rules", bundle)
+	require.NoError(t, err)
+
+	rules, err := LoadTransformRulesDir(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, len(bundle.TransformRules))
+	assert.Equal(t, "team rules", rules[0].Origin)
+
+	_, err = WriteRuleBundleFile(dir, "team rules", bundle)
+	assert.Error(t, err, "existing rule files are not overwritten")
+	assert.FileExists(t, path)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// ReadSecretFile reads a secret mounted as a file, such as a Kubernetes or Vault secret,
+// trimming surrounding whitespace. Relative paths are resolved against baseDir.
+func ReadSecretFile(path, baseDir string) (string, error) {
+	if !filepath.IsAbs(path) && baseDir != "" {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+	return secret, nil
+}
+
+// ResolveSecretFiles replaces the passwords of every configured connection with the content
+// of its password_file, when one is set
+func ResolveSecretFiles(config *models.Config, baseDir string) error {
+	type secret struct {
+		name  string
+		file  string
+		value *string
+	}
+	secrets := []secret{
+		{"mysql.password_file", config.MySQL.PasswordFile, &config.MySQL.Password},
+		{"neo4j.password_file", config.Neo4j.PasswordFile, &config.Neo4j.Password},
+	}
+	if database := config.Database; database != nil {
+		if database.MySQL != nil {
+			secrets = append(secrets, secret{"database.mysql.password_file", database.MySQL.PasswordFile, &database.MySQL.Password})
+		}
+		if database.PostgreSQL != nil {
+			secrets = append(secrets, secret{"database.postgresql.password_file", database.PostgreSQL.PasswordFile, &database.PostgreSQL.Password})
+		}
+	}
+
+	for _, s := range secrets {
+		if s.file == "" {
+			continue
+		}
+		value, err := ReadSecretFile(s.file, baseDir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.value = value
+	}
+	return nil
+}
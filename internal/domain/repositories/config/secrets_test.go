@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFileReadsPasswordFiles(t *testing.T) {
+	dir := t.TempDir()
+	secrets := t.TempDir()
+	writeFile(t, secrets, "neo4j-password", "graph-secret\n")
+	writeFile(t, dir, "pg-password", "  pg-secret  \n")
+	writeFile(t, dir, "config.yml", `mysql:
+  password: "inline"
+neo4j:
+  uri: "bolt://localhost:7687"
+  password: "inline"
+  password_file: "`+filepath.Join(secrets, "neo4j-password")+`"
+database:
+  type: postgresql
+  postgresql:
+    password: "inline"
+    password_file: "pg-password"
+`)
+
+	cfg, err := LoadFile(filepath.Join(dir, "config.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "graph-secret", cfg.Neo4j.Password, "password_file overrides the inline password")
+	assert.Equal(t, "pg-secret", cfg.Database.PostgreSQL.Password, "relative paths are read next to the config file")
+	assert.Equal(t, "inline", cfg.MySQL.Password, "inline passwords are kept without a password_file")
+}
+
+func TestLoadFileRejectsMissingPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "config.yml", `mysql:
+  password_file: "/run/secrets/does-not-exist"
+`)
+
+	_, err := LoadFile(filepath.Join(dir, "config.yml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mysql.password_file")
+	assert.Contains(t, err.Error(), "/run/secrets/does-not-exist")
+}
+
+func TestReadSecretFileRejectsEmptyFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "empty", " \n")
+
+	_, err := ReadSecretFile("empty", dir)
+	assert.ErrorContains(t, err, "is empty")
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+)
+
+// Validate checks the structure of a loaded configuration without connecting to anything:
+// required connection fields, a known database type, parseable durations and well-formed
+// transform rules. It returns every problem found, or nil for a valid configuration.
+func Validate(config *models.Config) []error {
+	var errs []error
+	errs = append(errs, validateDatabase(config)...)
+	if config.Neo4j.URI == "" {
+		errs = append(errs, fmt.Errorf("neo4j.uri is required"))
+	}
+	errs = append(errs, validateDurations(config)...)
+
+	names := make(map[string]bool, len(config.TransformRules))
+	for i, rule := range config.TransformRules {
+		label := fmt.Sprintf("transform_rules[%d]", i)
+		if rule.Name != "" {
+			label = fmt.Sprintf("transform rule %q", rule.Name)
+			if names[rule.Name] {
+				errs = append(errs, fmt.Errorf("%s is defined more than once", label))
+			}
+			names[rule.Name] = true
+		}
+		for _, problem := range validateRule(config, rule) {
+			errs = append(errs, fmt.Errorf("%s: %s", label, problem))
+		}
+	}
+	return errs
+}
+
+// validateDatabase checks the source database section: database.type when the
+// multi-database section is used, the legacy mysql section otherwise
+func validateDatabase(config *models.Config) []error {
+	if config.Database == nil {
+		return validateConnection("mysql", config.MySQL.Host, config.MySQL.Database, config.MySQL.Port)
+	}
+
+	switch config.Database.Type {
+	case "":
+		return []error{fmt.Errorf("database.type is required (%s or %s)", models.DatabaseTypeMySQL, models.DatabaseTypePostgreSQL)}
+	case models.DatabaseTypeMySQL:
+		if config.Database.MySQL == nil {
+			return []error{fmt.Errorf("database.mysql is required for database type %s", config.Database.Type)}
+		}
+		mysql := config.Database.MySQL
+		return validateConnection("database.mysql", mysql.Host, mysql.Database, mysql.Port)
+	case models.DatabaseTypePostgreSQL:
+		if config.Database.PostgreSQL == nil {
+			return []error{fmt.Errorf("database.postgresql is required for database type %s", config.Database.Type)}
+		}
+		postgres := config.Database.PostgreSQL
+		return validateConnection("database.postgresql", postgres.Host, postgres.Database, postgres.Port)
+	default:
+		return []error{fmt.Errorf("database.type %q is unknown (expected %s or %s)",
+			config.Database.Type, models.DatabaseTypeMySQL, models.DatabaseTypePostgreSQL)}
+	}
+}
+
+// validateConnection checks the fields every connection needs; a zero port means the
+// default port of the database type
+func validateConnection(section, host, database string, port int) []error {
+	var errs []error
+	if host == "" {
+		errs = append(errs, fmt.Errorf("%s.host is required", section))
+	}
+	if database == "" {
+		errs = append(errs, fmt.Errorf("%s.database is required", section))
+	}
+	if port < 0 || port > 65535 {
+		errs = append(errs, fmt.Errorf("%s.port %d is not between 1 and 65535", section, port))
+	}
+	return errs
+}
+
+// validateDurations parses every duration setting that is set
+func validateDurations(config *models.Config) []error {
+	durations := [][2]string{}
+	add := func(path, value string) {
+		if value != "" {
+			durations = append(durations, [2]string{path, value})
+		}
+	}
+
+	if batch := config.Neo4j.BatchProcessing; batch != nil {
+		add("neo4j.batch_processing.transaction_timeout", batch.TransactionTimeout)
+	}
+	if startup := config.Startup; startup != nil {
+		add("startup.connect_max_wait", startup.ConnectMaxWait)
+		add("startup.connect_initial_backoff", startup.ConnectInitialBackoff)
+		add("startup.connect_max_backoff", startup.ConnectMaxBackoff)
+	}
+	if run := config.Transform; run != nil {
+		add("transform.timeout", run.Timeout)
+		add("transform.table_growth_interval", run.TableGrowthInterval)
+	}
+	if performance := config.Performance; performance != nil {
+		if monitoring := performance.Monitoring; monitoring != nil {
+			add("performance.monitoring.update_interval", monitoring.UpdateInterval)
+			add("performance.monitoring.data_retention", monitoring.DataRetention)
+			if schema := monitoring.PerformanceSchema; schema != nil {
+				add("performance.monitoring.performance_schema.cache_duration", schema.CacheDuration)
+				add("performance.monitoring.performance_schema.collection_budget", schema.CollectionBudget)
+				add("performance.monitoring.performance_schema.share_window", schema.ShareWindow)
+				add("performance.monitoring.performance_schema.digest_reset_interval", schema.DigestResetInterval)
+				add("performance.monitoring.performance_schema.keepalive_interval", schema.KeepaliveInterval)
+			}
+		}
+		if realtime := performance.Realtime; realtime != nil {
+			add("performance.realtime.update_interval", realtime.UpdateInterval)
+			add("performance.realtime.heartbeat_interval", realtime.HeartbeatInterval)
+			add("performance.realtime.write_timeout", realtime.WriteTimeout)
+			add("performance.realtime.read_timeout", realtime.ReadTimeout)
+			add("performance.realtime.ping_timeout", realtime.PingTimeout)
+			add("performance.realtime.coalesce_window", realtime.CoalesceWindow)
+			add("performance.realtime.max_poll_duration", realtime.MaxPollDuration)
+			add("performance.realtime.backfill_window", realtime.BackfillWindow)
+		}
+		if benchmarks := performance.Benchmarks; benchmarks != nil {
+			add("performance.benchmarks.default_duration", benchmarks.DefaultDuration)
+			add("performance.benchmarks.max_duration", benchmarks.MaxDuration)
+			add("performance.benchmarks.results_retention", benchmarks.ResultsRetention)
+			for i, sink := range benchmarks.Sinks {
+				add(fmt.Sprintf("performance.benchmarks.sinks[%d].timeout", i), sink.Timeout)
+			}
+		}
+		if visualization := performance.Visualization; visualization != nil {
+			add("performance.visualization.update_interval", visualization.UpdateInterval)
+			add("performance.visualization.history_retention", visualization.HistoryRetention)
+		}
+	}
+
+	var errs []error
+	for _, duration := range durations {
+		if _, err := time.ParseDuration(duration[1]); err != nil {
+			errs = append(errs, fmt.Errorf("%s %q is not a valid duration (e.g. \"30s\", \"5m\")", duration[0], duration[1]))
+		}
+	}
+	return errs
+}
+
+// validateRule returns what is missing or malformed in a transform rule
+func validateRule(config *models.Config, rule models.TransformationConfig) []string {
+	var problems []string
+	if rule.Name == "" {
+		problems = append(problems, "name is required")
+	}
+
+	// Relationship rules without a source link nodes already stored by other rules; only
+	// node rules need one
+	source := rule.Source
+	sourceless := source.Type == "" && source.Value == "" && source.SourceTable == "" && source.Query == ""
+	switch {
+	case sourceless && rule.RuleType != string(transform.NodeRule):
+	case source.Query != "":
+		if _, ok := config.Queries[source.Query]; !ok {
+			problems = append(problems, fmt.Sprintf("source.query references unknown query %q", source.Query))
+		}
+	case source.Type != "query" && source.Type != "table":
+		problems = append(problems, fmt.Sprintf("source.type %q must be query or table", source.Type))
+	case source.Value == "":
+		problems = append(problems, "source.value is required")
+	}
+
+	switch transform.RuleType(rule.RuleType) {
+	case transform.NodeRule:
+		if rule.TargetType == "" {
+			problems = append(problems, "target_type is required for node rules")
+		}
+	case transform.RelationshipRule:
+		if rule.RelationType == "" && rule.RelationTypeFromColumn == "" {
+			problems = append(problems, "relationship_type or relationship_type_from_column is required for relationship rules")
+		}
+		if rule.RelationTypeFromColumn != "" && len(rule.AllowedRelationTypes) == 0 {
+			problems = append(problems, "allowed_relationship_types is required with relationship_type_from_column")
+		}
+		if rule.SourceNode.Type == "" {
+			problems = append(problems, "source_node.type is required for relationship rules")
+		}
+		if rule.TargetNode.Type == "" {
+			problems = append(problems, "target_node.type is required for relationship rules")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("rule_type %q must be %s or %s", rule.RuleType, transform.NodeRule, transform.RelationshipRule))
+	}
+	return problems
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newValidConfig has a PostgreSQL source, a node rule and a relationship rule linking
+// stored nodes
+func newValidConfig() *models.Config {
+	return &models.Config{
+		Database: &models.DatabaseSelector{
+			Type:       models.DatabaseTypePostgreSQL,
+			PostgreSQL: &models.PostgreSQLConfig{Host: "localhost", Database: "shop", User: "graph"},
+		},
+		Neo4j:     models.Neo4jConfig{URI: "bolt://localhost:7687"},
+		Startup:   &models.StartupConfig{ConnectMaxWait: "2m"},
+		Transform: &models.TransformRunConfig{Timeout: "10m"},
+		TransformRules: []models.TransformationConfig{
+			{
+				Name:       "customers",
+				RuleType:   "node",
+				Source:     models.SourceConfig{Type: "table", Value: "customers"},
+				TargetType: "Customer",
+			},
+			{
+				Name:         "referrals",
+				RuleType:     "relationship",
+				RelationType: "REFERRED",
+				SourceNode:   models.RelationNode{Type: "Customer", Key: "id", TargetField: "id"},
+				TargetNode:   models.RelationNode{Type: "Customer", Key: "referred_by", TargetField: "id"},
+			},
+		},
+	}
+}
+
+func errorMessages(errs []error) []string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestValidateAcceptsValidConfig(t *testing.T) {
+	assert.Empty(t, Validate(newValidConfig()))
+}
+
+func TestValidateReportsProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*models.Config)
+		want   []string
+	}{
+		{
+			name:   "bad duration",
+			change: func(c *models.Config) { c.Transform.Timeout = "10 minutes" },
+			want:   []string{`transform.timeout "10 minutes" is not a valid duration (e.g. "30s", "5m")`},
+		},
+		{
+			name:   "unknown database type",
+			change: func(c *models.Config) { c.Database.Type = "oracle" },
+			want:   []string{`database.type "oracle" is unknown (expected mysql or postgresql)`},
+		},
+		{
+			name: "malformed rule",
+			change: func(c *models.Config) {
+				c.TransformRules[0].TargetType = ""
+				c.TransformRules[0].Source.Type = "view"
+				c.TransformRules[1].RuleType = "edge"
+			},
+			want: []string{
+				`transform rule "customers": source.type "view" must be query or table`,
+				`transform rule "customers": target_type is required for node rules`,
+				`transform rule "referrals": rule_type "edge" must be node or relationship`,
+			},
+		},
+		{
+			name: "missing required fields",
+			change: func(c *models.Config) {
+				c.Database = nil
+				c.Neo4j.URI = ""
+				c.TransformRules[1].Name = ""
+			},
+			want: []string{
+				"mysql.host is required",
+				"mysql.database is required",
+				"neo4j.uri is required",
+				"transform_rules[1]: name is required",
+			},
+		},
+		{
+			name: "relationship type from column",
+			change: func(c *models.Config) {
+				c.TransformRules[1].RelationType = ""
+				c.TransformRules[1].RelationTypeFromColumn = "relation_type"
+			},
+			want: []string{
+				`transform rule "referrals": allowed_relationship_types is required with relationship_type_from_column`,
+			},
+		},
+		{
+			name:   "missing relationship type",
+			change: func(c *models.Config) { c.TransformRules[1].RelationType = "" },
+			want: []string{
+				`transform rule "referrals": relationship_type or relationship_type_from_column is required for relationship rules`,
+			},
+		},
+		{
+			name: "duplicate rule and unknown query",
+			change: func(c *models.Config) {
+				c.TransformRules[1].Name = "customers"
+				c.TransformRules[1].Source = models.SourceConfig{Query: "referrals"}
+			},
+			want: []string{
+				`transform rule "customers" is defined more than once`,
+				`transform rule "customers": source.query references unknown query "referrals"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newValidConfig()
+			tt.change(config)
+			assert.Equal(t, tt.want, errorMessages(Validate(config)))
+		})
+	}
+}
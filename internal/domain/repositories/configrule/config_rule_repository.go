@@ -93,10 +93,67 @@ func (r *RuleRepository) LoadRulesFromConfig(filePath string) ([]*transformAgg.R
 			RelationType:  configRule.RelationType,
 			Direction:     transformVal.ParseDirection(string(configRule.Direction)),
 			Properties:    configRule.Properties,
+			MaxTextLength: configRule.MaxTextLength,
+			IncludeBinary: configRule.IncludeBinary,
+
+			LabelFromColumn:        configRule.LabelFromColumn,
+			AllowedLabels:          configRule.AllowedLabels,
+			RelationTypeFromColumn: configRule.RelationTypeFromColumn,
+			AllowedRelationTypes:   configRule.AllowedRelationTypes,
+			Labels:                 configRule.Labels,
+			WeightProperty:         configRule.WeightProperty,
+			NullKeys:               transformVal.NullKeyPolicy(configRule.NullKeys),
+			KeyColumns:             configRule.KeyColumns,
+			IDStrategy:             transformVal.IDStrategyName(configRule.IDStrategy),
+			IDNamespace:            configRule.IDNamespace,
+		}
+		if err := transformRule.ValidateLabels(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+		if err := transformRule.ValidateRelationTypes(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+		if err := transformRule.NullKeys.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+
+		if configRule.RelationshipIdentity != nil {
+			transformRule.RelationshipIdentity = &transformVal.RelationshipIdentity{
+				Discriminator: configRule.RelationshipIdentity.Discriminator,
+			}
 		}
 
-		if configRule.Source.Type == "query" {
+		if configRule.KeyMatch != nil {
+			transformRule.KeyMatch = &transformVal.KeyMatch{
+				Trim:            configRule.KeyMatch.Trim,
+				CaseInsensitive: configRule.KeyMatch.CaseInsensitive,
+				Collation:       configRule.KeyMatch.Collation,
+			}
+			if err := transformRule.KeyMatch.Validate(); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+			}
+		}
+
+		switch configRule.Source.Type {
+		case "query":
 			transformRule.SourceSQL = configRule.Source.Value
+			transformRule.SourceParams = configRule.Source.Params
+		case "table":
+			transformRule.SourceTable = configRule.Source.Value
+			if configRule.Source.SourceTable != "" {
+				transformRule.SourceTable = configRule.Source.SourceTable
+			}
+		}
+
+		for _, filter := range configRule.Filters {
+			transformRule.Filters = append(transformRule.Filters, transformVal.RuleFilter{
+				Column:   filter.Column,
+				Operator: transformVal.FilterOperator(filter.Operator),
+				Value:    filter.Value,
+			})
+		}
+		if err := transformRule.ValidateFilters(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
 		}
 
 		if configRule.RuleType == "relationship" {
@@ -105,6 +162,10 @@ func (r *RuleRepository) LoadRulesFromConfig(filePath string) ([]*transformAgg.R
 					Type:        configRule.SourceNode.Type,
 					Key:         configRule.SourceNode.Key,
 					TargetField: configRule.SourceNode.TargetField,
+					Array:       configRule.SourceNode.Array,
+					Keys:        configRule.SourceNode.Keys,
+					IDStrategy:  transformVal.IDStrategyName(configRule.SourceNode.IDStrategy),
+					IDNamespace: configRule.SourceNode.IDNamespace,
 				}
 			}
 
@@ -113,6 +174,10 @@ func (r *RuleRepository) LoadRulesFromConfig(filePath string) ([]*transformAgg.R
 					Type:        configRule.TargetNode.Type,
 					Key:         configRule.TargetNode.Key,
 					TargetField: configRule.TargetNode.TargetField,
+					Array:       configRule.TargetNode.Array,
+					Keys:        configRule.TargetNode.Keys,
+					IDStrategy:  transformVal.IDStrategyName(configRule.TargetNode.IDStrategy),
+					IDNamespace: configRule.TargetNode.IDNamespace,
 				}
 			}
 		}
@@ -120,6 +185,31 @@ func (r *RuleRepository) LoadRulesFromConfig(filePath string) ([]*transformAgg.R
 		if configRule.Properties != nil {
 			transformRule.Properties = configRule.Properties
 		}
+		if err := transformRule.ValidateKeyColumns(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+		if err := transformRule.ValidateIDStrategy(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+		for _, step := range configRule.JoinPath {
+			transformRule.JoinPath = append(transformRule.JoinPath, transformVal.JoinStep{
+				Table: step.Table,
+				From:  step.From,
+				To:    step.To,
+			})
+		}
+		if transformRule, err = transformRule.ExpandJoinPath(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
+		if len(configRule.MergeStrategies) > 0 {
+			transformRule.MergeStrategies = make(map[string]transformVal.MergeStrategy, len(configRule.MergeStrategies))
+			for property, strategy := range configRule.MergeStrategies {
+				transformRule.MergeStrategies[property] = transformVal.MergeStrategy(strategy)
+			}
+		}
+		if err := transformRule.ValidateMergeStrategies(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", configRule.Name, err)
+		}
 
 		logrus.Infof("Created rule:")
 		logrus.Infof("- Name: %s", transformRule.Name)
@@ -131,8 +221,9 @@ func (r *RuleRepository) LoadRulesFromConfig(filePath string) ([]*transformAgg.R
 		logrus.Infof("- Properties: %+v", transformRule.Properties)
 
 		rules = append(rules, &transformAgg.RuleAggregate{
-			Rule: transformRule,
-			Name: transformRule.Name,
+			Rule:   transformRule,
+			Name:   transformRule.Name,
+			Origin: configRule.Origin,
 		})
 	}
 
@@ -56,10 +56,52 @@ type DatabaseRepository interface {
 	GetConnectionString(config models.DatabaseConfig) string
 }
 
+// ColumnProfiler is implemented by repositories that can sample column values, used to
+// measure how well a candidate foreign key joins against the key it appears to reference
+type ColumnProfiler interface {
+	// SampleColumnValues returns up to limit distinct non-NULL values of a column
+	SampleColumnValues(ctx context.Context, tableName, columnName string, limit int) ([]interface{}, error)
+	// CountMatchingValues returns how many of the given values exist in a column
+	CountMatchingValues(ctx context.Context, tableName, columnName string, values []interface{}) (int, error)
+}
+
+// RowCountSampler is implemented by repositories that can count the rows of a bounded random
+// sample of a table, used to estimate row counts when catalog statistics are unreliable
+type RowCountSampler interface {
+	// SampleRowCount counts the rows of a sample of about percent of the table, stopping at
+	// limit rows. fraction is the share of the table the sample stands for.
+	SampleRowCount(ctx context.Context, tableName string, percent float64, limit int) (rows int64, fraction float64, err error)
+}
+
 // DatabaseRepositoryFactory creates database-specific repository implementations
 type DatabaseRepositoryFactory interface {
 	CreateRepository(dbType models.DatabaseType) (DatabaseRepository, error)
 	GetSupportedDatabaseTypes() []models.DatabaseType
+	GetCapabilities(dbType models.DatabaseType) DatabaseCapabilities
+}
+
+// DatabaseCapabilities describes which features are available for a database type,
+// allowing the UI and CLI to adapt (e.g. hide performance views without a stats source)
+type DatabaseCapabilities struct {
+	DatabaseType models.DatabaseType `json:"database_type"`
+	Supported    bool                `json:"supported"`
+
+	// Schema introspection
+	ForeignKeyDiscovery bool `json:"foreign_key_discovery"`
+	IndexDiscovery      bool `json:"index_discovery"`
+	SchemaNamespaces    bool `json:"schema_namespaces"`
+
+	// Data access
+	DataSampling bool `json:"data_sampling"`
+	WriteSupport bool `json:"write_support"`
+	QueryExplain bool `json:"query_explain"`
+	ArrayColumns bool `json:"array_columns"`
+	JSONColumns  bool `json:"json_columns"`
+
+	// Performance monitoring
+	PerformanceCollection bool   `json:"performance_collection"`
+	PerformanceSource     string `json:"performance_source,omitempty"`
+	Benchmarking          bool   `json:"benchmarking"`
 }
 
 // QueryBuilder defines interface for building database-specific queries
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+// Cardinality is how many nodes on each end of a relationship type take part in it
+type Cardinality string
+
+const (
+	// CardinalityOneToOne links every node to at most one node on the other end
+	CardinalityOneToOne Cardinality = "1:1"
+	// CardinalityOneToMany links the nodes on one end to many nodes on the other end, as a
+	// foreign key whose values repeat; it does not say which end is the "one"
+	CardinalityOneToMany Cardinality = "1:N"
+	// CardinalityManyToMany links nodes on both ends to many nodes, as a junction table
+	CardinalityManyToMany Cardinality = "N:M"
+)
+
+// CardinalityProperty is the relationship property holding the cardinality of its type
+const CardinalityProperty = "_cardinality"
+
+// InferCardinality returns the cardinality of a relationship from the uniqueness of its
+// ends: sourceUnique when no source node links to more than one target (the target key is
+// unique per foreign key value), targetUnique when no target node is linked from more than
+// one source (the foreign key column is unique)
+func InferCardinality(sourceUnique, targetUnique bool) Cardinality {
+	switch {
+	case sourceUnique && targetUnique:
+		return CardinalityOneToOne
+	case sourceUnique || targetUnique:
+		return CardinalityOneToMany
+	default:
+		return CardinalityManyToMany
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompositeKeySeparator joins the parts of a composite node id
+const CompositeKeySeparator = "|"
+
+// compositeKeyEscaper escapes the separator inside key values, so ("a|b", "c") and
+// ("a", "b|c") get different ids
+var compositeKeyEscaper = strings.NewReplacer(`\`, `\\`, CompositeKeySeparator, `\`+CompositeKeySeparator)
+
+// CompositeID builds the stable id of a node identified by several key values, in key
+// column order. Byte slices are read as text, like other string keys.
+func CompositeID(values []any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		parts[i] = compositeKeyEscaper.Replace(fmt.Sprintf("%v", value))
+	}
+	return strings.Join(parts, CompositeKeySeparator)
+}
+
+// ValidateKeyColumns checks that composite keys are only set where they identify nodes
+func (r TransformRule) ValidateKeyColumns() error {
+	if len(r.KeyColumns) > 0 && r.RuleType != NodeRule {
+		return fmt.Errorf("key_columns only apply to node rules")
+	}
+	for _, mapping := range []*NodeMapping{r.SourceNode, r.TargetNode} {
+		if mapping != nil && len(mapping.Keys) > 0 && mapping.Array {
+			return fmt.Errorf("node %s cannot combine keys with an array key", mapping.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// IDStrategyName selects how node ids are derived from their key values
+type IDStrategyName string
+
+const (
+	// IDStrategyComposite joins the key values into a readable id (see CompositeID)
+	IDStrategyComposite IDStrategyName = "composite"
+	// IDStrategyHash uses a hex SHA-256 prefix of the composite id, for fixed-length ids
+	IDStrategyHash IDStrategyName = "hash"
+	// IDStrategyUUID uses a name-based UUID (version 5) of the composite id in a namespace,
+	// so equal keys of different node types get different ids
+	IDStrategyUUID IDStrategyName = "uuid"
+)
+
+// IDStrategy derives a node id from the values of its key columns, in key column order.
+// Implementations are deterministic: the same key values give the same id on every run.
+type IDStrategy interface {
+	NodeID(keys []any) string
+}
+
+// CompositeIDStrategy builds ids with CompositeID
+type CompositeIDStrategy struct{}
+
+// NodeID implements IDStrategy
+func (CompositeIDStrategy) NodeID(keys []any) string {
+	return CompositeID(keys)
+}
+
+// HashIDStrategy builds ids from a SHA-256 hash of the composite id
+type HashIDStrategy struct{}
+
+// NodeID implements IDStrategy
+func (HashIDStrategy) NodeID(keys []any) string {
+	sum := sha256.Sum256([]byte(CompositeID(keys)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// UUIDIDStrategy builds version 5 UUIDs of the composite id in Namespace
+type UUIDIDStrategy struct {
+	Namespace uuid.UUID
+}
+
+// NodeID implements IDStrategy
+func (s UUIDIDStrategy) NodeID(keys []any) string {
+	return uuid.NewSHA1(s.Namespace, []byte(CompositeID(keys))).String()
+}
+
+// NewIDStrategy returns the strategy of a configured name. namespace is used by
+// IDStrategyUUID: a UUID is used as is, other text names a namespace derived from it.
+func NewIDStrategy(name IDStrategyName, namespace string) (IDStrategy, error) {
+	switch IDStrategyName(strings.ToLower(string(name))) {
+	case "", IDStrategyComposite:
+		return CompositeIDStrategy{}, nil
+	case IDStrategyHash:
+		return HashIDStrategy{}, nil
+	case IDStrategyUUID:
+		if namespace == "" {
+			return nil, fmt.Errorf("id strategy %q requires a namespace", IDStrategyUUID)
+		}
+		if parsed, err := uuid.Parse(namespace); err == nil {
+			return UUIDIDStrategy{Namespace: parsed}, nil
+		}
+		return UUIDIDStrategy{Namespace: uuid.NewSHA1(uuid.NameSpaceURL, []byte(namespace))}, nil
+	default:
+		return nil, fmt.Errorf("unknown id strategy %q (expected %q, %q or %q)", name, IDStrategyComposite, IDStrategyHash, IDStrategyUUID)
+	}
+}
+
+// NodeIDStrategy returns the strategy identifying the nodes of a node rule. The UUID
+// namespace defaults to the rule's TargetType.
+func (r TransformRule) NodeIDStrategy() (IDStrategy, error) {
+	namespace := r.IDNamespace
+	if namespace == "" {
+		namespace = r.TargetType
+	}
+	return NewIDStrategy(r.IDStrategy, namespace)
+}
+
+// NodeIDStrategy returns the strategy a relationship endpoint applies to its key values; it
+// must match the strategy of the node rule producing the node. The UUID namespace defaults
+// to the node Type.
+func (m NodeMapping) NodeIDStrategy() (IDStrategy, error) {
+	namespace := m.IDNamespace
+	if namespace == "" {
+		namespace = m.Type
+	}
+	return NewIDStrategy(m.IDStrategy, namespace)
+}
+
+// ValidateIDStrategy checks that id strategies are known and only set where they identify
+// nodes
+func (r TransformRule) ValidateIDStrategy() error {
+	if r.IDStrategy != "" && r.RuleType != NodeRule {
+		return fmt.Errorf("id_strategy only applies to node rules; set it on source_node or target_node")
+	}
+	if _, err := r.NodeIDStrategy(); err != nil {
+		return err
+	}
+	for _, mapping := range []*NodeMapping{r.SourceNode, r.TargetNode} {
+		if mapping == nil || mapping.IDStrategy == "" {
+			continue
+		}
+		if mapping.Array {
+			return fmt.Errorf("node %s cannot combine id_strategy with an array key", mapping.Type)
+		}
+		if _, err := mapping.NodeIDStrategy(); err != nil {
+			return fmt.Errorf("node %s: %w", mapping.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IdentityFallback decides how node rules without an explicit key identify rows of tables
+// that have no primary key
+type IdentityFallback string
+
+const (
+	// IdentityFallbackHash identifies a row by a hash of all its columns, so equal rows
+	// become one node and re-runs keep their ids
+	IdentityFallbackHash IdentityFallback = "hash"
+	// IdentityFallbackGenerate gives every row a new generated id, as before detection
+	IdentityFallbackGenerate IdentityFallback = "generate"
+)
+
+// ParseIdentityFallback reads a configured fallback; empty selects IdentityFallbackHash
+func ParseIdentityFallback(value string) (IdentityFallback, error) {
+	switch fallback := IdentityFallback(strings.ToLower(value)); fallback {
+	case "":
+		return IdentityFallbackHash, nil
+	case IdentityFallbackHash, IdentityFallbackGenerate:
+		return fallback, nil
+	default:
+		return "", fmt.Errorf("unknown identity fallback %q (expected %q or %q)", value, IdentityFallbackHash, IdentityFallbackGenerate)
+	}
+}
+
+// HasExplicitKey reports whether a node rule identifies its nodes itself, through
+// KeyColumns, HashIdentity or a column mapped to id
+func (r TransformRule) HasExplicitKey() bool {
+	if len(r.KeyColumns) > 0 || r.HashIdentity {
+		return true
+	}
+	for _, target := range r.FieldMappings {
+		if target == "id" {
+			return true
+		}
+	}
+	return false
+}
+
+// HashID builds the id of a row identified by all of its columns. Columns are hashed in name
+// order; metadata columns starting with "_" (such as _table) are left out.
+func HashID(row map[string]any) string {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		if !strings.HasPrefix(column, "_") {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	values := make([]any, 0, 2*len(columns))
+	for _, column := range columns {
+		values = append(values, column, row[column])
+	}
+	sum := sha256.Sum256([]byte(CompositeID(values)))
+	return hex.EncodeToString(sum[:16])
+}
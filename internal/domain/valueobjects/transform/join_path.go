@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JoinStep joins one more table onto a relationship rule's join path: the From column of
+// the previous table matches the To column of Table
+type JoinStep struct {
+	Table string `yaml:"table"`
+	From  string `yaml:"from"`
+	To    string `yaml:"to"`
+}
+
+// Column aliases of the query built for a join path
+const (
+	JoinSourceKey      = "source_key"
+	JoinTargetKey      = "target_key"
+	joinPropertyPrefix = "property_"
+)
+
+// joinTablePattern and joinColumnPattern match the names spliced into a join path query
+var (
+	joinTablePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+	joinColumnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+)
+
+// ExpandJoinPath turns a relationship rule with a JoinPath into one reading a source query.
+// The query starts at SourceTable and joins every step in order; the source node key is
+// read from SourceTable and the target node key from the last table, so the relationship
+// links the two endpoints directly. Properties may name a column of any table on the path
+// as table.column; unqualified columns belong to SourceTable. Rules without a JoinPath are
+// returned unchanged.
+func (r TransformRule) ExpandJoinPath() (TransformRule, error) {
+	if len(r.JoinPath) == 0 {
+		return r, nil
+	}
+	if err := r.validateJoinPath(); err != nil {
+		return r, err
+	}
+
+	aliases := map[string]string{r.SourceTable: "j0"}
+	from := []string{fmt.Sprintf("%s j0", r.SourceTable)}
+	for i, step := range r.JoinPath {
+		alias := fmt.Sprintf("j%d", i+1)
+		from = append(from, fmt.Sprintf("JOIN %s %s ON j%d.%s = %s.%s", step.Table, alias, i, step.From, alias, step.To))
+		if _, ok := aliases[step.Table]; !ok {
+			aliases[step.Table] = alias
+		}
+	}
+	last := fmt.Sprintf("j%d", len(r.JoinPath))
+
+	columns := []string{
+		fmt.Sprintf("j0.%s AS %s", r.SourceNode.Key, JoinSourceKey),
+		fmt.Sprintf("%s.%s AS %s", last, r.TargetNode.Key, JoinTargetKey),
+	}
+	sourceColumns := make([]string, 0, len(r.Properties))
+	for column := range r.Properties {
+		sourceColumns = append(sourceColumns, column)
+	}
+	sort.Strings(sourceColumns)
+
+	properties := make(map[string]string, len(r.Properties))
+	for i, column := range sourceColumns {
+		table, name := r.SourceTable, column
+		if dot := strings.LastIndex(column, "."); dot >= 0 {
+			table, name = column[:dot], column[dot+1:]
+		}
+		alias, ok := aliases[table]
+		if !ok {
+			return r, fmt.Errorf("property column %q is not on the join path", column)
+		}
+		if !joinColumnPattern.MatchString(name) {
+			return r, fmt.Errorf("invalid property column %q", column)
+		}
+		aliased := fmt.Sprintf("%s%d", joinPropertyPrefix, i)
+		columns = append(columns, fmt.Sprintf("%s.%s AS %s", alias, name, aliased))
+		properties[aliased] = r.Properties[column]
+	}
+
+	expanded := r
+	expanded.SourceSQL = fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), strings.Join(from, " "))
+	expanded.Properties = properties
+	source, target := *r.SourceNode, *r.TargetNode
+	source.Key, target.Key = JoinSourceKey, JoinTargetKey
+	expanded.SourceNode, expanded.TargetNode = &source, &target
+	return expanded, nil
+}
+
+// validateJoinPath checks that a join path can be turned into a query
+func (r TransformRule) validateJoinPath() error {
+	if r.RuleType != RelationshipRule {
+		return fmt.Errorf("join_path is only supported on relationship rules")
+	}
+	if r.SourceSQL != "" {
+		return fmt.Errorf("join_path cannot be combined with a source query")
+	}
+	if !joinTablePattern.MatchString(r.SourceTable) {
+		return fmt.Errorf("join_path requires a valid source table, got %q", r.SourceTable)
+	}
+	for _, mapping := range []*NodeMapping{r.SourceNode, r.TargetNode} {
+		if mapping == nil {
+			return fmt.Errorf("join_path requires both source_node and target_node")
+		}
+		if len(mapping.Keys) > 0 || mapping.Array {
+			return fmt.Errorf("join_path requires a single key column for node %s", mapping.Type)
+		}
+		if !joinColumnPattern.MatchString(mapping.Key) {
+			return fmt.Errorf("invalid key column %q for node %s", mapping.Key, mapping.Type)
+		}
+	}
+	for i, step := range r.JoinPath {
+		if !joinTablePattern.MatchString(step.Table) {
+			return fmt.Errorf("join_path step %d: invalid table %q", i+1, step.Table)
+		}
+		if !joinColumnPattern.MatchString(step.From) || !joinColumnPattern.MatchString(step.To) {
+			return fmt.Errorf("join_path step %d: invalid join columns %q and %q", i+1, step.From, step.To)
+		}
+	}
+	return nil
+}
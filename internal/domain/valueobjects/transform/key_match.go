@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyMatch controls how relationship keys are compared with node keys. Both sides are
+// normalized the same way, so "ABC " matches "abc" with Trim and CaseInsensitive set.
+type KeyMatch struct {
+	// Trim removes leading and trailing whitespace
+	Trim bool `yaml:"trim,omitempty"`
+	// CaseInsensitive compares keys ignoring case
+	CaseInsensitive bool `yaml:"case_insensitive,omitempty"`
+	// Collation mirrors a MySQL collation of the key columns: "_ci" collations ignore case,
+	// and PAD SPACE collations (all but the utf8mb4_0900 family) ignore trailing spaces
+	Collation string `yaml:"collation,omitempty"`
+}
+
+// Validate reports collations whose comparison rules are not known
+func (k *KeyMatch) Validate() error {
+	if k == nil || k.Collation == "" {
+		return nil
+	}
+	collation := strings.ToLower(k.Collation)
+	for _, suffix := range []string{"_ci", "_cs", "_bin"} {
+		if strings.HasSuffix(collation, suffix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported key collation %q: expected a _ci, _cs or _bin collation", k.Collation)
+}
+
+// Normalize returns the comparable form of a key. A nil KeyMatch compares keys exactly.
+func (k *KeyMatch) Normalize(key any) string {
+	var value string
+	switch v := key.(type) {
+	case []uint8:
+		value = string(v)
+	default:
+		value = fmt.Sprintf("%v", key)
+	}
+	if k == nil {
+		return value
+	}
+
+	collation := strings.ToLower(k.Collation)
+	if k.Trim {
+		value = strings.TrimSpace(value)
+	} else if collation != "" && !strings.Contains(collation, "_0900_") {
+		value = strings.TrimRight(value, " ")
+	}
+	if k.CaseInsensitive || strings.HasSuffix(collation, "_ci") {
+		value = strings.ToLower(value)
+	}
+	return value
+}
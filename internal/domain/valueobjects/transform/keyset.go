@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var keysetColumnPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// StreamKey returns the column a node rule's query can be paged by: its only key column, or
+// the column mapped to id. ok is false when the rule has no single usable key.
+func (r TransformRule) StreamKey() (column string, ok bool) {
+	switch {
+	case len(r.KeyColumns) == 1:
+		column = r.KeyColumns[0]
+	case len(r.KeyColumns) == 0 && r.RuleType == NodeRule:
+		for source, target := range r.FieldMappings {
+			if target == "id" {
+				column = source
+				break
+			}
+		}
+	}
+	return column, keysetColumnPattern.MatchString(column)
+}
+
+// KeysetPageQuery wraps query to read its rows in key order, limit rows at a time, starting
+// after the key value after (nil for the first page)
+func KeysetPageQuery(query, key string, after any, limit int) (string, error) {
+	condition := ""
+	if after != nil {
+		condition = sqlLiteral(fmt.Sprint(after))
+	}
+	return keysetPage(query, key, condition, limit)
+}
+
+// KeysetPageQueryWithArgs is KeysetPageQuery for a query with arguments: the key value
+// after is passed as one more argument, with the placeholder of its position
+func KeysetPageQueryWithArgs(query string, args []any, key string, after any, limit int, placeholder QueryPlaceholder) (string, []any, error) {
+	args = append([]any(nil), args...)
+	condition := ""
+	if after != nil {
+		args = append(args, after)
+		condition = placeholder(len(args))
+	}
+	page, err := keysetPage(query, key, condition, limit)
+	if err != nil {
+		return "", nil, err
+	}
+	return page, args, nil
+}
+
+// keysetPage builds a page query of rows whose key is greater than the SQL expression after,
+// or of the first rows when after is empty
+func keysetPage(query, key, after string, limit int) (string, error) {
+	if !keysetColumnPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid keyset column %q", key)
+	}
+	if limit <= 0 {
+		return "", fmt.Errorf("keyset page size must be positive")
+	}
+
+	inner := strings.TrimRight(strings.TrimSpace(query), ";")
+	where := ""
+	if after != "" {
+		where = fmt.Sprintf(" WHERE %s > %s", key, after)
+	}
+	return fmt.Sprintf("SELECT * FROM (%s) AS keyset_page%s ORDER BY %s LIMIT %d", inner, where, key, limit), nil
+}
+
+func sqlLiteral(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MergeStrategy decides the value of a property when several rows or rules produce the
+// same node
+type MergeStrategy string
+
+const (
+	// MergeLastWins keeps the value of the row merged last
+	MergeLastWins MergeStrategy = "last_wins"
+	// MergeFirstWins keeps the value of the first row that set the property
+	MergeFirstWins MergeStrategy = "first_wins"
+	// MergeMax and MergeMin keep the largest or smallest value; values compare as numbers
+	// when both are numeric and as text otherwise, so ISO dates order correctly
+	MergeMax MergeStrategy = "max"
+	MergeMin MergeStrategy = "min"
+	// MergeConcat joins the values as text with MergeConcatSeparator
+	MergeConcat MergeStrategy = "concat"
+	// MergeArrayAppend collects the values of all rows in a list, including the first
+	MergeArrayAppend MergeStrategy = "array_append"
+)
+
+// MergeConcatSeparator separates the values joined by MergeConcat
+const MergeConcatSeparator = ", "
+
+// Validate accepts the known strategies
+func (m MergeStrategy) Validate() error {
+	switch m {
+	case MergeLastWins, MergeFirstWins, MergeMax, MergeMin, MergeConcat, MergeArrayAppend:
+		return nil
+	}
+	return fmt.Errorf("unknown merge strategy %q (use last_wins, first_wins, max, min, concat or array_append)", m)
+}
+
+// Initial returns the value a property takes on the node's first row
+func (m MergeStrategy) Initial(value any) any {
+	if m == MergeArrayAppend && value != nil {
+		return appendValue(nil, value)
+	}
+	return value
+}
+
+// Merge combines the value already on a node with the value of a row merged into it. A
+// missing (nil) value on either side leaves the other one.
+func (m MergeStrategy) Merge(existing, incoming any) any {
+	if existing == nil {
+		return m.Initial(incoming)
+	}
+	if incoming == nil {
+		return existing
+	}
+
+	switch m {
+	case MergeFirstWins:
+		return existing
+	case MergeMax:
+		if compareValues(incoming, existing) > 0 {
+			return incoming
+		}
+		return existing
+	case MergeMin:
+		if compareValues(incoming, existing) < 0 {
+			return incoming
+		}
+		return existing
+	case MergeConcat:
+		return fmt.Sprintf("%v%s%v", existing, MergeConcatSeparator, incoming)
+	case MergeArrayAppend:
+		return appendValue(existing, incoming)
+	default:
+		return incoming
+	}
+}
+
+// ValidateMergeStrategies checks that merge strategies are only set on node rules and name
+// known strategies
+func (r TransformRule) ValidateMergeStrategies() error {
+	if len(r.MergeStrategies) > 0 && r.RuleType != NodeRule {
+		return fmt.Errorf("merge_strategies only apply to node rules")
+	}
+	for property, strategy := range r.MergeStrategies {
+		if err := strategy.Validate(); err != nil {
+			return fmt.Errorf("property %s: %w", property, err)
+		}
+	}
+	return nil
+}
+
+// appendValue adds value to a list of values stored as text, since graph stores need
+// homogeneous lists
+func appendValue(list any, value any) []string {
+	var values []string
+	switch existing := list.(type) {
+	case nil:
+	case []string:
+		values = append(values, existing...)
+	default:
+		values = append(values, fmt.Sprintf("%v", existing))
+	}
+	return append(values, fmt.Sprintf("%v", value))
+}
+
+// compareValues orders two property values numerically when both are numbers (or numeric
+// text, as integer keys are stored as text) and as text otherwise
+func compareValues(a, b any) int {
+	textA, textB := fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)
+	numberA, errA := strconv.ParseFloat(textA, 64)
+	numberB, errB := strconv.ParseFloat(textB, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case numberA < numberB:
+			return -1
+		case numberA > numberB:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(textA, textB)
+}
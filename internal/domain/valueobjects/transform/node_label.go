@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// labelPattern matches labels that are safe to splice into Cypher unquoted
+var labelPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateLabels checks that a rule taking labels from a column lists the labels it may
+// produce, and that only node rules add labels. Labels are written into Cypher as-is, so
+// each must be a plain identifier.
+func (r TransformRule) ValidateLabels() error {
+	if len(r.Labels) > 0 && r.RuleType != NodeRule {
+		return fmt.Errorf("labels are only supported on node rules")
+	}
+	for _, label := range r.Labels {
+		if !labelPattern.MatchString(label) {
+			return fmt.Errorf("label %q is not a valid node label", label)
+		}
+	}
+
+	if r.LabelFromColumn == "" {
+		return nil
+	}
+	if r.RuleType != NodeRule {
+		return fmt.Errorf("label_from_column is only supported on node rules")
+	}
+	if len(r.AllowedLabels) == 0 {
+		return fmt.Errorf("label_from_column %q requires allowed_labels", r.LabelFromColumn)
+	}
+	for _, label := range r.AllowedLabels {
+		if !labelPattern.MatchString(label) {
+			return fmt.Errorf("allowed label %q is not a valid node label", label)
+		}
+	}
+	if r.TargetType != "" && !labelPattern.MatchString(r.TargetType) {
+		return fmt.Errorf("fallback label %q is not a valid node label", r.TargetType)
+	}
+	return nil
+}
+
+// ResolveLabel returns the label for a row whose LabelFromColumn holds value. Values match
+// AllowedLabels ignoring case and surrounding whitespace and take the listed spelling; other
+// values fall back to TargetType, and are rejected when no TargetType is set.
+func (r TransformRule) ResolveLabel(value any) (string, bool) {
+	var label string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		label = string(v)
+	default:
+		label = fmt.Sprintf("%v", v)
+	}
+	label = strings.TrimSpace(label)
+
+	for _, allowed := range r.AllowedLabels {
+		if strings.EqualFold(label, allowed) {
+			return allowed, true
+		}
+	}
+	return r.TargetType, r.TargetType != ""
+}
+
+// AdditionalLabels returns the rule's Labels other than primary, the label a node already has
+func (r TransformRule) AdditionalLabels(primary string) []string {
+	var labels []string
+	for _, label := range r.Labels {
+		if label != primary && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// NodeLabels returns every label a node rule can produce
+func (r TransformRule) NodeLabels() []string {
+	var labels []string
+	seen := make(map[string]bool)
+	candidates := append([]string{r.TargetType}, r.AllowedLabels...)
+	for _, label := range append(candidates, r.Labels...) {
+		if label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import "fmt"
+
+// NullKeyPolicy decides what a relationship rule does with a row whose source or target
+// key is NULL
+type NullKeyPolicy string
+
+const (
+	// NullKeySkip creates no relationship for the row; it is the default
+	NullKeySkip NullKeyPolicy = "skip"
+	// NullKeyBucket links the row's other endpoint to an "unknown" node of the missing type
+	NullKeyBucket NullKeyPolicy = "bucket"
+)
+
+// UnknownNodeID is the id of the per-type node that NULL keys are linked to in bucket mode
+const UnknownNodeID = "__unknown__"
+
+// Validate accepts the known policies and the empty default
+func (p NullKeyPolicy) Validate() error {
+	switch p {
+	case "", NullKeySkip, NullKeyBucket:
+		return nil
+	}
+	return fmt.Errorf("unknown null_keys policy %q (use skip or bucket)", p)
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NamingCase is the letter case property names are converted to
+type NamingCase string
+
+const (
+	// NamingCaseCamel turns snake_case names into camelCase (created_at becomes createdAt)
+	NamingCaseCamel NamingCase = "camel"
+	// NamingCaseSnake turns camelCase names into snake_case (createdAt becomes created_at)
+	NamingCaseSnake NamingCase = "snake"
+)
+
+// PropertyNaming renames the properties of imported nodes and relationships. A name listed
+// in Rename takes the given name; other names lose the first matching StripPrefixes entry
+// and are then converted to Case. The id property and internal properties (starting with
+// "_") keep their names.
+type PropertyNaming struct {
+	Case          NamingCase
+	StripPrefixes []string
+	Rename        map[string]string
+}
+
+// IsZero reports whether the naming leaves every name unchanged
+func (n PropertyNaming) IsZero() bool {
+	return n.Case == "" && len(n.StripPrefixes) == 0 && len(n.Rename) == 0
+}
+
+// Validate checks the case and that the id property is not renamed
+func (n PropertyNaming) Validate() error {
+	switch n.Case {
+	case "", NamingCaseCamel, NamingCaseSnake:
+	default:
+		return fmt.Errorf("unknown property naming case %q (expected %q or %q)", n.Case, NamingCaseCamel, NamingCaseSnake)
+	}
+	for from, to := range n.Rename {
+		if from == "" || to == "" {
+			return fmt.Errorf("property rename %q: %q must name both properties", from, to)
+		}
+		if from == "id" || to == "id" {
+			return fmt.Errorf("property rename %q: %q cannot change the id property", from, to)
+		}
+	}
+	return nil
+}
+
+// Apply returns the property name for a source name
+func (n PropertyNaming) Apply(name string) string {
+	if name == "id" || strings.HasPrefix(name, "_") {
+		return name
+	}
+	if renamed, ok := n.Rename[name]; ok {
+		return renamed
+	}
+	for _, prefix := range n.StripPrefixes {
+		if stripped := strings.TrimPrefix(name, prefix); stripped != name && stripped != "" {
+			name = stripped
+			break
+		}
+	}
+	switch n.Case {
+	case NamingCaseCamel:
+		return camelCase(name)
+	case NamingCaseSnake:
+		return snakeCase(name)
+	}
+	return name
+}
+
+// RenameProperties renames the keys of properties in place. A property whose new name is
+// already taken, by a property keeping its name or renamed before it, keeps its own name;
+// the names kept that way are returned.
+func (n PropertyNaming) RenameProperties(properties map[string]any) []string {
+	names := make([]string, 0, len(properties))
+	taken := make(map[string]bool, len(properties))
+	for name := range properties {
+		names = append(names, name)
+		if n.Apply(name) == name {
+			taken[name] = true
+		}
+	}
+	// Sorted so the same property wins a conflict on every run
+	sort.Strings(names)
+
+	var conflicts []string
+	targets := make(map[string]string)
+	for _, name := range names {
+		target := n.Apply(name)
+		if target == name {
+			continue
+		}
+		if taken[target] {
+			conflicts = append(conflicts, name)
+			continue
+		}
+		taken[target] = true
+		targets[name] = target
+	}
+	// A property kept by a conflict may be the target of another rename, which then keeps
+	// its name too
+	for reverted := true; reverted; {
+		reverted = false
+		for _, name := range names {
+			if target, ok := targets[name]; ok && hasKey(properties, target) {
+				if _, renamedAway := targets[target]; !renamedAway {
+					delete(targets, name)
+					conflicts = append(conflicts, name)
+					reverted = true
+				}
+			}
+		}
+	}
+
+	values := make(map[string]any, len(targets))
+	for name := range targets {
+		values[name] = properties[name]
+		delete(properties, name)
+	}
+	for name, target := range targets {
+		properties[target] = values[name]
+	}
+	return conflicts
+}
+
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	var out strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		if out.Len() == 0 {
+			out.WriteString(part)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		out.WriteString(string(runes))
+	}
+	if out.Len() == 0 {
+		return name
+	}
+	return out.String()
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var out strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previous != '_' && (unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower)) {
+				out.WriteRune('_')
+			}
+		}
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return out.String()
+}
+
+func hasKey(properties map[string]any, key string) bool {
+	_, ok := properties[key]
+	return ok
+}
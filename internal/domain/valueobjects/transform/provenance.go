@@ -0,0 +1,22 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+// Provenance properties set on imported nodes and relationships when provenance is enabled.
+// ProvenanceImportedAt holds the start of the importing run as an RFC3339 UTC time, so
+// import times compare as strings.
+const (
+	ProvenanceDatabase   = "_source_database"
+	ProvenanceTable      = "_source_table"
+	ProvenanceRunID      = "_import_run_id"
+	ProvenanceImportedAt = "_imported_at"
+)
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Runtime parameter values, resolved when the query is executed rather than when the
+// config is loaded. A parameter value of "@last_run" makes a query incremental.
+const (
+	RuntimeParamNow     = "@now"
+	RuntimeParamLastRun = "@last_run"
+)
+
+// QueryParamNames returns the :name placeholders used in a source query, in order of first
+// use. Placeholders inside quoted strings and identifiers, casts (::) and assignments (:=)
+// are ignored.
+func QueryParamNames(query string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	scanQueryParams(query, func(name string) string {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return ":" + name
+	})
+	return names
+}
+
+// ValidateQueryParams checks that every placeholder in query has a value in params, every
+// param is used, and runtime values are known
+func ValidateQueryParams(query string, params map[string]string) error {
+	used := make(map[string]bool)
+	for _, name := range QueryParamNames(query) {
+		if _, ok := params[name]; !ok {
+			return fmt.Errorf("query references undefined parameter :%s", name)
+		}
+		used[name] = true
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !used[name] {
+			return fmt.Errorf("parameter %q is not used by the query", name)
+		}
+		if value := params[name]; strings.HasPrefix(value, "@") && value != RuntimeParamNow && value != RuntimeParamLastRun {
+			return fmt.Errorf("parameter %q has unknown runtime value %s", name, value)
+		}
+	}
+	return nil
+}
+
+// BindQueryParams replaces placeholders with query arguments: each placeholder becomes
+// placeholder(n) and its value the n-th argument, so values are never read as SQL. Values
+// named in runtime (such as RuntimeParamLastRun) are substituted first.
+func BindQueryParams(query string, params map[string]string, runtime map[string]string, placeholder QueryPlaceholder) (string, []any, error) {
+	var missing string
+	var args []any
+	bound := scanQueryParams(query, func(name string) string {
+		value, ok := params[name]
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return ":" + name
+		}
+		if resolved, ok := runtime[value]; ok {
+			value = resolved
+		}
+		args = append(args, value)
+		return placeholder(len(args))
+	})
+	if missing != "" {
+		return "", nil, fmt.Errorf("query references undefined parameter :%s", missing)
+	}
+	return bound, args, nil
+}
+
+// scanQueryParams rewrites each placeholder with replace(name) and returns the new query
+func scanQueryParams(query string, replace func(name string) string) string {
+	var out strings.Builder
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if query[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(query))
+			out.WriteString(query[i:end])
+			i = end
+		case c == ':' && i+1 < len(query) && (query[i+1] == ':' || query[i+1] == '='):
+			out.WriteString(query[i : i+2])
+			i += 2
+		case c == ':' && i+1 < len(query) && isParamStart(query[i+1]) && (i == 0 || !isParamChar(query[i-1])):
+			end := i + 1
+			for end < len(query) && isParamChar(query[end]) {
+				end++
+			}
+			out.WriteString(replace(query[i+1 : end]))
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+func isParamStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isParamChar(c byte) bool {
+	return isParamStart(c) || (c >= '0' && c <= '9')
+}
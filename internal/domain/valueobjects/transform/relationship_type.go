@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateRelationTypes checks that a rule taking its relationship type from a column lists
+// the types it may produce. Types are written into Cypher as-is, so each must be a plain
+// identifier.
+func (r TransformRule) ValidateRelationTypes() error {
+	if r.RelationTypeFromColumn == "" {
+		return nil
+	}
+	if r.RuleType != RelationshipRule {
+		return fmt.Errorf("relationship_type_from_column is only supported on relationship rules")
+	}
+	if len(r.AllowedRelationTypes) == 0 {
+		return fmt.Errorf("relationship_type_from_column %q requires allowed_relationship_types", r.RelationTypeFromColumn)
+	}
+	for _, relType := range r.AllowedRelationTypes {
+		if !labelPattern.MatchString(relType) {
+			return fmt.Errorf("allowed relationship type %q is not a valid relationship type", relType)
+		}
+	}
+	if r.RelationType != "" && !labelPattern.MatchString(r.RelationType) {
+		return fmt.Errorf("fallback relationship type %q is not a valid relationship type", r.RelationType)
+	}
+	return nil
+}
+
+// ResolveRelationType returns the relationship type for a row whose RelationTypeFromColumn
+// holds value. Values match AllowedRelationTypes ignoring case, surrounding whitespace and
+// the difference between spaces, hyphens and underscores ("reports to" matches REPORTS_TO),
+// and take the listed spelling; other values fall back to RelationType, and are rejected
+// when no RelationType is set.
+func (r TransformRule) ResolveRelationType(value any) (string, bool) {
+	var relType string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		relType = string(v)
+	default:
+		relType = fmt.Sprintf("%v", v)
+	}
+	relType = relationTypeSeparators.Replace(strings.TrimSpace(relType))
+
+	for _, allowed := range r.AllowedRelationTypes {
+		if strings.EqualFold(relType, allowed) {
+			return allowed, true
+		}
+	}
+	return r.RelationType, r.RelationType != ""
+}
+
+// RelationTypes returns every relationship type a relationship rule can produce
+func (r TransformRule) RelationTypes() []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, relType := range append([]string{r.RelationType}, r.AllowedRelationTypes...) {
+		if relType != "" && !seen[relType] {
+			seen[relType] = true
+			types = append(types, relType)
+		}
+	}
+	return types
+}
+
+var relationTypeSeparators = strings.NewReplacer(" ", "_", "-", "_")
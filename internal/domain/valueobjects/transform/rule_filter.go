@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterOperator compares a filtered column with the filter value
+type FilterOperator string
+
+const (
+	FilterEqual          FilterOperator = "="
+	FilterNotEqual       FilterOperator = "!="
+	FilterLess           FilterOperator = "<"
+	FilterLessOrEqual    FilterOperator = "<="
+	FilterGreater        FilterOperator = ">"
+	FilterGreaterOrEqual FilterOperator = ">="
+	FilterLike           FilterOperator = "like"
+	FilterIn             FilterOperator = "in"
+	FilterNotIn          FilterOperator = "not in"
+	FilterIsNull         FilterOperator = "is null"
+	FilterIsNotNull      FilterOperator = "is not null"
+)
+
+// RuleFilter keeps the source rows of a rule whose Column compares to Value with Operator.
+// Values are passed to the database as query arguments, never spliced into the query.
+type RuleFilter struct {
+	Column   string         `yaml:"column"`
+	Operator FilterOperator `yaml:"operator"`
+	Value    any            `yaml:"value,omitempty"`
+}
+
+// QueryPlaceholder returns the placeholder of the n-th (1-based) argument of a query, such
+// as "?" for MySQL or "$1" for PostgreSQL
+type QueryPlaceholder func(n int) string
+
+// normalized returns the operator in lower case with single spaces; "==" and "<>" are
+// accepted for "=" and "!="
+func (o FilterOperator) normalized() FilterOperator {
+	operator := FilterOperator(strings.ToLower(strings.Join(strings.Fields(string(o)), " ")))
+	switch operator {
+	case "", "==":
+		return FilterEqual
+	case "<>":
+		return FilterNotEqual
+	}
+	return operator
+}
+
+// ValidateFilters checks that filters name plain columns, known operators and values that
+// fit them, and that the rule reads a source they can be applied to
+func (r TransformRule) ValidateFilters() error {
+	if len(r.Filters) == 0 {
+		return nil
+	}
+	if r.SourceSQL == "" && r.SourceTable == "" {
+		return fmt.Errorf("filters need a source table or query")
+	}
+	if r.SourceSQL == "" && !joinTablePattern.MatchString(r.SourceTable) {
+		return fmt.Errorf("filters cannot be applied to source table %q", r.SourceTable)
+	}
+	for _, filter := range r.Filters {
+		if !joinColumnPattern.MatchString(filter.Column) {
+			return fmt.Errorf("filter column %q must be a plain column name", filter.Column)
+		}
+		switch operator := filter.Operator.normalized(); operator {
+		case FilterEqual, FilterNotEqual, FilterLess, FilterLessOrEqual, FilterGreater, FilterGreaterOrEqual, FilterLike:
+			if filter.Value == nil {
+				return fmt.Errorf("filter on %s: operator %q needs a value (use %q to match NULL)", filter.Column, operator, FilterIsNull)
+			}
+			if _, isList := filter.Value.([]any); isList {
+				return fmt.Errorf("filter on %s: operator %q needs a single value", filter.Column, operator)
+			}
+		case FilterIn, FilterNotIn:
+			values, isList := filter.Value.([]any)
+			if !isList || len(values) == 0 {
+				return fmt.Errorf("filter on %s: operator %q needs a non-empty list of values", filter.Column, operator)
+			}
+		case FilterIsNull, FilterIsNotNull:
+			if filter.Value != nil {
+				return fmt.Errorf("filter on %s: operator %q takes no value", filter.Column, operator)
+			}
+		default:
+			return fmt.Errorf("filter on %s: unknown operator %q", filter.Column, filter.Operator)
+		}
+	}
+	return nil
+}
+
+// FilteredQuery applies the rule's filters to query, the rule's bound source query, or to
+// SourceTable when query is empty. args are the arguments query already takes; the filter
+// values are placed after them. It returns the filtered query with a placeholder for every
+// filter value, and all arguments in placeholder order.
+func (r TransformRule) FilteredQuery(query string, args []any, placeholder QueryPlaceholder) (string, []any, error) {
+	if err := r.ValidateFilters(); err != nil {
+		return "", nil, err
+	}
+
+	source := r.SourceTable
+	if query != "" {
+		source = fmt.Sprintf("(%s) AS filtered_source", strings.TrimRight(strings.TrimSpace(query), ";"))
+	}
+
+	var conditions []string
+	args = append([]any(nil), args...)
+	bind := func(value any) string {
+		args = append(args, value)
+		return placeholder(len(args))
+	}
+	for _, filter := range r.Filters {
+		switch operator := filter.Operator.normalized(); operator {
+		case FilterIsNull:
+			conditions = append(conditions, fmt.Sprintf("%s IS NULL", filter.Column))
+		case FilterIsNotNull:
+			conditions = append(conditions, fmt.Sprintf("%s IS NOT NULL", filter.Column))
+		case FilterIn, FilterNotIn:
+			values := filter.Value.([]any)
+			placeholders := make([]string, len(values))
+			for i, value := range values {
+				placeholders[i] = bind(value)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s (%s)", filter.Column, strings.ToUpper(string(operator)), strings.Join(placeholders, ", ")))
+		case FilterNotEqual:
+			conditions = append(conditions, fmt.Sprintf("%s <> %s", filter.Column, bind(filter.Value)))
+		default:
+			conditions = append(conditions, fmt.Sprintf("%s %s %s", filter.Column, strings.ToUpper(string(operator)), bind(filter.Value)))
+		}
+	}
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s", source, strings.Join(conditions, " AND ")), args, nil
+}
@@ -22,12 +22,23 @@ type NodeMapping struct {
 	Type        string `yaml:"type"`
 	Key         string `yaml:"key"`
 	TargetField string `yaml:"target_field"`
+	// Array marks Key as an array or set column (e.g. Postgres int[] or MySQL SET); the
+	// relationship is created once per element
+	Array bool `yaml:"array,omitempty"`
+	// Keys identifies a node with a composite key; the relationship row's values of these
+	// columns are combined into the node id instead of reading Key
+	Keys []string `yaml:"keys,omitempty"`
+	// IDStrategy and IDNamespace derive the node id from Keys (or Key) like the node rule
+	// producing the node; the endpoint is then matched against node ids
+	IDStrategy  IDStrategyName `yaml:"id_strategy,omitempty"`
+	IDNamespace string         `yaml:"id_namespace,omitempty"`
 }
 
 type TransformRule struct {
 	Name          string            `yaml:"name"`
 	SourceTable   string            `yaml:"source_table"`
 	SourceSQL     string            `yaml:"source_sql,omitempty"`
+	SourceParams  map[string]string `yaml:"source_params,omitempty"`
 	RuleType      RuleType          `yaml:"rule_type"`
 	TargetType    string            `yaml:"target_type"`
 	Direction     Direction         `yaml:"direction,omitempty"`
@@ -37,6 +48,78 @@ type TransformRule struct {
 	TargetNode    *NodeMapping      `yaml:"target_node,omitempty"`
 	Properties    map[string]string `yaml:"properties,omitempty"`
 	Priority      int               `yaml:"priority"`
+
+	// MaxTextLength caps string values; 0 uses DefaultMaxTextLength, negative disables the cap
+	MaxTextLength int `yaml:"max_text_length,omitempty"`
+	// IncludeBinary keeps binary (BLOB) values as base64 text instead of omitting them
+	IncludeBinary bool `yaml:"include_binary,omitempty"`
+	// KeyMatch normalizes source and target keys before relationship endpoints are matched
+	KeyMatch *KeyMatch `yaml:"key_match,omitempty"`
+	// LabelFromColumn takes each node's label from a row value (e.g. an entity_type
+	// discriminator) instead of TargetType, which becomes the fallback label
+	LabelFromColumn string `yaml:"label_from_column,omitempty"`
+	// AllowedLabels lists the labels LabelFromColumn may produce
+	AllowedLabels []string `yaml:"allowed_labels,omitempty"`
+	// RelationTypeFromColumn takes each relationship's type from a row value (e.g. the
+	// relation_type discriminator next to a polymorphic related_id) instead of RelationType,
+	// which becomes the fallback type
+	RelationTypeFromColumn string `yaml:"relationship_type_from_column,omitempty"`
+	// AllowedRelationTypes lists the types RelationTypeFromColumn may produce
+	AllowedRelationTypes []string `yaml:"allowed_relationship_types,omitempty"`
+	// Labels are added to every node of the rule next to its TargetType or column label
+	Labels []string `yaml:"labels,omitempty"`
+	// WeightProperty, when set on a relationship rule, merges the relationships produced by
+	// repeated source rows into one whose property of this name counts the rows
+	WeightProperty string `yaml:"weight_property,omitempty"`
+	// RelationshipIdentity, when set on a relationship rule, merges relationships with the
+	// same identity, so re-runs and rules producing the same edge keep a single relationship
+	RelationshipIdentity *RelationshipIdentity `yaml:"relationship_identity,omitempty"`
+	// NullKeys decides what a relationship rule does with rows whose key is NULL
+	NullKeys NullKeyPolicy `yaml:"null_keys,omitempty"`
+	// KeyColumns identifies the nodes of a node rule by several columns (e.g. order_id and
+	// line_no); rows with equal values become one node with a CompositeID id
+	KeyColumns []string `yaml:"key_columns,omitempty"`
+	// HashIdentity identifies the nodes of a node rule by HashID of all row columns, for
+	// tables without a key
+	HashIdentity bool `yaml:"hash_identity,omitempty"`
+	// IDStrategy derives node ids from KeyColumns, or from the value mapped to id, instead of
+	// using them as they are; see IDStrategy. IDNamespace is the UUID namespace, by default
+	// TargetType.
+	IDStrategy  IDStrategyName `yaml:"id_strategy,omitempty"`
+	IDNamespace string         `yaml:"id_namespace,omitempty"`
+	// JoinPath links the source node of a relationship rule to a target node several tables
+	// away, through bridge tables; see ExpandJoinPath
+	JoinPath []JoinStep `yaml:"join_path,omitempty"`
+	// MergeStrategies decides, per property, how a node rule combines the values of rows
+	// that produce the same node; other properties take the last row's value
+	MergeStrategies map[string]MergeStrategy `yaml:"merge_strategies,omitempty"`
+	// Filters restrict the rows read from the rule's source; see FilteredQuery
+	Filters []RuleFilter `yaml:"filters,omitempty"`
+}
+
+// RelationshipIdentity identifies a relationship by its type and the keys of its endpoints,
+// and by the value of its Discriminator property when set, for relationships of one type
+// between the same nodes that must stay apart (e.g. a role)
+type RelationshipIdentity struct {
+	Discriminator string `yaml:"discriminator,omitempty"`
+}
+
+// DefaultMaxTextLength is the longest string stored on a node or relationship by default
+const DefaultMaxTextLength = 10000
+
+// TruncatedMarker is the property listing which properties were shortened by MaxTextLength
+const TruncatedMarker = "_truncated"
+
+// TextLimit returns the effective maximum text length, or 0 when text is not capped
+func (r TransformRule) TextLimit() int {
+	switch {
+	case r.MaxTextLength < 0:
+		return 0
+	case r.MaxTextLength == 0:
+		return DefaultMaxTextLength
+	default:
+		return r.MaxTextLength
+	}
 }
 
 func (rt RuleType) Validate() bool {
@@ -12,14 +12,17 @@
 package factories
 
 import (
-	"fmt"
 	"sql-graph-visualizer/internal/domain/models"
 	"sql-graph-visualizer/internal/domain/repository"
-	"sql-graph-visualizer/internal/infrastructure/persistence/mysql"
-	"sql-graph-visualizer/internal/infrastructure/persistence/postgresql"
+	"sql-graph-visualizer/internal/infrastructure/persistence/registry"
+
+	// Built-in databases register themselves with the registry
+	_ "sql-graph-visualizer/internal/infrastructure/persistence/mysql"
+	_ "sql-graph-visualizer/internal/infrastructure/persistence/postgresql"
 )
 
-// DatabaseRepositoryFactory creates database-specific repository implementations
+// DatabaseRepositoryFactory creates database-specific repository implementations from the
+// drivers in the persistence registry
 type DatabaseRepositoryFactory struct{}
 
 // NewDatabaseRepositoryFactory creates a new repository factory
@@ -29,20 +32,27 @@ func NewDatabaseRepositoryFactory() repository.DatabaseRepositoryFactory {
 
 // CreateRepository creates a database-specific repository based on database type
 func (f *DatabaseRepositoryFactory) CreateRepository(dbType models.DatabaseType) (repository.DatabaseRepository, error) {
-	switch dbType {
-	case models.DatabaseTypeMySQL:
-		return mysql.NewMySQLDatabaseRepository(), nil
-	case models.DatabaseTypePostgreSQL:
-		return postgresql.NewPostgreSQLDatabaseRepository(), nil
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	driver, err := registry.Lookup(dbType)
+	if err != nil {
+		return nil, err
 	}
+	return driver.NewRepository(), nil
 }
 
 // GetSupportedDatabaseTypes returns list of supported database types
 func (f *DatabaseRepositoryFactory) GetSupportedDatabaseTypes() []models.DatabaseType {
-	return []models.DatabaseType{
-		models.DatabaseTypeMySQL,
-		models.DatabaseTypePostgreSQL,
+	return registry.Types()
+}
+
+// GetCapabilities returns the feature matrix for the given database type.
+// Unsupported types return a zero-value matrix with Supported set to false.
+func (f *DatabaseRepositoryFactory) GetCapabilities(dbType models.DatabaseType) repository.DatabaseCapabilities {
+	driver, err := registry.Lookup(dbType)
+	if err != nil {
+		return repository.DatabaseCapabilities{
+			DatabaseType: dbType,
+			Supported:    false,
+		}
 	}
+	return driver.Capabilities
 }
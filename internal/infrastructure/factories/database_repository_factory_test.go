@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package factories
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+	"sql-graph-visualizer/internal/infrastructure/persistence/registry"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	factory := NewDatabaseRepositoryFactory()
+
+	tests := []struct {
+		name                  string
+		dbType                models.DatabaseType
+		supported             bool
+		foreignKeyDiscovery   bool
+		performanceCollection bool
+		performanceSource     string
+		writeSupport          bool
+		arrayColumns          bool
+	}{
+		{
+			name:                  "MySQL",
+			dbType:                models.DatabaseTypeMySQL,
+			supported:             true,
+			foreignKeyDiscovery:   true,
+			performanceCollection: true,
+			performanceSource:     "performance_schema",
+		},
+		{
+			name:                  "PostgreSQL",
+			dbType:                models.DatabaseTypePostgreSQL,
+			supported:             true,
+			foreignKeyDiscovery:   true,
+			performanceCollection: true,
+			performanceSource:     "pg_stat_statements",
+			arrayColumns:          true,
+		},
+		{
+			name:   "SQLite",
+			dbType: models.DatabaseType("sqlite"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := factory.GetCapabilities(tt.dbType)
+
+			assert.Equal(t, tt.dbType, caps.DatabaseType)
+			assert.Equal(t, tt.supported, caps.Supported)
+			assert.Equal(t, tt.foreignKeyDiscovery, caps.ForeignKeyDiscovery)
+			assert.Equal(t, tt.performanceCollection, caps.PerformanceCollection)
+			assert.Equal(t, tt.performanceSource, caps.PerformanceSource)
+			assert.Equal(t, tt.writeSupport, caps.WriteSupport)
+			assert.Equal(t, tt.arrayColumns, caps.ArrayColumns)
+		})
+	}
+}
+
+func TestGetCapabilitiesMatchesSupportedTypes(t *testing.T) {
+	factory := NewDatabaseRepositoryFactory()
+
+	for _, dbType := range factory.GetSupportedDatabaseTypes() {
+		caps := factory.GetCapabilities(dbType)
+		assert.True(t, caps.Supported, "supported type %s must report capabilities", dbType)
+
+		_, err := factory.CreateRepository(dbType)
+		assert.NoError(t, err)
+	}
+
+	_, err := factory.CreateRepository(models.DatabaseType("sqlite"))
+	assert.Error(t, err)
+}
+
+// fakeRepository is the schema repository of the test-only database type
+type fakeRepository struct {
+	repository.DatabaseRepository
+}
+
+const fakeDatabaseType = models.DatabaseType("fakedb")
+
+func registerFakeDatabase() {
+	if _, err := registry.Lookup(fakeDatabaseType); err == nil {
+		return
+	}
+	registry.Register(registry.Driver{
+		Type: fakeDatabaseType,
+		Open: func(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error) {
+			return nil, nil
+		},
+		NewDatabasePort: func(db *sql.DB) ports.DatabasePort { return nil },
+		NewRepository:   func() repository.DatabaseRepository { return &fakeRepository{} },
+		Capabilities:    repository.DatabaseCapabilities{DataSampling: true},
+	})
+}
+
+func TestFactoryResolvesRegisteredDatabase(t *testing.T) {
+	registerFakeDatabase()
+	factory := NewDatabaseRepositoryFactory()
+
+	repo, err := factory.CreateRepository(fakeDatabaseType)
+	require.NoError(t, err)
+	assert.IsType(t, &fakeRepository{}, repo)
+
+	assert.Contains(t, factory.GetSupportedDatabaseTypes(), fakeDatabaseType)
+	assert.Contains(t, factory.GetSupportedDatabaseTypes(), models.DatabaseTypeMySQL)
+
+	caps := factory.GetCapabilities(fakeDatabaseType)
+	assert.True(t, caps.Supported)
+	assert.True(t, caps.DataSampling)
+	assert.Equal(t, fakeDatabaseType, caps.DatabaseType)
+}
@@ -15,6 +15,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"sql-graph-visualizer/internal/domain/models"
 	"sql-graph-visualizer/internal/domain/repository"
 	"strings"
@@ -44,25 +45,7 @@ func (r *MySQLDatabaseRepository) Connect(ctx context.Context, config models.Dat
 	// Use Username if set, otherwise fallback to User
 	username := mysqlConfig.GetUsername()
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&timeout=%ds&readTimeout=%ds&writeTimeout=%ds",
-		username,
-		mysqlConfig.GetPassword(),
-		mysqlConfig.GetHost(),
-		mysqlConfig.GetPort(),
-		mysqlConfig.GetDatabase(),
-		mysqlConfig.GetSecurity().ConnectionTimeout,
-		mysqlConfig.GetSecurity().QueryTimeout,
-		mysqlConfig.GetSecurity().QueryTimeout,
-	)
-
-	// Add SSL configuration if enabled
-	sslConfig := mysqlConfig.GetSSLConfig()
-	if sslConfig.Enabled {
-		dsn += "&tls=true"
-		if sslConfig.InsecureSkipVerify {
-			dsn += "&tls=skip-verify"
-		}
-	}
+	dsn := BuildDSN(mysqlConfig)
 
 	logrus.Infof("Connecting to MySQL database: %s@%s:%d/%s", username, mysqlConfig.GetHost(), mysqlConfig.GetPort(), mysqlConfig.GetDatabase())
 
@@ -112,7 +95,7 @@ func (r *MySQLDatabaseRepository) GetTables(ctx context.Context, filters models.
 		return nil, fmt.Errorf("no active database connection")
 	}
 
-	query := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
+	query := "SELECT TABLE_SCHEMA, TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -122,10 +105,13 @@ func (r *MySQLDatabaseRepository) GetTables(ctx context.Context, filters models.
 
 	var allTables []string
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var schema, tableName string
+		if err := rows.Scan(&schema, &tableName); err != nil {
 			return nil, err
 		}
+		if filters.IsSystemSchema(models.DatabaseTypeMySQL, schema) {
+			continue
+		}
 		allTables = append(allTables, tableName)
 	}
 
@@ -279,8 +265,9 @@ func (r *MySQLDatabaseRepository) GetIndexes(ctx context.Context, tableName stri
 	for _, idx := range indexMap {
 		indexes = append(indexes, *idx)
 	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
 
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
 // GetConstraints retrieves constraint information for a table
@@ -365,7 +352,7 @@ func (r *MySQLDatabaseRepository) GetSchemaNames(ctx context.Context) ([]string,
 			return nil, err
 		}
 		// Skip system databases
-		if schema != "information_schema" && schema != "mysql" && schema != "performance_schema" && schema != "sys" {
+		if !models.IsSystemSchema(schema, models.DefaultSystemSchemas(models.DatabaseTypeMySQL)) {
 			schemas = append(schemas, schema)
 		}
 	}
@@ -405,12 +392,100 @@ func (r *MySQLDatabaseRepository) SampleTableData(ctx context.Context, tableName
 	return nil, fmt.Errorf("not implemented yet")
 }
 
+// SampleColumnValues returns up to limit distinct non-NULL values of a column
+func (r *MySQLDatabaseRepository) SampleColumnValues(ctx context.Context, tableName, columnName string, limit int) ([]interface{}, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no active database connection")
+	}
+
+	column := r.EscapeIdentifier(columnName)
+	// #nosec G201 - identifiers are escaped using EscapeIdentifier
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL LIMIT %d",
+		column, r.EscapeIdentifier(tableName), column, limit)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample column values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []interface{}
+	for rows.Next() {
+		var value interface{}
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan column value: %w", err)
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// CountMatchingValues returns how many of the given values exist in a column
+func (r *MySQLDatabaseRepository) CountMatchingValues(ctx context.Context, tableName, columnName string, values []interface{}) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no active database connection")
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	column := r.EscapeIdentifier(columnName)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+	// #nosec G201 - identifiers are escaped using EscapeIdentifier, values are bound
+	query := fmt.Sprintf("SELECT COUNT(DISTINCT %s) FROM %s WHERE %s IN (%s)",
+		column, r.EscapeIdentifier(tableName), column, placeholders)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, values...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count matching values in %s.%s: %w", tableName, columnName, err)
+	}
+	return count, nil
+}
+
+// SampleRowCount counts the rows picked with probability percent/100, reading at most limit
+// rows. MySQL has no TABLESAMPLE, so rows are picked with RAND().
+func (r *MySQLDatabaseRepository) SampleRowCount(ctx context.Context, tableName string, percent float64, limit int) (int64, float64, error) {
+	if r.db == nil {
+		return 0, 0, fmt.Errorf("no active database connection")
+	}
+
+	// #nosec G201 - tableName is escaped using EscapeIdentifier, limit is a number
+	query := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s WHERE RAND() < ? LIMIT %d) AS sample",
+		r.EscapeIdentifier(tableName), limit)
+
+	var rows int64
+	if err := r.db.QueryRowContext(ctx, query, percent/100).Scan(&rows); err != nil {
+		return 0, 0, fmt.Errorf("failed to sample rows of %s: %w", tableName, err)
+	}
+	return rows, percent / 100, nil
+}
+
 func (r *MySQLDatabaseRepository) AnalyzeColumnStatistics(ctx context.Context, tableName, columnName string) (*models.ColumnStatistics, error) {
 	return nil, fmt.Errorf("not implemented yet")
 }
 
+// GetTableSize reads a table's row estimate and storage size from INFORMATION_SCHEMA, without
+// touching the table itself. TABLE_ROWS is an estimate for InnoDB.
 func (r *MySQLDatabaseRepository) GetTableSize(ctx context.Context, tableName string) (*models.TableSize, error) {
-	return nil, fmt.Errorf("not implemented yet")
+	if r.db == nil {
+		return nil, fmt.Errorf("no active database connection")
+	}
+
+	query := `
+		SELECT COALESCE(TABLE_ROWS, 0), COALESCE(DATA_LENGTH, 0), COALESCE(INDEX_LENGTH, 0)
+		FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_SCHEMA = DATABASE()
+			AND TABLE_NAME = ?
+	`
+
+	var rows, dataSize, indexSize int64
+	if err := r.db.QueryRowContext(ctx, query, tableName).Scan(&rows, &dataSize, &indexSize); err != nil {
+		return nil, fmt.Errorf("failed to read catalog size of %s: %w", tableName, err)
+	}
+	return models.NewTableSize(tableName, rows, dataSize, indexSize), nil
 }
 
 func (r *MySQLDatabaseRepository) GetQueryExecutionPlan(ctx context.Context, query string) (string, error) {
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+	"sql-graph-visualizer/internal/infrastructure/persistence/registry"
+)
+
+func init() {
+	registry.Register(registry.Driver{
+		Type:            models.DatabaseTypeMySQL,
+		Open:            openDatabase,
+		NewDatabasePort: NewMySQLDatabasePort,
+		NewRepository:   NewMySQLDatabaseRepository,
+		Capabilities: repository.DatabaseCapabilities{
+			ForeignKeyDiscovery:   true,
+			IndexDiscovery:        true,
+			SchemaNamespaces:      false,
+			DataSampling:          true,
+			WriteSupport:          false,
+			QueryExplain:          true,
+			ArrayColumns:          false,
+			JSONColumns:           true,
+			PerformanceCollection: true,
+			PerformanceSource:     "performance_schema",
+			Benchmarking:          true,
+		},
+	})
+}
+
+// openDatabase opens a MySQL connection pool and pings it
+func openDatabase(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error) {
+	mysqlConfig, ok := config.(*models.MySQLConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected MySQL configuration, got %T", config)
+	}
+
+	db, err := sql.Open("mysql", BuildDSN(mysqlConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
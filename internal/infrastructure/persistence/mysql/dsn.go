@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package mysql
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// BuildDSN builds a go-sql-driver DSN for config. Credentials are passed through the
+// driver's own formatter so passwords containing '@', ':' or '/' survive round-tripping.
+// DATETIME and TIMESTAMP columns are returned as time.Time unless config.ParseTime is false.
+func BuildDSN(c *models.MySQLConfig) string {
+	port := c.Port
+	if port == 0 {
+		port = models.DefaultMySQLPort
+	}
+
+	cfg := driver.NewConfig()
+	cfg.User = c.GetUsername()
+	cfg.Passwd = c.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(c.Host, strconv.Itoa(port))
+	cfg.DBName = c.Database
+	cfg.ParseTime = c.ParsesTime()
+
+	if c.Security.ConnectionTimeout > 0 {
+		cfg.Timeout = time.Duration(c.Security.ConnectionTimeout) * time.Second
+	}
+	if c.Security.QueryTimeout > 0 {
+		cfg.ReadTimeout = time.Duration(c.Security.QueryTimeout) * time.Second
+		cfg.WriteTimeout = time.Duration(c.Security.QueryTimeout) * time.Second
+	}
+
+	if c.SSLConfig.Enabled {
+		cfg.TLSConfig = "true"
+		if c.SSLConfig.InsecureSkipVerify {
+			cfg.TLSConfig = "skip-verify"
+		}
+	}
+
+	return cfg.FormatDSN()
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestBuildDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+	}{
+		{name: "plain password", password: "secret"},
+		{name: "at sign and colon", password: "p@ss:word"},
+		{name: "slash and question mark", password: "a/b?c=d&e"},
+		{name: "parentheses", password: "tcp(evil:1)/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.MySQLConfig{
+				Host:     "db.internal",
+				Port:     3307,
+				Username: "graph",
+				Password: tt.password,
+				Database: "shop",
+				Security: models.SecurityConfig{ConnectionTimeout: 5, QueryTimeout: 30},
+			}
+
+			parsed, err := driver.ParseDSN(BuildDSN(config))
+			require.NoError(t, err)
+			assert.Equal(t, "graph", parsed.User)
+			assert.Equal(t, tt.password, parsed.Passwd)
+			assert.Equal(t, "db.internal:3307", parsed.Addr)
+			assert.Equal(t, "shop", parsed.DBName)
+			assert.Equal(t, 5*time.Second, parsed.Timeout)
+			assert.Equal(t, 30*time.Second, parsed.ReadTimeout)
+		})
+	}
+}
+
+func TestBuildDSNDefaults(t *testing.T) {
+	config := &models.MySQLConfig{Host: "localhost", User: "legacy", Database: "shop"}
+
+	parsed, err := driver.ParseDSN(BuildDSN(config))
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", parsed.User, "User is used when Username is empty")
+	assert.Equal(t, "localhost:3306", parsed.Addr)
+	assert.True(t, parsed.ParseTime, "DATETIME columns are read as time values by default")
+
+	parseTime := false
+	config.ParseTime = &parseTime
+	parsed, err = driver.ParseDSN(BuildDSN(config))
+	require.NoError(t, err)
+	assert.False(t, parsed.ParseTime, "parse_time: false keeps DATETIME columns as text")
+	config.ParseTime = nil
+
+	config.SSLConfig = models.SSLConfig{Enabled: true, InsecureSkipVerify: true}
+	assert.Contains(t, BuildDSN(config), "tls=skip-verify")
+	assert.NotContains(t, BuildDSN(config), "tls=true")
+}
+
+func TestBuildDSNParseTimeFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		parseTime bool
+	}{
+		{name: "not set", yaml: "host: localhost", parseTime: true},
+		{name: "enabled", yaml: "host: localhost\nparse_time: true", parseTime: true},
+		{name: "disabled", yaml: "host: localhost\nparse_time: false", parseTime: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config models.MySQLConfig
+			require.NoError(t, yaml.Unmarshal([]byte(tt.yaml), &config))
+
+			parsed, err := driver.ParseDSN(BuildDSN(&config))
+			require.NoError(t, err)
+			assert.Equal(t, tt.parseTime, parsed.ParseTime)
+			assert.Equal(t, tt.parseTime, config.ParsesTime())
+		})
+	}
+}
@@ -48,41 +48,7 @@ func (r *MySQLRepository) Close() error {
 }
 
 func (r *MySQLRepository) ExecuteQuery(query string) ([]map[string]any, error) {
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("Error closing rows: %v", err)
-		}
-	}()
-
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
-
-	var results []map[string]any
-	for rows.Next() {
-		row := make(map[string]any)
-		columnPointers := make([]any, len(columns))
-		for i := range columns {
-			columnPointers[i] = new(any)
-		}
-
-		if err := rows.Scan(columnPointers...); err != nil {
-			return nil, err
-		}
-
-		for i, colName := range columns {
-			row[colName] = *(columnPointers[i].(*any))
-		}
-
-		results = append(results, row)
-	}
-
-	return results, nil
+	return r.ExecuteQueryWithContext(context.Background(), query)
 }
 
 // New methods for direct database connection (Issue #10)
@@ -95,24 +61,7 @@ func (r *MySQLRepository) ConnectToExisting(ctx context.Context, config *models.
 		username = config.User
 	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&timeout=%ds&readTimeout=%ds&writeTimeout=%ds",
-		username,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.Database,
-		config.Security.ConnectionTimeout,
-		config.Security.QueryTimeout,
-		config.Security.QueryTimeout,
-	)
-
-	// Add SSL configuration if enabled
-	if config.SSLConfig.Enabled {
-		dsn += "&tls=true"
-		if config.SSLConfig.InsecureSkipVerify {
-			dsn += "&tls=skip-verify"
-		}
-	}
+	dsn := BuildDSN(config)
 
 	logrus.Infof("Connecting to existing database: %s@%s:%d/%s", username, config.Host, config.Port, config.Database)
 
@@ -260,7 +209,7 @@ func (r *MySQLRepository) DiscoverSchema(ctx context.Context, db *sql.DB, filter
 
 // GetTables returns list of tables based on filtering configuration
 func (r *MySQLRepository) GetTables(ctx context.Context, db *sql.DB, filters *models.DataFilteringConfig) ([]string, error) {
-	query := "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
+	query := "SELECT TABLE_SCHEMA, TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_TYPE = 'BASE TABLE'"
 
 	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
@@ -268,12 +217,19 @@ func (r *MySQLRepository) GetTables(ctx context.Context, db *sql.DB, filters *mo
 	}
 	defer rows.Close()
 
+	var systemFilters models.DataFilteringConfig
+	if filters != nil {
+		systemFilters = *filters
+	}
 	var allTables []string
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var schema, tableName string
+		if err := rows.Scan(&schema, &tableName); err != nil {
 			return nil, err
 		}
+		if systemFilters.IsSystemSchema(models.DatabaseTypeMySQL, schema) {
+			continue
+		}
 		allTables = append(allTables, tableName)
 	}
 
@@ -451,10 +407,53 @@ func (r *MySQLRepository) ExtractTableData(ctx context.Context, db *sql.DB, tabl
 
 // ExecuteQueryWithContext executes query with context timeout
 func (r *MySQLRepository) ExecuteQueryWithContext(ctx context.Context, query string) ([]map[string]any, error) {
-	rows, err := r.db.QueryContext(ctx, query)
+	var results []map[string]any
+	err := r.StreamQuery(ctx, query, func(row map[string]any) error {
+		results = append(results, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// StreamQuery executes query and passes each row to fn as it is read, so large results are
+// never held in memory at once
+func (r *MySQLRepository) StreamQuery(ctx context.Context, query string, fn func(row map[string]any) error) error {
+	return r.streamRows(ctx, fn, query)
+}
+
+// QueryPlaceholder implements ports.ParameterizedQueryExecutor; MySQL uses "?" for every argument
+func (r *MySQLRepository) QueryPlaceholder(n int) string {
+	return "?"
+}
+
+// ExecuteQueryWithArgs executes query with its arguments passed separately to the driver
+func (r *MySQLRepository) ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	var results []map[string]any
+	err := r.streamRows(ctx, func(row map[string]any) error {
+		results = append(results, row)
+		return nil
+	}, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	return results, nil
+}
+
+// StreamQueryWithArgs executes query with its arguments passed separately to the driver and
+// passes each row to fn as it is read
+func (r *MySQLRepository) StreamQueryWithArgs(ctx context.Context, query string, args []any, fn func(row map[string]any) error) error {
+	return r.streamRows(ctx, fn, query, args...)
+}
+
+// streamRows executes query with args and passes each row to fn as it is read
+func (r *MySQLRepository) streamRows(ctx context.Context, fn func(row map[string]any) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if err := rows.Close(); err != nil {
 			log.Printf("Error closing rows: %v", err)
@@ -463,10 +462,14 @@ func (r *MySQLRepository) ExecuteQueryWithContext(ctx context.Context, query str
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
 	}
 
-	var results []map[string]any
 	for rows.Next() {
 		row := make(map[string]any)
 		columnPointers := make([]any, len(columns))
@@ -475,17 +478,51 @@ func (r *MySQLRepository) ExecuteQueryWithContext(ctx context.Context, query str
 		}
 
 		if err := rows.Scan(columnPointers...); err != nil {
-			return nil, err
+			return err
 		}
 
 		for i, colName := range columns {
-			row[colName] = *(columnPointers[i].(*any))
+			value := *(columnPointers[i].(*any))
+			// The text protocol returns every string column as []byte; only binary
+			// columns should stay that way so callers can tell BLOBs from text. BIT
+			// columns (often BIT(1) flags) are numbers.
+			if raw, ok := value.([]byte); ok {
+				switch typeName := columnTypes[i].DatabaseTypeName(); {
+				case strings.EqualFold(typeName, "BIT"):
+					value = bitValue(raw)
+				case !isBinaryColumnType(typeName):
+					value = string(raw)
+				}
+			}
+			row[colName] = value
 		}
 
-		results = append(results, row)
+		if err := fn(row); err != nil {
+			return err
+		}
 	}
 
-	return results, nil
+	return rows.Err()
+}
+
+// isBinaryColumnType reports whether a MySQL column type holds raw bytes rather than text
+func isBinaryColumnType(typeName string) bool {
+	switch strings.ToUpper(typeName) {
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "GEOMETRY":
+		return true
+	default:
+		return false
+	}
+}
+
+// bitValue reads a BIT(n) value, sent as big-endian bytes, as an integer; BIT(64) values
+// above the int64 range wrap around
+func bitValue(raw []byte) int64 {
+	var value uint64
+	for _, b := range raw {
+		value = value<<8 | uint64(b)
+	}
+	return int64(value)
 }
 
 // EstimateDataSize provides dataset size estimation
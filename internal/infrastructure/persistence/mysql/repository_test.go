@@ -0,0 +1,27 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitColumnsAreReadAsIntegers(t *testing.T) {
+	assert.False(t, isBinaryColumnType("BIT"), "BIT flags are imported, not dropped as binary")
+	assert.True(t, isBinaryColumnType("blob"))
+
+	assert.Equal(t, int64(0), bitValue([]byte{0}))
+	assert.Equal(t, int64(1), bitValue([]byte{1}))
+	assert.Equal(t, int64(0x0102), bitValue([]byte{0x01, 0x02}))
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sql-graph-visualizer/internal/application/ports"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// managedNodesQuery reads the nodes in scope, reporting each under its first label in scope
+const managedNodesQuery = `
+	MATCH (n)
+	WHERE any(l IN labels(n) WHERE l IN $labels) AND NOT $annotation IN labels(n) AND n.id IS NOT NULL
+	RETURN n, [l IN labels(n) WHERE l IN $labels][0]`
+
+// managedRelationshipsQuery reads the relationships in scope between nodes in scope
+const managedRelationshipsQuery = `
+	MATCH (a)-[r]->(b)
+	WHERE type(r) IN $types
+		AND any(l IN labels(a) WHERE l IN $labels) AND NOT $annotation IN labels(a)
+		AND any(l IN labels(b) WHERE l IN $labels) AND NOT $annotation IN labels(b)
+	RETURN r, [l IN labels(a) WHERE l IN $labels][0], a.id, [l IN labels(b) WHERE l IN $labels][0], b.id`
+
+// ReadManagedGraph returns the stored nodes and relationships within scope
+func (r *Neo4jRepository) ReadManagedGraph(ctx context.Context, scope ports.ReconcileScope) ([]ports.ExportedNode, []ports.ExportedRelationship, error) {
+	params := map[string]any{
+		"labels":     scope.NodeLabels,
+		"types":      scope.RelationshipTypes,
+		"annotation": scope.AnnotationLabel,
+	}
+
+	records, err := r.readPage(ctx, managedNodesQuery, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read managed nodes: %w", err)
+	}
+	nodes := make([]ports.ExportedNode, 0, len(records))
+	for _, record := range records {
+		node := record.Values[0].(neo4j.Node)
+		label, _ := record.Values[1].(string)
+		nodes = append(nodes, ports.ExportedNode{ID: node.Id, Label: label, Labels: node.Labels, Properties: node.Props})
+	}
+
+	records, err = r.readPage(ctx, managedRelationshipsQuery, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read managed relationships: %w", err)
+	}
+	rels := make([]ports.ExportedRelationship, 0, len(records))
+	for _, record := range records {
+		rel := record.Values[0].(neo4j.Relationship)
+		sourceLabel, _ := record.Values[1].(string)
+		targetLabel, _ := record.Values[3].(string)
+		rels = append(rels, ports.ExportedRelationship{
+			ID:          rel.Id,
+			Type:        rel.Type,
+			SourceLabel: sourceLabel,
+			SourceKey:   record.Values[2],
+			TargetLabel: targetLabel,
+			TargetKey:   record.Values[4],
+			Properties:  rel.Props,
+		})
+	}
+	return nodes, rels, nil
+}
+
+// ApplyGraphDelta writes the delta in batches like StoreGraphInBatches. Deletions run
+// first, so a relationship replaced by one with other endpoints never exists twice.
+func (r *Neo4jRepository) ApplyGraphDelta(ctx context.Context, delta *ports.GraphDelta, onCommit func(ports.GraphWriteProgress)) error {
+	session := r.driver.NewSession(neo4j.SessionConfig{})
+	defer func() {
+		if err := session.Close(); err != nil {
+			log.Printf("Error closing session: %v", err)
+		}
+	}()
+
+	writer := r.newBatchWriter(session, onCommit)
+	defer writer.rollback()
+
+	skipped := make(map[string]int)
+	defer reportSkippedRelationships(skipped)
+	for _, statement := range deltaStatements(delta) {
+		result, err := writer.run(ctx, statement.query, statement.params, statement.nodes+statement.relationships)
+		if err != nil {
+			return err
+		}
+		relationships := statement.relationships
+		if statement.matchesEndpoints && !endpointsFound(result) {
+			writer.skip()
+			skipped[statement.relationshipType]++
+			relationships = 0
+		}
+		if err := writer.done(statement.nodes, relationships); err != nil {
+			return err
+		}
+	}
+	return writer.commit()
+}
+
+// deltaStatement is one write of a delta and the elements it counts as written. A statement
+// that matchesEndpoints creates a relationship of relationshipType and returns how many it
+// wrote, so one whose endpoints were not found is counted as skipped.
+type deltaStatement struct {
+	query            string
+	params           map[string]any
+	nodes            int
+	relationships    int
+	matchesEndpoints bool
+	relationshipType string
+}
+
+// deltaStatements orders the writes of a delta: deletions, then nodes, then relationships
+// whose endpoints may only just have been created
+func deltaStatements(delta *ports.GraphDelta) []deltaStatement {
+	var statements []deltaStatement
+	for _, rel := range delta.DeleteRelationships {
+		statements = append(statements, deltaStatement{
+			query:         "MATCH ()-[r]->() WHERE id(r) = $id DELETE r",
+			params:        map[string]any{"id": rel.ID},
+			relationships: 1,
+		})
+	}
+	for _, node := range delta.DeleteNodes {
+		statements = append(statements, deltaStatement{
+			query:  "MATCH (n) WHERE id(n) = $id DETACH DELETE n",
+			params: map[string]any{"id": node.ID},
+			nodes:  1,
+		})
+	}
+	for _, node := range delta.CreateNodes {
+		statements = append(statements, deltaStatement{
+			query:  "CREATE (n:" + strings.Join(node.AllLabels(), ":") + ") SET n = $props",
+			params: map[string]any{"props": node.Properties},
+			nodes:  1,
+		})
+	}
+	for _, node := range delta.UpdateNodes {
+		query := "MATCH (n) WHERE id(n) = $id SET n = $props, n:" + strings.Join(node.AllLabels(), ":")
+		if removed := delta.RemoveLabels[node.ID]; len(removed) > 0 {
+			query += " REMOVE n:" + strings.Join(removed, ":")
+		}
+		statements = append(statements, deltaStatement{
+			query:  query,
+			params: map[string]any{"id": node.ID, "props": node.Properties},
+			nodes:  1,
+		})
+	}
+	for _, rel := range delta.CreateRelationships {
+		statements = append(statements, deltaStatement{
+			query: "MATCH (a:" + rel.SourceLabel + " {id: $sourceId}), (b:" + rel.TargetLabel + " {id: $targetId}) " +
+				"CREATE (a)-[r:" + rel.Type + "]->(b) SET r = $props" + writtenCountReturn,
+			params:           map[string]any{"sourceId": rel.SourceKey, "targetId": rel.TargetKey, "props": rel.Properties},
+			relationships:    1,
+			matchesEndpoints: true,
+			relationshipType: rel.Type,
+		})
+	}
+	for _, rel := range delta.UpdateRelationships {
+		statements = append(statements, deltaStatement{
+			query:         "MATCH ()-[r]->() WHERE id(r) = $id SET r = $props",
+			params:        map[string]any{"id": rel.ID, "props": rel.Properties},
+			relationships: 1,
+		})
+	}
+	return statements
+}
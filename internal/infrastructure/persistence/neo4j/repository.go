@@ -12,16 +12,38 @@
 package neo4j
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
+	"sql-graph-visualizer/internal/application/ports"
 	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	"sql-graph-visualizer/internal/domain/entities"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+	"strings"
+	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 	"github.com/sirupsen/logrus"
 )
 
+// DefaultWriteBatchSize is how many statements StoreGraph commits per transaction
+const DefaultWriteBatchSize = 500
+
 type Neo4jRepository struct {
-	driver neo4j.Driver
+	driver         neo4j.Driver
+	writeBatchSize int
+	writeBudget    WriteBudget
+}
+
+// WriteBudget bounds each write transaction so huge batches do not exhaust the Neo4j heap.
+// A batch about to exceed MaxEntities nodes and relationships, or MaxBytes of statement
+// parameters, is committed early and continued in a new transaction. TxTimeout caps how long
+// Neo4j lets one transaction run. Zero values leave the bound out.
+type WriteBudget struct {
+	MaxEntities int
+	MaxBytes    int64
+	TxTimeout   time.Duration
 }
 
 func NewNeo4jRepository(uri, username, password string) (*Neo4jRepository, error) {
@@ -36,6 +58,39 @@ func NewNeo4jRepository(uri, username, password string) (*Neo4jRepository, error
 }
 
 func (r *Neo4jRepository) StoreGraph(graph *graph.GraphAggregate) error {
+	return r.StoreGraphWithContext(context.Background(), graph)
+}
+
+// SetWriteBatchSize sets how many statements are committed per transaction when storing a graph
+func (r *Neo4jRepository) SetWriteBatchSize(size int) {
+	r.writeBatchSize = size
+}
+
+// SetWriteBudget bounds the size and duration of every write transaction
+func (r *Neo4jRepository) SetWriteBudget(budget WriteBudget) {
+	r.writeBudget = budget
+}
+
+// newBatchWriter creates the writer of one store run on session
+func (r *Neo4jRepository) newBatchWriter(session neo4j.Session, onCommit func(ports.GraphWriteProgress)) *batchWriter {
+	batchSize := r.writeBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultWriteBatchSize
+	}
+	return &batchWriter{session: session, size: batchSize, budget: r.writeBudget, onCommit: onCommit}
+}
+
+// StoreGraphWithContext stores the graph, stopping between statements once ctx is done.
+// When ctx has a deadline each batch gets a matching server-side transaction timeout,
+// so a statement already running is aborted by Neo4j as well.
+func (r *Neo4jRepository) StoreGraphWithContext(ctx context.Context, graph *graph.GraphAggregate) error {
+	return r.StoreGraphInBatches(ctx, graph, nil)
+}
+
+// StoreGraphInBatches stores the graph in transactions of writeBatchSize statements.
+// Once ctx is done the open transaction is rolled back and previously committed batches
+// are kept, so the graph reflects the last committed batch.
+func (r *Neo4jRepository) StoreGraphInBatches(ctx context.Context, graph *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
 	session := r.driver.NewSession(neo4j.SessionConfig{})
 	defer func() {
 		if err := session.Close(); err != nil {
@@ -43,60 +98,390 @@ func (r *Neo4jRepository) StoreGraph(graph *graph.GraphAggregate) error {
 		}
 	}()
 
+	writer := r.newBatchWriter(session, onCommit)
+	defer writer.rollback()
+
 	// Store nodes
 	for _, node := range graph.GetNodes() {
-		query := "CREATE (n:" + node.Type + ") SET n = $props"
-		if _, err := session.Run(query, map[string]any{
-			"props": node.Properties,
-		}); err != nil {
+		query, params := nodeWriteQuery(node)
+		if _, err := writer.run(ctx, query, params, 1); err != nil {
 			return err
 		}
 		logrus.Infof("Node saved: type=%s, properties=%+v", node.Type, node.Properties)
+
+		if err := writer.done(1, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := writeRelationships(ctx, writer, graph); err != nil {
+		return err
 	}
 
-	// Store relationships
+	return writer.commit()
+}
+
+// nodeWriteQuery creates a node, or merges it on its composite key properties when it has
+// MergeKeys so rows sharing the key end up as one node
+func nodeWriteQuery(node *entities.Node) (string, map[string]any) {
+	params := map[string]any{"props": node.Properties}
+	labels := strings.Join(node.Labels(), ":")
+	if len(node.MergeKeys) == 0 {
+		return "CREATE (n:" + labels + ") SET n = $props", params
+	}
+
+	names := make([]string, 0, len(node.MergeKeys))
+	for name := range node.MergeKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pattern := make([]string, len(names))
+	for i, name := range names {
+		param := fmt.Sprintf("key%d", i)
+		pattern[i] = "`" + strings.ReplaceAll(name, "`", "``") + "`: $" + param
+		params[param] = node.MergeKeys[name]
+	}
+	return "MERGE (n:" + labels + " {" + strings.Join(pattern, ", ") + "}) SET n += $props", params
+}
+
+// relationshipWriteQuery creates a relationship between the nodes matched by $sourceId and
+// $targetId, or merges it on its type, endpoints and MergeKeys when it is Merged so storing it
+// again updates the one relationship. It returns the number of relationships written, which
+// is 0 when an endpoint was not found, as MATCH then yields no row to write from.
+func relationshipWriteQuery(rel graph.Relationship) (string, map[string]any) {
+	params := map[string]any{"props": rel.Properties}
+	match := "MATCH (a {id: $sourceId}), (b {id: $targetId}) "
+	if !rel.Merged {
+		return match + "CREATE (a)-[r:" + rel.Type + "]->(b) SET r = $props" + writtenCountReturn, params
+	}
+
+	names := make([]string, 0, len(rel.MergeKeys))
+	for name := range rel.MergeKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	identity := ""
+	if len(names) > 0 {
+		pattern := make([]string, len(names))
+		for i, name := range names {
+			param := fmt.Sprintf("key%d", i)
+			pattern[i] = "`" + strings.ReplaceAll(name, "`", "``") + "`: $" + param
+			params[param] = rel.MergeKeys[name]
+		}
+		identity = " {" + strings.Join(pattern, ", ") + "}"
+	}
+	return match + "MERGE (a)-[r:" + rel.Type + identity + "]->(b) SET r += $props" + writtenCountReturn, params
+}
+
+// writtenCountReturn ends a relationship write with the number of relationships it wrote
+const writtenCountReturn = " RETURN count(r)"
+
+// endpointsFound reads the count a relationship write ending in writtenCountReturn returns
+// and reports whether its endpoints were found
+func endpointsFound(result neo4j.Result) bool {
+	if !result.Next() {
+		return false
+	}
+	written, _ := result.Record().Values[0].(int64)
+	return written > 0
+}
+
+// StoreRelationshipsInBatches stores only the graph's relationships, matching their endpoints
+// against nodes already in Neo4j. Stored nodes and their properties are left untouched.
+func (r *Neo4jRepository) StoreRelationshipsInBatches(ctx context.Context, graph *graph.GraphAggregate, onCommit func(ports.GraphWriteProgress)) error {
+	session := r.driver.NewSession(neo4j.SessionConfig{})
+	defer func() {
+		if err := session.Close(); err != nil {
+			log.Printf("Error closing session: %v", err)
+		}
+	}()
+
+	writer := r.newBatchWriter(session, onCommit)
+	defer writer.rollback()
+
+	if err := writeRelationships(ctx, writer, graph); err != nil {
+		return err
+	}
+
+	return writer.commit()
+}
+
+// writeRelationships creates every relationship of the graph between nodes matched by id.
+// Relationships whose endpoints are not found are skipped, counted and reported by type.
+func writeRelationships(ctx context.Context, writer *batchWriter, graph *graph.GraphAggregate) error {
 	logrus.Infof("Number of relationships to save: %d", len(graph.GetRelationships()))
+	skipped := make(map[string]int)
+	defer reportSkippedRelationships(skipped)
 	for _, rel := range graph.GetRelationships() {
 		// Get the actual IDs from node properties instead of node entity IDs
 		sourceID, exists := rel.SourceNode.Properties["id"]
 		if !exists {
 			logrus.Warnf("Source node missing id property for relationship %s", rel.Type)
+			writer.skip()
+			skipped[rel.Type]++
 			continue
 		}
 		targetID, exists := rel.TargetNode.Properties["id"]
 		if !exists {
 			logrus.Warnf("Target node missing id property for relationship %s", rel.Type)
+			writer.skip()
+			skipped[rel.Type]++
 			continue
 		}
 
 		logrus.Infof("Creating relationship %s: %v -> %v", rel.Type, sourceID, targetID)
 
 		// Create relationship with proper source and target matching
-		query := "MATCH (a {id: $sourceId}), (b {id: $targetId}) CREATE (a)-[r:" + rel.Type + "]->(b) SET r = $props"
-		params := map[string]any{
-			"sourceId": sourceID,
-			"targetId": targetID,
-			"props":    rel.Properties,
-		}
+		query, params := relationshipWriteQuery(rel)
+		params["sourceId"] = sourceID
+		params["targetId"] = targetID
 
-		result, err := session.Run(query, params)
+		result, err := writer.run(ctx, query, params, 1)
 		if err != nil {
 			logrus.Errorf("Failed to create relationship %s from %v to %v: %v", rel.Type, sourceID, targetID, err)
 			return err
 		}
 
+		if !endpointsFound(result) {
+			logrus.Debugf("Skipped relationship %s from %v to %v: endpoint not found", rel.Type, sourceID, targetID)
+			writer.skip()
+			skipped[rel.Type]++
+		}
+
 		// Check if relationship was actually created
+		created := 0
 		summary, err := result.Consume()
 		if err != nil {
 			logrus.Warnf("Error consuming result for relationship %s: %v", rel.Type, err)
 		} else {
-			logrus.Infof("Relationship %s created successfully. Relationships created: %d", rel.Type, summary.Counters().RelationshipsCreated())
+			created = summary.Counters().RelationshipsCreated()
+			logrus.Infof("Relationship %s created successfully. Relationships created: %d", rel.Type, created)
+		}
+
+		if err := writer.done(0, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportSkippedRelationships warns about the relationships skipped for a missing endpoint
+func reportSkippedRelationships(skipped map[string]int) {
+	if len(skipped) == 0 {
+		return
+	}
+	types := make([]string, 0, len(skipped))
+	total := 0
+	for relType, count := range skipped {
+		types = append(types, fmt.Sprintf("%s: %d", relType, count))
+		total += count
+	}
+	sort.Strings(types)
+	logrus.Warnf("Skipped %d relationships whose source or target node was not found (%s)", total, strings.Join(types, ", "))
+}
+
+// batchWriter groups statements into explicit transactions and tracks what was committed
+type batchWriter struct {
+	session  neo4j.Session
+	size     int
+	budget   WriteBudget
+	onCommit func(ports.GraphWriteProgress)
+
+	tx              neo4j.Transaction
+	statements      int
+	pendingNodes    int
+	pendingRels     int
+	pendingSkipped  int
+	pendingEntities int
+	pendingBytes    int64
+	committed       ports.GraphWriteProgress
+}
+
+// run executes a statement writing up to entities nodes and relationships in the current
+// batch, opening a transaction if needed. A batch the statement would push over the write
+// budget is committed first. The batch is rolled back when ctx is done or the statement fails.
+func (w *batchWriter) run(ctx context.Context, query string, params map[string]any, entities int) (neo4j.Result, error) {
+	size := paramBytes(params)
+	if w.tx != nil && w.exceedsBudget(entities, size) {
+		logrus.Infof("Batch reached the write budget after %d statements (%d entities, %d bytes), committing early",
+			w.statements, w.pendingEntities, w.pendingBytes)
+		if err := w.commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.tx == nil {
+		configurers, err := writeTxTimeout(ctx, w.budget.TxTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if w.tx, err = w.session.BeginTransaction(configurers...); err != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", err)
 		}
+	} else if err := ctx.Err(); err != nil {
+		w.rollback()
+		return nil, err
 	}
 
+	result, err := w.tx.Run(query, params)
+	if err != nil {
+		w.rollback()
+		return nil, err
+	}
+	w.pendingEntities += entities
+	w.pendingBytes += size
+	return result, nil
+}
+
+// exceedsBudget reports whether adding a statement would take the open batch over budget
+func (w *batchWriter) exceedsBudget(entities int, size int64) bool {
+	if w.budget.MaxEntities > 0 && w.pendingEntities+entities > w.budget.MaxEntities {
+		return true
+	}
+	return w.budget.MaxBytes > 0 && w.pendingBytes+size > w.budget.MaxBytes
+}
+
+// skip records a relationship that was not written because an endpoint was not found
+func (w *batchWriter) skip() {
+	w.pendingSkipped++
+}
+
+// done records a finished statement and commits the batch once it is full
+func (w *batchWriter) done(nodes, relationships int) error {
+	w.statements++
+	w.pendingNodes += nodes
+	w.pendingRels += relationships
+	if w.statements >= w.size {
+		return w.commit()
+	}
 	return nil
 }
 
+func (w *batchWriter) commit() error {
+	if w.tx == nil {
+		// Relationships skipped before any statement opened a batch are reported still
+		if w.pendingSkipped == 0 {
+			return nil
+		}
+	} else {
+		err := w.tx.Commit()
+		w.closeTx()
+		if err != nil {
+			return fmt.Errorf("failed to commit batch: %w", err)
+		}
+		w.committed.BatchesCommitted++
+	}
+
+	w.committed.NodesWritten += w.pendingNodes
+	w.committed.RelationshipsWritten += w.pendingRels
+	w.committed.RelationshipsSkipped += w.pendingSkipped
+	w.resetBatch()
+
+	logrus.Infof("Committed batch %d: %d nodes, %d relationships written so far",
+		w.committed.BatchesCommitted, w.committed.NodesWritten, w.committed.RelationshipsWritten)
+	if w.onCommit != nil {
+		w.onCommit(w.committed)
+	}
+	return nil
+}
+
+func (w *batchWriter) rollback() {
+	if w.tx == nil {
+		return
+	}
+	if err := w.tx.Rollback(); err != nil {
+		logrus.Warnf("Error rolling back batch: %v", err)
+	} else {
+		logrus.Infof("Rolled back uncommitted batch of %d statements", w.statements)
+	}
+	w.closeTx()
+	w.resetBatch()
+}
+
+func (w *batchWriter) closeTx() {
+	if err := w.tx.Close(); err != nil {
+		logrus.Warnf("Error closing transaction: %v", err)
+	}
+	w.tx = nil
+}
+
+func (w *batchWriter) resetBatch() {
+	w.statements = 0
+	w.pendingNodes = 0
+	w.pendingRels = 0
+	w.pendingSkipped = 0
+	w.pendingEntities = 0
+	w.pendingBytes = 0
+}
+
+// paramBytes estimates the size of statement parameters sent to Neo4j
+func paramBytes(value any) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool:
+		return 1
+	case int, int64, float64, int32, float32, uint, uint64:
+		return 8
+	case map[string]any:
+		var size int64
+		for key, item := range v {
+			size += int64(len(key)) + paramBytes(item)
+		}
+		return size
+	case []any:
+		var size int64
+		for _, item := range v {
+			size += paramBytes(item)
+		}
+		return size
+	case []string:
+		var size int64
+		for _, item := range v {
+			size += int64(len(item))
+		}
+		return size
+	default:
+		return int64(len(fmt.Sprint(v)))
+	}
+}
+
+// writeTxTimeout is the transaction timeout of txTimeoutFromContext, capped at limit when
+// limit is set
+func writeTxTimeout(ctx context.Context, limit time.Duration) ([]func(*neo4j.TransactionConfig), error) {
+	configurers, err := txTimeoutFromContext(ctx)
+	if err != nil || limit <= 0 {
+		return configurers, err
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < limit {
+		return configurers, nil
+	}
+	return []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(limit)}, nil
+}
+
+// txTimeoutFromContext returns ctx's error once it is done, otherwise a transaction
+// timeout matching the remaining time until ctx's deadline, if any
+func txTimeoutFromContext(ctx context.Context) ([]func(*neo4j.TransactionConfig), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, nil
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	return []func(*neo4j.TransactionConfig){neo4j.WithTxTimeout(remaining)}, nil
+}
+
 func (r *Neo4jRepository) SearchNodes(criteria string) ([]*graph.GraphAggregate, error) {
 	session := r.driver.NewSession(neo4j.SessionConfig{})
 	defer func() {
@@ -159,20 +544,7 @@ func (r *Neo4jRepository) ExportGraph(query string) (any, error) {
 		processedNodes[node.Id] = true
 
 		// Add node to graph
-		nodeProps := make(map[string]any)
-		for key, value := range node.Props {
-			nodeProps[key] = value
-		}
-
-		// Set ID if not present in props
-		if _, hasID := nodeProps["id"]; !hasID {
-			nodeProps["id"] = node.Id
-		}
-
-		label := "Unknown"
-		if len(node.Labels) > 0 {
-			label = node.Labels[0]
-		}
+		label, nodeProps := exportedNode(node)
 
 		logrus.Debugf("Adding node to graph: ID=%d, Label=%s, Props=%+v", node.Id, label, nodeProps)
 		if err := graphAgg.AddNode(label, nodeProps); err != nil {
@@ -228,10 +600,237 @@ func (r *Neo4jRepository) ExportGraph(query string) (any, error) {
 	return graphAgg, nil
 }
 
+// QueryGraph runs a Cypher query and builds a graph from the nodes, relationships and paths
+// it returns, including those inside lists. Relationships whose endpoints are not returned
+// are skipped.
+func (r *Neo4jRepository) QueryGraph(query string, params map[string]any) (*graph.GraphAggregate, error) {
+	session := r.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer func() {
+		if err := session.Close(); err != nil {
+			log.Printf("Error closing session: %v", err)
+		}
+	}()
+
+	result, err := session.Run(query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run graph query: %w", err)
+	}
+
+	nodes := make(map[int64]neo4j.Node)
+	var nodeOrder []int64
+	relationships := make(map[int64]neo4j.Relationship)
+	var relationshipOrder []int64
+
+	var collect func(value any)
+	collect = func(value any) {
+		switch v := value.(type) {
+		case neo4j.Node:
+			if _, seen := nodes[v.Id]; !seen {
+				nodes[v.Id] = v
+				nodeOrder = append(nodeOrder, v.Id)
+			}
+		case neo4j.Relationship:
+			if _, seen := relationships[v.Id]; !seen {
+				relationships[v.Id] = v
+				relationshipOrder = append(relationshipOrder, v.Id)
+			}
+		case neo4j.Path:
+			for _, node := range v.Nodes {
+				collect(node)
+			}
+			for _, rel := range v.Relationships {
+				collect(rel)
+			}
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+
+	for result.Next() {
+		for _, value := range result.Record().Values {
+			collect(value)
+		}
+	}
+	if err = result.Err(); err != nil {
+		return nil, fmt.Errorf("error processing graph query: %w", err)
+	}
+
+	graphAgg := graph.NewGraphAggregate("")
+	nodeKeys := make(map[int64]any, len(nodes))
+	for _, id := range nodeOrder {
+		label, props := exportedNode(nodes[id])
+		nodeKeys[id] = props["id"]
+		if err := graphAgg.AddNode(label, props); err != nil {
+			logrus.Errorf("Error adding %s node: %v", label, err)
+		}
+	}
+
+	for _, id := range relationshipOrder {
+		rel := relationships[id]
+		sourceKey, hasSource := nodeKeys[rel.StartId]
+		targetKey, hasTarget := nodeKeys[rel.EndId]
+		if !hasSource || !hasTarget {
+			logrus.Debugf("Skipping relationship %s %d: endpoint not returned by query", rel.Type, rel.Id)
+			continue
+		}
+
+		relProps := make(map[string]any, len(rel.Props))
+		for key, value := range rel.Props {
+			relProps[key] = value
+		}
+		if err := graphAgg.AddDirectRelationship(rel.Type, sourceKey, targetKey, relProps); err != nil {
+			logrus.Warnf("Failed to add relationship %s: %v", rel.Type, err)
+		}
+	}
+
+	logrus.Infof("QueryGraph complete: %d nodes, %d relationships",
+		len(graphAgg.GetNodes()), len(graphAgg.GetRelationships()))
+
+	return graphAgg, nil
+}
+
+// CountGraph counts the stored nodes and relationships
+func (r *Neo4jRepository) CountGraph(ctx context.Context) (ports.GraphCounts, error) {
+	var counts ports.GraphCounts
+	records, err := r.readPage(ctx, `MATCH (n) RETURN count(n)`, nil)
+	if err != nil {
+		return counts, fmt.Errorf("failed to count nodes: %w", err)
+	}
+	counts.Nodes = records[0].Values[0].(int64)
+
+	records, err = r.readPage(ctx, `MATCH ()-[r]->() RETURN count(r)`, nil)
+	if err != nil {
+		return counts, fmt.Errorf("failed to count relationships: %w", err)
+	}
+	counts.Relationships = records[0].Values[0].(int64)
+	return counts, nil
+}
+
+// ReadNodePage returns up to limit nodes with an internal id above afterID, imported within
+// window
+func (r *Neo4jRepository) ReadNodePage(ctx context.Context, afterID int64, limit int, window ports.ImportWindow) ([]ports.ExportedNode, error) {
+	params := map[string]any{"after": afterID, "limit": limit}
+	records, err := r.readPage(ctx,
+		`MATCH (n) WHERE id(n) > $after`+windowCondition(window, params, "n")+` RETURN n ORDER BY id(n) LIMIT $limit`,
+		params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read node page: %w", err)
+	}
+
+	nodes := make([]ports.ExportedNode, 0, len(records))
+	for _, record := range records {
+		node := record.Values[0].(neo4j.Node)
+		label, props := exportedNode(node)
+		nodes = append(nodes, ports.ExportedNode{ID: node.Id, Label: label, Properties: props})
+	}
+	return nodes, nil
+}
+
+// ReadRelationshipPage returns up to limit relationships with an internal id above afterID,
+// imported within window together with both endpoints. Endpoints are identified the way
+// exportedNode identifies them.
+func (r *Neo4jRepository) ReadRelationshipPage(ctx context.Context, afterID int64, limit int, window ports.ImportWindow) ([]ports.ExportedRelationship, error) {
+	params := map[string]any{"after": afterID, "limit": limit}
+	records, err := r.readPage(ctx, `
+		MATCH (a)-[r]->(b) WHERE id(r) > $after`+windowCondition(window, params, "r", "a", "b")+`
+		RETURN r,
+			coalesce(labels(a)[0], 'Unknown'), coalesce(a.id, id(a)),
+			coalesce(labels(b)[0], 'Unknown'), coalesce(b.id, id(b))
+		ORDER BY id(r) LIMIT $limit`,
+		params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relationship page: %w", err)
+	}
+
+	rels := make([]ports.ExportedRelationship, 0, len(records))
+	for _, record := range records {
+		rel := record.Values[0].(neo4j.Relationship)
+		rels = append(rels, ports.ExportedRelationship{
+			ID:          rel.Id,
+			Type:        rel.Type,
+			SourceLabel: record.Values[1].(string),
+			SourceKey:   record.Values[2],
+			TargetLabel: record.Values[3].(string),
+			TargetKey:   record.Values[4],
+			Properties:  rel.Props,
+		})
+	}
+	return rels, nil
+}
+
+// windowCondition returns the WHERE conditions, starting with AND, that keep the elements
+// bound to variables imported within window, adding their parameters to params. Import
+// times are RFC3339 UTC strings, so they are compared as strings.
+func windowCondition(window ports.ImportWindow, params map[string]any, variables ...string) string {
+	var conditions []string
+	for _, bound := range []struct {
+		name, operator string
+		value          time.Time
+	}{{"from", ">=", window.From}, {"to", "<", window.To}} {
+		if bound.value.IsZero() {
+			continue
+		}
+		params[bound.name] = bound.value.UTC().Format(time.RFC3339)
+		for _, variable := range variables {
+			conditions = append(conditions, fmt.Sprintf("%s.%s %s $%s", variable, transform.ProvenanceImportedAt, bound.operator, bound.name))
+		}
+	}
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(conditions, " AND ")
+}
+
+// readPage runs a read query bounded by ctx and collects its records
+func (r *Neo4jRepository) readPage(ctx context.Context, query string, params map[string]any) ([]*neo4j.Record, error) {
+	txConfig, err := txTimeoutFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session := r.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer func() {
+		if err := session.Close(); err != nil {
+			log.Printf("Error closing session: %v", err)
+		}
+	}()
+
+	result, err := session.Run(query, params, txConfig...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Collect()
+}
+
+// exportedNode returns the label and properties a stored node is exported with; the
+// internal id stands in for a missing id property
+func exportedNode(node neo4j.Node) (string, map[string]any) {
+	props := make(map[string]any, len(node.Props)+1)
+	for key, value := range node.Props {
+		props[key] = value
+	}
+	if _, hasID := props["id"]; !hasID {
+		props["id"] = node.Id
+	}
+
+	label := "Unknown"
+	if len(node.Labels) > 0 {
+		label = node.Labels[0]
+	}
+	return label, props
+}
+
 func (r *Neo4jRepository) Close() error {
 	return r.driver.Close()
 }
 
+// VerifyConnectivity checks that the Neo4j server is reachable with the configured credentials
+func (r *Neo4jRepository) VerifyConnectivity() error {
+	return r.driver.VerifyConnectivity()
+}
+
 func (r *Neo4jRepository) NewSession(config neo4j.SessionConfig) neo4j.Session {
 	return r.driver.NewSession(config)
 }
@@ -0,0 +1,306 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/aggregates/graph"
+	"sql-graph-visualizer/internal/domain/entities"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeWriteQuery(t *testing.T) {
+	node := entities.NewNodeWithType("Person_1", "Person", "1", "id")
+	node.Properties = map[string]any{"id": "1", "name": "Ada"}
+
+	query, params := nodeWriteQuery(node)
+	assert.Equal(t, "CREATE (n:Person) SET n = $props", query)
+	assert.Equal(t, map[string]any{"props": node.Properties}, params)
+
+	line := entities.NewNodeWithType("OrderLine_7|1", "OrderLine", "7|1", "id")
+	line.Properties = map[string]any{"id": "7|1", "order_id": "7", "line`no": "1"}
+	line.MergeKeys = map[string]any{"order_id": "7", "line`no": "1"}
+
+	query, params = nodeWriteQuery(line)
+	assert.Equal(t, "MERGE (n:OrderLine {`line``no`: $key0, `order_id`: $key1}) SET n += $props", query)
+	assert.Equal(t, map[string]any{"props": line.Properties, "key0": "1", "key1": "7"}, params)
+
+	node.ExtraLabels = []string{"Customer"}
+	query, _ = nodeWriteQuery(node)
+	assert.Equal(t, "CREATE (n:Person:Customer) SET n = $props", query)
+
+	line.ExtraLabels = []string{"Item"}
+	query, _ = nodeWriteQuery(line)
+	assert.Equal(t, "MERGE (n:OrderLine:Item {`line``no`: $key0, `order_id`: $key1}) SET n += $props", query)
+}
+
+func TestRelationshipWriteQuery(t *testing.T) {
+	rel := graph.Relationship{Type: "WORKS_ON", Properties: map[string]any{"role": "lead", "since": 2020}}
+
+	query, params := relationshipWriteQuery(rel)
+	assert.Equal(t, "MATCH (a {id: $sourceId}), (b {id: $targetId}) CREATE (a)-[r:WORKS_ON]->(b) SET r = $props RETURN count(r)", query)
+	assert.Equal(t, map[string]any{"props": rel.Properties}, params)
+
+	rel.Merged = true
+	query, _ = relationshipWriteQuery(rel)
+	assert.Equal(t, "MATCH (a {id: $sourceId}), (b {id: $targetId}) MERGE (a)-[r:WORKS_ON]->(b) SET r += $props RETURN count(r)", query)
+
+	rel.MergeKeys = map[string]any{"role": "lead"}
+	query, params = relationshipWriteQuery(rel)
+	assert.Equal(t, "MATCH (a {id: $sourceId}), (b {id: $targetId}) MERGE (a)-[r:WORKS_ON {`role`: $key0}]->(b) SET r += $props RETURN count(r)", query)
+	assert.Equal(t, map[string]any{"props": rel.Properties, "key0": "lead"}, params)
+}
+
+func TestWindowCondition(t *testing.T) {
+	params := map[string]any{}
+	assert.Empty(t, windowCondition(ports.ImportWindow{}, params, "n"))
+	assert.Empty(t, params)
+
+	from := time.Date(2025, 3, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+	condition := windowCondition(ports.ImportWindow{From: from}, params, "r", "a", "b")
+	assert.Equal(t, " AND r._imported_at >= $from AND a._imported_at >= $from AND b._imported_at >= $from", condition)
+	assert.Equal(t, map[string]any{"from": "2025-03-01T00:00:00Z"}, params, "bounds are compared as UTC strings")
+
+	params = map[string]any{}
+	condition = windowCondition(ports.ImportWindow{From: from, To: from.Add(time.Hour)}, params, "n")
+	assert.Equal(t, " AND n._imported_at >= $from AND n._imported_at < $to", condition)
+	assert.Equal(t, "2025-03-01T01:00:00Z", params["to"])
+}
+
+func TestDeltaStatementsDeleteBeforeWriting(t *testing.T) {
+	delta := &ports.GraphDelta{
+		CreateNodes:         []ports.ExportedNode{{Label: "Student", Properties: map[string]any{"id": "3"}}},
+		UpdateNodes:         []ports.ExportedNode{{ID: 11, Label: "Student", Properties: map[string]any{"id": "1", "name": "Ada L."}}},
+		RemoveLabels:        map[int64][]string{11: {"Alumni"}},
+		DeleteNodes:         []ports.ExportedNode{{ID: 12, Label: "Student"}},
+		CreateRelationships: []ports.ExportedRelationship{{Type: "ENROLLED_IN", SourceLabel: "Student", SourceKey: "3", TargetLabel: "Course", TargetKey: "10"}},
+		DeleteRelationships: []ports.ExportedRelationship{{ID: 40, Type: "ENROLLED_IN"}},
+	}
+
+	statements := deltaStatements(delta)
+	queries := make([]string, len(statements))
+	for i, statement := range statements {
+		queries[i] = statement.query
+	}
+	assert.Equal(t, []string{
+		"MATCH ()-[r]->() WHERE id(r) = $id DELETE r",
+		"MATCH (n) WHERE id(n) = $id DETACH DELETE n",
+		"CREATE (n:Student) SET n = $props",
+		"MATCH (n) WHERE id(n) = $id SET n = $props, n:Student REMOVE n:Alumni",
+		"MATCH (a:Student {id: $sourceId}), (b:Course {id: $targetId}) CREATE (a)-[r:ENROLLED_IN]->(b) SET r = $props RETURN count(r)",
+	}, queries)
+	assert.Equal(t, map[string]any{"id": int64(12)}, statements[1].params)
+	assert.Equal(t, 1, statements[4].relationships)
+
+	delta = &ports.GraphDelta{
+		CreateNodes: []ports.ExportedNode{{Label: "Student", Labels: []string{"Student", "Person"}}},
+		UpdateNodes: []ports.ExportedNode{{ID: 11, Label: "Student", Labels: []string{"Student", "Person", "Alumni"}}},
+	}
+	statements = deltaStatements(delta)
+	assert.Equal(t, "CREATE (n:Student:Person) SET n = $props", statements[0].query)
+	assert.Equal(t, "MATCH (n) WHERE id(n) = $id SET n = $props, n:Student:Person:Alumni", statements[1].query)
+}
+
+// fakeDriver records the statements committed through its sessions; the embedded interfaces
+// leave the methods the writer does not use unimplemented. Relationship writes to or from an
+// id in missing match no endpoints.
+type fakeDriver struct {
+	neo4j.Driver
+	commits [][]string
+	missing map[any]bool
+}
+
+func (d *fakeDriver) NewSession(config neo4j.SessionConfig) neo4j.Session {
+	return &fakeSession{driver: d}
+}
+
+type fakeSession struct {
+	neo4j.Session
+	driver *fakeDriver
+}
+
+func (s *fakeSession) BeginTransaction(configurers ...func(*neo4j.TransactionConfig)) (neo4j.Transaction, error) {
+	return &fakeTransaction{driver: s.driver}, nil
+}
+
+func (s *fakeSession) Close() error { return nil }
+
+type fakeTransaction struct {
+	neo4j.Transaction
+	driver     *fakeDriver
+	statements []string
+}
+
+func (tx *fakeTransaction) Run(cypher string, params map[string]any) (neo4j.Result, error) {
+	tx.statements = append(tx.statements, cypher)
+	if tx.driver.missing[params["sourceId"]] || tx.driver.missing[params["targetId"]] {
+		return &createdResult{}, nil
+	}
+	return &createdResult{written: 1}, nil
+}
+
+func (tx *fakeTransaction) Commit() error {
+	tx.driver.commits = append(tx.driver.commits, tx.statements)
+	return nil
+}
+
+func (tx *fakeTransaction) Rollback() error { return nil }
+func (tx *fakeTransaction) Close() error    { return nil }
+
+// createdResult returns the written count of a relationship write once and reports it as
+// the relationships created
+type createdResult struct {
+	neo4j.Result
+	written int64
+	read    bool
+}
+
+func (r *createdResult) Next() bool {
+	next := !r.read
+	r.read = true
+	return next
+}
+
+func (r *createdResult) Record() *neo4j.Record { return &neo4j.Record{Values: []any{r.written}} }
+
+func (r *createdResult) Consume() (neo4j.ResultSummary, error) {
+	return createdSummary{created: int(r.written)}, nil
+}
+
+type createdSummary struct {
+	neo4j.ResultSummary
+	created int
+}
+
+func (s createdSummary) Counters() neo4j.Counters { return createdCounters{created: s.created} }
+
+type createdCounters struct {
+	neo4j.Counters
+	created int
+}
+
+func (c createdCounters) RelationshipsCreated() int { return c.created }
+
+func TestStoreGraphSplitsBatchesOverEntityBudget(t *testing.T) {
+	g := graph.NewGraphAggregate("")
+	for i := 1; i <= 7; i++ {
+		require.NoError(t, g.AddNode("Person", map[string]any{"id": fmt.Sprint(i), "name": fmt.Sprint("Person ", i)}))
+	}
+	for i := 2; i <= 4; i++ {
+		require.NoError(t, g.AddDirectRelationship("KNOWS", "1", fmt.Sprint(i), nil))
+	}
+
+	driver := &fakeDriver{}
+	repo := &Neo4jRepository{driver: driver}
+	repo.SetWriteBudget(WriteBudget{MaxEntities: 4, TxTimeout: time.Minute})
+
+	var progress []ports.GraphWriteProgress
+	require.NoError(t, repo.StoreGraphInBatches(context.Background(), g, func(p ports.GraphWriteProgress) {
+		progress = append(progress, p)
+	}))
+
+	require.Len(t, driver.commits, 3, "10 entities with a budget of 4 need three transactions")
+	written := 0
+	for _, statements := range driver.commits {
+		assert.LessOrEqual(t, len(statements), 4)
+		written += len(statements)
+	}
+	assert.Equal(t, 10, written)
+	last := progress[len(progress)-1]
+	assert.Equal(t, 7, last.NodesWritten)
+	assert.Equal(t, 3, last.RelationshipsWritten)
+	assert.Equal(t, 3, last.BatchesCommitted)
+}
+
+func TestStoreGraphCountsRelationshipsWithoutEndpoints(t *testing.T) {
+	g := graph.NewGraphAggregate("")
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, g.AddNode("Person", map[string]any{"id": fmt.Sprint(i)}))
+	}
+	require.NoError(t, g.AddDirectRelationship("KNOWS", "1", "2", nil))
+	require.NoError(t, g.AddDirectRelationship("KNOWS", "1", "3", nil))
+
+	driver := &fakeDriver{missing: map[any]bool{"3": true}}
+	repo := &Neo4jRepository{driver: driver}
+
+	var progress []ports.GraphWriteProgress
+	require.NoError(t, repo.StoreGraphInBatches(context.Background(), g, func(p ports.GraphWriteProgress) {
+		progress = append(progress, p)
+	}))
+
+	last := progress[len(progress)-1]
+	assert.Equal(t, 1, last.RelationshipsWritten)
+	assert.Equal(t, 1, last.RelationshipsSkipped, "MATCH finds no node 3, so nothing is written for it")
+}
+
+func TestApplyGraphDeltaCountsRelationshipsWithoutEndpoints(t *testing.T) {
+	driver := &fakeDriver{missing: map[any]bool{int64(9): true}}
+	repo := &Neo4jRepository{driver: driver}
+
+	var progress []ports.GraphWriteProgress
+	require.NoError(t, repo.ApplyGraphDelta(context.Background(), &ports.GraphDelta{
+		CreateRelationships: []ports.ExportedRelationship{
+			{Type: "ENROLLED_IN", SourceLabel: "Student", SourceKey: int64(1), TargetLabel: "Course", TargetKey: int64(2)},
+			{Type: "ENROLLED_IN", SourceLabel: "Student", SourceKey: int64(9), TargetLabel: "Course", TargetKey: int64(2)},
+		},
+	}, func(p ports.GraphWriteProgress) {
+		progress = append(progress, p)
+	}))
+
+	last := progress[len(progress)-1]
+	assert.Equal(t, 1, last.RelationshipsWritten)
+	assert.Equal(t, 1, last.RelationshipsSkipped)
+}
+
+func TestStoreGraphSplitsBatchesOverByteBudget(t *testing.T) {
+	g := graph.NewGraphAggregate("")
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, g.AddNode("Document", map[string]any{"id": fmt.Sprint(i), "name": strings.Repeat("x", 1000)}))
+	}
+
+	driver := &fakeDriver{}
+	repo := &Neo4jRepository{driver: driver}
+	repo.SetWriteBudget(WriteBudget{MaxBytes: 1500})
+	require.NoError(t, repo.StoreGraphInBatches(context.Background(), g, nil))
+
+	assert.Len(t, driver.commits, 3, "each 1000-byte node gets its own transaction")
+}
+
+func TestWriteTxTimeout(t *testing.T) {
+	configurers, err := writeTxTimeout(context.Background(), time.Minute)
+	require.NoError(t, err)
+	config := &neo4j.TransactionConfig{}
+	for _, configure := range configurers {
+		configure(config)
+	}
+	assert.Equal(t, time.Minute, config.Timeout)
+
+	// A closer context deadline wins over the configured timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	configurers, err = writeTxTimeout(ctx, time.Minute)
+	require.NoError(t, err)
+	config = &neo4j.TransactionConfig{}
+	for _, configure := range configurers {
+		configure(config)
+	}
+	assert.LessOrEqual(t, config.Timeout, time.Second)
+}
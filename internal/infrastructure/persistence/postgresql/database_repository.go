@@ -15,6 +15,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"sql-graph-visualizer/internal/domain/models"
 	"sql-graph-visualizer/internal/domain/repository"
 	"strings"
@@ -44,47 +45,11 @@ func (r *PostgreSQLDatabaseRepository) Connect(ctx context.Context, config model
 	// Use Username if set, otherwise fallback to User
 	username := pgConfig.GetUsername()
 
-	// Build PostgreSQL connection string
-	var connString strings.Builder
-	connString.WriteString(fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-		pgConfig.GetHost(), pgConfig.GetPort(), username, pgConfig.GetPassword(), pgConfig.GetDatabase()))
-
-	// Add SSL configuration
-	if pgConfig.SSLConfig.Mode != "" {
-		connString.WriteString(fmt.Sprintf(" sslmode=%s", pgConfig.SSLConfig.Mode))
-	} else {
-		connString.WriteString(" sslmode=prefer") // Default to prefer
-	}
-
-	if pgConfig.SSLConfig.CertFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslcert=%s", pgConfig.SSLConfig.CertFile))
-	}
-	if pgConfig.SSLConfig.KeyFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslkey=%s", pgConfig.SSLConfig.KeyFile))
-	}
-	if pgConfig.SSLConfig.CAFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslrootcert=%s", pgConfig.SSLConfig.CAFile))
-	}
-
-	// Add timeout configurations
 	security := pgConfig.GetSecurity()
-	if security.ConnectionTimeout > 0 {
-		connString.WriteString(fmt.Sprintf(" connect_timeout=%d", security.ConnectionTimeout))
-	}
-	if pgConfig.StatementTimeout > 0 {
-		connString.WriteString(fmt.Sprintf(" statement_timeout=%dms", pgConfig.StatementTimeout*1000))
-	}
-
-	// Set application name for .monitoring
-	appName := pgConfig.ApplicationName
-	if appName == "" {
-		appName = "sql-graph-visualizer"
-	}
-	connString.WriteString(fmt.Sprintf(" application_name=%s", appName))
 
 	logrus.Infof("Connecting to PostgreSQL database: %s@%s:%d/%s", username, pgConfig.GetHost(), pgConfig.GetPort(), pgConfig.GetDatabase())
 
-	db, err := sql.Open("postgres", connString.String())
+	db, err := sql.Open("postgres", BuildDSN(pgConfig))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PostgreSQL database connection: %w", err)
 	}
@@ -131,7 +96,7 @@ func (r *PostgreSQLDatabaseRepository) GetTables(ctx context.Context, filters mo
 
 	// Get tables from current schema (typically 'public' if not specified)
 	query := `
-		SELECT table_name 
+		SELECT table_schema, table_name 
 		FROM information_schema.tables 
 		WHERE table_schema = current_schema() 
 			AND table_type = 'BASE TABLE'
@@ -146,10 +111,13 @@ func (r *PostgreSQLDatabaseRepository) GetTables(ctx context.Context, filters mo
 
 	var allTables []string
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var schema, tableName string
+		if err := rows.Scan(&schema, &tableName); err != nil {
 			return nil, err
 		}
+		if filters.IsSystemSchema(models.DatabaseTypePostgreSQL, schema) {
+			continue
+		}
 		allTables = append(allTables, tableName)
 	}
 
@@ -311,7 +279,8 @@ func (r *PostgreSQLDatabaseRepository) GetIndexes(ctx context.Context, tableName
 		JOIN pg_am am ON i.relam = am.oid
 		WHERE t.relname = $1
 			AND t.relkind = 'r'
-		ORDER BY i.relname, a.attnum
+			AND t.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())
+		ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, tableName)
@@ -346,8 +315,9 @@ func (r *PostgreSQLDatabaseRepository) GetIndexes(ctx context.Context, tableName
 	for _, idx := range indexMap {
 		indexes = append(indexes, *idx)
 	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
 
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
 // GetConstraints retrieves constraint information for a PostgreSQL table
@@ -432,8 +402,7 @@ func (r *PostgreSQLDatabaseRepository) GetSchemaNames(ctx context.Context) ([]st
 	query := `
 		SELECT schema_name 
 		FROM information_schema.schemata
-		WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast')
-			AND schema_name NOT LIKE 'pg_temp_%'
+		WHERE schema_name NOT LIKE 'pg_temp_%'
 			AND schema_name NOT LIKE 'pg_toast_temp_%'
 		ORDER BY schema_name
 	`
@@ -450,6 +419,9 @@ func (r *PostgreSQLDatabaseRepository) GetSchemaNames(ctx context.Context) ([]st
 		if err := rows.Scan(&schema); err != nil {
 			return nil, err
 		}
+		if models.IsSystemSchema(schema, models.DefaultSystemSchemas(models.DatabaseTypePostgreSQL)) {
+			continue
+		}
 		schemas = append(schemas, schema)
 	}
 
@@ -493,12 +465,116 @@ func (r *PostgreSQLDatabaseRepository) SampleTableData(ctx context.Context, tabl
 	return nil, fmt.Errorf("not implemented yet")
 }
 
+// SampleColumnValues returns up to limit distinct non-NULL values of a column
+func (r *PostgreSQLDatabaseRepository) SampleColumnValues(ctx context.Context, tableName, columnName string, limit int) ([]interface{}, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no active database connection")
+	}
+
+	column := r.EscapeIdentifier(columnName)
+	// #nosec G201 - identifiers are escaped using EscapeIdentifier
+	query := fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL LIMIT %d",
+		column, r.EscapeIdentifier(tableName), column, limit)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample column values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []interface{}
+	for rows.Next() {
+		var value interface{}
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan column value: %w", err)
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// CountMatchingValues returns how many of the given values exist in a column
+func (r *PostgreSQLDatabaseRepository) CountMatchingValues(ctx context.Context, tableName, columnName string, values []interface{}) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no active database connection")
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	column := r.EscapeIdentifier(columnName)
+	// #nosec G201 - identifiers are escaped using EscapeIdentifier, values are bound
+	query := fmt.Sprintf("SELECT COUNT(DISTINCT %s) FROM %s WHERE %s::text IN (%s)",
+		column, r.EscapeIdentifier(tableName), column, strings.Join(placeholders, ","))
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = fmt.Sprintf("%v", value)
+	}
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count matching values in %s.%s: %w", tableName, columnName, err)
+	}
+	return count, nil
+}
+
+// SampleRowCount counts the rows of a TABLESAMPLE SYSTEM sample of percent of the table's
+// pages, reading at most limit rows
+func (r *PostgreSQLDatabaseRepository) SampleRowCount(ctx context.Context, tableName string, percent float64, limit int) (int64, float64, error) {
+	if r.db == nil {
+		return 0, 0, fmt.Errorf("no active database connection")
+	}
+
+	// #nosec G201 - tableName is escaped using EscapeIdentifier, percent and limit are numbers
+	query := fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s TABLESAMPLE SYSTEM (%g) LIMIT %d) AS sample",
+		r.EscapeIdentifier(tableName), percent, limit)
+
+	var rows int64
+	if err := r.db.QueryRowContext(ctx, query).Scan(&rows); err != nil {
+		return 0, 0, fmt.Errorf("failed to sample rows of %s: %w", tableName, err)
+	}
+	return rows, percent / 100, nil
+}
+
 func (r *PostgreSQLDatabaseRepository) AnalyzeColumnStatistics(ctx context.Context, tableName, columnName string) (*models.ColumnStatistics, error) {
 	return nil, fmt.Errorf("not implemented yet")
 }
 
+// GetTableSize reads a table's row estimate and storage size from pg_class, without touching
+// the table itself. reltuples is -1 until the table is first analyzed; the live tuple count
+// from the statistics collector is used then.
 func (r *PostgreSQLDatabaseRepository) GetTableSize(ctx context.Context, tableName string) (*models.TableSize, error) {
-	return nil, fmt.Errorf("not implemented yet")
+	if r.db == nil {
+		return nil, fmt.Errorf("no active database connection")
+	}
+
+	query := `
+		SELECT
+			CASE WHEN c.reltuples >= 0 THEN c.reltuples::bigint ELSE COALESCE(s.n_live_tup, 0) END,
+			pg_relation_size(c.oid),
+			pg_indexes_size(c.oid)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_stat_user_tables s ON s.relid = c.oid
+		WHERE n.nspname = current_schema()
+			AND c.relname = $1
+			AND c.relkind IN ('r', 'p')
+	`
+
+	var rows, dataSize, indexSize int64
+	if err := r.db.QueryRowContext(ctx, query, tableName).Scan(&rows, &dataSize, &indexSize); err != nil {
+		return nil, fmt.Errorf("failed to read catalog size of %s: %w", tableName, err)
+	}
+	return models.NewTableSize(tableName, rows, dataSize, indexSize), nil
 }
 
 func (r *PostgreSQLDatabaseRepository) GetQueryExecutionPlan(ctx context.Context, query string) (string, error) {
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogDriver answers every query with one fixed row and records the SQL it was given
+type catalogDriver struct {
+	mu      sync.Mutex
+	queries []string
+	row     []driver.Value
+}
+
+type catalogConn struct{ driver *catalogDriver }
+
+type catalogRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (d *catalogDriver) Open(name string) (driver.Conn, error) { return catalogConn{d}, nil }
+
+func (c catalogConn) Prepare(query string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c catalogConn) Close() error                              { return nil }
+func (c catalogConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+func (c catalogConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	c.driver.queries = append(c.driver.queries, query)
+	return &catalogRows{row: c.driver.row}, nil
+}
+
+func (r *catalogRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = "c"
+	}
+	return columns
+}
+func (r *catalogRows) Close() error { return nil }
+func (r *catalogRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	copy(dest, r.row)
+	r.done = true
+	return nil
+}
+
+var registerCatalogDriver sync.Once
+var catalog = &catalogDriver{}
+
+func TestGetTableSizeReadsCatalogStatistics(t *testing.T) {
+	registerCatalogDriver.Do(func() { sql.Register("postgresql-catalog-stub", catalog) })
+	catalog.row = []driver.Value{int64(125000), int64(48 << 20), int64(16 << 20)}
+
+	db, err := sql.Open("postgresql-catalog-stub", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &PostgreSQLDatabaseRepository{db: db}
+	size, err := repo.GetTableSize(context.Background(), "orders")
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders", size.TableName)
+	assert.EqualValues(t, 125000, size.RowCount)
+	assert.InDelta(t, 48.0, size.DataSizeMB, 1e-9)
+	assert.InDelta(t, 64.0, size.TotalSizeMB, 1e-9)
+
+	require.Len(t, catalog.queries, 1)
+	query := strings.ToLower(catalog.queries[0])
+	assert.Contains(t, query, "reltuples")
+	assert.Contains(t, query, "from pg_class")
+	assert.NotContains(t, query, "count(", "the estimate must not scan the table")
+}
+
+func TestSampleRowCountRunsBoundedSample(t *testing.T) {
+	registerCatalogDriver.Do(func() { sql.Register("postgresql-catalog-stub", catalog) })
+	catalog.queries = nil
+	catalog.row = []driver.Value{int64(412)}
+
+	db, err := sql.Open("postgresql-catalog-stub", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &PostgreSQLDatabaseRepository{db: db}
+	rows, fraction, err := repo.SampleRowCount(context.Background(), "orders", 2.5, 10000)
+	require.NoError(t, err)
+	assert.EqualValues(t, 412, rows)
+	assert.InDelta(t, 0.025, fraction, 1e-12)
+
+	require.Len(t, catalog.queries, 1)
+	query := strings.ToLower(catalog.queries[0])
+	assert.Contains(t, query, "tablesample system (2.5)")
+	assert.Contains(t, query, "limit 10000")
+}
+
+func TestGetTablesExcludesSystemSchemas(t *testing.T) {
+	registerCatalogDriver.Do(func() { sql.Register("postgresql-catalog-stub", catalog) })
+	catalog.queries = nil
+
+	db, err := sql.Open("postgresql-catalog-stub", "")
+	require.NoError(t, err)
+	defer db.Close()
+	repo := &PostgreSQLDatabaseRepository{db: db}
+
+	catalog.row = []driver.Value{"pg_catalog", "pg_class"}
+	tables, err := repo.GetTables(context.Background(), models.DataFilteringConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, tables, "system schemas are excluded by default")
+
+	tables, err = repo.GetTables(context.Background(), models.DataFilteringConfig{IncludeSystemTables: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pg_class"}, tables)
+
+	catalog.row = []driver.Value{"public", "orders"}
+	tables, err = repo.GetTables(context.Background(), models.DataFilteringConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, tables)
+}
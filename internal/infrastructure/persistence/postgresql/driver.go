@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+	"sql-graph-visualizer/internal/infrastructure/persistence/registry"
+)
+
+func init() {
+	registry.Register(registry.Driver{
+		Type:            models.DatabaseTypePostgreSQL,
+		Open:            openDatabase,
+		NewDatabasePort: NewPostgreSQLDatabasePort,
+		NewRepository:   NewPostgreSQLDatabaseRepository,
+		Capabilities: repository.DatabaseCapabilities{
+			ForeignKeyDiscovery:   true,
+			IndexDiscovery:        true,
+			SchemaNamespaces:      true,
+			DataSampling:          true,
+			WriteSupport:          false,
+			QueryExplain:          true,
+			ArrayColumns:          true,
+			JSONColumns:           true,
+			PerformanceCollection: true,
+			PerformanceSource:     "pg_stat_statements",
+			Benchmarking:          true,
+		},
+	})
+}
+
+// openDatabase connects to an existing PostgreSQL database
+func openDatabase(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error) {
+	pgConfig, ok := config.(*models.PostgreSQLConfig)
+	if !ok {
+		return nil, fmt.Errorf("expected PostgreSQL configuration, got %T", config)
+	}
+	return (&PostgreSQLRepository{}).ConnectToExisting(ctx, pgConfig)
+}
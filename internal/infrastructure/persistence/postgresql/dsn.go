@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package postgresql
+
+import (
+	"strconv"
+	"strings"
+
+	"sql-graph-visualizer/internal/domain/models"
+)
+
+// BuildDSN builds a lib/pq keyword/value connection string for config with values quoted
+// and escaped where needed
+func BuildDSN(c *models.PostgreSQLConfig) string {
+	port := c.Port
+	if port == 0 {
+		port = models.DefaultPostgreSQLPort
+	}
+
+	sslMode := c.SSLConfig.Mode
+	if sslMode == "" {
+		sslMode = "prefer"
+	}
+
+	appName := c.ApplicationName
+	if appName == "" {
+		appName = "sql-graph-visualizer"
+	}
+
+	params := [][2]string{
+		{"host", c.Host},
+		{"port", strconv.Itoa(port)},
+		{"user", c.GetUsername()},
+		{"password", c.Password},
+		{"dbname", c.Database},
+		{"sslmode", sslMode},
+		{"sslcert", c.SSLConfig.CertFile},
+		{"sslkey", c.SSLConfig.KeyFile},
+		{"sslrootcert", c.SSLConfig.CAFile},
+	}
+	if c.Security.ConnectionTimeout > 0 {
+		params = append(params, [2]string{"connect_timeout", strconv.Itoa(c.Security.ConnectionTimeout)})
+	}
+	if c.StatementTimeout > 0 {
+		params = append(params, [2]string{"statement_timeout", strconv.Itoa(c.StatementTimeout*1000) + "ms"})
+	}
+	params = append(params, [2]string{"application_name", appName})
+
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		// Optional settings are omitted rather than sent empty, except the password
+		if param[1] == "" && param[0] != "password" {
+			continue
+		}
+		parts = append(parts, param[0]+"="+quotePostgreSQLValue(param[1]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quotePostgreSQLValue quotes a keyword/value connection parameter when it is empty
+// or contains whitespace, quotes or backslashes
+func quotePostgreSQLValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r'\\") {
+		return value
+	}
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `'`, `\'`)
+	return "'" + escaped + "'"
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		expected string
+	}{
+		{name: "plain password", password: "secret", expected: "password=secret "},
+		{name: "at sign and colon", password: "p@ss:word", expected: "password=p@ss:word "},
+		{name: "space", password: "two words", expected: "password='two words' "},
+		{name: "quote and backslash", password: `it's\here`, expected: `password='it\'s\\here' `},
+		{name: "empty", password: "", expected: "password='' "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.PostgreSQLConfig{
+				Host:     "pg.internal",
+				Username: "graph",
+				Password: tt.password,
+				Database: "shop",
+			}
+
+			dsn := BuildDSN(config)
+			assert.Contains(t, dsn, tt.expected)
+			assert.True(t, strings.HasPrefix(dsn, "host=pg.internal port=5432 user=graph "))
+
+			// The driver must accept the connection string without connecting
+			_, err := pq.NewConnector(dsn)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBuildDSNOptions(t *testing.T) {
+	config := &models.PostgreSQLConfig{
+		Host:             "pg.internal",
+		Port:             6432,
+		User:             "graph",
+		Password:         "secret",
+		Database:         "shop",
+		SSLConfig:        models.PostgreSQLSSLConfig{Mode: "verify-full", CAFile: "/etc/ssl/ca.pem"},
+		ApplicationName:  "graph loader",
+		StatementTimeout: 15,
+		Security:         models.SecurityConfig{ConnectionTimeout: 10},
+	}
+
+	dsn := BuildDSN(config)
+	assert.Contains(t, dsn, "port=6432")
+	assert.Contains(t, dsn, "sslmode=verify-full")
+	assert.Contains(t, dsn, "sslrootcert=/etc/ssl/ca.pem")
+	assert.Contains(t, dsn, "connect_timeout=10")
+	assert.Contains(t, dsn, "statement_timeout=15000ms")
+	assert.Contains(t, dsn, "application_name='graph loader'")
+	assert.NotContains(t, dsn, "sslcert=")
+}
@@ -98,46 +98,9 @@ func (r *PostgreSQLRepository) ConnectToExisting(ctx context.Context, config *mo
 		username = config.User
 	}
 
-	// Build PostgreSQL connection string
-	var connString strings.Builder
-	connString.WriteString(fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-		config.Host, config.Port, username, config.Password, config.Database))
-
-	// Add SSL configuration
-	if config.SSLConfig.Mode != "" {
-		connString.WriteString(fmt.Sprintf(" sslmode=%s", config.SSLConfig.Mode))
-	} else {
-		connString.WriteString(" sslmode=prefer") // Default to prefer
-	}
-
-	if config.SSLConfig.CertFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslcert=%s", config.SSLConfig.CertFile))
-	}
-	if config.SSLConfig.KeyFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslkey=%s", config.SSLConfig.KeyFile))
-	}
-	if config.SSLConfig.CAFile != "" {
-		connString.WriteString(fmt.Sprintf(" sslrootcert=%s", config.SSLConfig.CAFile))
-	}
-
-	// Add timeout configurations
-	if config.Security.ConnectionTimeout > 0 {
-		connString.WriteString(fmt.Sprintf(" connect_timeout=%d", config.Security.ConnectionTimeout))
-	}
-	if config.StatementTimeout > 0 {
-		connString.WriteString(fmt.Sprintf(" statement_timeout=%dms", config.StatementTimeout*1000))
-	}
-
-	// Set application name for .monitoring
-	appName := config.ApplicationName
-	if appName == "" {
-		appName = "sql-graph-visualizer"
-	}
-	connString.WriteString(fmt.Sprintf(" application_name=%s", appName))
-
 	logrus.Infof("Connecting to PostgreSQL database: %s@%s:%d/%s", username, config.Host, config.Port, config.Database)
 
-	db, err := sql.Open("postgres", connString.String())
+	db, err := sql.Open("postgres", BuildDSN(config))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
@@ -152,6 +115,7 @@ func (r *PostgreSQLRepository) ConnectToExisting(ctx context.Context, config *mo
 	defer cancel()
 
 	if err := db.PingContext(ctxTimeout); err != nil {
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -299,7 +263,7 @@ func (r *PostgreSQLRepository) DiscoverSchema(ctx context.Context, db *sql.DB, f
 func (r *PostgreSQLRepository) GetTables(ctx context.Context, db *sql.DB, filters *models.DataFilteringConfig) ([]string, error) {
 	// Get tables from current schema (typically 'public' if not specified)
 	query := `
-		SELECT table_name 
+		SELECT table_schema, table_name 
 		FROM information_schema.tables 
 		WHERE table_schema = current_schema() 
 			AND table_type = 'BASE TABLE'
@@ -312,12 +276,19 @@ func (r *PostgreSQLRepository) GetTables(ctx context.Context, db *sql.DB, filter
 	}
 	defer rows.Close()
 
+	var systemFilters models.DataFilteringConfig
+	if filters != nil {
+		systemFilters = *filters
+	}
 	var allTables []string
 	for rows.Next() {
-		var tableName string
-		if err := rows.Scan(&tableName); err != nil {
+		var schema, tableName string
+		if err := rows.Scan(&schema, &tableName); err != nil {
 			return nil, err
 		}
+		if systemFilters.IsSystemSchema(models.DatabaseTypePostgreSQL, schema) {
+			continue
+		}
 		allTables = append(allTables, tableName)
 	}
 
@@ -509,10 +480,54 @@ func (r *PostgreSQLRepository) ExtractTableData(ctx context.Context, db *sql.DB,
 
 // ExecuteQueryWithContext executes PostgreSQL query with context timeout
 func (r *PostgreSQLRepository) ExecuteQueryWithContext(ctx context.Context, query string) ([]map[string]any, error) {
-	rows, err := r.db.QueryContext(ctx, query)
+	var results []map[string]any
+	err := r.StreamQuery(ctx, query, func(row map[string]any) error {
+		results = append(results, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// StreamQuery executes a PostgreSQL query and passes each row to fn as it is read, so large
+// results are never held in memory at once
+func (r *PostgreSQLRepository) StreamQuery(ctx context.Context, query string, fn func(row map[string]any) error) error {
+	return r.streamRows(ctx, fn, query)
+}
+
+// QueryPlaceholder implements ports.ParameterizedQueryExecutor; PostgreSQL numbers its
+// arguments ($1, $2, ...)
+func (r *PostgreSQLRepository) QueryPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// ExecuteQueryWithArgs executes query with its arguments passed separately to the driver
+func (r *PostgreSQLRepository) ExecuteQueryWithArgs(ctx context.Context, query string, args []any) ([]map[string]any, error) {
+	var results []map[string]any
+	err := r.streamRows(ctx, func(row map[string]any) error {
+		results = append(results, row)
+		return nil
+	}, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	return results, nil
+}
+
+// StreamQueryWithArgs executes query with its arguments passed separately to the driver and
+// passes each row to fn as it is read
+func (r *PostgreSQLRepository) StreamQueryWithArgs(ctx context.Context, query string, args []any, fn func(row map[string]any) error) error {
+	return r.streamRows(ctx, fn, query, args...)
+}
+
+// streamRows executes query with args and passes each row to fn as it is read
+func (r *PostgreSQLRepository) streamRows(ctx context.Context, fn func(row map[string]any) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if err := rows.Close(); err != nil {
 			log.Printf("Error closing rows: %v", err)
@@ -521,10 +536,9 @@ func (r *PostgreSQLRepository) ExecuteQueryWithContext(ctx context.Context, quer
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var results []map[string]any
 	for rows.Next() {
 		row := make(map[string]any)
 		columnPointers := make([]any, len(columns))
@@ -533,17 +547,19 @@ func (r *PostgreSQLRepository) ExecuteQueryWithContext(ctx context.Context, quer
 		}
 
 		if err := rows.Scan(columnPointers...); err != nil {
-			return nil, err
+			return err
 		}
 
 		for i, colName := range columns {
 			row[colName] = *(columnPointers[i].(*any))
 		}
 
-		results = append(results, row)
+		if err := fn(row); err != nil {
+			return err
+		}
 	}
 
-	return results, nil
+	return rows.Err()
 }
 
 // EstimateDataSize provides PostgreSQL dataset size estimation
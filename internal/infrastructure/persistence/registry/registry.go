@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+// Package registry holds the supported source databases. Each persistence package registers
+// a Driver for its database type from init, so adding a database needs no central switch.
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+)
+
+// Driver describes how to connect to one database type and build its repositories
+type Driver struct {
+	Type models.DatabaseType
+	// Open connects with the given configuration and verifies the connection
+	Open func(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error)
+	// NewDatabasePort wraps an open connection as the transform source
+	NewDatabasePort func(db *sql.DB) ports.DatabasePort
+	// NewRepository creates the schema analysis repository
+	NewRepository func() repository.DatabaseRepository
+	// Capabilities is the feature matrix; Type and Supported are filled in on registration
+	Capabilities repository.DatabaseCapabilities
+}
+
+var (
+	mu      sync.RWMutex
+	drivers = make(map[models.DatabaseType]Driver)
+)
+
+// Register makes a database type available. Like database/sql.Register it panics when the
+// driver is incomplete or its type is already registered.
+func Register(driver Driver) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if driver.Type == "" || driver.Open == nil || driver.NewDatabasePort == nil || driver.NewRepository == nil {
+		panic(fmt.Sprintf("registry: incomplete driver for database type %q", driver.Type))
+	}
+	if _, exists := drivers[driver.Type]; exists {
+		panic(fmt.Sprintf("registry: database type %q registered twice", driver.Type))
+	}
+
+	driver.Capabilities.DatabaseType = driver.Type
+	driver.Capabilities.Supported = true
+	drivers[driver.Type] = driver
+}
+
+// Lookup returns the driver registered for dbType
+func Lookup(dbType models.DatabaseType) (Driver, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	driver, ok := drivers[dbType]
+	if !ok {
+		return Driver{}, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+	return driver, nil
+}
+
+// Types returns the registered database types in name order
+func Types() []models.DatabaseType {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	types := make([]models.DatabaseType, 0, len(drivers))
+	for dbType := range drivers {
+		types = append(types, dbType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
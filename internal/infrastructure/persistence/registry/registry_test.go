@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerForTest registers a driver and removes it when the test ends
+func registerForTest(t *testing.T, driver Driver) {
+	Register(driver)
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(drivers, driver.Type)
+		mu.Unlock()
+	})
+}
+
+func testDriver(dbType models.DatabaseType) Driver {
+	return Driver{
+		Type:            dbType,
+		Open:            func(ctx context.Context, config models.DatabaseConfig) (*sql.DB, error) { return nil, nil },
+		NewDatabasePort: func(db *sql.DB) ports.DatabasePort { return nil },
+		NewRepository:   func() repository.DatabaseRepository { return nil },
+	}
+}
+
+func TestRegisterAndLookup(t *testing.T) {
+	registerForTest(t, testDriver("registry_test_b"))
+	registerForTest(t, testDriver("registry_test_a"))
+
+	driver, err := Lookup("registry_test_a")
+	require.NoError(t, err)
+	assert.True(t, driver.Capabilities.Supported)
+	assert.Equal(t, models.DatabaseType("registry_test_a"), driver.Capabilities.DatabaseType)
+
+	types := Types()
+	assert.Equal(t, []models.DatabaseType{"registry_test_a", "registry_test_b"}, types)
+
+	_, err = Lookup("registry_test_missing")
+	assert.ErrorContains(t, err, "unsupported database type")
+}
+
+func TestRegisterRejectsDuplicatesAndIncompleteDrivers(t *testing.T) {
+	registerForTest(t, testDriver("registry_test_dup"))
+	assert.Panics(t, func() { Register(testDriver("registry_test_dup")) })
+
+	incomplete := testDriver("registry_test_incomplete")
+	incomplete.Open = nil
+	assert.Panics(t, func() { Register(incomplete) })
+}
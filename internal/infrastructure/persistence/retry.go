@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ConnectRetryPolicy controls how long startup waits for a dependency to accept connections
+type ConnectRetryPolicy struct {
+	// MaxWait bounds the total time spent retrying; zero tries once
+	MaxWait time.Duration
+	// InitialBackoff is the delay after the first failure; it doubles up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultConnectRetryPolicy waits up to two minutes, backing off from one to fifteen seconds
+func DefaultConnectRetryPolicy() ConnectRetryPolicy {
+	return ConnectRetryPolicy{
+		MaxWait:        2 * time.Minute,
+		InitialBackoff: time.Second,
+		MaxBackoff:     15 * time.Second,
+	}
+}
+
+// ConnectWithRetry calls connect until it succeeds, sleeping with exponential backoff between
+// attempts. It gives up with the last error once the next attempt would start after MaxWait,
+// or when ctx ends.
+func ConnectWithRetry(ctx context.Context, name string, policy ConnectRetryPolicy, connect func(ctx context.Context) error) error {
+	deadline := time.Now().Add(policy.MaxWait)
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logrus.Infof("Connected to %s after %d attempts", name, attempt)
+			}
+			return nil
+		}
+
+		wait := backoff
+		if remaining := time.Until(deadline); remaining <= 0 {
+			return fmt.Errorf("%s not reachable after %d attempts: %w", name, attempt, err)
+		} else if wait > remaining {
+			wait = remaining
+		}
+
+		logrus.Warnf("Connecting to %s failed (attempt %d), retrying in %s: %v", name, attempt, wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("%s not reachable: %w (last error: %v)", name, ctx.Err(), err)
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2025 Petr Miroslav Stepanek <petrstepanek99@gmail.com>
+ *
+ * This source code is licensed under a Dual License:
+ * - AGPL-3.0 for open source use (see LICENSE file)
+ * - Commercial License for business use (contact: petrstepanek99@gmail.com)
+ *
+ * This software contains patent-pending innovations in database analysis
+ * and graph visualization. Commercial use requires separate licensing.
+ */
+
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotReady = errors.New("connection refused")
+
+// flakyDependency fails a fixed number of times before accepting connections
+func flakyDependency(failures int) (func(ctx context.Context) error, *int) {
+	attempts := 0
+	return func(ctx context.Context) error {
+		attempts++
+		if attempts <= failures {
+			return errNotReady
+		}
+		return nil
+	}, &attempts
+}
+
+func TestConnectWithRetry_SucceedsAfterTwoFailures(t *testing.T) {
+	connect, attempts := flakyDependency(2)
+	policy := ConnectRetryPolicy{MaxWait: time.Second, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+
+	start := time.Now()
+	require.NoError(t, ConnectWithRetry(context.Background(), "neo4j", policy, connect))
+
+	assert.Equal(t, 3, *attempts)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "backs off 10ms then 20ms")
+	assert.Less(t, time.Since(start), policy.MaxWait)
+}
+
+func TestConnectWithRetry_GivesUpAfterMaxWait(t *testing.T) {
+	connect, attempts := flakyDependency(1000)
+	policy := ConnectRetryPolicy{MaxWait: 50 * time.Millisecond, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	start := time.Now()
+	err := ConnectWithRetry(context.Background(), "mysql", policy, connect)
+
+	require.ErrorIs(t, err, errNotReady)
+	assert.Contains(t, err.Error(), "mysql not reachable")
+	assert.Greater(t, *attempts, 1)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestConnectWithRetry_ZeroMaxWaitTriesOnce(t *testing.T) {
+	connect, attempts := flakyDependency(1)
+	err := ConnectWithRetry(context.Background(), "mysql", ConnectRetryPolicy{}, connect)
+
+	require.ErrorIs(t, err, errNotReady)
+	assert.Equal(t, 1, *attempts)
+}
+
+func TestConnectWithRetry_StopsWhenContextEnds(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	connect, _ := flakyDependency(1000)
+	policy := ConnectRetryPolicy{MaxWait: time.Minute, InitialBackoff: time.Minute}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err := ConnectWithRetry(ctx, "neo4j", policy, connect)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// API scopes granted by tokens
+const (
+	ScopeRead  = "read"
+	ScopeAdmin = "admin"
+)
+
+// APIAuthConfig lists the bearer tokens accepted by the API, as configured under api.auth.
+// Read tokens may call safe (GET, HEAD, OPTIONS) endpoints; admin tokens may call every
+// endpoint. Auth is disabled when no tokens are configured.
+type APIAuthConfig = models.APIAuthConfig
+
+// DefaultPublicPaths keeps the health probes reachable without a token
+var DefaultPublicPaths = []string{"/api/health", "/api/health/"}
+
+// DefaultAdminPaths keeps the configuration, which names hosts and users even with its
+// secrets redacted, from read tokens
+var DefaultAdminPaths = []string{ConfigPath}
+
+// NewScopeMiddleware rejects requests without a valid token with 401 and requests whose
+// token lacks the scope the endpoint needs with 403. Mutating methods need the admin scope.
+func NewScopeMiddleware(logger *logrus.Logger, config APIAuthConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !config.Enabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchesPath(config.PublicPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scope := tokenScope(config, r)
+			if scope == "" {
+				sendScopeError(w, logger, r, http.StatusUnauthorized, "UNAUTHORIZED", "A valid API token is required")
+				return
+			}
+			if required := requiredScope(config, r); required == ScopeAdmin && scope != ScopeAdmin {
+				sendScopeError(w, logger, r, http.StatusForbidden, "INSUFFICIENT_SCOPE", "This endpoint requires an admin token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tokenScope returns the scope of the request's bearer token, or "" when it is not known
+func tokenScope(config APIAuthConfig, r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	token := []byte(strings.TrimPrefix(header, "Bearer "))
+
+	// Every token is compared so the response time does not reveal which list matched
+	scope := ""
+	for _, admin := range config.AdminTokens {
+		if admin != "" && subtle.ConstantTimeCompare(token, []byte(admin)) == 1 {
+			scope = ScopeAdmin
+		}
+	}
+	for _, read := range config.ReadTokens {
+		if read != "" && subtle.ConstantTimeCompare(token, []byte(read)) == 1 && scope == "" {
+			scope = ScopeRead
+		}
+	}
+	return scope
+}
+
+func requiredScope(config APIAuthConfig, r *http.Request) string {
+	if matchesPath(config.AdminPaths, r.URL.Path) {
+		return ScopeAdmin
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeAdmin
+	}
+}
+
+func matchesPath(paths []string, path string) bool {
+	for _, candidate := range paths {
+		if candidate == path || (strings.HasSuffix(candidate, "/") && strings.HasPrefix(path, candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sendScopeError(w http.ResponseWriter, logger *logrus.Logger, r *http.Request, statusCode int, code, message string) {
+	logger.WithFields(logrus.Fields{
+		"status_code": statusCode,
+		"method":      r.Method,
+		"path":        r.URL.Path,
+	}).Warn("API request rejected")
+
+	if statusCode == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="sql-graph-visualizer"`)
+	}
+	sendJSONResponse(w, logger, statusCode, APIResponse{
+		Success:   false,
+		Error:     &APIError{Code: code, Message: message},
+		Timestamp: time.Now(),
+	})
+}
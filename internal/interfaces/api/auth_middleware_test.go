@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScopedTestRouter(t *testing.T, config APIAuthConfig) http.Handler {
+	_, router := newTestTransformHandlers(t)
+	router.HandleFunc("/api/graph/validate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	router.HandleFunc("/api/health/live", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	router.HandleFunc("/config", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	router.Use(NewScopeMiddleware(logger, config))
+	return router
+}
+
+func scopedRequest(handler http.Handler, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestScopeMiddleware_ReadTokenCannotTriggerTransform(t *testing.T) {
+	router := newScopedTestRouter(t, APIAuthConfig{
+		ReadTokens:  []string{"reader"},
+		AdminTokens: []string{"admin"},
+		PublicPaths: DefaultPublicPaths,
+		AdminPaths:  []string{"/config"},
+	})
+
+	assert.Equal(t, http.StatusOK, scopedRequest(router, http.MethodGet, "/api/graph/validate", "reader"))
+	assert.Equal(t, http.StatusOK, scopedRequest(router, http.MethodGet, "/api/transform/status", "reader"))
+	assert.Equal(t, http.StatusForbidden, scopedRequest(router, http.MethodPost, "/api/transform/start", "reader"))
+	assert.Equal(t, http.StatusForbidden, scopedRequest(router, http.MethodGet, "/config", "reader"), "admin paths need admin even for reads")
+
+	assert.Equal(t, http.StatusAccepted, scopedRequest(router, http.MethodPost, "/api/transform/start", "admin"))
+	assert.Equal(t, http.StatusOK, scopedRequest(router, http.MethodGet, "/config", "admin"))
+}
+
+func TestScopeMiddleware_RejectsMissingOrUnknownTokens(t *testing.T) {
+	router := newScopedTestRouter(t, APIAuthConfig{
+		ReadTokens:  []string{"reader"},
+		PublicPaths: DefaultPublicPaths,
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, scopedRequest(router, http.MethodGet, "/api/graph/validate", ""))
+	assert.Equal(t, http.StatusUnauthorized, scopedRequest(router, http.MethodGet, "/api/graph/validate", "guessed"))
+	assert.Equal(t, http.StatusOK, scopedRequest(router, http.MethodGet, "/api/health/live", ""), "health probes stay public")
+}
+
+func TestScopeMiddleware_DisabledWithoutTokens(t *testing.T) {
+	router := newScopedTestRouter(t, APIAuthConfig{})
+
+	assert.Equal(t, http.StatusAccepted, scopedRequest(router, http.MethodPost, "/api/transform/start", ""))
+}
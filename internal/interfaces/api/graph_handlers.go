@@ -0,0 +1,386 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/graph"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// GraphHandlers contains HTTP handlers for operations on the imported graph
+type GraphHandlers struct {
+	logger       *logrus.Logger
+	indexAdvisor *graph.IndexAdvisor
+	validator    *graph.GraphValidator
+	snapshots    *graph.GraphSnapshotStore
+	queries      *graph.GraphQueryService
+	nodeDetails  *graph.NodeDetailsService
+}
+
+// IndexApplyRequest selects which suggested indexes to create; an empty list applies all of them
+type IndexApplyRequest struct {
+	Names []string `json:"names,omitempty"`
+}
+
+// IndexApplyResponse summarizes an index apply run
+type IndexApplyResponse struct {
+	Applied int                      `json:"applied"`
+	Failed  int                      `json:"failed"`
+	Results []graph.IndexApplyResult `json:"results"`
+}
+
+// GraphQueryRequest runs the registered query Name with Params; Cypher instead runs a
+// client query, which only open query mode accepts
+type GraphQueryRequest struct {
+	Name   string         `json:"name,omitempty"`
+	Params map[string]any `json:"params,omitempty"`
+	Cypher string         `json:"cypher,omitempty"`
+}
+
+// GraphQueryResponse holds the records returned by a query
+type GraphQueryResponse struct {
+	Name string           `json:"name,omitempty"`
+	Rows []map[string]any `json:"rows"`
+}
+
+// GraphQueriesResponse lists the registered queries and whether client Cypher is accepted
+type GraphQueriesResponse struct {
+	Mode    string             `json:"mode"`
+	Queries []graph.NamedQuery `json:"queries"`
+}
+
+// NewGraphHandlers creates new graph handlers
+func NewGraphHandlers(logger *logrus.Logger, indexAdvisor *graph.IndexAdvisor, validator *graph.GraphValidator) *GraphHandlers {
+	return &GraphHandlers{
+		logger:       logger,
+		indexAdvisor: indexAdvisor,
+		validator:    validator,
+	}
+}
+
+// SetSnapshots enables the routes listing and comparing the graphs of past transform runs
+func (gh *GraphHandlers) SetSnapshots(snapshots *graph.GraphSnapshotStore) {
+	gh.snapshots = snapshots
+}
+
+// SetQueries enables the routes listing and running graph queries
+func (gh *GraphHandlers) SetQueries(queries *graph.GraphQueryService) {
+	gh.queries = queries
+}
+
+// SetNodeDetails enables the route describing a single node and its relationships
+func (gh *GraphHandlers) SetNodeDetails(nodeDetails *graph.NodeDetailsService) {
+	gh.nodeDetails = nodeDetails
+}
+
+// RegisterRoutes registers all graph-related routes
+func (gh *GraphHandlers) RegisterRoutes(router *mux.Router) {
+	api := router.PathPrefix("/api/graph").Subrouter()
+
+	api.HandleFunc("/indexes/suggestions", gh.GetIndexSuggestions).Methods("GET")
+	api.HandleFunc("/indexes/apply", gh.ApplyIndexes).Methods("POST")
+	api.HandleFunc("/validate", gh.ValidateGraph).Methods("GET")
+	api.HandleFunc("/validate/prune", gh.PruneDanglingRelationships).Methods("POST")
+	if gh.snapshots != nil {
+		api.HandleFunc("/snapshots", gh.ListSnapshots).Methods("GET")
+		api.HandleFunc("/snapshots/{id}", gh.GetSnapshot).Methods("GET")
+		api.HandleFunc("/snapshots/{id}/diff", gh.DiffSnapshot).Methods("GET")
+		api.HandleFunc("/table-growth", gh.GetTableGrowth).Methods("GET")
+	}
+	if gh.queries != nil {
+		api.HandleFunc("/queries", gh.ListQueries).Methods("GET")
+		api.HandleFunc("/query", gh.RunQuery).Methods("POST")
+	}
+	if gh.nodeDetails != nil {
+		api.HandleFunc("/node/{id}", gh.GetNodeDetails).Methods("GET")
+	}
+}
+
+// GetIndexSuggestions returns the CREATE INDEX statements recommended for the imported graph
+func (gh *GraphHandlers) GetIndexSuggestions(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := gh.indexAdvisor.Suggest(r.Context())
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusInternalServerError, "SUGGESTION_FAILED", "Failed to build index suggestions", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      suggestions,
+		Timestamp: time.Now(),
+	})
+}
+
+// ApplyIndexes creates the suggested indexes in Neo4j
+func (gh *GraphHandlers) ApplyIndexes(w http.ResponseWriter, r *http.Request) {
+	var req IndexApplyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	suggestions, err := gh.indexAdvisor.Suggest(r.Context())
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusInternalServerError, "SUGGESTION_FAILED", "Failed to build index suggestions", err.Error())
+		return
+	}
+
+	if len(req.Names) > 0 {
+		wanted := make(map[string]bool, len(req.Names))
+		for _, name := range req.Names {
+			wanted[name] = true
+		}
+		selected := suggestions[:0]
+		for _, suggestion := range suggestions {
+			if wanted[suggestion.Name] {
+				selected = append(selected, suggestion)
+				delete(wanted, suggestion.Name)
+			}
+		}
+		if len(wanted) > 0 {
+			unknown := make([]string, 0, len(wanted))
+			for name := range wanted {
+				unknown = append(unknown, name)
+			}
+			sendErrorResponse(w, gh.logger, http.StatusBadRequest, "UNKNOWN_INDEX", "Requested index is not a current suggestion", unknown[0])
+			return
+		}
+		suggestions = selected
+	}
+
+	response := IndexApplyResponse{Results: gh.indexAdvisor.Apply(r.Context(), suggestions)}
+	for _, result := range response.Results {
+		if result.Applied {
+			response.Applied++
+		} else {
+			response.Failed++
+		}
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   response.Failed == 0,
+		Data:      response,
+		Timestamp: time.Now(),
+	})
+}
+
+// ValidateGraph reports relationships whose source or target node was not created by the transform
+func (gh *GraphHandlers) ValidateGraph(w http.ResponseWriter, r *http.Request) {
+	gh.runValidation(w, r, false)
+}
+
+// PruneDanglingRelationships deletes the relationships reported by ValidateGraph
+func (gh *GraphHandlers) PruneDanglingRelationships(w http.ResponseWriter, r *http.Request) {
+	gh.runValidation(w, r, true)
+}
+
+// ListSnapshots returns the snapshots of past transform runs, oldest first
+func (gh *GraphHandlers) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      gh.snapshots.List(),
+		Timestamp: time.Now(),
+	})
+}
+
+// GetSnapshot returns the metadata and counts of one snapshot
+func (gh *GraphHandlers) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	id, ok := gh.snapshotID(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+	snapshot, err := gh.snapshots.Get(id)
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusNotFound, "SNAPSHOT_NOT_FOUND", "Snapshot not found", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      snapshot,
+		Timestamp: time.Now(),
+	})
+}
+
+// GetTableGrowth returns the size series of the source tables, the fastest growing first;
+// ?table= limits it to one table
+func (gh *GraphHandlers) GetTableGrowth(w http.ResponseWriter, r *http.Request) {
+	growth := gh.snapshots.TableGrowth()
+	if table := r.URL.Query().Get("table"); table != "" {
+		filtered := make([]graph.TableGrowth, 0, 1)
+		for _, series := range growth {
+			if series.Table == table {
+				filtered = append(filtered, series)
+			}
+		}
+		if len(filtered) == 0 {
+			sendErrorResponse(w, gh.logger, http.StatusNotFound, "TABLE_NOT_FOUND", "No size history for table", table)
+			return
+		}
+		growth = filtered
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      growth,
+		Timestamp: time.Now(),
+	})
+}
+
+// DiffSnapshot compares a snapshot with the one given by ?from=, by default the snapshot
+// taken before it
+func (gh *GraphHandlers) DiffSnapshot(w http.ResponseWriter, r *http.Request) {
+	id, ok := gh.snapshotID(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	var from int
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if from, ok = gh.snapshotID(w, fromStr); !ok {
+			return
+		}
+	} else {
+		previous, err := gh.snapshots.Previous(id)
+		if err != nil {
+			sendErrorResponse(w, gh.logger, http.StatusNotFound, "SNAPSHOT_NOT_FOUND", "No snapshot to compare with", err.Error())
+			return
+		}
+		from = previous.ID
+	}
+
+	diff, err := gh.snapshots.Diff(from, id)
+	if errors.Is(err, graph.ErrSnapshotCountsOnly) {
+		sendErrorResponse(w, gh.logger, http.StatusConflict, "SNAPSHOT_COUNTS_ONLY", "Snapshot keeps only counts", err.Error())
+		return
+	}
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusNotFound, "SNAPSHOT_NOT_FOUND", "Snapshot not found", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      diff,
+		Timestamp: time.Now(),
+	})
+}
+
+// ListQueries returns the registered named queries and the query mode
+func (gh *GraphHandlers) ListQueries(w http.ResponseWriter, r *http.Request) {
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      GraphQueriesResponse{Mode: gh.queries.Mode(), Queries: gh.queries.Queries()},
+		Timestamp: time.Now(),
+	})
+}
+
+// RunQuery runs a registered query by name, or client Cypher when the query mode is open
+func (gh *GraphHandlers) RunQuery(w http.ResponseWriter, r *http.Request) {
+	var req GraphQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		return
+	}
+	if (req.Name == "") == (req.Cypher == "") {
+		sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_REQUEST", "Either name or cypher is required", "")
+		return
+	}
+
+	var rows []map[string]any
+	var err error
+	if req.Name != "" {
+		rows, err = gh.queries.Run(req.Name, req.Params)
+	} else {
+		rows, err = gh.queries.RunCypher(req.Cypher, req.Params)
+	}
+	switch {
+	case errors.Is(err, graph.ErrQueryNotAllowed):
+		sendErrorResponse(w, gh.logger, http.StatusForbidden, "QUERY_NOT_ALLOWED", "Only registered queries may run", err.Error())
+		return
+	case errors.Is(err, graph.ErrUnknownQuery):
+		sendErrorResponse(w, gh.logger, http.StatusNotFound, "QUERY_NOT_FOUND", "Query not found", err.Error())
+		return
+	case errors.Is(err, graph.ErrInvalidQueryParams):
+		sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_QUERY_PARAMS", "Invalid query parameters", err.Error())
+		return
+	case err != nil:
+		sendErrorResponse(w, gh.logger, http.StatusInternalServerError, "QUERY_FAILED", "Failed to run query", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      GraphQueryResponse{Name: req.Name, Rows: rows},
+		Timestamp: time.Now(),
+	})
+}
+
+// GetNodeDetails returns a node by its graph id (<Label>_<id>) with relationship counts and
+// samples per type and direction; ?samples= sets the sample size per type
+func (gh *GraphHandlers) GetNodeDetails(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		id = r.PathValue("id")
+	}
+
+	sampleSize := graph.DefaultNodeSampleSize
+	if samplesStr := r.URL.Query().Get("samples"); samplesStr != "" {
+		samples, err := strconv.Atoi(samplesStr)
+		if err != nil || samples < 1 {
+			sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_SAMPLES", "Samples must be a positive number", samplesStr)
+			return
+		}
+		sampleSize = samples
+	}
+
+	details, err := gh.nodeDetails.NodeDetails(id, sampleSize)
+	switch {
+	case errors.Is(err, graph.ErrInvalidNodeID):
+		sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_NODE_ID", "Node ID must be <Label>_<id>", err.Error())
+		return
+	case errors.Is(err, graph.ErrNodeNotFound):
+		sendErrorResponse(w, gh.logger, http.StatusNotFound, "NODE_NOT_FOUND", "Node not found", err.Error())
+		return
+	case err != nil:
+		sendErrorResponse(w, gh.logger, http.StatusInternalServerError, "NODE_DETAILS_FAILED", "Failed to load node details", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      details,
+		Timestamp: time.Now(),
+	})
+}
+
+func (gh *GraphHandlers) snapshotID(w http.ResponseWriter, value string) (int, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusBadRequest, "INVALID_SNAPSHOT_ID", "Snapshot ID must be a number", value)
+		return 0, false
+	}
+	return id, true
+}
+
+func (gh *GraphHandlers) runValidation(w http.ResponseWriter, r *http.Request, prune bool) {
+	report, err := gh.validator.Validate(r.Context(), prune)
+	if err != nil {
+		sendErrorResponse(w, gh.logger, http.StatusInternalServerError, "VALIDATION_FAILED", "Failed to validate graph", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, gh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      report,
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/application/services/graph"
+	graphagg "sql-graph-visualizer/internal/domain/aggregates/graph"
+	transformagg "sql-graph-visualizer/internal/domain/aggregates/transform"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/valueobjects/transform"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNeo4jPort struct {
+	executed []string
+	rows     []map[string]interface{}
+}
+
+func (f *fakeNeo4jPort) StoreGraph(g *graphagg.GraphAggregate) error { return nil }
+func (f *fakeNeo4jPort) SearchNodes(criteria string) ([]*graphagg.GraphAggregate, error) {
+	return nil, nil
+}
+func (f *fakeNeo4jPort) ExportGraph(query string) (any, error) {
+	return graphagg.NewGraphAggregate(""), nil
+}
+func (f *fakeNeo4jPort) FetchNodes(nodeType string) ([]map[string]any, error) {
+	return nil, nil
+}
+func (f *fakeNeo4jPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	f.executed = append(f.executed, query)
+	return f.rows, nil
+}
+func (f *fakeNeo4jPort) Close() error { return nil }
+
+type fakeRuleRepository struct {
+	rules []*transformagg.RuleAggregate
+}
+
+func (f *fakeRuleRepository) GetAllRules(ctx context.Context) ([]*transformagg.RuleAggregate, error) {
+	return f.rules, nil
+}
+func (f *fakeRuleRepository) SaveRule(ctx context.Context, rule *transformagg.RuleAggregate) error {
+	f.rules = append(f.rules, rule)
+	return nil
+}
+func (f *fakeRuleRepository) DeleteRule(ctx context.Context, ruleID string) error { return nil }
+func (f *fakeRuleRepository) UpdateRulePriority(ctx context.Context, ruleID string, priority int) error {
+	return nil
+}
+
+func newTestGraphHandlers() (*fakeNeo4jPort, *mux.Router) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	neo4jPort := &fakeNeo4jPort{}
+	rules := &fakeRuleRepository{rules: []*transformagg.RuleAggregate{
+		{Rule: transform.TransformRule{Name: "users", RuleType: transform.NodeRule, TargetType: "User"}},
+		{Rule: transform.TransformRule{Name: "teams", RuleType: transform.NodeRule, TargetType: "Team"}},
+	}}
+
+	handlers := NewGraphHandlers(logger, graph.NewIndexAdvisor(neo4jPort, rules), graph.NewGraphValidator(neo4jPort, rules))
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	return neo4jPort, router
+}
+
+func TestGetIndexSuggestions(t *testing.T) {
+	_, router := newTestGraphHandlers()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/indexes/suggestions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Success bool                    `json:"success"`
+		Data    []graph.IndexSuggestion `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, "Team", response.Data[0].Label)
+	assert.Equal(t, "User", response.Data[1].Label)
+}
+
+func TestApplyIndexes(t *testing.T) {
+	neo4jPort, router := newTestGraphHandlers()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graph/indexes/apply", strings.NewReader(`{"names":["user_id_idx"]}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Success bool               `json:"success"`
+		Data    IndexApplyResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 1, response.Data.Applied)
+	require.Len(t, neo4jPort.executed, 1)
+	assert.Contains(t, neo4jPort.executed[0], "user_id_idx")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/graph/indexes/apply", strings.NewReader(`{"names":["missing_idx"]}`))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestValidateGraph(t *testing.T) {
+	neo4jPort, router := newTestGraphHandlers()
+	neo4jPort.rows = []map[string]interface{}{
+		{"id": int64(1), "type": "MEMBER_OF", "source_labels": []interface{}{"User"}, "source_key": int64(1), "target_labels": []interface{}{"Team"}, "target_key": int64(10)},
+		{"id": int64(2), "type": "MEMBER_OF", "source_labels": []interface{}{"User"}, "source_key": int64(2), "target_labels": []interface{}{"Team"}},
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/validate", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Success bool                        `json:"success"`
+		Data    graph.GraphValidationReport `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 2, response.Data.RelationshipsChecked)
+	assert.Equal(t, 1, response.Data.DanglingCount)
+	assert.True(t, response.Data.Dangling[0].MissingTarget)
+	assert.Len(t, neo4jPort.executed, 1, "GET does not prune")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/graph/validate/prune", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, neo4jPort.executed, 3)
+	assert.Contains(t, neo4jPort.executed[2], "DELETE r")
+}
+
+func TestSnapshotRoutes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	snapshots := graph.NewGraphSnapshotStore(0)
+	before := graphagg.NewGraphAggregate("")
+	require.NoError(t, before.AddNode("User", map[string]any{"id": int64(1), "name": "Ada"}))
+	after := graphagg.NewGraphAggregate("")
+	require.NoError(t, after.AddNode("User", map[string]any{"id": int64(1), "name": "Ada"}))
+	require.NoError(t, after.AddNode("User", map[string]any{"id": int64(2), "name": "Grace"}))
+	snapshots.RecordSnapshot(before, ports.GraphSnapshotMetadata{RuleSetVersion: "v1"})
+	snapshots.RecordSnapshot(after, ports.GraphSnapshotMetadata{RuleSetVersion: "v1"})
+
+	handlers := NewGraphHandlers(logger, nil, nil)
+	handlers.SetSnapshots(snapshots)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/snapshots", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Data []graph.GraphSnapshot `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&list))
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, 2, list.Data[1].NodeCount)
+	assert.Equal(t, "v1", list.Data[1].RuleSetVersion)
+
+	// Without ?from= a snapshot is compared with the one before it
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/snapshots/2/diff", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var diff struct {
+		Data graph.SnapshotDiff `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&diff))
+	assert.Equal(t, 1, diff.Data.From.ID)
+	require.Len(t, diff.Data.Changes.AddedNodes, 1)
+	assert.Equal(t, "Grace", diff.Data.Changes.AddedNodes[0].Properties["name"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/snapshots/1/diff", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "the first snapshot has nothing before it")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/snapshots/latest", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTableGrowthRoute(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	snapshots := graph.NewGraphSnapshotStore(0)
+	snapshots.RecordTableSizes([]*models.TableSize{{TableName: "users", RowCount: 10}, {TableName: "orders", RowCount: 5}})
+	snapshots.RecordTableSizes([]*models.TableSize{{TableName: "users", RowCount: 30}, {TableName: "orders", RowCount: 6}})
+
+	handlers := NewGraphHandlers(logger, nil, nil)
+	handlers.SetSnapshots(snapshots)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/table-growth?table=orders", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data []graph.TableGrowth `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "orders", response.Data[0].Table)
+	assert.Equal(t, int64(1), response.Data[0].RowDelta)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graph/table-growth?table=missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestQueryRoutesInAllowListMode(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	neo4jPort := &fakeNeo4jPort{rows: []map[string]interface{}{{"name": "Ada"}}}
+	queries, err := graph.NewGraphQueryService(neo4jPort, []graph.NamedQuery{{
+		Name:   "customer_by_id",
+		Cypher: "MATCH (c:Customer {id: $id}) RETURN c.name AS name",
+		Params: []string{"id"},
+	}}, graph.QueryModeAllowList)
+	require.NoError(t, err)
+
+	handlers := NewGraphHandlers(logger, nil, nil)
+	handlers.SetQueries(queries)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	run := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/graph/query", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := run(`{"name": "customer_by_id", "params": {"id": "1"}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data GraphQueryResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, []map[string]any{{"name": "Ada"}}, response.Data.Rows)
+	assert.Equal(t, []string{"MATCH (c:Customer {id: $id}) RETURN c.name AS name"}, neo4jPort.executed)
+
+	rec = run(`{"cypher": "MATCH (n) DETACH DELETE n"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "QUERY_NOT_ALLOWED")
+	assert.Len(t, neo4jPort.executed, 1, "arbitrary Cypher never reaches Neo4j")
+
+	assert.Equal(t, http.StatusNotFound, run(`{"name": "unknown"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, run(`{"name": "customer_by_id"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, run(`{}`).Code)
+}
+
+// nodeGraphPort holds Customer 1, who placed a single order
+type nodeGraphPort struct {
+	fakeNeo4jPort
+}
+
+func (p *nodeGraphPort) ExecuteQuery(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	p.executed = append(p.executed, query)
+	switch {
+	case strings.HasPrefix(query, "MATCH (n:`Customer`)") && params["keys"].([]any)[0] == "1":
+		return []map[string]any{{"node_id": int64(7), "labels": []any{"Customer"}, "properties": map[string]any{"id": int64(1), "name": "Ada"}}}, nil
+	case strings.HasPrefix(query, "MATCH (n:"):
+		return nil, nil
+	case strings.Contains(query, "count(r)"):
+		return []map[string]any{{"type": "PLACED", "outgoing": true, "count": int64(1)}}, nil
+	default:
+		return []map[string]any{{"labels": []any{"Order"}, "properties": map[string]any{"id": int64(10)}}}, nil
+	}
+}
+
+func TestNodeDetailsRoute(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	handlers := NewGraphHandlers(logger, nil, nil)
+	handlers.SetNodeDetails(graph.NewNodeDetailsService(&nodeGraphPort{}))
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/api/graph/node/Customer_1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response struct {
+		Data graph.NodeDetails `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "Ada", response.Data.Properties["name"])
+	require.Len(t, response.Data.Outgoing, 1)
+	assert.Equal(t, "PLACED", response.Data.Outgoing[0].Type)
+	assert.Equal(t, int64(1), response.Data.Outgoing[0].Count)
+	assert.Equal(t, "Order_10", response.Data.Outgoing[0].Samples[0].ID)
+	assert.Empty(t, response.Data.Incoming)
+
+	rec = get("/api/graph/node/Customer_2")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NODE_NOT_FOUND")
+	assert.Equal(t, http.StatusBadRequest, get("/api/graph/node/Customer").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/graph/node/Customer_1?samples=none").Code)
+}
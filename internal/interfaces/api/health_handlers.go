@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultDependencyCheckTimeout = 3 * time.Second
+
+// DependencyCheck reports whether a dependency is reachable
+type DependencyCheck func(ctx context.Context) error
+
+// DependencyStatus is the outcome of a single readiness check
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// HealthResponse is returned by the liveness and readiness probes
+type HealthResponse struct {
+	Status       string                      `json:"status"`
+	Timestamp    time.Time                   `json:"timestamp"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check DependencyCheck
+}
+
+// HealthHandlers serves the liveness and readiness probes
+type HealthHandlers struct {
+	logger  *logrus.Logger
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+// NewHealthHandlers creates health handlers without any dependency checks
+func NewHealthHandlers(logger *logrus.Logger) *HealthHandlers {
+	return &HealthHandlers{
+		logger:  logger,
+		timeout: defaultDependencyCheckTimeout,
+	}
+}
+
+// AddCheck registers a dependency that must be reachable for the service to be ready
+func (hh *HealthHandlers) AddCheck(name string, check DependencyCheck) {
+	hh.checks = append(hh.checks, namedCheck{name: name, check: check})
+}
+
+// SetCheckTimeout limits how long each dependency check may take
+func (hh *HealthHandlers) SetCheckTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		hh.timeout = timeout
+	}
+}
+
+// RegisterRoutes registers the liveness and readiness routes
+func (hh *HealthHandlers) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/health/live", hh.Live).Methods("GET")
+	router.HandleFunc("/api/health/ready", hh.Ready).Methods("GET")
+}
+
+// Live reports that the process is up; it never checks dependencies
+func (hh *HealthHandlers) Live(w http.ResponseWriter, r *http.Request) {
+	hh.writeResponse(w, http.StatusOK, HealthResponse{
+		Status:    "alive",
+		Timestamp: time.Now(),
+	})
+}
+
+// Ready runs all dependency checks and returns 503 if any of them fails
+func (hh *HealthHandlers) Ready(w http.ResponseWriter, r *http.Request) {
+	dependencies := hh.runChecks(r.Context())
+
+	status, code := "ready", http.StatusOK
+	for name, dependency := range dependencies {
+		if dependency.Status != "up" {
+			hh.logger.Warnf("Readiness check failed for %s: %s", name, dependency.Error)
+			status, code = "not_ready", http.StatusServiceUnavailable
+		}
+	}
+
+	hh.writeResponse(w, code, HealthResponse{
+		Status:       status,
+		Timestamp:    time.Now(),
+		Dependencies: dependencies,
+	})
+}
+
+func (hh *HealthHandlers) runChecks(ctx context.Context) map[string]DependencyStatus {
+	results := make(map[string]DependencyStatus, len(hh.checks))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, c := range hh.checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, hh.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.check(checkCtx)
+			result := DependencyStatus{
+				Status:    "up",
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = "down"
+				result.Error = err.Error()
+			}
+
+			mutex.Lock()
+			results[c.name] = result
+			mutex.Unlock()
+		}(c)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (hh *HealthHandlers) writeResponse(w http.ResponseWriter, statusCode int, response HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		hh.logger.WithError(err).Error("Failed to encode health response")
+	}
+}
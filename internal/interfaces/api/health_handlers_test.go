@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHealthRouter(dbErr error) *mux.Router {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	handlers := NewHealthHandlers(logger)
+	handlers.AddCheck("database", func(ctx context.Context) error { return dbErr })
+	handlers.AddCheck("neo4j", func(ctx context.Context) error { return nil })
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	return router
+}
+
+func probe(t *testing.T, router *mux.Router, path string) (int, HealthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var response HealthResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	return rec.Code, response
+}
+
+func TestReadyWhenDependenciesAreUp(t *testing.T) {
+	router := newTestHealthRouter(nil)
+
+	code, response := probe(t, router, "/api/health/ready")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", response.Status)
+	assert.Equal(t, "up", response.Dependencies["database"].Status)
+	assert.Equal(t, "up", response.Dependencies["neo4j"].Status)
+}
+
+func TestReadyFailsWhenDatabasePingFails(t *testing.T) {
+	router := newTestHealthRouter(errors.New("dial tcp 127.0.0.1:3306: connect: connection refused"))
+
+	code, response := probe(t, router, "/api/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not_ready", response.Status)
+	assert.Equal(t, "down", response.Dependencies["database"].Status)
+	assert.Contains(t, response.Dependencies["database"].Error, "connection refused")
+	assert.Equal(t, "up", response.Dependencies["neo4j"].Status)
+
+	code, response = probe(t, router, "/api/health/live")
+	assert.Equal(t, http.StatusOK, code, "liveness does not depend on the database")
+	assert.Equal(t, "alive", response.Status)
+	assert.Empty(t, response.Dependencies)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// DefaultVisualizationPort is the visualization server port when no address is configured
+const DefaultVisualizationPort = "3000"
+
+// ErrAddressInUse is returned by Listen when another process holds the address
+var ErrAddressInUse = errors.New("address already in use")
+
+// VisualizationAddress returns the address the visualization server binds to: the
+// VISUALIZATION_ADDR environment variable, then bindAddress from the configuration, then all
+// interfaces on the PORT environment variable (set by hosting platforms) or DefaultVisualizationPort
+func VisualizationAddress(bindAddress string) string {
+	if address := os.Getenv("VISUALIZATION_ADDR"); address != "" {
+		return address
+	}
+	if bindAddress != "" {
+		return bindAddress
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = DefaultVisualizationPort
+	}
+	return ":" + port
+}
+
+// Listen opens a TCP listener on address. An address held by another process fails with
+// ErrAddressInUse, unless fallbackToFreePort is set: a free port of the same host is used then,
+// and the listener's Addr tells which.
+func Listen(address string, fallbackToFreePort bool) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err == nil {
+		return listener, nil
+	}
+	if !errors.Is(err, syscall.EADDRINUSE) {
+		return nil, fmt.Errorf("cannot listen on %s: %w", address, err)
+	}
+	if !fallbackToFreePort {
+		return nil, fmt.Errorf("cannot listen on %s: %w", address, ErrAddressInUse)
+	}
+
+	host, _, splitErr := net.SplitHostPort(address)
+	if splitErr != nil {
+		return nil, fmt.Errorf("invalid listen address %s: %w", address, splitErr)
+	}
+	listener, err = net.Listen("tcp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot listen on a free port of %q: %w", host, err)
+	}
+	return listener, nil
+}
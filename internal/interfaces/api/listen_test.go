@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisualizationAddress(t *testing.T) {
+	t.Setenv("VISUALIZATION_ADDR", "")
+	t.Setenv("PORT", "")
+	assert.Equal(t, ":3000", VisualizationAddress(""))
+	assert.Equal(t, "127.0.0.1:4000", VisualizationAddress("127.0.0.1:4000"), "the configured address is used")
+
+	t.Setenv("PORT", "8081")
+	assert.Equal(t, ":8081", VisualizationAddress(""))
+	assert.Equal(t, "127.0.0.1:4000", VisualizationAddress("127.0.0.1:4000"))
+
+	t.Setenv("VISUALIZATION_ADDR", "0.0.0.0:5000")
+	assert.Equal(t, "0.0.0.0:5000", VisualizationAddress("127.0.0.1:4000"), "the environment overrides the configuration")
+}
+
+func TestListenUsesConfiguredAddress(t *testing.T) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := free.Addr().String()
+	require.NoError(t, free.Close())
+
+	listener, err := Listen(address, false)
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.Equal(t, address, listener.Addr().String())
+}
+
+func TestListenFailsOnBindConflict(t *testing.T) {
+	holder, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer holder.Close()
+
+	_, err = Listen(holder.Addr().String(), false)
+	assert.ErrorIs(t, err, ErrAddressInUse)
+	assert.ErrorContains(t, err, holder.Addr().String())
+
+	// The process holding the address keeps serving
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := holder.Accept()
+		if err == nil {
+			conn.Close()
+		}
+		accepted <- err
+	}()
+	conn, err := net.Dial("tcp", holder.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+	assert.NoError(t, <-accepted)
+}
+
+func TestListenFallsBackToFreePort(t *testing.T) {
+	holder, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer holder.Close()
+
+	listener, err := Listen(holder.Addr().String(), true)
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.NotEqual(t, holder.Addr().String(), listener.Addr().String())
+	host, _, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+}
@@ -0,0 +1,529 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/application/services"
+	"sql-graph-visualizer/internal/application/services/graph"
+	"sql-graph-visualizer/internal/application/services/performance"
+	"sql-graph-visualizer/internal/application/services/transform"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the generated spec follows
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIOperation documents a route for the generated spec. Request and Response hold values
+// of the types decoded from the request body and returned as APIResponse.data; their schemas
+// are derived from the struct definitions, so they follow changes to the handlers' types.
+type OpenAPIOperation struct {
+	Summary string
+	// Query lists the query parameters the handler reads, with their descriptions
+	Query    map[string]string
+	Request  any
+	Response any
+	// Status is the success status code; 200 if unset
+	Status int
+	// Raw responses are written as they are instead of wrapped in APIResponse
+	Raw         bool
+	ContentType string
+}
+
+// apiOperations documents the routes registered by the handlers, keyed by method and path
+// template. A route without an entry still appears in the spec, with an untyped response.
+var apiOperations = map[string]OpenAPIOperation{
+	"GET /api/openapi.json": {Summary: "OpenAPI specification of this API", Raw: true},
+
+	"GET /api/health/live":  {Summary: "Liveness probe", Response: HealthResponse{}, Raw: true},
+	"GET /api/health/ready": {Summary: "Readiness probe; 503 when a dependency is unreachable or the transform has not finished", Response: HealthResponse{}, Raw: true},
+
+	"GET /api/performance/benchmarks":               {Summary: "List running benchmarks", Response: []*performance.BenchmarkExecution{}},
+	"POST /api/performance/benchmarks":              {Summary: "Start a benchmark", Request: BenchmarkRequest{}, Response: BenchmarkStatusResponse{}, Status: http.StatusCreated},
+	"GET /api/performance/benchmarks/{id}":          {Summary: "Benchmark status", Response: BenchmarkStatusResponse{}},
+	"POST /api/performance/benchmarks/{id}/stop":    {Summary: "Stop a benchmark", Response: map[string]string{}},
+	"GET /api/performance/benchmarks/{id}/results":  {Summary: "Benchmark results", Response: &ports.BenchmarkResult{}},
+	"GET /api/performance/benchmarks/{id}/findings": {Summary: "Benchmark findings as a SARIF-style report", Response: &performance.FindingsReport{}, Raw: true, ContentType: "application/sarif+json"},
+	"GET /api/performance/data":                     {Summary: "Current performance data", Response: PerformanceDataResponse{}},
+	"GET /api/performance/data/analysis":            {Summary: "Analysis of the current performance data", Response: map[string]any{}},
+	"GET /api/performance/data/graph":               {Summary: "Performance data mapped onto the graph", Response: &performance.PerformanceGraphData{}},
+	"POST /api/performance/baseline":                {Summary: "Capture the current metrics as a named baseline", Request: CaptureBaselineRequest{}, Response: &performance.PerformanceBaseline{}, Status: http.StatusCreated},
+	"GET /api/performance/baselines":                {Summary: "List stored baselines", Response: []*performance.PerformanceBaseline{}},
+	"GET /api/performance/baselines/{name}/compare": {Summary: "Compare the current metrics with a baseline", Response: &performance.BaselineComparison{}},
+	"GET /api/performance/realtime/clients":         {Summary: "Connected WebSocket clients", Response: []*performance.ClientInfo{}},
+	"GET /api/performance/realtime/status":          {Summary: "Real-time monitor status", Response: map[string]any{}},
+	"POST /api/performance/realtime/pause":          {Summary: "Pause real-time broadcasts", Response: map[string]any{}},
+	"POST /api/performance/realtime/resume":         {Summary: "Resume real-time broadcasts", Response: map[string]any{}},
+	"POST /api/performance/realtime/broadcast-test": {Summary: "Broadcast a synthetic message (diagnostics token required)", Request: BroadcastTestRequest{}, Response: BroadcastTestResponse{}},
+	"GET /ws/performance":                           {Summary: "WebSocket stream of performance updates", Status: http.StatusSwitchingProtocols, Raw: true},
+	"GET /api/performance/metrics/summary":          {Summary: "Performance summary", Response: &PerformanceSummary{}},
+	"GET /api/performance/metrics/tables":           {Summary: "Table I/O statistics", Response: []performance.TableIOStatistic{}},
+	"GET /api/performance/metrics/alerts":           {Summary: "Active alerts", Response: []map[string]any{}},
+	"GET /api/performance/config":                   {Summary: "Performance configuration", Response: map[string]any{}},
+	"PUT /api/performance/config":                   {Summary: "Update the performance configuration", Response: map[string]string{}},
+	"GET /api/performance/data/history": {
+		Summary: "Metrics history, raw or aggregated into buckets",
+		Query: map[string]string{
+			"start_time": "RFC3339 start, default one hour ago",
+			"end_time":   "RFC3339 end, default now",
+			"window":     "bucket size such as 1m; raw samples when empty",
+			"limit":      "maximum number of samples or buckets",
+		},
+		Response: HistoryResponse{},
+	},
+	"GET /api/performance/data/trends": {
+		Summary: "Trend analysis of the metrics history",
+		Query: map[string]string{
+			"start_time": "RFC3339 start, default the oldest sample",
+			"end_time":   "RFC3339 end, default now",
+		},
+		Response: &ports.TrendAnalysis{},
+	},
+	"GET /api/performance/poll": {
+		Summary: "Long-polling fallback for the WebSocket stream",
+		Query: map[string]string{
+			"since":   "cursor from the last response, RFC3339 or unix milliseconds",
+			"timeout": "seconds to wait for new messages",
+		},
+		Response: PollResponse{},
+	},
+	"GET /api/performance/metrics/queries": {
+		Summary:  "Statement statistics",
+		Query:    map[string]string{"limit": "maximum number of statements, default 50"},
+		Response: []performance.StatementStatistic{},
+	},
+	"GET /api/performance/tables/{table}/queries": {
+		Summary:  "Statements touching a table",
+		Query:    map[string]string{"limit": "maximum number of statements, default 50"},
+		Response: TableQueriesResponse{},
+	},
+
+	"GET /api/graph/indexes/suggestions": {Summary: "Suggested Neo4j indexes", Response: []graph.IndexSuggestion{}},
+	"POST /api/graph/indexes/apply":      {Summary: "Create suggested indexes", Request: IndexApplyRequest{}, Response: IndexApplyResponse{}},
+	"GET /api/graph/validate":            {Summary: "Report dangling relationships", Response: &graph.GraphValidationReport{}},
+	"POST /api/graph/validate/prune":     {Summary: "Delete dangling relationships", Response: &graph.GraphValidationReport{}},
+	"GET /api/graph/snapshots":           {Summary: "Snapshots of past transform runs, oldest first", Response: []graph.GraphSnapshot{}},
+	"GET /api/graph/snapshots/{id}":      {Summary: "Metadata and counts of a snapshot", Response: graph.GraphSnapshot{}},
+	"GET /api/graph/snapshots/{id}/diff": {
+		Summary:  "Changes between two snapshots",
+		Query:    map[string]string{"from": "snapshot to compare with, default the one before"},
+		Response: &graph.SnapshotDiff{},
+	},
+	"GET /api/graph/table-growth": {
+		Summary:  "Size series of the source tables, fastest growing first",
+		Query:    map[string]string{"table": "limit the series to one table"},
+		Response: []graph.TableGrowth{},
+	},
+	"GET /api/graph/queries": {Summary: "Registered named queries and the query mode", Response: GraphQueriesResponse{}},
+	"POST /api/graph/query":  {Summary: "Run a named query, or Cypher in open query mode", Request: GraphQueryRequest{}, Response: GraphQueryResponse{}},
+	"GET /api/graph/node/{id}": {
+		Summary:  "A node with relationship counts and samples per type and direction",
+		Query:    map[string]string{"samples": "related nodes sampled per relationship type, default 5, at most 50"},
+		Response: &graph.NodeDetails{},
+	},
+
+	"GET /api/schema/dictionary": {
+		Summary:  "Data dictionary of the source schema: tables, columns, keys and relationships",
+		Query:    map[string]string{"format": "json (default), or markdown or html returned as a document"},
+		Response: &services.DataDictionary{},
+	},
+
+	"GET /api/transform/status":  {Summary: "Progress of the active or last transform", Response: transform.TransformProgress{}},
+	"POST /api/transform/start":  {Summary: "Start a transform", Response: transform.TransformProgress{}, Status: http.StatusAccepted},
+	"POST /api/transform/cancel": {Summary: "Cancel the running transform", Response: transform.TransformProgress{}},
+
+	"GET /api/rules/export": {
+		Summary:     "Download the loaded rules as a bundle",
+		Query:       map[string]string{"format": "yaml (default) or json"},
+		Raw:         true,
+		ContentType: "application/yaml",
+	},
+	"POST /api/rules/import": {
+		Summary:  "Validate and store a YAML or JSON rule bundle",
+		Query:    map[string]string{"name": "file name for the stored bundle"},
+		Response: RuleImportResult{},
+	},
+	"POST /api/rules/{id}/test": {
+		Summary:  "Run one rule against sample rows without writing to Neo4j",
+		Query:    map[string]string{"limit": "number of sample rows, default 10"},
+		Response: &transform.RulePreview{},
+	},
+}
+
+// OpenAPISpec is an OpenAPI 3 document
+type OpenAPISpec struct {
+	OpenAPI    string                               `json:"openapi"`
+	Info       OpenAPIInfo                          `json:"info"`
+	Paths      map[string]map[string]*OpenAPIPathOp `json:"paths"`
+	Components OpenAPIComponents                    `json:"components"`
+}
+
+// OpenAPIInfo describes the API
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIComponents holds the schemas operations refer to
+type OpenAPIComponents struct {
+	Schemas map[string]map[string]any `json:"schemas"`
+}
+
+// OpenAPIPathOp is an operation of the generated spec
+type OpenAPIPathOp struct {
+	Summary     string                      `json:"summary,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a path or query parameter
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      map[string]any `json:"schema"`
+}
+
+// OpenAPIBody is a request body
+type OpenAPIBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an operation
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType holds the schema of a body
+type OpenAPIMediaType struct {
+	Schema map[string]any `json:"schema"`
+}
+
+// OpenAPIHandlers serves the spec generated from the routes registered on a router
+type OpenAPIHandlers struct {
+	logger  *logrus.Logger
+	router  *mux.Router
+	title   string
+	version string
+}
+
+// NewOpenAPIHandlers creates handlers describing the routes of router. The spec is built on
+// each request, so routes registered after these handlers are included.
+func NewOpenAPIHandlers(logger *logrus.Logger, router *mux.Router, title, version string) *OpenAPIHandlers {
+	return &OpenAPIHandlers{
+		logger:  logger,
+		router:  router,
+		title:   title,
+		version: version,
+	}
+}
+
+// RegisterRoutes registers the spec route
+func (oh *OpenAPIHandlers) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/openapi.json", oh.GetSpec).Methods("GET")
+}
+
+// GetSpec returns the OpenAPI document of the API
+func (oh *OpenAPIHandlers) GetSpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := BuildOpenAPISpec(oh.router, oh.title, oh.version)
+	if err != nil {
+		oh.logger.WithError(err).Error("Failed to build OpenAPI spec")
+		http.Error(w, "failed to build OpenAPI spec", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(spec); err != nil {
+		oh.logger.WithError(err).Error("Failed to encode OpenAPI spec")
+	}
+}
+
+// BuildOpenAPISpec describes every route registered on router with a method. Shapes of
+// documented operations are taken from apiOperations; others get an untyped response.
+func BuildOpenAPISpec(router *mux.Router, title, version string) (*OpenAPISpec, error) {
+	spec := &OpenAPISpec{
+		OpenAPI:    OpenAPIVersion,
+		Info:       OpenAPIInfo{Title: title, Version: version},
+		Paths:      make(map[string]map[string]*OpenAPIPathOp),
+		Components: OpenAPIComponents{Schemas: make(map[string]map[string]any)},
+	}
+	schemas := newSchemaGenerator(spec.Components.Schemas)
+	envelope := schemas.schemaFor(reflect.TypeOf(APIResponse{}))
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Path prefixes of subrouters match no method of their own
+			return nil
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		path, params := openAPIPath(template)
+
+		for _, method := range methods {
+			doc := apiOperations[method+" "+path]
+			op := &OpenAPIPathOp{
+				Summary:     doc.Summary,
+				OperationID: operationID(method, path),
+				Responses:   make(map[string]*OpenAPIResponse),
+			}
+			for _, name := range params {
+				op.Parameters = append(op.Parameters, OpenAPIParameter{
+					Name: name, In: "path", Required: true, Schema: map[string]any{"type": "string"},
+				})
+			}
+			for _, name := range sortedQueryParams(doc.Query) {
+				op.Parameters = append(op.Parameters, OpenAPIParameter{
+					Name: name, In: "query", Description: doc.Query[name], Schema: map[string]any{"type": "string"},
+				})
+			}
+			if doc.Request != nil {
+				op.RequestBody = &OpenAPIBody{
+					Required: true,
+					Content:  jsonContent(schemas.schemaFor(reflect.TypeOf(doc.Request))),
+				}
+			}
+
+			status := doc.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			success := &OpenAPIResponse{Description: http.StatusText(status)}
+			switch {
+			case status == http.StatusSwitchingProtocols:
+			case doc.Raw:
+				contentType := doc.ContentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				schema := map[string]any{}
+				if doc.Response != nil {
+					schema = schemas.schemaFor(reflect.TypeOf(doc.Response))
+				}
+				success.Content = map[string]OpenAPIMediaType{contentType: {Schema: schema}}
+			case doc.Response != nil:
+				success.Content = jsonContent(map[string]any{"allOf": []any{
+					envelope,
+					map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"data": schemas.schemaFor(reflect.TypeOf(doc.Response))},
+					},
+				}})
+			default:
+				success.Content = jsonContent(envelope)
+			}
+			op.Responses[strconv.Itoa(status)] = success
+			if !doc.Raw {
+				op.Responses["default"] = &OpenAPIResponse{Description: "Error", Content: jsonContent(envelope)}
+			}
+
+			if spec.Paths[path] == nil {
+				spec.Paths[path] = make(map[string]*OpenAPIPathOp)
+			}
+			spec.Paths[path][strings.ToLower(method)] = op
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// pathVariable matches a mux path variable with an optional pattern, e.g. {id:[0-9]+}
+var pathVariable = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// openAPIPath drops the patterns of path variables and lists their names
+func openAPIPath(template string) (string, []string) {
+	var params []string
+	path := pathVariable.ReplaceAllStringFunc(template, func(variable string) string {
+		name := pathVariable.FindStringSubmatch(variable)[1]
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	return path, params
+}
+
+// operationID derives a unique identifier such as getApiPerformanceBenchmarksId
+func operationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		id.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return id.String()
+}
+
+func sortedQueryParams(query map[string]string) []string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func jsonContent(schema map[string]any) map[string]OpenAPIMediaType {
+	return map[string]OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	readableDuration  = reflect.TypeOf(models.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaGenerator derives JSON schemas from Go types the way encoding/json serializes them.
+// Named structs become components referred to by $ref, which also covers recursive types.
+type schemaGenerator struct {
+	components map[string]map[string]any
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator(components map[string]map[string]any) *schemaGenerator {
+	return &schemaGenerator{components: components, names: make(map[reflect.Type]string)}
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "format": "int64", "description": "nanoseconds"}
+	case t == readableDuration:
+		return map[string]any{"type": "string", "example": "1.5s"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Custom encodings cannot be described from the type
+		return map[string]any{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + g.component(t)}
+	default:
+		// interface{} and anything else holds any JSON value
+		return map[string]any{}
+	}
+}
+
+// component registers a named struct and returns its component name, qualified with the
+// package when two packages declare structs of the same name
+func (g *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := schemaName(t.Name())
+	if _, taken := g.components[name]; taken {
+		pkg := t.PkgPath()
+		name = schemaName(pkg[strings.LastIndex(pkg, "/")+1:] + "_" + t.Name())
+	}
+	g.names[t] = name
+	g.components[name] = map[string]any{}
+	g.components[name] = g.structSchema(t)
+	return name
+}
+
+func schemaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// structSchema describes the fields encoding/json writes; fields without omitempty are
+// listed as required and embedded structs are flattened
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	g.addFields(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = g.schemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
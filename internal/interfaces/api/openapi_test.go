@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/services/graph"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDocumentedRouter registers the routes of every handler group; services are not needed
+// for registration
+func newDocumentedRouter() *mux.Router {
+	logger := logrus.New()
+	router := mux.NewRouter()
+	NewPerformanceHandlers(logger, nil, nil, nil, nil, nil).RegisterRoutes(router)
+	graphHandlers := NewGraphHandlers(logger, nil, nil)
+	graphHandlers.SetSnapshots(graph.NewGraphSnapshotStore(0))
+	queries, _ := graph.NewGraphQueryService(nil, nil, "")
+	graphHandlers.SetQueries(queries)
+	graphHandlers.SetNodeDetails(graph.NewNodeDetailsService(nil))
+	graphHandlers.RegisterRoutes(router)
+	NewTransformHandlers(logger, nil).RegisterRoutes(router)
+	NewSchemaHandlers(logger, nil).RegisterRoutes(router)
+	NewHealthHandlers(logger).RegisterRoutes(router)
+	ruleHandlers := NewRuleHandlers(logger, nil, "", nil)
+	ruleHandlers.SetPreviewer(&stubRulePreviewer{})
+	ruleHandlers.RegisterRoutes(router)
+	NewOpenAPIHandlers(logger, router, "SQL Graph Visualizer API", "test").RegisterRoutes(router)
+	return router
+}
+
+func fetchSpec(t *testing.T, router *mux.Router) map[string]any {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	return spec
+}
+
+func TestOpenAPISpecIncludesEveryRegisteredRoute(t *testing.T) {
+	router := newDocumentedRouter()
+	spec := fetchSpec(t, router)
+	paths := spec["paths"].(map[string]any)
+
+	routes := 0
+	require.NoError(t, router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		template, _ := route.GetPathTemplate()
+		for _, method := range methods {
+			routes++
+			key := method + " " + template
+			assert.Contains(t, apiOperations, key, "route %s is not documented in apiOperations", key)
+
+			item, ok := paths[template].(map[string]any)
+			if assert.True(t, ok, "path %s is missing from the spec", template) {
+				assert.Contains(t, item, strings.ToLower(method), "%s is missing from the spec", key)
+			}
+		}
+		return nil
+	}))
+	assert.Greater(t, routes, 30)
+	assert.Len(t, apiOperations, routes, "every documented operation belongs to a registered route")
+}
+
+func TestOpenAPISpecDescribesResponseShapes(t *testing.T) {
+	spec := fetchSpec(t, newDocumentedRouter())
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+
+	status := schemas["BenchmarkStatusResponse"].(map[string]any)
+	properties := status["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, properties["start_time"])
+	assert.Contains(t, properties, "queue_position")
+	assert.ElementsMatch(t, []any{"id", "status", "start_time", "progress", "metadata"}, status["required"])
+
+	envelope := schemas["APIResponse"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/APIError", envelope["error"].(map[string]any)["$ref"])
+
+	// Data is typed by combining the envelope with the handler's response type
+	get := spec["paths"].(map[string]any)["/api/performance/benchmarks/{id}"].(map[string]any)["get"].(map[string]any)
+	schema := get["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	allOf := schema["allOf"].([]any)
+	require.Len(t, allOf, 2)
+	assert.Equal(t, "#/components/schemas/APIResponse", allOf[0].(map[string]any)["$ref"])
+	data := allOf[1].(map[string]any)["properties"].(map[string]any)["data"].(map[string]any)
+	assert.Equal(t, "#/components/schemas/BenchmarkStatusResponse", data["$ref"])
+
+	// Embedded structs are flattened like encoding/json does
+	progress := schemas["TransformProgress"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, progress, "phase")
+	assert.Contains(t, progress, "nodes_written")
+}
+
+func TestOpenAPISpecIsValid(t *testing.T) {
+	spec := fetchSpec(t, newDocumentedRouter())
+	for _, problem := range validateOpenAPIDocument(spec) {
+		t.Error(problem)
+	}
+}
+
+var (
+	openAPIMethods = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true}
+	statusCodeKey  = regexp.MustCompile(`^([1-5][0-9X]{2}|default)$`)
+	templateParam  = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// validateOpenAPIDocument checks the rules of the OpenAPI 3.0 schema the generated spec relies
+// on: required fields, operation and response structure, declared path parameters, unique
+// operation IDs and resolvable schema references
+func validateOpenAPIDocument(spec map[string]any) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if version, _ := spec["openapi"].(string); !regexp.MustCompile(`^3\.0\.\d+$`).MatchString(version) {
+		fail("openapi must be a 3.0.x version, got %q", spec["openapi"])
+	}
+	info, _ := spec["info"].(map[string]any)
+	if title, _ := info["title"].(string); title == "" {
+		fail("info.title is required")
+	}
+	if version, _ := info["version"].(string); version == "" {
+		fail("info.version is required")
+	}
+	schemas, _ := spec["components"].(map[string]any)["schemas"].(map[string]any)
+
+	var checkSchema func(where string, schema any)
+	checkSchema = func(where string, schema any) {
+		switch value := schema.(type) {
+		case map[string]any:
+			if ref, ok := value["$ref"].(string); ok {
+				name, found := strings.CutPrefix(ref, "#/components/schemas/")
+				if _, exists := schemas[name]; !found || !exists {
+					fail("%s: unresolved reference %s", where, ref)
+				}
+				if len(value) > 1 {
+					fail("%s: $ref must not have sibling keywords", where)
+				}
+			}
+			if kind, ok := value["type"].(string); ok {
+				switch kind {
+				case "object", "array", "string", "integer", "number", "boolean":
+				default:
+					fail("%s: invalid type %q", where, kind)
+				}
+				if kind == "array" && value["items"] == nil {
+					fail("%s: array schema without items", where)
+				}
+			}
+			for key, nested := range value {
+				checkSchema(where+"."+key, nested)
+			}
+		case []any:
+			for i, nested := range value {
+				checkSchema(fmt.Sprintf("%s[%d]", where, i), nested)
+			}
+		}
+	}
+	for name, schema := range schemas {
+		checkSchema("components.schemas."+name, schema)
+	}
+
+	paths, ok := spec["paths"].(map[string]any)
+	if !ok || len(paths) == 0 {
+		fail("paths is required")
+	}
+	operationIDs := make(map[string]string)
+	for path, rawItem := range paths {
+		if !strings.HasPrefix(path, "/") {
+			fail("path %s must start with /", path)
+		}
+		item, _ := rawItem.(map[string]any)
+		for method, rawOp := range item {
+			where := method + " " + path
+			if !openAPIMethods[method] {
+				fail("%s: unknown method", where)
+				continue
+			}
+			op, _ := rawOp.(map[string]any)
+
+			id, _ := op["operationId"].(string)
+			if previous, duplicate := operationIDs[id]; duplicate {
+				fail("%s: operationId %s also used by %s", where, id, previous)
+			}
+			operationIDs[id] = where
+
+			declared := make(map[string]bool)
+			params, _ := op["parameters"].([]any)
+			for _, rawParam := range params {
+				param, _ := rawParam.(map[string]any)
+				name, _ := param["name"].(string)
+				in, _ := param["in"].(string)
+				if name == "" || (in != "path" && in != "query" && in != "header" && in != "cookie") {
+					fail("%s: parameter needs a name and a valid location", where)
+				}
+				if in == "path" {
+					declared[name] = true
+					if param["required"] != true {
+						fail("%s: path parameter %s must be required", where, name)
+					}
+				}
+				checkSchema(where+" parameter "+name, param["schema"])
+			}
+			for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+				if !declared[match[1]] {
+					fail("%s: path parameter %s is not declared", where, match[1])
+				}
+			}
+
+			if body, ok := op["requestBody"].(map[string]any); ok {
+				content, _ := body["content"].(map[string]any)
+				if len(content) == 0 {
+					fail("%s: requestBody needs content", where)
+				}
+				checkSchema(where+" requestBody", body)
+			}
+
+			responses, _ := op["responses"].(map[string]any)
+			if len(responses) == 0 {
+				fail("%s: responses are required", where)
+			}
+			for code, rawResponse := range responses {
+				if !statusCodeKey.MatchString(code) {
+					fail("%s: invalid response code %s", where, code)
+				}
+				response, _ := rawResponse.(map[string]any)
+				if description, _ := response["description"].(string); description == "" {
+					fail("%s: response %s needs a description", where, code)
+				}
+				checkSchema(where+" response "+code, response["content"])
+			}
+		}
+	}
+	return problems
+}
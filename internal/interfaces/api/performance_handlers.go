@@ -1,10 +1,15 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"sql-graph-visualizer/internal/application/ports"
@@ -22,7 +27,15 @@ type PerformanceHandlers struct {
 	performanceAnalyzer *performance.PerformanceAnalyzer
 	graphMapper         *performance.GraphPerformanceMapper
 	realtimeMonitor     *performance.RealtimePerformanceMonitor
-	psAdapter           *performance.PerformanceSchemaAdapter
+	psAdapter           ports.PerformanceCollectorPort
+	metricPrecision     int
+	diagnosticsToken    string
+	// adminScoped is set when API tokens are configured, so the scope middleware has already
+	// required an admin token for the diagnostic endpoints
+	adminScoped bool
+	baselines   *performance.BaselineStore
+	// currentMetrics collects the metrics baselines are captured from and compared with
+	currentMetrics func(ctx context.Context) (*ports.PerformanceMetrics, error)
 }
 
 // APIResponse represents a standard API response
@@ -59,6 +72,8 @@ type BenchmarkStatusResponse struct {
 	Results   interface{}            `json:"results,omitempty"`
 	Error     string                 `json:"error,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata"`
+
+	QueuePosition int `json:"queue_position,omitempty"`
 }
 
 // PerformanceDataResponse represents performance data response
@@ -86,6 +101,22 @@ type PerformanceSummary struct {
 	PerformanceRating string  `json:"performance_rating"`
 }
 
+// PollResponse represents a long-polling response for clients without WebSocket support
+type PollResponse struct {
+	Messages []*performance.WebSocketMessage `json:"messages"`
+	Cursor   time.Time                       `json:"cursor"`
+	TimedOut bool                            `json:"timed_out"`
+}
+
+// defaultMetricPrecision is the number of decimal places metrics are rounded to in responses
+const defaultMetricPrecision = 2
+
+// maxMetricPrecision caps the configurable precision; float64 carries no more useful digits
+const maxMetricPrecision = 10
+
+// defaultPollWait is how long a poll request waits for new data before returning empty
+const defaultPollWait = 25 * time.Second
+
 // NewPerformanceHandlers creates new performance API handlers
 func NewPerformanceHandlers(
 	logger *logrus.Logger,
@@ -93,16 +124,49 @@ func NewPerformanceHandlers(
 	performanceAnalyzer *performance.PerformanceAnalyzer,
 	graphMapper *performance.GraphPerformanceMapper,
 	realtimeMonitor *performance.RealtimePerformanceMonitor,
-	psAdapter *performance.PerformanceSchemaAdapter,
+	psAdapter ports.PerformanceCollectorPort,
 ) *PerformanceHandlers {
-	return &PerformanceHandlers{
+	handlers := &PerformanceHandlers{
 		logger:              logger,
 		benchmarkService:    benchmarkService,
 		performanceAnalyzer: performanceAnalyzer,
 		graphMapper:         graphMapper,
 		realtimeMonitor:     realtimeMonitor,
 		psAdapter:           psAdapter,
+		metricPrecision:     defaultMetricPrecision,
+	}
+	handlers.currentMetrics = handlers.collectCurrentMetrics
+	return handlers
+}
+
+// SetMetricPrecision sets the number of decimal places metric values are rounded to
+// when serialized. Rounding is applied to copies, so collected data keeps full precision.
+func (ph *PerformanceHandlers) SetMetricPrecision(decimals int) {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if decimals > maxMetricPrecision {
+		decimals = maxMetricPrecision
 	}
+	ph.metricPrecision = decimals
+}
+
+// SetDiagnosticsToken enables the diagnostic endpoints for requests carrying this bearer
+// token; with an empty token they are disabled
+func (ph *PerformanceHandlers) SetDiagnosticsToken(token string) {
+	ph.diagnosticsToken = token
+}
+
+// SetAPIAuthEnabled tells the handlers whether API tokens are configured. The scope
+// middleware then requires an admin token for the diagnostic endpoints, which accept it in
+// place of the diagnostics token.
+func (ph *PerformanceHandlers) SetAPIAuthEnabled(enabled bool) {
+	ph.adminScoped = enabled
+}
+
+// SetBaselineStore enables capturing named baselines and comparing the current metrics with them
+func (ph *PerformanceHandlers) SetBaselineStore(store *performance.BaselineStore) {
+	ph.baselines = store
 }
 
 // RegisterRoutes registers all performance-related routes
@@ -113,22 +177,34 @@ func (ph *PerformanceHandlers) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/api/performance/benchmarks/{id}", ph.GetBenchmark).Methods("GET")
 	router.HandleFunc("/api/performance/benchmarks/{id}/stop", ph.StopBenchmark).Methods("POST")
 	router.HandleFunc("/api/performance/benchmarks/{id}/results", ph.GetBenchmarkResults).Methods("GET")
+	router.HandleFunc("/api/performance/benchmarks/{id}/findings", ph.GetBenchmarkFindings).Methods("GET")
 
 	// Performance data endpoints
 	router.HandleFunc("/api/performance/data", ph.GetCurrentPerformanceData).Methods("GET")
 	router.HandleFunc("/api/performance/data/history", ph.GetPerformanceHistory).Methods("GET")
+	router.HandleFunc("/api/performance/data/trends", ph.GetPerformanceTrends).Methods("GET")
 	router.HandleFunc("/api/performance/data/analysis", ph.GetPerformanceAnalysis).Methods("GET")
 	router.HandleFunc("/api/performance/data/graph", ph.GetPerformanceGraph).Methods("GET")
 
+	// Baseline endpoints
+	router.HandleFunc("/api/performance/baseline", ph.CaptureBaseline).Methods("POST")
+	router.HandleFunc("/api/performance/baselines", ph.ListBaselines).Methods("GET")
+	router.HandleFunc("/api/performance/baselines/{name}/compare", ph.CompareWithBaseline).Methods("GET")
+
 	// Real-time .monitoring endpoints
 	router.HandleFunc("/api/performance/realtime/clients", ph.GetRealtimeClients).Methods("GET")
 	router.HandleFunc("/api/performance/realtime/status", ph.GetRealtimeStatus).Methods("GET")
+	router.HandleFunc("/api/performance/realtime/pause", ph.PauseRealtime).Methods("POST")
+	router.HandleFunc("/api/performance/realtime/resume", ph.ResumeRealtime).Methods("POST")
+	router.HandleFunc("/api/performance/realtime/broadcast-test", ph.BroadcastTest).Methods("POST")
 	router.HandleFunc("/ws/performance", ph.HandleWebSocket).Methods("GET")
+	router.HandleFunc("/api/performance/poll", ph.PollPerformanceUpdates).Methods("GET")
 
 	// Performance metrics endpoints
 	router.HandleFunc("/api/performance/metrics/summary", ph.GetMetricsSummary).Methods("GET")
 	router.HandleFunc("/api/performance/metrics/tables", ph.GetTableMetrics).Methods("GET")
 	router.HandleFunc("/api/performance/metrics/queries", ph.GetQueryMetrics).Methods("GET")
+	router.HandleFunc("/api/performance/tables/{table}/queries", ph.GetTableQueries).Methods("GET")
 	router.HandleFunc("/api/performance/metrics/alerts", ph.GetAlerts).Methods("GET")
 
 	// Configuration endpoints
@@ -151,27 +227,27 @@ func (ph *PerformanceHandlers) ListBenchmarks(w http.ResponseWriter, r *http.Req
 func (ph *PerformanceHandlers) StartBenchmark(w http.ResponseWriter, r *http.Request) {
 	var req BenchmarkRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_request", "Invalid JSON in request body", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_request", "Invalid JSON in request body", err.Error())
 		return
 	}
 
 	// Validate request
 	if req.BenchmarkType == "" {
-		ph.sendErrorResponse(w, http.StatusBadRequest, "validation_error", "benchmark_type is required", "")
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "validation_error", "benchmark_type is required", "")
 		return
 	}
 
 	// Create benchmark configuration from ports
 	config := ports.BenchmarkConfig{
 		TestType:     req.BenchmarkType,
-		Duration:     time.Duration(req.Duration) * time.Second,
+		Duration:     models.Duration(time.Duration(req.Duration) * time.Second),
 		CustomParams: req.Config,
 	}
 
 	// Start benchmark
 	executionID, err := ph.benchmarkService.ExecuteBenchmark(r.Context(), config, req.BenchmarkType)
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "benchmark_error", "Failed to start benchmark", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "benchmark_error", "Failed to start benchmark", err.Error())
 		return
 	}
 
@@ -185,6 +261,11 @@ func (ph *PerformanceHandlers) StartBenchmark(w http.ResponseWriter, r *http.Req
 			"duration":       req.Duration,
 		},
 	}
+	if position := ph.benchmarkService.QueuePosition(executionID); position > 0 {
+		response.Status = string(ports.BenchmarkStatusQueued)
+		response.StartTime = time.Time{}
+		response.QueuePosition = position
+	}
 
 	ph.sendJSONResponse(w, http.StatusCreated, APIResponse{
 		Success:   true,
@@ -198,14 +279,14 @@ func (ph *PerformanceHandlers) GetBenchmark(w http.ResponseWriter, r *http.Reque
 	benchmarkID := vars["id"]
 
 	if benchmarkID == "" {
-		ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
 		return
 	}
 
 	// Get benchmark status
 	status := ph.benchmarkService.GetBenchmarkStatus(r.Context(), benchmarkID)
 	if status == nil {
-		ph.sendErrorResponse(w, http.StatusNotFound, "not_found", "Benchmark not found", "")
+		sendErrorResponse(w, ph.logger, http.StatusNotFound, "not_found", "Benchmark not found", "")
 		return
 	}
 
@@ -230,6 +311,7 @@ func (ph *PerformanceHandlers) GetBenchmark(w http.ResponseWriter, r *http.Reque
 		Metadata: map[string]interface{}{
 			"config": status.Config,
 		},
+		QueuePosition: ph.benchmarkService.QueuePosition(status.ID),
 	}
 
 	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
@@ -244,13 +326,13 @@ func (ph *PerformanceHandlers) StopBenchmark(w http.ResponseWriter, r *http.Requ
 	benchmarkID := vars["id"]
 
 	if benchmarkID == "" {
-		ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
 		return
 	}
 
 	err := ph.benchmarkService.StopBenchmark(r.Context(), benchmarkID)
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "stop_error", "Failed to stop benchmark", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "stop_error", "Failed to stop benchmark", err.Error())
 		return
 	}
 
@@ -266,13 +348,13 @@ func (ph *PerformanceHandlers) GetBenchmarkResults(w http.ResponseWriter, r *htt
 	benchmarkID := vars["id"]
 
 	if benchmarkID == "" {
-		ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_id", "Benchmark ID is required", "")
 		return
 	}
 
 	results := ph.benchmarkService.GetBenchmarkResults(r.Context(), benchmarkID)
 	if results == nil {
-		ph.sendErrorResponse(w, http.StatusNotFound, "not_found", "Benchmark results not found", "")
+		sendErrorResponse(w, ph.logger, http.StatusNotFound, "not_found", "Benchmark results not found", "")
 		return
 	}
 
@@ -283,6 +365,41 @@ func (ph *PerformanceHandlers) GetBenchmarkResults(w http.ResponseWriter, r *htt
 	})
 }
 
+// GetBenchmarkFindings exports the bottlenecks and query anti-patterns found in a
+// benchmark's results as a SARIF-style findings report for code-scanning dashboards
+func (ph *PerformanceHandlers) GetBenchmarkFindings(w http.ResponseWriter, r *http.Request) {
+	benchmarkID := mux.Vars(r)["id"]
+
+	results := ph.benchmarkService.GetBenchmarkResults(r.Context(), benchmarkID)
+	if results == nil {
+		sendErrorResponse(w, ph.logger, http.StatusNotFound, "not_found", "Benchmark results not found", "")
+		return
+	}
+
+	bottlenecks, err := ph.performanceAnalyzer.IdentifyBottlenecks(r.Context(), results)
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "analysis_error", "Failed to identify bottlenecks", err.Error())
+		return
+	}
+	patterns, err := ph.performanceAnalyzer.AnalyzeQueryPatterns(r.Context(), results.QueryResults)
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "analysis_error", "Failed to analyze query patterns", err.Error())
+		return
+	}
+
+	report := performance.BuildFindingsReport(performance.FindingsInput{
+		Bottlenecks:  bottlenecks,
+		AntiPatterns: patterns.AntiPatterns,
+	})
+
+	w.Header().Set("Content-Type", "application/sarif+json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=findings-%s.sarif", benchmarkID))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		ph.logger.WithError(err).Error("Failed to encode findings report")
+	}
+}
+
 // Performance data handlers
 
 func (ph *PerformanceHandlers) GetCurrentPerformanceData(w http.ResponseWriter, r *http.Request) {
@@ -292,7 +409,7 @@ func (ph *PerformanceHandlers) GetCurrentPerformanceData(w http.ResponseWriter,
 	// Collect current performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
@@ -346,7 +463,7 @@ func (ph *PerformanceHandlers) GetPerformanceHistory(w http.ResponseWriter, r *h
 	if startTimeStr != "" {
 		startTime, err = time.Parse(time.RFC3339, startTimeStr)
 		if err != nil {
-			ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_time", "Invalid start_time format", "Use RFC3339 format")
+			sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_time", "Invalid start_time format", "Use RFC3339 format")
 			return
 		}
 	} else {
@@ -356,45 +473,109 @@ func (ph *PerformanceHandlers) GetPerformanceHistory(w http.ResponseWriter, r *h
 	if endTimeStr != "" {
 		endTime, err = time.Parse(time.RFC3339, endTimeStr)
 		if err != nil {
-			ph.sendErrorResponse(w, http.StatusBadRequest, "invalid_time", "Invalid end_time format", "Use RFC3339 format")
+			sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_time", "Invalid end_time format", "Use RFC3339 format")
 			return
 		}
 	} else {
 		endTime = time.Now()
 	}
 
-	limit := 100 // Default limit
+	window, err := performance.ParseHistoryWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_window", "Invalid window parameter", err.Error())
+		return
+	}
+
+	// Raw samples are capped by default; aggregated buckets are already bounded by the window
+	limit := 0
+	if window == 0 {
+		limit = 100
+	}
 	if limitStr != "" {
 		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
 			limit = parsedLimit
 		}
 	}
 
-	// TODO: Implement historical data collection
-	// This would typically query a time-series database or historical storage
-	historyData := []interface{}{
-		map[string]interface{}{
-			"message": "Historical data collection not yet implemented",
-			"params": map[string]interface{}{
-				"start_time": startTime,
-				"end_time":   endTime,
-				"limit":      limit,
-			},
-		},
+	samples := ph.realtimeMonitor.MetricsHistory(startTime, endTime)
+	response := HistoryResponse{
+		Window:    r.URL.Query().Get("window"),
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	if window == 0 {
+		response.Window = "raw"
+		response.Samples = lastN(samples, limit)
+	} else {
+		response.Buckets = lastN(performance.AggregateHistory(samples, window), limit)
 	}
 
 	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
 		Success:   true,
-		Data:      historyData,
+		Data:      response,
 		Timestamp: time.Now(),
 	})
 }
 
+// GetPerformanceTrends runs trend analysis over the metrics history, including samples
+// backfilled from the history file on startup
+func (ph *PerformanceHandlers) GetPerformanceTrends(w http.ResponseWriter, r *http.Request) {
+	if ph.performanceAnalyzer == nil || ph.realtimeMonitor == nil {
+		sendErrorResponse(w, ph.logger, http.StatusServiceUnavailable, "trends_unavailable", "Trend analysis is not available", "")
+		return
+	}
+
+	var startTime time.Time
+	endTime := time.Now()
+	for param, target := range map[string]*time.Time{"start_time": &startTime, "end_time": &endTime} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_time", "Invalid "+param+" format", "Use RFC3339 format")
+			return
+		}
+		*target = parsed
+	}
+
+	snapshots := ph.realtimeMonitor.TrendSnapshots(startTime, endTime)
+	analysis, err := ph.performanceAnalyzer.AnalyzeTrends(r.Context(), snapshots)
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusUnprocessableEntity, "insufficient_data", "Not enough metrics history for trend analysis", err.Error())
+		return
+	}
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      analysis,
+		Timestamp: time.Now(),
+	})
+}
+
+// HistoryResponse carries either raw metrics samples or time-bucketed aggregates
+type HistoryResponse struct {
+	Window    string                      `json:"window"`
+	StartTime time.Time                   `json:"start_time"`
+	EndTime   time.Time                   `json:"end_time"`
+	Samples   []performance.MetricsSample `json:"samples,omitempty"`
+	Buckets   []performance.HistoryBucket `json:"buckets,omitempty"`
+}
+
+// lastN keeps the most recent limit items; zero keeps everything
+func lastN[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[len(items)-limit:]
+	}
+	return items
+}
+
 func (ph *PerformanceHandlers) GetPerformanceAnalysis(w http.ResponseWriter, r *http.Request) {
 	// Collect current performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
@@ -416,21 +597,21 @@ func (ph *PerformanceHandlers) GetPerformanceGraph(w http.ResponseWriter, r *htt
 	// Collect performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
 	// TODO: Get base graph from graph service
 	var baseGraph *models.Graph
 	if baseGraph == nil {
-		ph.sendErrorResponse(w, http.StatusServiceUnavailable, "graph_unavailable", "Base graph is not available", "")
+		sendErrorResponse(w, ph.logger, http.StatusServiceUnavailable, "graph_unavailable", "Base graph is not available", "")
 		return
 	}
 
 	// Map performance data to graph
 	graphData, err := ph.graphMapper.MapPerformanceToGraph(r.Context(), baseGraph, perfData)
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "mapping_error", "Failed to map performance to graph", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "mapping_error", "Failed to map performance to graph", err.Error())
 		return
 	}
 
@@ -441,6 +622,120 @@ func (ph *PerformanceHandlers) GetPerformanceGraph(w http.ResponseWriter, r *htt
 	})
 }
 
+// Baseline handlers
+
+// CaptureBaselineRequest names the baseline the current metrics are stored as
+type CaptureBaselineRequest struct {
+	Name string `json:"name"`
+}
+
+func (ph *PerformanceHandlers) CaptureBaseline(w http.ResponseWriter, r *http.Request) {
+	if ph.baselines == nil {
+		sendErrorResponse(w, ph.logger, http.StatusServiceUnavailable, "baselines_unavailable", "Baseline storage is not configured", "")
+		return
+	}
+
+	var req CaptureBaselineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_request", "Invalid JSON in request body", err.Error())
+		return
+	}
+	if err := performance.ValidateBaselineName(req.Name); err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_name", "Invalid baseline name", err.Error())
+		return
+	}
+
+	metrics, err := ph.currentMetrics(r.Context())
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		return
+	}
+
+	baseline, err := ph.baselines.Save(req.Name, metrics)
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "baseline_error", "Failed to store baseline", err.Error())
+		return
+	}
+
+	ph.sendJSONResponse(w, http.StatusCreated, APIResponse{
+		Success:   true,
+		Data:      baseline,
+		Timestamp: time.Now(),
+	})
+}
+
+func (ph *PerformanceHandlers) ListBaselines(w http.ResponseWriter, r *http.Request) {
+	if ph.baselines == nil {
+		sendErrorResponse(w, ph.logger, http.StatusServiceUnavailable, "baselines_unavailable", "Baseline storage is not configured", "")
+		return
+	}
+
+	baselines, err := ph.baselines.List()
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "baseline_error", "Failed to list baselines", err.Error())
+		return
+	}
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      baselines,
+		Timestamp: time.Now(),
+	})
+}
+
+func (ph *PerformanceHandlers) CompareWithBaseline(w http.ResponseWriter, r *http.Request) {
+	if ph.baselines == nil || ph.performanceAnalyzer == nil {
+		sendErrorResponse(w, ph.logger, http.StatusServiceUnavailable, "baselines_unavailable", "Baseline storage is not configured", "")
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	if err := performance.ValidateBaselineName(name); err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_name", "Invalid baseline name", err.Error())
+		return
+	}
+
+	baseline, err := ph.baselines.Get(name)
+	if err != nil {
+		if errors.Is(err, performance.ErrBaselineNotFound) {
+			sendErrorResponse(w, ph.logger, http.StatusNotFound, "not_found", "Baseline not found", err.Error())
+			return
+		}
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "baseline_error", "Failed to load baseline", err.Error())
+		return
+	}
+
+	metrics, err := ph.currentMetrics(r.Context())
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		return
+	}
+
+	comparison, err := ph.performanceAnalyzer.CompareWithBaseline(r.Context(), baseline, metrics)
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "comparison_error", "Failed to compare with baseline", err.Error())
+		return
+	}
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      comparison,
+		Timestamp: time.Now(),
+	})
+}
+
+// collectCurrentMetrics collects Performance Schema data and aggregates it into metrics
+func (ph *PerformanceHandlers) collectCurrentMetrics(ctx context.Context) (*ports.PerformanceMetrics, error) {
+	if ph.psAdapter == nil {
+		return nil, fmt.Errorf("performance schema collection is not available")
+	}
+	data, err := ph.psAdapter.CollectPerformanceData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ph.psAdapter.ConvertToPerformanceMetrics(data), nil
+}
+
 // Real-time .monitoring handlers
 
 func (ph *PerformanceHandlers) GetRealtimeClients(w http.ResponseWriter, r *http.Request) {
@@ -457,15 +752,21 @@ func (ph *PerformanceHandlers) GetRealtimeStatus(w http.ResponseWriter, r *http.
 	clients := ph.realtimeMonitor.GetConnectedClients()
 	lastGraphData := ph.realtimeMonitor.GetLastGraphData()
 
+	paused := ph.realtimeMonitor.IsPaused()
+
 	status := map[string]interface{}{
 		"connected_clients": len(clients),
 		"last_update":       nil,
-		"monitoring_active": true,
+		"monitoring_active": !paused,
+		"paused":            paused,
 	}
 
 	if lastGraphData != nil {
 		status["last_update"] = lastGraphData.GeneratedAt
 	}
+	if paused {
+		status["paused_at"] = ph.realtimeMonitor.PausedSince()
+	}
 
 	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
 		Success:   true,
@@ -474,17 +775,197 @@ func (ph *PerformanceHandlers) GetRealtimeStatus(w http.ResponseWriter, r *http.
 	})
 }
 
+// PauseRealtime halts Performance Schema polling while keeping WebSocket clients connected
+func (ph *PerformanceHandlers) PauseRealtime(w http.ResponseWriter, r *http.Request) {
+	changed := ph.realtimeMonitor.Pause()
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"paused":    true,
+			"changed":   changed,
+			"paused_at": ph.realtimeMonitor.PausedSince(),
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// ResumeRealtime restarts Performance Schema polling after a pause
+func (ph *PerformanceHandlers) ResumeRealtime(w http.ResponseWriter, r *http.Request) {
+	changed := ph.realtimeMonitor.Resume()
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"paused":  false,
+			"changed": changed,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// BroadcastTestRequest selects the topic of a synthetic diagnostic broadcast
+type BroadcastTestRequest struct {
+	Topic     string `json:"topic,omitempty"`      // "performance" (default) or "alerts"
+	TableName string `json:"table_name,omitempty"` // table the synthetic node or alert refers to
+}
+
+// BroadcastTestResponse identifies the injected message so clients can look for it
+type BroadcastTestResponse struct {
+	MessageID  string `json:"message_id"`
+	Topic      string `json:"topic"`
+	Recipients int    `json:"recipients"`
+}
+
+// authorizeDiagnostics checks the diagnostics token unless an admin API token was already
+// required, answering the request when it is not authorized
+func (ph *PerformanceHandlers) authorizeDiagnostics(w http.ResponseWriter, r *http.Request) bool {
+	if ph.adminScoped {
+		return true
+	}
+	if ph.diagnosticsToken == "" {
+		sendErrorResponse(w, ph.logger, http.StatusForbidden, "DIAGNOSTICS_DISABLED", "Diagnostic endpoints are disabled", "Set performance.diagnostics_token or api.auth tokens to enable them")
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(ph.diagnosticsToken)) != 1 {
+		sendErrorResponse(w, ph.logger, http.StatusUnauthorized, "UNAUTHORIZED", "A valid diagnostics token is required", "")
+		return false
+	}
+	return true
+}
+
+// broadcastTestPrefix marks synthetic graph data and alerts so dashboards can tell them apart
+const broadcastTestPrefix = "broadcast-test"
+
+// BroadcastTest injects a synthetic graph update or alert into the real-time broadcast path,
+// so client connectivity and topic filtering can be checked without waiting for real data
+func (ph *PerformanceHandlers) BroadcastTest(w http.ResponseWriter, r *http.Request) {
+	if !ph.authorizeDiagnostics(w, r) {
+		return
+	}
+
+	var req BroadcastTestRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendErrorResponse(w, ph.logger, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+			return
+		}
+	}
+	if req.Topic == "" {
+		req.Topic = "performance"
+	}
+	if req.TableName == "" {
+		req.TableName = broadcastTestPrefix
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("%s-%d", broadcastTestPrefix, now.UnixNano())
+
+	var data interface{}
+	switch req.Topic {
+	case "performance":
+		data = &performance.PerformanceGraphData{
+			ID:          id,
+			GeneratedAt: now,
+			Nodes: []performance.PerformanceGraphNode{
+				{ID: id + "-node", TableName: req.TableName, Label: req.TableName},
+			},
+		}
+	case "alerts":
+		data = &performance.PerformanceAlert{
+			ID:          id,
+			Type:        broadcastTestPrefix,
+			Severity:    "info",
+			Title:       "Broadcast test",
+			Description: "Synthetic alert injected to verify real-time delivery",
+			TableName:   req.TableName,
+			Timestamp:   now,
+		}
+	default:
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "INVALID_TOPIC", "Topic must be performance or alerts", req.Topic)
+		return
+	}
+
+	messageID, recipients := ph.realtimeMonitor.Broadcast(req.Topic, data)
+	ph.logger.WithFields(logrus.Fields{
+		"message_id": messageID,
+		"topic":      req.Topic,
+		"recipients": recipients,
+	}).Info("Diagnostic broadcast sent")
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success: true,
+		Data: BroadcastTestResponse{
+			MessageID:  messageID,
+			Topic:      req.Topic,
+			Recipients: recipients,
+		},
+		Timestamp: now,
+	})
+}
+
 func (ph *PerformanceHandlers) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	ph.realtimeMonitor.HandleWebSocket(w, r)
 }
 
+// PollPerformanceUpdates is a long-polling fallback for clients whose WebSocket upgrade
+// fails (e.g. behind proxies). It returns the same messages the WebSocket broadcasts.
+func (ph *PerformanceHandlers) PollPerformanceUpdates(w http.ResponseWriter, r *http.Request) {
+	since, err := parsePollCursor(r.URL.Query().Get("since"))
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_cursor", "Invalid since parameter", "Use RFC3339 format or unix milliseconds")
+		return
+	}
+
+	wait := defaultPollWait
+	if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+		seconds, err := strconv.Atoi(timeoutStr)
+		if err != nil || seconds < 0 {
+			sendErrorResponse(w, ph.logger, http.StatusBadRequest, "invalid_timeout", "Invalid timeout parameter", "Use a non-negative number of seconds")
+			return
+		}
+		wait = time.Duration(seconds) * time.Second
+	}
+
+	messages := ph.realtimeMonitor.WaitForMessages(r.Context(), since, wait)
+
+	// Polling clients cannot authenticate, so they see what anonymous WebSocket clients see;
+	// the cursor still advances past messages they may not see
+	response := PollResponse{
+		Messages: ph.realtimeMonitor.FilterForAnonymous(messages),
+		Cursor:   since,
+		TimedOut: len(messages) == 0,
+	}
+	if len(messages) > 0 {
+		response.Cursor = messages[len(messages)-1].Timestamp
+	}
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      response,
+		Timestamp: time.Now(),
+	})
+}
+
+// parsePollCursor accepts an RFC3339 timestamp or unix milliseconds; empty means from the beginning
+func parsePollCursor(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
+
 // Metrics handlers
 
 func (ph *PerformanceHandlers) GetMetricsSummary(w http.ResponseWriter, r *http.Request) {
 	// Collect current performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
@@ -501,7 +982,7 @@ func (ph *PerformanceHandlers) GetTableMetrics(w http.ResponseWriter, r *http.Re
 	// Collect current performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
@@ -523,7 +1004,7 @@ func (ph *PerformanceHandlers) GetQueryMetrics(w http.ResponseWriter, r *http.Re
 	// Collect current performance data
 	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
 	if err != nil {
-		ph.sendErrorResponse(w, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
 		return
 	}
 
@@ -540,6 +1021,44 @@ func (ph *PerformanceHandlers) GetQueryMetrics(w http.ResponseWriter, r *http.Re
 	})
 }
 
+// TableQueriesResponse lists the statements referencing one table, highest impact first
+type TableQueriesResponse struct {
+	Table   string                           `json:"table"`
+	Total   int                              `json:"total"`
+	Queries []performance.StatementStatistic `json:"queries"`
+}
+
+// GetTableQueries drills down from a table to the collected statements that reference it.
+// The table may be schema-qualified; limit (default 50) caps the statements returned.
+func (ph *PerformanceHandlers) GetTableQueries(w http.ResponseWriter, r *http.Request) {
+	table := mux.Vars(r)["table"]
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	perfData, err := ph.psAdapter.CollectPerformanceData(r.Context())
+	if err != nil {
+		sendErrorResponse(w, ph.logger, http.StatusInternalServerError, "collection_error", "Failed to collect performance data", err.Error())
+		return
+	}
+
+	queries := performance.StatementsForTable(perfData.StatementStats, table)
+	response := TableQueriesResponse{Table: table, Total: len(queries), Queries: queries}
+	if len(response.Queries) > limit {
+		response.Queries = response.Queries[:limit]
+	}
+
+	ph.sendJSONResponse(w, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      response,
+		Timestamp: time.Now(),
+	})
+}
+
 func (ph *PerformanceHandlers) GetAlerts(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement alerts retrieval
 	// This would typically query an alerts storage system
@@ -631,32 +1150,70 @@ func (ph *PerformanceHandlers) generatePerformanceSummary(perfData *performance.
 	}
 }
 
-func (ph *PerformanceHandlers) sendJSONResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// roundMetrics returns a copy of known metric payloads with float values rounded to the
+// configured precision; other payloads are returned unchanged
+func (ph *PerformanceHandlers) roundMetrics(data interface{}) interface{} {
+	decimals := ph.metricPrecision
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		ph.logger.WithError(err).Error("Failed to encode JSON response")
+	switch v := data.(type) {
+	case *PerformanceSummary:
+		if v == nil {
+			return v
+		}
+		rounded := *v
+		rounded.AverageLatency = roundMetric(v.AverageLatency, decimals)
+		rounded.QueriesPerSecond = roundMetric(v.QueriesPerSecond, decimals)
+		rounded.ErrorRate = roundMetric(v.ErrorRate, decimals)
+		return &rounded
+	case *PerformanceDataResponse:
+		if v == nil {
+			return v
+		}
+		rounded := *v
+		rounded.Summary, _ = ph.roundMetrics(v.Summary).(*PerformanceSummary)
+		return &rounded
+	case *ports.PerformanceMetrics:
+		if v == nil {
+			return v
+		}
+		rounded := *v
+		rounded.QueriesPerSecond = roundMetric(v.QueriesPerSecond, decimals)
+		rounded.TransactionsPerSec = roundMetric(v.TransactionsPerSec, decimals)
+		rounded.ReadQPS = roundMetric(v.ReadQPS, decimals)
+		rounded.WriteQPS = roundMetric(v.WriteQPS, decimals)
+		rounded.AverageLatency = roundMetric(v.AverageLatency, decimals)
+		rounded.MinLatency = roundMetric(v.MinLatency, decimals)
+		rounded.MaxLatency = roundMetric(v.MaxLatency, decimals)
+		rounded.Percentile95 = roundMetric(v.Percentile95, decimals)
+		rounded.Percentile99 = roundMetric(v.Percentile99, decimals)
+		rounded.ErrorRate = roundMetric(v.ErrorRate, decimals)
+		return &rounded
+	case *ports.BenchmarkResult:
+		if v == nil {
+			return v
+		}
+		rounded := *v
+		rounded.Metrics, _ = ph.roundMetrics(v.Metrics).(*ports.PerformanceMetrics)
+		return &rounded
+	case BenchmarkStatusResponse:
+		v.Progress = roundMetric(v.Progress, decimals)
+		v.Results = ph.roundMetrics(v.Results)
+		return v
+	default:
+		return data
 	}
 }
 
-func (ph *PerformanceHandlers) sendErrorResponse(w http.ResponseWriter, statusCode int, code, message, details string) {
-	response := APIResponse{
-		Success: false,
-		Error: &APIError{
-			Code:    code,
-			Message: message,
-			Details: details,
-		},
-		Timestamp: time.Now(),
+func roundMetric(value float64, decimals int) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
 	}
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
 
-	ph.sendJSONResponse(w, statusCode, response)
-
-	ph.logger.WithFields(logrus.Fields{
-		"status_code": statusCode,
-		"error_code":  code,
-		"message":     message,
-		"details":     details,
-	}).Warn("API error response sent")
+// sendJSONResponse rounds the metrics in the response to the configured precision before sending it
+func (ph *PerformanceHandlers) sendJSONResponse(w http.ResponseWriter, statusCode int, response APIResponse) {
+	response.Data = ph.roundMetrics(response.Data)
+	sendJSONResponse(w, ph.logger, statusCode, response)
 }
@@ -0,0 +1,449 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/ports"
+	"sql-graph-visualizer/internal/application/services/performance"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandlers() (*PerformanceHandlers, *mux.Router) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	monitor := performance.NewRealtimePerformanceMonitor(logger, nil, nil, nil, nil)
+	handlers := NewPerformanceHandlers(logger, nil, nil, nil, monitor, nil)
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	return handlers, router
+}
+
+func TestPollPerformanceUpdatesTimesOutWithCursor(t *testing.T) {
+	_, router := newTestHandlers()
+
+	since := time.Now().UTC().Truncate(time.Millisecond)
+	req := httptest.NewRequest(http.MethodGet, "/api/performance/poll?timeout=0&since="+since.Format(time.RFC3339Nano), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Success bool         `json:"success"`
+		Data    PollResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.Success)
+	assert.True(t, response.Data.TimedOut)
+	assert.Empty(t, response.Data.Messages)
+	assert.True(t, since.Equal(response.Data.Cursor))
+}
+
+func TestPollPerformanceUpdatesRejectsInvalidCursor(t *testing.T) {
+	_, router := newTestHandlers()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/performance/poll?since=yesterday", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestParsePollCursor(t *testing.T) {
+	cursor, err := parsePollCursor("")
+	require.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+
+	cursor, err = parsePollCursor("1700000000000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000000), cursor.UnixMilli())
+
+	cursor, err = parsePollCursor("2025-01-02T03:04:05.123456789Z")
+	require.NoError(t, err)
+	assert.Equal(t, 123456789, cursor.Nanosecond())
+}
+
+func TestSendJSONResponseRoundsMetrics(t *testing.T) {
+	handlers, _ := newTestHandlers()
+
+	summary := &PerformanceSummary{
+		TotalQueries:     3,
+		AverageLatency:   14.333333333333,
+		QueriesPerSecond: 0.016666666666,
+		ErrorRate:        33.333333333333,
+	}
+
+	tests := []struct {
+		name      string
+		precision *int
+		latency   float64
+		qps       float64
+	}{
+		{name: "default precision", latency: 14.33, qps: 0.02},
+		{name: "one decimal", precision: intPtr(1), latency: 14.3, qps: 0},
+		{name: "four decimals", precision: intPtr(4), latency: 14.3333, qps: 0.0167},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers.metricPrecision = defaultMetricPrecision
+			if tt.precision != nil {
+				handlers.SetMetricPrecision(*tt.precision)
+			}
+
+			rec := httptest.NewRecorder()
+			handlers.sendJSONResponse(rec, http.StatusOK, APIResponse{Success: true, Data: summary})
+
+			var response struct {
+				Data PerformanceSummary `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			assert.Equal(t, tt.latency, response.Data.AverageLatency)
+			assert.Equal(t, tt.qps, response.Data.QueriesPerSecond)
+		})
+	}
+
+	// Rounding happens on a copy; the summary itself keeps full precision
+	assert.Equal(t, 14.333333333333, summary.AverageLatency)
+	assert.Equal(t, 0.016666666666, summary.QueriesPerSecond)
+}
+
+func TestSendJSONResponseRoundsBenchmarkMetrics(t *testing.T) {
+	handlers, _ := newTestHandlers()
+
+	result := &ports.BenchmarkResult{
+		ID:      "bench-1",
+		Metrics: &ports.PerformanceMetrics{AverageLatency: 2.718281828, Percentile99: 9.87654321, RowsRead: 42},
+	}
+
+	rec := httptest.NewRecorder()
+	handlers.sendJSONResponse(rec, http.StatusOK, APIResponse{Success: true, Data: result})
+
+	var response struct {
+		Data struct {
+			Metrics map[string]float64 `json:"metrics"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 2.72, response.Data.Metrics["average_latency"])
+	assert.Equal(t, 9.88, response.Data.Metrics["percentile_99"])
+	assert.Equal(t, float64(42), response.Data.Metrics["rows_read"])
+	assert.Equal(t, 2.718281828, result.Metrics.AverageLatency)
+}
+
+func TestSendJSONResponseEncodesDurationsAsStrings(t *testing.T) {
+	handlers, _ := newTestHandlers()
+
+	rec := httptest.NewRecorder()
+	handlers.sendJSONResponse(rec, http.StatusOK, APIResponse{Success: true, Data: BenchmarkStatusResponse{
+		ID: "bench-1",
+		Results: &ports.BenchmarkResult{
+			ID:       "bench-1",
+			Duration: models.Duration(90 * time.Second),
+			QueryResults: []ports.QueryPerformance{
+				{QueryPattern: "SELECT ?", TotalTime: models.Duration(1500 * time.Millisecond), AverageTime: models.Duration(1500 * time.Microsecond)},
+			},
+		},
+	}})
+
+	var response struct {
+		Data struct {
+			Results struct {
+				Duration     any              `json:"duration"`
+				QueryResults []map[string]any `json:"query_results"`
+			} `json:"results"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "1m30s", response.Data.Results.Duration)
+	require.Len(t, response.Data.Results.QueryResults, 1)
+	assert.Equal(t, "1.5s", response.Data.Results.QueryResults[0]["total_time"])
+	assert.Equal(t, "1.5ms", response.Data.Results.QueryResults[0]["average_time"])
+	assert.Equal(t, "0s", response.Data.Results.QueryResults[0]["min_time"])
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestPauseAndResumeRealtime(t *testing.T) {
+	handlers, router := newTestHandlers()
+
+	status := func() map[string]interface{} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/realtime/status", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return response.Data
+	}
+
+	assert.Equal(t, false, status()["paused"])
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/realtime/pause", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, handlers.realtimeMonitor.IsPaused())
+
+	paused := status()
+	assert.Equal(t, true, paused["paused"])
+	assert.Equal(t, false, paused["monitoring_active"])
+	assert.Contains(t, paused, "paused_at")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/realtime/resume", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, handlers.realtimeMonitor.IsPaused())
+	assert.Equal(t, true, status()["monitoring_active"])
+}
+
+func TestBroadcastTestReachesConnectedClient(t *testing.T) {
+	handlers, router := newTestHandlers()
+	handlers.SetDiagnosticsToken("secret")
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/performance", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool {
+		return len(handlers.realtimeMonitor.GetConnectedClients()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/performance/realtime/broadcast-test", strings.NewReader(`{"topic":"alerts","table_name":"orders"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data BroadcastTestResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "alerts", response.Data.Topic)
+	assert.Equal(t, 1, response.Data.Recipients)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var message struct {
+		ID    string                       `json:"id"`
+		Topic string                       `json:"topic"`
+		Data  performance.PerformanceAlert `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, response.Data.MessageID, message.ID)
+	assert.Equal(t, "alerts", message.Topic)
+	assert.Equal(t, "broadcast-test", message.Data.Type)
+	assert.Equal(t, "orders", message.Data.TableName)
+}
+
+func TestBroadcastTestRequiresToken(t *testing.T) {
+	handlers, router := newTestHandlers()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/realtime/broadcast-test", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code, "disabled without a configured token")
+
+	handlers.SetDiagnosticsToken("secret")
+	req := httptest.NewRequest(http.MethodPost, "/api/performance/realtime/broadcast-test", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestBroadcastTestWithAPIAuth(t *testing.T) {
+	handlers, router := newTestHandlers()
+	handlers.SetDiagnosticsToken("diagnostics")
+	handlers.SetAPIAuthEnabled(true)
+	router.Use(NewScopeMiddleware(handlers.logger, APIAuthConfig{
+		ReadTokens:  []string{"reader"},
+		AdminTokens: []string{"admin"},
+	}))
+
+	broadcast := func(token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/performance/realtime/broadcast-test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, broadcast("admin"), "an admin token is enough once API auth is on")
+	assert.Equal(t, http.StatusForbidden, broadcast("reader"))
+	assert.Equal(t, http.StatusUnauthorized, broadcast("diagnostics"), "the diagnostics token is not an API token")
+}
+
+func TestGetPerformanceHistoryWindow(t *testing.T) {
+	_, router := newTestHandlers()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/data/history?window=2m", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/data/history?window=5m", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data HistoryResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "5m", response.Data.Window)
+	assert.Empty(t, response.Data.Buckets)
+}
+
+func TestGetPerformanceTrendsUsesBackfilledHistory(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	store := performance.NewMetricsHistoryStore(path)
+	for i := 10; i > 0; i-- {
+		require.NoError(t, store.Append(performance.MetricsSample{Timestamp: time.Now().Add(-time.Duration(i) * time.Minute)}))
+	}
+
+	trends := func(backfillWindow time.Duration) int {
+		monitor := performance.NewRealtimePerformanceMonitor(logger, &performance.RealtimeMonitorConfig{
+			DataUpdateInterval: time.Hour,
+			HistoryFile:        path,
+			BackfillWindow:     backfillWindow,
+		}, nil, nil, nil)
+		require.NoError(t, monitor.Start(context.Background()))
+		defer monitor.Stop()
+
+		router := mux.NewRouter()
+		NewPerformanceHandlers(logger, nil, performance.NewPerformanceAnalyzer(logger, nil), nil, monitor, nil).RegisterRoutes(router)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/data/trends", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnprocessableEntity, trends(0), "no history right after startup without backfill")
+	assert.Equal(t, http.StatusOK, trends(time.Hour))
+}
+
+func newBaselineTestHandlers(t *testing.T, current *ports.PerformanceMetrics) *mux.Router {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	store, err := performance.NewBaselineStore(t.TempDir())
+	require.NoError(t, err)
+
+	handlers := NewPerformanceHandlers(logger, nil, performance.NewPerformanceAnalyzer(logger, nil), nil, nil, nil)
+	handlers.SetBaselineStore(store)
+	handlers.currentMetrics = func(context.Context) (*ports.PerformanceMetrics, error) {
+		return current, nil
+	}
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	return router
+}
+
+func TestCaptureBaselineStoresCurrentMetrics(t *testing.T) {
+	router := newBaselineTestHandlers(t, &ports.PerformanceMetrics{QueriesPerSecond: 200, AverageLatency: 5})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/baseline", strings.NewReader(`{"name":"v1.4"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/baselines", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data []performance.PerformanceBaseline `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "v1.4", response.Data[0].Name)
+	assert.Equal(t, 200.0, response.Data[0].Metrics.QueriesPerSecond)
+}
+
+func TestCaptureBaselineRejectsInvalidName(t *testing.T) {
+	router := newBaselineTestHandlers(t, &ports.PerformanceMetrics{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/baseline", strings.NewReader(`{"name":"../v1"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCompareWithBaselineReturnsComparison(t *testing.T) {
+	current := &ports.PerformanceMetrics{QueriesPerSecond: 200, AverageLatency: 5}
+	router := newBaselineTestHandlers(t, current)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/performance/baseline", strings.NewReader(`{"name":"v1.4"}`)))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	// Throughput halves and latency doubles after the baseline was captured
+	*current = ports.PerformanceMetrics{QueriesPerSecond: 100, AverageLatency: 10}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/baselines/v1.4/compare", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response struct {
+		Data performance.BaselineComparison `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.NotNil(t, response.Data.Comparison)
+	assert.Equal(t, 200.0, response.Data.Baseline.Metrics.QueriesPerSecond)
+	assert.Equal(t, 100.0, response.Data.Current.QueriesPerSecond)
+	assert.Less(t, response.Data.Comparison.Improvement, 0.0)
+	assert.Len(t, response.Data.Regressions, 2)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/baselines/missing/compare", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// fakeCollector serves fixed performance data through the collector port
+type fakeCollector struct {
+	ports.PerformanceCollectorPort
+	data *performance.PerformanceSchemaData
+}
+
+func (f *fakeCollector) CollectPerformanceData(ctx context.Context) (*performance.PerformanceSchemaData, error) {
+	return f.data, nil
+}
+
+func TestTableMetricsUseInjectedCollector(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	collector := &fakeCollector{data: &performance.PerformanceSchemaData{
+		TableIOStats: []performance.TableIOStatistic{{SchemaName: "public", TableName: "orders", CountRead: 900}},
+	}}
+	router := mux.NewRouter()
+	NewPerformanceHandlers(logger, nil, nil, nil, nil, collector).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/performance/metrics/tables", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []performance.TableIOStatistic `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, collector.data.TableIOStats, body.Data)
+}
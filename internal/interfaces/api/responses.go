@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sendJSONResponse writes response as JSON with the given status code
+func sendJSONResponse(w http.ResponseWriter, logger *logrus.Logger, statusCode int, response APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.WithError(err).Error("Failed to encode JSON response")
+	}
+}
+
+// sendErrorResponse writes a failed APIResponse carrying the error and logs it
+func sendErrorResponse(w http.ResponseWriter, logger *logrus.Logger, statusCode int, code, message, details string) {
+	sendJSONResponse(w, logger, statusCode, APIResponse{
+		Success: false,
+		Error: &APIError{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Timestamp: time.Now(),
+	})
+
+	logger.WithFields(logrus.Fields{
+		"status_code": statusCode,
+		"error_code":  code,
+		"message":     message,
+		"details":     details,
+	}).Warn("API error response sent")
+}
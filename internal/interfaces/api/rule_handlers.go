@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/transform"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repositories/config"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRuleBundleSize bounds the body of a rule bundle import
+const maxRuleBundleSize = 4 << 20
+
+// Sample sizes for rule tests
+const (
+	defaultRulePreviewRows = 10
+	maxRulePreviewRows     = 1000
+)
+
+// SchemaColumnsFunc reads the table and column names of the connected database
+type SchemaColumnsFunc func(ctx context.Context) (map[string][]string, error)
+
+// RuleImportResult describes a validated rule bundle import
+type RuleImportResult struct {
+	Rules             int    `json:"rules"`
+	SchemaFingerprint string `json:"schema_fingerprint"`
+	// FingerprintMatches reports whether the bundle was exported from the same schema layout
+	FingerprintMatches bool `json:"fingerprint_matches"`
+	// File is the rule file the bundle was written to; empty when no import directory is set
+	File string `json:"file,omitempty"`
+}
+
+// RulePreviewer runs a single rule against sample source rows without storing the result
+type RulePreviewer interface {
+	PreviewRule(ctx context.Context, name string, limit int) (*transform.RulePreview, error)
+}
+
+// RuleHandlers contains HTTP handlers for sharing transform rule sets as bundles
+type RuleHandlers struct {
+	logger    *logrus.Logger
+	rules     []models.TransformationConfig
+	dbType    models.DatabaseType
+	schema    SchemaColumnsFunc
+	importDir string
+	previewer RulePreviewer
+}
+
+// NewRuleHandlers creates new rule bundle handlers for the given rule set
+func NewRuleHandlers(logger *logrus.Logger, rules []models.TransformationConfig, dbType models.DatabaseType, schema SchemaColumnsFunc) *RuleHandlers {
+	return &RuleHandlers{
+		logger: logger,
+		rules:  rules,
+		dbType: dbType,
+		schema: schema,
+	}
+}
+
+// SetImportDir sets the directory imported bundles are written to, normally the
+// transform_rules_dir. Without it imports are only validated.
+func (rh *RuleHandlers) SetImportDir(dir string) {
+	rh.importDir = dir
+}
+
+// SetPreviewer enables POST /api/rules/{id}/test, which runs one rule through previewer
+func (rh *RuleHandlers) SetPreviewer(previewer RulePreviewer) {
+	rh.previewer = previewer
+}
+
+// RegisterRoutes registers all rule bundle routes
+func (rh *RuleHandlers) RegisterRoutes(router *mux.Router) {
+	api := router.PathPrefix("/api/rules").Subrouter()
+
+	api.HandleFunc("/export", rh.ExportRules).Methods("GET")
+	api.HandleFunc("/import", rh.ImportRules).Methods("POST")
+	if rh.previewer != nil {
+		api.HandleFunc("/{id}/test", rh.TestRule).Methods("POST")
+	}
+}
+
+// TestRule runs the rule named by {id} against at most ?limit= source rows and returns
+// the nodes or relationships it would produce; nothing is written to Neo4j
+func (rh *RuleHandlers) TestRule(w http.ResponseWriter, r *http.Request) {
+	limit := defaultRulePreviewRows
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 || parsedLimit > maxRulePreviewRows {
+			sendErrorResponse(w, rh.logger, http.StatusBadRequest, "INVALID_LIMIT",
+				fmt.Sprintf("limit must be between 1 and %d", maxRulePreviewRows), limitStr)
+			return
+		}
+		limit = parsedLimit
+	}
+
+	preview, err := rh.previewer.PreviewRule(r.Context(), mux.Vars(r)["id"], limit)
+	switch {
+	case errors.Is(err, transform.ErrRuleNotFound):
+		sendErrorResponse(w, rh.logger, http.StatusNotFound, "RULE_NOT_FOUND", "Rule not found", err.Error())
+		return
+	case errors.Is(err, transform.ErrRuleNotPreviewable):
+		sendErrorResponse(w, rh.logger, http.StatusUnprocessableEntity, "RULE_NOT_PREVIEWABLE", "Rule cannot be tested on its own", err.Error())
+		return
+	case err != nil:
+		sendErrorResponse(w, rh.logger, http.StatusBadGateway, "PREVIEW_FAILED", "Failed to run rule against sample rows", err.Error())
+		return
+	}
+
+	sendJSONResponse(w, rh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      preview,
+		Timestamp: time.Now(),
+	})
+}
+
+// ExportRules downloads the loaded rule set as a bundle (?format=yaml|json)
+func (rh *RuleHandlers) ExportRules(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "yaml"
+	}
+
+	schema, err := rh.schema(r.Context())
+	if err != nil {
+		// The rules are still useful without a fingerprint
+		rh.logger.WithError(err).Warn("Exporting rule bundle without schema fingerprint")
+		schema = nil
+	}
+
+	data, err := config.MarshalRuleBundle(config.NewRuleBundle(rh.rules, rh.dbType, schema), format)
+	if err != nil {
+		sendErrorResponse(w, rh.logger, http.StatusBadRequest, "INVALID_FORMAT", "Unsupported bundle format", err.Error())
+		return
+	}
+
+	contentType := "application/yaml"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=rules.%s", format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		rh.logger.WithError(err).Error("Failed to write rule bundle")
+	}
+}
+
+// ImportRules validates a YAML or JSON bundle against the connected schema and, when an
+// import directory is set, stores it as a rule file named by ?name=
+func (rh *RuleHandlers) ImportRules(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRuleBundleSize))
+	if err != nil {
+		sendErrorResponse(w, rh.logger, http.StatusBadRequest, "INVALID_BUNDLE", "Failed to read rule bundle", err.Error())
+		return
+	}
+
+	bundle, err := config.ParseRuleBundle(data)
+	if err != nil {
+		sendErrorResponse(w, rh.logger, http.StatusBadRequest, "INVALID_BUNDLE", "Invalid rule bundle", err.Error())
+		return
+	}
+
+	schema, err := rh.schema(r.Context())
+	if err != nil {
+		sendErrorResponse(w, rh.logger, http.StatusBadGateway, "SCHEMA_UNAVAILABLE", "Failed to read the database schema", err.Error())
+		return
+	}
+
+	if err := config.ValidateRuleBundle(bundle, schema); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, config.ErrSchemaMismatch) {
+			status = http.StatusUnprocessableEntity
+		}
+		sendErrorResponse(w, rh.logger, status, "SCHEMA_MISMATCH", "Rule bundle does not match the database schema", err.Error())
+		return
+	}
+
+	combined := append(append([]models.TransformationConfig(nil), rh.rules...), bundle.TransformRules...)
+	if err := config.ValidateUniqueRuleNames(combined); err != nil {
+		sendErrorResponse(w, rh.logger, http.StatusConflict, "RULE_CONFLICT", "Rule bundle conflicts with loaded rules", err.Error())
+		return
+	}
+
+	fingerprint := config.SchemaColumns(schema).Fingerprint()
+	result := RuleImportResult{
+		Rules:              len(bundle.TransformRules),
+		SchemaFingerprint:  fingerprint,
+		FingerprintMatches: bundle.SchemaFingerprint == fingerprint,
+	}
+
+	if rh.importDir != "" {
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			name = "imported_" + time.Now().UTC().Format("20060102_150405")
+		}
+		path, err := config.WriteRuleBundleFile(rh.importDir, name, bundle)
+		if err != nil {
+			sendErrorResponse(w, rh.logger, http.StatusConflict, "WRITE_FAILED", "Failed to store rule bundle", err.Error())
+			return
+		}
+		result.File = path
+
+		rh.logger.WithFields(logrus.Fields{
+			"rules": result.Rules,
+			"file":  path,
+		}).Info("Rule bundle imported")
+	}
+
+	sendJSONResponse(w, rh.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      result,
+		Timestamp: time.Now(),
+	})
+}
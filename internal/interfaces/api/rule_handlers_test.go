@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/services/transform"
+	"sql-graph-visualizer/internal/domain/models"
+	"sql-graph-visualizer/internal/domain/repositories/config"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRuleHandlers(t *testing.T, rules []models.TransformationConfig, schema map[string][]string) (*RuleHandlers, *mux.Router) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	handlers := NewRuleHandlers(logger, rules, models.DatabaseTypeMySQL, func(ctx context.Context) (map[string][]string, error) {
+		return schema, nil
+	})
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	return handlers, router
+}
+
+func userNodeRule(name string) models.TransformationConfig {
+	return models.TransformationConfig{
+		Name:          name,
+		RuleType:      "node",
+		TargetType:    "User",
+		Source:        models.SourceConfig{Type: "table", Value: "users"},
+		FieldMappings: map[string]string{"id": "id", "email": "email"},
+	}
+}
+
+func TestExportAndImportRuleBundle(t *testing.T) {
+	schema := map[string][]string{"users": {"id", "email"}}
+	_, source := newTestRuleHandlers(t, []models.TransformationConfig{userNodeRule("users_to_nodes")}, schema)
+
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/rules/export?format=json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	exported := rec.Body.String()
+
+	target, router := newTestRuleHandlers(t, nil, schema)
+	dir := t.TempDir()
+	target.SetImportDir(dir)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/rules/import?name=shared", strings.NewReader(exported)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response struct {
+		Success bool             `json:"success"`
+		Data    RuleImportResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 1, response.Data.Rules)
+	assert.True(t, response.Data.FingerprintMatches)
+
+	rules, err := config.LoadTransformRulesDir(dir)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "users_to_nodes", rules[0].Name)
+}
+
+func TestImportRuleBundleErrors(t *testing.T) {
+	bundle, err := config.MarshalRuleBundle(config.NewRuleBundle(
+		[]models.TransformationConfig{userNodeRule("users_to_nodes")}, models.DatabaseTypeMySQL, nil), "yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		loaded   []models.TransformationConfig
+		schema   map[string][]string
+		body     string
+		status   int
+		wantCode string
+	}{
+		{"invalid bundle", nil, nil, "not: [a bundle", http.StatusBadRequest, "INVALID_BUNDLE"},
+		{"missing column", nil, map[string][]string{"users": {"id"}}, string(bundle), http.StatusUnprocessableEntity, "SCHEMA_MISMATCH"},
+		{"duplicate rule", []models.TransformationConfig{userNodeRule("users_to_nodes")}, map[string][]string{"users": {"id", "email"}}, string(bundle), http.StatusConflict, "RULE_CONFLICT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, router := newTestRuleHandlers(t, tt.loaded, tt.schema)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/rules/import", strings.NewReader(tt.body)))
+			require.Equal(t, tt.status, rec.Code)
+
+			var response APIResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			require.NotNil(t, response.Error)
+			assert.Equal(t, tt.wantCode, response.Error.Code)
+		})
+	}
+}
+
+// stubRulePreviewer previews the students rule and records the requested sample size
+type stubRulePreviewer struct {
+	limit int
+}
+
+func (p *stubRulePreviewer) PreviewRule(ctx context.Context, name string, limit int) (*transform.RulePreview, error) {
+	p.limit = limit
+	switch name {
+	case "students":
+		return &transform.RulePreview{
+			Rule:        name,
+			RuleType:    "node",
+			SampledRows: 1,
+			Nodes:       []transform.PreviewNode{{Type: "Student", ID: "1", Properties: map[string]any{"id": "1", "name": "Ada"}}},
+		}, nil
+	case "student_courses":
+		return nil, fmt.Errorf("%w: %s links existing nodes", transform.ErrRuleNotPreviewable, name)
+	default:
+		return nil, fmt.Errorf("%w: %s", transform.ErrRuleNotFound, name)
+	}
+}
+
+func TestTestRuleReturnsPreview(t *testing.T) {
+	handlers, _ := newTestRuleHandlers(t, nil, nil)
+	previewer := &stubRulePreviewer{}
+	handlers.SetPreviewer(previewer)
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/rules/students/test?limit=5", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, 5, previewer.limit)
+
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    transform.RulePreview `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Data.Nodes, 1)
+	assert.Equal(t, "Student", response.Data.Nodes[0].Type)
+	assert.Equal(t, "Ada", response.Data.Nodes[0].Properties["name"])
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/rules/students/test", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, defaultRulePreviewRows, previewer.limit)
+}
+
+func TestTestRuleErrors(t *testing.T) {
+	handlers, _ := newTestRuleHandlers(t, nil, nil)
+	handlers.SetPreviewer(&stubRulePreviewer{})
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+
+	tests := []struct {
+		path     string
+		status   int
+		wantCode string
+	}{
+		{"/api/rules/missing/test", http.StatusNotFound, "RULE_NOT_FOUND"},
+		{"/api/rules/student_courses/test", http.StatusUnprocessableEntity, "RULE_NOT_PREVIEWABLE"},
+		{"/api/rules/students/test?limit=0", http.StatusBadRequest, "INVALID_LIMIT"},
+		{"/api/rules/students/test?limit=100000", http.StatusBadRequest, "INVALID_LIMIT"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		require.Equal(t, tt.status, rec.Code, tt.path)
+
+		var response APIResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, tt.wantCode, response.Error.Code, tt.path)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// SchemaAnalyzeFunc analyzes the schema of the connected database
+type SchemaAnalyzeFunc func(ctx context.Context) (*models.UniversalDatabaseAnalysisResult, error)
+
+// SchemaHandlers contains HTTP handlers documenting the source database schema
+type SchemaHandlers struct {
+	logger  *logrus.Logger
+	analyze SchemaAnalyzeFunc
+}
+
+// NewSchemaHandlers creates new schema handlers
+func NewSchemaHandlers(logger *logrus.Logger, analyze SchemaAnalyzeFunc) *SchemaHandlers {
+	return &SchemaHandlers{
+		logger:  logger,
+		analyze: analyze,
+	}
+}
+
+// RegisterRoutes registers all schema routes
+func (sh *SchemaHandlers) RegisterRoutes(router *mux.Router) {
+	api := router.PathPrefix("/api/schema").Subrouter()
+
+	api.HandleFunc("/dictionary", sh.GetDataDictionary).Methods("GET")
+}
+
+// GetDataDictionary analyzes the schema and returns its data dictionary
+// (?format=json|markdown|html); markdown and html are returned as documents
+func (sh *SchemaHandlers) GetDataDictionary(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.DictionaryFormatJSON
+	}
+	switch format {
+	case services.DictionaryFormatJSON, services.DictionaryFormatMarkdown, services.DictionaryFormatHTML:
+	default:
+		sendErrorResponse(w, sh.logger, http.StatusBadRequest, "INVALID_FORMAT", "Unsupported dictionary format",
+			fmt.Sprintf("%s (expected json, markdown or html)", format))
+		return
+	}
+
+	result, err := sh.analyze(r.Context())
+	if err == nil && !result.Success {
+		err = errors.New(result.ErrorMessage)
+	}
+	if err != nil {
+		sendErrorResponse(w, sh.logger, http.StatusBadGateway, "ANALYSIS_FAILED", "Failed to analyze the database schema", err.Error())
+		return
+	}
+
+	dictionary := services.BuildDataDictionary(result)
+	if format == services.DictionaryFormatJSON {
+		sendJSONResponse(w, sh.logger, http.StatusOK, APIResponse{
+			Success:   true,
+			Data:      dictionary,
+			Timestamp: time.Now(),
+		})
+		return
+	}
+
+	data, contentType, err := dictionary.Render(format)
+	if err != nil {
+		sendErrorResponse(w, sh.logger, http.StatusInternalServerError, "RENDER_FAILED", "Failed to render the data dictionary", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		sh.logger.WithError(err).Error("Failed to write data dictionary")
+	}
+}
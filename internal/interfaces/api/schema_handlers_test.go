@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sql-graph-visualizer/internal/application/services"
+	"sql-graph-visualizer/internal/domain/models"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchemaTestRouter(result *models.UniversalDatabaseAnalysisResult, err error) *mux.Router {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	router := mux.NewRouter()
+	NewSchemaHandlers(logger, func(ctx context.Context) (*models.UniversalDatabaseAnalysisResult, error) {
+		return result, err
+	}).RegisterRoutes(router)
+	return router
+}
+
+func TestDataDictionaryRoute(t *testing.T) {
+	router := newSchemaTestRouter(&models.UniversalDatabaseAnalysisResult{
+		Success: true,
+		SchemaAnalysis: &models.UniversalSchemaAnalysisResult{
+			DatabaseName: "shop",
+			Tables: []*models.UniversalTableInfo{{
+				Name: "customers",
+				Columns: []*models.ColumnInfo{
+					{Name: "id", DataType: "int", IsNullable: "NO", KeyType: "PRI"},
+					{Name: "email", DataType: "varchar", IsNullable: "YES", Comment: "Login e-mail"},
+				},
+			}},
+		},
+	}, nil)
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/api/schema/dictionary")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Data services.DataDictionary `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.Len(t, response.Data.Tables, 1)
+	assert.Equal(t, []services.DictionaryColumn{
+		{Name: "id", Type: "int", Key: "PRIMARY"},
+		{Name: "email", Type: "varchar", Nullable: true, Comment: "Login e-mail"},
+	}, response.Data.Tables[0].Columns)
+
+	rec = get("/api/schema/dictionary?format=markdown")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "| email | varchar | YES |  |  | Login e-mail |")
+
+	rec = get("/api/schema/dictionary?format=html")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/schema/dictionary?format=pdf").Code)
+}
+
+func TestDataDictionaryRouteAnalysisFailure(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newSchemaTestRouter(nil, errors.New("connection refused")).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/schema/dictionary", nil))
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ANALYSIS_FAILED")
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/transform"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultCancelWait bounds how long a cancel request waits for the transform to stop
+const defaultCancelWait = 30 * time.Second
+
+// TransformHandlers contains HTTP handlers for controlling the data transformation
+type TransformHandlers struct {
+	logger           *logrus.Logger
+	transformService *transform.TransformService
+	cancelWait       time.Duration
+}
+
+// NewTransformHandlers creates new transform handlers
+func NewTransformHandlers(logger *logrus.Logger, transformService *transform.TransformService) *TransformHandlers {
+	return &TransformHandlers{
+		logger:           logger,
+		transformService: transformService,
+		cancelWait:       defaultCancelWait,
+	}
+}
+
+// RegisterRoutes registers all transform-related routes
+func (th *TransformHandlers) RegisterRoutes(router *mux.Router) {
+	api := router.PathPrefix("/api/transform").Subrouter()
+
+	api.HandleFunc("/status", th.GetTransformStatus).Methods("GET")
+	api.HandleFunc("/start", th.StartTransform).Methods("POST")
+	api.HandleFunc("/cancel", th.CancelTransform).Methods("POST")
+}
+
+// GetTransformStatus returns the progress of the active or last transform
+func (th *TransformHandlers) GetTransformStatus(w http.ResponseWriter, r *http.Request) {
+	sendJSONResponse(w, th.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      th.transformService.Progress(),
+		Timestamp: time.Now(),
+	})
+}
+
+// StartTransform triggers a transform run in the background; its progress is reported by
+// the status endpoint
+func (th *TransformHandlers) StartTransform(w http.ResponseWriter, r *http.Request) {
+	// The run outlives the request
+	err := th.transformService.Start(context.WithoutCancel(r.Context()))
+	if errors.Is(err, transform.ErrTransformRunning) {
+		sendErrorResponse(w, th.logger, http.StatusConflict, "TRANSFORM_RUNNING", "A transform is already running", "")
+		return
+	}
+	if err != nil {
+		sendErrorResponse(w, th.logger, http.StatusInternalServerError, "TRANSFORM_START_FAILED", "Failed to start the transform", err.Error())
+		return
+	}
+
+	th.logger.Info("Transform started through the API")
+	sendJSONResponse(w, th.logger, http.StatusAccepted, APIResponse{
+		Success:   true,
+		Data:      th.transformService.Progress(),
+		Timestamp: time.Now(),
+	})
+}
+
+// CancelTransform cancels the active transform, rolling back its uncommitted batch, and
+// returns what was written before cancellation
+func (th *TransformHandlers) CancelTransform(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), th.cancelWait)
+	defer cancel()
+
+	progress, err := th.transformService.Cancel(ctx)
+	switch {
+	case errors.Is(err, transform.ErrNoActiveTransform):
+		sendErrorResponse(w, th.logger, http.StatusConflict, "NO_ACTIVE_TRANSFORM", "No transform is running", "")
+		return
+	case err != nil:
+		sendErrorResponse(w, th.logger, http.StatusGatewayTimeout, "CANCEL_PENDING", "Transform was cancelled but has not stopped yet", err.Error())
+		return
+	}
+
+	th.logger.WithFields(logrus.Fields{
+		"nodes_written":         progress.NodesWritten,
+		"relationships_written": progress.RelationshipsWritten,
+		"relationships_skipped": progress.RelationshipsSkipped,
+		"batches_committed":     progress.BatchesCommitted,
+	}).Info("Transform cancelled")
+
+	sendJSONResponse(w, th.logger, http.StatusOK, APIResponse{
+		Success:   true,
+		Data:      progress,
+		Timestamp: time.Now(),
+	})
+}
+
+// ReadinessCheck is a DependencyCheck failing until a transform has finished, while one is
+// running and after one failed, so the graph is not served while empty or half-written
+func (th *TransformHandlers) ReadinessCheck(ctx context.Context) error {
+	progress := th.transformService.Progress()
+	switch {
+	case progress.StartedAt.IsZero():
+		return errors.New("no transform has run yet")
+	case progress.Running:
+		return fmt.Errorf("transform is running (%s)", progress.Phase)
+	case progress.Error != "":
+		return fmt.Errorf("last transform failed: %s", progress.Error)
+	}
+	return nil
+}
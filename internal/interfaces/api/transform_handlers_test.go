@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sql-graph-visualizer/internal/application/services/transform"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDatabasePort never finishes fetching until released
+type blockingDatabasePort struct {
+	release chan struct{}
+}
+
+func (p *blockingDatabasePort) FetchData() ([]map[string]any, error) {
+	<-p.release
+	return nil, nil
+}
+func (p *blockingDatabasePort) ExecuteQuery(query string) ([]map[string]any, error) { return nil, nil }
+func (p *blockingDatabasePort) Close() error                                        { return nil }
+
+func newTestTransformHandlers(t *testing.T) (*transform.TransformService, *mux.Router) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	db := &blockingDatabasePort{release: make(chan struct{})}
+	t.Cleanup(func() { close(db.release) })
+
+	service := transform.NewTransformService(db, &fakeNeo4jPort{}, &fakeRuleRepository{})
+	router := mux.NewRouter()
+	NewTransformHandlers(logger, service).RegisterRoutes(router)
+	return service, router
+}
+
+func TestCancelTransform(t *testing.T) {
+	service, router := newTestTransformHandlers(t)
+
+	result := make(chan error, 1)
+	go func() { result <- service.TransformAndStore(context.Background()) }()
+	require.Eventually(t, func() bool { return service.Progress().Running }, time.Second, 5*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/transform/cancel", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Success bool                        `json:"success"`
+		Data    transform.TransformProgress `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.True(t, response.Success)
+	assert.True(t, response.Data.Cancelled)
+	assert.False(t, response.Data.Running)
+	assert.Equal(t, transform.PhaseFetchSourceData, response.Data.Phase)
+	assert.Zero(t, response.Data.NodesWritten)
+
+	assert.ErrorIs(t, <-result, transform.ErrTransformCancelled)
+
+	// Nothing left to cancel
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/transform/cancel", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestStartTransform(t *testing.T) {
+	service, router := newTestTransformHandlers(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/transform/start", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.True(t, service.Progress().Running)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/transform/start", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code, "only one transform runs at a time")
+}
+
+func TestTransformReadinessCheck(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	db := &blockingDatabasePort{release: make(chan struct{})}
+	service := transform.NewTransformService(db, &fakeNeo4jPort{}, &fakeRuleRepository{})
+	handlers := NewTransformHandlers(logger, service)
+
+	assert.ErrorContains(t, handlers.ReadinessCheck(context.Background()), "no transform has run yet")
+
+	require.NoError(t, service.Start(context.Background()))
+	assert.ErrorContains(t, handlers.ReadinessCheck(context.Background()), "transform is running")
+
+	close(db.release)
+	require.Eventually(t, func() bool { return !service.Progress().Running }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, handlers.ReadinessCheck(context.Background()))
+}
+
+func TestTransformReadinessCheckReportsFailure(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	service := transform.NewTransformService(&blockingDatabasePort{release: make(chan struct{})}, &fakeNeo4jPort{}, &fakeRuleRepository{})
+	handlers := NewTransformHandlers(logger, service)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, service.TransformAndStore(ctx))
+	assert.ErrorContains(t, handlers.ReadinessCheck(context.Background()), "last transform failed")
+}
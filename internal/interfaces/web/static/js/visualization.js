@@ -17,7 +17,10 @@ class GraphVisualizer {
         console.log('Initializing visualization...');
         
         try {
-            const graphResponse = await fetch('/api/graph');
+            // A saved view can be selected with ?view=name on the page URL
+            const view = new URLSearchParams(window.location.search).get('view');
+            const graphUrl = view ? `/api/graph?view=${encodeURIComponent(view)}` : '/api/graph';
+            const graphResponse = await fetch(graphUrl);
             if (!graphResponse.ok) {
                 throw new Error(`HTTP error! status: ${graphResponse.status}`);
             }
@@ -70,7 +73,9 @@ class GraphVisualizer {
                         title: tooltip,
                         group: node.label,
                         size: nodeSize,
-                        properties: node.properties
+                        properties: node.properties,
+                        // Styling rules from the server override the defaults above
+                        ...(node.style || {})
                     });
                 });
             }
@@ -137,7 +142,8 @@ class GraphVisualizer {
                             highlight: '#FF6B6B'
                         },
                         width: edgeWidth,
-                        arrows: { to: { enabled: true, scaleFactor: 0.8 } }
+                        arrows: { to: { enabled: true, scaleFactor: 0.8 } },
+                        ...(rel.style || {})
                     });
                 });
             }
@@ -287,6 +293,7 @@ class GraphVisualizer {
         this.network.on('click', (params) => {
             if (params.nodes.length > 0) {
                 console.log('Node clicked:', params.nodes[0]);
+                this.loadNodeDetails(params.nodes[0]);
             }
         });
 
@@ -301,6 +308,21 @@ class GraphVisualizer {
         this.initializeLayoutSelector();
     }
 
+    // Fetches the clicked node's properties and relationship summaries
+    async loadNodeDetails(nodeId) {
+        try {
+            const response = await fetch(`/api/graph/node/${encodeURIComponent(nodeId)}`);
+            if (!response.ok) {
+                throw new Error(`HTTP error! status: ${response.status}`);
+            }
+            const details = (await response.json()).data;
+            console.log('Node details:', details);
+            return details;
+        } catch (error) {
+            console.error('Error loading node details:', error);
+        }
+    }
+
     initializeControlButtons() {
         const zoomInBtn = document.getElementById('zoomIn');
         if (zoomInBtn) {
@@ -17,14 +17,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -37,6 +34,7 @@ import (
 	"sql-graph-visualizer/internal/domain/models"
 	transformObjects "sql-graph-visualizer/internal/domain/valueobjects/transform"
 	"sql-graph-visualizer/internal/infrastructure/middleware"
+	"sql-graph-visualizer/internal/interfaces/api"
 
 	"sql-graph-visualizer/internal/config"
 
@@ -440,15 +438,9 @@ func startVisualizationServer(t *testing.T) *http.Server {
 		http.ServeFile(w, r, filepath.Join(webRoot, "templates", "visualization.html"))
 	})
 
-	listener, err := net.Listen("tcp", addr)
+	listener, err := api.Listen(addr, false)
 	if err != nil {
-		logrus.Warnf("Port %s is occupied: %v", addr, err)
-		exec.Command("fuser", "-k", "3000/tcp").Run()
-		time.Sleep(time.Second)
-		listener, err = net.Listen("tcp", addr)
-		if err != nil {
-			logrus.Fatalf("Cannot create listener: %v", err)
-		}
+		t.Fatalf("Cannot create listener: %v", err)
 	}
 	logrus.Infof("Listener created on %s", addr)
 